@@ -94,7 +94,7 @@ export default defineConfig({
           projectName: "mockd",
           description: `mockd is a fast, lightweight API mocking server written in Go.
 It supports HTTP, WebSocket, GraphQL, gRPC, MQTT, SSE, and SOAP protocols with built-in OAuth mock provider.
-Features include 18 MCP tools for AI agent integration, 35 faker types for response templating,
+Features include 19 MCP tools for AI agent integration, 35 faker types for response templating,
 chaos engineering with 12 fault types (including stateful circuit breakers), deterministic seeded responses,
 import from 8 formats (OpenAPI, Postman, HAR, WireMock, cURL, WSDL, Mockoon, mockd),
 stateful CRUD simulation, proxy recording, and multi-engine fan-out architecture.`,
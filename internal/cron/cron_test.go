@@ -0,0 +1,77 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func mustTime(t *testing.T, s string) time.Time {
+	t.Helper()
+	ts, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Fatalf("bad test time %q: %v", s, err)
+	}
+	return ts
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		spec string
+		from string
+		want string
+	}{
+		{"* * * * *", "2026-03-10T10:15:30Z", "2026-03-10T10:16:00Z"},
+		{"*/15 * * * *", "2026-03-10T10:15:00Z", "2026-03-10T10:30:00Z"},
+		{"0 9 * * mon-fri", "2026-03-13T09:00:00Z", "2026-03-16T09:00:00Z"}, // Fri -> Mon
+		{"30 2 1 * *", "2026-03-10T00:00:00Z", "2026-04-01T02:30:00Z"},
+		{"0 0 29 2 *", "2026-01-01T00:00:00Z", "2028-02-29T00:00:00Z"},
+		{"*/10 * * * * *", "2026-03-10T10:15:31Z", "2026-03-10T10:15:40Z"},
+		{"0 0 * * 7", "2026-03-10T00:00:00Z", "2026-03-15T00:00:00Z"}, // 7 == Sunday
+		{"0 0 1 jan *", "2026-03-10T00:00:00Z", "2027-01-01T00:00:00Z"},
+		{"@hourly", "2026-03-10T10:15:00Z", "2026-03-10T11:00:00Z"},
+		{"@every 45s", "2026-03-10T10:15:00Z", "2026-03-10T10:15:45Z"},
+		// Day-of-month OR day-of-week when both are restricted.
+		{"0 0 13 * fri", "2026-03-01T00:00:00Z", "2026-03-06T00:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			s, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.spec, err)
+			}
+			got := s.Next(mustTime(t, tt.from))
+			if want := mustTime(t, tt.want); !got.Equal(want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.from, got.Format(time.RFC3339), tt.want)
+			}
+		})
+	}
+}
+
+func TestNext_Impossible(t *testing.T) {
+	s, err := Parse("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if got := s.Next(mustTime(t, "2026-01-01T00:00:00Z")); !got.IsZero() {
+		t.Errorf("Next() = %s, want zero time", got)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"@often",
+		"@every 10ms",
+		"* * * foo *",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) expected error", spec)
+		}
+	}
+}
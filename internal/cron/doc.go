@@ -0,0 +1,16 @@
+// Package cron parses cron expressions and computes their next activation time.
+//
+// Both the classic five-field form (minute hour day-of-month month day-of-week)
+// and a six-field form with a leading seconds field are accepted. Each field
+// supports wildcards (* or ?), lists (1,15), ranges (1-5), and steps (*/10,
+// 0-30/5). Months and weekdays may be given by three-letter English names, and
+// 7 is accepted as Sunday.
+//
+// The following descriptors are also supported:
+//
+//   - @yearly (or @annually), @monthly, @weekly, @daily (or @midnight), @hourly
+//   - @every <duration>, e.g. "@every 30s", which fires at fixed intervals
+//
+// As in standard cron, when both day-of-month and day-of-week are restricted
+// a day matches if either field matches.
+package cron
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -549,6 +550,83 @@ func TestProxyHandler_CAPath_AcceptsValidRelative(t *testing.T) {
 	}
 }
 
+func TestProxyHandler_RejectsInvalidUpstream(t *testing.T) {
+	pm := NewProxyManager()
+
+	for _, upstream := range []string{"api.example.com", "ftp://api.example.com", "https://api.example.com?q=1"} {
+		body, _ := json.Marshal(ProxyStartRequest{Upstream: upstream})
+		rec := httptest.NewRecorder()
+		pm.handleProxyStart(rec, httptest.NewRequest("POST", "/proxy/start", bytes.NewReader(body)))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, "upstream %q should be rejected", upstream)
+		assert.Contains(t, rec.Body.String(), "invalid_upstream")
+	}
+	assert.False(t, pm.running)
+}
+
+func TestProxyHandler_RejectsInvalidRedaction(t *testing.T) {
+	pm := NewProxyManager()
+
+	body := []byte(`{"redaction":{"rules":[{"header":"Authorization","action":"shred"}]}}`)
+	rec := httptest.NewRecorder()
+	pm.handleProxyStart(rec, httptest.NewRequest("POST", "/proxy/start", bytes.NewReader(body)))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid_redaction")
+	assert.False(t, pm.running)
+}
+
+func TestProxyHandler_ReplayMode(t *testing.T) {
+	pm := NewProxyManager()
+
+	body, _ := json.Marshal(ProxyStartRequest{Mode: "replay", ReplaySession: "../elsewhere"})
+	rec := httptest.NewRecorder()
+	pm.handleProxyStart(rec, httptest.NewRequest("POST", "/proxy/start", bytes.NewReader(body)))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid_session")
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := l.Addr().(*net.TCPAddr).Port
+	require.NoError(t, l.Close())
+
+	body, _ = json.Marshal(ProxyStartRequest{Port: port, Mode: "record"})
+	rec = httptest.NewRecorder()
+	pm.handleProxyStart(rec, httptest.NewRequest("POST", "/proxy/start", bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	defer pm.handleProxyStop(httptest.NewRecorder(), httptest.NewRequest("POST", "/proxy/stop", nil))
+
+	setMode := func(mode ModeRequest) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(mode)
+		rec := httptest.NewRecorder()
+		pm.handleProxyMode(rec, httptest.NewRequest("PUT", "/proxy/mode", bytes.NewReader(body)))
+		return rec
+	}
+
+	rec = setMode(ModeRequest{Mode: "replay", OnMiss: "record"})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var status ProxyStatusResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.Equal(t, "replay", status.Mode)
+	assert.Equal(t, "record", status.OnMiss)
+	require.NotNil(t, status.Replay)
+
+	rec = setMode(ModeRequest{Mode: "replay", OnMiss: "skip"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid_on_miss")
+
+	rec = setMode(ModeRequest{Mode: "rewind"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = setMode(ModeRequest{Mode: "passthrough"})
+	require.Equal(t, http.StatusOK, rec.Code)
+	status = ProxyStatusResponse{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.Equal(t, "passthrough", status.Mode)
+	assert.Empty(t, status.OnMiss)
+	assert.Nil(t, status.Replay)
+}
+
 // ============================================================================
 // Regression Test for Bug 3.7: Missing Content-Type before WriteHeader
 // ============================================================================
@@ -9,6 +9,7 @@ import (
 	"net"
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -19,6 +20,7 @@ import (
 	"github.com/getmockd/mockd/pkg/ratelimit"
 	"github.com/getmockd/mockd/pkg/store"
 	"github.com/getmockd/mockd/pkg/store/file"
+	"github.com/getmockd/mockd/pkg/tcpproxy"
 	"github.com/getmockd/mockd/pkg/tracing"
 	"github.com/getmockd/mockd/pkg/workspace"
 )
@@ -44,6 +46,9 @@ type API struct {
 	streamRecordingManager *StreamRecordingManager
 	mqttRecordingManager   *MQTTRecordingManager
 	soapRecordingManager   *SOAPRecordingManager
+	grpcRecordingManager   *GRPCRecordingManager
+	chaosExperiments       *chaosExperimentManager
+	tcpProxies             *tcpproxy.Manager
 	workspaceStore         *store.WorkspaceFileStore
 	engineRegistry         *store.EngineRegistry
 	workspaceManager       workspace.Manager
@@ -115,11 +120,15 @@ func NewAPI(port int, opts ...Option) *API {
 	// Initialize metrics registry
 	metricsRegistry := metrics.Init()
 
+	tcpProxies := tcpproxy.NewManager()
 	api := &API{
 		proxyManager:                NewProxyManager(),
 		streamRecordingManager:      NewStreamRecordingManager(),
 		mqttRecordingManager:        NewMQTTRecordingManager(),
 		soapRecordingManager:        NewSOAPRecordingManager(),
+		grpcRecordingManager:        NewGRPCRecordingManager(),
+		chaosExperiments:            newChaosExperimentManager(tcpProxies),
+		tcpProxies:                  tcpProxies,
 		engineRegistry:              store.NewEngineRegistry(),
 		perEngineSync:               newPerEngineSyncMu(),
 		port:                        port,
@@ -233,6 +242,11 @@ func (a *API) SOAPRecordingManager() *SOAPRecordingManager {
 	return a.soapRecordingManager
 }
 
+// GRPCRecordingManager returns the gRPC recording manager.
+func (a *API) GRPCRecordingManager() *GRPCRecordingManager {
+	return a.grpcRecordingManager
+}
+
 // EngineRegistry returns the engine registry.
 func (a *API) EngineRegistry() *store.EngineRegistry {
 	return a.engineRegistry
@@ -276,12 +290,31 @@ func (a *API) HasLocalEngine() bool {
 // MaxBytesReader with a larger MaxBytesReader does not increase the limit.
 const maxRequestBodySize = 10 << 20
 
+// isStateImportRequest reports whether r targets POST /state/resources/{name}/import.
+func isStateImportRequest(r *http.Request) bool {
+	if r.Method != http.MethodPost {
+		return false
+	}
+	rest, ok := strings.CutPrefix(r.URL.Path, "/state/resources/")
+	if !ok {
+		return false
+	}
+	name, ok := strings.CutSuffix(rest, "/import")
+	return ok && name != "" && !strings.Contains(name, "/")
+}
+
 // withMiddleware wraps the handler with rate limiting, logging, security headers, CORS, API key auth, and tracing middleware.
 // Middleware order (outermost to innermost): Tracing -> Security Headers -> CORS -> API Key Auth -> Rate Limiting -> Workspace Normalization -> Body Limit -> Handler
 func (a *API) withMiddleware(handler http.Handler) http.Handler {
 	// Body size limit (innermost — protects all handlers from oversized request bodies)
 	bodyCapped := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+		limit := int64(maxRequestBodySize)
+		if isStateImportRequest(r) {
+			// Bulk item imports are streamed through to the engine, so they get
+			// a larger cap than the buffered JSON handlers.
+			limit = maxStateImportBodySize
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
 		handler.ServeHTTP(w, r)
 	})
 
@@ -493,6 +526,7 @@ func (a *API) SetLogger(log *slog.Logger) {
 	a.streamRecordingManager.SetLogger(log.With("component", "stream-recording"))
 	a.mqttRecordingManager.SetLogger(log.With("component", "mqtt-recording"))
 	a.soapRecordingManager.SetLogger(log.With("component", "soap-recording"))
+	a.grpcRecordingManager.SetLogger(log.With("component", "grpc-recording"))
 }
 
 // Stop gracefully shuts down the admin API server.
@@ -506,6 +540,12 @@ func (a *API) Stop() error {
 		a.rateLimiter.Stop()
 	}
 
+	// Stop the TCP fault-injection proxies
+	a.tcpProxies.Close()
+
+	// Stop the gRPC recording proxies
+	a.grpcRecordingManager.StopAll()
+
 	// Stop all workspace servers
 	if a.workspaceManager != nil {
 		if err := a.workspaceManager.StopAll(); err != nil {
@@ -0,0 +1,636 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/getmockd/mockd/pkg/admin/engineclient"
+	"github.com/getmockd/mockd/pkg/api/types"
+	"github.com/getmockd/mockd/pkg/chaos"
+	"github.com/getmockd/mockd/pkg/tcpproxy"
+)
+
+// Experiment states.
+const (
+	experimentRunning   = "running"
+	experimentCompleted = "completed"
+	experimentAborted   = "aborted"
+	experimentFailed    = "failed"
+)
+
+// Phase states.
+const (
+	phasePending   = "pending"
+	phaseRunning   = "running"
+	phaseCompleted = "completed"
+	phaseAborted   = "aborted"
+	phaseSkipped   = "skipped"
+)
+
+// defaultRampSteps is the number of ramp steps when a ramp sets none.
+const defaultRampSteps = 10
+
+// maxFinishedExperiments bounds how many finished experiments are kept for
+// the status API.
+const maxFinishedExperiments = 20
+
+// experimentPollInterval is how often a running phase checks its abort
+// conditions. Tests shorten it.
+var experimentPollInterval = time.Second
+
+// errExperimentRunning is returned when an experiment is started while
+// another one is still running.
+var errExperimentRunning = errors.New("a chaos experiment is already running")
+
+// chaosExperimentEngine is the part of the engine client an experiment drives.
+type chaosExperimentEngine interface {
+	GetChaos(ctx context.Context) (*engineclient.ChaosConfig, error)
+	SetChaos(ctx context.Context, cfg *engineclient.ChaosConfig) error
+	GetChaosStats(ctx context.Context) (*engineclient.ChaosStats, error)
+}
+
+// chaosExperimentManager runs chaos experiments one at a time and keeps the
+// most recent ones for the status API.
+type chaosExperimentManager struct {
+	tcpProxies *tcpproxy.Manager // proxies whose toxics phases may set
+
+	mu    sync.Mutex
+	runs  map[string]*chaosExperimentRun
+	order []string // run IDs, oldest first
+}
+
+func newChaosExperimentManager(tcpProxies *tcpproxy.Manager) *chaosExperimentManager {
+	return &chaosExperimentManager{tcpProxies: tcpProxies, runs: make(map[string]*chaosExperimentRun)}
+}
+
+// chaosExperimentRun is one execution of an experiment.
+type chaosExperimentRun struct {
+	durations []time.Duration
+	cancel    context.CancelFunc
+	done      chan struct{}
+
+	// tcpProxies are the proxies the phases set toxics on, with the toxics
+	// they had before the experiment.
+	tcpProxies     map[string]*tcpproxy.Proxy
+	previousToxics map[string][]tcpproxy.Toxic
+
+	mu          sync.Mutex
+	status      types.ChaosExperimentStatus
+	abortReason string
+}
+
+// validateExperiment checks an experiment and returns its phase durations.
+// Phases may only set toxics on proxies managed by tcpProxies, which may be nil.
+func validateExperiment(exp *types.ChaosExperiment, tcpProxies *tcpproxy.Manager) ([]time.Duration, error) {
+	if len(exp.Phases) == 0 {
+		return nil, errors.New("experiment needs at least one phase")
+	}
+
+	durations := make([]time.Duration, len(exp.Phases))
+	for i := range exp.Phases {
+		phase := &exp.Phases[i]
+		if phase.Name == "" {
+			phase.Name = fmt.Sprintf("phase-%d", i+1)
+		}
+
+		d, err := time.ParseDuration(phase.Duration)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("phases[%d]: duration must be a positive Go duration, got %q", i, phase.Duration)
+		}
+		durations[i] = d
+
+		if r := phase.Ramp; r != nil {
+			if r.Steps == 0 {
+				r.Steps = defaultRampSteps
+			}
+			if r.Steps < 1 {
+				return nil, fmt.Errorf("phases[%d]: ramp steps must be at least 1", i)
+			}
+			if r.From < 0 || r.From > 1 || r.To < 0 || r.To > 1 {
+				return nil, fmt.Errorf("phases[%d]: ramp from and to must be between 0 and 1", i)
+			}
+		}
+
+		if a := phase.Abort; a != nil {
+			if a.MaxFaultRate < 0 || a.MaxFaultRate > 1 {
+				return nil, fmt.Errorf("phases[%d]: abort maxFaultRate must be between 0 and 1", i)
+			}
+			if a.MaxInjectedFaults < 0 || a.MinRequests < 0 {
+				return nil, fmt.Errorf("phases[%d]: abort limits must not be negative", i)
+			}
+		}
+
+		if phase.Chaos != nil {
+			cfg := *phase.Chaos
+			cfg.Enabled = true
+			internal := types.ChaosConfigToInternal(&cfg)
+			internal.Clamp()
+			if err := internal.Validate(); err != nil {
+				return nil, fmt.Errorf("phases[%d]: chaos: %w", i, err)
+			}
+			if _, err := chaos.NewInjector(internal); err != nil {
+				return nil, fmt.Errorf("phases[%d]: chaos: %w", i, err)
+			}
+		}
+
+		for name, toxics := range phase.TCPToxics {
+			if tcpProxies == nil {
+				return nil, fmt.Errorf("phases[%d]: tcp proxy %q not found", i, name)
+			}
+			if _, ok := tcpProxies.Get(name); !ok {
+				return nil, fmt.Errorf("phases[%d]: tcp proxy %q not found", i, name)
+			}
+			for _, toxic := range toxics {
+				if err := toxic.Validate(); err != nil {
+					return nil, fmt.Errorf("phases[%d]: tcpToxics[%s]: %w", i, name, err)
+				}
+			}
+		}
+	}
+	return durations, nil
+}
+
+// Start validates exp and runs it in the background against engine. The
+// chaos configuration active on the engine is captured first and restored
+// when the experiment ends. parent bounds the run's lifetime.
+func (m *chaosExperimentManager) Start(ctx, parent context.Context, engine chaosExperimentEngine, exp types.ChaosExperiment) (types.ChaosExperimentStatus, error) {
+	durations, err := validateExperiment(&exp, m.tcpProxies)
+	if err != nil {
+		return types.ChaosExperimentStatus{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, run := range m.runs {
+		if run.snapshot(false).State == experimentRunning {
+			return types.ChaosExperimentStatus{}, errExperimentRunning
+		}
+	}
+
+	previous, err := engine.GetChaos(ctx)
+	if err != nil {
+		return types.ChaosExperimentStatus{}, fmt.Errorf("get current chaos config: %w", err)
+	}
+
+	phases := make([]types.ChaosExperimentPhaseStatus, len(exp.Phases))
+	for i, p := range exp.Phases {
+		phases[i] = types.ChaosExperimentPhaseStatus{Name: p.Name, State: phasePending}
+	}
+
+	proxies := make(map[string]*tcpproxy.Proxy)
+	previousToxics := make(map[string][]tcpproxy.Toxic)
+	for _, p := range exp.Phases {
+		for name := range p.TCPToxics {
+			proxy, ok := m.tcpProxies.Get(name)
+			if !ok {
+				return types.ChaosExperimentStatus{}, fmt.Errorf("tcp proxy %q not found", name)
+			}
+			proxies[name] = proxy
+			previousToxics[name] = proxy.Toxics()
+		}
+	}
+
+	runCtx, cancel := context.WithCancel(parent)
+	run := &chaosExperimentRun{
+		durations:      durations,
+		cancel:         cancel,
+		done:           make(chan struct{}),
+		tcpProxies:     proxies,
+		previousToxics: previousToxics,
+		status: types.ChaosExperimentStatus{
+			ID:         "exp_" + generateShortID(),
+			Name:       exp.Name,
+			State:      experimentRunning,
+			StartedAt:  time.Now(),
+			Phases:     phases,
+			Experiment: exp,
+		},
+	}
+	m.runs[run.status.ID] = run
+	m.order = append(m.order, run.status.ID)
+	m.prune()
+
+	go run.run(runCtx, engine, previous)
+	return run.snapshot(true), nil
+}
+
+// prune drops the oldest finished runs beyond maxFinishedExperiments.
+// The caller holds m.mu.
+func (m *chaosExperimentManager) prune() {
+	excess := len(m.order) - maxFinishedExperiments
+	kept := m.order[:0]
+	for _, id := range m.order {
+		if excess > 0 && m.runs[id].snapshot(false).State != experimentRunning {
+			delete(m.runs, id)
+			excess--
+			continue
+		}
+		kept = append(kept, id)
+	}
+	m.order = kept
+}
+
+// List returns the status of every kept experiment, newest first, without
+// timelines.
+func (m *chaosExperimentManager) List() []types.ChaosExperimentStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]types.ChaosExperimentStatus, 0, len(m.order))
+	for i := len(m.order) - 1; i >= 0; i-- {
+		out = append(out, m.runs[m.order[i]].snapshot(false))
+	}
+	return out
+}
+
+// get returns the run with the given ID.
+func (m *chaosExperimentManager) get(id string) (*chaosExperimentRun, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	run, ok := m.runs[id]
+	return run, ok
+}
+
+// Abort stops a running experiment, waits for it to restore the previous
+// chaos configuration and returns its final status.
+func (m *chaosExperimentManager) Abort(id, reason string) (types.ChaosExperimentStatus, bool, error) {
+	run, ok := m.get(id)
+	if !ok {
+		return types.ChaosExperimentStatus{}, false, nil
+	}
+
+	run.mu.Lock()
+	if run.status.State != experimentRunning {
+		run.mu.Unlock()
+		return types.ChaosExperimentStatus{}, true, fmt.Errorf("experiment %s is not running", id)
+	}
+	if run.abortReason == "" {
+		run.abortReason = reason
+	}
+	run.mu.Unlock()
+
+	run.cancel()
+	<-run.done
+	return run.snapshot(true), true, nil
+}
+
+// snapshot returns a copy of the run's status, with the timeline if requested.
+func (r *chaosExperimentRun) snapshot(withTimeline bool) types.ChaosExperimentStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := r.status
+	s.Phases = append([]types.ChaosExperimentPhaseStatus(nil), r.status.Phases...)
+	if withTimeline {
+		s.Timeline = append([]types.ChaosExperimentEvent(nil), r.status.Timeline...)
+	} else {
+		s.Timeline = nil
+	}
+	return s
+}
+
+// event appends an entry to the run's timeline.
+func (r *chaosExperimentRun) event(eventType, phase, message string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status.Timeline = append(r.status.Timeline, types.ChaosExperimentEvent{
+		Time:    time.Now(),
+		Type:    eventType,
+		Phase:   phase,
+		Message: message,
+	})
+}
+
+// updatePhase applies fn to phase i's status under the run's lock.
+func (r *chaosExperimentRun) updatePhase(i int, fn func(p *types.ChaosExperimentPhaseStatus)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fn(&r.status.Phases[i])
+}
+
+// run executes the phases in order, then restores previous. It always
+// restores previous, whether the experiment completed, was aborted or failed.
+func (r *chaosExperimentRun) run(ctx context.Context, engine chaosExperimentEngine, previous *engineclient.ChaosConfig) {
+	defer close(r.done)
+	defer r.cancel()
+
+	r.event("started", "", fmt.Sprintf("%d phases", len(r.durations)))
+	state, reason := r.runPhases(ctx, engine)
+
+	// The run context may be cancelled already; restore with a fresh one.
+	revertCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if previous == nil {
+		previous = &engineclient.ChaosConfig{}
+	}
+	if err := engine.SetChaos(revertCtx, previous); err != nil {
+		r.event(experimentFailed, "", "restoring the previous chaos config failed: "+err.Error())
+		if state == experimentCompleted {
+			state, reason = experimentFailed, "restoring the previous chaos config failed"
+		}
+	} else {
+		r.event("reverted", "", "restored the chaos config active before the experiment")
+	}
+	for name, proxy := range r.tcpProxies {
+		if err := proxy.SetToxics(r.previousToxics[name]); err != nil {
+			r.event(experimentFailed, "", fmt.Sprintf("restoring the toxics of tcp proxy %s failed: %v", name, err))
+		}
+	}
+
+	now := time.Now()
+	r.mu.Lock()
+	r.status.State = state
+	r.status.Reason = reason
+	r.status.EndedAt = &now
+	r.status.CurrentPhase = ""
+	for i := range r.status.Phases {
+		if r.status.Phases[i].State == phasePending {
+			r.status.Phases[i].State = phaseSkipped
+		}
+	}
+	r.mu.Unlock()
+
+	if state == experimentCompleted {
+		r.event(experimentCompleted, "", "")
+	}
+}
+
+// runPhases runs every phase and returns the experiment's final state and,
+// unless it completed, the reason.
+func (r *chaosExperimentRun) runPhases(ctx context.Context, engine chaosExperimentEngine) (string, string) {
+	phases := r.status.Experiment.Phases
+	for i := range phases {
+		phase := &phases[i]
+		if ctx.Err() != nil {
+			return r.stopped()
+		}
+
+		now := time.Now()
+		r.mu.Lock()
+		r.status.CurrentPhase = phase.Name
+		r.mu.Unlock()
+		r.updatePhase(i, func(p *types.ChaosExperimentPhaseStatus) {
+			p.State = phaseRunning
+			p.StartedAt = &now
+		})
+		r.event("phase_started", phase.Name, "")
+
+		state, reason := r.runPhase(ctx, engine, i)
+		ended := time.Now()
+		r.updatePhase(i, func(p *types.ChaosExperimentPhaseStatus) {
+			p.EndedAt = &ended
+			if state == experimentCompleted {
+				p.State = phaseCompleted
+			} else {
+				p.State = phaseAborted
+			}
+		})
+		if state != experimentCompleted {
+			r.event(state, phase.Name, reason)
+			return state, reason
+		}
+		r.event("phase_completed", phase.Name, "")
+	}
+	return experimentCompleted, ""
+}
+
+// runPhase applies phase i's chaos, one config per ramp step, and watches its
+// abort conditions until the phase's duration has passed.
+func (r *chaosExperimentRun) runPhase(ctx context.Context, engine chaosExperimentEngine, i int) (string, string) {
+	phase := r.status.Experiment.Phases[i]
+	steps, from, to := 1, 1.0, 1.0
+	if phase.Ramp != nil {
+		steps, from, to = phase.Ramp.Steps, phase.Ramp.From, phase.Ramp.To
+	}
+	stepDuration := r.durations[i] / time.Duration(steps)
+
+	// Applying a config replaces the engine's injector and resets its stats,
+	// so the phase totals add up the stats of each step.
+	var requests, faults int64
+	for step := 0; step < steps; step++ {
+		scale := to
+		if steps > 1 {
+			scale = from + (to-from)*float64(step)/float64(steps-1)
+		}
+		if err := engine.SetChaos(ctx, scaledChaosConfig(phase.Chaos, scale)); err != nil {
+			if ctx.Err() != nil {
+				return r.stopped()
+			}
+			return experimentFailed, "applying the phase chaos config failed: " + err.Error()
+		}
+		if err := r.applyTCPToxics(phase.TCPToxics, scale); err != nil {
+			return experimentFailed, "applying the phase tcp toxics failed: " + err.Error()
+		}
+		r.updatePhase(i, func(p *types.ChaosExperimentPhaseStatus) { p.Scale = scale })
+		if phase.Ramp != nil {
+			r.event("ramp_step", phase.Name, fmt.Sprintf("step %d/%d, scale %.2f", step+1, steps, scale))
+		}
+
+		stepRequests, stepFaults, reason := r.watch(ctx, engine, i, stepDuration, requests, faults)
+		requests += stepRequests
+		faults += stepFaults
+		if reason != "" {
+			return experimentAborted, reason
+		}
+		if ctx.Err() != nil {
+			return r.stopped()
+		}
+	}
+	return experimentCompleted, ""
+}
+
+// watch polls the engine's chaos stats for d, recording them on phase i on
+// top of the earlier steps' totals. It returns the step's counts and, when an
+// abort condition is met, the reason.
+func (r *chaosExperimentRun) watch(ctx context.Context, engine chaosExperimentEngine, i int, d time.Duration,
+	baseRequests, baseFaults int64) (requests, faults int64, reason string) {
+	abort := r.status.Experiment.Phases[i].Abort
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	ticker := time.NewTicker(experimentPollInterval)
+	defer ticker.Stop()
+
+	poll := func() string {
+		stats, err := engine.GetChaosStats(ctx)
+		if err != nil || stats == nil {
+			return ""
+		}
+		requests, faults = stats.TotalRequests, stats.InjectedFaults
+		total, totalFaults := baseRequests+requests, baseFaults+faults
+		r.updatePhase(i, func(p *types.ChaosExperimentPhaseStatus) {
+			p.Requests = total
+			p.InjectedFaults = totalFaults
+		})
+		return abortReason(abort, total, totalFaults)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return requests, faults, ""
+		case <-ticker.C:
+			if reason := poll(); reason != "" {
+				return requests, faults, reason
+			}
+		case <-timer.C:
+			return requests, faults, poll()
+		}
+	}
+}
+
+// stopped returns the final state of a run whose context was cancelled.
+func (r *chaosExperimentRun) stopped() (string, string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reason := r.abortReason
+	if reason == "" {
+		reason = "admin API shutting down"
+	}
+	return experimentAborted, reason
+}
+
+// abortReason reports which abort condition, if any, the phase totals meet.
+func abortReason(abort *types.ChaosExperimentAbort, requests, faults int64) string {
+	if abort == nil {
+		return ""
+	}
+	if abort.MaxInjectedFaults > 0 && faults > abort.MaxInjectedFaults {
+		return fmt.Sprintf("%d faults injected, limit is %d", faults, abort.MaxInjectedFaults)
+	}
+	if abort.MaxFaultRate > 0 && requests > 0 && requests >= abort.MinRequests {
+		if rate := float64(faults) / float64(requests); rate > abort.MaxFaultRate {
+			return fmt.Sprintf("fault rate %.2f over %d requests, limit is %.2f", rate, requests, abort.MaxFaultRate)
+		}
+	}
+	return ""
+}
+
+// scaledChaosConfig returns a copy of cfg, enabled, with every probability
+// multiplied by scale. A nil cfg gives a config with chaos disabled. Rule
+// probabilities are left alone: scaling the fault probabilities already
+// scales how often a matching rule injects anything.
+func scaledChaosConfig(cfg *engineclient.ChaosConfig, scale float64) *engineclient.ChaosConfig {
+	if cfg == nil {
+		return &engineclient.ChaosConfig{}
+	}
+
+	out := *cfg
+	out.Enabled = true
+	if cfg.Latency != nil {
+		l := *cfg.Latency
+		l.Probability = scaleProbability(l.Probability, scale)
+		out.Latency = &l
+	}
+	if cfg.ErrorRate != nil {
+		e := *cfg.ErrorRate
+		e.Probability = scaleProbability(e.Probability, scale)
+		out.ErrorRate = &e
+	}
+	if cfg.Bandwidth != nil {
+		b := *cfg.Bandwidth
+		b.Probability = scaleProbability(b.Probability, scale)
+		out.Bandwidth = &b
+	}
+	if cfg.Rules != nil {
+		out.Rules = make([]engineclient.ChaosRuleConfig, len(cfg.Rules))
+		for i, rule := range cfg.Rules {
+			rule.Faults = append([]engineclient.ChaosFaultConfig(nil), rule.Faults...)
+			for j := range rule.Faults {
+				rule.Faults[j].Probability = scaleProbability(rule.Faults[j].Probability, scale)
+			}
+			out.Rules[i] = rule
+		}
+	}
+	return &out
+}
+
+// applyTCPToxics sets each of the run's proxies to its toxics in phase, with
+// their toxicity multiplied by scale. Proxies phase omits get no toxics.
+func (r *chaosExperimentRun) applyTCPToxics(phase map[string][]tcpproxy.Toxic, scale float64) error {
+	for name, proxy := range r.tcpProxies {
+		toxics := make([]tcpproxy.Toxic, len(phase[name]))
+		for i, t := range phase[name] {
+			toxicity := 1.0
+			if t.Toxicity != nil {
+				toxicity = *t.Toxicity
+			}
+			toxicity = scaleProbability(toxicity, scale)
+			t.Toxicity = &toxicity
+			toxics[i] = t
+		}
+		if err := proxy.SetToxics(toxics); err != nil {
+			return fmt.Errorf("tcp proxy %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func scaleProbability(p, scale float64) float64 {
+	return min(p*scale, 1)
+}
+
+// --- Handlers ---
+
+// handleListChaosExperiments returns every kept experiment, newest first.
+func (a *API) handleListChaosExperiments(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, a.chaosExperiments.List())
+}
+
+// handleStartChaosExperiment starts a chaos experiment on the engine.
+func (a *API) handleStartChaosExperiment(w http.ResponseWriter, r *http.Request, engine *engineclient.Client) {
+	var exp types.ChaosExperiment
+	if err := json.NewDecoder(r.Body).Decode(&exp); err != nil {
+		writeJSONDecodeError(w, err, a.logger())
+		return
+	}
+
+	if _, err := validateExperiment(&exp, a.tcpProxies); err != nil {
+		writeError(w, http.StatusBadRequest, "validation_error", err.Error())
+		return
+	}
+
+	status, err := a.chaosExperiments.Start(r.Context(), a.ctx, engine, exp)
+	switch {
+	case errors.Is(err, errExperimentRunning):
+		writeError(w, http.StatusConflict, "experiment_running", err.Error())
+		return
+	case err != nil:
+		writeError(w, http.StatusServiceUnavailable, "engine_error", sanitizeEngineError(err, a.logger(), "start chaos experiment"))
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, status)
+}
+
+// handleGetChaosExperiment returns an experiment's status and timeline.
+func (a *API) handleGetChaosExperiment(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	run, ok := a.chaosExperiments.get(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "chaos experiment not found: "+id)
+		return
+	}
+	writeJSON(w, http.StatusOK, run.snapshot(true))
+}
+
+// handleAbortChaosExperiment aborts a running experiment and returns its
+// final status once the previous chaos config has been restored.
+func (a *API) handleAbortChaosExperiment(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	status, found, err := a.chaosExperiments.Abort(id, "aborted by user")
+	switch {
+	case !found:
+		writeError(w, http.StatusNotFound, "not_found", "chaos experiment not found: "+id)
+	case err != nil:
+		writeError(w, http.StatusConflict, "not_running", err.Error())
+	default:
+		writeJSON(w, http.StatusOK, status)
+	}
+}
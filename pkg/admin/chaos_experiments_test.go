@@ -0,0 +1,287 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getmockd/mockd/pkg/admin/engineclient"
+	"github.com/getmockd/mockd/pkg/api/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// experimentEngineServer simulates the engine's chaos endpoints and records
+// every chaos config an experiment applies.
+type experimentEngineServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	current engineclient.ChaosConfig
+	applied []engineclient.ChaosConfig
+	stats   engineclient.ChaosStats
+}
+
+func newExperimentEngineServer(initial engineclient.ChaosConfig) *experimentEngineServer {
+	s := &experimentEngineServer{current: initial}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /chaos", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		writeJSON(w, http.StatusOK, s.current)
+	})
+	mux.HandleFunc("PUT /chaos", func(w http.ResponseWriter, r *http.Request) {
+		var cfg engineclient.ChaosConfig
+		if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.current = cfg
+		s.applied = append(s.applied, cfg)
+		s.stats = engineclient.ChaosStats{} // a new injector starts with fresh stats
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.HandleFunc("GET /chaos/stats", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		writeJSON(w, http.StatusOK, s.stats)
+	})
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+func (s *experimentEngineServer) setStats(requests, faults int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats = engineclient.ChaosStats{TotalRequests: requests, InjectedFaults: faults}
+}
+
+func (s *experimentEngineServer) snapshot() (engineclient.ChaosConfig, []engineclient.ChaosConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.current, append([]engineclient.ChaosConfig(nil), s.applied...)
+}
+
+func setFastExperimentPolling(t *testing.T) {
+	t.Helper()
+	old := experimentPollInterval
+	experimentPollInterval = 5 * time.Millisecond
+	t.Cleanup(func() { experimentPollInterval = old })
+}
+
+func startExperiment(t *testing.T, api *API, engine *engineclient.Client, exp types.ChaosExperiment) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(exp)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/chaos/experiments", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	api.handleStartChaosExperiment(rec, req, engine)
+	return rec
+}
+
+func waitForExperiment(t *testing.T, api *API, id string) types.ChaosExperimentStatus {
+	t.Helper()
+	run, ok := api.chaosExperiments.get(id)
+	require.True(t, ok)
+	select {
+	case <-run.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("experiment did not finish")
+	}
+	return run.snapshot(true)
+}
+
+func TestChaosExperiment_RunsPhasesAndReverts(t *testing.T) {
+	setFastExperimentPolling(t)
+	previous := engineclient.ChaosConfig{Enabled: true, Latency: &engineclient.LatencyConfig{Min: "1ms", Max: "2ms", Probability: 0.5}}
+	server := newExperimentEngineServer(previous)
+	defer server.Close()
+	api := NewAPI(0, WithDataDir(t.TempDir()), WithLocalEngineClient(server.client()))
+
+	rec := startExperiment(t, api, server.client(), types.ChaosExperiment{
+		Name: "checkout",
+		Phases: []types.ChaosExperimentPhase{
+			{Name: "baseline", Duration: "20ms"},
+			{
+				Name:     "ramp",
+				Duration: "30ms",
+				Chaos:    &engineclient.ChaosConfig{ErrorRate: &engineclient.ErrorRateConfig{Probability: 0.3}},
+				Ramp:     &types.ChaosExperimentRamp{From: 0, To: 1, Steps: 3},
+			},
+		},
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var started types.ChaosExperimentStatus
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &started))
+	assert.Equal(t, experimentRunning, started.State)
+	assert.NotEmpty(t, started.ID)
+
+	status := waitForExperiment(t, api, started.ID)
+	assert.Equal(t, experimentCompleted, status.State)
+	require.Len(t, status.Phases, 2)
+	assert.Equal(t, phaseCompleted, status.Phases[0].State)
+	assert.Equal(t, phaseCompleted, status.Phases[1].State)
+	assert.InDelta(t, 1.0, status.Phases[1].Scale, 0.001)
+
+	current, applied := server.snapshot()
+	assert.Equal(t, previous, current, "previous chaos config should be restored")
+
+	// baseline, three ramp steps, revert
+	require.Len(t, applied, 5)
+	assert.False(t, applied[0].Enabled)
+	for i, want := range []float64{0, 0.15, 0.3} {
+		require.NotNil(t, applied[i+1].ErrorRate)
+		assert.True(t, applied[i+1].Enabled)
+		assert.InDelta(t, want, applied[i+1].ErrorRate.Probability, 0.001)
+	}
+
+	var events []string
+	for _, e := range status.Timeline {
+		events = append(events, e.Type)
+	}
+	assert.Equal(t, []string{
+		"started",
+		"phase_started", "phase_completed",
+		"phase_started", "ramp_step", "ramp_step", "ramp_step", "phase_completed",
+		"reverted", "completed",
+	}, events)
+}
+
+func TestChaosExperiment_AbortConditions(t *testing.T) {
+	setFastExperimentPolling(t)
+	server := newExperimentEngineServer(engineclient.ChaosConfig{})
+	defer server.Close()
+	api := NewAPI(0, WithDataDir(t.TempDir()), WithLocalEngineClient(server.client()))
+
+	rec := startExperiment(t, api, server.client(), types.ChaosExperiment{
+		Phases: []types.ChaosExperimentPhase{
+			{
+				Name:     "errors",
+				Duration: "5s",
+				Chaos:    &engineclient.ChaosConfig{ErrorRate: &engineclient.ErrorRateConfig{Probability: 0.5}},
+				Abort:    &types.ChaosExperimentAbort{MaxFaultRate: 0.2, MinRequests: 10},
+			},
+			{Name: "never", Duration: "1s"},
+		},
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var started types.ChaosExperimentStatus
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &started))
+
+	// Below minRequests the rate is not checked yet.
+	require.Eventually(t, func() bool {
+		_, applied := server.snapshot()
+		return len(applied) == 1
+	}, 2*time.Second, 5*time.Millisecond)
+	server.setStats(5, 5)
+	time.Sleep(30 * time.Millisecond)
+	run, _ := api.chaosExperiments.get(started.ID)
+	assert.Equal(t, experimentRunning, run.snapshot(false).State)
+
+	server.setStats(20, 10)
+	status := waitForExperiment(t, api, started.ID)
+	assert.Equal(t, experimentAborted, status.State)
+	assert.Contains(t, status.Reason, "fault rate 0.50")
+	assert.Equal(t, phaseAborted, status.Phases[0].State)
+	assert.Equal(t, int64(20), status.Phases[0].Requests)
+	assert.Equal(t, phaseSkipped, status.Phases[1].State)
+
+	current, _ := server.snapshot()
+	assert.False(t, current.Enabled, "chaos should be reverted to disabled")
+}
+
+func TestChaosExperiment_ManualAbort(t *testing.T) {
+	setFastExperimentPolling(t)
+	server := newExperimentEngineServer(engineclient.ChaosConfig{})
+	defer server.Close()
+	api := NewAPI(0, WithDataDir(t.TempDir()), WithLocalEngineClient(server.client()))
+
+	exp := types.ChaosExperiment{Phases: []types.ChaosExperimentPhase{{
+		Name:     "latency",
+		Duration: "1m",
+		Chaos:    &engineclient.ChaosConfig{Latency: &engineclient.LatencyConfig{Min: "10ms", Max: "20ms", Probability: 1}},
+	}}}
+	rec := startExperiment(t, api, server.client(), exp)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var started types.ChaosExperimentStatus
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &started))
+
+	// Only one experiment runs at a time.
+	rec = startExperiment(t, api, server.client(), exp)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	req := httptest.NewRequest(http.MethodPost, "/chaos/experiments/"+started.ID+"/abort", nil)
+	req.SetPathValue("id", started.ID)
+	rec = httptest.NewRecorder()
+	api.handleAbortChaosExperiment(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var status types.ChaosExperimentStatus
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.Equal(t, experimentAborted, status.State)
+	assert.Equal(t, "aborted by user", status.Reason)
+	require.NotEmpty(t, status.Timeline)
+	assert.Equal(t, "reverted", status.Timeline[len(status.Timeline)-1].Type)
+
+	current, _ := server.snapshot()
+	assert.False(t, current.Enabled)
+
+	// Aborting again conflicts; unknown IDs are not found.
+	rec = httptest.NewRecorder()
+	api.handleAbortChaosExperiment(rec, req)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/chaos/experiments/exp_missing", nil)
+	req.SetPathValue("id", "exp_missing")
+	rec = httptest.NewRecorder()
+	api.handleGetChaosExperiment(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	api.handleListChaosExperiments(rec, httptest.NewRequest(http.MethodGet, "/chaos/experiments", nil))
+	var list []types.ChaosExperimentStatus
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list, 1)
+	assert.Empty(t, list[0].Timeline)
+}
+
+func TestChaosExperiment_Validation(t *testing.T) {
+	server := newExperimentEngineServer(engineclient.ChaosConfig{})
+	defer server.Close()
+	api := NewAPI(0, WithDataDir(t.TempDir()), WithLocalEngineClient(server.client()))
+
+	tests := []struct {
+		name string
+		exp  types.ChaosExperiment
+	}{
+		{"no phases", types.ChaosExperiment{}},
+		{"bad duration", types.ChaosExperiment{Phases: []types.ChaosExperimentPhase{{Duration: "soon"}}}},
+		{"bad ramp", types.ChaosExperiment{Phases: []types.ChaosExperimentPhase{{Duration: "1s", Ramp: &types.ChaosExperimentRamp{From: 0, To: 2}}}}},
+		{"bad chaos", types.ChaosExperiment{Phases: []types.ChaosExperimentPhase{{
+			Duration: "1s",
+			Chaos:    &engineclient.ChaosConfig{Rules: []engineclient.ChaosRuleConfig{{PathPattern: "[", Probability: 1}}},
+		}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := startExperiment(t, api, server.client(), tt.exp)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+		})
+	}
+
+	_, applied := server.snapshot()
+	assert.Empty(t, applied)
+}
+
+func (s *experimentEngineServer) client() *engineclient.Client {
+	return engineclient.New(s.URL)
+}
@@ -1,13 +1,19 @@
 package admin
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/getmockd/mockd/pkg/admin/engineclient"
 	"github.com/getmockd/mockd/pkg/api/types"
 	"github.com/getmockd/mockd/pkg/chaos"
+	"github.com/getmockd/mockd/pkg/config"
+	"github.com/getmockd/mockd/pkg/store"
 )
 
 // handleGetChaos returns the current chaos configuration.
@@ -56,6 +62,7 @@ func (a *API) handleGetChaosStats(w http.ResponseWriter, r *http.Request, engine
 		writeError(w, http.StatusServiceUnavailable, "engine_error", sanitizeEngineError(err, a.logger(), "get chaos stats"))
 		return
 	}
+	stats.TCPProxies = a.tcpProxies.Stats()
 
 	writeJSON(w, http.StatusOK, stats)
 }
@@ -68,10 +75,32 @@ func (a *API) handleResetChaosStats(w http.ResponseWriter, r *http.Request, engi
 		writeError(w, http.StatusServiceUnavailable, "engine_error", sanitizeEngineError(err, a.logger(), "reset chaos stats"))
 		return
 	}
+	a.tcpProxies.ResetStats()
 
 	writeJSON(w, http.StatusOK, map[string]string{"message": "chaos stats reset"})
 }
 
+// handleGetChaosFaultLog returns the engine's chaos fault log.
+func (a *API) handleGetChaosFaultLog(w http.ResponseWriter, r *http.Request, engine *engineclient.Client) {
+	log, err := engine.GetChaosFaultLog(r.Context())
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, "engine_error", sanitizeEngineError(err, a.logger(), "get chaos fault log"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, log)
+}
+
+// handleResetChaosFaultLog clears the engine's chaos fault log.
+func (a *API) handleResetChaosFaultLog(w http.ResponseWriter, r *http.Request, engine *engineclient.Client) {
+	if err := engine.ResetChaosFaultLog(r.Context()); err != nil {
+		writeError(w, http.StatusServiceUnavailable, "engine_error", sanitizeEngineError(err, a.logger(), "reset chaos fault log"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "chaos fault log reset"})
+}
+
 // --- Chaos Profiles ---
 
 // chaosProfileResponse is the API-level representation of a chaos profile.
@@ -79,20 +108,73 @@ type chaosProfileResponse struct {
 	Name        string                   `json:"name"`
 	Description string                   `json:"description"`
 	Config      engineclient.ChaosConfig `json:"config"`
+	// Builtin is false for user-defined profiles, which can be updated and deleted.
+	Builtin bool `json:"builtin"`
 }
 
-// handleListChaosProfiles returns all available built-in chaos profiles.
+// chaosProfileRequest is the body of POST /chaos/profiles and PUT /chaos/profiles/{name}.
+type chaosProfileRequest struct {
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Config      engineclient.ChaosConfig `json:"config"`
+}
+
+// chaosProfileNameRe restricts custom profile names to URL-safe characters.
+var chaosProfileNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+func builtinProfileResponse(p chaos.Profile) chaosProfileResponse {
+	return chaosProfileResponse{
+		Name:        p.Name,
+		Description: p.Description,
+		Config:      chaosConfigToAPI(&p.Config),
+		Builtin:     true,
+	}
+}
+
+func customProfileResponse(p *config.ChaosProfileConfig) chaosProfileResponse {
+	return chaosProfileResponse{
+		Name:        p.Name,
+		Description: p.Description,
+		Config:      chaosConfigToAPI(&p.Config),
+	}
+}
+
+// lookupChaosProfile resolves a profile name to its response form, checking
+// the built-in profiles before the user-defined ones in the store.
+func (a *API) lookupChaosProfile(ctx context.Context, name string) (chaosProfileResponse, bool) {
+	if p, ok := chaos.GetProfile(name); ok {
+		return builtinProfileResponse(p), true
+	}
+	if a.dataStore == nil {
+		return chaosProfileResponse{}, false
+	}
+	p, err := a.dataStore.ChaosProfiles().Get(ctx, name)
+	if err != nil {
+		return chaosProfileResponse{}, false
+	}
+	return customProfileResponse(p), true
+}
+
+// handleListChaosProfiles returns the built-in and user-defined chaos
+// profiles, sorted by name.
 func (a *API) handleListChaosProfiles(w http.ResponseWriter, r *http.Request) {
 	profiles := chaos.ListProfiles()
 
 	resp := make([]chaosProfileResponse, 0, len(profiles))
 	for _, p := range profiles {
-		resp = append(resp, chaosProfileResponse{
-			Name:        p.Name,
-			Description: p.Description,
-			Config:      chaosConfigToAPI(&p.Config),
-		})
+		resp = append(resp, builtinProfileResponse(p))
+	}
+
+	if a.dataStore != nil {
+		custom, err := a.dataStore.ChaosProfiles().List(r.Context())
+		if err != nil {
+			a.logger().Warn("failed to list custom chaos profiles", "error", err)
+		}
+		for _, p := range custom {
+			resp = append(resp, customProfileResponse(p))
+		}
 	}
+	sort.Slice(resp, func(i, j int) bool { return resp[i].Name < resp[j].Name })
 
 	writeJSON(w, http.StatusOK, resp)
 }
@@ -105,17 +187,134 @@ func (a *API) handleGetChaosProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	p, ok := chaos.GetProfile(name)
+	p, ok := a.lookupChaosProfile(r.Context(), name)
 	if !ok {
 		writeError(w, http.StatusNotFound, "not_found", "chaos profile not found: "+name)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, chaosProfileResponse{
-		Name:        p.Name,
-		Description: p.Description,
-		Config:      chaosConfigToAPI(&p.Config),
-	})
+	writeJSON(w, http.StatusOK, p)
+}
+
+// decodeChaosProfile decodes and validates a chaos profile request body. The
+// profile's chaos config is always enabled, so applying it turns chaos on
+// like a built-in profile does. It writes an HTTP error and returns nil on failure.
+func (a *API) decodeChaosProfile(w http.ResponseWriter, r *http.Request) *config.ChaosProfileConfig {
+	var req chaosProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONDecodeError(w, err, a.logger())
+		return nil
+	}
+	if !chaosProfileNameRe.MatchString(req.Name) {
+		writeError(w, http.StatusBadRequest, "validation_error",
+			"profile name is required and may only contain letters, digits, '.', '_' and '-'")
+		return nil
+	}
+
+	cfg := types.ChaosConfigToInternal(&req.Config)
+	cfg.Enabled = true
+	if err := cfg.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, "validation_error", err.Error())
+		return nil
+	}
+
+	return &config.ChaosProfileConfig{
+		Name:        req.Name,
+		Description: req.Description,
+		Config:      *cfg,
+	}
+}
+
+// writeChaosProfileStoreError maps a chaos profile store error to a response.
+func (a *API) writeChaosProfileStoreError(w http.ResponseWriter, err error, name, op string) {
+	switch {
+	case errors.Is(err, store.ErrAlreadyExists):
+		writeError(w, http.StatusConflict, "already_exists", "chaos profile already exists: "+name)
+	case errors.Is(err, store.ErrNotFound):
+		writeError(w, http.StatusNotFound, "not_found", "chaos profile not found: "+name)
+	case errors.Is(err, store.ErrReadOnly):
+		writeError(w, http.StatusForbidden, "read_only", "data store is read-only")
+	default:
+		a.logger().Error("failed to "+op+" chaos profile", "name", name, "error", err)
+		writeError(w, http.StatusInternalServerError, "store_error", ErrMsgInternalError)
+	}
+}
+
+// handleCreateChaosProfile creates a user-defined chaos profile.
+func (a *API) handleCreateChaosProfile(w http.ResponseWriter, r *http.Request) {
+	if a.dataStore == nil {
+		writeError(w, http.StatusServiceUnavailable, "store_unavailable", "Data store not available")
+		return
+	}
+
+	profile := a.decodeChaosProfile(w, r)
+	if profile == nil {
+		return
+	}
+	if _, ok := chaos.GetProfile(profile.Name); ok {
+		writeError(w, http.StatusConflict, "builtin_profile", "a built-in chaos profile is named "+profile.Name)
+		return
+	}
+
+	if err := a.dataStore.ChaosProfiles().Create(r.Context(), profile); err != nil {
+		a.writeChaosProfileStoreError(w, err, profile.Name, "create")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, customProfileResponse(profile))
+}
+
+// handleUpdateChaosProfile replaces a user-defined chaos profile. Built-in
+// profiles cannot be changed.
+func (a *API) handleUpdateChaosProfile(w http.ResponseWriter, r *http.Request) {
+	if a.dataStore == nil {
+		writeError(w, http.StatusServiceUnavailable, "store_unavailable", "Data store not available")
+		return
+	}
+
+	name := r.PathValue("name")
+	if _, ok := chaos.GetProfile(name); ok {
+		writeError(w, http.StatusConflict, "builtin_profile", "built-in chaos profiles cannot be modified: "+name)
+		return
+	}
+
+	profile := a.decodeChaosProfile(w, r)
+	if profile == nil {
+		return
+	}
+	if profile.Name != name {
+		writeError(w, http.StatusBadRequest, "validation_error", "profile name in body does not match the URL")
+		return
+	}
+
+	if err := a.dataStore.ChaosProfiles().Update(r.Context(), profile); err != nil {
+		a.writeChaosProfileStoreError(w, err, name, "update")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, customProfileResponse(profile))
+}
+
+// handleDeleteChaosProfile deletes a user-defined chaos profile. Built-in
+// profiles cannot be deleted.
+func (a *API) handleDeleteChaosProfile(w http.ResponseWriter, r *http.Request) {
+	if a.dataStore == nil {
+		writeError(w, http.StatusServiceUnavailable, "store_unavailable", "Data store not available")
+		return
+	}
+
+	name := r.PathValue("name")
+	if _, ok := chaos.GetProfile(name); ok {
+		writeError(w, http.StatusConflict, "builtin_profile", "built-in chaos profiles cannot be deleted: "+name)
+		return
+	}
+
+	if err := a.dataStore.ChaosProfiles().Delete(r.Context(), name); err != nil {
+		a.writeChaosProfileStoreError(w, err, name, "delete")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
 
 // handleApplyChaosProfile applies a named chaos profile to the engine.
@@ -128,14 +327,13 @@ func (a *API) handleApplyChaosProfile(w http.ResponseWriter, r *http.Request, en
 		return
 	}
 
-	p, ok := chaos.GetProfile(name)
+	p, ok := a.lookupChaosProfile(ctx, name)
 	if !ok {
 		writeError(w, http.StatusNotFound, "not_found", "chaos profile not found: "+name)
 		return
 	}
 
-	apiConfig := chaosConfigToAPI(&p.Config)
-	if err := engine.SetChaos(ctx, &apiConfig); err != nil {
+	if err := engine.SetChaos(ctx, &p.Config); err != nil {
 		errMsg := err.Error()
 		if strings.Contains(errMsg, "must be between") || strings.Contains(errMsg, "validation") {
 			writeError(w, http.StatusBadRequest, "validation_error", errMsg)
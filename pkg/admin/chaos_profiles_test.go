@@ -0,0 +1,171 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getmockd/mockd/pkg/admin/engineclient"
+	"github.com/getmockd/mockd/pkg/chaos"
+	"github.com/getmockd/mockd/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func chaosProfileBody(t *testing.T, name, description string, cfg engineclient.ChaosConfig) *bytes.Reader {
+	t.Helper()
+	body, err := json.Marshal(chaosProfileRequest{Name: name, Description: description, Config: cfg})
+	require.NoError(t, err)
+	return bytes.NewReader(body)
+}
+
+func TestCustomChaosProfiles(t *testing.T) {
+	server := newMockChaosEngineServer()
+	defer server.Close()
+	api := NewAPI(0, WithDataDir(t.TempDir()), WithLocalEngineClient(server.client()))
+
+	degraded := engineclient.ChaosConfig{
+		Latency:   &engineclient.LatencyConfig{Min: "300ms", Max: "1200ms", Probability: 1},
+		ErrorRate: &engineclient.ErrorRateConfig{Probability: 0.05, StatusCodes: []int{502, 503}},
+	}
+
+	// Create
+	req := httptest.NewRequest(http.MethodPost, "/chaos/profiles", chaosProfileBody(t, "partner-degraded", "Partner API at its SLO limits", degraded))
+	rec := httptest.NewRecorder()
+	api.handleCreateChaosProfile(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var created chaosProfileResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.False(t, created.Builtin)
+	assert.True(t, created.Config.Enabled, "custom profiles always enable chaos")
+
+	// Duplicate names and built-in names conflict.
+	for _, name := range []string{"partner-degraded", "slow-api"} {
+		req = httptest.NewRequest(http.MethodPost, "/chaos/profiles", chaosProfileBody(t, name, "", degraded))
+		rec = httptest.NewRecorder()
+		api.handleCreateChaosProfile(rec, req)
+		assert.Equal(t, http.StatusConflict, rec.Code, name)
+	}
+
+	// Listed alongside the built-ins.
+	rec = httptest.NewRecorder()
+	api.handleListChaosProfiles(rec, httptest.NewRequest(http.MethodGet, "/chaos/profiles", nil))
+	var profiles []chaosProfileResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &profiles))
+	require.Len(t, profiles, len(chaos.ListProfiles())+1)
+	for i := 1; i < len(profiles); i++ {
+		assert.Less(t, profiles[i-1].Name, profiles[i].Name)
+	}
+
+	// Update
+	down := engineclient.ChaosConfig{ErrorRate: &engineclient.ErrorRateConfig{Probability: 1, DefaultCode: 503}}
+	req = httptest.NewRequest(http.MethodPut, "/chaos/profiles/partner-degraded", chaosProfileBody(t, "partner-degraded", "Partner API down", down))
+	req.SetPathValue("name", "partner-degraded")
+	rec = httptest.NewRecorder()
+	api.handleUpdateChaosProfile(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/chaos/profiles/partner-degraded", nil)
+	req.SetPathValue("name", "partner-degraded")
+	rec = httptest.NewRecorder()
+	api.handleGetChaosProfile(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var got chaosProfileResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, "Partner API down", got.Description)
+	assert.Nil(t, got.Config.Latency)
+
+	// Apply by name
+	req = httptest.NewRequest(http.MethodPost, "/chaos/profiles/partner-degraded/apply", nil)
+	req.SetPathValue("name", "partner-degraded")
+	rec = httptest.NewRecorder()
+	api.handleApplyChaosProfile(rec, req, server.client())
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.True(t, server.chaosConfig.Enabled)
+	require.NotNil(t, server.chaosConfig.ErrorRate)
+	assert.Equal(t, 503, server.chaosConfig.ErrorRate.DefaultCode)
+
+	// Delete
+	req = httptest.NewRequest(http.MethodDelete, "/chaos/profiles/partner-degraded", nil)
+	req.SetPathValue("name", "partner-degraded")
+	rec = httptest.NewRecorder()
+	api.handleDeleteChaosProfile(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = httptest.NewRecorder()
+	api.handleDeleteChaosProfile(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestCustomChaosProfiles_Rejected(t *testing.T) {
+	api := NewAPI(0, WithDataDir(t.TempDir()))
+
+	tests := []struct {
+		name    string
+		profile string
+		config  engineclient.ChaosConfig
+		want    int
+	}{
+		{"missing name", "", engineclient.ChaosConfig{}, http.StatusBadRequest},
+		{"unsafe name", "a/b", engineclient.ChaosConfig{}, http.StatusBadRequest},
+		{"invalid config", "bad", engineclient.ChaosConfig{ErrorRate: &engineclient.ErrorRateConfig{Probability: 2}}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/chaos/profiles", chaosProfileBody(t, tt.profile, "", tt.config))
+			rec := httptest.NewRecorder()
+			api.handleCreateChaosProfile(rec, req)
+			assert.Equal(t, tt.want, rec.Code, rec.Body.String())
+		})
+	}
+
+	// Built-in profiles cannot be modified or deleted.
+	req := httptest.NewRequest(http.MethodPut, "/chaos/profiles/flaky", chaosProfileBody(t, "flaky", "", engineclient.ChaosConfig{}))
+	req.SetPathValue("name", "flaky")
+	rec := httptest.NewRecorder()
+	api.handleUpdateChaosProfile(rec, req)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	req = httptest.NewRequest(http.MethodDelete, "/chaos/profiles/flaky", nil)
+	req.SetPathValue("name", "flaky")
+	rec = httptest.NewRecorder()
+	api.handleDeleteChaosProfile(rec, req)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestCustomChaosProfiles_ExportImport(t *testing.T) {
+	api := NewAPI(0, WithDataDir(t.TempDir()))
+	ctx := t.Context()
+
+	api.persistChaosProfiles(ctx, &config.MockCollection{ChaosProfiles: []*config.ChaosProfileConfig{
+		{
+			Name:   "partner-degraded",
+			Config: chaos.ChaosConfig{GlobalRules: &chaos.GlobalChaosRules{Latency: &chaos.LatencyFault{Min: "300ms", Max: "1s", Probability: 1}}},
+		},
+		{Name: "slow-api"}, // reserved for the built-in profile
+	}})
+
+	rec := httptest.NewRecorder()
+	api.handleExportConfig(rec, httptest.NewRequest(http.MethodGet, "/config", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var collection config.MockCollection
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &collection))
+	require.Len(t, collection.ChaosProfiles, 1)
+	exported := collection.ChaosProfiles[0]
+	assert.Equal(t, "partner-degraded", exported.Name)
+	assert.True(t, exported.Config.Enabled)
+	require.NotNil(t, exported.Config.GlobalRules)
+	assert.Equal(t, "1s", exported.Config.GlobalRules.Latency.Max)
+
+	// Re-importing updates the profile in place.
+	exported.Description = "v2"
+	api.persistChaosProfiles(ctx, &collection)
+	stored, err := api.dataStore.ChaosProfiles().List(ctx)
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, "v2", stored[0].Description)
+}
@@ -552,6 +552,110 @@ func (c *Client) ResetChaosStats(ctx context.Context) error {
 	return nil
 }
 
+// GetChaosFaultLog returns the chaos fault log.
+func (c *Client) GetChaosFaultLog(ctx context.Context) (*ChaosFaultLog, error) {
+	resp, err := c.get(ctx, "/chaos/fault-log")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var log ChaosFaultLog
+	if err := json.NewDecoder(resp.Body).Decode(&log); err != nil {
+		return nil, fmt.Errorf("failed to decode chaos fault log: %w", err)
+	}
+	return &log, nil
+}
+
+// ResetChaosFaultLog clears the chaos fault log.
+func (c *Client) ResetChaosFaultLog(ctx context.Context) error {
+	resp, err := c.post(ctx, "/chaos/fault-log/reset", nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return c.parseError(resp)
+	}
+	return nil
+}
+
+// GetShadow returns the shadow mode configuration.
+func (c *Client) GetShadow(ctx context.Context) (*ShadowConfig, error) {
+	resp, err := c.get(ctx, "/shadow")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var cfg ShadowConfig
+	if err := json.NewDecoder(resp.Body).Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode shadow config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// SetShadow updates the shadow mode configuration.
+func (c *Client) SetShadow(ctx context.Context, cfg *ShadowConfig) error {
+	resp, err := c.put(ctx, "/shadow", cfg)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return c.parseError(resp)
+	}
+	return nil
+}
+
+// GetDriftReport returns the shadow mode drift report. A non-empty mockID
+// limits it to that mock.
+func (c *Client) GetDriftReport(ctx context.Context, mockID string) (*DriftReport, error) {
+	path := "/shadow/drift"
+	if mockID != "" {
+		path += "?mockId=" + url.QueryEscape(mockID)
+	}
+	resp, err := c.get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var report DriftReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to decode drift report: %w", err)
+	}
+	return &report, nil
+}
+
+// ResetDrift clears the shadow mode drift report.
+func (c *Client) ResetDrift(ctx context.Context) error {
+	resp, err := c.post(ctx, "/shadow/drift/reset", nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return c.parseError(resp)
+	}
+	return nil
+}
+
 // GetStatefulFaultStats returns stats for all stateful chaos faults.
 func (c *Client) GetStatefulFaultStats(ctx context.Context) (*StatefulFaultStats, error) {
 	resp, err := c.get(ctx, "/chaos/faults")
@@ -801,6 +905,193 @@ func (c *Client) CreateStatefulItem(ctx context.Context, workspaceID, resourceNa
 	return item, nil
 }
 
+// UpdateStatefulItem replaces an item in a stateful resource (PUT semantics).
+func (c *Client) UpdateStatefulItem(ctx context.Context, workspaceID, resourceName, itemID string, data map[string]interface{}) (map[string]interface{}, error) {
+	return c.writeStatefulItem(ctx, http.MethodPut, workspaceID, resourceName, itemID, data)
+}
+
+// PatchStatefulItem merges fields into an item in a stateful resource (PATCH semantics).
+func (c *Client) PatchStatefulItem(ctx context.Context, workspaceID, resourceName, itemID string, data map[string]interface{}) (map[string]interface{}, error) {
+	return c.writeStatefulItem(ctx, http.MethodPatch, workspaceID, resourceName, itemID, data)
+}
+
+func (c *Client) writeStatefulItem(ctx context.Context, method, workspaceID, resourceName, itemID string, data map[string]interface{}) (map[string]interface{}, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(data); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+statefulItemPath(workspaceID, resourceName, itemID), &buf)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := c.statefulItemError(resp); err != nil {
+		return nil, err
+	}
+
+	var item map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&item); err != nil {
+		return nil, fmt.Errorf("failed to decode stateful item: %w", err)
+	}
+	return item, nil
+}
+
+// DeleteStatefulItem removes an item from a stateful resource.
+func (c *Client) DeleteStatefulItem(ctx context.Context, workspaceID, resourceName, itemID string) error {
+	resp, err := c.delete(ctx, statefulItemPath(workspaceID, resourceName, itemID))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	return c.statefulItemError(resp)
+}
+
+// invalidRequestError wraps ErrInvalid around the engine's error message,
+// without the error code prefix, so callers can surface it as-is.
+func (c *Client) invalidRequestError(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+	var errResp ErrorResponse
+	if json.Unmarshal(body, &errResp) == nil && errResp.Message != "" {
+		return fmt.Errorf("%w: %s", ErrInvalid, errResp.Message)
+	}
+	return fmt.Errorf("%w: status %d", ErrInvalid, resp.StatusCode)
+}
+
+// statefulItemError maps a non-2xx stateful item response to a sentinel error.
+func (c *Client) statefulItemError(resp *http.Response) error {
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return nil
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return fmt.Errorf("%w: %v", ErrConflict, c.parseError(resp))
+	case http.StatusBadRequest:
+		return c.invalidRequestError(resp)
+	case http.StatusInsufficientStorage:
+		return fmt.Errorf("%w: %v", ErrCapacity, c.parseError(resp))
+	default:
+		return c.parseError(resp)
+	}
+}
+
+func statefulItemPath(workspaceID, resourceName, itemID string) string {
+	path := "/state/resources/" + url.PathEscape(resourceName) + "/items/" + url.PathEscape(itemID)
+	if workspaceID != "" {
+		path += "?workspaceId=" + url.QueryEscape(workspaceID)
+	}
+	return path
+}
+
+// ImportStatefulItems streams a bulk payload into a stateful resource.
+// params may carry format, mode, validate and stopOnError; contentType is
+// forwarded so the engine can infer the format when none is given.
+func (c *Client) ImportStatefulItems(ctx context.Context, workspaceID, resourceName string, params url.Values, contentType string, body io.Reader) (*StatefulImportResponse, error) {
+	q := url.Values{}
+	for k, v := range params {
+		q[k] = v
+	}
+	if workspaceID != "" {
+		q.Set("workspaceId", workspaceID)
+	}
+	path := "/state/resources/" + url.PathEscape(resourceName) + "/import"
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := c.doStreaming(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return importError(resp)
+	}
+
+	var result StatefulImportResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode import response: %w", err)
+	}
+	return &result, nil
+}
+
+// importError maps a failed import response to a sentinel error. The partial
+// result in the error details, if any, is returned alongside it.
+func importError(resp *http.Response) (*StatefulImportResponse, error) {
+	body, _ := io.ReadAll(resp.Body)
+	var errResp struct {
+		Error   string                  `json:"error"`
+		Message string                  `json:"message"`
+		Details *StatefulImportResponse `json:"details"`
+	}
+	if json.Unmarshal(body, &errResp) != nil || errResp.Message == "" {
+		errResp.Message = fmt.Sprintf("status %d", resp.StatusCode)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return errResp.Details, ErrNotFound
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge:
+		return errResp.Details, fmt.Errorf("%w: %s", ErrInvalid, errResp.Message)
+	default:
+		return errResp.Details, fmt.Errorf("%s: %s", errResp.Error, errResp.Message)
+	}
+}
+
+// ExportStatefulItems streams every item in a stateful resource in the given
+// format ("json", "ndjson" or "csv"). The caller must close the returned body.
+func (c *Client) ExportStatefulItems(ctx context.Context, workspaceID, resourceName, format string) (io.ReadCloser, string, error) {
+	q := url.Values{}
+	if format != "" {
+		q.Set("format", format)
+	}
+	if workspaceID != "" {
+		q.Set("workspaceId", workspaceID)
+	}
+	path := "/state/resources/" + url.PathEscape(resourceName) + "/export"
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := c.doStreaming(req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, resp.Header.Get("Content-Type"), nil
+	case http.StatusNotFound:
+		_ = resp.Body.Close()
+		return nil, "", ErrNotFound
+	case http.StatusBadRequest:
+		defer func() { _ = resp.Body.Close() }()
+		return nil, "", c.invalidRequestError(resp)
+	default:
+		defer func() { _ = resp.Body.Close() }()
+		return nil, "", c.parseError(resp)
+	}
+}
+
 // RegisterStatefulResource registers a new stateful resource definition on the engine.
 func (c *Client) RegisterStatefulResource(ctx context.Context, workspaceID string, cfg *config.StatefulResourceConfig) error {
 	path := "/state/resources"
@@ -844,6 +1135,80 @@ func (c *Client) DeleteStatefulResource(ctx context.Context, workspaceID, name s
 	return nil
 }
 
+// ListStatePartitions returns the live isolation partitions of a workspace.
+func (c *Client) ListStatePartitions(ctx context.Context, workspaceID string) ([]*StatePartition, error) {
+	path := "/state/partitions"
+	if workspaceID != "" {
+		path += "?workspaceId=" + url.QueryEscape(workspaceID)
+	}
+	resp, err := c.get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var result struct {
+		Partitions []*StatePartition `json:"partitions"`
+		Count      int               `json:"count"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode state partitions: %w", err)
+	}
+	return result.Partitions, nil
+}
+
+// GetStatePartition returns a single isolation partition.
+func (c *Client) GetStatePartition(ctx context.Context, workspaceID, key string) (*StatePartition, error) {
+	path := "/state/partitions/" + url.PathEscape(key)
+	if workspaceID != "" {
+		path += "?workspaceId=" + url.QueryEscape(workspaceID)
+	}
+	resp, err := c.get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var partition StatePartition
+	if err := json.NewDecoder(resp.Body).Decode(&partition); err != nil {
+		return nil, fmt.Errorf("failed to decode state partition: %w", err)
+	}
+	return &partition, nil
+}
+
+// ResetStatePartition discards an isolation partition so that its next
+// request starts again from seed data.
+func (c *Client) ResetStatePartition(ctx context.Context, workspaceID, key string) error {
+	path := "/state/partitions/" + url.PathEscape(key)
+	if workspaceID != "" {
+		path += "?workspaceId=" + url.QueryEscape(workspaceID)
+	}
+	resp, err := c.delete(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return c.parseError(resp)
+	}
+	return nil
+}
+
 // ListCustomOperations returns all registered custom operations.
 func (c *Client) ListCustomOperations(ctx context.Context, workspaceID string) ([]CustomOperationInfo, error) {
 	path := "/state/operations"
@@ -1400,6 +1765,18 @@ func (c *Client) do(req *http.Request) (*http.Response, error) {
 	return c.httpClient.Do(req) //nolint:gosec // G704 — admin→engine internal client; base URL is config-sourced
 }
 
+// doStreaming sends a request without the client-wide timeout. Bulk transfers
+// are bounded by the caller's context instead, since large tables can take
+// longer than the default timeout to stream.
+func (c *Client) doStreaming(req *http.Request) (*http.Response, error) {
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	streaming := *c.httpClient
+	streaming.Timeout = 0
+	return streaming.Do(req) //nolint:gosec // G704 — admin→engine internal client; base URL is config-sourced
+}
+
 func (c *Client) parseError(resp *http.Response) error {
 	body, _ := io.ReadAll(resp.Body)
 	var errResp ErrorResponse
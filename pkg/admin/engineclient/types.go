@@ -18,6 +18,8 @@ var (
 	ErrConflict = errors.New("conflict")
 	// ErrCapacity is returned when a stateful resource is at max capacity (507).
 	ErrCapacity = errors.New("capacity exceeded")
+	// ErrInvalid is returned when the engine rejects a request payload (400).
+	ErrInvalid = errors.New("invalid request")
 )
 
 // Type aliases that point to the canonical shared types in pkg/api/types/.
@@ -41,9 +43,16 @@ type (
 	ChaosRuleConfig              = types.ChaosRuleConfig
 	ChaosFaultConfig             = types.ChaosFaultConfig
 	ChaosStats                   = types.ChaosStats
+	ChaosFaultLog                = types.ChaosFaultLog
+	ShadowConfig                 = types.ShadowConfig
+	DriftReport                  = types.DriftReport
+	MockDrift                    = types.MockDrift
 	StatefulResource             = types.StatefulResource
 	StatefulItemsResponse        = types.StatefulItemsResponse
+	StatefulImportResponse       = types.StatefulImportResponse
+	StatefulImportError          = types.StatefulImportError
 	StateOverview                = types.StateOverview
+	StatePartition               = types.StatePartition
 	ProtocolHandler              = types.ProtocolHandler
 	SSEConnection                = types.SSEConnection
 	SSEStats                     = types.SSEStats
@@ -0,0 +1,489 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/getmockd/mockd/pkg/grpc"
+	"github.com/getmockd/mockd/pkg/logging"
+	"github.com/getmockd/mockd/pkg/mock"
+	"github.com/getmockd/mockd/pkg/recording"
+)
+
+// GRPCRecordingManager manages gRPC recording proxies and their recordings
+// for the Admin API.
+type GRPCRecordingManager struct {
+	mu      sync.RWMutex
+	log     *slog.Logger
+	store   *recording.GRPCStore
+	proxies map[string]*grpc.RecordingProxy // proxy ID -> proxy
+}
+
+// NewGRPCRecordingManager creates a new gRPC recording manager.
+func NewGRPCRecordingManager() *GRPCRecordingManager {
+	return &GRPCRecordingManager{
+		log:     logging.Nop(),
+		store:   recording.NewGRPCStore(1000),
+		proxies: make(map[string]*grpc.RecordingProxy),
+	}
+}
+
+// SetLogger sets the logger under the manager's own lock.
+func (m *GRPCRecordingManager) SetLogger(log *slog.Logger) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if log != nil {
+		m.log = log
+	} else {
+		m.log = logging.Nop()
+	}
+}
+
+// Store returns the gRPC recording store.
+func (m *GRPCRecordingManager) Store() *recording.GRPCStore {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.store
+}
+
+// StopAll stops every recording proxy.
+func (m *GRPCRecordingManager) StopAll() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, proxy := range m.proxies {
+		if err := proxy.Stop(); err != nil {
+			m.log.Warn("error stopping gRPC recording proxy", "id", id, "error", err)
+		}
+		delete(m.proxies, id)
+	}
+}
+
+// grpcStoreAdapter adapts GRPCStore to the grpc.GRPCRecordingStore interface.
+type grpcStoreAdapter struct {
+	store    *recording.GRPCStore
+	redactor *recording.Redactor
+}
+
+func (a *grpcStoreAdapter) Add(data grpc.GRPCRecordingData) error {
+	// Convert from grpc.GRPCRecordingData to recording.GRPCRecording
+	rec := recording.NewGRPCRecording(data.Service, data.Method, data.StreamType)
+	rec.Metadata = data.Metadata
+	rec.Requests = data.Requests
+	rec.Responses = data.Responses
+	rec.ResponseHeaders = data.ResponseHeaders
+	rec.Trailers = data.Trailers
+	rec.StatusCode = data.StatusCode
+	rec.StatusMessage = data.StatusMessage
+	rec.Duration = data.Duration
+	rec.ProtoFiles = data.ProtoFiles
+	rec.ImportPaths = data.ImportPaths
+	rec.ProtoContent = data.ProtoContent
+	a.redactor.RedactGRPC(rec)
+	return a.store.Add(rec)
+}
+
+// Request/Response types
+
+// GRPCRecordingListResponse represents a list of gRPC recordings.
+type GRPCRecordingListResponse struct {
+	Recordings []*recording.GRPCRecording `json:"recordings"`
+	Total      int                        `json:"total"`
+	Limit      int                        `json:"limit,omitempty"`
+	Offset     int                        `json:"offset,omitempty"`
+}
+
+// GRPCRecordingStatsResponse represents gRPC recording statistics.
+type GRPCRecordingStatsResponse struct {
+	*recording.GRPCRecordingStats
+}
+
+// GRPCProxyStartRequest represents a request to start a gRPC recording proxy.
+type GRPCProxyStartRequest struct {
+	grpc.RecordingProxyConfig
+
+	// Redaction rules are applied to recordings before they are stored.
+	Redaction *recording.RedactionConfig `json:"redaction,omitempty"`
+}
+
+// GRPCProxyListResponse represents the running gRPC recording proxies.
+type GRPCProxyListResponse struct {
+	Proxies []grpc.RecordingProxyStatus `json:"proxies"`
+	Count   int                         `json:"count"`
+}
+
+// GRPCConvertRequest represents a request to convert recordings to a gRPC mock.
+type GRPCConvertRequest struct {
+	RecordingIDs   []string `json:"recordingIds,omitempty"`
+	Service        string   `json:"service,omitempty"`
+	Method         string   `json:"method,omitempty"`
+	MatchRequest   *bool    `json:"matchRequest,omitempty"`
+	MatchMetadata  []string `json:"matchMetadata,omitempty"`
+	IncludeDelay   bool     `json:"includeDelay,omitempty"`
+	PreserveErrors *bool    `json:"preserveErrors,omitempty"`
+	Port           int      `json:"port,omitempty"`
+}
+
+// options returns the conversion options, defaulting unset fields.
+func (req *GRPCConvertRequest) options() recording.GRPCConvertOptions {
+	opts := recording.DefaultGRPCConvertOptions()
+	if req.MatchRequest != nil {
+		opts.MatchRequest = *req.MatchRequest
+	}
+	if req.PreserveErrors != nil {
+		opts.PreserveErrors = *req.PreserveErrors
+	}
+	if req.Port > 0 {
+		opts.Port = req.Port
+	}
+	opts.MatchMetadata = req.MatchMetadata
+	opts.IncludeDelay = req.IncludeDelay
+	return opts
+}
+
+// GRPCConvertResponse represents the result of converting recordings.
+type GRPCConvertResponse struct {
+	Spec         *mock.GRPCSpec `json:"spec"`
+	ServiceCount int            `json:"serviceCount"`
+	MethodCount  int            `json:"methodCount"`
+	VariantCount int            `json:"variantCount"`
+	Total        int            `json:"total"`
+	Warnings     []string       `json:"warnings,omitempty"`
+}
+
+func newGRPCConvertResponse(result *recording.GRPCConvertResult) GRPCConvertResponse {
+	return GRPCConvertResponse{
+		Spec:         result.Spec,
+		ServiceCount: result.ServiceCount,
+		MethodCount:  result.MethodCount,
+		VariantCount: result.VariantCount,
+		Total:        result.Total,
+		Warnings:     result.Warnings,
+	}
+}
+
+// Proxy handlers
+
+// handleListGRPCProxies handles GET /grpc-proxies.
+func (m *GRPCRecordingManager) handleListGRPCProxies(w http.ResponseWriter, _ *http.Request) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	proxies := make([]grpc.RecordingProxyStatus, 0, len(m.proxies))
+	for _, proxy := range m.proxies {
+		proxies = append(proxies, proxy.Status())
+	}
+
+	writeJSON(w, http.StatusOK, GRPCProxyListResponse{
+		Proxies: proxies,
+		Count:   len(proxies),
+	})
+}
+
+// handleStartGRPCProxy handles POST /grpc-proxies.
+func (m *GRPCRecordingManager) handleStartGRPCProxy(w http.ResponseWriter, r *http.Request) {
+	var req GRPCProxyStartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONDecodeError(w, err, m.log)
+		return
+	}
+
+	redactor, err := recording.NewRedactor(req.Redaction)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_redaction", err.Error())
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if req.ID != "" && m.proxies[req.ID] != nil {
+		writeError(w, http.StatusConflict, "already_exists", fmt.Sprintf("gRPC recording proxy '%s' already exists", req.ID))
+		return
+	}
+
+	proxy, err := grpc.NewRecordingProxy(req.RecordingProxyConfig, &grpcStoreAdapter{store: m.store, redactor: redactor})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "validation_error", err.Error())
+		return
+	}
+	proxy.SetLogger(m.log)
+	if err := proxy.Start(); err != nil {
+		_ = proxy.Stop()
+		writeError(w, http.StatusBadRequest, "start_failed", err.Error())
+		return
+	}
+	if m.proxies[proxy.ID()] != nil {
+		_ = proxy.Stop()
+		writeError(w, http.StatusConflict, "already_exists", fmt.Sprintf("gRPC recording proxy '%s' already exists", proxy.ID()))
+		return
+	}
+	m.proxies[proxy.ID()] = proxy
+
+	m.log.Info("gRPC recording proxy started", "id", proxy.ID(), "listen", proxy.Addr(), "upstream", req.Upstream)
+	writeJSON(w, http.StatusCreated, proxy.Status())
+}
+
+// handleGetGRPCProxy handles GET /grpc-proxies/{id}.
+func (m *GRPCRecordingManager) handleGetGRPCProxy(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	m.mu.RLock()
+	proxy := m.proxies[id]
+	m.mu.RUnlock()
+
+	if proxy == nil {
+		writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("gRPC recording proxy '%s' not found", id))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, proxy.Status())
+}
+
+// handleStopGRPCProxy handles DELETE /grpc-proxies/{id}.
+func (m *GRPCRecordingManager) handleStopGRPCProxy(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	m.mu.Lock()
+	proxy := m.proxies[id]
+	delete(m.proxies, id)
+	m.mu.Unlock()
+
+	if proxy == nil {
+		writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("gRPC recording proxy '%s' not found", id))
+		return
+	}
+	if err := proxy.Stop(); err != nil {
+		m.log.Warn("error stopping gRPC recording proxy", "id", id, "error", err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Recording handlers
+
+// handleListGRPCRecordings handles GET /grpc-recordings.
+func (m *GRPCRecordingManager) handleListGRPCRecordings(w http.ResponseWriter, r *http.Request) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.store == nil {
+		writeJSON(w, http.StatusOK, GRPCRecordingListResponse{
+			Recordings: []*recording.GRPCRecording{},
+			Total:      0,
+		})
+		return
+	}
+
+	filter := recording.GRPCRecordingFilter{}
+
+	// Parse query parameters
+	if service := r.URL.Query().Get("service"); service != "" {
+		filter.Service = service
+	}
+	if method := r.URL.Query().Get("method"); method != "" {
+		filter.Method = method
+	}
+	if statusCode := r.URL.Query().Get("statusCode"); statusCode != "" {
+		filter.StatusCode = statusCode
+	}
+	if limit, ok := parsePositiveInt(r.URL.Query().Get("limit")); ok {
+		filter.Limit = limit
+	}
+	if offset, ok := parseNonNegativeInt(r.URL.Query().Get("offset")); ok {
+		filter.Offset = offset
+	}
+
+	recordings, total := m.store.List(filter)
+
+	writeJSON(w, http.StatusOK, GRPCRecordingListResponse{
+		Recordings: recordings,
+		Total:      total,
+		Limit:      filter.Limit,
+		Offset:     filter.Offset,
+	})
+}
+
+// handleGetGRPCRecording handles GET /grpc-recordings/{id}.
+func (m *GRPCRecordingManager) handleGetGRPCRecording(w http.ResponseWriter, r *http.Request) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "missing_id", "Recording ID is required")
+		return
+	}
+
+	if m.store == nil {
+		writeError(w, http.StatusNotFound, "not_found", "Recording not found")
+		return
+	}
+
+	rec := m.store.Get(id)
+	if rec == nil {
+		writeError(w, http.StatusNotFound, "not_found", "Recording not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rec)
+}
+
+// handleDeleteGRPCRecording handles DELETE /grpc-recordings/{id}.
+func (m *GRPCRecordingManager) handleDeleteGRPCRecording(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "missing_id", "Recording ID is required")
+		return
+	}
+
+	if m.store == nil {
+		writeError(w, http.StatusNotFound, "not_found", "Recording not found")
+		return
+	}
+
+	if err := m.store.Delete(id); err != nil {
+		writeError(w, http.StatusNotFound, "not_found", "Recording not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleClearGRPCRecordings handles DELETE /grpc-recordings.
+func (m *GRPCRecordingManager) handleClearGRPCRecordings(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.store == nil {
+		writeJSON(w, http.StatusOK, map[string]int{"deleted": 0})
+		return
+	}
+
+	count := m.store.Clear()
+	writeJSON(w, http.StatusOK, map[string]int{"deleted": count})
+}
+
+// handleGetGRPCRecordingStats handles GET /grpc-recordings/stats.
+func (m *GRPCRecordingManager) handleGetGRPCRecordingStats(w http.ResponseWriter, r *http.Request) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.store == nil {
+		writeJSON(w, http.StatusOK, GRPCRecordingStatsResponse{
+			GRPCRecordingStats: &recording.GRPCRecordingStats{},
+		})
+		return
+	}
+
+	stats := m.store.Stats()
+	writeJSON(w, http.StatusOK, GRPCRecordingStatsResponse{
+		GRPCRecordingStats: stats,
+	})
+}
+
+// handleConvertGRPCRecording handles POST /grpc-recordings/{id}/convert.
+func (m *GRPCRecordingManager) handleConvertGRPCRecording(w http.ResponseWriter, r *http.Request) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "missing_id", "Recording ID is required")
+		return
+	}
+
+	if m.store == nil {
+		writeError(w, http.StatusNotFound, "not_found", "Recording not found")
+		return
+	}
+
+	rec := m.store.Get(id)
+	if rec == nil {
+		writeError(w, http.StatusNotFound, "not_found", "Recording not found")
+		return
+	}
+
+	// Parse options
+	var req GRPCConvertRequest
+	if err := decodeOptionalJSONBody(r, &req); err != nil {
+		writeJSONDecodeError(w, err, m.log)
+		return
+	}
+
+	// Convert single recording
+	result := recording.ConvertGRPCRecordings([]*recording.GRPCRecording{rec}, req.options())
+
+	writeJSON(w, http.StatusOK, newGRPCConvertResponse(result))
+}
+
+// handleConvertGRPCRecordings handles POST /grpc-recordings/convert.
+func (m *GRPCRecordingManager) handleConvertGRPCRecordings(w http.ResponseWriter, r *http.Request) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.store == nil {
+		writeError(w, http.StatusBadRequest, "no_store", "No recording store available")
+		return
+	}
+
+	// Parse request
+	var req GRPCConvertRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONDecodeError(w, err, m.log)
+		return
+	}
+
+	// Get recordings to convert
+	var recordings []*recording.GRPCRecording
+	if len(req.RecordingIDs) > 0 {
+		for _, id := range req.RecordingIDs {
+			if rec := m.store.Get(id); rec != nil {
+				recordings = append(recordings, rec)
+			}
+		}
+	} else {
+		recordings, _ = m.store.List(recording.GRPCRecordingFilter{
+			Service: req.Service,
+			Method:  req.Method,
+		})
+	}
+
+	if len(recordings) == 0 {
+		writeError(w, http.StatusBadRequest, "no_recordings", "No recordings to convert")
+		return
+	}
+
+	result := recording.ConvertGRPCRecordings(recordings, req.options())
+
+	writeJSON(w, http.StatusOK, newGRPCConvertResponse(result))
+}
+
+// handleExportGRPCRecordings handles POST /grpc-recordings/export.
+func (m *GRPCRecordingManager) handleExportGRPCRecordings(w http.ResponseWriter, r *http.Request) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.store == nil {
+		writeError(w, http.StatusBadRequest, "no_store", "No recording store available")
+		return
+	}
+
+	data, err := m.store.Export()
+	if err != nil {
+		m.log.Error("failed to export gRPC recordings", "error", err)
+		writeError(w, http.StatusInternalServerError, "export_error", ErrMsgInternalError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", "attachment; filename=grpc-recordings.json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,162 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/getmockd/mockd/pkg/grpc"
+	"github.com/getmockd/mockd/pkg/recording"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"github.com/jhump/protoreflect/dynamic"
+	"github.com/jhump/protoreflect/dynamic/grpcdynamic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+func serveGRPCRecordingRequest(m *GRPCRecordingManager, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != nil {
+		data, _ := json.Marshal(body)
+		reader = bytes.NewReader(data)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	rec := httptest.NewRecorder()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /grpc-proxies", m.handleListGRPCProxies)
+	mux.HandleFunc("POST /grpc-proxies", m.handleStartGRPCProxy)
+	mux.HandleFunc("GET /grpc-proxies/{id}", m.handleGetGRPCProxy)
+	mux.HandleFunc("DELETE /grpc-proxies/{id}", m.handleStopGRPCProxy)
+	mux.HandleFunc("GET /grpc-recordings", m.handleListGRPCRecordings)
+	mux.HandleFunc("GET /grpc-recordings/stats", m.handleGetGRPCRecordingStats)
+	mux.HandleFunc("POST /grpc-recordings/convert", m.handleConvertGRPCRecordings)
+	mux.HandleFunc("GET /grpc-recordings/{id}", m.handleGetGRPCRecording)
+	mux.HandleFunc("POST /grpc-recordings/{id}/convert", m.handleConvertGRPCRecording)
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGRPCRecordingManager_RecordAndConvert(t *testing.T) {
+	protoPath := filepath.Join("..", "..", "tests", "fixtures", "grpc", "test.proto")
+	schema, err := grpc.ParseProtoFile(protoPath, nil)
+	require.NoError(t, err)
+
+	upstream, err := grpc.NewServer(&grpc.GRPCConfig{
+		Reflection: true,
+		Services: map[string]grpc.ServiceConfig{
+			"test.UserService": {
+				Methods: map[string]grpc.MethodConfig{
+					"GetUser": {Response: map[string]interface{}{"id": "user-1", "name": "User One"}},
+				},
+			},
+		},
+	}, schema)
+	require.NoError(t, err)
+	require.NoError(t, upstream.Start(context.Background()))
+	defer upstream.Stop(context.Background(), 5*time.Second)
+
+	mgr := NewGRPCRecordingManager()
+	defer mgr.StopAll()
+
+	// Start a recording proxy that masks the authorization metadata.
+	rec := serveGRPCRecordingRequest(mgr, http.MethodPost, "/grpc-proxies", map[string]interface{}{
+		"id":       "users",
+		"upstream": upstream.Address(),
+		"redaction": map[string]interface{}{
+			"rules": []map[string]string{{"header": "authorization", "action": "mask"}},
+		},
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var status grpc.RecordingProxyStatus
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.Equal(t, "users", status.ID)
+
+	rec = serveGRPCRecordingRequest(mgr, http.MethodPost, "/grpc-proxies", map[string]interface{}{
+		"id": "users", "upstream": upstream.Address(),
+	})
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	// Call through the proxy.
+	files, err := (&protoparse.Parser{}).ParseFiles(protoPath)
+	require.NoError(t, err)
+	methodDesc := files[0].FindService("test.UserService").FindMethodByName("GetUser")
+
+	conn, err := grpclib.NewClient(status.Address, grpclib.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer secret")
+
+	reqMsg := dynamic.NewMessage(methodDesc.GetInputType())
+	reqMsg.SetFieldByName("id", "user-1")
+	_, err = grpcdynamic.NewStub(conn).InvokeRpc(ctx, methodDesc, reqMsg)
+	require.NoError(t, err)
+
+	// The call is recorded with redacted metadata.
+	rec = serveGRPCRecordingRequest(mgr, http.MethodGet, "/grpc-recordings?service=test.UserService", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list GRPCRecordingListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Equal(t, 1, list.Total)
+	recorded := list.Recordings[0]
+	assert.Equal(t, "GetUser", recorded.Method)
+	assert.Equal(t, []string{recording.DefaultRedactMask}, recorded.Metadata["authorization"])
+
+	rec = serveGRPCRecordingRequest(mgr, http.MethodGet, "/grpc-proxies/users", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.Equal(t, int64(1), status.Recorded)
+
+	// Convert the recordings to a gRPC mock.
+	rec = serveGRPCRecordingRequest(mgr, http.MethodPost, "/grpc-recordings/convert", GRPCConvertRequest{Port: 50099})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var converted GRPCConvertResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &converted))
+	require.NotNil(t, converted.Spec)
+	assert.Equal(t, 50099, converted.Spec.Port)
+	assert.Contains(t, converted.Spec.ProtoContent, "service UserService")
+	method := converted.Spec.Services["test.UserService"].Methods["GetUser"]
+	require.NotNil(t, method.Match)
+	assert.Equal(t, "user-1", method.Match.Request["id"])
+	assert.Equal(t, "User One", method.Response.(map[string]interface{})["name"])
+
+	rec = serveGRPCRecordingRequest(mgr, http.MethodPost, "/grpc-recordings/"+recorded.ID+"/convert", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Stop the proxy.
+	rec = serveGRPCRecordingRequest(mgr, http.MethodDelete, "/grpc-proxies/users", nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = serveGRPCRecordingRequest(mgr, http.MethodGet, "/grpc-proxies/users", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestGRPCRecordingManager_StartValidation(t *testing.T) {
+	mgr := NewGRPCRecordingManager()
+	defer mgr.StopAll()
+
+	rec := serveGRPCRecordingRequest(mgr, http.MethodPost, "/grpc-proxies", map[string]interface{}{})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "validation_error")
+
+	rec = serveGRPCRecordingRequest(mgr, http.MethodPost, "/grpc-proxies", map[string]interface{}{
+		"upstream":  "127.0.0.1:1",
+		"redaction": map[string]interface{}{"rules": []map[string]string{{"header": "x", "action": "shred"}}},
+	})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid_redaction")
+
+	rec = serveGRPCRecordingRequest(mgr, http.MethodPost, "/grpc-recordings/convert", GRPCConvertRequest{})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "no_recordings")
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/getmockd/mockd/pkg/admin/engineclient"
 	types "github.com/getmockd/mockd/pkg/api/types"
+	"github.com/getmockd/mockd/pkg/chaos"
 	"github.com/getmockd/mockd/pkg/config"
 	"github.com/getmockd/mockd/pkg/httputil"
 	"github.com/getmockd/mockd/pkg/mock"
@@ -187,6 +188,11 @@ func (a *API) handleExportConfig(w http.ResponseWriter, r *http.Request) {
 		if err == nil && len(customOps) > 0 {
 			collection.CustomOperations = customOps
 		}
+		// Include user-defined chaos profiles if available.
+		profiles, err := a.dataStore.ChaosProfiles().List(ctx)
+		if err == nil && len(profiles) > 0 {
+			collection.ChaosProfiles = profiles
+		}
 	}
 
 	// Support YAML export via ?format=yaml query parameter.
@@ -340,6 +346,38 @@ func (a *API) persistCustomOperations(ctx context.Context, cfg *config.MockColle
 	}
 }
 
+// persistChaosProfiles writes user-defined chaos profiles from the imported
+// config into the file store. Profiles are global, so an import updates
+// profiles of the same name instead of clearing any. Profiles named like a
+// built-in one or with an invalid config are skipped.
+func (a *API) persistChaosProfiles(ctx context.Context, cfg *config.MockCollection) {
+	if len(cfg.ChaosProfiles) == 0 || a.dataStore == nil {
+		return
+	}
+	profileStore := a.dataStore.ChaosProfiles()
+	for _, p := range cfg.ChaosProfiles {
+		if p == nil {
+			continue
+		}
+		if _, ok := chaos.GetProfile(p.Name); ok || !chaosProfileNameRe.MatchString(p.Name) {
+			a.logger().Warn("skipping imported chaos profile with a reserved or invalid name", "name", p.Name)
+			continue
+		}
+		p.Config.Enabled = true
+		if err := p.Config.Validate(); err != nil {
+			a.logger().Warn("skipping invalid imported chaos profile", "name", p.Name, "error", err)
+			continue
+		}
+		err := profileStore.Create(ctx, p)
+		if errors.Is(err, store.ErrAlreadyExists) {
+			err = profileStore.Update(ctx, p)
+		}
+		if err != nil {
+			a.logger().Warn("failed to write chaos profile to file store", "name", p.Name, "error", err)
+		}
+	}
+}
+
 // preValidateImportMocks validates each mock that has a type and protocol config,
 // returning an error that identifies the failing mock by index and ID.
 func preValidateImportMocks(mocks []*mock.Mock) error {
@@ -467,6 +505,8 @@ func (a *API) handleImportConfig(w http.ResponseWriter, r *http.Request, engine
 	a.persistStatefulResources(ctx, req.Config, req.Replace, workspaceID)
 	// Dual-write custom operations to the file store so they survive restarts.
 	a.persistCustomOperations(ctx, req.Config, req.Replace, workspaceID)
+	// Chaos profiles are admin-side only and never reach the engine.
+	a.persistChaosProfiles(ctx, req.Config)
 
 	// Pre-validate mocks so we can surface which mock (by index) is invalid.
 	if err := preValidateImportMocks(req.Config.Mocks); err != nil {
@@ -503,6 +543,9 @@ func (a *API) handleImportConfig(w http.ResponseWriter, r *http.Request, engine
 	if len(req.Config.StatefulResources) > 0 {
 		response["statefulResources"] = len(req.Config.StatefulResources)
 	}
+	if len(req.Config.ChaosProfiles) > 0 {
+		response["chaosProfiles"] = len(req.Config.ChaosProfiles)
+	}
 	if len(importResult.Errors) > 0 {
 		response["warnings"] = importResult.Errors
 		response["message"] = fmt.Sprintf("Imported %d of %d mocks (%d failed)", imported, importResult.Total, len(importResult.Errors))
@@ -569,6 +612,7 @@ func (a *API) ImportConfigDirect(ctx context.Context, collection *config.MockCol
 	a.persistStatefulResources(ctx, collection, replace, store.DefaultWorkspaceID)
 	// Persist custom operations to the default workspace bucket.
 	a.persistCustomOperations(ctx, collection, replace, store.DefaultWorkspaceID)
+	a.persistChaosProfiles(ctx, collection)
 
 	// Pre-validate before sending to engine
 	if err := preValidateImportMocks(collection.Mocks); err != nil {
@@ -651,6 +695,60 @@ func (a *API) mockCreator() MockCreatorFunc {
 	}
 }
 
+// TableRegistrarFunc registers a stateful table the way POST /state/resources
+// does: with the engine first, then in the admin store so it survives
+// restarts. Session conversion uses it to back inferred CRUD mocks.
+type TableRegistrarFunc func(ctx context.Context, table *config.TableConfig) error
+
+// tableRegistrar returns a TableRegistrarFunc that registers tables in the
+// default workspace of the local engine and persists them.
+func (a *API) tableRegistrar() TableRegistrarFunc {
+	return func(ctx context.Context, table *config.TableConfig) error {
+		cfg := table.StatefulResource()
+		if cfg.IDField == "" {
+			cfg.IDField = "id"
+		}
+
+		if engine := a.localEngine.Load(); engine != nil {
+			if err := engine.RegisterStatefulResource(ctx, store.DefaultWorkspaceID, cfg); err != nil {
+				return err
+			}
+		}
+
+		if a.dataStore != nil {
+			cfg.Workspace = store.DefaultWorkspaceID
+			if err := a.dataStore.StatefulResources().Create(ctx, cfg); err != nil && !errors.Is(err, store.ErrAlreadyExists) {
+				a.logger().Warn("failed to persist stateful resource", "name", cfg.Name, "error", err)
+			}
+		}
+		return nil
+	}
+}
+
+// TableUnregistrarFunc removes a stateful table registered by a
+// TableRegistrarFunc, from the engine and the admin store. Session conversion
+// uses it to roll back tables when a later one cannot be registered.
+type TableUnregistrarFunc func(ctx context.Context, name string) error
+
+// tableUnregistrar returns a TableUnregistrarFunc for tables registered by
+// tableRegistrar.
+func (a *API) tableUnregistrar() TableUnregistrarFunc {
+	return func(ctx context.Context, name string) error {
+		if engine := a.localEngine.Load(); engine != nil {
+			if err := engine.DeleteStatefulResource(ctx, store.DefaultWorkspaceID, name); err != nil && !errors.Is(err, engineclient.ErrNotFound) {
+				return err
+			}
+		}
+
+		if a.dataStore != nil {
+			if err := a.dataStore.StatefulResources().Delete(ctx, store.DefaultWorkspaceID, name); err != nil && !errors.Is(err, store.ErrNotFound) {
+				a.logger().Warn("failed to delete stateful resource from store", "name", name, "error", err)
+			}
+		}
+		return nil
+	}
+}
+
 // handleListRequests handles GET /requests.
 // Supports filtering by protocol, method, path, and protocol-specific fields.
 //
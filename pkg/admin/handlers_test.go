@@ -26,6 +26,7 @@ type mockEngineServer struct {
 	*httptest.Server
 	mu           sync.RWMutex
 	mocks        map[string]*config.MockConfiguration
+	resources    map[string]*config.StatefulResourceConfig
 	requestCount int64
 	uptime       int64
 }
@@ -55,6 +56,7 @@ func (mes *mockEngineServer) setMock(id string, m *config.MockConfiguration) {
 func newMockEngineServer() *mockEngineServer {
 	mes := &mockEngineServer{
 		mocks:        make(map[string]*config.MockConfiguration),
+		resources:    make(map[string]*config.StatefulResourceConfig),
 		requestCount: 0,
 		uptime:       100,
 	}
@@ -265,6 +267,39 @@ func newMockEngineServer() *mockEngineServer {
 		json.NewEncoder(w).Encode(map[string]int{"cleared": 0})
 	})
 
+	// Register stateful resource
+	mux.HandleFunc("POST /state/resources", func(w http.ResponseWriter, r *http.Request) {
+		var cfg config.StatefulResourceConfig
+		if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "invalid_json", Message: err.Error()})
+			return
+		}
+		mes.mu.Lock()
+		defer mes.mu.Unlock()
+		if _, exists := mes.resources[cfg.Name]; exists {
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "conflict", Message: "resource already exists"})
+			return
+		}
+		mes.resources[cfg.Name] = &cfg
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	// Unregister stateful resource
+	mux.HandleFunc("POST /state/resources/{name}/unregister", func(w http.ResponseWriter, r *http.Request) {
+		mes.mu.Lock()
+		defer mes.mu.Unlock()
+		name := r.PathValue("name")
+		if _, exists := mes.resources[name]; !exists {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "not_found", Message: "resource not found"})
+			return
+		}
+		delete(mes.resources, name)
+		json.NewEncoder(w).Encode(map[string]interface{}{"unregistered": true, "name": name})
+	})
+
 	mes.Server = httptest.NewServer(mux)
 	return mes
 }
@@ -228,6 +228,66 @@ func TestConvertRecordings_DualWrite(t *testing.T) {
 	assert.True(t, server.hasMock(mockID), "converted mock must be in engine")
 }
 
+// TestConvertRecordings_GraphQL: POST /recordings/convert with graphql=true
+// turns GraphQL calls into a GraphQL mock and other calls into HTTP mocks.
+func TestConvertRecordings_GraphQL(t *testing.T) {
+	server := newMockEngineServer()
+	defer server.Close()
+
+	api := NewAPI(0,
+		WithDataDir(t.TempDir()),
+		WithLocalEngineClient(server.client()),
+	)
+
+	recStore := newFakeRecordingStore()
+	addRecordingToStore(recStore, "rest-1", "GET", "/api/users", 200, `[{"id":1}]`)
+	_ = recStore.AddRecording(&recording.Recording{
+		ID:        "gql-1",
+		Timestamp: time.Now(),
+		Request: recording.RecordedRequest{
+			Method:  "POST",
+			Path:    "/graphql",
+			URL:     "http://localhost/graphql",
+			Headers: http.Header{"Content-Type": {"application/json"}},
+			Body:    []byte(`{"query":"{ user(id: \"1\") { id } }"}`),
+		},
+		Response: recording.RecordedResponse{
+			StatusCode: 200,
+			Body:       []byte(`{"data":{"user":{"id":"1"}}}`),
+		},
+	})
+	api.proxyManager.mu.Lock()
+	api.proxyManager.store = recStore
+	api.proxyManager.mu.Unlock()
+
+	body := `{"recordingIds":["rest-1","gql-1"],"graphql":true}`
+	req := httptest.NewRequest("POST", "/recordings/convert", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	api.handleConvertRecordings(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code, "body: %s", rec.Body.String())
+
+	var result ConvertResult
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	require.Equal(t, 2, result.Count)
+
+	ctx := context.Background()
+	types := make(map[mock.Type]*config.MockConfiguration)
+	for _, id := range result.MockIDs {
+		m, err := api.dataStore.Mocks().Get(ctx, id)
+		require.NoError(t, err)
+		types[m.Type] = m
+	}
+	require.Contains(t, types, mock.TypeHTTP)
+	require.Contains(t, types, mock.TypeGraphQL)
+	gql := types[mock.TypeGraphQL].GraphQL
+	assert.Equal(t, "/graphql", gql.Path)
+	assert.Contains(t, gql.Schema, "type Query")
+	assert.Equal(t, "1", gql.Resolvers["Query.user"].Match.Args["id"])
+}
+
 // TestConvertSingleRecording_DualWrite: POST /recordings/{id}/to-mock with
 // addToServer=true writes to both store and engine.
 func TestConvertSingleRecording_DualWrite(t *testing.T) {
@@ -358,3 +418,149 @@ func TestConvertSession_DualWrite(t *testing.T) {
 		assert.True(t, server.hasMock(mockID), "session mock %s must be in engine", mockID)
 	}
 }
+
+// TestConvertSession_StatefulRegistersTables: with stateful and addToServer,
+// inferred tables are registered and the added mocks are bound to them
+// instead of replaying the recorded responses.
+func TestConvertSession_StatefulRegistersTables(t *testing.T) {
+	server := newMockEngineServer()
+	defer server.Close()
+
+	api := NewAPI(0,
+		WithDataDir(t.TempDir()),
+		WithLocalEngineClient(server.client()),
+	)
+
+	recStore := newFakeRecordingStore()
+	for i, r := range []struct{ method, path, body string }{
+		{"GET", "/api/todos/1", `{"id":"1","title":"a"}`},
+		{"PUT", "/api/todos/1", `{"id":"1","title":"b"}`},
+	} {
+		rec := recording.NewRecording("")
+		rec.Timestamp = time.Now().Add(time.Duration(i) * time.Second)
+		rec.Request = recording.RecordedRequest{Method: r.method, Path: r.path, URL: "http://localhost" + r.path}
+		rec.Response = recording.RecordedResponse{
+			StatusCode: 200,
+			Headers:    http.Header{"Content-Type": {"application/json"}},
+			Body:       []byte(r.body),
+		}
+		require.NoError(t, recStore.AddRecording(rec))
+	}
+	api.proxyManager.mu.Lock()
+	api.proxyManager.store = recStore
+	api.proxyManager.mu.Unlock()
+	sessionID := recStore.ListSessions()[0].ID
+
+	convert := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/recordings/sessions/"+sessionID+"/to-mocks",
+			strings.NewReader(`{"addToServer":true,"stateful":true}`))
+		req.SetPathValue("id", sessionID)
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		api.handleConvertSession(rec, req)
+		return rec
+	}
+
+	rec := convert()
+	require.Equal(t, http.StatusOK, rec.Code, "body: %s", rec.Body.String())
+
+	var result SessionConvertResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	require.Len(t, result.Tables, 1)
+	assert.Equal(t, 2, result.Added)
+
+	server.mu.RLock()
+	_, registered := server.resources["todos"]
+	server.mu.RUnlock()
+	assert.True(t, registered, "inferred table must be registered with the engine")
+
+	persisted, err := api.dataStore.StatefulResources().List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, persisted, 1, "inferred table must be persisted")
+	assert.Equal(t, "todos", persisted[0].Name)
+
+	for _, id := range result.MockIDs {
+		m, err := api.dataStore.Mocks().Get(context.Background(), id)
+		require.NoError(t, err)
+		require.NotNil(t, m.HTTP.StatefulBinding, "mock %s must be bound to its table", id)
+		assert.Equal(t, "todos", m.HTTP.StatefulBinding.Table)
+		assert.Nil(t, m.HTTP.Response, "bound mock must not replay the recorded response")
+	}
+
+	// Converting again conflicts with the table that now exists
+	assert.Equal(t, http.StatusConflict, convert().Code)
+}
+
+// TestConvertSession_StatefulConflictRollsBackTables: when a later inferred
+// table conflicts, the tables registered before it are removed again so a
+// retry does not conflict with them.
+func TestConvertSession_StatefulConflictRollsBackTables(t *testing.T) {
+	server := newMockEngineServer()
+	defer server.Close()
+
+	api := NewAPI(0,
+		WithDataDir(t.TempDir()),
+		WithLocalEngineClient(server.client()),
+	)
+
+	recStore := newFakeRecordingStore()
+	for i, path := range []string{"/api/todos/1", "/api/users/1"} {
+		rec := recording.NewRecording("")
+		rec.Timestamp = time.Now().Add(time.Duration(i) * time.Second)
+		rec.Request = recording.RecordedRequest{Method: "GET", Path: path, URL: "http://localhost" + path}
+		rec.Response = recording.RecordedResponse{
+			StatusCode: 200,
+			Headers:    http.Header{"Content-Type": {"application/json"}},
+			Body:       []byte(`{"id":"1","name":"a"}`),
+		}
+		require.NoError(t, recStore.AddRecording(rec))
+	}
+	api.proxyManager.mu.Lock()
+	api.proxyManager.store = recStore
+	api.proxyManager.mu.Unlock()
+	sessionID := recStore.ListSessions()[0].ID
+
+	convert := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/recordings/sessions/"+sessionID+"/to-mocks",
+			strings.NewReader(`{"addToServer":true,"stateful":true}`))
+		req.SetPathValue("id", sessionID)
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		api.handleConvertSession(rec, req)
+		return rec
+	}
+	registered := func(name string) bool {
+		server.mu.RLock()
+		defer server.mu.RUnlock()
+		_, ok := server.resources[name]
+		return ok
+	}
+
+	// "users" is registered after "todos", so it conflicts with todos already registered.
+	server.mu.Lock()
+	server.resources["users"] = &config.StatefulResourceConfig{Name: "users"}
+	server.mu.Unlock()
+
+	rec := convert()
+	require.Equal(t, http.StatusConflict, rec.Code, "body: %s", rec.Body.String())
+	assert.Contains(t, rec.Body.String(), "users")
+	assert.False(t, registered("todos"), "tables registered before the conflict must be rolled back")
+
+	persisted, err := api.dataStore.StatefulResources().List(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, persisted, "rolled back tables must not stay persisted")
+
+	mocks, err := api.dataStore.Mocks().List(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, mocks, "no mocks are added when tables cannot be registered")
+
+	// Once the conflict is gone, a retry succeeds.
+	server.mu.Lock()
+	delete(server.resources, "users")
+	server.mu.Unlock()
+
+	rec = convert()
+	require.Equal(t, http.StatusOK, rec.Code, "body: %s", rec.Body.String())
+	assert.True(t, registered("todos"))
+	assert.True(t, registered("users"))
+}
@@ -95,7 +95,8 @@ func (m *MQTTRecordingManager) ListBrokers() []string {
 
 // mqttStoreAdapter adapts MQTTStore to the mqtt.MQTTRecordingStore interface.
 type mqttStoreAdapter struct {
-	store *recording.MQTTStore
+	store    *recording.MQTTStore
+	redactor *recording.Redactor
 }
 
 func (a *mqttStoreAdapter) Add(data mqtt.MQTTRecordingData) error {
@@ -108,6 +109,7 @@ func (a *mqttStoreAdapter) Add(data mqtt.MQTTRecordingData) error {
 		data.ClientID,
 		recording.MQTTDirection(data.Direction),
 	)
+	a.redactor.RedactMQTT(rec)
 	return a.store.Add(rec)
 }
 
@@ -147,6 +149,13 @@ type MQTTGlobalStatusResponse struct {
 	Brokers      []MQTTBrokerStatusResponse `json:"brokers"`
 }
 
+// RecordingStartRequest is the optional body of a protocol recording start
+// request.
+type RecordingStartRequest struct {
+	// Redaction rules are applied to recordings before they are stored.
+	Redaction *recording.RedactionConfig `json:"redaction,omitempty"`
+}
+
 // MQTTRecordingStartResponse represents the response after starting recording.
 type MQTTRecordingStartResponse struct {
 	Message string `json:"message"`
@@ -324,6 +333,18 @@ func (m *MQTTRecordingManager) handleStartMQTTRecording(w http.ResponseWriter, r
 		return
 	}
 
+	var req RecordingStartRequest
+	if err := decodeOptionalJSONBody(r, &req); err != nil {
+		writeJSONDecodeError(w, err, m.log)
+		return
+	}
+	redactor, err := recording.NewRedactor(req.Redaction)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_redaction", err.Error())
+		return
+	}
+
+	broker.SetRecordingStore(&mqttStoreAdapter{store: m.Store(), redactor: redactor})
 	broker.EnableRecording()
 
 	writeJSON(w, http.StatusOK, MQTTRecordingStartResponse{
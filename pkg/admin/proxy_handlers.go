@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
+	"net/url"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -57,6 +58,19 @@ type ProxyStartRequest struct {
 	SessionName string              `json:"sessionName"`
 	CAPath      string              `json:"caPath"`
 	Filters     *FilterConfigUpdate `json:"filters,omitempty"`
+	// OnMiss is the replay miss policy: fail (default), forward or record.
+	OnMiss string `json:"onMiss,omitempty"`
+	// Upstream, when set, runs a reverse proxy forwarding every request to
+	// this base URL, so clients need no proxy configuration.
+	Upstream string `json:"upstream,omitempty"`
+	// ReplaySession names a session in the recordings directory, or
+	// "latest", whose recordings are loaded for replay.
+	ReplaySession string `json:"replaySession,omitempty"`
+	// Redaction rules are applied to every recording before it is stored.
+	Redaction *recording.RedactionConfig `json:"redaction,omitempty"`
+	// GraphQLIntrospection fetches each recorded GraphQL endpoint's schema
+	// through introspection so conversion can use it.
+	GraphQLIntrospection bool `json:"graphqlIntrospection,omitempty"`
 }
 
 // FilterConfigUpdate represents filter configuration for updates.
@@ -72,14 +86,20 @@ type ProxyStatusResponse struct {
 	Running        bool   `json:"running"`
 	Port           int    `json:"port,omitempty"`
 	Mode           string `json:"mode,omitempty"`
+	Upstream       string `json:"upstream,omitempty"`
 	SessionID      string `json:"sessionId,omitempty"`
 	RecordingCount int    `json:"recordingCount,omitempty"`
 	Uptime         int    `json:"uptime,omitempty"`
+	// OnMiss and Replay are set in replay mode.
+	OnMiss string             `json:"onMiss,omitempty"`
+	Replay *proxy.ReplayStats `json:"replay,omitempty"`
 }
 
-// ModeRequest represents a mode change request.
+// ModeRequest represents a mode change request. An empty OnMiss keeps the
+// current replay miss policy.
 type ModeRequest struct {
-	Mode string `json:"mode"`
+	Mode   string `json:"mode"`
+	OnMiss string `json:"onMiss,omitempty"`
 }
 
 // CAInfoResponse represents CA certificate info.
@@ -116,17 +136,50 @@ func (pm *ProxyManager) handleProxyStart(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Parse mode
-	var mode proxy.Mode
-	switch req.Mode {
-	case "record":
-		mode = proxy.ModeRecord
-	case "passthrough":
-		mode = proxy.ModePassthrough
-	default:
-		writeError(w, http.StatusBadRequest, "invalid_mode", "Mode must be 'record' or 'passthrough'")
+	mode, err := proxy.ParseMode(req.Mode)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_mode", "Mode must be 'record', 'passthrough' or 'replay'")
+		return
+	}
+	onMiss, err := proxy.ParseMissPolicy(req.OnMiss)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_on_miss", "onMiss must be 'fail', 'forward' or 'record'")
+		return
+	}
+
+	var upstream *url.URL
+	if req.Upstream != "" {
+		if upstream, err = proxy.ParseUpstream(req.Upstream); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_upstream", err.Error())
+			return
+		}
+	}
+
+	redactor, err := recording.NewRedactor(req.Redaction)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_redaction", err.Error())
 		return
 	}
 
+	// Load the recordings to replay before touching any state
+	var replayed []*recording.Recording
+	if req.ReplaySession != "" {
+		if strings.Contains(req.ReplaySession, "..") || strings.ContainsAny(req.ReplaySession, `/\`) {
+			writeError(w, http.StatusBadRequest, "invalid_session", "replaySession must be a session name, not a path")
+			return
+		}
+		dir, err := recording.ResolveSessionDir(recording.DefaultRecordingsBaseDir(), req.ReplaySession)
+		if err != nil {
+			writeError(w, http.StatusNotFound, "session_not_found", fmt.Sprintf("Recording session %q not found", req.ReplaySession))
+			return
+		}
+		if replayed, err = recording.LoadFromDir(dir); err != nil {
+			pm.log.Error("failed to load recordings for replay", "dir", dir, "error", err)
+			writeError(w, http.StatusInternalServerError, "load_error", "Failed to load recordings for replay")
+			return
+		}
+	}
+
 	// Create store and session
 	store := recording.NewStore()
 	sessionName := req.SessionName
@@ -134,6 +187,9 @@ func (pm *ProxyManager) handleProxyStart(w http.ResponseWriter, r *http.Request)
 		sessionName = "default"
 	}
 	session := store.CreateSession(sessionName, nil)
+	for _, rec := range replayed {
+		session.AddRecording(rec)
+	}
 	pm.sessionID = session.ID
 
 	// Create CA manager if path provided
@@ -183,10 +239,15 @@ func (pm *ProxyManager) handleProxyStart(w http.ResponseWriter, r *http.Request)
 	logger := slog.NewLogLogger(pm.log.Handler(), slog.LevelInfo)
 	p := proxy.New(proxy.Options{
 		Mode:      mode,
+		OnMiss:    onMiss,
+		Upstream:  upstream,
+		Redactor:  redactor,
 		Store:     store,
 		Filter:    filter,
 		CAManager: ca,
 		Logger:    logger,
+
+		GraphQLIntrospection: req.GraphQLIntrospection,
 	})
 
 	// Start HTTP server
@@ -270,15 +331,20 @@ func (pm *ProxyManager) handleProxyMode(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	switch req.Mode {
-	case "record":
-		pm.proxy.SetMode(proxy.ModeRecord)
-	case "passthrough":
-		pm.proxy.SetMode(proxy.ModePassthrough)
-	default:
-		writeError(w, http.StatusBadRequest, "invalid_mode", "Mode must be 'record' or 'passthrough'")
+	mode, err := proxy.ParseMode(req.Mode)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_mode", "Mode must be 'record', 'passthrough' or 'replay'")
 		return
 	}
+	if req.OnMiss != "" {
+		onMiss, err := proxy.ParseMissPolicy(req.OnMiss)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_on_miss", "onMiss must be 'fail', 'forward' or 'record'")
+			return
+		}
+		pm.proxy.SetOnMiss(onMiss)
+	}
+	pm.proxy.SetMode(mode)
 
 	writeJSON(w, http.StatusOK, pm.getStatus())
 }
@@ -450,8 +516,16 @@ func (pm *ProxyManager) getStatus() ProxyStatusResponse {
 	if pm.running {
 		status.Port = pm.port
 		status.Mode = string(pm.proxy.Mode())
+		if u := pm.proxy.Upstream(); u != nil {
+			status.Upstream = u.String()
+		}
 		status.SessionID = pm.sessionID
 		status.Uptime = int(time.Since(pm.startTime).Seconds())
+		if pm.proxy.Mode() == proxy.ModeReplay {
+			stats := pm.proxy.ReplayStats()
+			status.OnMiss = string(pm.proxy.OnMiss())
+			status.Replay = &stats
+		}
 
 		if pm.store != nil {
 			_, total := pm.store.ListRecordings(recording.RecordingFilter{})
@@ -2,11 +2,13 @@ package admin
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 
 	"gopkg.in/yaml.v3"
 
+	"github.com/getmockd/mockd/pkg/admin/engineclient"
 	"github.com/getmockd/mockd/pkg/config"
 	"github.com/getmockd/mockd/pkg/recording"
 )
@@ -25,6 +27,7 @@ type ConvertRequest struct {
 	SessionID      string   `json:"sessionId,omitempty"`
 	Deduplicate    bool     `json:"deduplicate"`
 	IncludeHeaders bool     `json:"includeHeaders"`
+	GraphQL        bool     `json:"graphql,omitempty"` // Convert GraphQL calls to GraphQL mocks
 }
 
 // ConvertResult represents the result of converting recordings.
@@ -194,7 +197,14 @@ func (pm *ProxyManager) handleConvertRecordings(w http.ResponseWriter, r *http.R
 		Deduplicate:    req.Deduplicate,
 		IncludeHeaders: req.IncludeHeaders,
 	}
-	mocks := recording.ToMocks(recordings, opts)
+	var mocks []*config.MockConfiguration
+	if req.GraphQL {
+		graphqlRecs, others := recording.SplitGraphQLRecordings(recordings)
+		mocks = append(recording.ToMocks(others, opts),
+			recording.ToGraphQLMocks(graphqlRecs, recording.DefaultGraphQLConvertOptions())...)
+	} else {
+		mocks = recording.ToMocks(recordings, opts)
+	}
 
 	// Add mocks via dual-write path (store + engine)
 	mockIDs := make([]string, 0, len(mocks))
@@ -363,20 +373,32 @@ type SessionConvertRequest struct {
 	Duplicates   string `json:"duplicates,omitempty"`   // "first", "last", "all"
 	AddToServer  bool   `json:"addToServer,omitempty"`  // Add mocks directly
 	SmartMatch   bool   `json:"smartMatch,omitempty"`   // Convert /users/123 to /users/{id}
+	GraphQL      bool   `json:"graphql,omitempty"`      // Convert GraphQL calls to GraphQL mocks
+	Stateful     bool   `json:"stateful,omitempty"`     // Infer stateful tables from REST resources
+	Templates    bool   `json:"templates,omitempty"`    // Template echoed request values and volatile fields
 }
 
 // SessionConvertResponse represents the result of converting session recordings.
 type SessionConvertResponse struct {
-	Mocks    []*config.MockConfiguration      `json:"mocks"`
-	MockIDs  []string                         `json:"mockIds"`
-	Warnings []recording.SensitiveDataWarning `json:"warnings,omitempty"`
-	Filtered int                              `json:"filtered"`
-	Total    int                              `json:"total"`
-	Added    int                              `json:"added"`
+	Mocks             []*config.MockConfiguration         `json:"mocks"`
+	MockIDs           []string                            `json:"mockIds"`
+	Warnings          []recording.SensitiveDataWarning    `json:"warnings,omitempty"`
+	Filtered          int                                 `json:"filtered"`
+	Total             int                                 `json:"total"`
+	Added             int                                 `json:"added"`
+	GraphQLOperations []recording.GraphQLOperationSummary `json:"graphqlOperations,omitempty"`
+	GraphQLWarnings   []string                            `json:"graphqlWarnings,omitempty"`
+	Tables            []*config.TableConfig               `json:"tables,omitempty"`
+	Extend            []*config.ExtendBinding             `json:"extend,omitempty"`
+	StatefulResources []recording.StatefulResourceSummary `json:"statefulResources,omitempty"`
+	StatefulWarnings  []string                            `json:"statefulWarnings,omitempty"`
+	Substitutions     []recording.TemplateSubstitution    `json:"substitutions,omitempty"`
 }
 
 // handleConvertSession handles POST /recordings/sessions/{id}/to-mocks.
-func (pm *ProxyManager) handleConvertSession(w http.ResponseWriter, r *http.Request, createMock MockCreatorFunc) {
+// With stateful and addToServer set, the inferred tables are registered and
+// their mocks bound to them before the mocks are added.
+func (pm *ProxyManager) handleConvertSession(w http.ResponseWriter, r *http.Request, createMock MockCreatorFunc, registerTable TableRegistrarFunc, unregisterTable TableUnregistrarFunc) {
 	pm.mu.RLock()
 	defer pm.mu.RUnlock()
 
@@ -424,6 +446,9 @@ func (pm *ProxyManager) handleConvertSession(w http.ResponseWriter, r *http.Requ
 		ConvertOptions: recording.ConvertOptions{
 			Deduplicate: req.Duplicates != "all",
 			SmartMatch:  req.SmartMatch,
+			GraphQL:     req.GraphQL,
+			Stateful:    req.Stateful,
+			Templates:   req.Templates,
 		},
 		Filter: recording.FilterOptions{
 			PathPattern: req.PathFilter,
@@ -442,11 +467,36 @@ func (pm *ProxyManager) handleConvertSession(w http.ResponseWriter, r *http.Requ
 	// Convert with options
 	result := recording.ConvertSessionWithOptions(session, opts)
 
+	ctx := r.Context()
+
+	// Tables must exist before the mocks bound to them are served
+	if req.AddToServer && len(result.Tables) > 0 {
+		if err := config.ApplyExtendBindings(result.Mocks, result.Tables, result.Extend); err != nil {
+			writeError(w, http.StatusInternalServerError, "convert_error", sanitizeError(err, pm.log, "bind stateful mocks"))
+			return
+		}
+		for i, table := range result.Tables {
+			if err := registerTable(ctx, table); err != nil {
+				// Roll back so a retry does not conflict with our own tables.
+				for _, registered := range result.Tables[:i] {
+					if uerr := unregisterTable(ctx, registered.Name); uerr != nil {
+						pm.log.Warn("failed to roll back stateful table", "name", registered.Name, "error", uerr)
+					}
+				}
+				if errors.Is(err, engineclient.ErrConflict) {
+					writeError(w, http.StatusConflict, "conflict", "stateful table already exists: "+table.Name)
+					return
+				}
+				writeError(w, http.StatusServiceUnavailable, "engine_error", sanitizeEngineError(err, pm.log, "register stateful table"))
+				return
+			}
+		}
+	}
+
 	// Add to server if requested via dual-write (store + engine)
 	addedCount := 0
 	mockIDs := make([]string, 0, len(result.Mocks))
 
-	ctx := r.Context()
 	for _, mock := range result.Mocks {
 		mockIDs = append(mockIDs, mock.ID)
 		if req.AddToServer {
@@ -463,6 +513,14 @@ func (pm *ProxyManager) handleConvertSession(w http.ResponseWriter, r *http.Requ
 		Filtered: result.Filtered,
 		Total:    result.Total,
 		Added:    addedCount,
+
+		GraphQLOperations: result.GraphQLOperations,
+		GraphQLWarnings:   result.GraphQLWarnings,
+		Tables:            result.Tables,
+		Extend:            result.Extend,
+		StatefulResources: result.StatefulResources,
+		StatefulWarnings:  result.StatefulWarnings,
+		Substitutions:     result.Substitutions,
 	})
 }
 
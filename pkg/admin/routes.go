@@ -45,6 +45,7 @@ func (a *API) registerRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("POST /mocks/{id}/verify", a.requireEngine(a.handleVerifyMock))
 	mux.HandleFunc("GET /mocks/{id}/invocations", a.requireEngine(a.handleListMockInvocations))
 	mux.HandleFunc("DELETE /mocks/{id}/invocations", a.requireEngine(a.handleResetMockVerification))
+	mux.HandleFunc("GET /mocks/{id}/drift", a.requireEngine(a.handleGetMockDrift))
 	mux.HandleFunc("DELETE /verify", a.requireEngine(a.handleResetAllVerification))
 
 	// Configuration import/export
@@ -109,6 +110,14 @@ func (a *API) registerRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /state/resources/{name}/items", a.requireEngine(a.handleListStatefulItems))
 	mux.HandleFunc("GET /state/resources/{name}/items/{id}", a.requireEngine(a.handleGetStatefulItem))
 	mux.HandleFunc("POST /state/resources/{name}/items", a.requireEngine(a.handleCreateStatefulItem))
+	mux.HandleFunc("PUT /state/resources/{name}/items/{id}", a.requireEngine(a.handleUpdateStatefulItem))
+	mux.HandleFunc("PATCH /state/resources/{name}/items/{id}", a.requireEngine(a.handlePatchStatefulItem))
+	mux.HandleFunc("DELETE /state/resources/{name}/items/{id}", a.requireEngine(a.handleDeleteStatefulItem))
+	mux.HandleFunc("POST /state/resources/{name}/import", a.requireEngine(a.handleImportStatefulItems))
+	mux.HandleFunc("GET /state/resources/{name}/export", a.requireEngine(a.handleExportStatefulItems))
+	mux.HandleFunc("GET /state/partitions", a.requireEngine(a.handleListStatePartitions))
+	mux.HandleFunc("GET /state/partitions/{key}", a.requireEngine(a.handleGetStatePartition))
+	mux.HandleFunc("DELETE /state/partitions/{key}", a.requireEngine(a.handleResetStatePartition))
 
 	// Custom operations
 	mux.HandleFunc("GET /state/operations", a.requireEngine(a.handleListCustomOperations))
@@ -182,17 +191,43 @@ func (a *API) registerRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("PUT /chaos", a.requireEngine(a.handleSetChaos))
 	mux.HandleFunc("GET /chaos/stats", a.requireEngine(a.handleGetChaosStats))
 	mux.HandleFunc("POST /chaos/stats/reset", a.requireEngine(a.handleResetChaosStats))
+	mux.HandleFunc("GET /chaos/fault-log", a.requireEngine(a.handleGetChaosFaultLog))
+	mux.HandleFunc("POST /chaos/fault-log/reset", a.requireEngine(a.handleResetChaosFaultLog))
+
+	// Shadow mode: compare mock responses with the real upstream
+	mux.HandleFunc("GET /shadow", a.requireEngine(a.handleGetShadow))
+	mux.HandleFunc("PUT /shadow", a.requireEngine(a.handleSetShadow))
+	mux.HandleFunc("GET /shadow/drift", a.requireEngine(a.handleGetDrift))
+	mux.HandleFunc("POST /shadow/drift/reset", a.requireEngine(a.handleResetDrift))
+
+	// Chaos experiments
+	mux.HandleFunc("GET /chaos/experiments", a.handleListChaosExperiments)
+	mux.HandleFunc("POST /chaos/experiments", a.requireEngine(a.handleStartChaosExperiment))
+	mux.HandleFunc("GET /chaos/experiments/{id}", a.handleGetChaosExperiment)
+	mux.HandleFunc("POST /chaos/experiments/{id}/abort", a.handleAbortChaosExperiment)
 
 	// Stateful fault introspection
 	mux.HandleFunc("GET /chaos/faults", a.requireEngine(a.handleGetStatefulFaultStats))
 	mux.HandleFunc("POST /chaos/circuit-breakers/{key}/trip", a.requireEngine(a.handleTripCircuitBreaker))
 	mux.HandleFunc("POST /chaos/circuit-breakers/{key}/reset", a.requireEngine(a.handleResetCircuitBreaker))
 
-	// Chaos profiles (built-in presets)
+	// Chaos profiles (built-in presets and user-defined profiles)
 	mux.HandleFunc("GET /chaos/profiles", a.handleListChaosProfiles)
+	mux.HandleFunc("POST /chaos/profiles", a.handleCreateChaosProfile)
 	mux.HandleFunc("GET /chaos/profiles/{name}", a.handleGetChaosProfile)
+	mux.HandleFunc("PUT /chaos/profiles/{name}", a.handleUpdateChaosProfile)
+	mux.HandleFunc("DELETE /chaos/profiles/{name}", a.handleDeleteChaosProfile)
 	mux.HandleFunc("POST /chaos/profiles/{name}/apply", a.requireEngine(a.handleApplyChaosProfile))
 
+	// TCP fault-injection proxies
+	mux.HandleFunc("GET /chaos/tcp-proxies", a.handleListTCPProxies)
+	mux.HandleFunc("POST /chaos/tcp-proxies", a.handleCreateTCPProxy)
+	mux.HandleFunc("GET /chaos/tcp-proxies/{name}", a.handleGetTCPProxy)
+	mux.HandleFunc("DELETE /chaos/tcp-proxies/{name}", a.handleDeleteTCPProxy)
+	mux.HandleFunc("PUT /chaos/tcp-proxies/{name}/toxics", a.handleSetTCPToxics)
+	mux.HandleFunc("POST /chaos/tcp-proxies/{name}/toxics", a.handleAddTCPToxic)
+	mux.HandleFunc("DELETE /chaos/tcp-proxies/{name}/toxics/{toxic}", a.handleRemoveTCPToxic)
+
 	// gRPC server management (convenience — proxies to /mocks?type=grpc)
 	mux.HandleFunc("GET /grpc", func(w http.ResponseWriter, r *http.Request) {
 		q := r.URL.Query()
@@ -218,6 +253,22 @@ func (a *API) registerRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("DELETE /mqtt-recordings/{id}", a.mqttRecordingManager.handleDeleteMQTTRecording)
 	mux.HandleFunc("POST /mqtt-recordings/{id}/convert", a.mqttRecordingManager.handleConvertMQTTRecording)
 
+	// gRPC recording proxies
+	mux.HandleFunc("GET /grpc-proxies", a.grpcRecordingManager.handleListGRPCProxies)
+	mux.HandleFunc("POST /grpc-proxies", a.grpcRecordingManager.handleStartGRPCProxy)
+	mux.HandleFunc("GET /grpc-proxies/{id}", a.grpcRecordingManager.handleGetGRPCProxy)
+	mux.HandleFunc("DELETE /grpc-proxies/{id}", a.grpcRecordingManager.handleStopGRPCProxy)
+
+	// gRPC recording management
+	mux.HandleFunc("GET /grpc-recordings", a.grpcRecordingManager.handleListGRPCRecordings)
+	mux.HandleFunc("GET /grpc-recordings/stats", a.grpcRecordingManager.handleGetGRPCRecordingStats)
+	mux.HandleFunc("DELETE /grpc-recordings", a.grpcRecordingManager.handleClearGRPCRecordings)
+	mux.HandleFunc("POST /grpc-recordings/convert", a.grpcRecordingManager.handleConvertGRPCRecordings)
+	mux.HandleFunc("POST /grpc-recordings/export", a.grpcRecordingManager.handleExportGRPCRecordings)
+	mux.HandleFunc("GET /grpc-recordings/{id}", a.grpcRecordingManager.handleGetGRPCRecording)
+	mux.HandleFunc("DELETE /grpc-recordings/{id}", a.grpcRecordingManager.handleDeleteGRPCRecording)
+	mux.HandleFunc("POST /grpc-recordings/{id}/convert", a.grpcRecordingManager.handleConvertGRPCRecording)
+
 	// SOAP handler management
 	mux.HandleFunc("GET /soap", a.soapRecordingManager.handleListSOAPHandlers)
 	mux.HandleFunc("GET /soap/{id}/status", a.soapRecordingManager.handleGetSOAPHandlerStatus)
@@ -302,7 +353,7 @@ func (a *API) handleConvertSingleRecording(w http.ResponseWriter, r *http.Reques
 
 // handleConvertSession wraps the session convert handler.
 func (a *API) handleConvertSession(w http.ResponseWriter, r *http.Request) {
-	a.proxyManager.handleConvertSession(w, r, a.mockCreator())
+	a.proxyManager.handleConvertSession(w, r, a.mockCreator(), a.tableRegistrar(), a.tableUnregistrar())
 }
 
 // handleConvertStreamRecording wraps the stream recording convert handler.
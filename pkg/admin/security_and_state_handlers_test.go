@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -187,6 +188,104 @@ func TestMapStatefulResourceError(t *testing.T) {
 	}
 }
 
+func TestMapStatefulItemError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantMsg    string
+	}{
+		{
+			name:       "not found",
+			err:        engineclient.ErrNotFound,
+			wantStatus: http.StatusNotFound,
+			wantCode:   "not_found",
+			wantMsg:    "Resource or item not found",
+		},
+		{
+			name:       "invalid surfaces engine message",
+			err:        fmt.Errorf("%w: unsupported format \"xml\"", engineclient.ErrInvalid),
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_request",
+			wantMsg:    `unsupported format "xml"`,
+		},
+		{
+			name:       "engine error",
+			err:        errors.New("dial tcp 127.0.0.1:9999: connect: connection refused"),
+			wantStatus: http.StatusServiceUnavailable,
+			wantCode:   "engine_error",
+			wantMsg:    ErrMsgEngineUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, code, msg := mapStatefulItemError(tt.err, nil, "import stateful items")
+			if status != tt.wantStatus {
+				t.Fatalf("status=%d want=%d", status, tt.wantStatus)
+			}
+			if code != tt.wantCode {
+				t.Fatalf("code=%q want=%q", code, tt.wantCode)
+			}
+			if msg != tt.wantMsg {
+				t.Fatalf("msg=%q want=%q", msg, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestHandleImportStatefulItems_ReportsPartialResult(t *testing.T) {
+	engineServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"invalid_payload","message":"invalid JSON payload at row 3: unexpected EOF","details":{"resource":"users","total":2,"created":2}}`))
+	}))
+	defer engineServer.Close()
+
+	engine := engineclient.New(engineServer.URL)
+	api := NewAPI(0, WithDataDir(t.TempDir()), WithLocalEngineClient(engine))
+	defer api.Stop()
+
+	req := httptest.NewRequest(http.MethodPost, "/state/resources/users/import", strings.NewReader(`[{},{},{`))
+	req.SetPathValue("name", "users")
+	rec := httptest.NewRecorder()
+	api.handleImportStatefulItems(rec, req, engine)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status=%d want=%d", rec.Code, http.StatusBadRequest)
+	}
+	var resp struct {
+		Message string                              `json:"message"`
+		Details engineclient.StatefulImportResponse `json:"details"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Details.Created != 2 || !strings.Contains(resp.Message, "row 3") {
+		t.Fatalf("response = %s", rec.Body.String())
+	}
+}
+
+func TestIsStateImportRequest(t *testing.T) {
+	tests := []struct {
+		method, path string
+		want         bool
+	}{
+		{http.MethodPost, "/state/resources/users/import", true},
+		{http.MethodGet, "/state/resources/users/import", false},
+		{http.MethodPost, "/state/resources/users/items", false},
+		{http.MethodPost, "/state/resources//import", false},
+		{http.MethodPost, "/state/resources/a/b/import", false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if got := isStateImportRequest(req); got != tt.want {
+			t.Errorf("%s %s: got %v want %v", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
 func TestMapSSEEngineError(t *testing.T) {
 	status, code, msg := mapSSEEngineError(errors.New("dial tcp 127.0.0.1:9999: connect: connection refused"), nil, "get SSE stats")
 	if status != http.StatusServiceUnavailable {
@@ -0,0 +1,101 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/getmockd/mockd/pkg/admin/engineclient"
+	"github.com/getmockd/mockd/pkg/store"
+)
+
+// handleGetShadow returns the shadow mode configuration.
+func (a *API) handleGetShadow(w http.ResponseWriter, r *http.Request, engine *engineclient.Client) {
+	cfg, err := engine.GetShadow(r.Context())
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, "engine_error", sanitizeEngineError(err, a.logger(), "get shadow config"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, cfg)
+}
+
+// handleSetShadow enables, reconfigures or disables shadow mode.
+func (a *API) handleSetShadow(w http.ResponseWriter, r *http.Request, engine *engineclient.Client) {
+	var cfg engineclient.ShadowConfig
+	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+		writeJSONDecodeError(w, err, a.logger())
+		return
+	}
+
+	if err := engine.SetShadow(r.Context(), &cfg); err != nil {
+		// Surface validation errors from the engine instead of generic "unavailable"
+		if strings.Contains(err.Error(), "validation") {
+			writeError(w, http.StatusBadRequest, "validation_error", err.Error())
+			return
+		}
+		writeError(w, http.StatusServiceUnavailable, "engine_error", sanitizeEngineError(err, a.logger(), "set shadow config"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleGetDrift returns the drift report of shadow mode. ?mockId= limits
+// it to one mock.
+func (a *API) handleGetDrift(w http.ResponseWriter, r *http.Request, engine *engineclient.Client) {
+	report, err := engine.GetDriftReport(r.Context(), r.URL.Query().Get("mockId"))
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, "engine_error", sanitizeEngineError(err, a.logger(), "get drift report"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+// handleResetDrift clears the drift report.
+func (a *API) handleResetDrift(w http.ResponseWriter, r *http.Request, engine *engineclient.Client) {
+	if err := engine.ResetDrift(r.Context()); err != nil {
+		writeError(w, http.StatusServiceUnavailable, "engine_error", sanitizeEngineError(err, a.logger(), "reset drift report"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "drift report reset"})
+}
+
+// handleGetMockDrift returns the drift of one mock. A mock that was never
+// shadowed has zero counts.
+func (a *API) handleGetMockDrift(w http.ResponseWriter, r *http.Request, engine *engineclient.Client) {
+	ctx := r.Context()
+	id := r.PathValue("id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "missing_id", "Mock ID is required")
+		return
+	}
+
+	// Check if mock exists in the admin store (single source of truth).
+	if mockStore := a.getMockStore(); mockStore != nil {
+		if _, err := mockStore.Get(ctx, id); err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				writeError(w, http.StatusNotFound, "not_found", "Mock not found")
+				return
+			}
+			writeError(w, http.StatusInternalServerError, "store_error", ErrMsgInternalError)
+			return
+		}
+	}
+
+	report, err := engine.GetDriftReport(ctx, id)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, "engine_error", sanitizeEngineError(err, a.logger(), "get mock drift"))
+		return
+	}
+	for _, m := range report.Mocks {
+		if m.MockID == id {
+			writeJSON(w, http.StatusOK, m)
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, engineclient.MockDrift{MockID: id})
+}
@@ -0,0 +1,122 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/getmockd/mockd/pkg/admin/engineclient"
+	"github.com/getmockd/mockd/pkg/config"
+	"github.com/getmockd/mockd/pkg/mock"
+	"github.com/getmockd/mockd/pkg/shadow"
+)
+
+// newMockShadowEngineServer simulates the engine's shadow mode endpoints,
+// validating configs and filtering a fixed drift report by ?mockId=.
+func newMockShadowEngineServer(t *testing.T) (*httptest.Server, *engineclient.ShadowConfig) {
+	t.Helper()
+	cfg := &engineclient.ShadowConfig{}
+	report := engineclient.DriftReport{
+		Enabled:  true,
+		Upstream: "https://api.example.com",
+		Compared: 3,
+		Drifted:  1,
+		Mocks: []engineclient.MockDrift{
+			{MockID: "users", Method: "GET", Path: "/users", Compared: 2, Drifted: 1,
+				Differences: []shadow.DriftDifference{{Difference: shadow.Difference{Kind: shadow.KindMissing, Path: "$.email"}, Count: 1}}},
+			{MockID: "orders", Method: "GET", Path: "/orders", Compared: 1},
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /shadow", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(cfg)
+	})
+	mux.HandleFunc("PUT /shadow", func(w http.ResponseWriter, r *http.Request) {
+		var next engineclient.ShadowConfig
+		_ = json.NewDecoder(r.Body).Decode(&next)
+		if err := next.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(engineclient.ErrorResponse{Error: "validation_error", Message: err.Error()})
+			return
+		}
+		*cfg = next
+		_ = json.NewEncoder(w).Encode(cfg)
+	})
+	mux.HandleFunc("GET /shadow/drift", func(w http.ResponseWriter, r *http.Request) {
+		filtered := report
+		if id := r.URL.Query().Get("mockId"); id != "" {
+			filtered.Mocks = nil
+			for _, m := range report.Mocks {
+				if m.MockID == id {
+					filtered.Mocks = append(filtered.Mocks, m)
+				}
+			}
+		}
+		_ = json.NewEncoder(w).Encode(filtered)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, cfg
+}
+
+func TestHandleSetShadow(t *testing.T) {
+	server, cfg := newMockShadowEngineServer(t)
+	engine := engineclient.New(server.URL)
+	api := NewAPI(0, WithDataDir(t.TempDir()), WithLocalEngineClient(engine))
+
+	rec := httptest.NewRecorder()
+	api.handleSetShadow(rec, httptest.NewRequest(http.MethodPut, "/shadow",
+		strings.NewReader(`{"enabled":true,"upstream":"https://api.example.com","ignore":{"fields":["request_id"]}}`)), engine)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "https://api.example.com", cfg.Upstream)
+	assert.Equal(t, []string{"request_id"}, cfg.Ignore.Fields)
+
+	rec = httptest.NewRecorder()
+	api.handleSetShadow(rec, httptest.NewRequest(http.MethodPut, "/shadow",
+		strings.NewReader(`{"enabled":true,"upstream":"ftp://nope"}`)), engine)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "upstream must be an http or https URL")
+}
+
+func TestHandleGetMockDrift(t *testing.T) {
+	server, _ := newMockShadowEngineServer(t)
+	engine := engineclient.New(server.URL)
+	api := NewAPI(0, WithDataDir(t.TempDir()), WithLocalEngineClient(engine))
+
+	ctx := t.Context()
+	for _, id := range []string{"users", "health"} {
+		require.NoError(t, api.dataStore.Mocks().Create(ctx, &config.MockConfiguration{ID: id, Type: mock.TypeHTTP}))
+	}
+
+	get := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/mocks/"+id+"/drift", nil)
+		req.SetPathValue("id", id)
+		rec := httptest.NewRecorder()
+		api.handleGetMockDrift(rec, req, engine)
+		return rec
+	}
+
+	rec := get("users")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var drift engineclient.MockDrift
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &drift))
+	assert.Equal(t, int64(1), drift.Drifted)
+	require.Len(t, drift.Differences, 1)
+	assert.Equal(t, "$.email", drift.Differences[0].Path)
+
+	// A mock that was never shadowed has no drift
+	rec = get("health")
+	require.Equal(t, http.StatusOK, rec.Code)
+	drift = engineclient.MockDrift{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &drift))
+	assert.Equal(t, engineclient.MockDrift{MockID: "health"}, drift)
+
+	assert.Equal(t, http.StatusNotFound, get("missing").Code)
+}
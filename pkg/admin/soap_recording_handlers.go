@@ -93,7 +93,8 @@ func (m *SOAPRecordingManager) ListHandlers() []string {
 
 // soapStoreAdapter adapts SOAPStore to the soap.SOAPRecordingStore interface.
 type soapStoreAdapter struct {
-	store *recording.SOAPStore
+	store    *recording.SOAPStore
+	redactor *recording.Redactor
 }
 
 func (a *soapStoreAdapter) Add(data soap.SOAPRecordingData) error {
@@ -109,6 +110,7 @@ func (a *soapStoreAdapter) Add(data soap.SOAPRecordingData) error {
 	if data.HasFault {
 		rec.SetFault(data.FaultCode, data.FaultMessage)
 	}
+	a.redactor.RedactSOAP(rec)
 	return a.store.Add(rec)
 }
 
@@ -310,6 +312,18 @@ func (m *SOAPRecordingManager) handleStartSOAPRecording(w http.ResponseWriter, r
 		return
 	}
 
+	var req RecordingStartRequest
+	if err := decodeOptionalJSONBody(r, &req); err != nil {
+		writeJSONDecodeError(w, err, m.log)
+		return
+	}
+	redactor, err := recording.NewRedactor(req.Redaction)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_redaction", err.Error())
+		return
+	}
+
+	handler.SetRecordingStore(&soapStoreAdapter{store: m.Store(), redactor: redactor})
 	handler.EnableRecording()
 
 	writeJSON(w, http.StatusOK, SOAPRecordingStartResponse{
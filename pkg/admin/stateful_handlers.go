@@ -1,11 +1,15 @@
 package admin
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/getmockd/mockd/pkg/admin/engineclient"
 	"github.com/getmockd/mockd/pkg/config"
@@ -320,6 +324,174 @@ func (a *API) handleCreateStatefulItem(w http.ResponseWriter, r *http.Request, e
 	writeJSON(w, http.StatusCreated, item)
 }
 
+// handleUpdateStatefulItem replaces an item in a stateful resource (PUT semantics).
+func (a *API) handleUpdateStatefulItem(w http.ResponseWriter, r *http.Request, engine *engineclient.Client) {
+	a.writeStatefulItem(w, r, "update stateful item", engine.UpdateStatefulItem)
+}
+
+// handlePatchStatefulItem merges fields into an item in a stateful resource (PATCH semantics).
+func (a *API) handlePatchStatefulItem(w http.ResponseWriter, r *http.Request, engine *engineclient.Client) {
+	a.writeStatefulItem(w, r, "patch stateful item", engine.PatchStatefulItem)
+}
+
+// writeStatefulItem is the shared implementation for PUT and PATCH on a single item.
+func (a *API) writeStatefulItem(w http.ResponseWriter, r *http.Request, operation string,
+	mutate func(context.Context, string, string, string, map[string]interface{}) (map[string]interface{}, error)) {
+	ctx := r.Context()
+	workspaceID := r.URL.Query().Get("workspaceId")
+	name := r.PathValue("name")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "missing_name", "Resource name is required")
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "missing_id", "Item ID is required")
+		return
+	}
+
+	var data map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		writeJSONDecodeError(w, err, a.logger())
+		return
+	}
+
+	item, err := mutate(ctx, workspaceID, name, id, data)
+	if err != nil {
+		status, code, msg := mapStatefulItemError(err, a.logger(), operation)
+		writeError(w, status, code, msg)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, item)
+}
+
+// handleDeleteStatefulItem removes an item from a stateful resource.
+func (a *API) handleDeleteStatefulItem(w http.ResponseWriter, r *http.Request, engine *engineclient.Client) {
+	ctx := r.Context()
+	workspaceID := r.URL.Query().Get("workspaceId")
+	name := r.PathValue("name")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "missing_name", "Resource name is required")
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "missing_id", "Item ID is required")
+		return
+	}
+
+	if err := engine.DeleteStatefulItem(ctx, workspaceID, name, id); err != nil {
+		status, code, msg := mapStatefulItemError(err, a.logger(), "delete stateful item")
+		writeError(w, status, code, msg)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"deleted":  true,
+		"resource": name,
+		"id":       id,
+	})
+}
+
+// maxStateImportBodySize is the body limit for bulk item imports (256MB).
+// The body is streamed through to the engine rather than buffered, so this
+// bounds transfer size rather than memory.
+const maxStateImportBodySize = 256 << 20
+
+// handleImportStatefulItems bulk-loads items into a stateful resource.
+// POST /state/resources/{name}/import?format=json|ndjson|csv&mode=append|upsert|replace
+//
+// The body is streamed to the engine unchanged. When ?format= is omitted the
+// Content-Type header decides; anything unrecognized is treated as a JSON array.
+func (a *API) handleImportStatefulItems(w http.ResponseWriter, r *http.Request, engine *engineclient.Client) {
+	ctx := r.Context()
+	workspaceID := r.URL.Query().Get("workspaceId")
+	name := r.PathValue("name")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "missing_name", "Resource name is required")
+		return
+	}
+
+	params := url.Values{}
+	for _, key := range []string{"format", "mode", "validate", "stopOnError"} {
+		if v := r.URL.Query().Get(key); v != "" {
+			params.Set(key, v)
+		}
+	}
+
+	result, err := engine.ImportStatefulItems(ctx, workspaceID, name, params, r.Header.Get("Content-Type"), r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, "body_too_large", "Request body too large")
+			return
+		}
+		status, code, msg := mapStatefulItemError(err, a.logger(), "import stateful items")
+		errResp := ErrorResponse{Error: code, Message: msg}
+		if result != nil {
+			// Rows before the failure were already imported.
+			errResp.Details = result
+		}
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleExportStatefulItems streams every item in a stateful resource.
+// GET /state/resources/{name}/export?format=json|ndjson|csv
+func (a *API) handleExportStatefulItems(w http.ResponseWriter, r *http.Request, engine *engineclient.Client) {
+	ctx := r.Context()
+	workspaceID := r.URL.Query().Get("workspaceId")
+	name := r.PathValue("name")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "missing_name", "Resource name is required")
+		return
+	}
+
+	body, contentType, err := engine.ExportStatefulItems(ctx, workspaceID, name, r.URL.Query().Get("format"))
+	if err != nil {
+		status, code, msg := mapStatefulItemError(err, a.logger(), "export stateful items")
+		writeError(w, status, code, msg)
+		return
+	}
+	defer func() { _ = body.Close() }()
+
+	if contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, body); err != nil {
+		a.logger().Warn("stateful export interrupted", "resource", name, "error", err)
+	}
+}
+
+// mapStatefulItemError maps engine client errors for item-level operations to
+// an HTTP status, error code, and client-safe message.
+func mapStatefulItemError(err error, log *slog.Logger, operation string) (int, string, string) {
+	switch {
+	case errors.Is(err, engineclient.ErrNotFound):
+		return http.StatusNotFound, "not_found", "Resource or item not found"
+	case errors.Is(err, engineclient.ErrConflict):
+		return http.StatusConflict, "conflict", "Item already exists"
+	case errors.Is(err, engineclient.ErrCapacity):
+		return http.StatusInsufficientStorage, "capacity_exceeded", "Resource capacity exceeded"
+	case errors.Is(err, engineclient.ErrInvalid):
+		// Engine validation messages (bad format, malformed payload, failed
+		// field validation) are safe to surface and needed to fix the input.
+		if log != nil {
+			log.Debug("stateful item request rejected", "operation", operation, "error", err)
+		}
+		return http.StatusBadRequest, "invalid_request", strings.TrimPrefix(err.Error(), engineclient.ErrInvalid.Error()+": ")
+	default:
+		return http.StatusServiceUnavailable, "engine_error", sanitizeEngineError(err, log, operation)
+	}
+}
+
 func mapCreateStatefulItemError(err error, log *slog.Logger) (int, string, string) {
 	if errors.Is(err, engineclient.ErrNotFound) {
 		return http.StatusNotFound, "not_found", "Resource not found"
@@ -387,6 +559,68 @@ func (a *API) handleResetTable(w http.ResponseWriter, r *http.Request, engine *e
 	})
 }
 
+// --- State Partition Handlers ---
+
+// handleListStatePartitions returns the live isolation partitions.
+func (a *API) handleListStatePartitions(w http.ResponseWriter, r *http.Request, engine *engineclient.Client) {
+	ctx := r.Context()
+	workspaceID := r.URL.Query().Get("workspaceId")
+
+	partitions, err := engine.ListStatePartitions(ctx, workspaceID)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, "engine_error", sanitizeEngineError(err, a.logger(), "list state partitions"))
+		return
+	}
+
+	if partitions == nil {
+		partitions = []*engineclient.StatePartition{}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"partitions": partitions,
+		"count":      len(partitions),
+	})
+}
+
+// handleGetStatePartition returns a single isolation partition with the item
+// count of each table copied into it.
+func (a *API) handleGetStatePartition(w http.ResponseWriter, r *http.Request, engine *engineclient.Client) {
+	ctx := r.Context()
+	workspaceID := r.URL.Query().Get("workspaceId")
+	key := r.PathValue("key")
+
+	partition, err := engine.GetStatePartition(ctx, workspaceID, key)
+	if err != nil {
+		if errors.Is(err, engineclient.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "not_found", "Partition not found: "+key)
+			return
+		}
+		writeError(w, http.StatusServiceUnavailable, "engine_error", sanitizeEngineError(err, a.logger(), "get state partition"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, partition)
+}
+
+// handleResetStatePartition discards an isolation partition. Its next request
+// starts again from seed data.
+func (a *API) handleResetStatePartition(w http.ResponseWriter, r *http.Request, engine *engineclient.Client) {
+	ctx := r.Context()
+	workspaceID := r.URL.Query().Get("workspaceId")
+	key := r.PathValue("key")
+
+	if err := engine.ResetStatePartition(ctx, workspaceID, key); err != nil {
+		if errors.Is(err, engineclient.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "not_found", "Partition not found: "+key)
+			return
+		}
+		writeError(w, http.StatusServiceUnavailable, "engine_error", sanitizeEngineError(err, a.logger(), "reset state partition"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "reset", "partition": key})
+}
+
 // --- Custom Operation Handlers ---
 
 // handleListCustomOperations returns all registered custom operations.
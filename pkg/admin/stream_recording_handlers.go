@@ -112,6 +112,8 @@ type StartRecordingRequest struct {
 	Path     string            `json:"path"`
 	Headers  map[string]string `json:"headers,omitempty"`
 	Name     string            `json:"name,omitempty"`
+	// Redaction replaces the store's redaction rules for this recording.
+	Redaction *recording.RedactionConfig `json:"redaction,omitempty"`
 }
 
 // StartRecordingResponse represents the response from starting a recording.
@@ -329,6 +331,12 @@ func (m *StreamRecordingManager) handleStartRecording(w http.ResponseWriter, r *
 		return
 	}
 
+	redactor, err := recording.NewRedactor(req.Redaction)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_redaction", err.Error())
+		return
+	}
+
 	// Create metadata
 	metadata := recording.RecordingMetadata{
 		Path:    req.Path,
@@ -348,6 +356,9 @@ func (m *StreamRecordingManager) handleStartRecording(w http.ResponseWriter, r *
 	if req.Name != "" {
 		session.Recording().Name = req.Name
 	}
+	if redactor != nil {
+		_ = m.store.SetSessionRedactor(session.ID(), redactor)
+	}
 
 	writeJSON(w, http.StatusCreated, StartRecordingResponse{
 		SessionID:   session.ID(),
@@ -0,0 +1,132 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/getmockd/mockd/pkg/tcpproxy"
+)
+
+// --- TCP fault-injection proxies ---
+
+// handleListTCPProxies returns every TCP proxy with its toxics and stats.
+func (a *API) handleListTCPProxies(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, a.tcpProxies.List())
+}
+
+// handleCreateTCPProxy starts a TCP proxy.
+func (a *API) handleCreateTCPProxy(w http.ResponseWriter, r *http.Request) {
+	var cfg tcpproxy.Config
+	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+		writeJSONDecodeError(w, err, a.logger())
+		return
+	}
+
+	p, err := a.tcpProxies.Create(cfg)
+	switch {
+	case errors.Is(err, tcpproxy.ErrProxyExists):
+		writeError(w, http.StatusConflict, "already_exists", err.Error())
+		return
+	case err != nil:
+		writeError(w, http.StatusBadRequest, "validation_error", err.Error())
+		return
+	}
+
+	a.logger().Info("tcp proxy started", "name", cfg.Name, "listen", p.Addr(), "upstream", cfg.Upstream)
+	writeJSON(w, http.StatusCreated, p.Status())
+}
+
+// handleGetTCPProxy returns a TCP proxy with its toxics and stats.
+func (a *API) handleGetTCPProxy(w http.ResponseWriter, r *http.Request) {
+	p, ok := a.getTCPProxy(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, p.Status())
+}
+
+// handleDeleteTCPProxy stops a TCP proxy and closes its connections.
+func (a *API) handleDeleteTCPProxy(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if err := a.tcpProxies.Delete(name); err != nil {
+		if errors.Is(err, tcpproxy.ErrProxyNotFound) {
+			writeError(w, http.StatusNotFound, "not_found", "tcp proxy not found: "+name)
+			return
+		}
+		a.logger().Warn("error closing tcp proxy", "name", name, "error", err)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleSetTCPToxics replaces every toxic of a TCP proxy. An empty list
+// clears them.
+func (a *API) handleSetTCPToxics(w http.ResponseWriter, r *http.Request) {
+	p, ok := a.getTCPProxy(w, r)
+	if !ok {
+		return
+	}
+
+	var toxics []tcpproxy.Toxic
+	if err := json.NewDecoder(r.Body).Decode(&toxics); err != nil {
+		writeJSONDecodeError(w, err, a.logger())
+		return
+	}
+	if err := p.SetToxics(toxics); err != nil {
+		writeError(w, http.StatusBadRequest, "validation_error", err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, p.Status())
+}
+
+// handleAddTCPToxic adds one toxic to a TCP proxy.
+func (a *API) handleAddTCPToxic(w http.ResponseWriter, r *http.Request) {
+	p, ok := a.getTCPProxy(w, r)
+	if !ok {
+		return
+	}
+
+	var toxic tcpproxy.Toxic
+	if err := json.NewDecoder(r.Body).Decode(&toxic); err != nil {
+		writeJSONDecodeError(w, err, a.logger())
+		return
+	}
+	added, err := p.AddToxic(toxic)
+	switch {
+	case errors.Is(err, tcpproxy.ErrToxicExists):
+		writeError(w, http.StatusConflict, "already_exists", err.Error())
+		return
+	case err != nil:
+		writeError(w, http.StatusBadRequest, "validation_error", err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, added)
+}
+
+// handleRemoveTCPToxic removes one toxic from a TCP proxy.
+func (a *API) handleRemoveTCPToxic(w http.ResponseWriter, r *http.Request) {
+	p, ok := a.getTCPProxy(w, r)
+	if !ok {
+		return
+	}
+
+	toxic := r.PathValue("toxic")
+	if err := p.RemoveToxic(toxic); err != nil {
+		writeError(w, http.StatusNotFound, "not_found", "toxic not found: "+toxic)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getTCPProxy returns the proxy named in the request path, writing a 404 if
+// there is none.
+func (a *API) getTCPProxy(w http.ResponseWriter, r *http.Request) (*tcpproxy.Proxy, bool) {
+	name := r.PathValue("name")
+	p, ok := a.tcpProxies.Get(name)
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "tcp proxy not found: "+name)
+	}
+	return p, ok
+}
@@ -0,0 +1,183 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/getmockd/mockd/pkg/admin/engineclient"
+	"github.com/getmockd/mockd/pkg/api/types"
+	"github.com/getmockd/mockd/pkg/tcpproxy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startTCPEcho starts a TCP echo server and returns its address.
+func startTCPEcho(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func serveTCPProxyRequest(api *API, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != nil {
+		data, _ := json.Marshal(body)
+		reader = bytes.NewReader(data)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	rec := httptest.NewRecorder()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /chaos/tcp-proxies", api.handleListTCPProxies)
+	mux.HandleFunc("POST /chaos/tcp-proxies", api.handleCreateTCPProxy)
+	mux.HandleFunc("GET /chaos/tcp-proxies/{name}", api.handleGetTCPProxy)
+	mux.HandleFunc("DELETE /chaos/tcp-proxies/{name}", api.handleDeleteTCPProxy)
+	mux.HandleFunc("PUT /chaos/tcp-proxies/{name}/toxics", api.handleSetTCPToxics)
+	mux.HandleFunc("POST /chaos/tcp-proxies/{name}/toxics", api.handleAddTCPToxic)
+	mux.HandleFunc("DELETE /chaos/tcp-proxies/{name}/toxics/{toxic}", api.handleRemoveTCPToxic)
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestTCPProxyHandlers(t *testing.T) {
+	api := NewAPI(0, WithDataDir(t.TempDir()))
+	defer api.tcpProxies.Close()
+	upstream := startTCPEcho(t)
+
+	rec := serveTCPProxyRequest(api, http.MethodPost, "/chaos/tcp-proxies", tcpproxy.Config{
+		Name: "redis", Listen: "127.0.0.1:0", Upstream: upstream,
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created tcpproxy.Status
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.NotEqual(t, "127.0.0.1:0", created.Listen, "the actual listen address is reported")
+
+	rec = serveTCPProxyRequest(api, http.MethodPost, "/chaos/tcp-proxies", tcpproxy.Config{
+		Name: "redis", Listen: "127.0.0.1:0", Upstream: upstream,
+	})
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	rec = serveTCPProxyRequest(api, http.MethodPost, "/chaos/tcp-proxies", tcpproxy.Config{Name: "nowhere", Listen: "127.0.0.1:0"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Traffic flows through the proxy.
+	conn, err := net.Dial("tcp", created.Listen)
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("PING"))
+	require.NoError(t, err)
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "PING", string(buf))
+	_ = conn.Close()
+
+	rec = serveTCPProxyRequest(api, http.MethodPost, "/chaos/tcp-proxies/redis/toxics", tcpproxy.Toxic{Type: "latency", Latency: "10ms"})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var added tcpproxy.Toxic
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &added))
+	assert.Equal(t, "latency_downstream", added.Name)
+
+	rec = serveTCPProxyRequest(api, http.MethodPost, "/chaos/tcp-proxies/redis/toxics", tcpproxy.Toxic{Type: "latency", Latency: "10ms"})
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	rec = serveTCPProxyRequest(api, http.MethodPost, "/chaos/tcp-proxies/redis/toxics", tcpproxy.Toxic{Type: "gremlins"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = serveTCPProxyRequest(api, http.MethodPut, "/chaos/tcp-proxies/redis/toxics", []tcpproxy.Toxic{
+		{Type: "bandwidth", BytesPerSecond: 1024},
+		{Type: "reset_peer", Stream: "upstream", Timeout: "5s"},
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = serveTCPProxyRequest(api, http.MethodGet, "/chaos/tcp-proxies/redis", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var status tcpproxy.Status
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	require.Len(t, status.Toxics, 2)
+	assert.Equal(t, "bandwidth_downstream", status.Toxics[0].Name)
+	assert.Equal(t, int64(1), status.Stats.Connections)
+	assert.Equal(t, int64(4), status.Stats.BytesUpstream)
+
+	rec = serveTCPProxyRequest(api, http.MethodDelete, "/chaos/tcp-proxies/redis/toxics/bandwidth_downstream", nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = serveTCPProxyRequest(api, http.MethodDelete, "/chaos/tcp-proxies/redis/toxics/bandwidth_downstream", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = serveTCPProxyRequest(api, http.MethodGet, "/chaos/tcp-proxies", nil)
+	var list []tcpproxy.Status
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list, 1)
+	assert.Len(t, list[0].Toxics, 1)
+
+	rec = serveTCPProxyRequest(api, http.MethodDelete, "/chaos/tcp-proxies/redis", nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = serveTCPProxyRequest(api, http.MethodGet, "/chaos/tcp-proxies/redis", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestChaosExperiment_TCPToxics(t *testing.T) {
+	setFastExperimentPolling(t)
+	server := newExperimentEngineServer(engineclient.ChaosConfig{})
+	defer server.Close()
+	api := NewAPI(0, WithDataDir(t.TempDir()), WithLocalEngineClient(server.client()))
+	defer api.tcpProxies.Close()
+
+	proxy, err := api.tcpProxies.Create(tcpproxy.Config{
+		Name: "db", Listen: "127.0.0.1:0", Upstream: startTCPEcho(t),
+		Toxics: []tcpproxy.Toxic{{Name: "baseline", Type: "latency", Latency: "1ms"}},
+	})
+	require.NoError(t, err)
+
+	// Unknown proxies and invalid toxics are rejected up front.
+	rec := startExperiment(t, api, server.client(), types.ChaosExperiment{Phases: []types.ChaosExperimentPhase{{
+		Duration: "1s", TCPToxics: map[string][]tcpproxy.Toxic{"missing": {{Type: "half_open"}}},
+	}}})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = startExperiment(t, api, server.client(), types.ChaosExperiment{Phases: []types.ChaosExperimentPhase{{
+		Duration: "1s", TCPToxics: map[string][]tcpproxy.Toxic{"db": {{Type: "latency"}}},
+	}}})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = startExperiment(t, api, server.client(), types.ChaosExperiment{Phases: []types.ChaosExperimentPhase{
+		{
+			Name:      "slow-db",
+			Duration:  "1m",
+			TCPToxics: map[string][]tcpproxy.Toxic{"db": {{Type: "latency", Latency: "200ms"}}},
+			Ramp:      &types.ChaosExperimentRamp{From: 0.5, To: 0.5, Steps: 1},
+		},
+	}})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var started types.ChaosExperimentStatus
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &started))
+
+	require.Eventually(t, func() bool {
+		toxics := proxy.Toxics()
+		return len(toxics) == 1 && toxics[0].Name == "latency_downstream"
+	}, 2*time.Second, 5*time.Millisecond)
+	toxic := proxy.Toxics()[0]
+	require.NotNil(t, toxic.Toxicity)
+	assert.InDelta(t, 0.5, *toxic.Toxicity, 0.001, "toxicity follows the ramp scale")
+
+	_, _, err = api.chaosExperiments.Abort(started.ID, "aborted by user")
+	require.NoError(t, err)
+	toxics := proxy.Toxics()
+	require.Len(t, toxics, 1)
+	assert.Equal(t, "baseline", toxics[0].Name, "the toxics active before the experiment are restored")
+}
@@ -16,6 +16,8 @@ func ChaosConfigFromInternal(src *chaos.ChaosConfig) ChaosConfig {
 
 	cfg := ChaosConfig{
 		Enabled: src.Enabled,
+		Seed:    src.Seed,
+		Replay:  src.Replay,
 	}
 
 	// Convert global rules (flat latency/error/bandwidth fields)
@@ -48,6 +50,7 @@ func ChaosConfigFromInternal(src *chaos.ChaosConfig) ChaosConfig {
 	// Convert per-path rules
 	for _, rule := range src.Rules {
 		apiRule := ChaosRuleConfig{
+			Protocol:    rule.Protocol,
 			PathPattern: rule.PathPattern,
 			Probability: rule.Probability,
 		}
@@ -55,6 +58,19 @@ func ChaosConfigFromInternal(src *chaos.ChaosConfig) ChaosConfig {
 			apiRule.Methods = make([]string, len(rule.Methods))
 			copy(apiRule.Methods, rule.Methods)
 		}
+		if m := rule.Match; m != nil {
+			apiRule.Match = &ChaosRuleMatch{
+				Headers:    m.Headers,
+				Query:      m.Query,
+				ClientIPs:  m.ClientIPs,
+				ClientCN:   m.ClientCN,
+				JWTClaims:  m.JWTClaims,
+				MockIDs:    m.MockIDs,
+				MockName:   m.MockName,
+				Tags:       m.Tags,
+				Workspaces: m.Workspaces,
+			}
+		}
 		for _, f := range rule.Faults {
 			apiRule.Faults = append(apiRule.Faults, ChaosFaultConfig{
 				Type:        string(f.Type),
@@ -79,6 +95,8 @@ func ChaosConfigToInternal(src *ChaosConfig) *chaos.ChaosConfig {
 
 	cfg := &chaos.ChaosConfig{
 		Enabled: src.Enabled,
+		Seed:    src.Seed,
+		Replay:  src.Replay,
 	}
 
 	// Convert global rules
@@ -109,10 +127,24 @@ func ChaosConfigToInternal(src *ChaosConfig) *chaos.ChaosConfig {
 	// Convert per-path rules
 	for _, rule := range src.Rules {
 		cr := chaos.ChaosRule{
+			Protocol:    rule.Protocol,
 			PathPattern: rule.PathPattern,
 			Methods:     rule.Methods,
 			Probability: rule.Probability,
 		}
+		if m := rule.Match; m != nil {
+			cr.Match = &chaos.RuleMatch{
+				Headers:    m.Headers,
+				Query:      m.Query,
+				ClientIPs:  m.ClientIPs,
+				ClientCN:   m.ClientCN,
+				JWTClaims:  m.JWTClaims,
+				MockIDs:    m.MockIDs,
+				MockName:   m.MockName,
+				Tags:       m.Tags,
+				Workspaces: m.Workspaces,
+			}
+		}
 		for _, f := range rule.Faults {
 			cr.Faults = append(cr.Faults, chaos.FaultConfig{
 				Type:        chaos.FaultType(f.Type),
@@ -6,8 +6,11 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/getmockd/mockd/pkg/chaos"
 	"github.com/getmockd/mockd/pkg/config"
 	"github.com/getmockd/mockd/pkg/requestlog"
+	"github.com/getmockd/mockd/pkg/shadow"
+	"github.com/getmockd/mockd/pkg/tcpproxy"
 )
 
 // --- General Responses ---
@@ -157,6 +160,9 @@ type RequestLogEntry struct {
 	// Near-miss debugging data (populated for unmatched requests).
 	NearMisses []requestlog.NearMissInfo `json:"nearMisses,omitempty"`
 
+	// Chaos faults injected into the request, linked to the fault log.
+	Chaos *requestlog.ChaosMeta `json:"chaos,omitempty"`
+
 	// Protocol-specific metadata (only one populated based on Protocol).
 	GRPC      *requestlog.GRPCMeta      `json:"grpc,omitempty"`
 	WebSocket *requestlog.WebSocketMeta `json:"websocket,omitempty"`
@@ -202,8 +208,18 @@ type ChaosConfig struct {
 	ErrorRate *ErrorRateConfig  `json:"errorRate,omitempty"`
 	Bandwidth *BandwidthConfig  `json:"bandwidth,omitempty"`
 	Rules     []ChaosRuleConfig `json:"rules,omitempty"`
+
+	// Seed seeds chaos's random numbers; zero picks one at random.
+	Seed int64 `json:"seed,omitempty"`
+	// Replay replays the records of an exported fault log instead of
+	// rolling probabilities.
+	Replay []chaos.FaultRecord `json:"replay,omitempty"`
 }
 
+// ChaosFaultLog is an export of the chaos fault log: the faults injected
+// into each request, replayable through ChaosConfig.Replay.
+type ChaosFaultLog = chaos.FaultLog
+
 // LatencyConfig configures latency injection.
 type LatencyConfig struct {
 	Min         string  `json:"min"`
@@ -226,10 +242,26 @@ type BandwidthConfig struct {
 
 // ChaosRuleConfig represents a path-specific chaos rule.
 type ChaosRuleConfig struct {
+	Protocol    string             `json:"protocol,omitempty"`
 	PathPattern string             `json:"pathPattern"`
 	Methods     []string           `json:"methods,omitempty"`
 	Faults      []ChaosFaultConfig `json:"faults,omitempty"`
 	Probability float64            `json:"probability,omitempty"`
+	Match       *ChaosRuleMatch    `json:"match,omitempty"`
+}
+
+// ChaosRuleMatch narrows an HTTP chaos rule to particular callers and mocks.
+// Every condition that is set must hold.
+type ChaosRuleMatch struct {
+	Headers    map[string]string `json:"headers,omitempty"`   // header name -> regex
+	Query      map[string]string `json:"query,omitempty"`     // query parameter -> regex
+	ClientIPs  []string          `json:"clientIPs,omitempty"` // IPs or CIDR ranges
+	ClientCN   string            `json:"clientCN,omitempty"`  // regex for the mTLS client certificate CN
+	JWTClaims  map[string]string `json:"jwtClaims,omitempty"` // bearer token claim -> regex
+	MockIDs    []string          `json:"mockIds,omitempty"`
+	MockName   string            `json:"mockName,omitempty"` // regex
+	Tags       []string          `json:"tags,omitempty"`
+	Workspaces []string          `json:"workspaces,omitempty"`
 }
 
 // ChaosFaultConfig represents a fault within a chaos rule.
@@ -311,6 +343,83 @@ type ChaosStats struct {
 	ErrorsInjected   int64            `json:"errorsInjected"`
 	TimeoutsInjected int64            `json:"timeoutsInjected"`
 	FaultsByType     map[string]int64 `json:"faultsByType"`
+	// Message chaos (WebSocket, SSE, MQTT)
+	MessagesProcessed int64 `json:"messagesProcessed"`
+	MessagesDropped   int64 `json:"messagesDropped"`
+	// TCPProxies holds the stats of the admin's TCP fault-injection proxies,
+	// by proxy name.
+	TCPProxies map[string]tcpproxy.Stats `json:"tcpProxies,omitempty"`
+}
+
+// ChaosExperiment defines a scheduled chaos experiment: ordered phases that
+// each apply a chaos configuration for a fixed time. When the experiment ends,
+// is aborted or fails, the chaos configuration active before it is restored.
+type ChaosExperiment struct {
+	Name   string                 `json:"name,omitempty"`
+	Phases []ChaosExperimentPhase `json:"phases"`
+}
+
+// ChaosExperimentPhase is one step of a chaos experiment.
+type ChaosExperimentPhase struct {
+	Name     string `json:"name"`
+	Duration string `json:"duration"` // Go duration, e.g. "5m"
+	// Chaos is applied for the phase; nil runs the phase with chaos disabled
+	// (a baseline).
+	Chaos *ChaosConfig `json:"chaos,omitempty"`
+	// TCPToxics sets the toxics of TCP proxies, by proxy name, for the phase.
+	// Proxies named in any phase have no toxics in the phases that omit them.
+	TCPToxics map[string][]tcpproxy.Toxic `json:"tcpToxics,omitempty"`
+	Ramp      *ChaosExperimentRamp        `json:"ramp,omitempty"`
+	Abort     *ChaosExperimentAbort       `json:"abort,omitempty"`
+}
+
+// ChaosExperimentRamp scales every probability in a phase's chaos config from
+// From to To in equal steps spread over the phase.
+type ChaosExperimentRamp struct {
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Steps int     `json:"steps,omitempty"` // default 10
+}
+
+// ChaosExperimentAbort lists the conditions that abort an experiment during a
+// phase. They are checked against the chaos stats accumulated in the phase.
+type ChaosExperimentAbort struct {
+	MaxInjectedFaults int64   `json:"maxInjectedFaults,omitempty"`
+	MaxFaultRate      float64 `json:"maxFaultRate,omitempty"` // injected faults per request, 0.0-1.0
+	MinRequests       int64   `json:"minRequests,omitempty"`  // requests before maxFaultRate applies
+}
+
+// ChaosExperimentStatus reports the progress of a chaos experiment.
+type ChaosExperimentStatus struct {
+	ID           string                       `json:"id"`
+	Name         string                       `json:"name,omitempty"`
+	State        string                       `json:"state"` // running, completed, aborted, failed
+	CurrentPhase string                       `json:"currentPhase,omitempty"`
+	StartedAt    time.Time                    `json:"startedAt"`
+	EndedAt      *time.Time                   `json:"endedAt,omitempty"`
+	Reason       string                       `json:"reason,omitempty"` // why it was aborted or failed
+	Phases       []ChaosExperimentPhaseStatus `json:"phases"`
+	Timeline     []ChaosExperimentEvent       `json:"timeline,omitempty"`
+	Experiment   ChaosExperiment              `json:"experiment"`
+}
+
+// ChaosExperimentPhaseStatus reports the progress of one experiment phase.
+type ChaosExperimentPhaseStatus struct {
+	Name           string     `json:"name"`
+	State          string     `json:"state"` // pending, running, completed, aborted, skipped
+	StartedAt      *time.Time `json:"startedAt,omitempty"`
+	EndedAt        *time.Time `json:"endedAt,omitempty"`
+	Scale          float64    `json:"scale"` // current ramp scale (1 without a ramp)
+	Requests       int64      `json:"requests"`
+	InjectedFaults int64      `json:"injectedFaults"`
+}
+
+// ChaosExperimentEvent is one entry of an experiment's timeline.
+type ChaosExperimentEvent struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"` // started, phase_started, ramp_step, phase_completed, aborted, failed, reverted, completed
+	Phase   string    `json:"phase,omitempty"`
+	Message string    `json:"message,omitempty"`
 }
 
 // StatefulFaultStats contains stats for all stateful chaos faults.
@@ -356,6 +465,19 @@ type ProgressiveDegradationStatus struct {
 	IsErroring     bool  `json:"isErroring"`
 }
 
+// --- Shadow Mode ---
+
+// ShadowConfig configures shadow mode: selected mock requests are also sent
+// to the upstream and the responses compared.
+type ShadowConfig = shadow.Config
+
+// DriftReport reports the differences shadow mode found between mock and
+// upstream responses, per mock.
+type DriftReport = shadow.Report
+
+// MockDrift is the drift of one mock.
+type MockDrift = shadow.MockDrift
+
 // --- Stateful Resources ---
 
 // StatefulResource represents a stateful mock resource for the API.
@@ -375,6 +497,22 @@ type StateOverview struct {
 	ResourceList []string           `json:"resourceList"`
 }
 
+// StatePartition describes an isolation partition of the stateful tables.
+type StatePartition struct {
+	Key        string    `json:"key"`
+	Created    time.Time `json:"created"`
+	LastAccess time.Time `json:"lastAccess"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	// Resources maps each table copied into the partition to its item count.
+	Resources map[string]int `json:"resources"`
+}
+
+// StatePartitionListResponse lists the isolation partitions of a workspace.
+type StatePartitionListResponse struct {
+	Partitions []*StatePartition `json:"partitions"`
+	Count      int               `json:"count"`
+}
+
 // ResetStateRequest is the request body for resetting state.
 type ResetStateRequest struct {
 	Resource string `json:"resource,omitempty"`
@@ -393,6 +531,25 @@ type StatefulItemsResponse struct {
 	Meta PaginationMeta           `json:"meta"`
 }
 
+// StatefulImportResponse summarizes a bulk import into a stateful resource.
+type StatefulImportResponse struct {
+	Resource string                `json:"resource"`
+	Format   string                `json:"format"`
+	Mode     string                `json:"mode"`
+	Total    int                   `json:"total"`
+	Created  int                   `json:"created"`
+	Updated  int                   `json:"updated"`
+	Failed   int                   `json:"failed"`
+	Errors   []StatefulImportError `json:"errors,omitempty"`
+}
+
+// StatefulImportError describes a single row rejected during a bulk import.
+type StatefulImportError struct {
+	Row   int    `json:"row"`
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error"`
+}
+
 // --- Custom Operations ---
 
 // CustomOperationInfo is a summary of a registered custom operation.
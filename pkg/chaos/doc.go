@@ -17,6 +17,15 @@
 //   - Connection Reset: Simulates connection reset
 //   - Partial Response: Truncates responses
 //
+// Rules with Protocol "grpc" target mock gRPC servers by "package.Service/Method"
+// (see Injector.ShouldInjectGRPC) and add gRPC-specific faults: status codes
+// with error details, RESOURCE_EXHAUSTED with RetryInfo, stream aborts,
+// dropped trailers and deadline expiry.
+//
+// Rules with Protocol "websocket", "sse" or "mqtt" act on individual messages
+// of a stream (see MessageChaos): dropping, duplicating, reordering, delaying
+// or throttling them, or closing the stream with a chosen code and reason.
+//
 // # Configuration
 //
 // Chaos injection is configured via ChaosConfig:
@@ -0,0 +1,153 @@
+package chaos
+
+import "time"
+
+// Fault log limits. The log keeps the most recent records; requests beyond
+// maxOccurrenceKeys distinct targets are not numbered and cannot be replayed.
+const (
+	maxFaultRecords   = 10000
+	maxOccurrenceKeys = 100000
+)
+
+// globalRule is the InjectedFault.Rule of faults from the global settings.
+const globalRule = -1
+
+// FaultLog is an export of an injector's fault log, the input of replay.
+type FaultLog struct {
+	// Seed is the seed of the injector's random numbers: the configured
+	// seed, or the one picked when none was configured.
+	Seed int64 `json:"seed"`
+	// Records lists the requests that received faults, oldest first.
+	Records []FaultRecord `json:"records"`
+	// Dropped counts the oldest records evicted when the log was full.
+	Dropped int64 `json:"dropped,omitempty"`
+}
+
+// FaultRecord records the faults injected into one HTTP request or gRPC call.
+type FaultRecord struct {
+	Seq  int64     `json:"seq" yaml:"seq"`
+	Time time.Time `json:"time" yaml:"time"`
+	// RequestID is the ID of the request's entry in the request log. It is
+	// set once the request is logged; gRPC calls have none.
+	RequestID string `json:"requestId,omitempty" yaml:"requestId,omitempty"`
+	Protocol  string `json:"protocol" yaml:"protocol"`
+	Method    string `json:"method,omitempty" yaml:"method,omitempty"`
+	Target    string `json:"target" yaml:"target"`
+	// Occurrence numbers the requests with the same protocol, method and
+	// target from 1, faulted or not. Replay matches requests by it.
+	Occurrence int64           `json:"occurrence" yaml:"occurrence"`
+	Faults     []InjectedFault `json:"faults" yaml:"faults"`
+}
+
+// InjectedFault is one fault of a FaultRecord.
+type InjectedFault struct {
+	// Rule is the index of the rule that injected the fault, or -1 for the
+	// global settings.
+	Rule int `json:"rule" yaml:"rule"`
+	// RuleDraw is the random number drawn against the rule's probability
+	// and Draw the one drawn against the fault's. Stateful faults are not
+	// drawn for.
+	RuleDraw float64     `json:"ruleDraw,omitempty" yaml:"ruleDraw,omitempty"`
+	Draw     float64     `json:"draw,omitempty" yaml:"draw,omitempty"`
+	Fault    FaultConfig `json:"fault" yaml:"fault"`
+}
+
+// faultLog is an injector's bounded fault log plus the replay index. It is
+// guarded by the injector's mutex.
+type faultLog struct {
+	seq         int64
+	records     []*FaultRecord
+	dropped     int64
+	occurrences map[string]int64
+
+	// replay maps a request key and occurrence to the faults to replay.
+	replay map[string]map[int64][]InjectedFault
+}
+
+func newFaultLog(replay []FaultRecord) *faultLog {
+	l := &faultLog{occurrences: make(map[string]int64)}
+	if len(replay) > 0 {
+		l.replay = make(map[string]map[int64][]InjectedFault)
+		for _, rec := range replay {
+			key := requestKey(rec.Protocol, rec.Method, rec.Target)
+			if l.replay[key] == nil {
+				l.replay[key] = make(map[int64][]InjectedFault)
+			}
+			l.replay[key][rec.Occurrence] = rec.Faults
+		}
+	}
+	return l
+}
+
+func requestKey(protocol, method, target string) string {
+	return protocol + " " + method + " " + target
+}
+
+// next numbers a request, returning 0 once too many targets are tracked.
+func (l *faultLog) next(key string) int64 {
+	n, ok := l.occurrences[key]
+	if !ok && len(l.occurrences) >= maxOccurrenceKeys {
+		return 0
+	}
+	n++
+	l.occurrences[key] = n
+	return n
+}
+
+// add appends a record, evicting the oldest when the log is full.
+func (l *faultLog) add(rec *FaultRecord) {
+	l.seq++
+	rec.Seq = l.seq
+	if len(l.records) >= maxFaultRecords {
+		l.records = l.records[1:]
+		l.dropped++
+	}
+	l.records = append(l.records, rec)
+}
+
+// replaying reports whether the log replays an exported fault log.
+func (l *faultLog) replaying() bool {
+	return l.replay != nil
+}
+
+// FaultLog returns a copy of the fault log.
+func (i *Injector) FaultLog() FaultLog {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	out := FaultLog{Seed: i.seed, Records: make([]FaultRecord, 0, len(i.faults.records)), Dropped: i.faults.dropped}
+	for _, rec := range i.faults.records {
+		out.Records = append(out.Records, *rec)
+	}
+	return out
+}
+
+// ResetFaultLog clears the fault log. Occurrence numbering continues, so
+// later records still line up with the requests since the injector started.
+func (i *Injector) ResetFaultLog() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.faults.records = nil
+	i.faults.dropped = 0
+}
+
+// Seed returns the seed of the injector's random numbers.
+func (i *Injector) Seed() int64 {
+	return i.seed
+}
+
+// LinkRequest sets the request log ID of the fault record with the given
+// sequence number, if the record is still in the log.
+func (i *Injector) LinkRequest(seq int64, requestID string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	records := i.faults.records
+	if len(records) == 0 {
+		return
+	}
+	idx := seq - records[0].Seq
+	if idx >= 0 && idx < int64(len(records)) {
+		records[idx].RequestID = requestID
+	}
+}
@@ -0,0 +1,199 @@
+package chaos
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func seededConfig(seed int64) *ChaosConfig {
+	return &ChaosConfig{
+		Enabled: true,
+		Seed:    seed,
+		Rules: []ChaosRule{{
+			PathPattern: "^/api/",
+			Probability: 0.5,
+			Faults: []FaultConfig{
+				{Type: FaultLatency, Probability: 0.5, Config: map[string]interface{}{"min": "1ms", "max": "1ms"}},
+				{Type: FaultError, Probability: 0.3, Config: map[string]interface{}{"defaultCode": 503}},
+			},
+		}},
+	}
+}
+
+// faultSequence sends n requests alternating between two paths and returns
+// the fault types each one received.
+func faultSequence(t *testing.T, injector *Injector, n int) [][]FaultType {
+	t.Helper()
+	var seq [][]FaultType
+	for idx := 0; idx < n; idx++ {
+		path := "/api/orders"
+		if idx%2 == 1 {
+			path = "/api/users"
+		}
+		var types []FaultType
+		for _, f := range injector.ShouldInject(httptest.NewRequest(http.MethodGet, path, nil)) {
+			types = append(types, f.Type)
+		}
+		seq = append(seq, types)
+	}
+	return seq
+}
+
+func TestInjector_SeedIsDeterministic(t *testing.T) {
+	a, err := NewInjector(seededConfig(42))
+	if err != nil {
+		t.Fatalf("NewInjector() error = %v", err)
+	}
+	b, err := NewInjector(seededConfig(42))
+	if err != nil {
+		t.Fatalf("NewInjector() error = %v", err)
+	}
+
+	seqA, seqB := faultSequence(t, a, 100), faultSequence(t, b, 100)
+	if !reflect.DeepEqual(seqA, seqB) {
+		t.Errorf("the same seed injected different faults:\n%v\n%v", seqA, seqB)
+	}
+	if got := a.FaultLog().Seed; got != 42 {
+		t.Errorf("FaultLog().Seed = %d, want 42", got)
+	}
+
+	random, err := NewInjector(seededConfig(0))
+	if err != nil {
+		t.Fatalf("NewInjector() error = %v", err)
+	}
+	if random.Seed() == 0 {
+		t.Error("an unseeded injector reports no seed")
+	}
+}
+
+func TestInjector_FaultLog(t *testing.T) {
+	injector, err := NewInjector(&ChaosConfig{
+		Enabled: true,
+		Rules: []ChaosRule{{
+			PathPattern: "^/flaky$",
+			Methods:     []string{http.MethodPost},
+			Probability: 1,
+			Faults:      []FaultConfig{{Type: FaultError, Probability: 1}},
+		}},
+		GlobalRules: &GlobalChaosRules{Latency: &LatencyFault{Min: "1ms", Max: "1ms", Probability: 1}},
+	})
+	if err != nil {
+		t.Fatalf("NewInjector() error = %v", err)
+	}
+
+	injector.ShouldInject(httptest.NewRequest(http.MethodPost, "/flaky", nil))
+	injector.ShouldInject(httptest.NewRequest(http.MethodGet, "/other", nil))
+	injector.ShouldInject(httptest.NewRequest(http.MethodPost, "/flaky", nil))
+	injector.ShouldInjectGRPC("/pkg.Svc/Get")
+
+	log := injector.FaultLog()
+	if len(log.Records) != 4 {
+		t.Fatalf("FaultLog() has %d records, want 4", len(log.Records))
+	}
+	third := log.Records[2]
+	if third.Seq != 3 || third.Protocol != ProtocolHTTP || third.Method != http.MethodPost || third.Target != "/flaky" || third.Occurrence != 2 {
+		t.Errorf("third record = %+v", third)
+	}
+	if len(third.Faults) != 1 || third.Faults[0].Rule != 0 || third.Faults[0].Fault.Type != FaultError {
+		t.Errorf("third record faults = %+v, want rule 0's error", third.Faults)
+	}
+	if f := log.Records[1].Faults[0]; f.Rule != globalRule || f.Fault.Type != FaultLatency {
+		t.Errorf("second record fault = %+v, want the global latency", f)
+	}
+	if grpc := log.Records[3]; grpc.Protocol != ProtocolGRPC || grpc.Target != "pkg.Svc/Get" {
+		t.Errorf("gRPC record = %+v", grpc)
+	}
+
+	injector.LinkRequest(third.Seq, "req-3")
+	if got := injector.FaultLog().Records[2].RequestID; got != "req-3" {
+		t.Errorf("RequestID = %q after LinkRequest, want req-3", got)
+	}
+
+	injector.ResetFaultLog()
+	if n := len(injector.FaultLog().Records); n != 0 {
+		t.Errorf("FaultLog() has %d records after ResetFaultLog()", n)
+	}
+	injector.LinkRequest(third.Seq, "gone") // must not panic
+}
+
+func TestInjector_Replay(t *testing.T) {
+	original, err := NewInjector(seededConfig(0))
+	if err != nil {
+		t.Fatalf("NewInjector() error = %v", err)
+	}
+	want := faultSequence(t, original, 200)
+
+	// Replay from an exported log, through JSON as the CLI does. The
+	// replaying config's probabilities no longer matter.
+	data, err := json.Marshal(original.FaultLog())
+	if err != nil {
+		t.Fatalf("marshal fault log: %v", err)
+	}
+	var exported FaultLog
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("unmarshal fault log: %v", err)
+	}
+	cfg := seededConfig(exported.Seed)
+	cfg.Rules[0].Probability = 0
+	cfg.Replay = exported.Records
+	replayer, err := NewInjector(cfg)
+	if err != nil {
+		t.Fatalf("NewInjector() error = %v", err)
+	}
+
+	if got := faultSequence(t, replayer, 200); !reflect.DeepEqual(got, want) {
+		t.Errorf("replay injected different faults:\n got %v\nwant %v", got, want)
+	}
+	if a, b := original.GetStats(), replayer.GetStats(); a.InjectedFaults != b.InjectedFaults || a.TotalRequests != b.TotalRequests {
+		t.Errorf("replay stats = %+v, want %+v", b, a)
+	}
+
+	// Error faults still answer with the recorded config.
+	for _, rec := range exported.Records {
+		for _, f := range rec.Faults {
+			if f.Fault.Type == FaultError {
+				code := replayer.StatusCodeFromConfig(f.Fault.Config)
+				if code != 503 {
+					t.Fatalf("replayed error code = %d, want 503", code)
+				}
+				return
+			}
+		}
+	}
+}
+
+func TestMiddleware_FaultLogContext(t *testing.T) {
+	injector, err := NewInjector(&ChaosConfig{
+		Enabled: true,
+		Rules: []ChaosRule{
+			{PathPattern: "^/slow$", Probability: 1, Faults: []FaultConfig{{Type: FaultLatency, Probability: 1, Config: map[string]interface{}{"min": "1ms", "max": "1ms"}}}},
+			{PathPattern: "^/down$", Probability: 1, Faults: []FaultConfig{{Type: FaultError, Probability: 1, Config: map[string]interface{}{"defaultCode": 502}}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewInjector() error = %v", err)
+	}
+
+	var handled *ChaosContext
+	mw := NewMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handled = GetChaosContext(r.Context())
+	}), injector)
+	var abortedSeq int64
+	var abortedStatus int
+	mw.OnAbort(func(r *http.Request, status int) {
+		abortedSeq, abortedStatus = GetChaosContext(r.Context()).Seq, status
+	})
+
+	mw.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+	if handled == nil || handled.Seq != 1 || !reflect.DeepEqual(handled.FaultTypes(), []string{"latency"}) {
+		t.Errorf("handler ChaosContext = %+v", handled)
+	}
+
+	mw.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/down", nil))
+	if abortedSeq != 2 || abortedStatus != http.StatusBadGateway {
+		t.Errorf("OnAbort got seq %d status %d, want 2 and 502", abortedSeq, abortedStatus)
+	}
+}
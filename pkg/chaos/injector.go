@@ -7,6 +7,7 @@ import (
 	"math/rand"
 	"net/http"
 	"regexp"
+	"strings"
 	"sync"
 	"time"
 )
@@ -16,20 +17,27 @@ type Injector struct {
 	config *ChaosConfig
 	rules  []*compiledRule
 	rng    *rand.Rand
+	seed   int64
 	mu     sync.Mutex
 	stats  *ChaosStats
+	faults *faultLog
 
 	// Stateful fault managers (keyed by "ruleIdx:faultIdx")
 	circuitBreakers map[string]*CircuitBreaker
 	retryTrackers   map[string]*RetryAfterTracker
 	progressives    map[string]*ProgressiveDegradation
+
+	// needsMockTarget is set when a rule matches on mocks; see NeedsMockTarget.
+	needsMockTarget bool
 }
 
 type compiledRule struct {
-	pattern *regexp.Regexp
-	methods map[string]bool
-	faults  []FaultConfig
-	prob    float64
+	protocol string
+	pattern  *regexp.Regexp
+	methods  map[string]bool
+	faults   []FaultConfig
+	prob     float64
+	match    *compiledMatch
 }
 
 // NewInjector creates a chaos injector from configuration
@@ -41,11 +49,17 @@ func NewInjector(config *ChaosConfig) (*Injector, error) {
 	// Clamp probability/rate values to [0.0, 1.0]
 	config.Clamp()
 
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
 	i := &Injector{
 		config:          config,
 		rng:             rng,
+		seed:            seed,
 		stats:           NewChaosStats(),
+		faults:          newFaultLog(config.Replay),
 		circuitBreakers: make(map[string]*CircuitBreaker),
 		retryTrackers:   make(map[string]*RetryAfterTracker),
 		progressives:    make(map[string]*ProgressiveDegradation),
@@ -58,6 +72,9 @@ func NewInjector(config *ChaosConfig) (*Injector, error) {
 			return nil, fmt.Errorf("failed to compile rule for pattern %q: %w", rule.PathPattern, err)
 		}
 		i.rules = append(i.rules, compiled)
+		if compiled.match != nil && compiled.match.needsMock() {
+			i.needsMockTarget = true
+		}
 
 		// Create stateful fault managers for this rule
 		for faultIdx, fault := range rule.Faults {
@@ -80,11 +97,25 @@ func NewInjector(config *ChaosConfig) (*Injector, error) {
 }
 
 func compileRule(rule ChaosRule) (*compiledRule, error) {
+	protocol := rule.Protocol
+	switch protocol {
+	case "":
+		protocol = ProtocolHTTP
+	case ProtocolHTTP, ProtocolGRPC, ProtocolWebSocket, ProtocolSSE, ProtocolMQTT:
+	default:
+		return nil, fmt.Errorf("unknown protocol %q", rule.Protocol)
+	}
+
 	pattern, err := regexp.Compile(rule.PathPattern)
 	if err != nil {
 		return nil, err
 	}
 
+	match, err := compileMatch(rule.Match)
+	if err != nil {
+		return nil, fmt.Errorf("match: %w", err)
+	}
+
 	methods := make(map[string]bool)
 	for _, m := range rule.Methods {
 		methods[m] = true
@@ -97,10 +128,12 @@ func compileRule(rule ChaosRule) (*compiledRule, error) {
 	// prob == 0 means "disabled" — rule matches but never fires
 
 	return &compiledRule{
-		pattern: pattern,
-		methods: methods,
-		faults:  rule.Faults,
-		prob:    prob,
+		protocol: protocol,
+		pattern:  pattern,
+		methods:  methods,
+		faults:   rule.Faults,
+		prob:     prob,
+		match:    match,
 	}, nil
 }
 
@@ -109,11 +142,37 @@ func (i *Injector) IsEnabled() bool {
 	return i.config != nil && i.config.Enabled
 }
 
+// NeedsMockTarget reports whether a rule matches on the mock a request is
+// served by. Callers then attach the request's MockTarget with
+// WithMockTarget before calling ShouldInject.
+func (i *Injector) NeedsMockTarget() bool {
+	return i != nil && i.needsMockTarget
+}
+
 // ShouldInject determines if chaos should be injected for a request
 // Returns the list of faults to apply
 func (i *Injector) ShouldInject(r *http.Request) []FaultConfig {
+	faults, _ := i.shouldInject(ProtocolHTTP, r.URL.Path, r.Method, r)
+	return faults
+}
+
+// ShouldInjectGRPC determines if chaos should be injected for a gRPC call.
+// fullMethod is the gRPC method path ("/package.Service/Method"); rules with
+// protocol "grpc" match their pattern against it without the leading slash.
+// Global latency and error rules apply too; the bandwidth rule does not.
+func (i *Injector) ShouldInjectGRPC(fullMethod string) []FaultConfig {
+	faults, _ := i.shouldInject(ProtocolGRPC, strings.TrimPrefix(fullMethod, "/"), "", nil)
+	return faults
+}
+
+// shouldInject selects the faults for one request or call of the given
+// protocol and records them in the fault log, returning the record when any
+// fault was selected. method and r are the HTTP method and request, empty
+// for other protocols. When replaying, the recorded faults are returned
+// instead of rolling the rules.
+func (i *Injector) shouldInject(protocol, target, method string, r *http.Request) ([]FaultConfig, *FaultRecord) {
 	if !i.IsEnabled() {
-		return nil
+		return nil, nil
 	}
 
 	i.mu.Lock()
@@ -121,17 +180,64 @@ func (i *Injector) ShouldInject(r *http.Request) []FaultConfig {
 
 	i.stats.TotalRequests++
 
-	var faults []FaultConfig
+	key := requestKey(protocol, method, target)
+	occurrence := i.faults.next(key)
+
+	var injected []InjectedFault
+	if i.faults.replaying() {
+		injected = i.faults.replay[key][occurrence]
+	} else {
+		injected = i.rollRules(protocol, target, method, r)
+	}
+	if len(injected) == 0 {
+		return nil, nil
+	}
+
+	faults := make([]FaultConfig, len(injected))
+	for idx, f := range injected {
+		faults[idx] = f.Fault
+		i.stats.InjectedFaults++
+		i.stats.FaultsByType[f.Fault.Type]++
+		if f.Rule == globalRule {
+			switch f.Fault.Type { //nolint:exhaustive // only global latency and errors have counters
+			case FaultLatency:
+				i.stats.LatencyInjected++
+			case FaultError:
+				i.stats.ErrorsInjected++
+			}
+		}
+	}
+
+	rec := &FaultRecord{
+		Time:       time.Now(),
+		Protocol:   protocol,
+		Method:     method,
+		Target:     target,
+		Occurrence: occurrence,
+		Faults:     injected,
+	}
+	i.faults.add(rec)
+	return faults, rec
+}
+
+// rollRules draws the rules and faults that apply to a request.
+func (i *Injector) rollRules(protocol, target, method string, r *http.Request) []InjectedFault {
+	var faults []InjectedFault
 	pathRuleMatched := false
 
 	// Check path-specific rules first
 	for ruleIdx, rule := range i.rules {
-		if !rule.pattern.MatchString(r.URL.Path) {
+		if rule.protocol != protocol || !rule.pattern.MatchString(target) {
 			continue
 		}
 
 		// Check method filter
-		if len(rule.methods) > 0 && !rule.methods[r.Method] {
+		if method != "" && len(rule.methods) > 0 && !rule.methods[method] {
+			continue
+		}
+
+		// Check caller and mock conditions
+		if rule.match != nil && !rule.match.matches(r) {
 			continue
 		}
 
@@ -141,30 +247,28 @@ func (i *Injector) ShouldInject(r *http.Request) []FaultConfig {
 		pathRuleMatched = true
 
 		// Check rule probability
-		if i.rng.Float64() > rule.prob {
+		ruleDraw := i.rng.Float64()
+		if ruleDraw > rule.prob {
 			continue
 		}
 
 		// Check each fault's probability
 		for faultIdx, fault := range rule.faults {
-			// Stateful faults always pass through — the state machine decides
+			// Stateful faults always pass through — the state machine decides.
+			// Their state machines answer HTTP requests only.
 			if isStatefulFault(fault.Type) {
-				key := statefulFaultKey(ruleIdx, faultIdx)
-				faultCopy := fault
-				if faultCopy.Config == nil {
-					faultCopy.Config = make(map[string]interface{})
+				if protocol != ProtocolHTTP {
+					continue
 				}
-				faultCopy.Config["_stateKey"] = key
-				faults = append(faults, faultCopy)
-				i.stats.InjectedFaults++
-				i.stats.FaultsByType[fault.Type]++
+				faultCopy := fault
+				faultCopy.Config = cloneFaultConfig(fault.Config)
+				faultCopy.Config["_stateKey"] = statefulFaultKey(ruleIdx, faultIdx)
+				faults = append(faults, InjectedFault{Rule: ruleIdx, RuleDraw: ruleDraw, Fault: faultCopy})
 				continue
 			}
 
-			if i.rng.Float64() <= fault.Probability {
-				faults = append(faults, fault)
-				i.stats.InjectedFaults++
-				i.stats.FaultsByType[fault.Type]++
+			if draw := i.rng.Float64(); draw <= fault.Probability {
+				faults = append(faults, InjectedFault{Rule: ruleIdx, RuleDraw: ruleDraw, Draw: draw, Fault: fault})
 			}
 		}
 	}
@@ -173,59 +277,66 @@ func (i *Injector) ShouldInject(r *http.Request) []FaultConfig {
 	// If a per-path rule matched but its probability roll failed,
 	// we intentionally skip global rules — the per-path rule takes precedence.
 	if !pathRuleMatched && i.config.GlobalRules != nil {
-		faults = i.applyGlobalRules()
+		faults = i.applyGlobalRules(protocol)
 	}
 
 	return faults
 }
 
-func (i *Injector) applyGlobalRules() []FaultConfig {
-	var faults []FaultConfig
+func (i *Injector) applyGlobalRules(protocol string) []InjectedFault {
+	var faults []InjectedFault
 	global := i.config.GlobalRules
 
-	if global.Latency != nil && i.rng.Float64() <= global.Latency.Probability {
-		faults = append(faults, FaultConfig{
-			Type:        FaultLatency,
-			Probability: global.Latency.Probability,
-			Config: map[string]interface{}{
-				"min": global.Latency.Min,
-				"max": global.Latency.Max,
-			},
-		})
-		i.stats.InjectedFaults++
-		i.stats.FaultsByType[FaultLatency]++
-		i.stats.LatencyInjected++
-	}
-
-	if global.ErrorRate != nil && i.rng.Float64() <= global.ErrorRate.Probability {
-		faults = append(faults, FaultConfig{
-			Type:        FaultError,
-			Probability: global.ErrorRate.Probability,
-			Config: map[string]interface{}{
-				"statusCodes": global.ErrorRate.StatusCodes,
-				"defaultCode": global.ErrorRate.DefaultCode,
-			},
-		})
-		i.stats.InjectedFaults++
-		i.stats.FaultsByType[FaultError]++
-		i.stats.ErrorsInjected++
-	}
-
-	if global.Bandwidth != nil && i.rng.Float64() <= global.Bandwidth.Probability {
-		faults = append(faults, FaultConfig{
-			Type:        FaultSlowBody,
-			Probability: global.Bandwidth.Probability,
-			Config: map[string]interface{}{
-				"bytesPerSecond": global.Bandwidth.BytesPerSecond,
-			},
-		})
-		i.stats.InjectedFaults++
-		i.stats.FaultsByType[FaultSlowBody]++
+	if global.Latency != nil {
+		if draw := i.rng.Float64(); draw <= global.Latency.Probability {
+			faults = append(faults, InjectedFault{Rule: globalRule, Draw: draw, Fault: FaultConfig{
+				Type:        FaultLatency,
+				Probability: global.Latency.Probability,
+				Config: map[string]interface{}{
+					"min": global.Latency.Min,
+					"max": global.Latency.Max,
+				},
+			}})
+		}
+	}
+
+	if global.ErrorRate != nil {
+		if draw := i.rng.Float64(); draw <= global.ErrorRate.Probability {
+			faults = append(faults, InjectedFault{Rule: globalRule, Draw: draw, Fault: FaultConfig{
+				Type:        FaultError,
+				Probability: global.ErrorRate.Probability,
+				Config: map[string]interface{}{
+					"statusCodes": global.ErrorRate.StatusCodes,
+					"defaultCode": global.ErrorRate.DefaultCode,
+				},
+			}})
+		}
+	}
+
+	if global.Bandwidth != nil && protocol == ProtocolHTTP {
+		if draw := i.rng.Float64(); draw <= global.Bandwidth.Probability {
+			faults = append(faults, InjectedFault{Rule: globalRule, Draw: draw, Fault: FaultConfig{
+				Type:        FaultSlowBody,
+				Probability: global.Bandwidth.Probability,
+				Config: map[string]interface{}{
+					"bytesPerSecond": global.Bandwidth.BytesPerSecond,
+				},
+			}})
+		}
 	}
 
 	return faults
 }
 
+// cloneFaultConfig returns a shallow copy of a fault's config.
+func cloneFaultConfig(config map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(config)+1)
+	for k, v := range config {
+		out[k] = v
+	}
+	return out
+}
+
 // InjectLatency injects latency fault by sleeping for a random duration
 func (i *Injector) InjectLatency(ctx context.Context, fault *LatencyFault) error {
 	if fault == nil {
@@ -334,6 +445,13 @@ func (i *Injector) InjectError(w http.ResponseWriter, fault *ErrorRateFault) {
 		return
 	}
 
+	statusCode := i.pickStatusCode(fault)
+	http.Error(w, http.StatusText(statusCode), statusCode)
+}
+
+// pickStatusCode returns a random entry of fault.StatusCodes, falling back to
+// DefaultCode and then 500.
+func (i *Injector) pickStatusCode(fault *ErrorRateFault) int {
 	statusCode := fault.DefaultCode
 	if statusCode == 0 {
 		statusCode = http.StatusInternalServerError
@@ -345,8 +463,16 @@ func (i *Injector) InjectError(w http.ResponseWriter, fault *ErrorRateFault) {
 		statusCode = fault.StatusCodes[i.rng.Intn(len(fault.StatusCodes))]
 		i.mu.Unlock()
 	}
+	return statusCode
+}
 
-	http.Error(w, http.StatusText(statusCode), statusCode)
+// StatusCodeFromConfig returns the HTTP status code an error fault with the
+// given config would respond with. Other protocols map it to their own codes.
+func (i *Injector) StatusCodeFromConfig(config map[string]interface{}) int {
+	return i.pickStatusCode(&ErrorRateFault{
+		DefaultCode: getIntOrDefault(config, "defaultCode", http.StatusInternalServerError),
+		StatusCodes: getIntSlice(config, "statusCodes"),
+	})
 }
 
 // InjectErrorFromConfig injects an error from a FaultConfig
@@ -412,6 +538,9 @@ func (i *Injector) GetStats() ChaosStats {
 		ErrorsInjected:   i.stats.ErrorsInjected,
 		TimeoutsInjected: i.stats.TimeoutsInjected,
 		FaultsByType:     make(map[FaultType]int64),
+
+		MessagesProcessed: i.stats.MessagesProcessed,
+		MessagesDropped:   i.stats.MessagesDropped,
 	}
 	for k, v := range i.stats.FaultsByType {
 		statsCopy.FaultsByType[k] = v
@@ -532,6 +661,11 @@ func (i *Injector) UpdateConfig(config *ChaosConfig) error {
 	i.mu.Lock()
 	defer i.mu.Unlock()
 
+	if config.Seed != 0 {
+		i.seed = config.Seed
+		i.rng.Seed(config.Seed)
+	}
+	i.faults = newFaultLog(config.Replay)
 	i.config = config
 	i.rules = newRules
 	i.circuitBreakers = newCBs
@@ -1369,3 +1369,48 @@ func TestInjector_PerPathRulePartialProbability_NoGlobalFallback(t *testing.T) {
 		}
 	}
 }
+
+func TestInjector_ShouldInjectGRPC(t *testing.T) {
+	inj, err := NewInjector(&ChaosConfig{
+		Enabled: true,
+		Rules: []ChaosRule{
+			{PathPattern: ".*", Faults: []FaultConfig{{Type: FaultError, Probability: 1.0}}, Probability: 1.0},
+			{Protocol: ProtocolGRPC, PathPattern: "^users.UserService/GetUser$", Methods: []string{"POST"},
+				Faults: []FaultConfig{{Type: FaultGRPCStatus, Probability: 1.0}, {Type: FaultRetryAfter, Probability: 1.0}}, Probability: 1.0},
+		},
+		GlobalRules: &GlobalChaosRules{
+			Latency:   &LatencyFault{Min: "1ms", Max: "1ms", Probability: 1.0},
+			Bandwidth: &BandwidthFault{BytesPerSecond: 10, Probability: 1.0},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewInjector() error = %v", err)
+	}
+
+	// gRPC rules ignore HTTP methods and stateful (HTTP-only) faults.
+	faults := inj.ShouldInjectGRPC("/users.UserService/GetUser")
+	if len(faults) != 1 || faults[0].Type != FaultGRPCStatus {
+		t.Errorf("expected only the grpc_status fault, got %+v", faults)
+	}
+
+	// Unmatched calls fall back to global rules, without bandwidth limiting.
+	faults = inj.ShouldInjectGRPC("/users.UserService/ListUsers")
+	if len(faults) != 1 || faults[0].Type != FaultLatency {
+		t.Errorf("expected only the global latency fault, got %+v", faults)
+	}
+
+	// gRPC rules never apply to HTTP requests.
+	for _, f := range inj.ShouldInject(httptest.NewRequest("POST", "/users.UserService/GetUser", nil)) {
+		if f.Type == FaultGRPCStatus {
+			t.Error("gRPC rule applied to an HTTP request")
+		}
+	}
+
+	if got := inj.GetStats().TotalRequests; got != 3 {
+		t.Errorf("expected gRPC calls to share stats, got %d requests", got)
+	}
+
+	if _, err := NewInjector(&ChaosConfig{Rules: []ChaosRule{{Protocol: "smtp", PathPattern: ".*"}}}); err == nil {
+		t.Error("expected an error for an unknown protocol")
+	}
+}
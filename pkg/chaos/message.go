@@ -0,0 +1,279 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CloseError is returned by MessageChaos.Send when a message_close fault
+// fires. The caller closes the stream with the given code and reason; a zero
+// Code means the protocol's default.
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	return fmt.Sprintf("chaos: stream closed (code %d): %s", e.Code, e.Reason)
+}
+
+// MessageChaos injects message-level faults into one message stream: a
+// WebSocket connection, an SSE stream or an MQTT topic. Every outgoing
+// message is routed through Send. A nil *MessageChaos delivers messages
+// unchanged, so callers need not check whether chaos is active.
+type MessageChaos struct {
+	injector *Injector
+	faults   []FaultConfig
+
+	mu           sync.Mutex
+	delivered    int
+	held         []heldMessage
+	throttleNext time.Time
+	closeErr     *CloseError
+}
+
+// heldMessage is a message held back by a message_reorder fault until
+// remaining more messages have been delivered.
+type heldMessage struct {
+	deliver   func() error
+	remaining int
+}
+
+// NewMessageChaos returns the message chaos for a stream of the given
+// protocol ("websocket", "sse" or "mqtt"), or nil when no rule applies.
+// target is the WebSocket or SSE path, or the MQTT topic. Each matching
+// rule's probability is rolled once for the whole stream; fault
+// probabilities are rolled for every message. Global rules do not apply to
+// message streams.
+func (i *Injector) NewMessageChaos(protocol, target string) *MessageChaos {
+	if i == nil || !i.IsEnabled() {
+		return nil
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	var faults []FaultConfig
+	for _, rule := range i.rules {
+		if rule.protocol != protocol || !rule.pattern.MatchString(target) || !rule.match.matches(nil) {
+			continue
+		}
+		if i.rng.Float64() > rule.prob {
+			continue
+		}
+		for _, fault := range rule.faults {
+			if isMessageFault(fault.Type) {
+				faults = append(faults, fault)
+			}
+		}
+	}
+	if len(faults) == 0 {
+		return nil
+	}
+	return &MessageChaos{injector: i, faults: faults}
+}
+
+// isMessageFault reports whether a fault type acts on individual messages.
+func isMessageFault(t FaultType) bool {
+	switch t { //nolint:exhaustive // only message faults are listed
+	case FaultMessageDrop, FaultMessageDuplicate, FaultMessageReorder,
+		FaultMessageDelay, FaultMessageClose, FaultMessageThrottle:
+		return true
+	}
+	return false
+}
+
+// messageDecision is the outcome of rolling a stream's faults for one message.
+type messageDecision struct {
+	close     *CloseError
+	drop      bool
+	duplicate bool
+	holdFor   int
+	delay     time.Duration
+	throttle  *FaultConfig
+}
+
+// Send delivers one message of size bytes through deliver, applying the
+// stream's faults. Faults take effect in a fixed order: close, drop, delay
+// and throttle, then reorder and duplicate. A dropped or held-back message
+// returns nil without being delivered. Once a close fault has fired, Send
+// returns the same *CloseError for every later message.
+func (m *MessageChaos) Send(ctx context.Context, size int, deliver func() error) error {
+	if m == nil {
+		return deliver()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closeErr != nil {
+		return m.closeErr
+	}
+
+	d := m.decide()
+	switch {
+	case d.close != nil:
+		m.closeErr = d.close
+		return d.close
+	case d.drop:
+		return nil
+	}
+
+	if d.delay > 0 {
+		if err := sleepContext(ctx, d.delay); err != nil {
+			return err
+		}
+	}
+	if d.throttle != nil {
+		if err := m.throttle(ctx, d.throttle.Config, size); err != nil {
+			return err
+		}
+	}
+
+	if d.holdFor > 0 {
+		m.held = append(m.held, heldMessage{deliver: deliver, remaining: d.holdFor})
+		return nil
+	}
+
+	if err := deliver(); err != nil {
+		return err
+	}
+	if d.duplicate {
+		if err := deliver(); err != nil {
+			return err
+		}
+	}
+	m.delivered++
+	return m.release()
+}
+
+// Flush delivers every message still held back for reordering. Streams that
+// end normally call it so held messages are not lost.
+func (m *MessageChaos) Flush() error {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	held := m.held
+	m.held = nil
+	for _, h := range held {
+		if err := h.deliver(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decide rolls each fault for the next message and records the faults that
+// fire in the injector's stats.
+func (m *MessageChaos) decide() messageDecision {
+	i := m.injector
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.stats.MessagesProcessed++
+
+	var d messageDecision
+	for idx := range m.faults {
+		fault := &m.faults[idx]
+		if fault.Type == FaultMessageClose && m.delivered < getIntOrDefault(fault.Config, "afterMessages", 0) {
+			continue
+		}
+		if i.rng.Float64() > fault.Probability {
+			continue
+		}
+		i.stats.InjectedFaults++
+		i.stats.FaultsByType[fault.Type]++
+
+		switch fault.Type { //nolint:exhaustive // only message faults reach a MessageChaos
+		case FaultMessageClose:
+			d.close = &CloseError{
+				Code:   getIntOrDefault(fault.Config, "code", 0),
+				Reason: getStringOrDefault(fault.Config, "reason", "chaos"),
+			}
+		case FaultMessageDrop:
+			d.drop = true
+		case FaultMessageDuplicate:
+			d.duplicate = true
+		case FaultMessageReorder:
+			d.holdFor = getIntOrDefault(fault.Config, "window", 1)
+		case FaultMessageDelay:
+			minDur, _ := time.ParseDuration(getStringOrDefault(fault.Config, "min", "0ms"))
+			maxDur, _ := time.ParseDuration(getStringOrDefault(fault.Config, "max", "100ms"))
+			if minDur > maxDur {
+				minDur, maxDur = maxDur, minDur
+			}
+			d.delay = minDur
+			if maxDur > minDur {
+				d.delay += time.Duration(i.rng.Int63n(int64(maxDur - minDur)))
+			}
+		case FaultMessageThrottle:
+			d.throttle = fault
+		}
+	}
+	if d.drop && d.close == nil {
+		i.stats.MessagesDropped++
+	}
+	return d
+}
+
+// throttle waits until the stream may send another message of size bytes
+// under the fault's messagesPerSecond and bytesPerSecond limits.
+func (m *MessageChaos) throttle(ctx context.Context, config map[string]interface{}, size int) error {
+	var interval time.Duration
+	if mps := getFloat64OrDefault(config, "messagesPerSecond", 0); mps > 0 {
+		interval = time.Duration(float64(time.Second) / mps)
+	}
+	if bps := getFloat64OrDefault(config, "bytesPerSecond", 0); bps > 0 && size > 0 {
+		if d := time.Duration(float64(size) / bps * float64(time.Second)); d > interval {
+			interval = d
+		}
+	}
+
+	now := time.Now()
+	if m.throttleNext.After(now) {
+		if err := sleepContext(ctx, m.throttleNext.Sub(now)); err != nil {
+			return err
+		}
+		now = m.throttleNext
+	}
+	m.throttleNext = now.Add(interval)
+	return nil
+}
+
+// release delivers the held messages whose reorder window has passed.
+func (m *MessageChaos) release() error {
+	var due []heldMessage
+	kept := m.held[:0]
+	for _, h := range m.held {
+		h.remaining--
+		if h.remaining <= 0 {
+			due = append(due, h)
+		} else {
+			kept = append(kept, h)
+		}
+	}
+	m.held = kept
+	for _, h := range due {
+		if err := h.deliver(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sleepContext sleeps for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
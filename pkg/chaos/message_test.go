@@ -0,0 +1,187 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// newTestMessageChaos returns the message chaos for a websocket stream on
+// /ws with the given faults, all firing with probability 1.
+func newTestMessageChaos(t *testing.T, faults ...FaultConfig) (*Injector, *MessageChaos) {
+	t.Helper()
+	for i := range faults {
+		faults[i].Probability = 1
+	}
+	injector, err := NewInjector(&ChaosConfig{
+		Enabled: true,
+		Rules: []ChaosRule{{
+			Protocol:    ProtocolWebSocket,
+			PathPattern: "^/ws$",
+			Faults:      faults,
+			Probability: 1,
+		}},
+	})
+	if err != nil {
+		t.Fatalf("NewInjector() error = %v", err)
+	}
+	mc := injector.NewMessageChaos(ProtocolWebSocket, "/ws")
+	if mc == nil {
+		t.Fatal("NewMessageChaos() = nil, want message chaos for /ws")
+	}
+	return injector, mc
+}
+
+// sendAll sends messages 1..n through mc and returns what was delivered.
+func sendAll(t *testing.T, mc *MessageChaos, n int) ([]int, error) {
+	t.Helper()
+	var got []int
+	for i := 1; i <= n; i++ {
+		msg := i
+		if err := mc.Send(context.Background(), 10, func() error {
+			got = append(got, msg)
+			return nil
+		}); err != nil {
+			return got, err
+		}
+	}
+	return got, nil
+}
+
+func TestNewMessageChaos_Matching(t *testing.T) {
+	injector, err := NewInjector(&ChaosConfig{
+		Enabled: true,
+		Rules: []ChaosRule{
+			{Protocol: ProtocolMQTT, PathPattern: "^sensors/", Faults: []FaultConfig{{Type: FaultMessageDrop, Probability: 1}}, Probability: 1},
+			{PathPattern: ".*", Faults: []FaultConfig{{Type: FaultMessageDrop, Probability: 1}}, Probability: 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewInjector() error = %v", err)
+	}
+
+	if injector.NewMessageChaos(ProtocolMQTT, "sensors/temp") == nil {
+		t.Error("mqtt rule should match sensors/temp")
+	}
+	if injector.NewMessageChaos(ProtocolMQTT, "alerts/fire") != nil {
+		t.Error("mqtt rule should not match alerts/fire")
+	}
+	if injector.NewMessageChaos(ProtocolSSE, "/events") != nil {
+		t.Error("http rules should not apply to sse streams")
+	}
+
+	var nilInjector *Injector
+	if nilInjector.NewMessageChaos(ProtocolSSE, "/events") != nil {
+		t.Error("nil injector should return nil")
+	}
+
+	var nilChaos *MessageChaos
+	delivered := false
+	if err := nilChaos.Send(context.Background(), 1, func() error { delivered = true; return nil }); err != nil || !delivered {
+		t.Errorf("nil MessageChaos should deliver unchanged, delivered=%v err=%v", delivered, err)
+	}
+}
+
+func TestMessageChaos_Drop(t *testing.T) {
+	injector, mc := newTestMessageChaos(t, FaultConfig{Type: FaultMessageDrop})
+
+	got, err := sendAll(t, mc, 5)
+	if err != nil || len(got) != 0 {
+		t.Fatalf("delivered %v, err %v; want nothing", got, err)
+	}
+
+	stats := injector.GetStats()
+	if stats.MessagesProcessed != 5 || stats.MessagesDropped != 5 {
+		t.Errorf("stats processed=%d dropped=%d, want 5 and 5", stats.MessagesProcessed, stats.MessagesDropped)
+	}
+	if stats.FaultsByType[FaultMessageDrop] != 5 {
+		t.Errorf("FaultsByType[message_drop] = %d, want 5", stats.FaultsByType[FaultMessageDrop])
+	}
+}
+
+func TestMessageChaos_Duplicate(t *testing.T) {
+	_, mc := newTestMessageChaos(t, FaultConfig{Type: FaultMessageDuplicate})
+
+	got, _ := sendAll(t, mc, 2)
+	if want := []int{1, 1, 2, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("delivered %v, want %v", got, want)
+	}
+}
+
+func TestMessageChaos_Reorder(t *testing.T) {
+	// The first message is held back until two later messages are out.
+	_, mc := newTestMessageChaos(t, FaultConfig{Type: FaultMessageReorder, Config: map[string]interface{}{"window": 2}})
+	var got []int
+	send := func(msg int) {
+		_ = mc.Send(context.Background(), 1, func() error { got = append(got, msg); return nil })
+	}
+	send(1)
+	mc.faults[0].Probability = 0
+	send(2)
+	send(3)
+	send(4)
+	if want := []int{2, 3, 1, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("delivered %v, want %v", got, want)
+	}
+
+	mc.faults[0].Probability = 1
+	send(5)
+	if err := mc.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if want := []int{2, 3, 1, 4, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after Flush delivered %v, want %v", got, want)
+	}
+}
+
+func TestMessageChaos_CloseAfterMessages(t *testing.T) {
+	_, mc := newTestMessageChaos(t, FaultConfig{
+		Type:   FaultMessageClose,
+		Config: map[string]interface{}{"code": 4000, "reason": "chaos close", "afterMessages": 2},
+	})
+
+	got, err := sendAll(t, mc, 5)
+	if want := []int{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("delivered %v, want %v", got, want)
+	}
+	var closeErr *CloseError
+	if !errors.As(err, &closeErr) {
+		t.Fatalf("error = %v, want *CloseError", err)
+	}
+	if closeErr.Code != 4000 || closeErr.Reason != "chaos close" {
+		t.Errorf("CloseError = %+v", closeErr)
+	}
+	if err := mc.Send(context.Background(), 1, func() error { return nil }); !errors.As(err, &closeErr) {
+		t.Errorf("Send after close = %v, want *CloseError", err)
+	}
+}
+
+func TestMessageChaos_DelayAndThrottle(t *testing.T) {
+	_, mc := newTestMessageChaos(t, FaultConfig{Type: FaultMessageDelay, Config: map[string]interface{}{"min": "20ms", "max": "20ms"}})
+	start := time.Now()
+	if _, err := sendAll(t, mc, 2); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("2 delayed messages took %v, want >= 40ms", elapsed)
+	}
+
+	_, mc = newTestMessageChaos(t, FaultConfig{Type: FaultMessageThrottle, Config: map[string]interface{}{"messagesPerSecond": 50}})
+	start = time.Now()
+	if _, err := sendAll(t, mc, 4); err != nil {
+		t.Fatal(err)
+	}
+	// The first message goes out immediately, the next three 20ms apart.
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("4 throttled messages took %v, want >= 60ms", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, mc = newTestMessageChaos(t, FaultConfig{Type: FaultMessageDelay, Config: map[string]interface{}{"min": "1s", "max": "1s"}})
+	if err := mc.Send(ctx, 1, func() error { return nil }); !errors.Is(err, context.Canceled) {
+		t.Errorf("Send with cancelled context = %v, want context.Canceled", err)
+	}
+}
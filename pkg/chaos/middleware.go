@@ -1,7 +1,9 @@
 package chaos
 
 import (
+	"bufio"
 	"context"
+	"net"
 	"net/http"
 	"time"
 )
@@ -10,6 +12,7 @@ import (
 type Middleware struct {
 	handler  http.Handler
 	injector *Injector
+	onAbort  func(r *http.Request, status int)
 }
 
 // NewMiddleware creates a new chaos middleware
@@ -20,6 +23,13 @@ func NewMiddleware(handler http.Handler, injector *Injector) *Middleware {
 	}
 }
 
+// OnAbort sets a function called after a fault answers a request itself, so
+// the handler never sees it. status is the status code written, or 0 when the
+// connection was dropped. r carries the request's ChaosContext.
+func (m *Middleware) OnAbort(fn func(r *http.Request, status int)) {
+	m.onAbort = fn
+}
+
 // faultResult indicates how the middleware should proceed after processing a fault.
 type faultResult int
 
@@ -37,20 +47,35 @@ func (m *Middleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Determine which faults to inject
-	faults := m.injector.ShouldInject(r)
+	faults, rec := m.injector.shouldInject(ProtocolHTTP, r.URL.Path, r.Method, r)
 	if len(faults) == 0 {
 		m.handler.ServeHTTP(w, r)
 		return
 	}
+	r = r.WithContext(WithChaosContext(r.Context(), &ChaosContext{
+		Faults:   faults,
+		Injected: true,
+		Seq:      rec.Seq,
+		injector: m.injector,
+	}))
 
 	// Process faults
 	ctx := r.Context()
 	responseWriter := w
+	origWriter := w
+	var sw *statusWriter
+	if m.onAbort != nil {
+		sw = &statusWriter{ResponseWriter: w}
+		origWriter = sw
+	}
 
 	for _, fault := range faults {
 		var result faultResult
-		result, responseWriter = m.processFault(fault, ctx, w, responseWriter)
+		result, responseWriter = m.processFault(fault, ctx, origWriter, responseWriter)
 		if result == faultAbort {
+			if sw != nil {
+				m.onAbort(r, sw.status)
+			}
 			return
 		}
 	}
@@ -193,6 +218,59 @@ const (
 type ChaosContext struct {
 	Faults   []FaultConfig
 	Injected bool
+	// Seq is the sequence number of the request's fault log record.
+	Seq int64
+
+	injector *Injector
+}
+
+// LinkRequest links the request's fault log record to its request log entry.
+func (c *ChaosContext) LinkRequest(requestID string) {
+	if c != nil && c.injector != nil && requestID != "" {
+		c.injector.LinkRequest(c.Seq, requestID)
+	}
+}
+
+// FaultTypes returns the types of the faults injected into the request.
+func (c *ChaosContext) FaultTypes() []string {
+	types := make([]string, len(c.Faults))
+	for idx, f := range c.Faults {
+		types[idx] = string(f.Type)
+	}
+	return types
+}
+
+// statusWriter records the status code written by a fault that answers the
+// request itself.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	return hijacker.Hijack()
+}
+
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
 }
 
 // WithChaosContext adds chaos context to the request
@@ -257,6 +257,7 @@ func deepCopyChaosConfig(src *ChaosConfig) *ChaosConfig {
 		dst.Rules = make([]ChaosRule, len(src.Rules))
 		for i, rule := range src.Rules {
 			dst.Rules[i] = ChaosRule{
+				Protocol:    rule.Protocol,
 				PathPattern: rule.PathPattern,
 				Probability: rule.Probability,
 			}
@@ -0,0 +1,312 @@
+package chaos
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RuleMatch narrows a rule to particular callers and mocks. Every condition
+// that is set must hold; the entries of one list are alternatives. Regexes
+// are unanchored, like PathPattern. Conditions apply to HTTP requests only:
+// a rule with a match never applies to gRPC calls or message streams.
+type RuleMatch struct {
+	// Headers maps header names to regexes one of the header's values must match.
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	// Query maps query parameters to regexes one of the parameter's values must match.
+	Query map[string]string `json:"query,omitempty" yaml:"query,omitempty"`
+	// ClientIPs lists IP addresses and CIDR ranges the connection's remote
+	// address must be in. X-Forwarded-For is not consulted.
+	ClientIPs []string `json:"clientIPs,omitempty" yaml:"clientIPs,omitempty"`
+	// ClientCN is a regex the Common Name of the mTLS client certificate must match.
+	ClientCN string `json:"clientCN,omitempty" yaml:"clientCN,omitempty"`
+	// JWTClaims maps claims of the bearer token to regexes. The token's
+	// signature is not verified.
+	JWTClaims map[string]string `json:"jwtClaims,omitempty" yaml:"jwtClaims,omitempty"`
+
+	// MockIDs lists the IDs of the mocks the request must match.
+	MockIDs []string `json:"mockIds,omitempty" yaml:"mockIds,omitempty"`
+	// MockName is a regex the name of the matched mock must match.
+	MockName string `json:"mockName,omitempty" yaml:"mockName,omitempty"`
+	// Tags lists mock tags; the matched mock must carry at least one.
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Workspaces lists the workspace IDs the matched mock must belong to.
+	// "default" names the default workspace.
+	Workspaces []string `json:"workspaces,omitempty" yaml:"workspaces,omitempty"`
+}
+
+// MockTarget identifies the mock a request will be served by. The engine
+// resolves it before chaos runs when a rule matches on mocks, and passes it
+// in the request context with WithMockTarget.
+type MockTarget struct {
+	ID        string
+	Name      string
+	Workspace string
+	Tags      []string
+}
+
+type mockTargetKey struct{}
+
+// WithMockTarget returns a copy of ctx carrying target.
+func WithMockTarget(ctx context.Context, target *MockTarget) context.Context {
+	return context.WithValue(ctx, mockTargetKey{}, target)
+}
+
+// MockTargetFromContext returns the mock target stored in ctx, or nil.
+func MockTargetFromContext(ctx context.Context) *MockTarget {
+	target, _ := ctx.Value(mockTargetKey{}).(*MockTarget)
+	return target
+}
+
+// compiledMatch is a RuleMatch ready for matching.
+type compiledMatch struct {
+	headers    map[string]*regexp.Regexp
+	query      map[string]*regexp.Regexp
+	networks   []*net.IPNet
+	clientCN   *regexp.Regexp
+	claims     map[string]*regexp.Regexp
+	mockIDs    map[string]bool
+	mockName   *regexp.Regexp
+	tags       map[string]bool
+	workspaces map[string]bool
+}
+
+// compileMatch compiles m, returning nil when m sets no condition.
+func compileMatch(m *RuleMatch) (*compiledMatch, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	c := &compiledMatch{}
+	var err error
+	if c.headers, err = compilePatterns("headers", m.Headers, http.CanonicalHeaderKey); err != nil {
+		return nil, err
+	}
+	if c.query, err = compilePatterns("query", m.Query, nil); err != nil {
+		return nil, err
+	}
+	if c.claims, err = compilePatterns("jwtClaims", m.JWTClaims, nil); err != nil {
+		return nil, err
+	}
+	for _, s := range m.ClientIPs {
+		network, err := parseNetwork(s)
+		if err != nil {
+			return nil, err
+		}
+		c.networks = append(c.networks, network)
+	}
+	if m.ClientCN != "" {
+		if c.clientCN, err = regexp.Compile(m.ClientCN); err != nil {
+			return nil, fmt.Errorf("clientCN: %w", err)
+		}
+	}
+	if m.MockName != "" {
+		if c.mockName, err = regexp.Compile(m.MockName); err != nil {
+			return nil, fmt.Errorf("mockName: %w", err)
+		}
+	}
+	c.mockIDs = stringSet(m.MockIDs)
+	c.tags = stringSet(m.Tags)
+	c.workspaces = stringSet(m.Workspaces)
+	if c.workspaces["default"] {
+		c.workspaces[""] = true
+	}
+
+	if len(c.headers) == 0 && len(c.query) == 0 && len(c.claims) == 0 && len(c.networks) == 0 &&
+		c.clientCN == nil && !c.needsMock() {
+		return nil, nil
+	}
+	return c, nil
+}
+
+// compilePatterns compiles a map of regexes, normalizing keys with key if set.
+func compilePatterns(field string, patterns map[string]string, key func(string) string) (map[string]*regexp.Regexp, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]*regexp.Regexp, len(patterns))
+	for name, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%s[%s]: %w", field, name, err)
+		}
+		if key != nil {
+			name = key(name)
+		}
+		out[name] = re
+	}
+	return out, nil
+}
+
+// parseNetwork parses a CIDR range or a single IP address.
+func parseNetwork(s string) (*net.IPNet, error) {
+	if strings.Contains(s, "/") {
+		_, network, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("clientIPs: %w", err)
+		}
+		return network, nil
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("clientIPs: invalid IP address %q", s)
+	}
+	bits := 128
+	if ip4 := ip.To4(); ip4 != nil {
+		ip, bits = ip4, 32
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
+
+func stringSet(values []string) map[string]bool {
+	if len(values) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+// needsMock reports whether matching needs the request's mock target.
+func (c *compiledMatch) needsMock() bool {
+	return len(c.mockIDs) > 0 || c.mockName != nil || len(c.tags) > 0 || len(c.workspaces) > 0
+}
+
+// matches reports whether r meets every condition. r is nil for traffic
+// other than HTTP requests, which never matches.
+func (c *compiledMatch) matches(r *http.Request) bool {
+	if c == nil {
+		return true
+	}
+	if r == nil {
+		return false
+	}
+
+	for name, re := range c.headers {
+		if !anyMatch(re, r.Header.Values(name)) {
+			return false
+		}
+	}
+	if len(c.query) > 0 {
+		query := r.URL.Query()
+		for name, re := range c.query {
+			if !anyMatch(re, query[name]) {
+				return false
+			}
+		}
+	}
+	if len(c.networks) > 0 && !c.clientIPMatches(r.RemoteAddr) {
+		return false
+	}
+	if c.clientCN != nil {
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 ||
+			!c.clientCN.MatchString(r.TLS.PeerCertificates[0].Subject.CommonName) {
+			return false
+		}
+	}
+	if len(c.claims) > 0 {
+		claims := bearerClaims(r.Header.Get("Authorization"))
+		for name, re := range c.claims {
+			v, ok := claims[name]
+			if !ok || !re.MatchString(v) {
+				return false
+			}
+		}
+	}
+	if c.needsMock() {
+		return c.mockMatches(MockTargetFromContext(r.Context()))
+	}
+	return true
+}
+
+func (c *compiledMatch) clientIPMatches(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, network := range c.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *compiledMatch) mockMatches(target *MockTarget) bool {
+	if target == nil {
+		return false
+	}
+	if len(c.mockIDs) > 0 && !c.mockIDs[target.ID] {
+		return false
+	}
+	if c.mockName != nil && !c.mockName.MatchString(target.Name) {
+		return false
+	}
+	if len(c.workspaces) > 0 && !c.workspaces[target.Workspace] {
+		return false
+	}
+	if len(c.tags) > 0 {
+		for _, tag := range target.Tags {
+			if c.tags[tag] {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func anyMatch(re *regexp.Regexp, values []string) bool {
+	for _, v := range values {
+		if re.MatchString(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// bearerClaims returns the claims of a "Bearer <jwt>" Authorization value as
+// strings, or nil if the header is not a decodable JWT. Only string, number
+// and boolean claims are returned.
+func bearerClaims(authorization string) map[string]string {
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok {
+		return nil
+	}
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return nil
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil
+	}
+	claims := make(map[string]string, len(raw))
+	for name, v := range raw {
+		switch v := v.(type) {
+		case string:
+			claims[name] = v
+		case float64:
+			claims[name] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			claims[name] = strconv.FormatBool(v)
+		}
+	}
+	return claims
+}
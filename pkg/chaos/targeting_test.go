@@ -0,0 +1,206 @@
+package chaos
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// targetedInjector returns an injector with one always-firing error rule on
+// every path, narrowed by match.
+func targetedInjector(t *testing.T, match *RuleMatch) *Injector {
+	t.Helper()
+	injector, err := NewInjector(&ChaosConfig{
+		Enabled: true,
+		Rules: []ChaosRule{{
+			PathPattern: ".*",
+			Match:       match,
+			Faults:      []FaultConfig{{Type: FaultError, Probability: 1}},
+			Probability: 1,
+		}},
+	})
+	if err != nil {
+		t.Fatalf("NewInjector() error = %v", err)
+	}
+	return injector
+}
+
+func testJWT(payload string) string {
+	return "Bearer e30." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".sig"
+}
+
+func TestRuleMatch_Callers(t *testing.T) {
+	tests := []struct {
+		name  string
+		match *RuleMatch
+		setup func(r *http.Request)
+		want  bool
+	}{
+		{
+			name:  "header matches",
+			match: &RuleMatch{Headers: map[string]string{"x-client-id": "^checkout$"}},
+			setup: func(r *http.Request) { r.Header.Set("X-Client-Id", "checkout") },
+			want:  true,
+		},
+		{
+			name:  "header differs",
+			match: &RuleMatch{Headers: map[string]string{"X-Client-Id": "^checkout$"}},
+			setup: func(r *http.Request) { r.Header.Set("X-Client-Id", "billing") },
+		},
+		{
+			name:  "header missing",
+			match: &RuleMatch{Headers: map[string]string{"X-Client-Id": ".*"}},
+		},
+		{
+			name:  "query matches",
+			match: &RuleMatch{Query: map[string]string{"tenant": "^acme$"}},
+			setup: func(r *http.Request) { r.URL.RawQuery = "tenant=acme" },
+			want:  true,
+		},
+		{
+			name:  "client ip in cidr",
+			match: &RuleMatch{ClientIPs: []string{"10.1.0.0/16"}},
+			setup: func(r *http.Request) { r.RemoteAddr = "10.1.2.3:5555" },
+			want:  true,
+		},
+		{
+			name:  "client ip exact",
+			match: &RuleMatch{ClientIPs: []string{"192.168.1.9", "::1"}},
+			setup: func(r *http.Request) { r.RemoteAddr = "[::1]:5555" },
+			want:  true,
+		},
+		{
+			name:  "client ip outside",
+			match: &RuleMatch{ClientIPs: []string{"10.1.0.0/16"}},
+			setup: func(r *http.Request) { r.RemoteAddr = "10.2.0.1:5555" },
+		},
+		{
+			name:  "mtls cn",
+			match: &RuleMatch{ClientCN: "^svc-orders$"},
+			setup: func(r *http.Request) {
+				r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "svc-orders"}}}}
+			},
+			want: true,
+		},
+		{
+			name:  "mtls cn without certificate",
+			match: &RuleMatch{ClientCN: ".*"},
+		},
+		{
+			name:  "jwt claim",
+			match: &RuleMatch{JWTClaims: map[string]string{"client_id": "^mobile-", "tier": "^2$"}},
+			setup: func(r *http.Request) {
+				r.Header.Set("Authorization", testJWT(`{"client_id":"mobile-ios","tier":2}`))
+			},
+			want: true,
+		},
+		{
+			name:  "jwt claim missing",
+			match: &RuleMatch{JWTClaims: map[string]string{"client_id": ".*"}},
+			setup: func(r *http.Request) { r.Header.Set("Authorization", testJWT(`{"sub":"42"}`)) },
+		},
+		{
+			name: "all conditions must hold",
+			match: &RuleMatch{
+				Headers:   map[string]string{"X-Client-Id": "checkout"},
+				ClientIPs: []string{"127.0.0.1"},
+			},
+			setup: func(r *http.Request) {
+				r.Header.Set("X-Client-Id", "checkout")
+				r.RemoteAddr = "10.0.0.1:1234"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			injector := targetedInjector(t, tt.match)
+			r := httptest.NewRequest("GET", "/api/orders", nil)
+			if tt.setup != nil {
+				tt.setup(r)
+			}
+			got := len(injector.ShouldInject(r)) > 0
+			if got != tt.want {
+				t.Errorf("fault injected = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleMatch_Mocks(t *testing.T) {
+	target := &MockTarget{ID: "mock-1", Name: "Get order", Workspace: "", Tags: []string{"orders", "beta"}}
+
+	tests := []struct {
+		name  string
+		match *RuleMatch
+		want  bool
+	}{
+		{"mock id", &RuleMatch{MockIDs: []string{"mock-2", "mock-1"}}, true},
+		{"other mock id", &RuleMatch{MockIDs: []string{"mock-2"}}, false},
+		{"mock name", &RuleMatch{MockName: "(?i)order"}, true},
+		{"tag", &RuleMatch{Tags: []string{"beta"}}, true},
+		{"missing tag", &RuleMatch{Tags: []string{"payments"}}, false},
+		{"default workspace", &RuleMatch{Workspaces: []string{"default"}}, true},
+		{"other workspace", &RuleMatch{Workspaces: []string{"ws-team-b"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			injector := targetedInjector(t, tt.match)
+			if !injector.NeedsMockTarget() {
+				t.Fatal("NeedsMockTarget() = false for a mock condition")
+			}
+			r := httptest.NewRequest("GET", "/api/orders/1", nil)
+			r = r.WithContext(WithMockTarget(r.Context(), target))
+			if got := len(injector.ShouldInject(r)) > 0; got != tt.want {
+				t.Errorf("fault injected = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Without a resolved mock, mock conditions never match.
+	injector := targetedInjector(t, &RuleMatch{Tags: []string{"orders"}})
+	if faults := injector.ShouldInject(httptest.NewRequest("GET", "/unmatched", nil)); len(faults) != 0 {
+		t.Errorf("ShouldInject() without a mock target = %v, want none", faults)
+	}
+	if targetedInjector(t, &RuleMatch{Headers: map[string]string{"X-Test": "1"}}).NeedsMockTarget() {
+		t.Error("NeedsMockTarget() = true for caller-only conditions")
+	}
+}
+
+func TestRuleMatch_OtherProtocols(t *testing.T) {
+	injector, err := NewInjector(&ChaosConfig{
+		Enabled: true,
+		Rules: []ChaosRule{{
+			Protocol:    ProtocolGRPC,
+			PathPattern: ".*",
+			Match:       &RuleMatch{Headers: map[string]string{"X-Client-Id": ".*"}},
+			Faults:      []FaultConfig{{Type: FaultGRPCStatus, Probability: 1}},
+			Probability: 1,
+		}},
+	})
+	if err != nil {
+		t.Fatalf("NewInjector() error = %v", err)
+	}
+	if faults := injector.ShouldInjectGRPC("/pkg.Svc/Call"); len(faults) != 0 {
+		t.Errorf("gRPC call matched a rule with caller conditions: %v", faults)
+	}
+}
+
+func TestRuleMatch_Validate(t *testing.T) {
+	for _, match := range []*RuleMatch{
+		{ClientIPs: []string{"not-an-ip"}},
+		{ClientIPs: []string{"10.0.0.0/99"}},
+		{Headers: map[string]string{"X-Id": "("}},
+		{MockName: "["},
+	} {
+		rule := ChaosRule{PathPattern: ".*", Match: match}
+		if err := rule.Validate(); err == nil {
+			t.Errorf("Validate() accepted match %+v", match)
+		}
+	}
+}
@@ -9,16 +9,44 @@ type ChaosConfig struct {
 	Enabled     bool              `json:"enabled" yaml:"enabled"`
 	Rules       []ChaosRule       `json:"rules,omitempty" yaml:"rules,omitempty"`
 	GlobalRules *GlobalChaosRules `json:"global,omitempty" yaml:"global,omitempty"`
+	// Seed seeds the random numbers behind every probability roll, so the
+	// same seed and request sequence inject the same faults. Zero picks a
+	// seed at random; the fault log reports it.
+	Seed int64 `json:"seed,omitempty" yaml:"seed,omitempty"`
+	// Replay replays the records of an exported fault log: each request
+	// receives the faults recorded for the same occurrence of its protocol,
+	// method and target, and rule probabilities are not rolled.
+	Replay []FaultRecord `json:"replay,omitempty" yaml:"replay,omitempty"`
 }
 
 // ChaosRule defines a chaos rule for specific paths
 type ChaosRule struct {
+	// Protocol selects the traffic the rule applies to: "http" (default),
+	// "grpc", "websocket", "sse" or "mqtt". gRPC rules match PathPattern
+	// against "package.Service/Method", MQTT rules against the topic.
+	Protocol    string        `json:"protocol,omitempty" yaml:"protocol,omitempty"`
 	PathPattern string        `json:"pathPattern" yaml:"pathPattern"` // Regex or glob
 	Methods     []string      `json:"methods,omitempty" yaml:"methods,omitempty"`
 	Faults      []FaultConfig `json:"faults" yaml:"faults"`
 	Probability float64       `json:"probability,omitempty" yaml:"probability,omitempty"` // 0.0-1.0
+	// Match narrows an HTTP rule to particular callers and mocks.
+	Match *RuleMatch `json:"match,omitempty" yaml:"match,omitempty"`
 }
 
+// Rule protocols.
+const (
+	// ProtocolHTTP selects HTTP requests (the default).
+	ProtocolHTTP = "http"
+	// ProtocolGRPC selects gRPC calls to mock gRPC servers.
+	ProtocolGRPC = "grpc"
+	// ProtocolWebSocket selects messages sent on WebSocket connections.
+	ProtocolWebSocket = "websocket"
+	// ProtocolSSE selects events sent on SSE streams.
+	ProtocolSSE = "sse"
+	// ProtocolMQTT selects messages published to MQTT topics.
+	ProtocolMQTT = "mqtt"
+)
+
 // GlobalChaosRules apply to all requests
 type GlobalChaosRules struct {
 	Latency   *LatencyFault   `json:"latency,omitempty" yaml:"latency,omitempty"`
@@ -64,6 +92,34 @@ const (
 	FaultProgressiveDegradation FaultType = "progressive_degradation"
 	// FaultChunkedDribble delivers response body in timed chunks
 	FaultChunkedDribble FaultType = "chunked_dribble"
+
+	// --- gRPC Fault Types ---
+
+	// FaultGRPCStatus fails the call with a chosen status code and optional error details
+	FaultGRPCStatus FaultType = "grpc_status"
+	// FaultGRPCResourceExhausted fails the call with RESOURCE_EXHAUSTED and a RetryInfo detail
+	FaultGRPCResourceExhausted FaultType = "grpc_resource_exhausted"
+	// FaultGRPCStreamAbort aborts a streaming call after N messages
+	FaultGRPCStreamAbort FaultType = "grpc_stream_abort"
+	// FaultGRPCDropTrailers ends the call without sending the status trailer
+	FaultGRPCDropTrailers FaultType = "grpc_drop_trailers"
+	// FaultGRPCDeadline fails the call with DEADLINE_EXCEEDED, as if the client deadline expired
+	FaultGRPCDeadline FaultType = "grpc_deadline"
+
+	// --- Message Fault Types (WebSocket, SSE, MQTT) ---
+
+	// FaultMessageDrop silently drops a message
+	FaultMessageDrop FaultType = "message_drop"
+	// FaultMessageDuplicate delivers a message twice
+	FaultMessageDuplicate FaultType = "message_duplicate"
+	// FaultMessageReorder holds a message back so later messages overtake it
+	FaultMessageReorder FaultType = "message_reorder"
+	// FaultMessageDelay delays a message by a random duration
+	FaultMessageDelay FaultType = "message_delay"
+	// FaultMessageClose abruptly closes the stream with a chosen close code and reason
+	FaultMessageClose FaultType = "message_close"
+	// FaultMessageThrottle limits message throughput per stream
+	FaultMessageThrottle FaultType = "message_throttle"
 )
 
 // LatencyFault adds random latency to responses
@@ -119,6 +175,10 @@ type ChaosStats struct {
 	LatencyInjected  int64               `json:"latencyInjected"`
 	ErrorsInjected   int64               `json:"errorsInjected"`
 	TimeoutsInjected int64               `json:"timeoutsInjected"`
+	// MessagesProcessed and MessagesDropped count WebSocket, SSE and MQTT
+	// messages that passed through message chaos.
+	MessagesProcessed int64 `json:"messagesProcessed"`
+	MessagesDropped   int64 `json:"messagesDropped"`
 }
 
 // NewChaosStats creates a new stats tracker
@@ -208,6 +268,12 @@ func (c *ChaosConfig) Validate() error {
 
 // Validate checks if the ChaosRule is valid.
 func (r *ChaosRule) Validate() error {
+	switch r.Protocol {
+	case "", ProtocolHTTP, ProtocolGRPC, ProtocolWebSocket, ProtocolSSE, ProtocolMQTT:
+	default:
+		return fmt.Errorf("protocol must be one of http, grpc, websocket, sse or mqtt, got %q", r.Protocol)
+	}
+
 	if r.Probability != 0 {
 		if err := validateProbability(r.Probability, "probability"); err != nil {
 			return err
@@ -220,6 +286,10 @@ func (r *ChaosRule) Validate() error {
 		}
 	}
 
+	if _, err := compileMatch(r.Match); err != nil {
+		return fmt.Errorf("match: %w", err)
+	}
+
 	return nil
 }
 
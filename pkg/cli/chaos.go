@@ -13,6 +13,7 @@ var (
 	chaosEnableErrorCode   int
 	chaosEnablePath        string
 	chaosEnableProbability float64
+	chaosEnableSeed        int64
 )
 
 var chaosCmd = &cobra.Command{
@@ -51,6 +52,10 @@ var chaosEnableCmd = &cobra.Command{
 			}
 		}
 
+		if chaosEnableSeed != 0 {
+			chaosConfig["seed"] = chaosEnableSeed
+		}
+
 		if chaosEnablePath != "" {
 			chaosConfig["rules"] = []map[string]interface{}{
 				{
@@ -123,6 +128,12 @@ var chaosStatusCmd = &cobra.Command{
 			}
 
 			fmt.Println("Chaos injection: enabled")
+			if seed, ok := config["seed"].(float64); ok && seed != 0 {
+				fmt.Printf("  Seed: %d\n", int64(seed))
+			}
+			if replay, ok := config["replay"].([]interface{}); ok && len(replay) > 0 {
+				fmt.Printf("  Replaying: %d fault log records\n", len(replay))
+			}
 
 			if global, ok := config["global"].(map[string]interface{}); ok {
 				if latency, ok := global["latency"].(map[string]interface{}); ok {
@@ -155,8 +166,10 @@ var chaosStatusCmd = &cobra.Command{
 
 var chaosProfilesCmd = &cobra.Command{
 	Use:   "profiles",
-	Short: "List available chaos profiles",
-	Long:  `List all built-in chaos profiles that can be applied by name.`,
+	Short: "List and manage chaos profiles",
+	Long: `List the built-in and user-defined chaos profiles that can be applied by
+name. User-defined profiles are managed with the create, update and delete
+subcommands and are included in config exports.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := NewAdminClientWithAuth(adminURL)
 		profiles, err := client.ListChaosProfiles()
@@ -168,7 +181,11 @@ var chaosProfilesCmd = &cobra.Command{
 			fmt.Println("Available chaos profiles:")
 			fmt.Println()
 			for _, p := range profiles {
-				fmt.Printf("  %-15s %s\n", p.Name, p.Description)
+				kind := "built-in"
+				if !p.Builtin {
+					kind = "custom"
+				}
+				fmt.Printf("  %-20s %-9s %s\n", p.Name, kind, p.Description)
 			}
 			fmt.Println()
 			fmt.Println("Apply a profile with: mockd chaos apply <profile-name>")
@@ -180,8 +197,8 @@ var chaosProfilesCmd = &cobra.Command{
 var chaosApplyCmd = &cobra.Command{
 	Use:   "apply <profile-name>",
 	Short: "Apply a named chaos profile",
-	Long: `Apply a built-in chaos profile by name. This sets the chaos configuration
-to the profile's preset values and enables chaos injection.
+	Long: `Apply a built-in or user-defined chaos profile by name. This sets the
+chaos configuration to the profile's preset values and enables chaos injection.
 
 Use "mockd chaos profiles" to see available profiles.`,
 	Args: cobra.ExactArgs(1),
@@ -240,6 +257,9 @@ func printChaosProfileConfig(p *ChaosProfileInfo) {
 			fmt.Println()
 		}
 	}
+	if rules, ok := cfg["rules"].([]interface{}); ok && len(rules) > 0 {
+		fmt.Printf("  Rules: %d\n", len(rules))
+	}
 	if bandwidth, ok := cfg["bandwidth"].(map[string]interface{}); ok {
 		bps, _ := bandwidth["bytesPerSecond"].(float64)
 		if bps >= 1024 {
@@ -259,6 +279,7 @@ func init() {
 	chaosEnableCmd.Flags().IntVar(&chaosEnableErrorCode, "error-code", 500, "HTTP error code to return")
 	chaosEnableCmd.Flags().StringVarP(&chaosEnablePath, "path", "p", "", "Path pattern to apply chaos to (regex)")
 	chaosEnableCmd.Flags().Float64Var(&chaosEnableProbability, "probability", 1.0, "Probability of applying chaos (0.0-1.0)")
+	chaosEnableCmd.Flags().Int64Var(&chaosEnableSeed, "seed", 0, "Seed chaos's random numbers so runs inject the same faults (0 picks one)")
 
 	chaosCmd.AddCommand(chaosDisableCmd)
 	chaosCmd.AddCommand(chaosStatusCmd)
@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var chaosExperimentWait bool
+
+var chaosExperimentCmd = &cobra.Command{
+	Use:   "experiment",
+	Short: "Run scheduled chaos experiments",
+	Long: `Run scheduled chaos experiments: ordered phases that each apply a chaos
+configuration for a fixed time, optionally ramping it up and aborting when
+the injected fault rate crosses a threshold. The chaos configuration that was
+active before the experiment is restored when it completes, is aborted or
+fails.`,
+}
+
+var chaosExperimentStartCmd = &cobra.Command{
+	Use:   "start <file>",
+	Short: "Start a chaos experiment from a JSON or YAML file",
+	Example: `  # experiment.yaml
+  name: checkout-resilience
+  phases:
+    - name: baseline
+      duration: 5m
+    - name: ramp-errors
+      duration: 10m
+      chaos:
+        errorRate: {probability: 0.3, defaultCode: 503}
+      ramp: {from: 0, to: 1, steps: 10}
+      abort: {maxFaultRate: 0.5, minRequests: 100}
+    - name: recovery
+      duration: 5m
+
+  mockd chaos experiment start experiment.yaml --wait`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read experiment: %w", err)
+		}
+		var experiment map[string]interface{}
+		if err := yaml.Unmarshal(data, &experiment); err != nil {
+			return fmt.Errorf("failed to parse experiment: %w", err)
+		}
+		if experiment == nil {
+			return errors.New("experiment file is empty")
+		}
+
+		client := NewAdminClientWithAuth(adminURL)
+		status, err := client.StartChaosExperiment(experiment)
+		if err != nil {
+			return fmt.Errorf("failed to start chaos experiment: %s", FormatConnectionError(err))
+		}
+
+		if chaosExperimentWait {
+			id, _ := status["id"].(string)
+			for status["state"] == "running" {
+				time.Sleep(time.Second)
+				if status, err = client.GetChaosExperiment(id); err != nil {
+					return fmt.Errorf("failed to get chaos experiment: %s", FormatConnectionError(err))
+				}
+			}
+		}
+
+		printResult(status, func() {
+			if chaosExperimentWait {
+				printChaosExperiment(status)
+				return
+			}
+			fmt.Printf("Started chaos experiment: %v\n", status["id"])
+			fmt.Printf("Follow it with: mockd chaos experiment status %v\n", status["id"])
+		})
+		return nil
+	},
+}
+
+var chaosExperimentListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List running and recent chaos experiments",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := NewAdminClientWithAuth(adminURL)
+		experiments, err := client.ListChaosExperiments()
+		if err != nil {
+			return fmt.Errorf("failed to list chaos experiments: %s", FormatConnectionError(err))
+		}
+
+		printList(experiments, func() {
+			if len(experiments) == 0 {
+				fmt.Println("No chaos experiments")
+				return
+			}
+			for _, e := range experiments {
+				fmt.Printf("  %-22v %-10v %-20v %v\n", e["id"], e["state"], e["name"], e["currentPhase"])
+			}
+		})
+		return nil
+	},
+}
+
+var chaosExperimentStatusCmd = &cobra.Command{
+	Use:   "status <id>",
+	Short: "Show a chaos experiment's phases and timeline",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := NewAdminClientWithAuth(adminURL)
+		status, err := client.GetChaosExperiment(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to get chaos experiment: %s", FormatConnectionError(err))
+		}
+
+		printResult(status, func() { printChaosExperiment(status) })
+		return nil
+	},
+}
+
+var chaosExperimentAbortCmd = &cobra.Command{
+	Use:   "abort <id>",
+	Short: "Abort a running chaos experiment and restore the previous chaos config",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := NewAdminClientWithAuth(adminURL)
+		status, err := client.AbortChaosExperiment(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to abort chaos experiment: %s", FormatConnectionError(err))
+		}
+
+		printResult(status, func() {
+			fmt.Printf("Aborted chaos experiment: %s\n", args[0])
+			fmt.Println("The previous chaos configuration has been restored.")
+		})
+		return nil
+	},
+}
+
+// printChaosExperiment displays an experiment's phases and timeline.
+func printChaosExperiment(status map[string]interface{}) {
+	fmt.Printf("Experiment %v", status["id"])
+	if name, _ := status["name"].(string); name != "" {
+		fmt.Printf(" (%s)", name)
+	}
+	fmt.Printf(": %v\n", status["state"])
+	if reason, _ := status["reason"].(string); reason != "" {
+		fmt.Printf("  Reason: %s\n", reason)
+	}
+
+	if phases, ok := status["phases"].([]interface{}); ok {
+		fmt.Println("  Phases:")
+		for _, p := range phases {
+			phase, _ := p.(map[string]interface{})
+			requests, _ := phase["requests"].(float64)
+			faults, _ := phase["injectedFaults"].(float64)
+			fmt.Printf("    %-20v %-10v requests=%d faults=%d\n", phase["name"], phase["state"], int64(requests), int64(faults))
+		}
+	}
+
+	if timeline, ok := status["timeline"].([]interface{}); ok && len(timeline) > 0 {
+		fmt.Println("  Timeline:")
+		for _, e := range timeline {
+			event, _ := e.(map[string]interface{})
+			ts, _ := event["time"].(string)
+			if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+				ts = t.Local().Format("15:04:05")
+			}
+			line := fmt.Sprintf("    %s  %-16v", ts, event["type"])
+			if phase, _ := event["phase"].(string); phase != "" {
+				line += " " + phase
+			}
+			if msg, _ := event["message"].(string); msg != "" {
+				line += " - " + msg
+			}
+			fmt.Println(line)
+		}
+	}
+}
+
+func init() {
+	chaosCmd.AddCommand(chaosExperimentCmd)
+
+	chaosExperimentCmd.AddCommand(chaosExperimentStartCmd)
+	chaosExperimentStartCmd.Flags().BoolVar(&chaosExperimentWait, "wait", false, "Wait for the experiment to finish and print its timeline")
+
+	chaosExperimentCmd.AddCommand(chaosExperimentListCmd)
+	chaosExperimentCmd.AddCommand(chaosExperimentStatusCmd)
+	chaosExperimentCmd.AddCommand(chaosExperimentAbortCmd)
+}
@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	chaosFaultLogOutput string
+	chaosReplayStop     bool
+)
+
+var chaosFaultLogCmd = &cobra.Command{
+	Use:   "fault-log",
+	Short: "Show or export the faults chaos injected",
+	Long: `Show the chaos fault log: every HTTP request and gRPC call that received
+faults, with the rule, fault and random draw behind each one, and the seed of
+chaos's random numbers. Records link to request log entries by requestId.
+
+Export the log with -o and feed it to "mockd chaos replay" to inject exactly
+the same faults again, for example to reproduce a CI failure locally.`,
+	Example: `  mockd chaos fault-log
+  mockd chaos fault-log -o faults.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := NewAdminClientWithAuth(adminURL)
+		log, err := client.GetChaosFaultLog()
+		if err != nil {
+			return fmt.Errorf("failed to get chaos fault log: %s", FormatConnectionError(err))
+		}
+
+		records, _ := log["records"].([]interface{})
+		if chaosFaultLogOutput != "" {
+			data, err := json.MarshalIndent(log, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to encode fault log: %w", err)
+			}
+			if err := os.WriteFile(chaosFaultLogOutput, append(data, '\n'), 0o600); err != nil {
+				return fmt.Errorf("failed to write fault log: %w", err)
+			}
+			printResult(map[string]any{"file": chaosFaultLogOutput, "records": len(records), "seed": log["seed"]}, func() {
+				fmt.Printf("Wrote %d fault log records to %s (seed %v)\n", len(records), chaosFaultLogOutput, log["seed"])
+			})
+			return nil
+		}
+
+		printResult(log, func() {
+			fmt.Printf("Seed: %v\n", log["seed"])
+			if dropped, ok := log["dropped"].(json.Number); ok {
+				fmt.Printf("Dropped: %s older records\n", dropped)
+			}
+			if len(records) == 0 {
+				fmt.Println("No faults injected")
+				return
+			}
+			for _, r := range records {
+				rec, _ := r.(map[string]interface{})
+				faults, _ := rec["faults"].([]interface{})
+				var types []string
+				for _, f := range faults {
+					injected, _ := f.(map[string]interface{})
+					fault, _ := injected["fault"].(map[string]interface{})
+					if t, ok := fault["type"].(string); ok {
+						types = append(types, t)
+					}
+				}
+				fmt.Printf("  #%-5v %-5v %-7v %-30v occurrence=%v faults=%v request=%v\n",
+					rec["seq"], rec["protocol"], rec["method"], rec["target"], rec["occurrence"], types, rec["requestId"])
+			}
+		})
+		return nil
+	},
+}
+
+var chaosFaultLogResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Clear the chaos fault log",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := NewAdminClientWithAuth(adminURL)
+		if err := client.ResetChaosFaultLog(); err != nil {
+			return fmt.Errorf("failed to reset chaos fault log: %s", FormatConnectionError(err))
+		}
+
+		printResult(map[string]any{"reset": true}, func() {
+			fmt.Println("Chaos fault log cleared")
+		})
+		return nil
+	},
+}
+
+var chaosReplayCmd = &cobra.Command{
+	Use:   "replay <file>",
+	Short: "Replay the faults of an exported fault log",
+	Long: `Replay a fault log exported with "mockd chaos fault-log -o". Chaos stops
+rolling probabilities: each request receives the faults recorded for the same
+occurrence of its protocol, method and path, and the log's seed is restored
+for the remaining random choices such as latency durations.
+
+Replay keeps the current chaos rules, which stateful faults such as circuit
+breakers need. Stop replaying with --stop.`,
+	Example: `  mockd chaos fault-log -o faults.json   # in CI
+  mockd chaos replay faults.json          # locally, then rerun the tests
+  mockd chaos replay --stop`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if chaosReplayStop == (len(args) == 1) {
+			return errors.New("pass a fault log file or --stop")
+		}
+
+		client := NewAdminClientWithAuth(adminURL)
+		config, err := client.GetChaosConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get chaos config: %s", FormatConnectionError(err))
+		}
+
+		if chaosReplayStop {
+			delete(config, "replay")
+			if err := client.SetChaosConfig(config); err != nil {
+				return fmt.Errorf("failed to stop replay: %s", FormatConnectionError(err))
+			}
+			printResult(map[string]any{"replaying": false}, func() {
+				fmt.Println("Stopped replaying the fault log")
+			})
+			return nil
+		}
+
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read fault log: %w", err)
+		}
+		var log struct {
+			Seed    int64         `yaml:"seed"`
+			Records []interface{} `yaml:"records"`
+		}
+		if err := yaml.Unmarshal(data, &log); err != nil {
+			return fmt.Errorf("failed to parse fault log: %w", err)
+		}
+
+		config["enabled"] = true
+		config["replay"] = log.Records
+		if log.Seed != 0 {
+			config["seed"] = log.Seed
+		}
+		if err := client.SetChaosConfig(config); err != nil {
+			return fmt.Errorf("failed to start replay: %s", FormatConnectionError(err))
+		}
+
+		printResult(map[string]any{"replaying": true, "records": len(log.Records), "seed": log.Seed}, func() {
+			fmt.Printf("Replaying %d fault log records (seed %d)\n", len(log.Records), log.Seed)
+		})
+		return nil
+	},
+}
+
+func init() {
+	chaosCmd.AddCommand(chaosFaultLogCmd)
+	chaosFaultLogCmd.Flags().StringVarP(&chaosFaultLogOutput, "output", "o", "", "Write the fault log to a file for replay")
+	chaosFaultLogCmd.AddCommand(chaosFaultLogResetCmd)
+
+	chaosCmd.AddCommand(chaosReplayCmd)
+	chaosReplayCmd.Flags().BoolVar(&chaosReplayStop, "stop", false, "Stop replaying and roll probabilities again")
+}
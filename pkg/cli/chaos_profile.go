@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var chaosProfileCreateCmd = &cobra.Command{
+	Use:   "create <file>",
+	Short: "Create a chaos profile from a JSON or YAML file",
+	Long: `Create a user-defined chaos profile. The file holds the profile's name, an
+optional description and its chaos configuration in the same shape as
+"mockd chaos status" reports it. Applying the profile always enables chaos.`,
+	Example: `  # partner-degraded.yaml
+  name: partner-degraded
+  description: Partner API at its SLO limits
+  config:
+    latency: {min: 300ms, max: 1200ms, probability: 1}
+    errorRate: {probability: 0.05, statusCodes: [502, 503]}
+
+  mockd chaos profiles create partner-degraded.yaml
+  mockd chaos apply partner-degraded`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile, err := readChaosProfileFile(args[0])
+		if err != nil {
+			return err
+		}
+
+		client := NewAdminClientWithAuth(adminURL)
+		created, err := client.CreateChaosProfile(profile)
+		if err != nil {
+			return fmt.Errorf("failed to create chaos profile: %s", FormatConnectionError(err))
+		}
+
+		printResult(created, func() {
+			fmt.Printf("Created chaos profile: %s\n", created.Name)
+			fmt.Printf("Apply it with: mockd chaos apply %s\n", created.Name)
+		})
+		return nil
+	},
+}
+
+var chaosProfileUpdateCmd = &cobra.Command{
+	Use:   "update <file>",
+	Short: "Replace a chaos profile from a JSON or YAML file",
+	Long: `Replace the user-defined chaos profile named in the file. Built-in profiles
+cannot be changed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile, err := readChaosProfileFile(args[0])
+		if err != nil {
+			return err
+		}
+		name, _ := profile["name"].(string)
+		if name == "" {
+			return errors.New("profile file must set a name")
+		}
+
+		client := NewAdminClientWithAuth(adminURL)
+		updated, err := client.UpdateChaosProfile(name, profile)
+		if err != nil {
+			return fmt.Errorf("failed to update chaos profile: %s", FormatConnectionError(err))
+		}
+
+		printResult(updated, func() {
+			fmt.Printf("Updated chaos profile: %s\n", updated.Name)
+		})
+		return nil
+	},
+}
+
+var chaosProfileDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a user-defined chaos profile",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := NewAdminClientWithAuth(adminURL)
+		if err := client.DeleteChaosProfile(args[0]); err != nil {
+			return fmt.Errorf("failed to delete chaos profile: %s", FormatConnectionError(err))
+		}
+
+		printResult(map[string]any{"name": args[0], "deleted": true}, func() {
+			fmt.Printf("Deleted chaos profile: %s\n", args[0])
+		})
+		return nil
+	},
+}
+
+// readChaosProfileFile reads a chaos profile definition from a JSON or YAML file.
+func readChaosProfileFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile: %w", err)
+	}
+	var profile map[string]interface{}
+	if err := yaml.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("failed to parse profile: %w", err)
+	}
+	if profile == nil {
+		return nil, errors.New("profile file is empty")
+	}
+	return profile, nil
+}
+
+func init() {
+	chaosProfilesCmd.AddCommand(chaosProfileCreateCmd)
+	chaosProfilesCmd.AddCommand(chaosProfileUpdateCmd)
+	chaosProfilesCmd.AddCommand(chaosProfileDeleteCmd)
+}
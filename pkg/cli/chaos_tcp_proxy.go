@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	tcpProxyListen   string
+	tcpProxyUpstream string
+)
+
+var chaosTCPProxyCmd = &cobra.Command{
+	Use:   "tcp-proxy",
+	Short: "Inject faults into TCP connections to real dependencies",
+	Long: `Run TCP proxies in front of real dependencies such as databases, caches and
+message brokers, and degrade the connections through them with toxics:
+latency, bandwidth, slicer, timeout, reset_peer and half_open. Point the
+application at the proxy's listen address instead of the dependency.
+
+Proxies live in the admin process and are not persisted. Chaos experiments
+can set their toxics per phase with tcpToxics.`,
+}
+
+var chaosTCPProxyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List TCP proxies with their toxics and stats",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := NewAdminClientWithAuth(adminURL)
+		proxies, err := client.ListTCPProxies()
+		if err != nil {
+			return fmt.Errorf("failed to list tcp proxies: %s", FormatConnectionError(err))
+		}
+
+		printList(proxies, func() {
+			if len(proxies) == 0 {
+				fmt.Println("No TCP proxies")
+				return
+			}
+			for _, p := range proxies {
+				toxics, _ := p["toxics"].([]interface{})
+				stats, _ := p["stats"].(map[string]interface{})
+				active, _ := stats["activeConnections"].(float64)
+				fmt.Printf("  %-16v %-22v -> %-22v toxics=%d active=%d\n", p["name"], p["listen"], p["upstream"], len(toxics), int64(active))
+			}
+		})
+		return nil
+	},
+}
+
+var chaosTCPProxyCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Start a TCP proxy",
+	Example: `  mockd chaos tcp-proxy create postgres --listen 127.0.0.1:15432 --upstream db.internal:5432
+  mockd chaos tcp-proxy toxics set postgres toxics.yaml`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if tcpProxyUpstream == "" {
+			return errors.New("--upstream is required")
+		}
+
+		client := NewAdminClientWithAuth(adminURL)
+		status, err := client.CreateTCPProxy(map[string]interface{}{
+			"name":     args[0],
+			"listen":   tcpProxyListen,
+			"upstream": tcpProxyUpstream,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create tcp proxy: %s", FormatConnectionError(err))
+		}
+
+		printResult(status, func() {
+			fmt.Printf("TCP proxy %s listening on %v, forwarding to %v\n", args[0], status["listen"], status["upstream"])
+		})
+		return nil
+	},
+}
+
+var chaosTCPProxyDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Stop a TCP proxy and close its connections",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := NewAdminClientWithAuth(adminURL)
+		if err := client.DeleteTCPProxy(args[0]); err != nil {
+			return fmt.Errorf("failed to delete tcp proxy: %s", FormatConnectionError(err))
+		}
+
+		printResult(map[string]any{"name": args[0], "deleted": true}, func() {
+			fmt.Printf("Deleted TCP proxy: %s\n", args[0])
+		})
+		return nil
+	},
+}
+
+var chaosTCPToxicsCmd = &cobra.Command{
+	Use:   "toxics",
+	Short: "Manage the toxics of a TCP proxy",
+}
+
+var chaosTCPToxicsSetCmd = &cobra.Command{
+	Use:   "set <proxy> <file>",
+	Short: "Replace a TCP proxy's toxics from a JSON or YAML file",
+	Long: `Replace every toxic of a TCP proxy with the list in the file. The new
+toxics apply to open connections immediately.`,
+	Example: `  # toxics.yaml
+  - type: latency
+    latency: 200ms
+    jitter: 50ms
+  - type: reset_peer
+    stream: upstream
+    timeout: 30s
+    toxicity: 0.1
+
+  mockd chaos tcp-proxy toxics set postgres toxics.yaml`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := os.ReadFile(args[1])
+		if err != nil {
+			return fmt.Errorf("failed to read toxics: %w", err)
+		}
+		var toxics []interface{}
+		if err := yaml.Unmarshal(data, &toxics); err != nil {
+			return fmt.Errorf("failed to parse toxics: %w", err)
+		}
+
+		client := NewAdminClientWithAuth(adminURL)
+		status, err := client.SetTCPToxics(args[0], toxics)
+		if err != nil {
+			return fmt.Errorf("failed to set toxics: %s", FormatConnectionError(err))
+		}
+
+		printResult(status, func() {
+			fmt.Printf("Set %d toxics on TCP proxy %s\n", len(toxics), args[0])
+		})
+		return nil
+	},
+}
+
+var chaosTCPToxicsClearCmd = &cobra.Command{
+	Use:   "clear <proxy>",
+	Short: "Remove every toxic from a TCP proxy",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := NewAdminClientWithAuth(adminURL)
+		status, err := client.SetTCPToxics(args[0], nil)
+		if err != nil {
+			return fmt.Errorf("failed to clear toxics: %s", FormatConnectionError(err))
+		}
+
+		printResult(status, func() {
+			fmt.Printf("Cleared the toxics of TCP proxy %s\n", args[0])
+		})
+		return nil
+	},
+}
+
+func init() {
+	chaosCmd.AddCommand(chaosTCPProxyCmd)
+
+	chaosTCPProxyCmd.AddCommand(chaosTCPProxyListCmd)
+	chaosTCPProxyCmd.AddCommand(chaosTCPProxyCreateCmd)
+	chaosTCPProxyCreateCmd.Flags().StringVar(&tcpProxyListen, "listen", "127.0.0.1:0", "Address to listen on (port 0 picks a free port)")
+	chaosTCPProxyCreateCmd.Flags().StringVar(&tcpProxyUpstream, "upstream", "", "Address to forward connections to (host:port)")
+	chaosTCPProxyCmd.AddCommand(chaosTCPProxyDeleteCmd)
+
+	chaosTCPProxyCmd.AddCommand(chaosTCPToxicsCmd)
+	chaosTCPToxicsCmd.AddCommand(chaosTCPToxicsSetCmd)
+	chaosTCPToxicsCmd.AddCommand(chaosTCPToxicsClearCmd)
+}
@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 
+	"github.com/getmockd/mockd/pkg/config"
 	"github.com/getmockd/mockd/pkg/recording"
 	"github.com/spf13/cobra"
 )
@@ -25,6 +27,9 @@ var (
 	convertSmartMatch     bool
 	convertDuplicates     string
 	convertIncludeHeaders bool
+	convertGraphQL        bool
+	convertStateful       bool
+	convertTemplates      bool
 	convertCheckSensitive bool
 	convertOutput         string
 )
@@ -46,6 +51,15 @@ Examples:
   # Convert only specific hosts and methods
   mockd convert --include-hosts "api.stripe.com" --method GET,POST
 
+  # Convert GraphQL calls to GraphQL mocks with per-field resolvers
+  mockd convert --session github-api --graphql
+
+  # Turn REST resources into stateful tables with CRUD bindings
+  mockd convert --session shop-api --stateful -o shop.json
+
+  # Template echoed request values, UUIDs and timestamps in responses
+  mockd convert --session my-api --smart-match --templates
+
   # Convert a specific file
   mockd convert --file ./my-recordings/rec_abc123.json
 
@@ -58,6 +72,9 @@ Examples:
 		includeHosts := &convertIncludeHosts
 		statusFilter := &convertStatus
 		includeHeaders := &convertIncludeHeaders
+		graphQL := &convertGraphQL
+		stateful := &convertStateful
+		templates := &convertTemplates
 		duplicates := &convertDuplicates
 		smartMatch := &convertSmartMatch
 		pathFilter := &convertPathFilter
@@ -92,6 +109,9 @@ Examples:
 				IncludeHeaders: *includeHeaders,
 				Deduplicate:    *duplicates != "all",
 				SmartMatch:     *smartMatch,
+				GraphQL:        *graphQL,
+				Stateful:       *stateful,
+				Templates:      *templates,
 			},
 			Filter: recording.FilterOptions{
 				PathPattern: *pathFilter,
@@ -133,6 +153,36 @@ Examples:
 			fmt.Fprintln(os.Stderr)
 		}
 
+		// Show GraphQL conversion notes
+		for _, w := range result.GraphQLWarnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+		}
+		if len(result.GraphQLOperations) > 0 {
+			fmt.Fprintf(os.Stderr, "GraphQL operations:\n")
+			for _, op := range result.GraphQLOperations {
+				fmt.Fprintf(os.Stderr, "  %s %s (%s) x%d\n", op.Type, op.Name, strings.Join(op.Fields, ", "), op.Count)
+			}
+		}
+
+		// Show inferred stateful resources
+		for _, w := range result.StatefulWarnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+		}
+		if len(result.StatefulResources) > 0 {
+			fmt.Fprintf(os.Stderr, "Stateful tables:\n")
+			for _, res := range result.StatefulResources {
+				fmt.Fprintf(os.Stderr, "  %s %s (%s) %d seed rows\n", res.Table, res.CollectionPath, strings.Join(res.Actions, ", "), res.SeedRows)
+			}
+		}
+
+		// Show inferred template substitutions
+		if len(result.Substitutions) > 0 {
+			fmt.Fprintf(os.Stderr, "Template substitutions:\n")
+			for _, sub := range result.Substitutions {
+				fmt.Fprintf(os.Stderr, "  %s %s %s %s: %q -> %s\n", sub.Method, sub.Path, sub.Location, sub.Field, sub.Value, sub.Template)
+			}
+		}
+
 		// Show stats
 		fmt.Fprintf(os.Stderr, "Processed %d recordings", result.Total)
 		if result.Filtered > 0 {
@@ -158,6 +208,9 @@ func init() {
 	convertCmd.Flags().BoolVar(&convertSmartMatch, "smart-match", false, "Convert dynamic path segments like /users/123 to /users/{id}")
 	convertCmd.Flags().StringVar(&convertDuplicates, "duplicates", "first", "Duplicate handling strategy: first, last, all")
 	convertCmd.Flags().BoolVar(&convertIncludeHeaders, "include-headers", false, "Include request headers in mock matchers")
+	convertCmd.Flags().BoolVar(&convertGraphQL, "graphql", false, "Convert GraphQL calls to GraphQL mocks with per-field resolvers")
+	convertCmd.Flags().BoolVar(&convertStateful, "stateful", false, "Infer stateful tables and CRUD bindings from REST resources")
+	convertCmd.Flags().BoolVar(&convertTemplates, "templates", false, "Replace echoed request values, UUIDs and timestamps in responses with templates")
 	convertCmd.Flags().BoolVar(&convertCheckSensitive, "check-sensitive", true, "Check for sensitive data and show warnings")
 
 	convertCmd.Flags().StringVarP(&convertOutput, "output", "o", "", "Output file path (default: stdout)")
@@ -212,13 +265,17 @@ func matchHost(host, pattern string) bool {
 // configEnvelope wraps mock configurations in the format accepted by
 // mockd's config import endpoint (POST /config) and the 'mockd import -f mockd' command.
 type configEnvelope struct {
-	Version string      `json:"version"`
-	Mocks   interface{} `json:"mocks"`
+	Version string                  `json:"version"`
+	Tables  []*config.TableConfig   `json:"tables,omitempty"`
+	Extend  []*config.ExtendBinding `json:"extend,omitempty"`
+	Mocks   interface{}             `json:"mocks"`
 }
 
 func outputConversionResult(result *recording.ConversionResult, output string) error {
 	envelope := configEnvelope{
 		Version: "1.0",
+		Tables:  result.Tables,
+		Extend:  result.Extend,
 		Mocks:   result.Mocks,
 	}
 
@@ -8,7 +8,6 @@ import (
 	"strings"
 
 	"github.com/getmockd/mockd/pkg/config"
-	"github.com/getmockd/mockd/pkg/mock"
 	"github.com/getmockd/mockd/pkg/portability"
 )
 
@@ -112,9 +111,9 @@ func applyNamespace(collection *config.MockCollection, namespace string) {
 //  1. Convert each TableConfig → StatefulResourceConfig and append to
 //     collection.StatefulResources. Tables have no basePath because routing
 //     is done via StatefulBinding on the mock, not via MatchPath.
-//  2. For each ExtendBinding, find the target mock by OperationID (exact match)
-//     or by "METHOD /path" fallback. Set mock.HTTP.StatefulBinding.
-//  3. Resolve response transforms: binding.Response > table.Response > nil.
+//  2. Resolve extend bindings with config.ApplyExtendBindings: find each
+//     target mock by OperationID or "METHOD /path", set
+//     mock.HTTP.StatefulBinding and resolve its response transform.
 func processTablesAndExtend(collection *config.MockCollection) error {
 	// Step 1: Convert tables to stateful resources
 	if len(collection.Tables) > 0 {
@@ -129,99 +128,12 @@ func processTablesAndExtend(collection *config.MockCollection) error {
 			tableMap[table.Name] = table
 
 			// Convert TableConfig → StatefulResourceConfig (no basePath — bridge-only)
-			res := &config.StatefulResourceConfig{
-				Name:          table.Name,
-				IDField:       table.IDField,
-				IDStrategy:    table.IDStrategy,
-				IDPrefix:      table.IDPrefix,
-				ParentField:   table.ParentField,
-				MaxItems:      table.MaxItems,
-				SeedData:      table.SeedData,
-				Response:      table.Response,
-				Relationships: table.Relationships,
-			}
-			collection.StatefulResources = append(collection.StatefulResources, res)
+			collection.StatefulResources = append(collection.StatefulResources, table.StatefulResource())
 		}
 
 		// Step 2: Resolve extend bindings
-		if len(collection.Extend) > 0 {
-			// Build indexes for mock lookup
-			opIDIndex := make(map[string]*mock.Mock, len(collection.Mocks))
-			methodPathIndex := make(map[string]*mock.Mock, len(collection.Mocks))
-			for _, m := range collection.Mocks {
-				if m.OperationID != "" {
-					opIDIndex[m.OperationID] = m
-				}
-				if m.HTTP != nil && m.HTTP.Matcher != nil {
-					method := strings.ToUpper(m.HTTP.Matcher.Method)
-					path := m.HTTP.Matcher.Path
-					if method != "" && path != "" {
-						methodPathIndex[method+" "+path] = m
-					}
-				}
-			}
-
-			for _, binding := range collection.Extend {
-				if binding.Mock == "" {
-					return errors.New("extend: mock reference is required")
-				}
-				if binding.Table == "" {
-					return fmt.Errorf("extend %q: table is required", binding.Mock)
-				}
-				if binding.Action == "" {
-					return fmt.Errorf("extend %q: action is required", binding.Mock)
-				}
-
-				// Validate table reference
-				table, ok := tableMap[binding.Table]
-				if !ok {
-					return fmt.Errorf("extend %q: table %q not found", binding.Mock, binding.Table)
-				}
-
-				// Find the target mock: operationId first, then "METHOD /path"
-				target := opIDIndex[binding.Mock]
-				if target == nil {
-					target = methodPathIndex[binding.Mock]
-				}
-				if target == nil {
-					return fmt.Errorf("extend %q: mock not found (checked operationId and METHOD /path)", binding.Mock)
-				}
-
-				// Ensure mock has HTTP spec
-				if target.HTTP == nil {
-					return fmt.Errorf("extend %q: mock is not an HTTP mock", binding.Mock)
-				}
-
-				// Validate custom action has operation name
-				if binding.Action == "custom" && binding.Operation == "" {
-					return fmt.Errorf("extend %q: action 'custom' requires an operation name", binding.Mock)
-				}
-
-				// Clear conflicting response types — the mock may have a static
-				// Response from an OpenAPI import, but extend replaces it with
-				// a stateful binding.
-				target.HTTP.ClearConflictingResponseTypes()
-
-				// Set StatefulBinding on the mock
-				target.HTTP.StatefulBinding = &mock.StatefulBinding{
-					Table:     binding.Table,
-					Action:    binding.Action,
-					Operation: binding.Operation,
-				}
-
-				// Resolve response transform: binding override > table default > nil
-				var responseTransform *config.ResponseTransform
-				if binding.Response != nil {
-					responseTransform = binding.Response
-				} else if table.Response != nil {
-					responseTransform = table.Response
-				}
-				if responseTransform != nil {
-					target.HTTP.StatefulBinding.Response = &mock.StatefulBindingResponse{
-						Transform: responseTransform,
-					}
-				}
-			}
+		if err := config.ApplyExtendBindings(collection.Mocks, collection.Tables, collection.Extend); err != nil {
+			return err
 		}
 	}
 
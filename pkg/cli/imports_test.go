@@ -10,6 +10,7 @@ import (
 
 	"github.com/getmockd/mockd/pkg/config"
 	"github.com/getmockd/mockd/pkg/mock"
+	"github.com/getmockd/mockd/pkg/recording"
 )
 
 // ---------------------------------------------------------------------------
@@ -540,6 +541,11 @@ func TestProcessTablesAndExtend(t *testing.T) {
 					ParentField:   "org_id",
 					SeedData:      seed,
 					Relationships: relationships,
+					Lifecycle: []*config.LifecycleTransition{
+						{From: "pending", To: "active", Delay: "5s"},
+					},
+					SeedGenerate: &config.SeedGenerateConfig{Count: 10},
+					Pagination:   &config.PaginationConfig{Style: "page"},
 					Response: &config.ResponseTransform{
 						List: &config.ListTransform{
 							DataField: "results",
@@ -584,6 +590,15 @@ func TestProcessTablesAndExtend(t *testing.T) {
 		if res.Response == nil {
 			t.Error("Response transform should be propagated")
 		}
+		if len(res.Lifecycle) != 1 || res.Lifecycle[0].To != "active" {
+			t.Errorf("Lifecycle should be propagated, got %+v", res.Lifecycle)
+		}
+		if res.SeedGenerate == nil || res.SeedGenerate.Count != 10 {
+			t.Errorf("SeedGenerate should be propagated, got %+v", res.SeedGenerate)
+		}
+		if res.Pagination == nil || res.Pagination.Style != "page" {
+			t.Errorf("Pagination should be propagated, got %+v", res.Pagination)
+		}
 	})
 
 	t.Run("multiple tables create multiple resources", func(t *testing.T) {
@@ -1169,4 +1184,33 @@ func TestProcessTablesAndExtend(t *testing.T) {
 			t.Errorf("users seed data length = %d, want 1", len(usersRes.SeedData))
 		}
 	})
+	t.Run("stateful conversion of recordings resolves", func(t *testing.T) {
+		rec := func(method, path string, status int, body string) *recording.Recording {
+			r := recording.NewRecording("session")
+			r.Request = recording.RecordedRequest{Method: method, Path: path}
+			r.Response = recording.RecordedResponse{StatusCode: status, Body: []byte(body)}
+			return r
+		}
+		opts := recording.DefaultSessionConvertOptions()
+		opts.Stateful = true
+		result := recording.ConvertRecordingsWithOptions([]*recording.Recording{
+			rec("GET", "/api/users", 200, `[{"id":"u1","name":"Ann"}]`),
+			rec("POST", "/api/users", 201, `{"id":"u2","name":"Bob"}`),
+			rec("GET", "/api/users/u1", 200, `{"id":"u1","name":"Ann"}`),
+		}, opts)
+
+		col := &config.MockCollection{Tables: result.Tables, Extend: result.Extend, Mocks: result.Mocks}
+		if err := processTablesAndExtend(col); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		bound := 0
+		for _, m := range col.Mocks {
+			if m.HTTP.StatefulBinding != nil {
+				bound++
+			}
+		}
+		if bound != 3 {
+			t.Errorf("bound mocks = %d, want 3", bound)
+		}
+	})
 }
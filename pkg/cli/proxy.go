@@ -7,6 +7,7 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -19,6 +20,7 @@ import (
 	"github.com/getmockd/mockd/pkg/recording"
 	"github.com/getmockd/mockd/pkg/store"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 // SessionMeta is the metadata written to meta.json for each recording session.
@@ -28,6 +30,7 @@ type SessionMeta struct {
 	EndTime        string   `json:"endTime,omitempty"`
 	Port           int      `json:"port"`
 	Mode           string   `json:"mode"`
+	Upstream       string   `json:"upstream,omitempty"`
 	RecordingCount int      `json:"recordingCount"`
 	Hosts          []string `json:"hosts,omitempty"`
 	Filters        *struct {
@@ -54,6 +57,11 @@ var (
 	proxyStartExcludePaths  string
 	proxyStartIncludeHosts  string
 	proxyStartExcludeHosts  string
+	proxyStartOnMiss        string
+	proxyStartReplaySession string
+	proxyStartUpstream      string
+	proxyStartRedact        string
+	proxyStartIntrospect    bool
 )
 
 var proxyStartCmd = &cobra.Command{
@@ -61,7 +69,42 @@ var proxyStartCmd = &cobra.Command{
 	Short: "Start the MITM proxy server (foreground, Ctrl+C to stop)",
 	Long: `Start the MITM proxy server for recording API traffic.
 Recordings are written to disk as traffic flows through the proxy.
-Press Ctrl+C to stop. Use 'mockd recordings list' to view captured traffic.`,
+Press Ctrl+C to stop. Use 'mockd recordings list' to view captured traffic.
+
+In replay mode the proxy answers requests from an existing recording session
+instead of the network, like a VCR cassette. Requests match recordings by
+method, host and path, with IDs in the path matched smartly (/users/42 replays
+a recording of /users/7). --on-miss decides what happens to requests without
+a recording: fail (502), forward them, or forward and record them into the
+replayed session.
+
+With --upstream the proxy is a reverse proxy instead: clients call it directly,
+with no HTTP_PROXY or CA certificate, and every request is forwarded to the
+upstream base URL. Location headers, cookies and absolute URLs in response
+bodies are rewritten to point back at the proxy. Recording and replay work the
+same way, with recordings holding the upstream's URLs.
+
+--redact loads redaction rules (YAML or JSON) that are applied to every
+recording before it is written to disk. Rules target a header, cookie, query
+parameter, JSONPath, XPath or regex and drop, mask, hash or fake the value.
+Hashed and faked values are stable per original value, so the same token
+redacts to the same replacement everywhere; set "salt" to keep them stable
+across sessions.`,
+	Example: `  # Record, then run the test suite offline against the recordings
+  mockd proxy start --session api
+  mockd proxy start --mode replay --replay-session api
+
+  # Fill gaps in the cassette as new requests appear
+  mockd proxy start --mode replay --on-miss record
+
+  # Reverse proxy: point the app's base URL at http://localhost:8888
+  mockd proxy start --upstream https://api.stripe.com
+
+  # Redact secrets and PII before anything reaches disk
+  mockd proxy start --redact redaction.yaml
+
+  # Capture GraphQL schemas for 'mockd convert --graphql'
+  mockd proxy start --upstream https://api.example.com --graphql-introspect`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		port := &proxyStartPort
 		mode := &proxyStartMode
@@ -79,14 +122,23 @@ Press Ctrl+C to stop. Use 'mockd recordings list' to view captured traffic.`,
 		}
 
 		// Parse mode
-		var proxyMode proxy.Mode
-		switch *mode {
-		case "record":
-			proxyMode = proxy.ModeRecord
-		case "passthrough":
-			proxyMode = proxy.ModePassthrough
-		default:
-			return fmt.Errorf("invalid mode: %s (must be 'record' or 'passthrough')", *mode)
+		proxyMode, err := proxy.ParseMode(*mode)
+		if err != nil {
+			return err
+		}
+		onMiss, err := proxy.ParseMissPolicy(proxyStartOnMiss)
+		if err != nil {
+			return err
+		}
+		var upstream *url.URL
+		if proxyStartUpstream != "" {
+			if upstream, err = proxy.ParseUpstream(proxyStartUpstream); err != nil {
+				return err
+			}
+		}
+		redactor, err := loadRedactor(proxyStartRedact)
+		if err != nil {
+			return err
 		}
 
 		// Determine session name and directory
@@ -104,8 +156,18 @@ Press Ctrl+C to stop. Use 'mockd recordings list' to view captured traffic.`,
 		}
 		sessionDir := filepath.Join(baseDir, sessionDirName)
 
-		// Create session directory
-		if err := os.MkdirAll(sessionDir, 0700); err != nil {
+		// Replay mode reads an existing session and records misses into it
+		var replayed []*recording.Recording
+		if proxyMode == proxy.ModeReplay {
+			sessionDir, err = recording.ResolveSessionDir(baseDir, proxyStartReplaySession)
+			if err != nil {
+				return fmt.Errorf("failed to find recordings to replay: %w", err)
+			}
+			if replayed, err = recording.LoadFromDir(sessionDir); err != nil {
+				return fmt.Errorf("failed to load recordings to replay: %w", err)
+			}
+			sessionDirName = filepath.Base(sessionDir)
+		} else if err := os.MkdirAll(sessionDir, 0700); err != nil {
 			return fmt.Errorf("failed to create session directory: %w", err)
 		}
 
@@ -130,6 +192,7 @@ Press Ctrl+C to stop. Use 'mockd recordings list' to view captured traffic.`,
 			StartTime: time.Now().Format(time.RFC3339),
 			Port:      *port,
 			Mode:      *mode,
+			Upstream:  proxyStartUpstream,
 		}
 		if *includePaths != "" || *excludePaths != "" || *includeHosts != "" || *excludeHosts != "" {
 			meta.Filters = &struct {
@@ -144,8 +207,10 @@ Press Ctrl+C to stop. Use 'mockd recordings list' to view captured traffic.`,
 				ExcludeHosts: filter.ExcludeHosts,
 			}
 		}
-		if err := writeSessionMeta(sessionDir, &meta); err != nil {
-			return fmt.Errorf("failed to write session metadata: %w", err)
+		if proxyMode != proxy.ModeReplay {
+			if err := writeSessionMeta(sessionDir, &meta); err != nil {
+				return fmt.Errorf("failed to write session metadata: %w", err)
+			}
 		}
 
 		// Create CA manager (optional, enables HTTPS MITM)
@@ -159,17 +224,25 @@ Press Ctrl+C to stop. Use 'mockd recordings list' to view captured traffic.`,
 
 		// Create in-memory store (for summary on exit)
 		memStore := recording.NewStore()
-		memStore.CreateSession(sessionName, nil)
+		memSession := memStore.CreateSession(sessionName, nil)
+		for _, r := range replayed {
+			memSession.AddRecording(r)
+		}
 
 		// Create proxy with disk persistence
 		logger := log.New(os.Stdout, "[proxy] ", log.LstdFlags)
 		p := proxy.New(proxy.Options{
 			Mode:      proxyMode,
+			OnMiss:    onMiss,
+			Upstream:  upstream,
 			Store:     memStore,
 			DiskDir:   sessionDir,
+			Redactor:  redactor,
 			Filter:    filter,
 			CAManager: ca,
 			Logger:    logger,
+
+			GraphQLIntrospection: proxyStartIntrospect,
 		})
 
 		// Start HTTP server
@@ -187,10 +260,18 @@ Press Ctrl+C to stop. Use 'mockd recordings list' to view captured traffic.`,
 		}
 
 		// Print startup info
-		fmt.Printf("Proxy server running on http://localhost:%d\n", *port)
+		if upstream != nil {
+			fmt.Printf("Reverse proxy running on http://localhost:%d -> %s\n", *port, upstream)
+		} else {
+			fmt.Printf("Proxy server running on http://localhost:%d\n", *port)
+		}
 		fmt.Printf("Mode: %s\n", proxyMode)
-		fmt.Printf("Session: %s\n", sessionName)
-		fmt.Printf("Recordings: %s\n", sessionDir)
+		if proxyMode == proxy.ModeReplay {
+			fmt.Printf("Replaying: %d recordings from %s (on miss: %s)\n", len(replayed), sessionDir, onMiss)
+		} else {
+			fmt.Printf("Session: %s\n", sessionName)
+			fmt.Printf("Recordings: %s\n", sessionDir)
+		}
 		if ca != nil {
 			fmt.Printf("CA certificate: %s\n", ca.CertPath())
 		}
@@ -213,6 +294,11 @@ Press Ctrl+C to stop. Use 'mockd recordings list' to view captured traffic.`,
 			output.Warn("server shutdown error: %v", err)
 		}
 
+		if proxyMode == proxy.ModeReplay {
+			finishReplaySession(p, sessionDir, len(replayed))
+			return nil
+		}
+
 		// Update meta.json with final stats
 		hosts := discoverHosts(sessionDir)
 		recordings, total := memStore.ListRecordings(recording.RecordingFilter{})
@@ -317,6 +403,56 @@ var proxyCAGenerateCmd = &cobra.Command{
 	},
 }
 
+// finishReplaySession prints replay statistics and, when misses were
+// recorded into the replayed session, updates its meta.json.
+func finishReplaySession(p *proxy.Proxy, sessionDir string, replayed int) {
+	stats := p.ReplayStats()
+	_, total := p.Store().ListRecordings(recording.RecordingFilter{})
+	recorded := total - replayed
+
+	if recorded > 0 {
+		var meta SessionMeta
+		if data, err := os.ReadFile(filepath.Join(sessionDir, "meta.json")); err == nil {
+			_ = json.Unmarshal(data, &meta)
+		}
+		if meta.Name == "" {
+			meta.Name = filepath.Base(sessionDir)
+		}
+		meta.RecordingCount = total
+		meta.Hosts = discoverHosts(sessionDir)
+		if err := writeSessionMeta(sessionDir, &meta); err != nil {
+			output.Warn("failed to update session metadata: %v", err)
+		}
+	}
+
+	fmt.Println("Proxy stopped")
+	fmt.Printf("\nReplayed %d requests, %d misses\n", stats.Hits, stats.Misses)
+	if recorded > 0 {
+		fmt.Printf("Recorded %d new recordings into %s\n", recorded, sessionDir)
+	}
+}
+
+// loadRedactor reads a redaction rules file. YAML is a superset of JSON, so
+// both formats parse the same way. An empty path means no redaction.
+func loadRedactor(path string) (*recording.Redactor, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read redaction rules: %w", err)
+	}
+	var cfg recording.RedactionConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse redaction rules %s: %w", path, err)
+	}
+	redactor, err := recording.NewRedactor(&cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redaction rules %s: %w", path, err)
+	}
+	return redactor, nil
+}
+
 // writeSessionMeta writes the meta.json file for a session directory.
 func writeSessionMeta(sessionDir string, meta *SessionMeta) error {
 	data, err := json.MarshalIndent(meta, "", "  ")
@@ -381,7 +517,7 @@ func init() {
 
 	proxyCmd.AddCommand(proxyStartCmd)
 	proxyStartCmd.Flags().IntVarP(&proxyStartPort, "port", "p", 8888, "Proxy server port")
-	proxyStartCmd.Flags().StringVarP(&proxyStartMode, "mode", "m", "record", "Proxy mode: record or passthrough")
+	proxyStartCmd.Flags().StringVarP(&proxyStartMode, "mode", "m", "record", "Proxy mode: record, passthrough or replay")
 	proxyStartCmd.Flags().StringVarP(&proxyStartSession, "session", "s", "", "Recording session name")
 	proxyStartCmd.Flags().StringVar(&proxyStartRecordingsDir, "recordings-dir", "", "Base directory for recordings")
 	proxyStartCmd.Flags().StringVar(&proxyStartCAPath, "ca-path", "", "Path to CA certificate directory")
@@ -389,6 +525,11 @@ func init() {
 	proxyStartCmd.Flags().StringVar(&proxyStartExcludePaths, "exclude", "", "Comma-separated path patterns to exclude")
 	proxyStartCmd.Flags().StringVar(&proxyStartIncludeHosts, "include-hosts", "", "Comma-separated host patterns to include")
 	proxyStartCmd.Flags().StringVar(&proxyStartExcludeHosts, "exclude-hosts", "", "Comma-separated host patterns to exclude")
+	proxyStartCmd.Flags().StringVar(&proxyStartOnMiss, "on-miss", "fail", "Replay mode: what to do without a recording (fail, forward or record)")
+	proxyStartCmd.Flags().StringVar(&proxyStartUpstream, "upstream", "", "Run as a reverse proxy forwarding all requests to this base URL")
+	proxyStartCmd.Flags().StringVar(&proxyStartReplaySession, "replay-session", "latest", "Replay mode: recording session to replay")
+	proxyStartCmd.Flags().StringVar(&proxyStartRedact, "redact", "", "Redaction rules file (YAML or JSON) applied before recordings are written")
+	proxyStartCmd.Flags().BoolVar(&proxyStartIntrospect, "graphql-introspect", false, "Fetch the schema of recorded GraphQL endpoints through introspection")
 
 	proxyCmd.AddCommand(proxyCACmd)
 
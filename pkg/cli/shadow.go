@@ -0,0 +1,330 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	shadowEnableUpstream      string
+	shadowEnableMocks         []string
+	shadowEnablePath          string
+	shadowEnableMethods       []string
+	shadowEnableSampleRate    float64
+	shadowEnableTimeout       string
+	shadowEnableHeaders       []string
+	shadowEnableIgnoreStatus  bool
+	shadowEnableIgnoreHeaders []string
+	shadowEnableIgnoreFields  []string
+	shadowEnableIgnoreMissing bool
+
+	driftReportMock        string
+	driftReportFailOnDrift bool
+)
+
+var shadowCmd = &cobra.Command{
+	Use:   "shadow",
+	Short: "Compare mock responses with the real upstream",
+	Long: `Manage shadow mode. Requests selected by shadow mode are answered by the
+mock as usual and also sent, in the background, to the real upstream. The two
+responses are compared structurally — status, headers, JSON shape and types —
+and any drift is reported per mock by "mockd drift report".`,
+}
+
+var shadowEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Enable shadow mode against an upstream",
+	Example: `  mockd shadow enable --upstream https://api.example.com
+  mockd shadow enable --upstream https://api.example.com --mock users --sample-rate 0.1
+  mockd shadow enable --upstream https://api.example.com --path '^/api/' \
+    --ignore-field request_id --ignore-field '$.meta' --header 'Authorization=Bearer token'`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if shadowEnableUpstream == "" {
+			return errors.New("--upstream is required")
+		}
+
+		shadowConfig := map[string]interface{}{
+			"enabled":  true,
+			"upstream": shadowEnableUpstream,
+		}
+		if len(shadowEnableMocks) > 0 {
+			shadowConfig["mockIds"] = shadowEnableMocks
+		}
+		if shadowEnablePath != "" {
+			shadowConfig["pathPattern"] = shadowEnablePath
+		}
+		if len(shadowEnableMethods) > 0 {
+			shadowConfig["methods"] = shadowEnableMethods
+		}
+		if shadowEnableSampleRate > 0 {
+			shadowConfig["sampleRate"] = shadowEnableSampleRate
+		}
+		if shadowEnableTimeout != "" {
+			shadowConfig["timeout"] = shadowEnableTimeout
+		}
+		if len(shadowEnableHeaders) > 0 {
+			headers := make(map[string]string, len(shadowEnableHeaders))
+			for _, h := range shadowEnableHeaders {
+				name, value, ok := strings.Cut(h, "=")
+				if !ok || name == "" {
+					return fmt.Errorf("invalid header %q: expected name=value", h)
+				}
+				headers[name] = value
+			}
+			shadowConfig["headers"] = headers
+		}
+
+		ignore := map[string]interface{}{}
+		if shadowEnableIgnoreStatus {
+			ignore["status"] = true
+		}
+		if len(shadowEnableIgnoreHeaders) > 0 {
+			ignore["headers"] = shadowEnableIgnoreHeaders
+		}
+		if len(shadowEnableIgnoreFields) > 0 {
+			ignore["fields"] = shadowEnableIgnoreFields
+		}
+		if shadowEnableIgnoreMissing {
+			ignore["missingFields"] = true
+		}
+		if len(ignore) > 0 {
+			shadowConfig["ignore"] = ignore
+		}
+
+		client := NewAdminClientWithAuth(adminURL)
+		if err := client.SetShadowConfig(shadowConfig); err != nil {
+			return fmt.Errorf("failed to enable shadow mode: %s", FormatConnectionError(err))
+		}
+
+		printResult(map[string]any{"enabled": true, "config": shadowConfig}, func() {
+			fmt.Println("Shadow mode enabled")
+			fmt.Printf("  Upstream: %s\n", shadowEnableUpstream)
+			if len(shadowEnableMocks) > 0 {
+				fmt.Printf("  Mocks: %s\n", strings.Join(shadowEnableMocks, ", "))
+			}
+			if shadowEnablePath != "" {
+				fmt.Printf("  Path pattern: %s\n", shadowEnablePath)
+			}
+			if shadowEnableSampleRate > 0 {
+				fmt.Printf("  Sample rate: %.0f%%\n", shadowEnableSampleRate*100)
+			}
+			fmt.Println()
+			fmt.Println("See drift with: mockd drift report")
+		})
+		return nil
+	},
+}
+
+var shadowDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Disable shadow mode",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := NewAdminClientWithAuth(adminURL)
+		if err := client.SetShadowConfig(map[string]interface{}{"enabled": false}); err != nil {
+			return fmt.Errorf("failed to disable shadow mode: %s", FormatConnectionError(err))
+		}
+
+		printResult(map[string]any{"enabled": false}, func() {
+			fmt.Println("Shadow mode disabled")
+		})
+		return nil
+	},
+}
+
+var shadowStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the shadow mode configuration",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := NewAdminClientWithAuth(adminURL)
+		config, err := client.GetShadowConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get shadow status: %s", FormatConnectionError(err))
+		}
+
+		printResult(config, func() {
+			enabled, _ := config["enabled"].(bool)
+			if !enabled {
+				fmt.Println("Shadow mode: disabled")
+				return
+			}
+
+			fmt.Println("Shadow mode: enabled")
+			fmt.Printf("  Upstream: %v\n", config["upstream"])
+			if mocks := joinStrings(config["mockIds"]); mocks != "" {
+				fmt.Printf("  Mocks: %s\n", mocks)
+			}
+			if pattern, ok := config["pathPattern"].(string); ok {
+				fmt.Printf("  Path pattern: %s\n", pattern)
+			}
+			if methods := joinStrings(config["methods"]); methods != "" {
+				fmt.Printf("  Methods: %s\n", methods)
+			}
+			if rate, ok := config["sampleRate"].(float64); ok {
+				fmt.Printf("  Sample rate: %.0f%%\n", rate*100)
+			}
+			if ignore, ok := config["ignore"].(map[string]interface{}); ok {
+				if fields := joinStrings(ignore["fields"]); fields != "" {
+					fmt.Printf("  Ignored fields: %s\n", fields)
+				}
+				if headers := joinStrings(ignore["headers"]); headers != "" {
+					fmt.Printf("  Ignored headers: %s\n", headers)
+				}
+				if status, _ := ignore["status"].(bool); status {
+					fmt.Println("  Ignoring status codes")
+				}
+				if missing, _ := ignore["missingFields"].(bool); missing {
+					fmt.Println("  Ignoring fields missing from mocks")
+				}
+			}
+		})
+		return nil
+	},
+}
+
+var driftCmd = &cobra.Command{
+	Use:   "drift",
+	Short: "Report drift between mocks and the real upstream",
+	Long: `Report the drift shadow mode found between mock responses and the real
+upstream. Enable shadow mode first with "mockd shadow enable".`,
+}
+
+var driftReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Show the drift of each shadowed mock",
+	Long: `Show, for each shadowed mock, how many responses were compared with the
+upstream, how many drifted, and the differences found: status codes, headers,
+and JSON fields that are missing from the mock, extra in the mock, or of a
+different type.
+
+Use --fail-on-drift in CI to exit non-zero when any mock drifted.`,
+	Example: `  mockd drift report
+  mockd drift report --mock users
+  mockd drift report --fail-on-drift`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := NewAdminClientWithAuth(adminURL)
+		report, err := client.GetDriftReport(driftReportMock)
+		if err != nil {
+			return fmt.Errorf("failed to get drift report: %s", FormatConnectionError(err))
+		}
+
+		mocks, _ := report["mocks"].([]interface{})
+		printResult(report, func() {
+			enabled, _ := report["enabled"].(bool)
+			if enabled {
+				fmt.Printf("Shadow mode: enabled (upstream %v)\n", report["upstream"])
+			} else {
+				fmt.Println("Shadow mode: disabled")
+			}
+			fmt.Printf("Compared: %v  Drifted: %v  Errors: %v\n", report["compared"], report["drifted"], report["errors"])
+			if dropped, ok := report["dropped"].(float64); ok && dropped > 0 {
+				fmt.Printf("Dropped: %d requests (shadow queue full)\n", int64(dropped))
+			}
+			if len(mocks) == 0 {
+				fmt.Println("No shadowed responses yet")
+				return
+			}
+
+			for _, m := range mocks {
+				drift, _ := m.(map[string]interface{})
+				fmt.Println()
+				fmt.Printf("%v  %v %v\n", drift["mockId"], drift["method"], drift["path"])
+				fmt.Printf("  compared=%v drifted=%v errors=%v\n", drift["compared"], drift["drifted"], drift["errors"])
+				if lastError, ok := drift["lastError"].(string); ok {
+					fmt.Printf("  last error: %s\n", lastError)
+				}
+				diffs, _ := drift["differences"].([]interface{})
+				for _, d := range diffs {
+					diff, _ := d.(map[string]interface{})
+					fmt.Printf("  - %s (x%v)\n", describeDifference(diff), diff["count"])
+				}
+			}
+		})
+
+		if driftReportFailOnDrift {
+			if drifted, _ := report["drifted"].(float64); drifted > 0 {
+				return fmt.Errorf("%d shadowed responses drifted from the upstream", int64(drifted))
+			}
+		}
+		return nil
+	},
+}
+
+var driftResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Clear the drift report",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := NewAdminClientWithAuth(adminURL)
+		if err := client.ResetDrift(); err != nil {
+			return fmt.Errorf("failed to reset drift report: %s", FormatConnectionError(err))
+		}
+
+		printResult(map[string]any{"reset": true}, func() {
+			fmt.Println("Drift report cleared")
+		})
+		return nil
+	},
+}
+
+// describeDifference renders a drift difference from the admin API as one line.
+func describeDifference(diff map[string]interface{}) string {
+	kind, _ := diff["kind"].(string)
+	path, _ := diff["path"].(string)
+	mockValue, _ := diff["mock"].(string)
+	upstreamValue, _ := diff["upstream"].(string)
+
+	switch kind {
+	case "status":
+		return fmt.Sprintf("status: mock %s, upstream %s", mockValue, upstreamValue)
+	case "header":
+		if upstreamValue == "" {
+			return fmt.Sprintf("header %s: missing upstream", path)
+		}
+		return fmt.Sprintf("header %s: mock %q, upstream %q", path, mockValue, upstreamValue)
+	case "missing":
+		return fmt.Sprintf("missing from mock: %s (%s)", path, upstreamValue)
+	case "extra":
+		return fmt.Sprintf("not in upstream: %s (%s)", path, mockValue)
+	case "type":
+		return fmt.Sprintf("type of %s: mock %s, upstream %s", path, mockValue, upstreamValue)
+	default:
+		return fmt.Sprintf("%s %s: mock %s, upstream %s", kind, path, mockValue, upstreamValue)
+	}
+}
+
+// joinStrings joins a JSON string array with commas.
+func joinStrings(v interface{}) string {
+	items, _ := v.([]interface{})
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		parts = append(parts, fmt.Sprint(item))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func init() {
+	rootCmd.AddCommand(shadowCmd)
+
+	shadowEnableCmd.Flags().StringVar(&shadowEnableUpstream, "upstream", "", "Base URL of the real upstream (required)")
+	shadowEnableCmd.Flags().StringSliceVar(&shadowEnableMocks, "mock", nil, "Only shadow these mock IDs (repeatable)")
+	shadowEnableCmd.Flags().StringVar(&shadowEnablePath, "path", "", "Only shadow request paths matching this regex")
+	shadowEnableCmd.Flags().StringSliceVar(&shadowEnableMethods, "method", nil, "Only shadow these HTTP methods (repeatable; default GET, HEAD, OPTIONS)")
+	shadowEnableCmd.Flags().Float64Var(&shadowEnableSampleRate, "sample-rate", 0, "Fraction of selected requests to shadow (0-1, default all)")
+	shadowEnableCmd.Flags().StringVar(&shadowEnableTimeout, "timeout", "", "Upstream request timeout (default 10s)")
+	shadowEnableCmd.Flags().StringArrayVar(&shadowEnableHeaders, "header", nil, "Header to add to upstream requests, as name=value (repeatable)")
+	shadowEnableCmd.Flags().BoolVar(&shadowEnableIgnoreStatus, "ignore-status", false, "Do not compare status codes")
+	shadowEnableCmd.Flags().StringSliceVar(&shadowEnableIgnoreHeaders, "ignore-header", nil, "Header to leave out of the comparison (repeatable)")
+	shadowEnableCmd.Flags().StringSliceVar(&shadowEnableIgnoreFields, "ignore-field", nil, "JSON field to leave out: a path like $.items[*].id or a bare name (repeatable)")
+	shadowEnableCmd.Flags().BoolVar(&shadowEnableIgnoreMissing, "ignore-missing", false, "Do not report upstream fields the mock leaves out")
+	shadowCmd.AddCommand(shadowEnableCmd)
+	shadowCmd.AddCommand(shadowDisableCmd)
+	shadowCmd.AddCommand(shadowStatusCmd)
+
+	rootCmd.AddCommand(driftCmd)
+	driftReportCmd.Flags().StringVar(&driftReportMock, "mock", "", "Only report this mock ID")
+	driftReportCmd.Flags().BoolVar(&driftReportFailOnDrift, "fail-on-drift", false, "Exit non-zero if any shadowed response drifted")
+	driftCmd.AddCommand(driftReportCmd)
+	driftCmd.AddCommand(driftResetCmd)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"slices"
@@ -30,6 +31,13 @@ var (
 	statefulListOffset      int
 	statefulListSort        string
 	statefulListOrder       string
+	statefulImportFile      string
+	statefulImportFormat    string
+	statefulImportMode      string
+	statefulImportValidate  bool
+	statefulImportStopOnErr bool
+	statefulExportFormat    string
+	statefulExportOutput    string
 
 	// Custom operation flags
 	customAddFile                string
@@ -142,6 +150,49 @@ Examples:
 	RunE: runStatefulItems,
 }
 
+var statefulImportCmd = &cobra.Command{
+	Use:   "import <name>",
+	Short: "Bulk-load items into a stateful resource",
+	Long: `Bulk-load items into a stateful resource from a JSON array, NDJSON or CSV file.
+
+The file is streamed to the server, so large fixtures (100k+ rows) do not need
+to fit in memory. The format is taken from --format, or inferred from the file
+extension (.json, .ndjson/.jsonl, .csv).
+
+CSV cells are coerced using the resource's validation field types when
+declared; otherwise numbers, booleans, null and JSON objects/arrays are
+detected automatically. Empty cells are treated as absent fields.
+
+Modes:
+  append   Create new items; rows whose ID already exists fail (default)
+  upsert   Create new items and replace existing ones
+  replace  Clear the resource first, then load the file
+
+Examples:
+  mockd stateful import users --file users.json
+  mockd stateful import orders --file orders.csv --mode upsert
+  mockd stateful import events --file events.ndjson --mode replace --validate
+  cat users.jsonl | mockd stateful import users --file - --format ndjson`,
+	Args: cobra.ExactArgs(1),
+	RunE: runStatefulImport,
+}
+
+var statefulExportCmd = &cobra.Command{
+	Use:   "export <name>",
+	Short: "Export all items of a stateful resource",
+	Long: `Export every item of a stateful resource as a JSON array, NDJSON or CSV.
+
+Output goes to stdout unless --output is given. When writing to a file the
+format is inferred from its extension if --format is not set.
+
+Examples:
+  mockd stateful export users > users.json
+  mockd stateful export orders --format csv --output orders.csv
+  mockd stateful export events --output events.ndjson`,
+	Args: cobra.ExactArgs(1),
+	RunE: runStatefulExport,
+}
+
 // --- Custom operation commands ---
 
 var customCmd = &cobra.Command{
@@ -274,6 +325,18 @@ func init() {
 	statefulItemsCmd.Flags().StringVar(&statefulListSort, "sort", "", "Sort field")
 	statefulItemsCmd.Flags().StringVar(&statefulListOrder, "order", "", "Sort order (asc or desc)")
 
+	statefulCmd.AddCommand(statefulImportCmd)
+	statefulImportCmd.Flags().StringVarP(&statefulImportFile, "file", "f", "", "Path to the file to import (- for stdin)")
+	statefulImportCmd.Flags().StringVar(&statefulImportFormat, "format", "", "Input format: json, ndjson or csv (default: from file extension)")
+	statefulImportCmd.Flags().StringVar(&statefulImportMode, "mode", "append", "Import mode: append, upsert or replace")
+	statefulImportCmd.Flags().BoolVar(&statefulImportValidate, "validate", false, "Apply the resource's validation rules to each row")
+	statefulImportCmd.Flags().BoolVar(&statefulImportStopOnErr, "stop-on-error", false, "Stop at the first failing row instead of skipping it")
+	_ = statefulImportCmd.MarkFlagRequired("file")
+
+	statefulCmd.AddCommand(statefulExportCmd)
+	statefulExportCmd.Flags().StringVar(&statefulExportFormat, "format", "", "Output format: json, ndjson or csv (default: from --output extension, else json)")
+	statefulExportCmd.Flags().StringVarP(&statefulExportOutput, "output", "o", "", "Write to this file instead of stdout")
+
 	// Custom operation subcommands
 	statefulCmd.AddCommand(customCmd)
 	customCmd.AddCommand(customListCmd)
@@ -511,6 +574,127 @@ func runStatefulItems(_ *cobra.Command, args []string) error {
 	return nil
 }
 
+// runStatefulImport streams a file into a stateful resource.
+func runStatefulImport(_ *cobra.Command, args []string) error {
+	name := args[0]
+
+	format := statefulImportFormat
+	if format == "" {
+		format = bulkFormatFromPath(statefulImportFile)
+	}
+	if format == "" && statefulImportFile == "-" {
+		return errors.New("--format is required when reading from stdin")
+	}
+	bulkFormat, err := stateful.ResolveBulkFormat(format, "")
+	if err != nil {
+		return err
+	}
+	if _, err := stateful.ParseImportMode(statefulImportMode); err != nil {
+		return err
+	}
+
+	var in io.Reader = os.Stdin
+	if statefulImportFile != "-" {
+		f, err := os.Open(statefulImportFile)
+		if err != nil {
+			return fmt.Errorf("failed to open import file: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		in = f
+	}
+
+	client := NewAdminClientWithAuth(adminURL)
+	result, err := client.ImportStatefulItems(resolvedWorkspace(), name, in, StatefulImportOptions{
+		Format:      string(bulkFormat),
+		Mode:        statefulImportMode,
+		Validate:    statefulImportValidate,
+		StopOnError: statefulImportStopOnErr,
+		ContentType: bulkFormat.ContentType(),
+	})
+	if err != nil {
+		return fmt.Errorf("%s", FormatConnectionError(err))
+	}
+
+	printResult(result, func() {
+		fmt.Printf("Imported into %s (%s, %s): %d rows, %d created, %d updated, %d failed\n",
+			name, result.Format, result.Mode, result.Total, result.Created, result.Updated, result.Failed)
+		for _, rowErr := range result.Errors {
+			if rowErr.ID != "" {
+				fmt.Printf("  row %d (id %s): %s\n", rowErr.Row, rowErr.ID, rowErr.Error)
+			} else {
+				fmt.Printf("  row %d: %s\n", rowErr.Row, rowErr.Error)
+			}
+		}
+		if result.Failed > len(result.Errors) {
+			fmt.Printf("  ... and %d more\n", result.Failed-len(result.Errors))
+		}
+	})
+	return nil
+}
+
+// runStatefulExport streams all items of a stateful resource to stdout or a file.
+func runStatefulExport(_ *cobra.Command, args []string) error {
+	name := args[0]
+
+	format := statefulExportFormat
+	if format == "" {
+		format = bulkFormatFromPath(statefulExportOutput)
+	}
+	bulkFormat, err := stateful.ResolveBulkFormat(format, "")
+	if err != nil {
+		return err
+	}
+
+	client := NewAdminClientWithAuth(adminURL)
+	if statefulExportOutput == "" {
+		if err := client.ExportStatefulItems(resolvedWorkspace(), name, string(bulkFormat), os.Stdout); err != nil {
+			return fmt.Errorf("%s", FormatConnectionError(err))
+		}
+		return nil
+	}
+
+	f, err := os.Create(statefulExportOutput)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	if err := client.ExportStatefulItems(resolvedWorkspace(), name, string(bulkFormat), f); err != nil {
+		_ = f.Close()
+		_ = os.Remove(statefulExportOutput)
+		return fmt.Errorf("%s", FormatConnectionError(err))
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	printResult(struct {
+		Resource string `json:"resource"`
+		Format   string `json:"format"`
+		Output   string `json:"output"`
+	}{
+		Resource: name,
+		Format:   string(bulkFormat),
+		Output:   statefulExportOutput,
+	}, func() {
+		fmt.Printf("Exported %s to %s (%s)\n", name, statefulExportOutput, bulkFormat)
+	})
+	return nil
+}
+
+// bulkFormatFromPath infers an import/export format from a file extension.
+// Returns "" when the extension is not recognised.
+func bulkFormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return string(stateful.BulkFormatCSV)
+	case ".ndjson", ".jsonl":
+		return string(stateful.BulkFormatNDJSON)
+	case ".json":
+		return string(stateful.BulkFormatJSON)
+	default:
+		return ""
+	}
+}
+
 // --- Custom operation command implementations ---
 
 // runCustomList lists all registered custom operations.
@@ -1125,6 +1309,7 @@ func validateCustomOperationLocally(cfg *config.CustomOperationConfig, input map
 		Name:        cfg.Name,
 		Consistency: stateful.ConsistencyMode(cfg.Consistency),
 		Response:    cfg.Response,
+		Idempotency: cfg.Idempotency,
 	}
 	if _, err := stateful.NormalizeCustomOperation(op); err != nil {
 		return nil, err
@@ -940,3 +940,42 @@ func TestLoadError_Error_NoWrappedErr(t *testing.T) {
 	assert.Contains(t, errStr, "/path/to/file.yaml")
 	assert.Contains(t, errStr, "validation failed")
 }
+
+// TestApplyExtendBindings_RejectsInvalidTargets verifies bindings that cannot
+// apply are reported instead of panicking or binding the wrong mock.
+func TestApplyExtendBindings_RejectsInvalidTargets(t *testing.T) {
+	tables := []*TableConfig{{Name: "todos"}}
+	newMocks := func() []*MockConfiguration {
+		return []*MockConfiguration{
+			{OperationID: "api.getTodo", HTTP: &mock.HTTPSpec{
+				Matcher:  &mock.HTTPMatcher{Method: "GET", Path: "/todos/{id}"},
+				Response: &mock.HTTPResponse{StatusCode: 200},
+			}},
+			{OperationID: "api.listTodosRPC"},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		binding *ExtendBinding
+		wantErr string
+	}{
+		{"missing action", &ExtendBinding{Mock: "GET /todos/{id}", Table: "todos"}, "action is required"},
+		{"unknown table", &ExtendBinding{Mock: "GET /todos/{id}", Table: "users", Action: "get"}, `table "users" not found`},
+		{"not an HTTP mock", &ExtendBinding{Mock: "api.listTodosRPC", Table: "todos", Action: "list"}, "not an HTTP mock"},
+		{"custom without operation", &ExtendBinding{Mock: "api.getTodo", Table: "todos", Action: "custom"}, "requires an operation name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ApplyExtendBindings(newMocks(), tables, []*ExtendBinding{tt.binding})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	mocks := newMocks()
+	require.NoError(t, ApplyExtendBindings(mocks, tables, []*ExtendBinding{{Mock: "api.getTodo", Table: "todos", Action: "get"}}))
+	require.NotNil(t, mocks[0].HTTP.StatefulBinding)
+	assert.Equal(t, "todos", mocks[0].HTTP.StatefulBinding.Table)
+	assert.Nil(t, mocks[0].HTTP.Response)
+}
@@ -1,7 +1,9 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/getmockd/mockd/internal/id"
@@ -106,6 +108,96 @@ func convertHTTPMockConfig(cfg *HTTPMockConfig) (*mock.HTTPSpec, error) {
 	return spec, nil
 }
 
+// ApplyExtendBindings binds mocks to stateful tables as described by extend.
+// Each binding finds its target mock by operationId (exact match) or by
+// "METHOD /path", replaces the mock's static response with a StatefulBinding,
+// and resolves its response transform: binding.Response > table.Response > nil.
+func ApplyExtendBindings(mocks []*MockConfiguration, tables []*TableConfig, extend []*ExtendBinding) error {
+	tableMap := make(map[string]*TableConfig, len(tables))
+	for _, table := range tables {
+		tableMap[table.Name] = table
+	}
+
+	// Build indexes for mock lookup
+	opIDIndex := make(map[string]*mock.Mock, len(mocks))
+	methodPathIndex := make(map[string]*mock.Mock, len(mocks))
+	for _, m := range mocks {
+		if m.OperationID != "" {
+			opIDIndex[m.OperationID] = m
+		}
+		if m.HTTP != nil && m.HTTP.Matcher != nil {
+			method := strings.ToUpper(m.HTTP.Matcher.Method)
+			path := m.HTTP.Matcher.Path
+			if method != "" && path != "" {
+				methodPathIndex[method+" "+path] = m
+			}
+		}
+	}
+
+	for _, binding := range extend {
+		if binding.Mock == "" {
+			return errors.New("extend: mock reference is required")
+		}
+		if binding.Table == "" {
+			return fmt.Errorf("extend %q: table is required", binding.Mock)
+		}
+		if binding.Action == "" {
+			return fmt.Errorf("extend %q: action is required", binding.Mock)
+		}
+
+		// Validate table reference
+		table, ok := tableMap[binding.Table]
+		if !ok {
+			return fmt.Errorf("extend %q: table %q not found", binding.Mock, binding.Table)
+		}
+
+		// Find the target mock: operationId first, then "METHOD /path"
+		target := opIDIndex[binding.Mock]
+		if target == nil {
+			target = methodPathIndex[binding.Mock]
+		}
+		if target == nil {
+			return fmt.Errorf("extend %q: mock not found (checked operationId and METHOD /path)", binding.Mock)
+		}
+
+		// Ensure mock has HTTP spec
+		if target.HTTP == nil {
+			return fmt.Errorf("extend %q: mock is not an HTTP mock", binding.Mock)
+		}
+
+		// Validate custom action has operation name
+		if binding.Action == "custom" && binding.Operation == "" {
+			return fmt.Errorf("extend %q: action 'custom' requires an operation name", binding.Mock)
+		}
+
+		// Clear conflicting response types — the mock may have a static
+		// Response from an OpenAPI import or a recording, but extend
+		// replaces it with a stateful binding.
+		target.HTTP.ClearConflictingResponseTypes()
+
+		// Set StatefulBinding on the mock
+		target.HTTP.StatefulBinding = &mock.StatefulBinding{
+			Table:     binding.Table,
+			Action:    binding.Action,
+			Operation: binding.Operation,
+		}
+
+		// Resolve response transform: binding override > table default > nil
+		var responseTransform *ResponseTransform
+		if binding.Response != nil {
+			responseTransform = binding.Response
+		} else if table.Response != nil {
+			responseTransform = table.Response
+		}
+		if responseTransform != nil {
+			target.HTTP.StatefulBinding.Response = &mock.StatefulBindingResponse{
+				Transform: responseTransform,
+			}
+		}
+	}
+	return nil
+}
+
 // generateIDForType generates a prefixed mock ID based on the mock type.
 func generateIDForType(t mock.Type) string {
 	prefix := "mock"
@@ -7,6 +7,9 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
+
+	"github.com/getmockd/mockd/internal/cron"
 )
 
 // SchemaValidationError represents a single config validation error.
@@ -351,6 +354,87 @@ func validateStatefulResource(resource *StatefulResourceConfig, path string, nam
 	if resource.Response != nil {
 		validateResponseTransform(resource.Response, path+".response", result)
 	}
+
+	for i, lt := range resource.Lifecycle {
+		validateLifecycleTransition(lt, fmt.Sprintf("%s.lifecycle[%d]", path, i), result)
+	}
+
+	if resource.Idempotency != nil {
+		validateIdempotency(resource.Idempotency, path+".idempotency", result)
+	}
+
+	if resource.SeedGenerate != nil {
+		validateSeedGenerate(resource.SeedGenerate, path+".seedGenerate", result)
+	}
+
+	if resource.Pagination != nil {
+		validatePagination(resource.Pagination, path+".pagination", result)
+	}
+}
+
+// validatePagination checks a pagination block's style and limits.
+func validatePagination(pc *PaginationConfig, path string, result *SchemaValidationResult) {
+	switch pc.Style {
+	case "", "offset", "page", "cursor", "token":
+	default:
+		result.AddError(path+".style", fmt.Sprintf("unknown style %q (expected offset, page, cursor or token)", pc.Style))
+	}
+	if pc.DefaultLimit < 0 {
+		result.AddError(path+".defaultLimit", "must not be negative")
+	}
+	if pc.MaxLimit < 0 {
+		result.AddError(path+".maxLimit", "must not be negative")
+	}
+}
+
+// validateSeedGenerate checks a seedGenerate block's count and relationships.
+func validateSeedGenerate(sg *SeedGenerateConfig, path string, result *SchemaValidationResult) {
+	if sg.Count <= 0 {
+		result.AddError(path+".count", "must be greater than 0")
+	}
+	for field, rel := range sg.Relationships {
+		if rel == nil || rel.Table == "" {
+			result.AddError(path+".relationships."+field+".table", "required")
+		}
+	}
+}
+
+// validateIdempotency checks an idempotency block's TTL.
+func validateIdempotency(ic *IdempotencyConfig, path string, result *SchemaValidationResult) {
+	if ic.TTL == "" {
+		return
+	}
+	if d, err := time.ParseDuration(ic.TTL); err != nil || d <= 0 {
+		result.AddError(path+".ttl", fmt.Sprintf("invalid duration %q", ic.TTL))
+	}
+}
+
+// validateLifecycleTransition checks a lifecycle transition's required fields and schedule.
+func validateLifecycleTransition(lt *LifecycleTransition, path string, result *SchemaValidationResult) {
+	if lt == nil {
+		result.AddError(path, "must not be null")
+		return
+	}
+	if lt.From == "" {
+		result.AddError(path+".from", "required")
+	}
+	if lt.To == "" {
+		result.AddError(path+".to", "required")
+	}
+	switch {
+	case lt.Delay == "" && lt.Cron == "":
+		result.AddError(path, "one of delay or cron is required")
+	case lt.Delay != "" && lt.Cron != "":
+		result.AddError(path, "delay and cron are mutually exclusive")
+	case lt.Delay != "":
+		if d, err := time.ParseDuration(lt.Delay); err != nil || d < 0 {
+			result.AddError(path+".delay", fmt.Sprintf("invalid duration %q", lt.Delay))
+		}
+	default:
+		if _, err := cron.Parse(lt.Cron); err != nil {
+			result.AddError(path+".cron", err.Error())
+		}
+	}
 }
 
 // validateResponseTransform checks response transform configuration for obvious errors.
@@ -127,6 +127,25 @@ type RateLimitConfig struct {
 	TrustedProxies []string `json:"trustedProxies,omitempty" yaml:"trustedProxies,omitempty"`
 }
 
+// StateIsolationConfig partitions stateful tables per tenant, session or test
+// run. Requests carrying an isolation key get their own copy of every table,
+// created from seed data on first use. The first source that yields a value
+// wins, in the order header, cookie, query, JWT claim. Requests without a key
+// use the shared tables.
+type StateIsolationConfig struct {
+	// Header names a request header holding the key (e.g., "X-Test-Run").
+	Header string `json:"header,omitempty" yaml:"header,omitempty"`
+	// Cookie names a cookie holding the key.
+	Cookie string `json:"cookie,omitempty" yaml:"cookie,omitempty"`
+	// Query names a query parameter holding the key (e.g., "api_key").
+	Query string `json:"query,omitempty" yaml:"query,omitempty"`
+	// JWTClaim names a claim of the bearer token in the Authorization header
+	// (e.g., "tenant_id"). The token signature is not verified.
+	JWTClaim string `json:"jwtClaim,omitempty" yaml:"jwtClaim,omitempty"`
+	// TTL is how long an idle partition is kept (e.g., "30m"). Default: 30m
+	TTL string `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+}
+
 // ServerConfiguration defines the mock server runtime settings and operational parameters.
 type ServerConfiguration struct {
 	// HTTPPort is the port for the HTTP server (0 = disabled unless HTTPAutoPort is true)
@@ -149,6 +168,8 @@ type ServerConfiguration struct {
 	CORS *CORSConfig `json:"cors,omitempty" yaml:"cors,omitempty"`
 	// RateLimit configures rate limiting for the mock engine. Default: disabled.
 	RateLimit *RateLimitConfig `json:"rateLimit,omitempty" yaml:"rateLimit,omitempty"`
+	// StateIsolation partitions stateful tables per isolation key. Default: disabled.
+	StateIsolation *StateIsolationConfig `json:"stateIsolation,omitempty" yaml:"stateIsolation,omitempty"`
 	// LogRequests enables request logging
 	LogRequests bool `json:"logRequests" yaml:"logRequests"`
 	// MaxLogEntries is the maximum number of request log entries to retain
@@ -208,6 +229,9 @@ type MockCollection struct {
 	// CustomOperations defines multi-step custom operations that compose reads, writes,
 	// and expression-evaluated transforms against stateful resources.
 	CustomOperations []*CustomOperationConfig `json:"customOperations,omitempty" yaml:"customOperations,omitempty"`
+	// ChaosProfiles defines user-defined chaos profiles that can be applied by
+	// name like the built-in ones.
+	ChaosProfiles []*ChaosProfileConfig `json:"chaosProfiles,omitempty" yaml:"chaosProfiles,omitempty"`
 	// WebSocketEndpoints defines WebSocket endpoints
 	WebSocketEndpoints []*WebSocketEndpointConfig `json:"websocketEndpoints,omitempty" yaml:"websocketEndpoints,omitempty"`
 }
@@ -239,6 +263,108 @@ type Relationship struct {
 	Field string `json:"field,omitempty" yaml:"field,omitempty"`
 }
 
+// SeedGenerateConfig generates seed rows instead of listing them by hand.
+//
+// Example YAML:
+//
+//	seedGenerate:
+//	  count: 5000
+//	  seed: 42
+//	  fields:
+//	    name: "{{faker.name}}"
+//	    email: "{{faker.email}}"
+//	    orderNo: '{{sequence("orders", 1000)}}'
+//	    amount: "{{random.int(1, 500)}}"
+//	    active: true
+//	  relationships:
+//	    customerId:
+//	      table: customers
+type SeedGenerateConfig struct {
+	// Count is the number of rows to generate.
+	Count int `json:"count" yaml:"count"`
+	// Seed makes generation deterministic: the same seed yields the same rows.
+	// Zero generates different rows on every start.
+	Seed int64 `json:"seed,omitempty" yaml:"seed,omitempty"`
+	// Fields maps field names to values. String values are templates
+	// ({{faker.*}}, {{random.*}}, {{sequence(...)}}, {{uuid}}, ...); other
+	// values are copied as-is. A value that is a single template expression
+	// producing a number or boolean is stored with that type.
+	Fields map[string]interface{} `json:"fields,omitempty" yaml:"fields,omitempty"`
+	// Relationships sets a field to the ID (or Field) of a random item in
+	// another table, which must be registered before this one.
+	Relationships map[string]*Relationship `json:"relationships,omitempty" yaml:"relationships,omitempty"`
+}
+
+// LifecycleTransition moves items from one state to another without a client
+// request, e.g. orders from "pending" to "shipped" 30 seconds after creation.
+// Exactly one of Delay or Cron must be set.
+type LifecycleTransition struct {
+	// Field is the item field holding the state (default: "status").
+	Field string `json:"field,omitempty" yaml:"field,omitempty"`
+	// From is the state an item must be in to transition.
+	From string `json:"from" yaml:"from"`
+	// To is the state the item is moved to.
+	To string `json:"to" yaml:"to"`
+	// Delay is how long an item stays in From before moving, measured from its
+	// last update (e.g., "30s", "5m"). Chained transitions therefore run in sequence.
+	Delay string `json:"delay,omitempty" yaml:"delay,omitempty"`
+	// Cron moves every matching item at each activation of a cron expression
+	// (5 or 6 fields, or a descriptor such as "@hourly" or "@every 10s").
+	Cron string `json:"cron,omitempty" yaml:"cron,omitempty"`
+	// Condition is an optional expr-lang expression that must evaluate to true
+	// for an item to transition. The item is available as `item`
+	// (e.g., `item.amount < 1000`) and the current time as `now`.
+	Condition string `json:"condition,omitempty" yaml:"condition,omitempty"`
+}
+
+// IdempotencyConfig makes retried requests safe: a request repeating a
+// previously seen idempotency key gets the original response replayed, and a
+// request reusing a key with a different payload is rejected with 409 Conflict.
+type IdempotencyConfig struct {
+	// Header is the request header carrying the key (default: "Idempotency-Key").
+	Header string `json:"header,omitempty" yaml:"header,omitempty"`
+	// TTL is how long a key and its stored response are kept (default: "24h").
+	TTL string `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+	// Required rejects requests that do not carry the header with 400 Bad Request.
+	Required bool `json:"required,omitempty" yaml:"required,omitempty"`
+}
+
+// PaginationConfig selects how HTTP list endpoints of a table paginate and
+// which pagination hints they emit. Without it, lists accept limit/offset and
+// Stripe-style starting_after/ending_before.
+type PaginationConfig struct {
+	// Style is the paging scheme: "offset" (limit/offset, default), "page"
+	// (page/per_page), "cursor" (Stripe-style starting_after/ending_before)
+	// or "token" (opaque base64 next_token).
+	Style string `json:"style,omitempty" yaml:"style,omitempty"`
+	// LimitParam is the page size query parameter (default: "limit", or
+	// "per_page" for the page style).
+	LimitParam string `json:"limitParam,omitempty" yaml:"limitParam,omitempty"`
+	// OffsetParam is the offset query parameter for the offset style (default: "offset").
+	OffsetParam string `json:"offsetParam,omitempty" yaml:"offsetParam,omitempty"`
+	// PageParam is the 1-based page number parameter for the page style (default: "page").
+	PageParam string `json:"pageParam,omitempty" yaml:"pageParam,omitempty"`
+	// CursorParam carries the cursor for the cursor and token styles
+	// (default: "starting_after" or "next_token").
+	CursorParam string `json:"cursorParam,omitempty" yaml:"cursorParam,omitempty"`
+	// BeforeParam is the backward cursor parameter for the cursor style (default: "ending_before").
+	BeforeParam string `json:"beforeParam,omitempty" yaml:"beforeParam,omitempty"`
+	// TokenField is the response body field holding the next token for the
+	// token style (default: the cursor parameter name). It is null on the last page.
+	TokenField string `json:"tokenField,omitempty" yaml:"tokenField,omitempty"`
+	// DefaultLimit is the page size when the request gives none (default: 100).
+	DefaultLimit int `json:"defaultLimit,omitempty" yaml:"defaultLimit,omitempty"`
+	// MaxLimit caps the requested page size (0 = no cap).
+	MaxLimit int `json:"maxLimit,omitempty" yaml:"maxLimit,omitempty"`
+	// LinkHeader emits an RFC 5988 Link header with first, prev, next and last URLs.
+	LinkHeader bool `json:"linkHeader,omitempty" yaml:"linkHeader,omitempty"`
+	// TotalCountHeader names a header carrying the total item count (e.g., "X-Total-Count").
+	TotalCountHeader string `json:"totalCountHeader,omitempty" yaml:"totalCountHeader,omitempty"`
+	// Links adds a JSON:API style "links" object (self, first, prev, next, last)
+	// to the list response body.
+	Links bool `json:"links,omitempty" yaml:"links,omitempty"`
+}
+
 // TableConfig defines a stateful data table (pure data, no routing).
 // Tables store items and handle CRUD operations but have no knowledge of
 // protocols, routes, or response formats. Use extend: bindings to attach
@@ -256,6 +382,9 @@ type TableConfig struct {
 	MaxItems int `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
 	// SeedData is initial data loaded on startup and after reset.
 	SeedData []map[string]interface{} `json:"seedData,omitempty" yaml:"seedData,omitempty"`
+	// SeedGenerate generates additional seed rows from field templates, loaded
+	// after SeedData on startup and after reset.
+	SeedGenerate *SeedGenerateConfig `json:"seedGenerate,omitempty" yaml:"seedGenerate,omitempty"`
 	// Validation defines input validation rules.
 	Validation *validation.ValidationConfig `json:"validation,omitempty" yaml:"validation,omitempty"`
 	// Response is the DEFAULT response transform for this table.
@@ -270,6 +399,33 @@ type TableConfig struct {
 	// When a client requests expansion (e.g., ?expand[]=customer), mockd looks up the
 	// field value as an ID in the related table and inlines the full object.
 	Relationships map[string]*Relationship `json:"relationships,omitempty" yaml:"relationships,omitempty"`
+	// Lifecycle defines time-driven state transitions applied to items in the background.
+	Lifecycle []*LifecycleTransition `json:"lifecycle,omitempty" yaml:"lifecycle,omitempty"`
+	// Idempotency enables Idempotency-Key handling for creates and custom actions
+	// bound to this table.
+	Idempotency *IdempotencyConfig `json:"idempotency,omitempty" yaml:"idempotency,omitempty"`
+	// Pagination selects the paging style and hints of HTTP list responses.
+	Pagination *PaginationConfig `json:"pagination,omitempty" yaml:"pagination,omitempty"`
+}
+
+// StatefulResource returns the stateful resource a table registers as. It
+// has no basePath: tables are reached through mocks' stateful bindings.
+func (t *TableConfig) StatefulResource() *StatefulResourceConfig {
+	return &StatefulResourceConfig{
+		Name:          t.Name,
+		IDField:       t.IDField,
+		IDStrategy:    t.IDStrategy,
+		IDPrefix:      t.IDPrefix,
+		ParentField:   t.ParentField,
+		MaxItems:      t.MaxItems,
+		SeedData:      t.SeedData,
+		SeedGenerate:  t.SeedGenerate,
+		Response:      t.Response,
+		Relationships: t.Relationships,
+		Lifecycle:     t.Lifecycle,
+		Idempotency:   t.Idempotency,
+		Pagination:    t.Pagination,
+	}
 }
 
 // ExtendBinding binds a mock to a stateful table with a specific action.
@@ -339,6 +495,8 @@ type WebSocketEndpointConfig struct {
 	// Default: true (allows any origin for development/testing convenience).
 	// Set to false to enforce that Origin matches the Host header.
 	SkipOriginVerify *bool `json:"skipOriginVerify,omitempty" yaml:"skipOriginVerify,omitempty"`
+	// StatefulStream pushes live changes from a stateful table to every connection
+	StatefulStream *mock.StatefulStreamConfig `json:"statefulStream,omitempty" yaml:"statefulStream,omitempty"`
 }
 
 // StatefulResourceConfig defines configuration for a stateful CRUD resource.
@@ -366,6 +524,8 @@ type StatefulResourceConfig struct {
 	MaxItems int `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
 	// SeedData is the initial data to load on startup/reset
 	SeedData []map[string]interface{} `json:"seedData,omitempty" yaml:"seedData,omitempty"`
+	// SeedGenerate generates additional seed rows from field templates
+	SeedGenerate *SeedGenerateConfig `json:"seedGenerate,omitempty" yaml:"seedGenerate,omitempty"`
 	// Validation defines validation rules for CRUD operations
 	Validation *validation.StatefulValidation `json:"validation,omitempty" yaml:"validation,omitempty"`
 	// Response defines how stateful CRUD responses are transformed before serialization.
@@ -374,6 +534,12 @@ type StatefulResourceConfig struct {
 	Response *ResponseTransform `json:"response,omitempty" yaml:"response,omitempty"`
 	// Relationships maps field names to related tables for ?expand[] support.
 	Relationships map[string]*Relationship `json:"relationships,omitempty" yaml:"relationships,omitempty"`
+	// Lifecycle defines time-driven state transitions applied to items in the background.
+	Lifecycle []*LifecycleTransition `json:"lifecycle,omitempty" yaml:"lifecycle,omitempty"`
+	// Idempotency enables Idempotency-Key handling for creates on this resource.
+	Idempotency *IdempotencyConfig `json:"idempotency,omitempty" yaml:"idempotency,omitempty"`
+	// Pagination selects the paging style and hints of HTTP list responses.
+	Pagination *PaginationConfig `json:"pagination,omitempty" yaml:"pagination,omitempty"`
 }
 
 // ResponseTransform defines how stateful resource responses are shaped.
@@ -530,11 +696,35 @@ type CustomOperationConfig struct {
 	Steps []CustomStepConfig `json:"steps" yaml:"steps"`
 	// Response is a map of field → expression that builds the result
 	Response map[string]string `json:"response,omitempty" yaml:"response,omitempty"`
+	// Idempotency enables Idempotency-Key handling when the operation is invoked over HTTP
+	Idempotency *IdempotencyConfig `json:"idempotency,omitempty" yaml:"idempotency,omitempty"`
+}
+
+// ChaosProfileConfig defines a user-defined chaos profile: a named chaos
+// configuration that is applied by name like the built-in profiles.
+//
+// Example YAML:
+//
+//	chaosProfiles:
+//	  - name: partner-degraded
+//	    description: Partner API at its SLO limits
+//	    config:
+//	      global:
+//	        latency: {min: 300ms, max: 1200ms, probability: 1}
+//	        errorRate: {probability: 0.05, statusCodes: [502, 503]}
+type ChaosProfileConfig struct {
+	// Name is the unique profile name. It must not collide with a built-in profile.
+	Name string `json:"name" yaml:"name"`
+	// Description is a short human-readable summary of the profile
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Config is the chaos configuration the profile applies
+	Config chaos.ChaosConfig `json:"config" yaml:"config"`
 }
 
 // CustomStepConfig defines a single step in a custom operation pipeline.
 type CustomStepConfig struct {
-	// Type is the step kind: "read", "update", "delete", "create", "set", "list", "validate"
+	// Type is the step kind: "read", "update", "delete", "create", "set", "list",
+	// "validate", "if", "foreach", "http"
 	Type string `json:"type" yaml:"type"`
 	// Resource is the stateful resource name (for read/update/delete/create/list)
 	Resource string `json:"resource,omitempty" yaml:"resource,omitempty"`
@@ -551,11 +741,29 @@ type CustomStepConfig struct {
 	// Filter contains field → expression mappings for list steps
 	Filter map[string]string `json:"filter,omitempty" yaml:"filter,omitempty"`
 	// Condition is a boolean expression for validate steps (halts on false)
+	// and if steps (selects steps or else)
 	Condition string `json:"condition,omitempty" yaml:"condition,omitempty"`
-	// ErrorMessage is returned when a validate step fails
+	// ErrorMessage is returned when a validate or http step fails
 	ErrorMessage string `json:"errorMessage,omitempty" yaml:"errorMessage,omitempty"`
 	// ErrorStatus is the HTTP status code for validate failures (default: 400)
+	// and http step failures (default: 502)
 	ErrorStatus int `json:"errorStatus,omitempty" yaml:"errorStatus,omitempty"`
+	// Steps are the nested steps of if (condition true) and foreach (per item) steps
+	Steps []CustomStepConfig `json:"steps,omitempty" yaml:"steps,omitempty"`
+	// Else are the nested steps run by an if step when the condition is false
+	Else []CustomStepConfig `json:"else,omitempty" yaml:"else,omitempty"`
+	// Items is an expression resolving to the array a foreach step iterates
+	Items string `json:"items,omitempty" yaml:"items,omitempty"`
+	// Method is the HTTP method for http steps (default: GET, or POST with a body)
+	Method string `json:"method,omitempty" yaml:"method,omitempty"`
+	// URL is the request URL for http steps; supports {{ expr }} placeholders
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+	// Headers are request headers for http steps; values support {{ expr }} placeholders
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	// Body is the request body template for http steps; supports {{ expr }} placeholders
+	Body string `json:"body,omitempty" yaml:"body,omitempty"`
+	// Timeout bounds an http step's request as a duration string (default: 10s)
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
 }
 
 // DefaultServerConfiguration returns a ServerConfiguration with sensible defaults.
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/getmockd/mockd/pkg/chaos"
 	"github.com/getmockd/mockd/pkg/config"
 	"github.com/getmockd/mockd/pkg/httputil"
 	"github.com/getmockd/mockd/pkg/requestlog"
@@ -336,6 +337,7 @@ func entryToAPI(e *requestlog.Entry) *RequestLogEntry {
 		DurationMs:    e.DurationMs,
 		Error:         e.Error,
 		NearMisses:    e.NearMisses,
+		Chaos:         e.Chaos,
 		GRPC:          e.GRPC,
 		WebSocket:     e.WebSocket,
 		SSE:           e.SSE,
@@ -411,6 +413,20 @@ func (s *Server) handleResetChaosStats(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"message": "chaos stats reset"})
 }
 
+func (s *Server) handleGetChaosFaultLog(w http.ResponseWriter, r *http.Request) {
+	log := s.engine.GetChaosFaultLog()
+	if log == nil {
+		writeJSON(w, http.StatusOK, ChaosFaultLog{Records: []chaos.FaultRecord{}})
+		return
+	}
+	writeJSON(w, http.StatusOK, log)
+}
+
+func (s *Server) handleResetChaosFaultLog(w http.ResponseWriter, r *http.Request) {
+	s.engine.ResetChaosFaultLog()
+	writeJSON(w, http.StatusOK, map[string]string{"message": "chaos fault log reset"})
+}
+
 func (s *Server) handleGetStatefulFaultStats(w http.ResponseWriter, _ *http.Request) {
 	stats := s.engine.GetStatefulFaultStats()
 	if stats == nil {
@@ -446,6 +462,57 @@ func (s *Server) handleResetCircuitBreaker(w http.ResponseWriter, r *http.Reques
 	writeJSON(w, http.StatusOK, map[string]string{"message": "circuit breaker reset", "key": key})
 }
 
+// Shadow mode handlers
+
+func (s *Server) handleGetShadow(w http.ResponseWriter, r *http.Request) {
+	cfg := s.engine.GetShadowConfig()
+	if cfg == nil {
+		writeJSON(w, http.StatusOK, ShadowConfig{Enabled: false})
+		return
+	}
+	writeJSON(w, http.StatusOK, cfg)
+}
+
+func (s *Server) handleSetShadow(w http.ResponseWriter, r *http.Request) {
+	limitedBody(w, r)
+	var cfg ShadowConfig
+	if err := decodeJSONBody(r, &cfg, false); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if err := s.engine.SetShadowConfig(&cfg); err != nil {
+		writeError(w, http.StatusBadRequest, "validation_error", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, cfg)
+}
+
+// handleGetDrift returns the drift report, limited to one mock with ?mockId=.
+func (s *Server) handleGetDrift(w http.ResponseWriter, r *http.Request) {
+	report := s.engine.GetDriftReport()
+	if report == nil {
+		report = &DriftReport{}
+	}
+	if report.Mocks == nil {
+		report.Mocks = []MockDrift{}
+	}
+	if mockID := r.URL.Query().Get("mockId"); mockID != "" {
+		mocks := report.Mocks[:0]
+		for _, m := range report.Mocks {
+			if m.MockID == mockID {
+				mocks = append(mocks, m)
+			}
+		}
+		report.Mocks = mocks
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+func (s *Server) handleResetDrift(w http.ResponseWriter, r *http.Request) {
+	s.engine.ResetDrift()
+	writeJSON(w, http.StatusOK, map[string]string{"message": "drift report reset"})
+}
+
 // State handlers
 
 func (s *Server) handleGetState(w http.ResponseWriter, r *http.Request) {
@@ -595,6 +662,156 @@ func (s *Server) handleCreateStatefulItem(w http.ResponseWriter, r *http.Request
 	writeJSON(w, http.StatusCreated, item)
 }
 
+func (s *Server) handleUpdateStatefulItem(w http.ResponseWriter, r *http.Request) {
+	s.mutateStatefulItem(w, r, s.engine.UpdateStatefulItem)
+}
+
+func (s *Server) handlePatchStatefulItem(w http.ResponseWriter, r *http.Request) {
+	s.mutateStatefulItem(w, r, s.engine.PatchStatefulItem)
+}
+
+// mutateStatefulItem is the shared implementation for PUT and PATCH on a single item.
+func (s *Server) mutateStatefulItem(w http.ResponseWriter, r *http.Request, mutate func(string, string, string, map[string]interface{}) (map[string]interface{}, error)) {
+	workspaceID := r.URL.Query().Get("workspaceId")
+	name := r.PathValue("name")
+	id := r.PathValue("id")
+	limitedBody(w, r)
+
+	var data map[string]interface{}
+	if err := decodeJSONBody(r, &data, false); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	item, err := mutate(workspaceID, name, id, data)
+	if err != nil {
+		status, code := mapStatefulLookupError(err)
+		writeError(w, status, code, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, item)
+}
+
+func (s *Server) handleDeleteStatefulItem(w http.ResponseWriter, r *http.Request) {
+	workspaceID := r.URL.Query().Get("workspaceId")
+	name := r.PathValue("name")
+	id := r.PathValue("id")
+
+	if err := s.engine.DeleteStatefulItem(workspaceID, name, id); err != nil {
+		status, code := mapStatefulLookupError(err)
+		writeError(w, status, code, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"deleted":  true,
+		"resource": name,
+		"id":       id,
+	})
+}
+
+// maxStatefulImportBodySize is the body limit for bulk imports (256 MB).
+// Imports are streamed row by row, so this bounds transfer size rather than memory.
+const maxStatefulImportBodySize = 256 << 20
+
+// handleImportStatefulItems bulk-loads items from a JSON array, NDJSON or CSV body.
+// The format comes from ?format= or the Content-Type header; ?mode= selects
+// append (default), upsert or replace.
+func (s *Server) handleImportStatefulItems(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxStatefulImportBodySize)
+	q := r.URL.Query()
+	workspaceID := q.Get("workspaceId")
+	name := r.PathValue("name")
+
+	format, err := stateful.ResolveBulkFormat(q.Get("format"), r.Header.Get("Content-Type"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "validation_error", err.Error())
+		return
+	}
+	mode, err := stateful.ParseImportMode(q.Get("mode"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "validation_error", err.Error())
+		return
+	}
+
+	resp, err := s.engine.ImportStatefulItems(workspaceID, name, r.Body, stateful.ImportOptions{
+		Format:      format,
+		Mode:        mode,
+		Validate:    q.Get("validate") == "true",
+		StopOnError: q.Get("stopOnError") == "true",
+	})
+	if err != nil {
+		// Rows before the failure were already imported; report their counts
+		// in the error details.
+		errResp := ErrorResponse{Message: err.Error()}
+		if resp != nil {
+			errResp.Details = resp
+		}
+		var maxBytesErr *http.MaxBytesError
+		status, code := mapStatefulLookupError(err)
+		switch {
+		case errors.As(err, &maxBytesErr):
+			status, code, errResp.Message = http.StatusRequestEntityTooLarge, "body_too_large", "request body too large"
+		case status == http.StatusBadRequest:
+			code = "invalid_payload"
+		case strings.Contains(err.Error(), "not initialized"):
+			status, code = http.StatusServiceUnavailable, "store_unavailable"
+		}
+		errResp.Error = code
+		writeJSON(w, status, errResp)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleExportStatefulItems streams every item in a resource as a JSON array,
+// NDJSON or CSV (?format=, default json).
+func (s *Server) handleExportStatefulItems(w http.ResponseWriter, r *http.Request) {
+	workspaceID := r.URL.Query().Get("workspaceId")
+	name := r.PathValue("name")
+
+	format, err := stateful.ResolveBulkFormat(r.URL.Query().Get("format"), "")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "validation_error", err.Error())
+		return
+	}
+
+	ew := &exportWriter{w: w, contentType: format.ContentType()}
+	if err := s.engine.ExportStatefulItems(workspaceID, name, ew, format); err != nil {
+		if ew.started {
+			// Headers are already on the wire; all we can do is log and cut the stream.
+			s.log.Warn("stateful export aborted", "resource", name, "error", err)
+			return
+		}
+		status, code := mapStatefulLookupError(err)
+		writeError(w, status, code, err.Error())
+		return
+	}
+	if !ew.started {
+		ew.writeHeader()
+	}
+}
+
+// exportWriter defers writing the response status and Content-Type until the
+// first byte of export data, so lookup errors can still produce a JSON error.
+type exportWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	started     bool
+}
+
+func (e *exportWriter) writeHeader() {
+	e.started = true
+	e.w.Header().Set("Content-Type", e.contentType)
+	e.w.WriteHeader(http.StatusOK)
+}
+
+func (e *exportWriter) Write(p []byte) (int, error) {
+	if !e.started {
+		e.writeHeader()
+	}
+	return e.w.Write(p)
+}
+
 // handleRegisterStatefulResource registers a new stateful resource definition.
 func (s *Server) handleRegisterStatefulResource(w http.ResponseWriter, r *http.Request) {
 	limitedBody(w, r)
@@ -627,6 +844,42 @@ func (s *Server) handleRegisterStatefulResource(w http.ResponseWriter, r *http.R
 	})
 }
 
+// State partition handlers
+
+func (s *Server) handleListStatePartitions(w http.ResponseWriter, r *http.Request) {
+	workspaceID := r.URL.Query().Get("workspaceId")
+	partitions := s.engine.ListStatePartitions(workspaceID)
+	if partitions == nil {
+		partitions = []*StatePartition{}
+	}
+	writeJSON(w, http.StatusOK, StatePartitionListResponse{
+		Partitions: partitions,
+		Count:      len(partitions),
+	})
+}
+
+func (s *Server) handleGetStatePartition(w http.ResponseWriter, r *http.Request) {
+	workspaceID := r.URL.Query().Get("workspaceId")
+	partition, err := s.engine.GetStatePartition(workspaceID, r.PathValue("key"))
+	if err != nil {
+		status, code := mapStatefulLookupError(err)
+		writeError(w, status, code, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, partition)
+}
+
+func (s *Server) handleResetStatePartition(w http.ResponseWriter, r *http.Request) {
+	workspaceID := r.URL.Query().Get("workspaceId")
+	key := r.PathValue("key")
+	if err := s.engine.ResetStatePartition(workspaceID, key); err != nil {
+		status, code := mapStatefulLookupError(err)
+		writeError(w, status, code, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "reset", "partition": key})
+}
+
 // Custom operation handlers
 
 func (s *Server) handleListCustomOperations(w http.ResponseWriter, r *http.Request) {
@@ -2,8 +2,10 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -44,6 +46,9 @@ type mockEngine struct {
 	// Custom operations support
 	customOps map[string]*CustomOperationDetail
 
+	// State partitions keyed by partition key
+	partitions map[string]*StatePartition
+
 	// Error injection for testing error paths
 	addMockErr            error
 	updateMockErr         error
@@ -54,6 +59,8 @@ type mockEngine struct {
 	resetStateErr         error
 	listStatefulItemsErr  error
 	getStatefulItemErr    error
+	mutateStatefulItemErr error
+	importStatefulErr     error
 	// wsSendErr allows injecting a specific error for a connection ID in
 	// SendToWebSocketConnection. Keyed by connection ID.
 	wsSendErr map[string]error
@@ -64,6 +71,7 @@ func newMockEngine() *mockEngine {
 		mocks:       make(map[string]*config.MockConfiguration),
 		requestLogs: make(map[string]*requestlog.Entry),
 		customOps:   make(map[string]*CustomOperationDetail),
+		partitions:  make(map[string]*StatePartition),
 		wsSendErr:   make(map[string]error),
 		running:     true,
 		uptime:      100,
@@ -233,6 +241,26 @@ func (m *mockEngine) ResetChaosStats() {
 	m.chaosStats = &ChaosStats{FaultsByType: make(map[string]int64)}
 }
 
+func (m *mockEngine) GetChaosFaultLog() *ChaosFaultLog {
+	return nil
+}
+
+func (m *mockEngine) ResetChaosFaultLog() {}
+
+func (m *mockEngine) GetShadowConfig() *ShadowConfig {
+	return nil
+}
+
+func (m *mockEngine) SetShadowConfig(cfg *ShadowConfig) error {
+	return cfg.Validate()
+}
+
+func (m *mockEngine) GetDriftReport() *DriftReport {
+	return nil
+}
+
+func (m *mockEngine) ResetDrift() {}
+
 func (m *mockEngine) GetStatefulFaultStats() *StatefulFaultStats {
 	return &StatefulFaultStats{}
 }
@@ -301,6 +329,68 @@ func (m *mockEngine) CreateStatefulItem(workspaceID string, resourceName string,
 	return data, nil
 }
 
+func (m *mockEngine) UpdateStatefulItem(workspaceID string, resourceName, itemID string, data map[string]interface{}) (map[string]interface{}, error) {
+	if m.mutateStatefulItemErr != nil {
+		return nil, m.mutateStatefulItemErr
+	}
+	data["id"] = itemID
+	return data, nil
+}
+
+func (m *mockEngine) PatchStatefulItem(workspaceID string, resourceName, itemID string, data map[string]interface{}) (map[string]interface{}, error) {
+	return m.UpdateStatefulItem(workspaceID, resourceName, itemID, data)
+}
+
+func (m *mockEngine) DeleteStatefulItem(workspaceID string, resourceName, itemID string) error {
+	return m.mutateStatefulItemErr
+}
+
+func (m *mockEngine) ImportStatefulItems(workspaceID string, resourceName string, body io.Reader, opts stateful.ImportOptions) (*StatefulImportResponse, error) {
+	if m.importStatefulErr != nil {
+		return nil, m.importStatefulErr
+	}
+	r := stateful.NewStatefulResource(&stateful.ResourceConfig{Name: resourceName})
+	result, err := r.Import(context.Background(), stateful.NewRowReader(body, opts.Format, nil), opts)
+	return &StatefulImportResponse{
+		Resource: resourceName,
+		Format:   string(opts.Format),
+		Mode:     string(opts.Mode),
+		Total:    result.Total,
+		Created:  result.Created,
+	}, err
+}
+
+func (m *mockEngine) ExportStatefulItems(workspaceID string, resourceName string, w io.Writer, format stateful.BulkFormat) error {
+	if m.getStateResourceErr != nil {
+		return m.getStateResourceErr
+	}
+	_, err := io.WriteString(w, `[{"id":"u1"}]`)
+	return err
+}
+
+func (m *mockEngine) ListStatePartitions(workspaceID string) []*StatePartition {
+	result := make([]*StatePartition, 0, len(m.partitions))
+	for _, p := range m.partitions {
+		result = append(result, p)
+	}
+	return result
+}
+
+func (m *mockEngine) GetStatePartition(workspaceID string, key string) (*StatePartition, error) {
+	if p, ok := m.partitions[key]; ok {
+		return p, nil
+	}
+	return nil, &stateful.NotFoundError{Resource: "partition", ID: key}
+}
+
+func (m *mockEngine) ResetStatePartition(workspaceID string, key string) error {
+	if _, ok := m.partitions[key]; !ok {
+		return &stateful.NotFoundError{Resource: "partition", ID: key}
+	}
+	delete(m.partitions, key)
+	return nil
+}
+
 func (m *mockEngine) ListProtocolHandlers() []*ProtocolHandler {
 	return m.handlers
 }
@@ -880,6 +970,238 @@ func TestStatefulLookupHandlers_Return500ForInternalErrors(t *testing.T) {
 	})
 }
 
+func TestHandleMutateStatefulItem(t *testing.T) {
+	t.Parallel()
+
+	t.Run("put returns updated item", func(t *testing.T) {
+		server := newTestServer(newMockEngine())
+
+		req := httptest.NewRequest(http.MethodPut, "/state/resources/users/items/u1", strings.NewReader(`{"name":"Alice"}`))
+		req.SetPathValue("name", "users")
+		req.SetPathValue("id", "u1")
+		rec := httptest.NewRecorder()
+
+		server.handleUpdateStatefulItem(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var item map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &item))
+		assert.Equal(t, "u1", item["id"])
+		assert.Equal(t, "Alice", item["name"])
+	})
+
+	t.Run("patch maps not found", func(t *testing.T) {
+		engine := newMockEngine()
+		engine.mutateStatefulItemErr = &stateful.NotFoundError{Resource: "users", ID: "u1"}
+		server := newTestServer(engine)
+
+		req := httptest.NewRequest(http.MethodPatch, "/state/resources/users/items/u1", strings.NewReader(`{}`))
+		req.SetPathValue("name", "users")
+		req.SetPathValue("id", "u1")
+		rec := httptest.NewRecorder()
+
+		server.handlePatchStatefulItem(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("delete maps not found", func(t *testing.T) {
+		engine := newMockEngine()
+		engine.mutateStatefulItemErr = &stateful.NotFoundError{Resource: "users", ID: "u1"}
+		server := newTestServer(engine)
+
+		req := httptest.NewRequest(http.MethodDelete, "/state/resources/users/items/u1", nil)
+		req.SetPathValue("name", "users")
+		req.SetPathValue("id", "u1")
+		rec := httptest.NewRecorder()
+
+		server.handleDeleteStatefulItem(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestHandleImportStatefulItems(t *testing.T) {
+	t.Parallel()
+
+	t.Run("format from content type", func(t *testing.T) {
+		server := newTestServer(newMockEngine())
+
+		req := httptest.NewRequest(http.MethodPost, "/state/resources/users/import?mode=upsert", strings.NewReader("id,name\nu1,Alice\n"))
+		req.SetPathValue("name", "users")
+		req.Header.Set("Content-Type", "text/csv")
+		rec := httptest.NewRecorder()
+
+		server.handleImportStatefulItems(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var resp StatefulImportResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "csv", resp.Format)
+		assert.Equal(t, "upsert", resp.Mode)
+		assert.Equal(t, 1, resp.Created)
+	})
+
+	t.Run("rejects unknown mode", func(t *testing.T) {
+		server := newTestServer(newMockEngine())
+
+		req := httptest.NewRequest(http.MethodPost, "/state/resources/users/import?mode=merge", strings.NewReader(`[]`))
+		req.SetPathValue("name", "users")
+		rec := httptest.NewRecorder()
+
+		server.handleImportStatefulItems(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("malformed payload", func(t *testing.T) {
+		server := newTestServer(newMockEngine())
+
+		req := httptest.NewRequest(http.MethodPost, "/state/resources/users/import", strings.NewReader(`{"id":"u1"}`))
+		req.SetPathValue("name", "users")
+		rec := httptest.NewRecorder()
+
+		server.handleImportStatefulItems(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var resp ErrorResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "invalid_payload", resp.Error)
+	})
+
+	t.Run("malformed payload after imported rows", func(t *testing.T) {
+		server := newTestServer(newMockEngine())
+
+		body := "[" + strings.Repeat(`{"name":"Ann"},`, 150) + `{"name":`
+		req := httptest.NewRequest(http.MethodPost, "/state/resources/users/import", strings.NewReader(body))
+		req.SetPathValue("name", "users")
+		rec := httptest.NewRecorder()
+
+		server.handleImportStatefulItems(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var resp struct {
+			Error   string                 `json:"error"`
+			Details StatefulImportResponse `json:"details"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "invalid_payload", resp.Error)
+		assert.Equal(t, 150, resp.Details.Total, "the partial result is reported")
+		assert.Equal(t, 150, resp.Details.Created)
+	})
+
+	t.Run("store unavailable", func(t *testing.T) {
+		engine := newMockEngine()
+		engine.importStatefulErr = errors.New("stateful store not initialized")
+		server := newTestServer(engine)
+
+		req := httptest.NewRequest(http.MethodPost, "/state/resources/users/import", strings.NewReader(`[]`))
+		req.SetPathValue("name", "users")
+		rec := httptest.NewRecorder()
+
+		server.handleImportStatefulItems(rec, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+
+	t.Run("internal error", func(t *testing.T) {
+		engine := newMockEngine()
+		engine.importStatefulErr = context.Canceled
+		server := newTestServer(engine)
+
+		req := httptest.NewRequest(http.MethodPost, "/state/resources/users/import", strings.NewReader(`[]`))
+		req.SetPathValue("name", "users")
+		rec := httptest.NewRecorder()
+
+		server.handleImportStatefulItems(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("unknown resource", func(t *testing.T) {
+		engine := newMockEngine()
+		engine.importStatefulErr = &stateful.NotFoundError{Resource: "users"}
+		server := newTestServer(engine)
+
+		req := httptest.NewRequest(http.MethodPost, "/state/resources/users/import", strings.NewReader(`[]`))
+		req.SetPathValue("name", "users")
+		rec := httptest.NewRecorder()
+
+		server.handleImportStatefulItems(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestHandleExportStatefulItems(t *testing.T) {
+	t.Parallel()
+
+	t.Run("streams with content type", func(t *testing.T) {
+		server := newTestServer(newMockEngine())
+
+		req := httptest.NewRequest(http.MethodGet, "/state/resources/users/export?format=ndjson", nil)
+		req.SetPathValue("name", "users")
+		rec := httptest.NewRecorder()
+
+		server.handleExportStatefulItems(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+	})
+
+	t.Run("lookup error is still JSON", func(t *testing.T) {
+		engine := newMockEngine()
+		engine.getStateResourceErr = &stateful.NotFoundError{Resource: "users"}
+		server := newTestServer(engine)
+
+		req := httptest.NewRequest(http.MethodGet, "/state/resources/users/export", nil)
+		req.SetPathValue("name", "users")
+		rec := httptest.NewRecorder()
+
+		server.handleExportStatefulItems(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		var resp ErrorResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "not_found", resp.Error)
+	})
+}
+
+func TestHandleStatePartitions(t *testing.T) {
+	t.Parallel()
+
+	engine := newMockEngine()
+	engine.partitions["run-1"] = &StatePartition{Key: "run-1", Resources: map[string]int{"users": 3}}
+	server := newTestServer(engine)
+
+	req := httptest.NewRequest(http.MethodGet, "/state/partitions", nil)
+	rec := httptest.NewRecorder()
+	server.handleListStatePartitions(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var list StatePartitionListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	assert.Equal(t, 1, list.Count)
+
+	req = httptest.NewRequest(http.MethodGet, "/state/partitions/run-1", nil)
+	req.SetPathValue("key", "run-1")
+	rec = httptest.NewRecorder()
+	server.handleGetStatePartition(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var info StatePartition
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &info))
+	assert.Equal(t, 3, info.Resources["users"])
+
+	req = httptest.NewRequest(http.MethodDelete, "/state/partitions/run-1", nil)
+	req.SetPathValue("key", "run-1")
+	rec = httptest.NewRecorder()
+	server.handleResetStatePartition(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	server.handleResetStatePartition(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
 // TestHandleListMocks tests the GET /mocks handler.
 func TestHandleListMocks(t *testing.T) {
 	t.Run("returns empty list when no mocks", func(t *testing.T) {
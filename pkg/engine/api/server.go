@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net"
 	"net/http"
@@ -12,6 +13,7 @@ import (
 	"github.com/getmockd/mockd/pkg/config"
 	"github.com/getmockd/mockd/pkg/logging"
 	"github.com/getmockd/mockd/pkg/requestlog"
+	"github.com/getmockd/mockd/pkg/stateful"
 	"github.com/getmockd/mockd/pkg/store"
 )
 
@@ -54,10 +56,18 @@ type EngineController interface {
 	SetChaosConfig(cfg *ChaosConfig) error
 	GetChaosStats() *ChaosStats
 	ResetChaosStats()
+	GetChaosFaultLog() *ChaosFaultLog
+	ResetChaosFaultLog()
 	GetStatefulFaultStats() *StatefulFaultStats
 	TripCircuitBreaker(key string) error
 	ResetCircuitBreaker(key string) error
 
+	// Shadow mode
+	GetShadowConfig() *ShadowConfig
+	SetShadowConfig(cfg *ShadowConfig) error
+	GetDriftReport() *DriftReport
+	ResetDrift()
+
 	// Stateful resources
 	GetStateOverview(workspaceID string) *StateOverview
 	GetStateResource(workspaceID string, name string) (*StatefulResource, error)
@@ -68,6 +78,14 @@ type EngineController interface {
 	ListStatefulItems(workspaceID string, name string, limit, offset int, sort, order string) (*StatefulItemsResponse, error)
 	GetStatefulItem(workspaceID string, resourceName, itemID string) (map[string]interface{}, error)
 	CreateStatefulItem(workspaceID string, resourceName string, data map[string]interface{}) (map[string]interface{}, error)
+	UpdateStatefulItem(workspaceID string, resourceName, itemID string, data map[string]interface{}) (map[string]interface{}, error)
+	PatchStatefulItem(workspaceID string, resourceName, itemID string, data map[string]interface{}) (map[string]interface{}, error)
+	DeleteStatefulItem(workspaceID string, resourceName, itemID string) error
+	ImportStatefulItems(workspaceID string, resourceName string, body io.Reader, opts stateful.ImportOptions) (*StatefulImportResponse, error)
+	ExportStatefulItems(workspaceID string, resourceName string, w io.Writer, format stateful.BulkFormat) error
+	ListStatePartitions(workspaceID string) []*StatePartition
+	GetStatePartition(workspaceID string, key string) (*StatePartition, error)
+	ResetStatePartition(workspaceID string, key string) error
 
 	// Custom operations
 	ListCustomOperations(workspaceID string) []CustomOperationInfo
@@ -207,10 +225,18 @@ func (s *Server) registerRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("PUT /chaos", s.handleSetChaos)
 	mux.HandleFunc("GET /chaos/stats", s.handleGetChaosStats)
 	mux.HandleFunc("POST /chaos/stats/reset", s.handleResetChaosStats)
+	mux.HandleFunc("GET /chaos/fault-log", s.handleGetChaosFaultLog)
+	mux.HandleFunc("POST /chaos/fault-log/reset", s.handleResetChaosFaultLog)
 	mux.HandleFunc("GET /chaos/faults", s.handleGetStatefulFaultStats)
 	mux.HandleFunc("POST /chaos/circuit-breakers/{key}/trip", s.handleTripCircuitBreaker)
 	mux.HandleFunc("POST /chaos/circuit-breakers/{key}/reset", s.handleResetCircuitBreaker)
 
+	// Shadow mode
+	mux.HandleFunc("GET /shadow", s.handleGetShadow)
+	mux.HandleFunc("PUT /shadow", s.handleSetShadow)
+	mux.HandleFunc("GET /shadow/drift", s.handleGetDrift)
+	mux.HandleFunc("POST /shadow/drift/reset", s.handleResetDrift)
+
 	// State management
 	mux.HandleFunc("GET /state", s.handleGetState)
 	mux.HandleFunc("POST /state/reset", s.handleResetState)
@@ -221,6 +247,14 @@ func (s *Server) registerRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /state/resources/{name}/items", s.handleListStatefulItems)
 	mux.HandleFunc("GET /state/resources/{name}/items/{id}", s.handleGetStatefulItem)
 	mux.HandleFunc("POST /state/resources/{name}/items", s.handleCreateStatefulItem)
+	mux.HandleFunc("PUT /state/resources/{name}/items/{id}", s.handleUpdateStatefulItem)
+	mux.HandleFunc("PATCH /state/resources/{name}/items/{id}", s.handlePatchStatefulItem)
+	mux.HandleFunc("DELETE /state/resources/{name}/items/{id}", s.handleDeleteStatefulItem)
+	mux.HandleFunc("POST /state/resources/{name}/import", s.handleImportStatefulItems)
+	mux.HandleFunc("GET /state/resources/{name}/export", s.handleExportStatefulItems)
+	mux.HandleFunc("GET /state/partitions", s.handleListStatePartitions)
+	mux.HandleFunc("GET /state/partitions/{key}", s.handleGetStatePartition)
+	mux.HandleFunc("DELETE /state/partitions/{key}", s.handleResetStatePartition)
 
 	// Custom operations
 	mux.HandleFunc("GET /state/operations", s.handleListCustomOperations)
@@ -28,9 +28,17 @@ type (
 	ChaosRuleConfig                 = types.ChaosRuleConfig
 	ChaosFaultConfig                = types.ChaosFaultConfig
 	ChaosStats                      = types.ChaosStats
+	ChaosFaultLog                   = types.ChaosFaultLog
+	ShadowConfig                    = types.ShadowConfig
+	DriftReport                     = types.DriftReport
+	MockDrift                       = types.MockDrift
 	StatefulResource                = types.StatefulResource
 	StatefulItemsResponse           = types.StatefulItemsResponse
+	StatefulImportResponse          = types.StatefulImportResponse
+	StatefulImportError             = types.StatefulImportError
 	StateOverview                   = types.StateOverview
+	StatePartition                  = types.StatePartition
+	StatePartitionListResponse      = types.StatePartitionListResponse
 	ResetStateRequest               = types.ResetStateRequest
 	ResetStateResponse              = types.ResetStateResponse
 	ProtocolHandler                 = types.ProtocolHandler
@@ -311,18 +311,9 @@ func (cl *ConfigLoader) Export(name string) *config.MockCollection {
 					Name:        name,
 					Consistency: string(op.Consistency),
 					Response:    op.Response,
+					Idempotency: op.Idempotency,
 				}
-				for _, s := range op.Steps {
-					cfg.Steps = append(cfg.Steps, config.CustomStepConfig{
-						Type:     string(s.Type),
-						Resource: s.Resource,
-						ID:       s.ID,
-						As:       s.As,
-						Set:      s.Set,
-						Var:      s.Var,
-						Value:    s.Value,
-					})
-				}
+				cfg.Steps = exportCustomSteps(op.Steps)
 				configs = append(configs, cfg)
 			}
 			collection.CustomOperations = configs
@@ -452,6 +443,16 @@ func (cl *ConfigLoader) mergeServerConfig(src *config.ServerConfiguration) {
 		cl.log.Info("OAuth configured from config file", "count", len(src.OAuth))
 	}
 
+	// State isolation: merge if not already configured
+	if dst.StateIsolation == nil && src.StateIsolation != nil {
+		if err := cl.server.handler.SetStateIsolation(src.StateIsolation); err != nil {
+			cl.log.Error("failed to configure state isolation from config", "error", err)
+		} else {
+			dst.StateIsolation = src.StateIsolation
+			cl.log.Info("state isolation configured from config file")
+		}
+	}
+
 	// Chaos: merge if not already configured
 	if dst.Chaos == nil && src.Chaos != nil {
 		dst.Chaos = src.Chaos
@@ -478,8 +479,27 @@ func (cl *ConfigLoader) registerStatefulResource(workspaceID string, cfg *config
 
 // convertCustomOperation converts a config.CustomOperationConfig to a stateful.CustomOperation.
 func convertCustomOperation(cfg *config.CustomOperationConfig) (*stateful.CustomOperation, error) {
-	steps := make([]stateful.Step, 0, len(cfg.Steps))
-	for _, s := range cfg.Steps {
+	op := &stateful.CustomOperation{
+		Name:        cfg.Name,
+		Consistency: stateful.ConsistencyMode(cfg.Consistency),
+		Steps:       convertCustomSteps(cfg.Steps),
+		Response:    cfg.Response,
+		Idempotency: cfg.Idempotency,
+	}
+	if _, err := stateful.NormalizeCustomOperation(op); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+// convertCustomSteps converts config steps, including nested if/foreach steps,
+// to stateful steps.
+func convertCustomSteps(cfgSteps []config.CustomStepConfig) []stateful.Step {
+	if len(cfgSteps) == 0 {
+		return nil
+	}
+	steps := make([]stateful.Step, 0, len(cfgSteps))
+	for _, s := range cfgSteps {
 		steps = append(steps, stateful.Step{
 			Type:         stateful.StepType(s.Type),
 			Resource:     s.Resource,
@@ -492,16 +512,48 @@ func convertCustomOperation(cfg *config.CustomOperationConfig) (*stateful.Custom
 			Condition:    s.Condition,
 			ErrorMessage: s.ErrorMessage,
 			ErrorStatus:  s.ErrorStatus,
+			Steps:        convertCustomSteps(s.Steps),
+			Else:         convertCustomSteps(s.Else),
+			Items:        s.Items,
+			Method:       s.Method,
+			URL:          s.URL,
+			Headers:      s.Headers,
+			Body:         s.Body,
+			Timeout:      s.Timeout,
 		})
 	}
-	op := &stateful.CustomOperation{
-		Name:        cfg.Name,
-		Consistency: stateful.ConsistencyMode(cfg.Consistency),
-		Steps:       steps,
-		Response:    cfg.Response,
+	return steps
+}
+
+// exportCustomSteps is the inverse of convertCustomSteps, used when exporting
+// registered custom operations back to config.
+func exportCustomSteps(steps []stateful.Step) []config.CustomStepConfig {
+	if len(steps) == 0 {
+		return nil
 	}
-	if _, err := stateful.NormalizeCustomOperation(op); err != nil {
-		return nil, err
+	cfgSteps := make([]config.CustomStepConfig, 0, len(steps))
+	for _, s := range steps {
+		cfgSteps = append(cfgSteps, config.CustomStepConfig{
+			Type:         string(s.Type),
+			Resource:     s.Resource,
+			ID:           s.ID,
+			As:           s.As,
+			Set:          s.Set,
+			Var:          s.Var,
+			Value:        s.Value,
+			Filter:       s.Filter,
+			Condition:    s.Condition,
+			ErrorMessage: s.ErrorMessage,
+			ErrorStatus:  s.ErrorStatus,
+			Steps:        exportCustomSteps(s.Steps),
+			Else:         exportCustomSteps(s.Else),
+			Items:        s.Items,
+			Method:       s.Method,
+			URL:          s.URL,
+			Headers:      s.Headers,
+			Body:         s.Body,
+			Timeout:      s.Timeout,
+		})
 	}
-	return op, nil
+	return cfgSteps
 }
@@ -686,6 +686,9 @@ func TestConvertCustomOperation(t *testing.T) {
 			{"set", stateful.StepSet},
 			{"list", stateful.StepList},
 			{"validate", stateful.StepValidate},
+			{"if", stateful.StepIf},
+			{"foreach", stateful.StepForEach},
+			{"http", stateful.StepHTTP},
 		}
 
 		for _, tt := range tests {
@@ -760,6 +763,55 @@ func TestConvertCustomOperation(t *testing.T) {
 		assert.Equal(t, 422, op.Steps[3].ErrorStatus)
 	})
 
+	t.Run("nested and http steps are propagated and exported", func(t *testing.T) {
+		t.Parallel()
+		cfgSteps := []config.CustomStepConfig{
+			{
+				Type:  "foreach",
+				Items: "charges",
+				As:    "charge",
+				Steps: []config.CustomStepConfig{
+					{
+						Type:    "http",
+						Method:  "POST",
+						URL:     "https://payments.local/charges/{{ charge.id }}/refunds",
+						Headers: map[string]string{"Authorization": "Bearer {{ input.token }}"},
+						Body:    `{"amount": {{ charge.amount }}}`,
+						Timeout: "2s",
+						As:      "refund",
+					},
+					{
+						Type:      "if",
+						Condition: "refund.status == 200",
+						Steps:     []config.CustomStepConfig{{Type: "set", Var: "ok", Value: "true"}},
+						Else:      []config.CustomStepConfig{{Type: "set", Var: "ok", Value: "false"}},
+					},
+				},
+			},
+		}
+
+		op, err := convertCustomOperation(&config.CustomOperationConfig{Name: "Refund", Steps: cfgSteps})
+		require.NoError(t, err)
+		require.Len(t, op.Steps, 1)
+
+		loop := op.Steps[0]
+		assert.Equal(t, "charges", loop.Items)
+		require.Len(t, loop.Steps, 2)
+		call := loop.Steps[0]
+		assert.Equal(t, stateful.StepHTTP, call.Type)
+		assert.Equal(t, "POST", call.Method)
+		assert.Equal(t, "https://payments.local/charges/{{ charge.id }}/refunds", call.URL)
+		assert.Equal(t, map[string]string{"Authorization": "Bearer {{ input.token }}"}, call.Headers)
+		assert.Equal(t, `{"amount": {{ charge.amount }}}`, call.Body)
+		assert.Equal(t, "2s", call.Timeout)
+		branch := loop.Steps[1]
+		require.Len(t, branch.Steps, 1)
+		require.Len(t, branch.Else, 1)
+		assert.Equal(t, "false", branch.Else[0].Value)
+
+		assert.Equal(t, cfgSteps, exportCustomSteps(op.Steps))
+	})
+
 	t.Run("invalid consistency mode returns error", func(t *testing.T) {
 		t.Parallel()
 		cfg := &config.CustomOperationConfig{
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 
 	"github.com/getmockd/mockd/pkg/api/types"
 	"github.com/getmockd/mockd/pkg/chaos"
@@ -198,6 +199,9 @@ func (a *ControlAPIAdapter) GetChaosStats() *api.ChaosStats {
 		ErrorsInjected:   stats.ErrorsInjected,
 		TimeoutsInjected: stats.TimeoutsInjected,
 		FaultsByType:     faultsByType,
+
+		MessagesProcessed: stats.MessagesProcessed,
+		MessagesDropped:   stats.MessagesDropped,
 	}
 }
 
@@ -209,6 +213,44 @@ func (a *ControlAPIAdapter) ResetChaosStats() {
 	}
 }
 
+// GetChaosFaultLog implements api.EngineController.
+func (a *ControlAPIAdapter) GetChaosFaultLog() *api.ChaosFaultLog {
+	injector := a.server.ChaosInjector()
+	if injector == nil {
+		return nil
+	}
+	log := injector.FaultLog()
+	return &log
+}
+
+// ResetChaosFaultLog implements api.EngineController.
+func (a *ControlAPIAdapter) ResetChaosFaultLog() {
+	injector := a.server.ChaosInjector()
+	if injector != nil {
+		injector.ResetFaultLog()
+	}
+}
+
+// GetShadowConfig implements api.EngineController.
+func (a *ControlAPIAdapter) GetShadowConfig() *api.ShadowConfig {
+	return a.server.ShadowConfig()
+}
+
+// SetShadowConfig implements api.EngineController.
+func (a *ControlAPIAdapter) SetShadowConfig(cfg *api.ShadowConfig) error {
+	return a.server.SetShadowConfig(cfg)
+}
+
+// GetDriftReport implements api.EngineController.
+func (a *ControlAPIAdapter) GetDriftReport() *api.DriftReport {
+	return a.server.DriftReport()
+}
+
+// ResetDrift implements api.EngineController.
+func (a *ControlAPIAdapter) ResetDrift() {
+	a.server.ResetDrift()
+}
+
 // GetStatefulFaultStats implements api.EngineController.
 func (a *ControlAPIAdapter) GetStatefulFaultStats() *api.StatefulFaultStats {
 	injector := a.server.ChaosInjector()
@@ -393,6 +435,57 @@ func (a *ControlAPIAdapter) ResetState(workspaceID string, resourceName string)
 	}, nil
 }
 
+// ListStatePartitions implements api.EngineController.
+func (a *ControlAPIAdapter) ListStatePartitions(workspaceID string) []*api.StatePartition {
+	store := a.server.StatefulStore()
+	if store == nil {
+		return nil
+	}
+	infos := store.Partitions(workspaceID)
+	result := make([]*api.StatePartition, 0, len(infos))
+	for _, info := range infos {
+		result = append(result, toAPIStatePartition(info))
+	}
+	return result
+}
+
+// GetStatePartition implements api.EngineController.
+func (a *ControlAPIAdapter) GetStatePartition(workspaceID string, key string) (*api.StatePartition, error) {
+	store := a.server.StatefulStore()
+	if store == nil {
+		return nil, ErrStatefulStoreNotInitialized
+	}
+	partition := store.Partition(workspaceID, key)
+	if partition == nil {
+		return nil, &stateful.NotFoundError{Resource: "partition", ID: key}
+	}
+	return toAPIStatePartition(partition), nil
+}
+
+// toAPIStatePartition converts a stateful.PartitionInfo to its API type.
+func toAPIStatePartition(info *stateful.PartitionInfo) *api.StatePartition {
+	return &api.StatePartition{
+		Key:        info.Key,
+		Created:    info.Created,
+		LastAccess: info.LastAccess,
+		ExpiresAt:  info.ExpiresAt,
+		Resources:  info.Resources,
+	}
+}
+
+// ResetStatePartition implements api.EngineController. The partition is
+// dropped; its next request starts again from seed data.
+func (a *ControlAPIAdapter) ResetStatePartition(workspaceID string, key string) error {
+	store := a.server.StatefulStore()
+	if store == nil {
+		return ErrStatefulStoreNotInitialized
+	}
+	if !store.DropPartition(workspaceID, key) {
+		return &stateful.NotFoundError{Resource: "partition", ID: key}
+	}
+	return nil
+}
+
 // RegisterStatefulResource implements api.EngineController.
 func (a *ControlAPIAdapter) RegisterStatefulResource(workspaceID string, cfg *config.StatefulResourceConfig) error {
 	if cfg == nil {
@@ -483,6 +576,101 @@ func (a *ControlAPIAdapter) CreateStatefulItem(workspaceID string, resourceName
 	return item.ToJSON(), nil
 }
 
+// UpdateStatefulItem implements api.EngineController.
+func (a *ControlAPIAdapter) UpdateStatefulItem(workspaceID string, resourceName, itemID string, data map[string]interface{}) (map[string]interface{}, error) {
+	return a.executeStatefulItem(workspaceID, resourceName, itemID, stateful.ActionUpdate, data)
+}
+
+// PatchStatefulItem implements api.EngineController.
+func (a *ControlAPIAdapter) PatchStatefulItem(workspaceID string, resourceName, itemID string, data map[string]interface{}) (map[string]interface{}, error) {
+	return a.executeStatefulItem(workspaceID, resourceName, itemID, stateful.ActionPatch, data)
+}
+
+// DeleteStatefulItem implements api.EngineController.
+func (a *ControlAPIAdapter) DeleteStatefulItem(workspaceID string, resourceName, itemID string) error {
+	_, err := a.executeStatefulItem(workspaceID, resourceName, itemID, stateful.ActionDelete, nil)
+	return err
+}
+
+// executeStatefulItem routes a single-item mutation through the bridge so that
+// validation and observer hooks behave exactly as they do for protocol traffic.
+func (a *ControlAPIAdapter) executeStatefulItem(workspaceID, resourceName, itemID string, action stateful.Action, data map[string]interface{}) (map[string]interface{}, error) {
+	bridge := a.server.StatefulBridge()
+	if bridge == nil {
+		return nil, ErrStatefulStoreNotInitialized
+	}
+
+	result := bridge.Execute(context.Background(), &stateful.OperationRequest{
+		WorkspaceID: workspaceID,
+		Resource:    resourceName,
+		Action:      action,
+		ResourceID:  itemID,
+		Data:        data,
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+	return result.Item.ToJSON(), nil
+}
+
+// ImportStatefulItems implements api.EngineController.
+func (a *ControlAPIAdapter) ImportStatefulItems(workspaceID string, resourceName string, body io.Reader, opts stateful.ImportOptions) (*api.StatefulImportResponse, error) {
+	store := a.server.StatefulStore()
+	if store == nil {
+		return nil, ErrStatefulStoreNotInitialized
+	}
+
+	resource := store.Get(workspaceID, resourceName)
+	if resource == nil {
+		return nil, &stateful.NotFoundError{Resource: resourceName}
+	}
+
+	// A failed import still returns its result: rows before the failure were
+	// already written and callers need the counts.
+	rows := stateful.NewRowReader(body, opts.Format, resource.FieldTypes())
+	result, err := resource.Import(context.Background(), rows, opts)
+	if result == nil {
+		return nil, err
+	}
+
+	resp := &api.StatefulImportResponse{
+		Resource: result.Resource,
+		Format:   string(result.Format),
+		Mode:     string(result.Mode),
+		Total:    result.Total,
+		Created:  result.Created,
+		Updated:  result.Updated,
+		Failed:   result.Failed,
+	}
+	for _, rowErr := range result.Errors {
+		resp.Errors = append(resp.Errors, api.StatefulImportError{
+			Row:   rowErr.Row,
+			ID:    rowErr.ID,
+			Error: rowErr.Message,
+		})
+	}
+	return resp, err
+}
+
+// ExportStatefulItems implements api.EngineController.
+func (a *ControlAPIAdapter) ExportStatefulItems(workspaceID string, resourceName string, w io.Writer, format stateful.BulkFormat) error {
+	store := a.server.StatefulStore()
+	if store == nil {
+		return ErrStatefulStoreNotInitialized
+	}
+
+	resource := store.Get(workspaceID, resourceName)
+	if resource == nil {
+		return &stateful.NotFoundError{Resource: resourceName}
+	}
+
+	_, err := resource.Export(w, format)
+	return err
+}
+
 // ListProtocolHandlers implements api.EngineController.
 func (a *ControlAPIAdapter) ListProtocolHandlers() []*api.ProtocolHandler {
 	registry := a.server.ProtocolRegistry()
@@ -0,0 +1,94 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/getmockd/mockd/pkg/graphql"
+	"github.com/getmockd/mockd/pkg/mock"
+	"github.com/getmockd/mockd/pkg/recording"
+)
+
+// TestConvertGraphQLResolverConfig_CarriesVariants verifies that resolver
+// match variants survive the conversion into the executor's config in order.
+func TestConvertGraphQLResolverConfig_CarriesVariants(t *testing.T) {
+	src := mock.ResolverConfig{
+		Match:    &mock.ResolverMatch{Args: map[string]any{"id": "1"}},
+		Response: map[string]any{"id": "1"},
+		Variants: []mock.ResolverConfig{
+			{
+				Match: &mock.ResolverMatch{Args: map[string]any{"id": "404"}},
+				Error: &mock.GraphQLErrorConfig{Message: "not found"},
+				// Nested variants are dropped.
+				Variants: []mock.ResolverConfig{{Response: "ignored"}},
+			},
+			{Response: map[string]any{"id": "0"}},
+		},
+	}
+
+	got := convertGraphQLResolverConfig(src)
+
+	require.NotNil(t, got.Match)
+	assert.Equal(t, "1", got.Match.Args["id"])
+	require.Len(t, got.Variants, 2)
+	require.NotNil(t, got.Variants[0].Error)
+	assert.Equal(t, "not found", got.Variants[0].Error.Message)
+	assert.Empty(t, got.Variants[0].Variants)
+	assert.Nil(t, got.Variants[1].Match, "default variant has no match")
+}
+
+// TestConvertedGraphQLRecordings_Replay verifies that a mock converted from
+// recorded GraphQL traffic answers the recorded queries with the recorded
+// data, choosing the variant by arguments.
+func TestConvertedGraphQLRecordings_Replay(t *testing.T) {
+	const query = `query GetUser($id: ID!) { user(id: $id) { id profile: name } }`
+	record := func(id, response string) *recording.Recording {
+		body, err := json.Marshal(map[string]any{"query": query, "variables": map[string]any{"id": id}})
+		require.NoError(t, err)
+		rec := recording.NewRecording("session")
+		rec.Request = recording.RecordedRequest{
+			Method:  http.MethodPost,
+			Path:    "/graphql",
+			Headers: http.Header{"Content-Type": {"application/json"}},
+			Body:    body,
+		}
+		rec.Response = recording.RecordedResponse{StatusCode: http.StatusOK, Body: []byte(response)}
+		return rec
+	}
+
+	result := recording.ConvertGraphQLRecordings([]*recording.Recording{
+		record("1", `{"data":{"user":{"id":"1","profile":"Ann"}}}`),
+		record("2", `{"data":{"user":null},"errors":[{"message":"user not found","path":["user"]}]}`),
+	}, recording.DefaultGraphQLConvertOptions())
+	require.Len(t, result.Mocks, 1)
+	spec := result.Mocks[0].GraphQL
+
+	schema, err := graphql.ParseSchema(spec.Schema)
+	require.NoError(t, err)
+	cfg := &graphql.GraphQLConfig{Resolvers: make(map[string]graphql.ResolverConfig)}
+	for path, resolver := range spec.Resolvers {
+		cfg.Resolvers[path] = convertGraphQLResolverConfig(resolver)
+	}
+	executor := graphql.NewExecutor(schema, cfg)
+
+	resp := executor.Execute(context.Background(), &graphql.GraphQLRequest{
+		Query:     query,
+		Variables: map[string]any{"id": "1"},
+	})
+	require.Empty(t, resp.Errors)
+	data, err := json.Marshal(resp.Data)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"user":{"id":"1","profile":"Ann"}}`, string(data))
+
+	resp = executor.Execute(context.Background(), &graphql.GraphQLRequest{
+		Query:     query,
+		Variables: map[string]any{"id": "2"},
+	})
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "user not found", resp.Errors[0].Message)
+}
@@ -0,0 +1,126 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/getmockd/mockd/pkg/config"
+	"github.com/getmockd/mockd/pkg/graphql"
+	"github.com/getmockd/mockd/pkg/stateful"
+)
+
+// graphqlStatefulAdapter implements graphql.StatefulExecutor by delegating to stateful.Bridge.
+// This adapter lives in the engine package to avoid an import cycle between graphql and stateful.
+type graphqlStatefulAdapter struct {
+	bridge *stateful.Bridge
+}
+
+// newGraphQLStatefulAdapter creates a new adapter wrapping the given bridge.
+func newGraphQLStatefulAdapter(bridge *stateful.Bridge) *graphqlStatefulAdapter {
+	return &graphqlStatefulAdapter{bridge: bridge}
+}
+
+// ExecuteStateful implements graphql.StatefulExecutor.
+// It translates graphql.StatefulRequest → stateful.OperationRequest,
+// calls Bridge.Execute(), and translates stateful.OperationResult → graphql.StatefulResult.
+// Items pass through the table's response transforms so GraphQL and REST
+// façades over the same table return the same shapes.
+func (a *graphqlStatefulAdapter) ExecuteStateful(ctx context.Context, req *graphql.StatefulRequest) *graphql.StatefulResult {
+	if a.bridge == nil || req == nil {
+		return &graphql.StatefulResult{
+			Error: &graphql.GraphQLError{
+				Message:    "stateful bridge is not configured",
+				Extensions: map[string]interface{}{"code": "INTERNAL_SERVER_ERROR"},
+			},
+		}
+	}
+
+	workspaceID := contextWorkspace(ctx, req.WorkspaceID)
+	opReq := &stateful.OperationRequest{
+		WorkspaceID:   workspaceID,
+		Resource:      req.Resource,
+		Action:        stateful.Action(req.Action),
+		OperationName: req.OperationName,
+		ResourceID:    req.ResourceID,
+		Data:          req.Data,
+	}
+
+	if req.Filter != nil {
+		opReq.Filter = &stateful.QueryFilter{
+			Limit:   req.Filter.Limit,
+			Offset:  req.Filter.Offset,
+			Sort:    req.Filter.Sort,
+			Order:   req.Filter.Order,
+			Filters: req.Filter.Filters,
+		}
+	}
+
+	result := a.bridge.Execute(ctx, opReq)
+	if result.Error != nil {
+		return &graphql.StatefulResult{Error: errorToGraphQLError(result.Error)}
+	}
+
+	// Custom operations build their own response; only table items are transformed.
+	var responseCfg *config.ResponseTransform
+	if req.Resource != "" && opReq.Action != stateful.ActionCustom {
+		responseCfg = a.bridge.GetResponseConfig(workspaceID, req.Resource)
+	}
+
+	gqlResult := &graphql.StatefulResult{}
+	if result.Item != nil {
+		if opReq.Action == stateful.ActionCustom {
+			gqlResult.Item = result.Item.Data
+		} else {
+			gqlResult.Item = stateful.TransformItem(result.Item.ToJSON(), responseCfg)
+		}
+	}
+	if result.List != nil {
+		gqlResult.Items = make([]map[string]interface{}, len(result.List.Data))
+		for i, item := range result.List.Data {
+			gqlResult.Items[i] = stateful.TransformItem(item, responseCfg)
+		}
+		gqlResult.Meta = &graphql.StatefulListMeta{
+			Total:  result.List.Meta.Total,
+			Count:  result.List.Meta.Count,
+			Offset: result.List.Meta.Offset,
+			Limit:  result.List.Meta.Limit,
+		}
+	}
+
+	return gqlResult
+}
+
+// StatefulIDField implements graphql.StatefulIDFieldProvider.
+func (a *graphqlStatefulAdapter) StatefulIDField(ctx context.Context, workspaceID, table string) string {
+	if a.bridge == nil {
+		return ""
+	}
+	resource := a.bridge.Store().Get(contextWorkspace(ctx, workspaceID), table)
+	if resource == nil {
+		return ""
+	}
+	return resource.IDField()
+}
+
+// errorToGraphQLError converts a stateful error to a GraphQL error whose
+// extensions.code follows common server conventions.
+func errorToGraphQLError(err error) *graphql.GraphQLError {
+	var code string
+	switch stateful.GetErrorCode(err) {
+	case stateful.ErrCodeNotFound:
+		code = "NOT_FOUND"
+	case stateful.ErrCodeConflict:
+		code = "CONFLICT"
+	case stateful.ErrCodeValidation:
+		code = "BAD_USER_INPUT"
+	case stateful.ErrCodePayloadTooLarge:
+		code = "PAYLOAD_TOO_LARGE"
+	case stateful.ErrCodeCapacityExceeded:
+		code = "CAPACITY_EXCEEDED"
+	default:
+		code = "INTERNAL_SERVER_ERROR"
+	}
+	return &graphql.GraphQLError{
+		Message:    err.Error(),
+		Extensions: map[string]interface{}{"code": code},
+	}
+}
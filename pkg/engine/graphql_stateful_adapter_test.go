@@ -0,0 +1,216 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getmockd/mockd/pkg/config"
+	"github.com/getmockd/mockd/pkg/graphql"
+	"github.com/getmockd/mockd/pkg/mock"
+	"github.com/getmockd/mockd/pkg/stateful"
+)
+
+func TestGraphQLStatefulAdapter_Create_Get_List_Delete(t *testing.T) {
+	store := stateful.NewStateStore()
+	if err := store.Register("", &config.StatefulResourceConfig{
+		Name: "users",
+	}); err != nil {
+		t.Fatalf("failed to register resource: %v", err)
+	}
+
+	adapter := newGraphQLStatefulAdapter(stateful.NewBridge(store))
+	ctx := context.Background()
+
+	// CREATE
+	createResult := adapter.ExecuteStateful(ctx, &graphql.StatefulRequest{
+		Resource: "users",
+		Action:   graphql.StatefulActionCreate,
+		Data:     map[string]interface{}{"name": "Alice", "role": "admin"},
+	})
+	if createResult.Error != nil {
+		t.Fatalf("create failed: %v", createResult.Error.Message)
+	}
+	userID, ok := createResult.Item["id"].(string)
+	if !ok || userID == "" {
+		t.Fatalf("expected non-empty string ID, got %v", createResult.Item["id"])
+	}
+
+	// GET
+	getResult := adapter.ExecuteStateful(ctx, &graphql.StatefulRequest{
+		Resource:   "users",
+		Action:     graphql.StatefulActionGet,
+		ResourceID: userID,
+	})
+	if getResult.Error != nil {
+		t.Fatalf("get failed: %v", getResult.Error.Message)
+	}
+	if getResult.Item["name"] != "Alice" {
+		t.Errorf("expected name=Alice, got %v", getResult.Item["name"])
+	}
+
+	// LIST with filter
+	listResult := adapter.ExecuteStateful(ctx, &graphql.StatefulRequest{
+		Resource: "users",
+		Action:   graphql.StatefulActionList,
+		Filter:   &graphql.StatefulFilter{Limit: 10, Filters: map[string]string{"role": "admin"}},
+	})
+	if listResult.Error != nil {
+		t.Fatalf("list failed: %v", listResult.Error.Message)
+	}
+	if len(listResult.Items) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(listResult.Items))
+	}
+	if listResult.Meta == nil || listResult.Meta.Total != 1 {
+		t.Errorf("expected meta.total=1, got %+v", listResult.Meta)
+	}
+
+	// DELETE
+	deleteResult := adapter.ExecuteStateful(ctx, &graphql.StatefulRequest{
+		Resource:   "users",
+		Action:     graphql.StatefulActionDelete,
+		ResourceID: userID,
+	})
+	if deleteResult.Error != nil {
+		t.Fatalf("delete failed: %v", deleteResult.Error.Message)
+	}
+
+	// GET after delete
+	missing := adapter.ExecuteStateful(ctx, &graphql.StatefulRequest{
+		Resource:   "users",
+		Action:     graphql.StatefulActionGet,
+		ResourceID: userID,
+	})
+	if missing.Error == nil {
+		t.Fatal("expected error after delete")
+	}
+	if missing.Error.Extensions["code"] != "NOT_FOUND" {
+		t.Errorf("expected code NOT_FOUND, got %v", missing.Error.Extensions["code"])
+	}
+}
+
+func TestGraphQLStatefulAdapter_IsolationPartition(t *testing.T) {
+	store := stateful.NewStateStore()
+	if err := store.Register("", &config.StatefulResourceConfig{Name: "users"}); err != nil {
+		t.Fatalf("failed to register resource: %v", err)
+	}
+	adapter := newGraphQLStatefulAdapter(stateful.NewBridge(store))
+
+	ctx := stateful.ContextWithPartition(context.Background(), "run-a")
+	result := adapter.ExecuteStateful(ctx, &graphql.StatefulRequest{
+		Resource: "users",
+		Action:   graphql.StatefulActionCreate,
+		Data:     map[string]interface{}{"id": "u1", "name": "Alice"},
+	})
+	if result.Error != nil {
+		t.Fatalf("create failed: %v", result.Error.Message)
+	}
+
+	if got := store.Get("", "users").Count(); got != 0 {
+		t.Errorf("shared table has %d items, want 0", got)
+	}
+	if got := store.Get(stateful.PartitionWorkspaceID("", "run-a"), "users").Count(); got != 1 {
+		t.Errorf("partition table has %d items, want 1", got)
+	}
+}
+
+func TestGraphQLStatefulAdapter_NilBridge(t *testing.T) {
+	adapter := newGraphQLStatefulAdapter(nil)
+	result := adapter.ExecuteStateful(context.Background(), &graphql.StatefulRequest{
+		Resource: "users",
+		Action:   graphql.StatefulActionGet,
+	})
+	if result.Error == nil {
+		t.Fatal("expected error for nil bridge")
+	}
+	if result.Error.Extensions["code"] != "INTERNAL_SERVER_ERROR" {
+		t.Errorf("expected code INTERNAL_SERVER_ERROR, got %v", result.Error.Extensions["code"])
+	}
+}
+
+func TestGraphQLStatefulAdapter_StatefulIDField(t *testing.T) {
+	store := stateful.NewStateStore()
+	if err := store.Register("", &config.StatefulResourceConfig{Name: "users", IDField: "username"}); err != nil {
+		t.Fatalf("failed to register resource: %v", err)
+	}
+	adapter := newGraphQLStatefulAdapter(stateful.NewBridge(store))
+	ctx := context.Background()
+
+	if got := adapter.StatefulIDField(ctx, "", "users"); got != "username" {
+		t.Errorf("StatefulIDField(users) = %q, want username", got)
+	}
+	if got := adapter.StatefulIDField(ctx, "", "missing"); got != "" {
+		t.Errorf("StatefulIDField(missing) = %q, want empty", got)
+	}
+	if got := newGraphQLStatefulAdapter(nil).StatefulIDField(ctx, "", "users"); got != "" {
+		t.Errorf("StatefulIDField with nil bridge = %q, want empty", got)
+	}
+}
+
+func TestGraphQLStatefulAdapter_ErrorCodes(t *testing.T) {
+	tests := []struct {
+		err  error
+		code string
+	}{
+		{&stateful.NotFoundError{Resource: "users", ID: "1"}, "NOT_FOUND"},
+		{&stateful.ConflictError{Resource: "users", ID: "1"}, "CONFLICT"},
+		{&stateful.ValidationError{Message: "bad"}, "BAD_USER_INPUT"},
+		{context.Canceled, "INTERNAL_SERVER_ERROR"},
+	}
+	for _, tt := range tests {
+		if got := errorToGraphQLError(tt.err).Extensions["code"]; got != tt.code {
+			t.Errorf("errorToGraphQLError(%T) code = %v, want %s", tt.err, got, tt.code)
+		}
+	}
+}
+
+func TestGraphQLStatefulAdapter_SharedTableWithREST(t *testing.T) {
+	// One table backs both the REST and GraphQL façades: items seeded into the
+	// table are visible through GraphQL, including nested relationships.
+	store := stateful.NewStateStore()
+	for _, cfg := range []*config.StatefulResourceConfig{
+		{Name: "users", SeedData: []map[string]interface{}{{"id": "u1", "name": "Alice"}}},
+		{Name: "orders", SeedData: []map[string]interface{}{
+			{"id": "o1", "userId": "u1", "total": float64(10)},
+			{"id": "o2", "userId": "u2", "total": float64(20)},
+		}},
+	} {
+		if err := store.Register("", cfg); err != nil {
+			t.Fatalf("failed to register resource: %v", err)
+		}
+	}
+
+	schema, err := graphql.ParseSchema(`
+		type Query { user(id: ID!): User }
+		type User { id: ID! name: String! orders: [Order!]! }
+		type Order { id: ID! total: Float! }
+	`)
+	if err != nil {
+		t.Fatalf("ParseSchema() error = %v", err)
+	}
+	executor := graphql.NewExecutor(schema, &graphql.GraphQLConfig{
+		Resolvers: map[string]graphql.ResolverConfig{
+			"Query.user":  {StatefulBinding: &mock.GraphQLStatefulBinding{Table: "users", Action: "get"}},
+			"User.orders": {StatefulBinding: &mock.GraphQLStatefulBinding{Table: "orders", Action: "list", ForeignKey: "userId"}},
+		},
+	})
+	executor.SetStatefulExecutor(newGraphQLStatefulAdapter(stateful.NewBridge(store)))
+
+	resp := executor.Execute(context.Background(), &graphql.GraphQLRequest{
+		Query: `{ user(id: "u1") { name orders { id total } } }`,
+	})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("Execute() returned errors: %v", resp.Errors[0].Message)
+	}
+
+	user := resp.Data.(map[string]interface{})["user"].(map[string]interface{})
+	if user["name"] != "Alice" {
+		t.Errorf("expected name=Alice, got %v", user["name"])
+	}
+	orders, ok := user["orders"].([]interface{})
+	if !ok || len(orders) != 1 {
+		t.Fatalf("expected 1 order, got %v", user["orders"])
+	}
+	if orders[0].(map[string]interface{})["id"] != "o1" {
+		t.Errorf("expected order o1, got %v", orders[0])
+	}
+}
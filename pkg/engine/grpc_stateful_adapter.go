@@ -0,0 +1,177 @@
+package engine
+
+import (
+	"context"
+	"errors"
+
+	"github.com/getmockd/mockd/pkg/config"
+	"github.com/getmockd/mockd/pkg/grpc"
+	"github.com/getmockd/mockd/pkg/stateful"
+)
+
+// grpcStatefulAdapter implements grpc.StatefulExecutor by delegating to stateful.Bridge.
+// This adapter lives in the engine package to avoid an import cycle between grpc and stateful.
+type grpcStatefulAdapter struct {
+	bridge *stateful.Bridge
+
+	// isolation returns the active state isolation config, if any. gRPC
+	// requests do not pass through the HTTP handler, so the isolation key is
+	// read from incoming metadata here.
+	isolation func() *config.StateIsolationConfig
+}
+
+// newGRPCStatefulAdapter creates a new adapter wrapping the given bridge.
+func newGRPCStatefulAdapter(bridge *stateful.Bridge) *grpcStatefulAdapter {
+	return &grpcStatefulAdapter{bridge: bridge}
+}
+
+// ExecuteStateful implements grpc.StatefulExecutor.
+// It translates grpc.StatefulRequest → stateful.OperationRequest,
+// calls Bridge.Execute(), and translates stateful.OperationResult → grpc.StatefulResult.
+func (a *grpcStatefulAdapter) ExecuteStateful(ctx context.Context, req *grpc.StatefulRequest) *grpc.StatefulResult {
+	if a.bridge == nil || req == nil {
+		return &grpc.StatefulResult{
+			Error: &grpc.GRPCErrorConfig{
+				Code:    "INTERNAL",
+				Message: "stateful bridge is not configured",
+			},
+		}
+	}
+
+	// gRPC servers use the default workspace since the protocol does not
+	// yet carry workspace context, partitioned by the isolation key.
+	workspaceID := a.workspace(ctx)
+	opReq := &stateful.OperationRequest{
+		WorkspaceID:   workspaceID,
+		Resource:      req.Resource,
+		Action:        stateful.Action(req.Action),
+		OperationName: req.OperationName,
+		ResourceID:    req.ResourceID,
+		Data:          req.Data,
+	}
+
+	if req.Filter != nil {
+		opReq.Filter = &stateful.QueryFilter{
+			Limit:   req.Filter.Limit,
+			Offset:  req.Filter.Offset,
+			Sort:    req.Filter.Sort,
+			Order:   req.Filter.Order,
+			Filters: req.Filter.Filters,
+		}
+	}
+
+	result := a.bridge.Execute(ctx, opReq)
+	if result.Error != nil {
+		return &grpc.StatefulResult{Error: errorToGRPCError(result.Error)}
+	}
+
+	// Custom operations build their own response; only table items are transformed.
+	var responseCfg *config.ResponseTransform
+	if req.Resource != "" && opReq.Action != stateful.ActionCustom {
+		responseCfg = a.bridge.GetResponseConfig(workspaceID, req.Resource)
+	}
+
+	grpcResult := &grpc.StatefulResult{}
+	if result.Item != nil {
+		if opReq.Action == stateful.ActionCustom {
+			grpcResult.Item = result.Item.Data
+		} else {
+			grpcResult.Item = stateful.TransformItem(result.Item.ToJSON(), responseCfg)
+		}
+	}
+	if result.List != nil {
+		grpcResult.Items = make([]map[string]interface{}, len(result.List.Data))
+		for i, item := range result.List.Data {
+			grpcResult.Items[i] = stateful.TransformItem(item, responseCfg)
+		}
+		grpcResult.Meta = &grpc.StatefulListMeta{
+			Total:  result.List.Meta.Total,
+			Count:  result.List.Meta.Count,
+			Offset: result.List.Meta.Offset,
+			Limit:  result.List.Meta.Limit,
+		}
+	}
+
+	return grpcResult
+}
+
+// workspace returns the (possibly partitioned) default workspace for a call.
+func (a *grpcStatefulAdapter) workspace(ctx context.Context) string {
+	if a.isolation == nil {
+		return ""
+	}
+	cfg := a.isolation()
+	if cfg == nil {
+		return ""
+	}
+	return stateful.PartitionWorkspaceID("", metadataIsolationKey(ctx, cfg))
+}
+
+// errorToGRPCError converts a stateful error to a gRPC error config with
+// google.rpc error details:
+//
+//	NotFound   → NOT_FOUND + ResourceInfo
+//	Conflict   → ALREADY_EXISTS + ResourceInfo
+//	Validation → INVALID_ARGUMENT + BadRequest (when the field is known)
+//	Capacity   → RESOURCE_EXHAUSTED + QuotaFailure
+//	Too large  → RESOURCE_EXHAUSTED
+//	Other      → INTERNAL
+func errorToGRPCError(err error) *grpc.GRPCErrorConfig {
+	errCfg := &grpc.GRPCErrorConfig{Message: err.Error()}
+
+	var notFound *stateful.NotFoundError
+	var conflict *stateful.ConflictError
+	var validation *stateful.ValidationError
+	var capacity *stateful.CapacityError
+
+	switch stateful.GetErrorCode(err) {
+	case stateful.ErrCodeNotFound:
+		errCfg.Code = "NOT_FOUND"
+		if errors.As(err, &notFound) {
+			errCfg.Details = resourceInfoDetails(notFound.Resource, notFound.ID)
+		}
+	case stateful.ErrCodeConflict:
+		errCfg.Code = "ALREADY_EXISTS"
+		if errors.As(err, &conflict) {
+			errCfg.Details = resourceInfoDetails(conflict.Resource, conflict.ID)
+		}
+	case stateful.ErrCodeValidation:
+		errCfg.Code = "INVALID_ARGUMENT"
+		if errors.As(err, &validation) && validation.Field != "" {
+			errCfg.Details = map[string]interface{}{
+				"bad_request": map[string]interface{}{
+					"field_violations": []interface{}{
+						map[string]interface{}{"field": validation.Field, "description": validation.Message},
+					},
+				},
+			}
+		}
+	case stateful.ErrCodeCapacityExceeded:
+		errCfg.Code = "RESOURCE_EXHAUSTED"
+		if errors.As(err, &capacity) {
+			errCfg.Details = map[string]interface{}{
+				"quota_failure": map[string]interface{}{
+					"violations": []interface{}{
+						map[string]interface{}{"subject": capacity.Resource, "description": err.Error()},
+					},
+				},
+			}
+		}
+	case stateful.ErrCodePayloadTooLarge:
+		errCfg.Code = "RESOURCE_EXHAUSTED"
+	default:
+		errCfg.Code = "INTERNAL"
+	}
+
+	return errCfg
+}
+
+// resourceInfoDetails builds a ResourceInfo error detail for a table item.
+func resourceInfoDetails(resource, id string) map[string]interface{} {
+	return map[string]interface{}{
+		"resource_info": map[string]interface{}{
+			"resource_type": resource,
+			"resource_name": id,
+		},
+	}
+}
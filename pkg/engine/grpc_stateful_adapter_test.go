@@ -0,0 +1,164 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/getmockd/mockd/pkg/config"
+	"github.com/getmockd/mockd/pkg/grpc"
+	"github.com/getmockd/mockd/pkg/stateful"
+)
+
+func TestGRPCStatefulAdapter_Create_Get_List_Delete(t *testing.T) {
+	store := stateful.NewStateStore()
+	if err := store.Register("", &config.StatefulResourceConfig{
+		Name: "users",
+	}); err != nil {
+		t.Fatalf("failed to register resource: %v", err)
+	}
+
+	adapter := newGRPCStatefulAdapter(stateful.NewBridge(store))
+	ctx := context.Background()
+
+	// CREATE
+	createResult := adapter.ExecuteStateful(ctx, &grpc.StatefulRequest{
+		Resource: "users",
+		Action:   grpc.StatefulActionCreate,
+		Data:     map[string]interface{}{"name": "Alice", "role": "admin"},
+	})
+	if createResult.Error != nil {
+		t.Fatalf("create failed: %v", createResult.Error.Message)
+	}
+	userID, ok := createResult.Item["id"].(string)
+	if !ok || userID == "" {
+		t.Fatalf("expected non-empty string ID, got %v", createResult.Item["id"])
+	}
+
+	// CREATE duplicate ID
+	dup := adapter.ExecuteStateful(ctx, &grpc.StatefulRequest{
+		Resource:   "users",
+		Action:     grpc.StatefulActionCreate,
+		ResourceID: userID,
+		Data:       map[string]interface{}{"id": userID, "name": "Alice again"},
+	})
+	if dup.Error == nil || dup.Error.Code != "ALREADY_EXISTS" {
+		t.Fatalf("expected ALREADY_EXISTS, got %+v", dup.Error)
+	}
+
+	// LIST with filter
+	listResult := adapter.ExecuteStateful(ctx, &grpc.StatefulRequest{
+		Resource: "users",
+		Action:   grpc.StatefulActionList,
+		Filter:   &grpc.StatefulFilter{Limit: 10, Filters: map[string]string{"role": "admin"}},
+	})
+	if listResult.Error != nil {
+		t.Fatalf("list failed: %v", listResult.Error.Message)
+	}
+	if len(listResult.Items) != 1 || listResult.Meta == nil || listResult.Meta.Total != 1 {
+		t.Fatalf("expected 1 item with meta.total=1, got %d items, meta %+v", len(listResult.Items), listResult.Meta)
+	}
+
+	// DELETE
+	deleteResult := adapter.ExecuteStateful(ctx, &grpc.StatefulRequest{
+		Resource:   "users",
+		Action:     grpc.StatefulActionDelete,
+		ResourceID: userID,
+	})
+	if deleteResult.Error != nil {
+		t.Fatalf("delete failed: %v", deleteResult.Error.Message)
+	}
+
+	// GET after delete
+	missing := adapter.ExecuteStateful(ctx, &grpc.StatefulRequest{
+		Resource:   "users",
+		Action:     grpc.StatefulActionGet,
+		ResourceID: userID,
+	})
+	if missing.Error == nil || missing.Error.Code != "NOT_FOUND" {
+		t.Fatalf("expected NOT_FOUND after delete, got %+v", missing.Error)
+	}
+	info, ok := missing.Error.Details["resource_info"].(map[string]interface{})
+	if !ok || info["resource_type"] != "users" || info["resource_name"] != userID {
+		t.Errorf("expected resource_info for users/%s, got %v", userID, missing.Error.Details)
+	}
+}
+
+func TestGRPCStatefulAdapter_IsolationMetadata(t *testing.T) {
+	store := stateful.NewStateStore()
+	if err := store.Register("", &config.StatefulResourceConfig{Name: "users"}); err != nil {
+		t.Fatalf("failed to register resource: %v", err)
+	}
+	adapter := newGRPCStatefulAdapter(stateful.NewBridge(store))
+	adapter.isolation = func() *config.StateIsolationConfig {
+		return &config.StateIsolationConfig{Header: "X-Test-Run"}
+	}
+
+	create := func(ctx context.Context, id string) {
+		t.Helper()
+		result := adapter.ExecuteStateful(ctx, &grpc.StatefulRequest{
+			Resource: "users",
+			Action:   grpc.StatefulActionCreate,
+			Data:     map[string]interface{}{"id": id},
+		})
+		if result.Error != nil {
+			t.Fatalf("create %s failed: %v", id, result.Error.Message)
+		}
+	}
+	create(metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-test-run", "run-a")), "isolated")
+	create(context.Background(), "shared")
+
+	if store.Get("", "users").Get("isolated") != nil {
+		t.Error("isolated item leaked into the shared table")
+	}
+	if store.Get(stateful.PartitionWorkspaceID("", "run-a"), "users").Get("isolated") == nil {
+		t.Error("isolated item missing from its partition")
+	}
+	if store.Get("", "users").Get("shared") == nil {
+		t.Error("call without metadata should use the shared table")
+	}
+}
+
+func TestGRPCStatefulAdapter_NilBridge(t *testing.T) {
+	adapter := newGRPCStatefulAdapter(nil)
+	result := adapter.ExecuteStateful(context.Background(), &grpc.StatefulRequest{
+		Resource: "users",
+		Action:   grpc.StatefulActionGet,
+	})
+	if result.Error == nil || result.Error.Code != "INTERNAL" {
+		t.Fatalf("expected INTERNAL error for nil bridge, got %+v", result.Error)
+	}
+}
+
+func TestGRPCStatefulAdapter_ErrorCodes(t *testing.T) {
+	tests := []struct {
+		err     error
+		code    string
+		details string
+	}{
+		{&stateful.NotFoundError{Resource: "users", ID: "1"}, "NOT_FOUND", "resource_info"},
+		{&stateful.ConflictError{Resource: "users", ID: "1"}, "ALREADY_EXISTS", "resource_info"},
+		{&stateful.ValidationError{Field: "email", Message: "is required"}, "INVALID_ARGUMENT", "bad_request"},
+		{&stateful.ValidationError{Message: "bad"}, "INVALID_ARGUMENT", ""},
+		{&stateful.CapacityError{Resource: "users", MaxItems: 10}, "RESOURCE_EXHAUSTED", "quota_failure"},
+		{&stateful.PayloadTooLargeError{MaxSize: 10}, "RESOURCE_EXHAUSTED", ""},
+		{context.Canceled, "INTERNAL", ""},
+	}
+	for _, tt := range tests {
+		errCfg := errorToGRPCError(tt.err)
+		if errCfg.Code != tt.code {
+			t.Errorf("errorToGRPCError(%T) code = %s, want %s", tt.err, errCfg.Code, tt.code)
+		}
+		if !grpc.ValidateStatusCode(errCfg.Code) {
+			t.Errorf("errorToGRPCError(%T) code %s is not a valid gRPC status", tt.err, errCfg.Code)
+		}
+		if tt.details == "" {
+			if len(errCfg.Details) != 0 {
+				t.Errorf("errorToGRPCError(%T) details = %v, want none", tt.err, errCfg.Details)
+			}
+		} else if _, ok := errCfg.Details[tt.details]; !ok {
+			t.Errorf("errorToGRPCError(%T) details = %v, want %s", tt.err, errCfg.Details, tt.details)
+		}
+	}
+}
@@ -15,16 +15,20 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/getmockd/mockd/internal/matching"
 	"github.com/getmockd/mockd/internal/storage"
+	"github.com/getmockd/mockd/pkg/chaos"
+	"github.com/getmockd/mockd/pkg/config"
 	"github.com/getmockd/mockd/pkg/graphql"
 	"github.com/getmockd/mockd/pkg/logging"
 	"github.com/getmockd/mockd/pkg/mock"
 	"github.com/getmockd/mockd/pkg/mtls"
 	"github.com/getmockd/mockd/pkg/oauth"
 	"github.com/getmockd/mockd/pkg/requestlog"
+	"github.com/getmockd/mockd/pkg/shadow"
 	"github.com/getmockd/mockd/pkg/soap"
 	"github.com/getmockd/mockd/pkg/sse"
 	"github.com/getmockd/mockd/pkg/stateful"
@@ -46,12 +50,14 @@ type Handler struct {
 	store          storage.MockStore
 	statefulStore  *stateful.StateStore
 	statefulBridge *stateful.Bridge // Bridge for custom operation execution
+	stateIsolation atomic.Pointer[config.StateIsolationConfig]
 	logger         RequestLogger
 	log            *slog.Logger // Operational logger for errors/warnings
 	sseHandler     *sse.SSEHandler
 	chunkedHandler *sse.ChunkedHandler
 	wsManager      *websocket.ConnectionManager
 	templateEngine *template.Engine
+	shadower       atomic.Pointer[shadow.Shadower] // Shadow mode, nil when disabled
 
 	// baseDir is the base directory for resolving relative file paths (e.g., bodyFile).
 	// When set, relative paths in bodyFile are resolved against this directory.
@@ -101,11 +107,23 @@ func (h *Handler) SetOperationalLogger(log *slog.Logger) {
 	} else {
 		h.log = logging.Nop()
 	}
+	h.sseHandler.SetOperationalLogger(h.log.With("protocol", "sse"))
+	h.wsManager.SetOperationalLogger(h.log.With("protocol", "websocket"))
 }
 
 // SetStatefulStore sets the stateful resource store for the handler.
 func (h *Handler) SetStatefulStore(store *stateful.StateStore) {
 	h.statefulStore = store
+	// SSE and WebSocket endpoints can stream table changes from the store.
+	h.sseHandler.SetStatefulStore(store)
+	h.wsManager.SetStatefulStore(store)
+}
+
+// SetChaosSource sets where SSE streams and WebSocket connections get their
+// message chaos from.
+func (h *Handler) SetChaosSource(source func() *chaos.Injector) {
+	h.sseHandler.SetChaosSource(source)
+	h.wsManager.SetChaosSource(source)
 }
 
 // SetStatefulBridge sets the stateful bridge for custom operation execution.
@@ -113,6 +131,17 @@ func (h *Handler) SetStatefulBridge(bridge *stateful.Bridge) {
 	h.statefulBridge = bridge
 }
 
+// SetShadower sets the shadower comparing mock responses with the upstream,
+// or disables shadow mode when nil. It returns the previous shadower.
+func (h *Handler) SetShadower(s *shadow.Shadower) *shadow.Shadower {
+	return h.shadower.Swap(s)
+}
+
+// Shadower returns the active shadower, or nil when shadow mode is disabled.
+func (h *Handler) Shadower() *shadow.Shadower {
+	return h.shadower.Load()
+}
+
 // SetStore sets the mock store for the handler.
 func (h *Handler) SetStore(store storage.MockStore) {
 	h.store = store
@@ -129,6 +158,33 @@ func (h *Handler) HasMatch(r *http.Request) bool {
 	return SelectBestMatchWithCaptures(mocks, r) != nil
 }
 
+// chaosMockTarget returns the HTTP mock that will serve r, for chaos rules
+// that match on mocks, or nil if no mock matches.
+func (h *Handler) chaosMockTarget(r *http.Request) *chaos.MockTarget {
+	match := selectHTTPMatch(h.store.ListByType(mock.TypeHTTP), r, nil)
+	if match == nil {
+		return nil
+	}
+	return &chaos.MockTarget{
+		ID:        match.Mock.ID,
+		Name:      match.Mock.Name,
+		Workspace: match.Mock.WorkspaceID,
+		Tags:      match.Mock.Tags,
+	}
+}
+
+// selectHTTPMatch finds the mock that serves r. A HEAD request no mock
+// matches is retried as GET.
+func selectHTTPMatch(mocks []*mock.Mock, r *http.Request, bodyBytes []byte) *MatchResult {
+	matchResult := SelectBestMatchWithCaptures(mocks, r, bodyBytes)
+	if matchResult == nil && r.Method == http.MethodHead {
+		getFallback := r.Clone(r.Context())
+		getFallback.Method = http.MethodGet
+		matchResult = SelectBestMatchWithCaptures(mocks, getFallback, bodyBytes)
+	}
+	return matchResult
+}
+
 // ServeHTTP implements the http.Handler interface.
 // Note: CORS is handled by the CORSMiddleware wrapper, not directly in this handler.
 // This ensures CORS configuration is respected rather than using hardcoded wildcards.
@@ -145,6 +201,10 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) { //nolint:g
 		return
 	}
 
+	// Carry the state isolation key for protocols that resolve stateful
+	// tables away from the request (streams, resolvers, SOAP operations)
+	r = h.withIsolationKey(r)
+
 	// Check for WebSocket upgrade first
 	if websocket.IsWebSocketRequest(r) {
 		// Check for GraphQL subscription WebSocket first
@@ -227,14 +287,8 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) { //nolint:g
 	// Find best matching mock using scoring algorithm (with regex captures).
 	// Pass the already-read bodyBytes to avoid a second 10 MB body read inside
 	// the matcher — this halves peak memory per request for large bodies.
-	matchResult := SelectBestMatchWithCaptures(mocks, r, bodyBytes)
-
-	// HEAD fallback: if no match for HEAD, retry as GET
-	if matchResult == nil && r.Method == http.MethodHead {
-		getFallback := r.Clone(r.Context())
-		getFallback.Method = http.MethodGet
-		matchResult = SelectBestMatchWithCaptures(mocks, getFallback, bodyBytes)
-	}
+	// HEAD requests fall back to GET mocks.
+	matchResult := selectHTTPMatch(mocks, r, bodyBytes)
 
 	if matchResult != nil {
 		match := matchResult.Mock
@@ -277,6 +331,14 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) { //nolint:g
 			return
 		}
 
+		// Shadow mode: send the request to the upstream once the mock has
+		// answered, and compare the two responses
+		if sh := h.shadower.Load(); sh != nil && sh.Selects(matchedID, r.Method, r.URL.Path) {
+			capture := shadow.NewCapture(w)
+			w = capture
+			defer func() { sh.Submit(matchedID, r, bodyBytes, capture.Response()) }()
+		}
+
 		// Run per-mock validation if configured
 		if match.HTTP != nil && match.HTTP.Validation != nil && !match.HTTP.Validation.IsEmpty() {
 			validationResult := h.validateHTTPRequest(r, bodyBytes, pathParams, match.HTTP.Validation)
@@ -381,8 +443,25 @@ func (h *Handler) logRequest(startTime time.Time, r *http.Request, headers map[s
 			DurationMs:     int(time.Since(startTime).Milliseconds()),
 			NearMisses:     nearMisses,
 		}
+		cc := chaos.GetChaosContext(r.Context())
+		if cc != nil {
+			entry.Chaos = &requestlog.ChaosMeta{FaultSeq: cc.Seq, Faults: cc.FaultTypes()}
+		}
 		h.logger.Log(entry)
+		cc.LinkRequest(entry.ID)
+	}
+}
+
+// logChaosAbort logs a request that a chaos fault answered before it reached
+// the handler, such as an injected error or a dropped connection.
+func (h *Handler) logChaosAbort(r *http.Request, startTime time.Time, statusCode int) {
+	var workspaceID, matchedID string
+	if target := chaos.MockTargetFromContext(r.Context()); target != nil {
+		workspaceID, matchedID = target.Workspace, target.ID
 	}
+	headers := make(map[string][]string)
+	maps.Copy(headers, r.Header)
+	h.logRequest(startTime, r, headers, nil, matchedID, workspaceID, statusCode, nil)
 }
 
 // writeResponse writes the mock response to the HTTP response writer.
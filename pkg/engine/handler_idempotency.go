@@ -0,0 +1,137 @@
+// Idempotency-Key handling for stateful creates and custom operations.
+//
+// A request carrying the configured key header is executed once; retries with
+// the same key and request fingerprint get the stored response replayed
+// byte-for-byte, and reusing the key for a different request is a 409.
+
+package engine
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/getmockd/mockd/pkg/stateful"
+)
+
+// IdempotentReplayedHeader marks a response replayed from the idempotency cache.
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// withIdempotency runs serve under the idempotency policy of the given table
+// and/or custom operation. Without a policy, or without a key on an optional
+// policy, serve runs as-is.
+func (h *Handler) withIdempotency(w http.ResponseWriter, r *http.Request, workspaceID, table, operation string, bodyBytes []byte, serve func(http.ResponseWriter) int) int {
+	policy := h.statefulBridge.GetIdempotencyPolicy(workspaceID, table, operation)
+	if policy == nil {
+		return serve(w)
+	}
+
+	resourceName := table
+	if operation != "" {
+		resourceName = operation
+	}
+
+	key := r.Header.Get(policy.Header)
+	if key == "" {
+		if policy.Required {
+			return h.writeStatefulErrorWithHint(w, http.StatusBadRequest, policy.Header+" header is required", resourceName, "",
+				"Send a unique "+policy.Header+" with each request and reuse it when retrying")
+		}
+		return serve(w)
+	}
+
+	cache := h.statefulBridge.Idempotency()
+	scope := stateful.IdempotencyScope(workspaceID, table, operation)
+	stored, err := cache.Begin(scope, key, idempotencyFingerprint(r, bodyBytes), policy.TTL)
+	if err != nil {
+		var keyErr *stateful.IdempotencyKeyError
+		if errors.As(err, &keyErr) {
+			return h.writeStatefulErrorWithHint(w, keyErr.StatusCode(), keyErr.Error(), resourceName, "", keyErr.Hint())
+		}
+		return h.writeStatefulError(w, http.StatusBadRequest, err.Error(), resourceName, "")
+	}
+	if stored != nil {
+		return replayStoredResponse(w, stored)
+	}
+
+	// Release the key if serve panics so the client can retry.
+	done := false
+	defer func() {
+		if !done {
+			cache.Abort(scope, key)
+		}
+	}()
+
+	before := w.Header().Clone()
+	rec := &idempotencyRecorder{ResponseWriter: w}
+	status := serve(rec)
+	done = true
+
+	// Server errors are not remembered so a retry can succeed.
+	if status >= http.StatusInternalServerError {
+		cache.Abort(scope, key)
+		return status
+	}
+	cache.Complete(scope, key, &stateful.StoredResponse{
+		StatusCode: status,
+		Header:     addedHeaders(before, w.Header()),
+		Body:       rec.body.Bytes(),
+	})
+	return status
+}
+
+// replayStoredResponse writes a stored response and marks it as replayed.
+func replayStoredResponse(w http.ResponseWriter, stored *stateful.StoredResponse) int {
+	for name, values := range stored.Header {
+		w.Header()[name] = append([]string(nil), values...)
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(stored.StatusCode)
+	_, _ = w.Write(stored.Body)
+	return stored.StatusCode
+}
+
+// idempotencyFingerprint identifies a request by method, URI and raw body, so a
+// key reused for any other request is detected.
+func idempotencyFingerprint(r *http.Request, body []byte) string {
+	sum := sha256.New()
+	sum.Write([]byte(r.Method))
+	sum.Write([]byte{0})
+	sum.Write([]byte(r.URL.RequestURI()))
+	sum.Write([]byte{0})
+	sum.Write(body)
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// addedHeaders returns the headers set or changed in after relative to before,
+// i.e. the headers written by the handler itself rather than by middleware.
+func addedHeaders(before, after http.Header) map[string][]string {
+	added := make(map[string][]string)
+	for name, values := range after {
+		if prev, ok := before[name]; ok && slices.Equal(prev, values) {
+			continue
+		}
+		added[name] = append([]string(nil), values...)
+	}
+	return added
+}
+
+// idempotencyRecorder writes through to the client while keeping a copy of the body.
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write records b before passing it on.
+func (w *idempotencyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController support.
+func (w *idempotencyRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,207 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getmockd/mockd/pkg/config"
+	"github.com/getmockd/mockd/pkg/mock"
+	"github.com/getmockd/mockd/pkg/stateful"
+)
+
+func newIdempotentPaymentsHandler(t *testing.T, cfg *config.IdempotencyConfig) (*Handler, *stateful.StateStore) {
+	t.Helper()
+	store := stateful.NewStateStore()
+	if err := store.Register("", &stateful.ResourceConfig{Name: "payments", Idempotency: cfg}); err != nil {
+		t.Fatalf("failed to register resource: %v", err)
+	}
+	return &Handler{log: slog.Default(), statefulBridge: stateful.NewBridge(store)}, store
+}
+
+func paymentsCreateMock() *mock.Mock {
+	return &mock.Mock{
+		HTTP: &mock.HTTPSpec{
+			Matcher:         &mock.HTTPMatcher{Path: "/v1/payments", Method: "POST"},
+			StatefulBinding: &mock.StatefulBinding{Table: "payments", Action: "create"},
+		},
+	}
+}
+
+func postPayment(h *Handler, m *mock.Mock, key string, body []byte) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/v1/payments", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	h.handleStatefulBinding(w, req, m, body, nil)
+	return w
+}
+
+func TestIdempotency_CreateReplaysOriginalResponse(t *testing.T) {
+	h, store := newIdempotentPaymentsHandler(t, &config.IdempotencyConfig{})
+	m := paymentsCreateMock()
+	body := []byte(`{"amount":1000}`)
+
+	first := postPayment(h, m, "key-1", body)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("first create: expected 201, got %d", first.Code)
+	}
+	if first.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Error("first response must not be marked as replayed")
+	}
+
+	retry := postPayment(h, m, "key-1", body)
+	if retry.Code != http.StatusCreated {
+		t.Fatalf("retry: expected 201, got %d", retry.Code)
+	}
+	if !bytes.Equal(retry.Body.Bytes(), first.Body.Bytes()) {
+		t.Errorf("retry body = %s, want byte-identical %s", retry.Body.Bytes(), first.Body.Bytes())
+	}
+	if retry.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("expected %s: true on replay", IdempotentReplayedHeader)
+	}
+	if got := store.Get("", "payments").Count(); got != 1 {
+		t.Errorf("expected 1 payment after retry, got %d", got)
+	}
+
+	// A new key creates a new item.
+	if w := postPayment(h, m, "key-2", body); w.Code != http.StatusCreated {
+		t.Fatalf("second key: expected 201, got %d", w.Code)
+	}
+	if got := store.Get("", "payments").Count(); got != 2 {
+		t.Errorf("expected 2 payments, got %d", got)
+	}
+}
+
+func TestIdempotency_KeyReuseWithDifferentBodyConflicts(t *testing.T) {
+	h, store := newIdempotentPaymentsHandler(t, &config.IdempotencyConfig{})
+	m := paymentsCreateMock()
+
+	postPayment(h, m, "key-1", []byte(`{"amount":1000}`))
+	w := postPayment(h, m, "key-1", []byte(`{"amount":2000}`))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+	var resp stateful.ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode error response: %v", err)
+	}
+	if resp.Hint == "" {
+		t.Error("expected a resolution hint")
+	}
+	if got := store.Get("", "payments").Count(); got != 1 {
+		t.Errorf("expected 1 payment, got %d", got)
+	}
+}
+
+func TestIdempotency_RequiredAndOptionalKey(t *testing.T) {
+	h, store := newIdempotentPaymentsHandler(t, &config.IdempotencyConfig{Required: true})
+	m := paymentsCreateMock()
+	if w := postPayment(h, m, "", []byte(`{"amount":1000}`)); w.Code != http.StatusBadRequest {
+		t.Fatalf("required key: expected 400, got %d", w.Code)
+	}
+	if got := store.Get("", "payments").Count(); got != 0 {
+		t.Errorf("expected no payments, got %d", got)
+	}
+
+	h, store = newIdempotentPaymentsHandler(t, &config.IdempotencyConfig{})
+	postPayment(h, m, "", []byte(`{"amount":1000}`))
+	postPayment(h, m, "", []byte(`{"amount":1000}`))
+	if got := store.Get("", "payments").Count(); got != 2 {
+		t.Errorf("requests without a key are not deduplicated: expected 2, got %d", got)
+	}
+}
+
+func TestIdempotency_CustomHeaderOnCustomOperation(t *testing.T) {
+	store := stateful.NewStateStore()
+	if err := store.Register("", &stateful.ResourceConfig{Name: "payments"}); err != nil {
+		t.Fatalf("failed to register resource: %v", err)
+	}
+	br := stateful.NewBridge(store)
+	br.RegisterCustomOperation("", "Charge", &stateful.CustomOperation{
+		Name: "Charge",
+		Steps: []stateful.Step{
+			{Type: stateful.StepCreate, Resource: "payments", Set: map[string]string{"amount": "input.amount"}, As: "payment"},
+		},
+		Response:    map[string]string{"id": "payment.id"},
+		Idempotency: &config.IdempotencyConfig{Header: "X-Charge-Key"},
+	})
+	h := &Handler{log: slog.Default(), statefulBridge: br}
+
+	body := []byte(`{"amount":500}`)
+	charge := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/charges", bytes.NewReader(body))
+		req.Header.Set("X-Charge-Key", "charge-1")
+		h.handleCustomOperation(w, req, "", "Charge", body)
+		return w
+	}
+
+	first := charge()
+	if first.Code != http.StatusOK {
+		t.Fatalf("first charge: expected 200, got %d: %s", first.Code, first.Body.String())
+	}
+	retry := charge()
+	if !bytes.Equal(retry.Body.Bytes(), first.Body.Bytes()) {
+		t.Errorf("retry body = %s, want %s", retry.Body.Bytes(), first.Body.Bytes())
+	}
+	if got := store.Get("", "payments").Count(); got != 1 {
+		t.Errorf("expected 1 payment after retry, got %d", got)
+	}
+}
+
+func TestIdempotency_ServerErrorsAreNotStored(t *testing.T) {
+	h, _ := newIdempotentPaymentsHandler(t, &config.IdempotencyConfig{})
+	req := httptest.NewRequest(http.MethodPost, "/v1/payments", nil)
+	req.Header.Set("Idempotency-Key", "key-1")
+
+	calls := 0
+	serve := func(w http.ResponseWriter) int {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return http.StatusInternalServerError
+		}
+		w.WriteHeader(http.StatusCreated)
+		return http.StatusCreated
+	}
+
+	if status := h.withIdempotency(httptest.NewRecorder(), req, "", "payments", "", nil, serve); status != http.StatusInternalServerError {
+		t.Fatalf("first attempt: expected 500, got %d", status)
+	}
+	if status := h.withIdempotency(httptest.NewRecorder(), req, "", "payments", "", nil, serve); status != http.StatusCreated {
+		t.Fatalf("retry after 500: expected 201, got %d", status)
+	}
+	if calls != 2 {
+		t.Errorf("expected the retry to execute, got %d calls", calls)
+	}
+}
+
+func TestIdempotency_ResetForgetsKeys(t *testing.T) {
+	h, store := newIdempotentPaymentsHandler(t, &config.IdempotencyConfig{})
+	m := paymentsCreateMock()
+	body := []byte(`{"amount":1000}`)
+
+	if w := postPayment(h, m, "key-1", body); w.Code != http.StatusCreated {
+		t.Fatalf("first create: expected 201, got %d", w.Code)
+	}
+	if _, err := store.Reset("", "payments"); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+
+	retry := postPayment(h, m, "key-1", body)
+	if retry.Code != http.StatusCreated {
+		t.Fatalf("retry after reset: expected 201, got %d", retry.Code)
+	}
+	if retry.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Error("retry after reset must not replay the response from before the reset")
+	}
+	if got := store.Get("", "payments").Count(); got != 1 {
+		t.Errorf("expected the retry to create a payment, got %d payments", got)
+	}
+}
@@ -0,0 +1,146 @@
+package engine
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/getmockd/mockd/pkg/config"
+	"github.com/getmockd/mockd/pkg/stateful"
+)
+
+// SetStateIsolation enables per-key partitioning of stateful tables. Requests
+// that carry an isolation key read and write their own copy of each table.
+// Pass nil to disable isolation.
+func (h *Handler) SetStateIsolation(cfg *config.StateIsolationConfig) error {
+	if cfg == nil {
+		h.stateIsolation.Store(nil)
+		return nil
+	}
+	if cfg.Header == "" && cfg.Cookie == "" && cfg.Query == "" && cfg.JWTClaim == "" {
+		return fmt.Errorf("stateIsolation: one of header, cookie, query or jwtClaim is required")
+	}
+	var ttl time.Duration
+	if cfg.TTL != "" {
+		d, err := time.ParseDuration(cfg.TTL)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("stateIsolation: invalid ttl %q", cfg.TTL)
+		}
+		ttl = d
+	}
+	if h.statefulStore != nil {
+		h.statefulStore.SetPartitionTTL(ttl)
+	}
+	h.stateIsolation.Store(cfg)
+	return nil
+}
+
+// statefulWorkspace returns the workspace ID stateful operations for r should
+// use: the partition of workspaceID named by the request's isolation key, or
+// workspaceID itself when isolation is off or the request carries no key.
+func (h *Handler) statefulWorkspace(r *http.Request, workspaceID string) string {
+	cfg := h.stateIsolation.Load()
+	if cfg == nil {
+		return workspaceID
+	}
+	return stateful.PartitionWorkspaceID(workspaceID, isolationKey(r, cfg))
+}
+
+// withIsolationKey attaches the request's isolation key to its context so
+// protocol handlers that do not see the request itself (GraphQL resolvers,
+// SOAP operations, SSE and WebSocket change streams) use the same partition.
+func (h *Handler) withIsolationKey(r *http.Request) *http.Request {
+	cfg := h.stateIsolation.Load()
+	if cfg == nil {
+		return r
+	}
+	key := isolationKey(r, cfg)
+	if key == "" {
+		return r
+	}
+	return r.WithContext(stateful.ContextWithPartition(r.Context(), key))
+}
+
+// contextWorkspace returns the workspace ID for stateful operations made on
+// behalf of ctx, partitioned by the isolation key withIsolationKey attached.
+func contextWorkspace(ctx context.Context, workspaceID string) string {
+	return stateful.PartitionWorkspaceID(workspaceID, stateful.PartitionFromContext(ctx))
+}
+
+// metadataIsolationKey extracts the isolation key from incoming gRPC
+// metadata. Metadata carries the HTTP/2 headers, so header, cookie and JWT
+// claim sources work as for HTTP; query parameters do not apply.
+func metadataIsolationKey(ctx context.Context, cfg *config.StateIsolationConfig) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	r := &http.Request{Header: make(http.Header, len(md)), URL: &url.URL{}}
+	for name, values := range md {
+		for _, v := range values {
+			r.Header.Add(name, v)
+		}
+	}
+	return isolationKey(r, cfg)
+}
+
+// isolationKey extracts the isolation key from a request, trying the header,
+// cookie, query parameter and JWT claim in that order.
+func isolationKey(r *http.Request, cfg *config.StateIsolationConfig) string {
+	if cfg.Header != "" {
+		if v := r.Header.Get(cfg.Header); v != "" {
+			return v
+		}
+	}
+	if cfg.Cookie != "" {
+		if c, err := r.Cookie(cfg.Cookie); err == nil && c.Value != "" {
+			return c.Value
+		}
+	}
+	if cfg.Query != "" {
+		if v := r.URL.Query().Get(cfg.Query); v != "" {
+			return v
+		}
+	}
+	if cfg.JWTClaim != "" {
+		return bearerClaim(r.Header.Get("Authorization"), cfg.JWTClaim)
+	}
+	return ""
+}
+
+// bearerClaim returns a claim from a "Bearer <jwt>" Authorization value as a
+// string, or "" if the header is not a decodable JWT or lacks the claim.
+// The signature is not verified: the key only selects a partition.
+func bearerClaim(authorization, claim string) string {
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok {
+		return ""
+	}
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ""
+	}
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return ""
+	}
+	switch v := claims[claim].(type) {
+	case string:
+		return v
+	case float64, bool:
+		return fmt.Sprint(v)
+	default:
+		return ""
+	}
+}
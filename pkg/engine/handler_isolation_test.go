@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getmockd/mockd/pkg/config"
+	"github.com/getmockd/mockd/pkg/mock"
+	"github.com/getmockd/mockd/pkg/stateful"
+)
+
+func TestIsolationKey_Sources(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"tenant_id":"acme","org":42}`))
+	token := "Bearer eyJhbGciOiJub25lIn0." + payload + ".sig"
+
+	tests := []struct {
+		name  string
+		cfg   config.StateIsolationConfig
+		setup func(r *http.Request)
+		want  string
+	}{
+		{"header", config.StateIsolationConfig{Header: "X-Test-Run"}, func(r *http.Request) { r.Header.Set("X-Test-Run", "run-1") }, "run-1"},
+		{"cookie", config.StateIsolationConfig{Cookie: "session"}, func(r *http.Request) { r.AddCookie(&http.Cookie{Name: "session", Value: "s-1"}) }, "s-1"},
+		{"query", config.StateIsolationConfig{Query: "api_key"}, func(r *http.Request) { r.URL.RawQuery = "api_key=k-1" }, "k-1"},
+		{"jwt claim", config.StateIsolationConfig{JWTClaim: "tenant_id"}, func(r *http.Request) { r.Header.Set("Authorization", token) }, "acme"},
+		{"numeric jwt claim", config.StateIsolationConfig{JWTClaim: "org"}, func(r *http.Request) { r.Header.Set("Authorization", token) }, "42"},
+		{"malformed jwt", config.StateIsolationConfig{JWTClaim: "tenant_id"}, func(r *http.Request) { r.Header.Set("Authorization", "Bearer nope") }, ""},
+		{"header before query", config.StateIsolationConfig{Header: "X-Test-Run", Query: "api_key"}, func(r *http.Request) {
+			r.Header.Set("X-Test-Run", "run-1")
+			r.URL.RawQuery = "api_key=k-1"
+		}, "run-1"},
+		{"falls through to query", config.StateIsolationConfig{Header: "X-Test-Run", Query: "api_key"}, func(r *http.Request) { r.URL.RawQuery = "api_key=k-1" }, "k-1"},
+		{"no key", config.StateIsolationConfig{Header: "X-Test-Run"}, func(r *http.Request) {}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/users", nil)
+			tt.setup(req)
+			if got := isolationKey(req, &tt.cfg); got != tt.want {
+				t.Errorf("isolationKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetStateIsolation_Validation(t *testing.T) {
+	h := &Handler{statefulStore: stateful.NewStateStore()}
+	if err := h.SetStateIsolation(&config.StateIsolationConfig{}); err == nil {
+		t.Error("expected an error when no key source is configured")
+	}
+	if err := h.SetStateIsolation(&config.StateIsolationConfig{Header: "X-Test-Run", TTL: "later"}); err == nil {
+		t.Error("expected an error for an invalid ttl")
+	}
+	if err := h.SetStateIsolation(&config.StateIsolationConfig{Header: "X-Test-Run", TTL: "5m"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := h.statefulStore.PartitionTTL().String(); got != "5m0s" {
+		t.Errorf("partition TTL = %s, want 5m0s", got)
+	}
+}
+
+func TestWithIsolationKey_AnnotatesContext(t *testing.T) {
+	h := &Handler{statefulStore: stateful.NewStateStore()}
+	r := httptest.NewRequest(http.MethodGet, "/events", nil)
+	r.Header.Set("X-Test-Run", "run-1")
+
+	if got := stateful.PartitionFromContext(h.withIsolationKey(r).Context()); got != "" {
+		t.Errorf("partition without isolation = %q, want none", got)
+	}
+	if err := h.SetStateIsolation(&config.StateIsolationConfig{Header: "X-Test-Run"}); err != nil {
+		t.Fatalf("SetStateIsolation: %v", err)
+	}
+	if got := stateful.PartitionFromContext(h.withIsolationKey(r).Context()); got != "run-1" {
+		t.Errorf("partition = %q, want run-1", got)
+	}
+}
+
+func TestStateIsolation_PartitionsStatefulBindings(t *testing.T) {
+	store := stateful.NewStateStore()
+	if err := store.Register("", &stateful.ResourceConfig{
+		Name:     "users",
+		SeedData: []map[string]interface{}{{"id": "u1", "name": "Alice"}},
+	}); err != nil {
+		t.Fatalf("failed to register resource: %v", err)
+	}
+	h := &Handler{log: slog.Default(), statefulStore: store, statefulBridge: stateful.NewBridge(store)}
+	if err := h.SetStateIsolation(&config.StateIsolationConfig{Header: "X-Test-Run"}); err != nil {
+		t.Fatalf("SetStateIsolation: %v", err)
+	}
+
+	create := &mock.Mock{HTTP: &mock.HTTPSpec{
+		Matcher:         &mock.HTTPMatcher{Path: "/users", Method: "POST"},
+		StatefulBinding: &mock.StatefulBinding{Table: "users", Action: "create"},
+	}}
+	list := &mock.Mock{HTTP: &mock.HTTPSpec{
+		Matcher:         &mock.HTTPMatcher{Path: "/users", Method: "GET"},
+		StatefulBinding: &mock.StatefulBinding{Table: "users", Action: "list"},
+	}}
+	count := func(run string) int {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		if run != "" {
+			req.Header.Set("X-Test-Run", run)
+		}
+		h.handleStatefulBinding(w, req, list, nil, nil)
+		var resp struct {
+			Data []map[string]interface{} `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode list response: %v (%s)", err, w.Body.String())
+		}
+		return len(resp.Data)
+	}
+
+	body := []byte(`{"name":"Bob"}`)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Test-Run", "run-a")
+	h.handleStatefulBinding(w, req, create, body, nil)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create: expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if got := count("run-a"); got != 2 {
+		t.Errorf("run-a: expected 2 users, got %d", got)
+	}
+	if got := count("run-b"); got != 1 {
+		t.Errorf("run-b: expected seed data only, got %d users", got)
+	}
+	if got := count(""); got != 1 {
+		t.Errorf("shared tables: expected seed data only, got %d users", got)
+	}
+	if got := len(store.Partitions("")); got != 2 {
+		t.Errorf("expected 2 partitions, got %d", got)
+	}
+}
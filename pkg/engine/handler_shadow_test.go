@@ -0,0 +1,59 @@
+package engine
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/getmockd/mockd/internal/storage"
+	"github.com/getmockd/mockd/pkg/shadow"
+)
+
+func TestHandler_ShadowMode(t *testing.T) {
+	t.Parallel()
+
+	var upstreamHits atomic.Int64
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamHits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"users":[{"id":1}],"next":null}`))
+	}))
+	defer upstream.Close()
+
+	store := storage.NewInMemoryMockStore()
+	require.NoError(t, store.Set(createTestHTTPMock("users", "/api/users", "GET", 200, `{"users":[{"id":"1"}]}`)))
+	require.NoError(t, store.Set(createTestHTTPMock("health", "/api/health", "GET", 200, `{"ok":true}`)))
+	handler := NewHandler(store)
+
+	tracker := shadow.NewTracker()
+	sh, err := shadow.New(&shadow.Config{Enabled: true, Upstream: upstream.URL, MockIDs: []string{"users"}}, tracker)
+	require.NoError(t, err)
+	handler.SetShadower(sh)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"users":[{"id":"1"}]}`, rec.Body.String(), "the mock answers the client")
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/health", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Disabling shadow mode hands back the shadower; closing it waits for
+	// the queued comparison.
+	handler.SetShadower(nil).Close()
+
+	assert.Equal(t, int64(1), upstreamHits.Load(), "only the selected mock is shadowed")
+	drift, ok := tracker.Mock("users")
+	require.True(t, ok)
+	assert.Equal(t, int64(1), drift.Drifted)
+	kinds := make(map[string]string)
+	for _, d := range drift.Differences {
+		kinds[d.Path] = d.Kind
+	}
+	assert.Equal(t, map[string]string{"$.users[*].id": shadow.KindType, "$.next": shadow.KindMissing}, kinds)
+}
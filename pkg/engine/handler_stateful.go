@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -37,7 +38,7 @@ func (h *Handler) handleStatefulBinding(w http.ResponseWriter, r *http.Request,
 		return h.writeStatefulError(w, http.StatusServiceUnavailable, "stateful bridge not configured", binding.Table, "")
 	}
 
-	workspaceID := matched.WorkspaceID
+	workspaceID := h.statefulWorkspace(r, matched.WorkspaceID)
 	action := stateful.Action(binding.Action)
 
 	// Resolve response transform config: binding override > table default.
@@ -71,7 +72,9 @@ func (h *Handler) handleStatefulBinding(w http.ResponseWriter, r *http.Request,
 	case stateful.ActionGet:
 		return h.handleBindingGet(w, r, workspaceID, binding.Table, itemID, responseCfg)
 	case stateful.ActionCreate:
-		return h.handleBindingCreate(w, r, workspaceID, binding.Table, pathParams, bodyBytes, responseCfg)
+		return h.withIdempotency(w, r, workspaceID, binding.Table, "", bodyBytes, func(w http.ResponseWriter) int {
+			return h.handleBindingCreate(w, r, workspaceID, binding.Table, pathParams, bodyBytes, responseCfg)
+		})
 	case stateful.ActionUpdate:
 		return h.handleBindingMutate(w, r, workspaceID, binding.Table, itemID, pathParams, bodyBytes, responseCfg, stateful.ActionUpdate)
 	case stateful.ActionPatch:
@@ -79,7 +82,9 @@ func (h *Handler) handleStatefulBinding(w http.ResponseWriter, r *http.Request,
 	case stateful.ActionDelete:
 		return h.handleBindingDelete(w, r, workspaceID, binding.Table, itemID, responseCfg)
 	case stateful.ActionCustom:
-		return h.handleBindingCustom(w, r, workspaceID, binding, pathParams, bodyBytes, responseCfg)
+		return h.withIdempotency(w, r, workspaceID, binding.Table, binding.Operation, bodyBytes, func(w http.ResponseWriter) int {
+			return h.handleBindingCustom(w, r, workspaceID, binding, pathParams, bodyBytes, responseCfg)
+		})
 	default:
 		return h.writeStatefulError(w, http.StatusBadRequest, "unsupported action: "+binding.Action, binding.Table, "")
 	}
@@ -127,6 +132,12 @@ func (h *Handler) handleBindingList(w http.ResponseWriter, r *http.Request, work
 	}
 
 	filter := h.parseQueryFilter(r, resource, pathParams)
+	pagination := resource.PaginationPolicy()
+	if pagination != nil {
+		if err := pagination.ParseQuery(r.URL.Query(), filter); err != nil {
+			return h.writeStatefulError(w, http.StatusBadRequest, err.Error(), table, "")
+		}
+	}
 	result := h.statefulBridge.Execute(r.Context(), &stateful.OperationRequest{
 		Resource:    table,
 		Action:      stateful.ActionList,
@@ -137,6 +148,12 @@ func (h *Handler) handleBindingList(w http.ResponseWriter, r *http.Request, work
 		return h.writeBindingError(w, result, table, "", responseCfg)
 	}
 
+	// Page links need item IDs, so compute them before transforms rename fields.
+	var page *stateful.PageInfo
+	if pagination != nil {
+		page = pagination.Page(absoluteRequestURL(r), filter, result.List)
+	}
+
 	// Apply ?expand[] to each item in the list if requested
 	expandFields := parseExpandFields(r)
 	if len(expandFields) > 0 {
@@ -150,7 +167,11 @@ func (h *Handler) handleBindingList(w http.ResponseWriter, r *http.Request, work
 		}
 	}
 
-	response := stateful.TransformList(result.List.Data, result.List.Meta, responseCfg)
+	var response interface{} = stateful.TransformList(result.List.Data, result.List.Meta, responseCfg)
+	if page != nil {
+		page.WriteHeaders(w.Header())
+		response = page.Decorate(response)
+	}
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(response)
 	return http.StatusOK
@@ -376,6 +397,14 @@ func (h *Handler) handleCustomOperation(w http.ResponseWriter, r *http.Request,
 	if h.statefulBridge == nil {
 		return h.writeStatefulError(w, http.StatusServiceUnavailable, "stateful bridge not configured", "", "")
 	}
+	workspaceID = h.statefulWorkspace(r, workspaceID)
+	return h.withIdempotency(w, r, workspaceID, "", operationName, bodyBytes, func(w http.ResponseWriter) int {
+		return h.serveCustomOperation(w, r, workspaceID, operationName, bodyBytes)
+	})
+}
+
+// serveCustomOperation runs a custom operation for handleCustomOperation.
+func (h *Handler) serveCustomOperation(w http.ResponseWriter, r *http.Request, workspaceID string, operationName string, bodyBytes []byte) int {
 	if len(bodyBytes) > MaxStatefulBodySize {
 		return h.writeStatefulErrorWithHint(w, http.StatusRequestEntityTooLarge, "request body too large", operationName, "", "Reduce request body size to under 1MB")
 	}
@@ -722,8 +751,12 @@ func (h *Handler) parseQueryFilter(r *http.Request, resource *stateful.StatefulR
 		}
 	}
 
+	var paginationParams []string
+	if pagination := resource.PaginationPolicy(); pagination != nil {
+		paginationParams = pagination.Params()
+	}
 	for key, values := range query {
-		if !reservedQueryParams[key] && len(values) > 0 {
+		if !reservedQueryParams[key] && !slices.Contains(paginationParams, key) && len(values) > 0 {
 			filter.Filters[key] = values[0]
 		}
 	}
@@ -731,6 +764,17 @@ func (h *Handler) parseQueryFilter(r *http.Request, resource *stateful.StatefulR
 	return filter
 }
 
+// absoluteRequestURL reconstructs the absolute URL of r for pagination links.
+func absoluteRequestURL(r *http.Request) *url.URL {
+	u := *r.URL
+	u.Scheme = "http"
+	if r.TLS != nil {
+		u.Scheme = "https"
+	}
+	u.Host = r.Host
+	return &u
+}
+
 // parseExpandFields extracts ?expand[] and ?expand query params from the request.
 func parseExpandFields(r *http.Request) []string {
 	expandFields := stateful.ParseExpandParam(r.URL.Query()["expand[]"])
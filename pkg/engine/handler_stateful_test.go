@@ -2683,3 +2683,60 @@ func TestHandleCustomOperation_Success(t *testing.T) {
 		t.Errorf("expected message='Hello Alice', got %v", body["message"])
 	}
 }
+
+func TestHandleStatefulBinding_ListWithPaginationConfig(t *testing.T) {
+	seed := make([]map[string]interface{}, 5)
+	for i := range seed {
+		seed[i] = map[string]interface{}{"id": fmt.Sprintf("r%d", i+1), "owner": "octo"}
+	}
+	store := stateful.NewStateStore()
+	if err := store.Register("", &stateful.ResourceConfig{
+		Name:     "repos",
+		SeedData: seed,
+		Pagination: &config.PaginationConfig{
+			Style:            "page",
+			LinkHeader:       true,
+			TotalCountHeader: "X-Total-Count",
+		},
+		Response: &config.ResponseTransform{List: &config.ListTransform{DataField: "items", HideMeta: true}},
+	}); err != nil {
+		t.Fatalf("failed to register resource: %v", err)
+	}
+	h := &Handler{log: slog.Default(), statefulBridge: stateful.NewBridge(store)}
+
+	m := &mock.Mock{
+		HTTP: &mock.HTTPSpec{
+			Matcher:         &mock.HTTPMatcher{Path: "/repos"},
+			StatefulBinding: &mock.StatefulBinding{Table: "repos", Action: "list"},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://api.test/repos?per_page=2&page=2&owner=octo", nil)
+	if status := h.handleStatefulBinding(w, req, m, nil, nil); status != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", status, w.Body.String())
+	}
+
+	if got := w.Header().Get("X-Total-Count"); got != "5" {
+		t.Errorf("X-Total-Count = %q, want 5", got)
+	}
+	link := w.Header().Get("Link")
+	for _, want := range []string{
+		`<http://api.test/repos?owner=octo&page=1&per_page=2>; rel="prev"`,
+		`<http://api.test/repos?owner=octo&page=3&per_page=2>; rel="next"`,
+		`<http://api.test/repos?owner=octo&page=3&per_page=2>; rel="last"`,
+	} {
+		if !strings.Contains(link, want) {
+			t.Errorf("Link header %q missing %s", link, want)
+		}
+	}
+
+	var body map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	items, ok := body["items"].([]interface{})
+	if !ok || len(items) != 2 {
+		t.Fatalf("expected 2 items on page 2, got %v", body["items"])
+	}
+}
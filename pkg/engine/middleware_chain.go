@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"sync/atomic"
+	"time"
 
 	"github.com/getmockd/mockd/pkg/audit"
 	"github.com/getmockd/mockd/pkg/chaos"
@@ -20,6 +21,8 @@ type MiddlewareChain struct {
 	validator     *validation.OpenAPIValidator
 	auditLogger   audit.AuditLogger
 	tracer        *tracing.Tracer
+	mockTargets   func(*http.Request) *chaos.MockTarget
+	chaosAborts   func(r *http.Request, start time.Time, status int)
 }
 
 // MiddlewareChainOption configures a MiddlewareChain.
@@ -33,6 +36,22 @@ func WithChainTracer(t *tracing.Tracer) MiddlewareChainOption {
 	}
 }
 
+// WithChainMockTargets sets how the chain resolves the mock a request will be
+// served by, for chaos rules that match on mocks.
+func WithChainMockTargets(resolve func(*http.Request) *chaos.MockTarget) MiddlewareChainOption {
+	return func(mc *MiddlewareChain) {
+		mc.mockTargets = resolve
+	}
+}
+
+// WithChainChaosAborts sets how the chain logs requests that a chaos fault
+// answered before they reached the wrapped handler.
+func WithChainChaosAborts(log func(r *http.Request, start time.Time, status int)) MiddlewareChainOption {
+	return func(mc *MiddlewareChain) {
+		mc.chaosAborts = log
+	}
+}
+
 // NewMiddlewareChain creates a new middleware chain from configuration.
 // It initializes chaos, validation, and audit components if configured.
 func NewMiddlewareChain(cfg *config.ServerConfiguration, opts ...MiddlewareChainOption) (*MiddlewareChain, error) {
@@ -161,7 +180,16 @@ type dynamicChaosHandler struct {
 func (h *dynamicChaosHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ci := h.chain.chaosInjector.Load()
 	if ci != nil && ci.IsEnabled() {
+		if ci.NeedsMockTarget() && h.chain.mockTargets != nil {
+			if target := h.chain.mockTargets(r); target != nil {
+				r = r.WithContext(chaos.WithMockTarget(r.Context(), target))
+			}
+		}
 		chaosMiddleware := chaos.NewMiddleware(h.handler, ci)
+		if log := h.chain.chaosAborts; log != nil {
+			start := time.Now()
+			chaosMiddleware.OnAbort(func(r *http.Request, status int) { log(r, start, status) })
+		}
 		chaosMiddleware.ServeHTTP(w, r)
 		return
 	}
@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/getmockd/mockd/internal/storage"
 	"github.com/getmockd/mockd/pkg/chaos"
 	"github.com/getmockd/mockd/pkg/config"
 	"github.com/stretchr/testify/assert"
@@ -358,3 +359,94 @@ func TestDynamicChaosHandler(t *testing.T) {
 		assert.Equal(t, "chaos-pass", rec.Body.String())
 	})
 }
+
+func TestDynamicChaosHandler_MockTargets(t *testing.T) {
+	t.Parallel()
+
+	store := storage.NewInMemoryMockStore()
+	tagged := createTestHTTPMock("orders", "/api/orders", "GET", 200, `{"orders":[]}`)
+	tagged.Tags = []string{"team-orders"}
+	require.NoError(t, store.Set(tagged))
+	require.NoError(t, store.Set(createTestHTTPMock("users", "/api/users", "GET", 200, `{"users":[]}`)))
+	handler := NewHandler(store)
+
+	mc, err := NewMiddlewareChain(&config.ServerConfiguration{}, WithChainMockTargets(handler.chaosMockTarget))
+	require.NoError(t, err)
+	injector, err := chaos.NewInjector(&chaos.ChaosConfig{
+		Enabled: true,
+		Rules: []chaos.ChaosRule{{
+			PathPattern: ".*",
+			Match:       &chaos.RuleMatch{Tags: []string{"team-orders"}},
+			Faults:      []chaos.FaultConfig{{Type: chaos.FaultError, Probability: 1, Config: map[string]interface{}{"defaultCode": 503}}},
+			Probability: 1,
+		}},
+	})
+	require.NoError(t, err)
+	mc.SetChaosInjector(injector)
+	wrapped := mc.Wrap(handler)
+
+	rec := httptest.NewRecorder()
+	wrapped.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/orders", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code, "tagged mock should get the fault")
+
+	rec = httptest.NewRecorder()
+	wrapped.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/api/orders", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code, "HEAD served by the tagged GET mock should get the fault")
+
+	rec = httptest.NewRecorder()
+	wrapped.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users", nil))
+	assert.Equal(t, http.StatusOK, rec.Code, "other mocks should be untouched")
+}
+
+func TestDynamicChaosHandler_FaultLogLinksRequests(t *testing.T) {
+	t.Parallel()
+
+	store := storage.NewInMemoryMockStore()
+	require.NoError(t, store.Set(createTestHTTPMock("slow", "/api/slow", "GET", 200, `{}`)))
+	require.NoError(t, store.Set(createTestHTTPMock("broken", "/api/broken", "GET", 200, `{}`)))
+	handler := NewHandler(store)
+	logger := NewInMemoryRequestLogger(100)
+	handler.SetLogger(logger)
+
+	mc, err := NewMiddlewareChain(&config.ServerConfiguration{}, WithChainChaosAborts(handler.logChaosAbort))
+	require.NoError(t, err)
+	injector, err := chaos.NewInjector(&chaos.ChaosConfig{
+		Enabled: true,
+		Seed:    7,
+		Rules: []chaos.ChaosRule{
+			{
+				PathPattern: "^/api/slow$",
+				Faults:      []chaos.FaultConfig{{Type: chaos.FaultLatency, Probability: 1, Config: map[string]interface{}{"min": "1ms", "max": "1ms"}}},
+				Probability: 1,
+			},
+			{
+				PathPattern: "^/api/broken$",
+				Faults:      []chaos.FaultConfig{{Type: chaos.FaultError, Probability: 1, Config: map[string]interface{}{"defaultCode": 503}}},
+				Probability: 1,
+			},
+		},
+	})
+	require.NoError(t, err)
+	mc.SetChaosInjector(injector)
+	wrapped := mc.Wrap(handler)
+
+	for _, path := range []string{"/api/slow", "/api/broken"} {
+		wrapped.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	log := injector.FaultLog()
+	assert.Equal(t, int64(7), log.Seed)
+	require.Len(t, log.Records, 2)
+	for _, rec := range log.Records {
+		require.NotEmpty(t, rec.RequestID, "record for %s is linked to its request", rec.Target)
+		entry := logger.Get(rec.RequestID)
+		require.NotNil(t, entry)
+		assert.Equal(t, rec.Target, entry.Path)
+		require.NotNil(t, entry.Chaos)
+		assert.Equal(t, rec.Seq, entry.Chaos.FaultSeq)
+	}
+
+	broken := logger.Get(log.Records[1].RequestID)
+	assert.Equal(t, http.StatusServiceUnavailable, broken.ResponseStatus, "requests answered by a fault are logged")
+	assert.Equal(t, []string{"error"}, broken.Chaos.Faults)
+}
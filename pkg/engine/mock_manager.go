@@ -392,6 +392,7 @@ func (mm *MockManager) registerWebSocketMock(m *mock.Mock) error {
 	}
 
 	cfg := &config.WebSocketEndpointConfig{
+		EntityMeta:         config.EntityMeta{WorkspaceID: m.WorkspaceID},
 		ID:                 m.ID,
 		Name:               m.Name,
 		Path:               ws.Path,
@@ -405,6 +406,7 @@ func (mm *MockManager) registerWebSocketMock(m *mock.Mock) error {
 		IdleTimeout:        ws.IdleTimeout,
 		MaxConnections:     ws.MaxConnections,
 		EchoMode:           ws.EchoMode,
+		StatefulStream:     ws.StatefulStream,
 	}
 
 	return mm.handler.RegisterWebSocketEndpoint(cfg)
@@ -424,6 +426,7 @@ func (mm *MockManager) registerGraphQLMock(m *mock.Mock) error {
 		Name:          m.Name,
 		ParentID:      m.ParentID,
 		MetaSortKey:   m.MetaSortKey,
+		WorkspaceID:   m.WorkspaceID,
 		Path:          gqlSpec.Path,
 		Schema:        gqlSpec.Schema,
 		SchemaFile:    gqlSpec.SchemaFile,
@@ -435,23 +438,7 @@ func (mm *MockManager) registerGraphQLMock(m *mock.Mock) error {
 	if gqlSpec.Resolvers != nil {
 		cfg.Resolvers = make(map[string]graphql.ResolverConfig)
 		for path, resolver := range gqlSpec.Resolvers {
-			rc := graphql.ResolverConfig{
-				Response: resolver.Response,
-				Delay:    resolver.Delay,
-			}
-			if resolver.Match != nil {
-				rc.Match = &graphql.ResolverMatch{
-					Args: resolver.Match.Args,
-				}
-			}
-			if resolver.Error != nil {
-				rc.Error = &graphql.GraphQLErrorConfig{
-					Message:    resolver.Error.Message,
-					Path:       resolver.Error.Path,
-					Extensions: resolver.Error.Extensions,
-				}
-			}
-			cfg.Resolvers[path] = rc
+			cfg.Resolvers[path] = convertGraphQLResolverConfig(resolver)
 		}
 	}
 
@@ -473,6 +460,10 @@ func (mm *MockManager) registerGraphQLMock(m *mock.Mock) error {
 	// Create executor and handler
 	executor := graphql.NewExecutor(schema, cfg)
 	handler := graphql.NewHandler(executor, cfg)
+	// Set stateful executor if available
+	if mm.protocolManager != nil && mm.protocolManager.gqlStatefulExec != nil {
+		handler.SetStatefulExecutor(mm.protocolManager.gqlStatefulExec)
+	}
 
 	// Register with the HTTP handler
 	mm.handler.RegisterGraphQLHandler(cfg.Path, handler)
@@ -706,6 +697,35 @@ func (mm *MockManager) registerGRPCMock(m *mock.Mock) error {
 	return nil
 }
 
+// convertGraphQLResolverConfig converts a mock.ResolverConfig into the
+// graphql.ResolverConfig the executor consumes, carrying match variants
+// through in order.
+func convertGraphQLResolverConfig(resolver mock.ResolverConfig) graphql.ResolverConfig {
+	rc := graphql.ResolverConfig{
+		Response:        resolver.Response,
+		Delay:           resolver.Delay,
+		StatefulBinding: resolver.StatefulBinding,
+	}
+	if resolver.Match != nil {
+		rc.Match = &graphql.ResolverMatch{
+			Args: resolver.Match.Args,
+		}
+	}
+	if resolver.Error != nil {
+		rc.Error = &graphql.GraphQLErrorConfig{
+			Message:    resolver.Error.Message,
+			Path:       resolver.Error.Path,
+			Extensions: resolver.Error.Extensions,
+		}
+	}
+	for _, variant := range resolver.Variants {
+		converted := convertGraphQLResolverConfig(variant)
+		converted.Variants = nil
+		rc.Variants = append(rc.Variants, converted)
+	}
+	return rc
+}
+
 // convertGRPCMethodConfig converts a mock.MethodConfig (admin/config form) into
 // the grpc.MethodConfig the server consumes. It carries ALL match variants
 // through: the primary config's fields plus each entry in Variants, so that
@@ -714,9 +734,10 @@ func (mm *MockManager) registerGRPCMock(m *mock.Mock) error {
 // level is meaningful.
 func convertGRPCMethodConfig(method mock.MethodConfig) grpc.MethodConfig {
 	grpcMethod := grpc.MethodConfig{
-		Response:    method.Response,
-		Delay:       method.Delay,
-		StreamDelay: method.StreamDelay,
+		Response:        method.Response,
+		Delay:           method.Delay,
+		StreamDelay:     method.StreamDelay,
+		StatefulBinding: method.StatefulBinding,
 	}
 	// Convert responses slice
 	grpcMethod.Responses = append(grpcMethod.Responses, method.Responses...)
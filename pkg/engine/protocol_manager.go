@@ -26,7 +26,11 @@ type ProtocolManager struct {
 	requestLogger    RequestLogger
 	log              *slog.Logger
 	mu               sync.RWMutex
-	soapStatefulExec soap.StatefulExecutor // optional: stateful bridge adapter for SOAP handlers
+	soapStatefulExec soap.StatefulExecutor    // optional: stateful bridge adapter for SOAP handlers
+	gqlStatefulExec  graphql.StatefulExecutor // optional: stateful bridge adapter for GraphQL resolvers
+	grpcStatefulExec grpc.StatefulExecutor    // optional: stateful bridge adapter for gRPC methods
+	grpcChaos        grpc.ChaosSource         // optional: chaos injector lookup for gRPC servers
+	mqttChaos        mqtt.ChaosSource         // optional: chaos injector lookup for MQTT brokers
 
 	// Protocol handlers
 	graphqlHandlers    []*graphql.Handler
@@ -72,6 +76,38 @@ func (pm *ProtocolManager) SetSOAPStatefulExecutor(executor soap.StatefulExecuto
 	pm.soapStatefulExec = executor
 }
 
+// SetGraphQLStatefulExecutor sets the stateful executor for GraphQL resolvers.
+// When set, newly registered GraphQL mocks can bind resolvers to stateful tables.
+func (pm *ProtocolManager) SetGraphQLStatefulExecutor(executor graphql.StatefulExecutor) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.gqlStatefulExec = executor
+}
+
+// SetGRPCStatefulExecutor sets the stateful executor for gRPC servers.
+// When set, newly started gRPC servers can bind methods to stateful tables.
+func (pm *ProtocolManager) SetGRPCStatefulExecutor(executor grpc.StatefulExecutor) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.grpcStatefulExec = executor
+}
+
+// SetGRPCChaosSource sets where gRPC servers get the chaos injector from.
+// When set, newly started gRPC servers apply the engine's gRPC chaos rules.
+func (pm *ProtocolManager) SetGRPCChaosSource(source grpc.ChaosSource) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.grpcChaos = source
+}
+
+// SetMQTTChaosSource sets where MQTT brokers get the chaos injector from.
+// When set, newly started brokers apply the engine's mqtt message chaos rules.
+func (pm *ProtocolManager) SetMQTTChaosSource(source mqtt.ChaosSource) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.mqttChaos = source
+}
+
 // Registry returns the protocol handler registry.
 func (pm *ProtocolManager) Registry() *protocol.Registry {
 	return pm.registry
@@ -174,6 +210,11 @@ func (pm *ProtocolManager) startGraphQL(cfg *ProtocolConfig, handler *Handler) e
 			return fmt.Errorf("failed to create GraphQL endpoint %s: %w", gqlCfg.Path, err)
 		}
 
+		// Set stateful executor if available
+		if pm.gqlStatefulExec != nil {
+			gqlHandler.SetStatefulExecutor(pm.gqlStatefulExec)
+		}
+
 		// Set request logger for unified logging
 		if pm.requestLogger != nil {
 			gqlHandler.SetRequestLogger(pm.requestLogger)
@@ -336,6 +377,12 @@ func (pm *ProtocolManager) startGRPC(ctx context.Context, cfg *ProtocolConfig) e
 		if pm.requestLogger != nil {
 			server.SetRequestLogger(pm.requestLogger)
 		}
+		if pm.grpcStatefulExec != nil {
+			server.SetStatefulExecutor(pm.grpcStatefulExec)
+		}
+		if pm.grpcChaos != nil {
+			server.SetChaosSource(pm.grpcChaos)
+		}
 
 		// Start the server
 		if err := server.Start(ctx); err != nil {
@@ -377,6 +424,9 @@ func (pm *ProtocolManager) startMQTT(ctx context.Context, cfg *ProtocolConfig) e
 		if pm.requestLogger != nil {
 			broker.SetRequestLogger(pm.requestLogger)
 		}
+		if pm.mqttChaos != nil {
+			broker.SetChaosSource(pm.mqttChaos)
+		}
 
 		// Start the broker
 		if err := broker.Start(ctx); err != nil {
@@ -473,6 +523,12 @@ func (pm *ProtocolManager) StartGRPCServer(cfg *grpc.GRPCConfig) (*grpc.Server,
 	if pm.requestLogger != nil {
 		server.SetRequestLogger(pm.requestLogger)
 	}
+	if pm.grpcStatefulExec != nil {
+		server.SetStatefulExecutor(pm.grpcStatefulExec)
+	}
+	if pm.grpcChaos != nil {
+		server.SetChaosSource(pm.grpcChaos)
+	}
 
 	// Start the server. If the port was just released by a stopped server,
 	// the OS may need a moment to fully free it — retry once after a short delay.
@@ -584,6 +640,9 @@ func (pm *ProtocolManager) StartMQTTBroker(cfg *mqtt.MQTTConfig) (*mqtt.Broker,
 	if pm.requestLogger != nil {
 		broker.SetRequestLogger(pm.requestLogger)
 	}
+	if pm.mqttChaos != nil {
+		broker.SetChaosSource(pm.mqttChaos)
+	}
 
 	// Start the broker
 	if err := broker.Start(context.Background()); err != nil {
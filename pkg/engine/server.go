@@ -10,6 +10,7 @@ import (
 	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/net/netutil"
@@ -23,6 +24,7 @@ import (
 	"github.com/getmockd/mockd/pkg/protocol"
 	"github.com/getmockd/mockd/pkg/ratelimit"
 	"github.com/getmockd/mockd/pkg/requestlog"
+	"github.com/getmockd/mockd/pkg/shadow"
 	"github.com/getmockd/mockd/pkg/stateful"
 	"github.com/getmockd/mockd/pkg/store"
 	"github.com/getmockd/mockd/pkg/tracing"
@@ -74,6 +76,7 @@ type Server struct {
 	handler         *Handler
 	httpHandler     http.Handler // The actual handler used by servers (may be wrapped with middleware)
 	middlewareChain *MiddlewareChain
+	chaosChain      atomic.Pointer[MiddlewareChain] // middlewareChain, readable without s.mu
 	tlsManager      *TLSManager
 	tlsConfig       *tls.Config
 	mu              sync.RWMutex
@@ -100,6 +103,12 @@ type Server struct {
 
 	// Rate limiter for the mock engine (optional)
 	rateLimiter *ratelimit.PerIPLimiter
+
+	// Lifecycle scheduler for time-driven stateful transitions (runs while started)
+	lifecycleScheduler *stateful.LifecycleScheduler
+
+	// Drift found by shadow mode; kept across shadow configuration changes
+	shadowTracker *shadow.Tracker
 }
 
 // ServerOption is a functional option for configuring a Server.
@@ -171,7 +180,7 @@ func NewServer(cfg *config.ServerConfiguration, opts ...ServerOption) *Server {
 	pm := NewProtocolManager()
 	pm.SetRequestLogger(logger)
 
-	// Create stateful bridge and wire into protocol manager for SOAP support
+	// Create stateful bridge and wire into protocol manager for SOAP and GraphQL support
 	// and into the handler for HTTP custom operation support.
 	// The bridge is stored on the server so ConfigLoader can register custom operations.
 	bridge := stateful.NewBridge(statefulStore)
@@ -179,6 +188,13 @@ func NewServer(cfg *config.ServerConfiguration, opts ...ServerOption) *Server {
 		bridge.SetTracer(s.tracer)
 	}
 	pm.SetSOAPStatefulExecutor(newSOAPStatefulAdapter(bridge))
+	pm.SetGraphQLStatefulExecutor(newGraphQLStatefulAdapter(bridge))
+	grpcStateful := newGRPCStatefulAdapter(bridge)
+	grpcStateful.isolation = handler.stateIsolation.Load
+	pm.SetGRPCStatefulExecutor(grpcStateful)
+	pm.SetGRPCChaosSource(s.liveChaosInjector)
+	pm.SetMQTTChaosSource(s.liveChaosInjector)
+	handler.SetChaosSource(s.liveChaosInjector)
 	handler.SetStatefulBridge(bridge)
 
 	mockManager := NewMockManager(mockStore, handler, pm)
@@ -192,6 +208,13 @@ func NewServer(cfg *config.ServerConfiguration, opts ...ServerOption) *Server {
 	s.protocolManager = pm
 	s.tlsManager = NewTLSManagerFromServerConfig(cfg)
 	s.mockManager = mockManager
+	s.shadowTracker = shadow.NewTracker()
+
+	if cfg.StateIsolation != nil {
+		if err := handler.SetStateIsolation(cfg.StateIsolation); err != nil {
+			s.log.Warn("state isolation disabled", "error", err)
+		}
+	}
 
 	// Initialize config loader (needs server reference)
 	s.configLoader = NewConfigLoader(s)
@@ -266,7 +289,10 @@ func (s *Server) Start() error {
 	}
 
 	// Initialize middleware chain (handles validation, chaos, audit, and tracing)
-	var mcOpts []MiddlewareChainOption
+	mcOpts := []MiddlewareChainOption{
+		WithChainMockTargets(s.handler.chaosMockTarget),
+		WithChainChaosAborts(s.handler.logChaosAbort),
+	}
 	if s.tracer != nil {
 		mcOpts = append(mcOpts, WithChainTracer(s.tracer))
 	}
@@ -275,6 +301,7 @@ func (s *Server) Start() error {
 		return err
 	}
 	s.middlewareChain = mc
+	s.chaosChain.Store(mc)
 
 	// Wrap handler with middleware chain
 	s.httpHandler = s.middlewareChain.Wrap(s.handler)
@@ -395,6 +422,9 @@ func (s *Server) Start() error {
 		}
 	}
 
+	s.lifecycleScheduler = stateful.NewLifecycleScheduler(s.statefulStore, stateful.DefaultLifecycleInterval)
+	s.lifecycleScheduler.Start()
+
 	s.running = true
 	s.startTime = time.Now()
 	s.log.Info("engine started", "http_port", s.cfg.HTTPPort, "https_port", s.cfg.HTTPSPort)
@@ -428,6 +458,7 @@ func (s *Server) teardownOnStartupFailure() {
 			s.log.Warn("failed to close middleware chain after startup error", "error", closeErr)
 		}
 		s.middlewareChain = nil
+		s.chaosChain.Store(nil)
 	}
 }
 
@@ -451,6 +482,17 @@ func (s *Server) Stop() error {
 		s.rateLimiter = nil
 	}
 
+	// Stop lifecycle transitions before tearing down protocol handlers
+	if s.lifecycleScheduler != nil {
+		s.lifecycleScheduler.Stop()
+		s.lifecycleScheduler = nil
+	}
+
+	// Stop shadow mode; queued upstream requests finish in the background
+	if prev := s.handler.SetShadower(nil); prev != nil {
+		go prev.Close()
+	}
+
 	// Stop the control API
 	if s.controlAPI != nil {
 		if err := s.controlAPI.Stop(ctx); err != nil {
@@ -481,6 +523,7 @@ func (s *Server) Stop() error {
 			errs = append(errs, fmt.Errorf("middleware chain close: %w", err))
 		}
 		s.middlewareChain = nil
+		s.chaosChain.Store(nil)
 	}
 
 	s.running = false
@@ -826,6 +869,16 @@ func (s *Server) ChaosInjector() *chaos.Injector {
 	return s.middlewareChain.ChaosInjector()
 }
 
+// liveChaosInjector returns the chaos injector for gRPC calls and message
+// streams. It does not take s.mu, which Stop holds while waiting for in-flight
+// calls and connections to finish.
+func (s *Server) liveChaosInjector() *chaos.Injector {
+	if mc := s.chaosChain.Load(); mc != nil {
+		return mc.ChaosInjector()
+	}
+	return nil
+}
+
 // SetChaosInjector sets the chaos injector for dynamic chaos injection.
 // Unlike startup-time configuration, this updates the injector dynamically
 // and requires the middlewareChain to be in place.
@@ -840,6 +893,49 @@ func (s *Server) SetChaosInjector(injector *chaos.Injector) error {
 	return nil
 }
 
+// ShadowConfig returns the shadow mode configuration, or nil when shadow
+// mode is disabled.
+func (s *Server) ShadowConfig() *shadow.Config {
+	sh := s.handler.Shadower()
+	if sh == nil {
+		return nil
+	}
+	cfg := sh.Config()
+	return &cfg
+}
+
+// SetShadowConfig enables or reconfigures shadow mode, or disables it when
+// cfg is nil or not enabled. Requests already queued for the previous
+// configuration are still compared.
+func (s *Server) SetShadowConfig(cfg *shadow.Config) error {
+	var sh *shadow.Shadower
+	if cfg != nil && cfg.Enabled {
+		var err error
+		if sh, err = shadow.New(cfg, s.shadowTracker); err != nil {
+			return err
+		}
+	}
+	if prev := s.handler.SetShadower(sh); prev != nil {
+		go prev.Close()
+	}
+	return nil
+}
+
+// DriftReport returns the drift found by shadow mode.
+func (s *Server) DriftReport() *shadow.Report {
+	report := s.shadowTracker.Report()
+	if cfg := s.ShadowConfig(); cfg != nil {
+		report.Enabled = true
+		report.Upstream = cfg.Upstream
+	}
+	return report
+}
+
+// ResetDrift clears the drift report.
+func (s *Server) ResetDrift() {
+	s.shadowTracker.Reset()
+}
+
 // Validator returns the OpenAPI validator (for admin API use).
 func (s *Server) Validator() *validation.OpenAPIValidator {
 	s.mu.RLock()
@@ -34,14 +34,15 @@ func (a *soapStatefulAdapter) ExecuteStateful(ctx context.Context, req *soap.Sta
 
 	// Translate soap.StatefulRequest → stateful.OperationRequest
 	// SOAP operations use the default workspace since the SOAP protocol
-	// does not yet carry workspace context.
+	// does not yet carry workspace context, partitioned by the request's
+	// isolation key.
 	opReq := &stateful.OperationRequest{
 		Resource:      req.Resource,
 		Action:        stateful.Action(req.Action),
 		OperationName: req.OperationName,
 		ResourceID:    req.ResourceID,
 		Data:          req.Data,
-		WorkspaceID:   "",
+		WorkspaceID:   contextWorkspace(ctx, ""),
 	}
 
 	// Translate filter
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/getmockd/mockd/pkg/mock"
 	"github.com/getmockd/mockd/pkg/template"
 	"github.com/vektah/gqlparser/v2"
 	"github.com/vektah/gqlparser/v2/ast"
@@ -19,6 +20,8 @@ type Executor struct {
 	config         *GraphQLConfig
 	resolvers      map[string][]ResolverConfig // "Query.user" -> resolvers (multiple for conditional matching)
 	templateEngine *template.Engine
+
+	statefulExecutor StatefulExecutor // optional: backs resolvers with a StatefulBinding
 }
 
 // NewExecutor creates a new GraphQL executor with the given schema and configuration.
@@ -33,7 +36,13 @@ func NewExecutor(schema *Schema, config *GraphQLConfig) *Executor {
 	// Index resolvers by path for efficient lookup
 	if config != nil && config.Resolvers != nil {
 		for path, resolver := range config.Resolvers {
+			variants := resolver.Variants
+			resolver.Variants = nil
 			e.resolvers[path] = append(e.resolvers[path], resolver)
+			for _, variant := range variants {
+				variant.Variants = nil
+				e.resolvers[path] = append(e.resolvers[path], variant)
+			}
 		}
 	}
 
@@ -697,7 +706,7 @@ func (e *Executor) executeSelectionSetWithDoc(ctx context.Context, doc *ast.Quer
 			resolver := e.findResolver(path, args)
 
 			// Resolve the field
-			value, err := e.resolveField(ctx, s, resolver, variables)
+			value, err := e.resolveField(ctx, opType, s, resolver, variables)
 			if err != nil {
 				if err.Path == nil {
 					err.Path = []interface{}{alias}
@@ -708,7 +717,12 @@ func (e *Executor) executeSelectionSetWithDoc(ctx context.Context, doc *ast.Quer
 				// Prune the response to only include selected sub-fields
 				// This ensures @skip/@include on sub-fields work correctly
 				if s.SelectionSet != nil {
-					value = e.pruneResponse(doc, e.fieldTypeName(opType, s.Name), value, s.SelectionSet, variables)
+					pr := &pruneState{ctx: ctx, path: []interface{}{alias}}
+					if resolver != nil {
+						pr.table = statefulItemTable(resolver.StatefulBinding)
+					}
+					value = e.pruneResponse(pr, doc, e.fieldTypeName(opType, s.Name), value, s.SelectionSet, variables)
+					errors = append(errors, pr.errors...)
 				}
 				result[alias] = value
 			}
@@ -751,11 +765,23 @@ func (e *Executor) executeSelectionSetWithDoc(ctx context.Context, doc *ast.Quer
 	return result, errors
 }
 
+// pruneState carries request context through pruning so fields bound to
+// stateful tables can be resolved against their parent object.
+type pruneState struct {
+	ctx    context.Context
+	path   []interface{}
+	errors []*GraphQLError
+	// table is the stateful table the object being pruned was read from,
+	// "" when it did not come from a table.
+	table string
+}
+
 // pruneResponse filters a resolved value to only include fields present in the selection set,
 // respecting @skip/@include directives. This is necessary because mock resolvers return
 // complete pre-configured response objects, and directive-based field exclusion needs to
-// happen as a post-processing step.
-func (e *Executor) pruneResponse(doc *ast.QueryDocument, typeName string, value interface{}, selections ast.SelectionSet, variables map[string]interface{}) interface{} {
+// happen as a post-processing step. Selected fields with a stateful resolver
+// (e.g. User.orders) are resolved from the table using the parent object.
+func (e *Executor) pruneResponse(pr *pruneState, doc *ast.QueryDocument, typeName string, value interface{}, selections ast.SelectionSet, variables map[string]interface{}) interface{} {
 	if value == nil || selections == nil {
 		return value
 	}
@@ -764,14 +790,16 @@ func (e *Executor) pruneResponse(doc *ast.QueryDocument, typeName string, value
 	case map[string]interface{}:
 		// Collect all requested field names (expanding fragments)
 		result := make(map[string]interface{})
-		e.collectSelectedFields(doc, typeName, result, v, selections, variables)
+		e.collectSelectedFields(pr, doc, typeName, result, v, selections, variables)
 		return result
 
 	case []interface{}:
 		// Apply pruning to each element in the array
 		pruned := make([]interface{}, len(v))
 		for i, item := range v {
-			pruned[i] = e.pruneResponse(doc, typeName, item, selections, variables)
+			pr.path = append(pr.path, i)
+			pruned[i] = e.pruneResponse(pr, doc, typeName, item, selections, variables)
+			pr.path = pr.path[:len(pr.path)-1]
 		}
 		return pruned
 
@@ -782,7 +810,7 @@ func (e *Executor) pruneResponse(doc *ast.QueryDocument, typeName string, value
 
 // collectSelectedFields walks the selection set and copies matching fields from src to dst,
 // respecting @skip/@include directives and expanding fragments.
-func (e *Executor) collectSelectedFields(doc *ast.QueryDocument, typeName string, dst, src map[string]interface{}, selections ast.SelectionSet, variables map[string]interface{}) {
+func (e *Executor) collectSelectedFields(pr *pruneState, doc *ast.QueryDocument, typeName string, dst, src map[string]interface{}, selections ast.SelectionSet, variables map[string]interface{}) {
 	for _, sel := range selections {
 		switch s := sel.(type) {
 		case *ast.Field:
@@ -804,9 +832,18 @@ func (e *Executor) collectSelectedFields(doc *ast.QueryDocument, typeName string
 				}
 				continue
 			}
+			if binding := e.statefulResolver(typeName, s.Name, e.extractArguments(s, variables)); binding != nil {
+				e.collectStatefulField(pr, doc, typeName, s, alias, binding, dst, src, variables)
+				continue
+			}
 			if val, ok := src[s.Name]; ok {
 				if s.SelectionSet != nil {
-					val = e.pruneResponse(doc, e.fieldTypeName(typeName, s.Name), val, s.SelectionSet, variables)
+					pr.path = append(pr.path, alias)
+					table := pr.table
+					pr.table = ""
+					val = e.pruneResponse(pr, doc, e.fieldTypeName(typeName, s.Name), val, s.SelectionSet, variables)
+					pr.table = table
+					pr.path = pr.path[:len(pr.path)-1]
 				}
 				dst[alias] = val
 			}
@@ -824,7 +861,7 @@ func (e *Executor) collectSelectedFields(doc *ast.QueryDocument, typeName string
 						if frag.TypeCondition != "" {
 							fragType = frag.TypeCondition
 						}
-						e.collectSelectedFields(doc, fragType, dst, src, frag.SelectionSet, variables)
+						e.collectSelectedFields(pr, doc, fragType, dst, src, frag.SelectionSet, variables)
 						break
 					}
 				}
@@ -839,11 +876,35 @@ func (e *Executor) collectSelectedFields(doc *ast.QueryDocument, typeName string
 			if s.TypeCondition != "" {
 				fragType = s.TypeCondition
 			}
-			e.collectSelectedFields(doc, fragType, dst, src, s.SelectionSet, variables)
+			e.collectSelectedFields(pr, doc, fragType, dst, src, s.SelectionSet, variables)
 		}
 	}
 }
 
+// collectStatefulField resolves a field bound to a stateful table using src
+// as the parent object and stores the pruned result in dst.
+func (e *Executor) collectStatefulField(pr *pruneState, doc *ast.QueryDocument, typeName string, field *ast.Field, alias string, binding *mock.GraphQLStatefulBinding, dst, src map[string]interface{}, variables map[string]interface{}) {
+	pr.path = append(pr.path, alias)
+	defer func() { pr.path = pr.path[:len(pr.path)-1] }()
+
+	val, gqlErr := e.resolveStateful(pr.ctx, typeName, field, binding, e.extractArguments(field, variables), src, pr.table)
+	if gqlErr != nil {
+		if gqlErr.Path == nil {
+			gqlErr.Path = append([]interface{}(nil), pr.path...)
+		}
+		pr.errors = append(pr.errors, gqlErr)
+		dst[alias] = nil
+		return
+	}
+	if field.SelectionSet != nil {
+		table := pr.table
+		pr.table = statefulItemTable(binding)
+		val = e.pruneResponse(pr, doc, e.fieldTypeName(typeName, field.Name), val, field.SelectionSet, variables)
+		pr.table = table
+	}
+	dst[alias] = val
+}
+
 // fieldTypeName returns the unwrapped (named) GraphQL type of the field
 // fieldName declared on the type typeName, or "" when it cannot be resolved
 // from the schema. Non-null and list wrappers are stripped so the result is
@@ -1009,8 +1070,8 @@ func (e *Executor) valuesEqual(a, b interface{}) bool {
 	return fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
 }
 
-// resolveField resolves a single field using the resolver configuration.
-func (e *Executor) resolveField(ctx context.Context, field *ast.Field, resolver *ResolverConfig, variables map[string]interface{}) (interface{}, *GraphQLError) {
+// resolveField resolves a single field of typeName using the resolver configuration.
+func (e *Executor) resolveField(ctx context.Context, typeName string, field *ast.Field, resolver *ResolverConfig, variables map[string]interface{}) (interface{}, *GraphQLError) {
 	if resolver == nil {
 		// No resolver configured - return null
 		return nil, nil
@@ -1045,6 +1106,10 @@ func (e *Executor) resolveField(ctx context.Context, field *ast.Field, resolver
 	// Get arguments for template substitution
 	args := e.extractArguments(field, variables)
 
+	if resolver.StatefulBinding != nil {
+		return e.resolveStateful(ctx, typeName, field, resolver.StatefulBinding, args, nil, "")
+	}
+
 	// Apply variable substitution to the response
 	response := e.applyVariables(resolver.Response, args)
 
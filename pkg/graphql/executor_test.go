@@ -810,6 +810,61 @@ func TestExecutor_Execute_ConditionalResolver(t *testing.T) {
 	}
 }
 
+func TestExecutor_Execute_ResolverVariants(t *testing.T) {
+	schema, err := ParseSchema(executorTestSchema)
+	if err != nil {
+		t.Fatalf("ParseSchema() error = %v", err)
+	}
+
+	config := &GraphQLConfig{
+		Resolvers: map[string]ResolverConfig{
+			"Query.user": {
+				Match:    &ResolverMatch{Args: map[string]interface{}{"id": "1"}},
+				Response: map[string]interface{}{"id": "1", "name": "First"},
+				Variants: []ResolverConfig{
+					{
+						Match: &ResolverMatch{Args: map[string]interface{}{"id": "404"}},
+						Error: &GraphQLErrorConfig{Message: "user not found"},
+					},
+					{Response: map[string]interface{}{"id": "0", "name": "Default"}},
+				},
+			},
+		},
+	}
+
+	executor := NewExecutor(schema, config)
+
+	tests := []struct {
+		id       string
+		wantName string
+		wantErr  string
+	}{
+		{id: "1", wantName: "First"},
+		{id: "404", wantErr: "user not found"},
+		{id: "2", wantName: "Default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			resp := executor.Execute(context.Background(), &GraphQLRequest{
+				Query: `query { user(id: "` + tt.id + `") { id name } }`,
+			})
+			if tt.wantErr != "" {
+				if len(resp.Errors) != 1 || resp.Errors[0].Message != tt.wantErr {
+					t.Fatalf("Execute() errors = %v, want %q", resp.Errors, tt.wantErr)
+				}
+				return
+			}
+			if len(resp.Errors) > 0 {
+				t.Fatalf("Execute() returned errors: %v", resp.Errors)
+			}
+			user := resp.Data.(map[string]interface{})["user"].(map[string]interface{})
+			if user["name"] != tt.wantName {
+				t.Errorf("user.name = %v, want %q", user["name"], tt.wantName)
+			}
+		})
+	}
+}
+
 func TestExecutor_Execute_NoResolver(t *testing.T) {
 	schema, err := ParseSchema(executorTestSchema)
 	if err != nil {
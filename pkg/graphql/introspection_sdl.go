@@ -0,0 +1,304 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+)
+
+// IntrospectionQuery is the standard introspection query. Sending it to a
+// GraphQL server returns a response that SchemaFromIntrospection can turn
+// back into SDL.
+const IntrospectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+  }
+}
+
+fragment FullType on __Type {
+  kind
+  name
+  description
+  fields(includeDeprecated: true) {
+    name
+    description
+    args { ...InputValue }
+    type { ...TypeRef }
+  }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) { name description }
+  possibleTypes { ...TypeRef }
+}
+
+fragment InputValue on __InputValue {
+  name
+  description
+  type { ...TypeRef }
+  defaultValue
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType {
+    kind
+    name
+    ofType {
+      kind
+      name
+      ofType {
+        kind
+        name
+        ofType {
+          kind
+          name
+          ofType {
+            kind
+            name
+            ofType {
+              kind
+              name
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// ErrNoIntrospectionSchema is returned when an introspection response does
+// not contain a __schema object.
+var ErrNoIntrospectionSchema = errors.New("introspection response has no __schema")
+
+// introspectionSchema mirrors the __schema object returned by IntrospectionQuery.
+type introspectionSchema struct {
+	QueryType        *introspectionNamed `json:"queryType"`
+	MutationType     *introspectionNamed `json:"mutationType"`
+	SubscriptionType *introspectionNamed `json:"subscriptionType"`
+	Types            []introspectionType `json:"types"`
+}
+
+type introspectionNamed struct {
+	Name string `json:"name"`
+}
+
+type introspectionType struct {
+	Kind          string                    `json:"kind"`
+	Name          string                    `json:"name"`
+	Description   string                    `json:"description"`
+	Fields        []introspectionField      `json:"fields"`
+	InputFields   []introspectionInputValue `json:"inputFields"`
+	Interfaces    []introspectionTypeRef    `json:"interfaces"`
+	EnumValues    []introspectionEnumValue  `json:"enumValues"`
+	PossibleTypes []introspectionTypeRef    `json:"possibleTypes"`
+}
+
+type introspectionField struct {
+	Name        string                    `json:"name"`
+	Description string                    `json:"description"`
+	Args        []introspectionInputValue `json:"args"`
+	Type        introspectionTypeRef      `json:"type"`
+}
+
+type introspectionInputValue struct {
+	Name         string               `json:"name"`
+	Description  string               `json:"description"`
+	Type         introspectionTypeRef `json:"type"`
+	DefaultValue *string              `json:"defaultValue"`
+}
+
+type introspectionEnumValue struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type introspectionTypeRef struct {
+	Kind   string                `json:"kind"`
+	Name   string                `json:"name"`
+	OfType *introspectionTypeRef `json:"ofType"`
+}
+
+// builtinScalars are the scalars every schema has implicitly.
+var builtinScalars = map[string]bool{
+	"String":  true,
+	"Int":     true,
+	"Float":   true,
+	"Boolean": true,
+	"ID":      true,
+}
+
+// SchemaFromIntrospection converts an introspection result into SDL. It
+// accepts either a full response ({"data": {"__schema": ...}}) or the bare
+// data object ({"__schema": ...}). Introspection types and built-in scalars
+// are omitted from the output.
+func SchemaFromIntrospection(data []byte) (string, error) {
+	var envelope struct {
+		Data *struct {
+			Schema *introspectionSchema `json:"__schema"`
+		} `json:"data"`
+		Schema *introspectionSchema `json:"__schema"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return "", fmt.Errorf("failed to parse introspection response: %w", err)
+	}
+
+	schema := envelope.Schema
+	if envelope.Data != nil && envelope.Data.Schema != nil {
+		schema = envelope.Data.Schema
+	}
+	if schema == nil {
+		return "", ErrNoIntrospectionSchema
+	}
+
+	doc := &ast.SchemaDocument{}
+	if def := introspectionSchemaDefinition(schema); def != nil {
+		doc.Schema = append(doc.Schema, def)
+	}
+	for _, t := range schema.Types {
+		if t.Name == "" || strings.HasPrefix(t.Name, "__") || builtinScalars[t.Name] {
+			continue
+		}
+		def, err := introspectionDefinition(t)
+		if err != nil {
+			return "", err
+		}
+		doc.Definitions = append(doc.Definitions, def)
+	}
+
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf).FormatSchemaDocument(doc)
+	return buf.String(), nil
+}
+
+// introspectionSchemaDefinition returns an explicit schema block when the
+// root operation types do not use the default names.
+func introspectionSchemaDefinition(s *introspectionSchema) *ast.SchemaDefinition {
+	roots := []struct {
+		op   ast.Operation
+		name string
+		typ  *introspectionNamed
+	}{
+		{ast.Query, "Query", s.QueryType},
+		{ast.Mutation, "Mutation", s.MutationType},
+		{ast.Subscription, "Subscription", s.SubscriptionType},
+	}
+
+	custom := false
+	def := &ast.SchemaDefinition{}
+	for _, root := range roots {
+		if root.typ == nil || root.typ.Name == "" {
+			continue
+		}
+		if root.typ.Name != root.name {
+			custom = true
+		}
+		def.OperationTypes = append(def.OperationTypes, &ast.OperationTypeDefinition{
+			Operation: root.op,
+			Type:      root.typ.Name,
+		})
+	}
+	if !custom {
+		return nil
+	}
+	return def
+}
+
+func introspectionDefinition(t introspectionType) (*ast.Definition, error) {
+	def := &ast.Definition{
+		Name:        t.Name,
+		Description: t.Description,
+	}
+
+	switch t.Kind {
+	case "SCALAR":
+		def.Kind = ast.Scalar
+	case "OBJECT", "INTERFACE":
+		def.Kind = ast.Object
+		if t.Kind == "INTERFACE" {
+			def.Kind = ast.Interface
+		}
+		for _, f := range t.Fields {
+			field := &ast.FieldDefinition{
+				Name:        f.Name,
+				Description: f.Description,
+				Type:        f.Type.astType(),
+			}
+			for _, arg := range f.Args {
+				field.Arguments = append(field.Arguments, arg.argumentDefinition())
+			}
+			def.Fields = append(def.Fields, field)
+		}
+		for _, iface := range t.Interfaces {
+			def.Interfaces = append(def.Interfaces, iface.Name)
+		}
+	case "UNION":
+		def.Kind = ast.Union
+		for _, member := range t.PossibleTypes {
+			def.Types = append(def.Types, member.Name)
+		}
+	case "ENUM":
+		def.Kind = ast.Enum
+		for _, v := range t.EnumValues {
+			def.EnumValues = append(def.EnumValues, &ast.EnumValueDefinition{
+				Name:        v.Name,
+				Description: v.Description,
+			})
+		}
+	case "INPUT_OBJECT":
+		def.Kind = ast.InputObject
+		for _, f := range t.InputFields {
+			arg := f.argumentDefinition()
+			def.Fields = append(def.Fields, &ast.FieldDefinition{
+				Name:         arg.Name,
+				Description:  arg.Description,
+				Type:         arg.Type,
+				DefaultValue: arg.DefaultValue,
+			})
+		}
+	default:
+		return nil, fmt.Errorf("type %s has unknown kind %q", t.Name, t.Kind)
+	}
+
+	return def, nil
+}
+
+func (v introspectionInputValue) argumentDefinition() *ast.ArgumentDefinition {
+	arg := &ast.ArgumentDefinition{
+		Name:        v.Name,
+		Description: v.Description,
+		Type:        v.Type.astType(),
+	}
+	if v.DefaultValue != nil {
+		// Default values are already GraphQL literals; keep them verbatim.
+		arg.DefaultValue = &ast.Value{Kind: ast.EnumValue, Raw: *v.DefaultValue}
+	}
+	return arg
+}
+
+func (r introspectionTypeRef) astType() *ast.Type {
+	switch r.Kind {
+	case "NON_NULL":
+		if r.OfType == nil {
+			return ast.NamedType("String", nil)
+		}
+		t := r.OfType.astType()
+		t.NonNull = true
+		return t
+	case "LIST":
+		if r.OfType == nil {
+			return ast.ListType(ast.NamedType("String", nil), nil)
+		}
+		return ast.ListType(r.OfType.astType(), nil)
+	default:
+		return ast.NamedType(r.Name, nil)
+	}
+}
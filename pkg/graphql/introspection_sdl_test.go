@@ -0,0 +1,94 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSchemaFromIntrospection_RoundTrip(t *testing.T) {
+	schema, err := ParseSchema(testSchema)
+	if err != nil {
+		t.Fatalf("ParseSchema() error = %v", err)
+	}
+
+	// Introspect the mock executor and rebuild the SDL from its answer.
+	executor := NewExecutor(schema, &GraphQLConfig{Introspection: true})
+	resp := executor.Execute(context.Background(), &GraphQLRequest{Query: IntrospectionQuery})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("introspection errors = %v", resp.Errors)
+	}
+	body, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	sdl, err := SchemaFromIntrospection(body)
+	if err != nil {
+		t.Fatalf("SchemaFromIntrospection() error = %v", err)
+	}
+	if strings.Contains(sdl, "__Schema") || strings.Contains(sdl, "scalar String") {
+		t.Errorf("SDL contains introspection or built-in types:\n%s", sdl)
+	}
+
+	rebuilt, err := ParseSchema(sdl)
+	if err != nil {
+		t.Fatalf("ParseSchema(rebuilt) error = %v\n%s", err, sdl)
+	}
+	for _, q := range schema.ListQueries() {
+		if rebuilt.GetQueryField(q) == nil {
+			t.Errorf("rebuilt schema is missing Query.%s", q)
+		}
+	}
+	for _, m := range schema.ListMutations() {
+		if rebuilt.GetMutationField(m) == nil {
+			t.Errorf("rebuilt schema is missing Mutation.%s", m)
+		}
+	}
+	if got := rebuilt.GetQueryField("user").Type.String(); got != "User" {
+		t.Errorf("Query.user type = %s, want User", got)
+	}
+	if got := rebuilt.GetQueryField("users").Type.String(); got != "[User!]!" {
+		t.Errorf("Query.users type = %s, want [User!]!", got)
+	}
+	if got := rebuilt.GetEnumValues("Role"); len(got) == 0 {
+		t.Error("rebuilt schema has no Role enum values")
+	}
+}
+
+func TestSchemaFromIntrospection_BareSchemaAndCustomRoots(t *testing.T) {
+	body := `{"__schema": {
+		"queryType": {"name": "RootQuery"},
+		"types": [
+			{"kind": "OBJECT", "name": "RootQuery", "fields": [
+				{"name": "ping", "args": [
+					{"name": "times", "type": {"kind": "SCALAR", "name": "Int"}, "defaultValue": "1"}
+				], "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "String"}}}
+			]},
+			{"kind": "SCALAR", "name": "String"},
+			{"kind": "OBJECT", "name": "__Type", "fields": []}
+		]
+	}}`
+
+	sdl, err := SchemaFromIntrospection([]byte(body))
+	if err != nil {
+		t.Fatalf("SchemaFromIntrospection() error = %v", err)
+	}
+	if !strings.Contains(sdl, "query: RootQuery") || !strings.Contains(sdl, "times: Int = 1") {
+		t.Errorf("unexpected SDL:\n%s", sdl)
+	}
+	if _, err := ParseSchema(sdl); err != nil {
+		t.Errorf("ParseSchema() error = %v\n%s", err, sdl)
+	}
+}
+
+func TestSchemaFromIntrospection_Errors(t *testing.T) {
+	if _, err := SchemaFromIntrospection([]byte(`{"data": {"user": null}}`)); !errors.Is(err, ErrNoIntrospectionSchema) {
+		t.Errorf("error = %v, want ErrNoIntrospectionSchema", err)
+	}
+	if _, err := SchemaFromIntrospection([]byte(`not json`)); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
@@ -0,0 +1,329 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/getmockd/mockd/pkg/mock"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Stateful actions accepted by GraphQLStatefulBinding.Action.
+const (
+	StatefulActionGet    = "get"
+	StatefulActionList   = "list"
+	StatefulActionCreate = "create"
+	StatefulActionUpdate = "update"
+	StatefulActionPatch  = "patch"
+	StatefulActionDelete = "delete"
+	StatefulActionCustom = "custom"
+)
+
+// Default argument names used by stateful bindings.
+const (
+	defaultIDArg    = "id"
+	defaultInputArg = "input"
+)
+
+// listArgs are the arguments list bindings read as pagination rather than filters.
+var listArgs = map[string]bool{"limit": true, "offset": true, "sort": true, "order": true}
+
+// StatefulRequest is a protocol-agnostic request to perform a table action.
+// The executor builds it from the field's arguments (and parent object, for
+// nested fields); the StatefulExecutor provided by the engine runs it.
+type StatefulRequest struct {
+	// WorkspaceID identifies the workspace whose tables are used.
+	WorkspaceID string
+	// Resource is the stateful table name (e.g., "users").
+	Resource string
+	// Action is the table action to perform.
+	Action string
+	// OperationName is the custom operation to execute when Action is "custom".
+	OperationName string
+	// ResourceID is the item ID for single-item actions.
+	ResourceID string
+	// Data is the payload for create, update, patch and custom.
+	Data map[string]interface{}
+	// Filter contains pagination and equality filters for list.
+	Filter *StatefulFilter
+}
+
+// StatefulFilter contains pagination/filter parameters for list actions.
+type StatefulFilter struct {
+	Limit   int
+	Offset  int
+	Sort    string
+	Order   string
+	Filters map[string]string
+}
+
+// StatefulResult is the protocol-agnostic result of a table action.
+type StatefulResult struct {
+	// Item is the single item result (get, create, update, patch, delete, custom).
+	Item map[string]interface{}
+	// Items is the list result.
+	Items []map[string]interface{}
+	// Meta contains pagination metadata for list results.
+	Meta *StatefulListMeta
+	// Error is the GraphQL error to report, if any.
+	Error *GraphQLError
+}
+
+// StatefulListMeta contains pagination metadata.
+type StatefulListMeta struct {
+	Total  int
+	Count  int
+	Offset int
+	Limit  int
+}
+
+// StatefulExecutor is the interface that the engine provides to execute
+// table actions. This decouples the GraphQL package from pkg/stateful; the
+// engine wires in an implementation that delegates to stateful.Bridge.
+type StatefulExecutor interface {
+	ExecuteStateful(ctx context.Context, req *StatefulRequest) *StatefulResult
+}
+
+// StatefulIDFieldProvider is implemented by a StatefulExecutor that can report
+// a table's configured ID field. Nested list bindings use it to read the
+// parent's ID; without it the parent's "id" field is used.
+type StatefulIDFieldProvider interface {
+	StatefulIDField(ctx context.Context, workspaceID, table string) string
+}
+
+// SetStatefulExecutor configures the executor used by resolvers with a
+// StatefulBinding. Without one, bound fields resolve to an error.
+func (e *Executor) SetStatefulExecutor(executor StatefulExecutor) {
+	e.statefulExecutor = executor
+}
+
+// GetStatefulExecutor returns the stateful executor, if configured.
+func (e *Executor) GetStatefulExecutor() StatefulExecutor {
+	return e.statefulExecutor
+}
+
+// SetStatefulExecutor configures the stateful executor for this handler's
+// resolvers. See Executor.SetStatefulExecutor.
+func (h *Handler) SetStatefulExecutor(executor StatefulExecutor) {
+	h.executor.SetStatefulExecutor(executor)
+}
+
+// statefulResolver returns the stateful binding for typeName.fieldName, or nil
+// when the field is not bound to a table.
+func (e *Executor) statefulResolver(typeName, fieldName string, args map[string]interface{}) *mock.GraphQLStatefulBinding {
+	if typeName == "" {
+		return nil
+	}
+	resolver := e.findResolver(typeName+"."+fieldName, args)
+	if resolver == nil {
+		return nil
+	}
+	return resolver.StatefulBinding
+}
+
+// resolveStateful resolves a bound field. parent is the enclosing object for
+// fields on object types and nil for root fields; parentTable is the table
+// parent was read from, "" when unknown.
+func (e *Executor) resolveStateful(ctx context.Context, typeName string, field *ast.Field, binding *mock.GraphQLStatefulBinding, args, parent map[string]interface{}, parentTable string) (interface{}, *GraphQLError) {
+	if e.statefulExecutor == nil {
+		return nil, &GraphQLError{
+			Message:    "stateful executor is not configured",
+			Extensions: map[string]interface{}{"code": "INTERNAL_SERVER_ERROR"},
+		}
+	}
+
+	req, ok := e.buildStatefulRequest(ctx, binding, args, parent, parentTable)
+	if !ok {
+		// A nested relationship with no key on the parent resolves to null.
+		return nil, nil
+	}
+
+	result := e.statefulExecutor.ExecuteStateful(ctx, req)
+	if result == nil {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	var fieldType *ast.Type
+	if e.schema != nil {
+		if def := e.schema.GetField(typeName, field.Name); def != nil {
+			fieldType = def.Type
+		}
+	}
+
+	if result.Items != nil {
+		items := make([]interface{}, len(result.Items))
+		for i, item := range result.Items {
+			items[i] = item
+		}
+		if fieldType == nil || fieldType.Elem != nil {
+			return items, nil
+		}
+		// Non-list return types (connection objects) get the REST list envelope.
+		value := map[string]interface{}{"data": items}
+		if result.Meta != nil {
+			value["meta"] = map[string]interface{}{
+				"total":  result.Meta.Total,
+				"count":  result.Meta.Count,
+				"offset": result.Meta.Offset,
+				"limit":  result.Meta.Limit,
+			}
+		}
+		return value, nil
+	}
+
+	if fieldType != nil && fieldType.Elem == nil && fieldType.NamedType == "Boolean" {
+		return true, nil
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+	return result.Item, nil
+}
+
+// buildStatefulRequest maps field arguments (and the parent object, for
+// nested fields) to a table request. It returns false when a nested field's
+// key is missing from the parent.
+func (e *Executor) buildStatefulRequest(ctx context.Context, binding *mock.GraphQLStatefulBinding, args, parent map[string]interface{}, parentTable string) (*StatefulRequest, bool) {
+	req := &StatefulRequest{
+		Resource:      binding.Table,
+		Action:        binding.Action,
+		OperationName: binding.Operation,
+	}
+	if e.config != nil {
+		req.WorkspaceID = e.config.WorkspaceID
+	}
+
+	idArg := binding.IDArg
+	if idArg == "" {
+		idArg = defaultIDArg
+	}
+
+	switch binding.Action {
+	case StatefulActionGet, StatefulActionUpdate, StatefulActionPatch, StatefulActionDelete:
+		var id interface{}
+		if binding.ParentField != "" && parent != nil {
+			id = parent[binding.ParentField]
+			if id == nil {
+				return nil, false
+			}
+		} else {
+			id = args[idArg]
+		}
+		if id != nil {
+			req.ResourceID = fmt.Sprintf("%v", id)
+		}
+		if binding.Action == StatefulActionUpdate || binding.Action == StatefulActionPatch {
+			req.Data = bindingPayload(binding, args, idArg)
+		}
+
+	case StatefulActionCreate:
+		req.Data = bindingPayload(binding, args, "")
+
+	case StatefulActionCustom:
+		req.Data = bindingPayload(binding, args, "")
+		if parent != nil {
+			req.Data["parent"] = parent
+		}
+
+	case StatefulActionList:
+		filter := &StatefulFilter{Limit: 100, Sort: "createdAt", Order: "desc", Filters: make(map[string]string)}
+		if v, ok := argInt(args["limit"]); ok {
+			filter.Limit = v
+		}
+		if v, ok := argInt(args["offset"]); ok {
+			filter.Offset = v
+		}
+		if v, ok := args["sort"].(string); ok && v != "" {
+			filter.Sort = v
+		}
+		if v, ok := args["order"].(string); ok && v != "" {
+			filter.Order = v
+		}
+		if len(binding.Filter) > 0 {
+			for tableField, argName := range binding.Filter {
+				if v, ok := args[argName]; ok && v != nil {
+					filter.Filters[tableField] = fmt.Sprintf("%v", v)
+				}
+			}
+		} else {
+			for name, v := range args {
+				if !listArgs[name] && v != nil {
+					filter.Filters[name] = fmt.Sprintf("%v", v)
+				}
+			}
+		}
+		if binding.ForeignKey != "" && parent != nil {
+			id := parent[e.statefulIDField(ctx, req.WorkspaceID, parentTable)]
+			if id == nil {
+				return nil, false
+			}
+			filter.Filters[binding.ForeignKey] = fmt.Sprintf("%v", id)
+		}
+		req.Filter = filter
+	}
+
+	return req, true
+}
+
+// statefulIDField returns the ID field of table, or "id" when the table is
+// unknown or the executor cannot report it.
+func (e *Executor) statefulIDField(ctx context.Context, workspaceID, table string) string {
+	if provider, ok := e.statefulExecutor.(StatefulIDFieldProvider); ok && table != "" {
+		if idField := provider.StatefulIDField(ctx, workspaceID, table); idField != "" {
+			return idField
+		}
+	}
+	return defaultIDArg
+}
+
+// statefulItemTable returns the table whose items a binding resolves to, or
+// "" when its result is not a table item (custom operations).
+func statefulItemTable(binding *mock.GraphQLStatefulBinding) string {
+	if binding == nil || binding.Action == StatefulActionCustom {
+		return ""
+	}
+	return binding.Table
+}
+
+// bindingPayload returns the input argument when it is an object, or else
+// every argument except skip.
+func bindingPayload(binding *mock.GraphQLStatefulBinding, args map[string]interface{}, skip string) map[string]interface{} {
+	inputArg := binding.InputArg
+	if inputArg == "" {
+		inputArg = defaultInputArg
+	}
+	data := make(map[string]interface{})
+	if input, ok := args[inputArg].(map[string]interface{}); ok {
+		for k, v := range input {
+			data[k] = v
+		}
+		return data
+	}
+	for k, v := range args {
+		if k != skip {
+			data[k] = v
+		}
+	}
+	return data
+}
+
+// argInt converts a GraphQL argument (int64 literal or JSON number variable) to int.
+func argInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case float64:
+		return int(n), true
+	case string:
+		i, err := strconv.Atoi(n)
+		return i, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,477 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/getmockd/mockd/pkg/mock"
+)
+
+const statefulTestSchema = `
+type Query {
+	user(id: ID!): User
+	users(role: String, limit: Int, offset: Int): [User!]!
+	usersPage(limit: Int): UserPage!
+	order(orderId: ID!): Order
+}
+
+type Mutation {
+	createUser(input: CreateUserInput!): User
+	updateUser(id: ID!, input: CreateUserInput!): User
+	deleteUser(id: ID!): Boolean!
+}
+
+type User {
+	id: ID!
+	name: String!
+	role: String
+	orders: [Order!]!
+}
+
+type Order {
+	id: ID!
+	userId: ID!
+	total: Float!
+	customer: User
+}
+
+type UserPage {
+	data: [User!]!
+	meta: PageMeta!
+}
+
+type PageMeta {
+	total: Int!
+	count: Int!
+}
+
+input CreateUserInput {
+	name: String!
+	role: String
+}
+`
+
+// fakeStatefulExecutor is an in-memory table store recording every request.
+type fakeStatefulExecutor struct {
+	tables   map[string]map[string]map[string]interface{}
+	requests []*StatefulRequest
+	nextID   int
+}
+
+func newFakeStatefulExecutor() *fakeStatefulExecutor {
+	return &fakeStatefulExecutor{
+		tables: map[string]map[string]map[string]interface{}{
+			"users": {
+				"u1": {"id": "u1", "name": "Alice", "role": "admin"},
+				"u2": {"id": "u2", "name": "Bob", "role": "user"},
+			},
+			"orders": {
+				"o1": {"id": "o1", "userId": "u1", "total": 10.5},
+				"o2": {"id": "o2", "userId": "u1", "total": 20.0},
+				"o3": {"id": "o3", "userId": "u2", "total": 5.0},
+			},
+		},
+	}
+}
+
+func (f *fakeStatefulExecutor) ExecuteStateful(_ context.Context, req *StatefulRequest) *StatefulResult {
+	f.requests = append(f.requests, req)
+	table := f.tables[req.Resource]
+	notFound := &StatefulResult{Error: &GraphQLError{
+		Message:    fmt.Sprintf("%s %q not found", req.Resource, req.ResourceID),
+		Extensions: map[string]interface{}{"code": "NOT_FOUND"},
+	}}
+
+	switch req.Action {
+	case StatefulActionGet:
+		item, ok := table[req.ResourceID]
+		if !ok {
+			return notFound
+		}
+		return &StatefulResult{Item: item}
+	case StatefulActionList:
+		ids := make([]string, 0, len(table))
+		for id := range table {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		var items []map[string]interface{}
+		for _, id := range ids {
+			item := table[id]
+			match := true
+			for k, v := range req.Filter.Filters {
+				if fmt.Sprintf("%v", item[k]) != v {
+					match = false
+				}
+			}
+			if match {
+				items = append(items, item)
+			}
+		}
+		total := len(items)
+		if req.Filter.Offset < len(items) {
+			items = items[req.Filter.Offset:]
+		} else {
+			items = nil
+		}
+		if req.Filter.Limit < len(items) {
+			items = items[:req.Filter.Limit]
+		}
+		if items == nil {
+			items = []map[string]interface{}{}
+		}
+		return &StatefulResult{Items: items, Meta: &StatefulListMeta{Total: total, Count: len(items), Limit: req.Filter.Limit}}
+	case StatefulActionCreate:
+		f.nextID++
+		item := map[string]interface{}{"id": fmt.Sprintf("new-%d", f.nextID)}
+		for k, v := range req.Data {
+			item[k] = v
+		}
+		table[item["id"].(string)] = item
+		return &StatefulResult{Item: item}
+	case StatefulActionUpdate:
+		if _, ok := table[req.ResourceID]; !ok {
+			return notFound
+		}
+		item := map[string]interface{}{"id": req.ResourceID}
+		for k, v := range req.Data {
+			item[k] = v
+		}
+		table[req.ResourceID] = item
+		return &StatefulResult{Item: item}
+	case StatefulActionDelete:
+		item, ok := table[req.ResourceID]
+		if !ok {
+			return notFound
+		}
+		delete(table, req.ResourceID)
+		return &StatefulResult{Item: item}
+	case StatefulActionCustom:
+		return &StatefulResult{Item: map[string]interface{}{"operation": req.OperationName, "input": req.Data}}
+	}
+	return &StatefulResult{}
+}
+
+func newStatefulTestExecutor(t *testing.T, resolvers map[string]ResolverConfig) (*Executor, *fakeStatefulExecutor) {
+	t.Helper()
+	schema, err := ParseSchema(statefulTestSchema)
+	if err != nil {
+		t.Fatalf("ParseSchema() error = %v", err)
+	}
+	executor := NewExecutor(schema, &GraphQLConfig{WorkspaceID: "ws-1", Resolvers: resolvers})
+	fake := newFakeStatefulExecutor()
+	executor.SetStatefulExecutor(fake)
+	return executor, fake
+}
+
+func executeStateful(t *testing.T, executor *Executor, query string, variables map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	resp := executor.Execute(context.Background(), &GraphQLRequest{Query: query, Variables: variables})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("Execute() returned errors: %v", resp.Errors[0].Message)
+	}
+	data, ok := resp.Data.(map[string]interface{})
+	if !ok {
+		t.Fatalf("Execute() data is not a map, got %T", resp.Data)
+	}
+	return data
+}
+
+func TestStateful_GetByID(t *testing.T) {
+	executor, fake := newStatefulTestExecutor(t, map[string]ResolverConfig{
+		"Query.user": {StatefulBinding: &mock.GraphQLStatefulBinding{Table: "users", Action: "get"}},
+	})
+
+	data := executeStateful(t, executor, `query { user(id: "u1") { id name } }`, nil)
+
+	user, ok := data["user"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("data['user'] is not a map, got %T", data["user"])
+	}
+	if user["name"] != "Alice" {
+		t.Errorf("user.name = %v, want Alice", user["name"])
+	}
+	if _, ok := user["role"]; ok {
+		t.Error("unselected field role should be pruned")
+	}
+	if len(fake.requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(fake.requests))
+	}
+	req := fake.requests[0]
+	if req.WorkspaceID != "ws-1" || req.Resource != "users" || req.ResourceID != "u1" {
+		t.Errorf("request = %+v, want workspace ws-1, users/u1", req)
+	}
+}
+
+func TestStateful_GetNotFound(t *testing.T) {
+	executor, _ := newStatefulTestExecutor(t, map[string]ResolverConfig{
+		"Query.user": {StatefulBinding: &mock.GraphQLStatefulBinding{Table: "users", Action: "get"}},
+	})
+
+	resp := executor.Execute(context.Background(), &GraphQLRequest{Query: `query { user(id: "missing") { id } }`})
+
+	if len(resp.Errors) != 1 {
+		t.Fatalf("got %d errors, want 1", len(resp.Errors))
+	}
+	if resp.Errors[0].Extensions["code"] != "NOT_FOUND" {
+		t.Errorf("error code = %v, want NOT_FOUND", resp.Errors[0].Extensions["code"])
+	}
+	if len(resp.Errors[0].Path) != 1 || resp.Errors[0].Path[0] != "user" {
+		t.Errorf("error path = %v, want [user]", resp.Errors[0].Path)
+	}
+}
+
+func TestStateful_ListWithFiltersAndPagination(t *testing.T) {
+	executor, fake := newStatefulTestExecutor(t, map[string]ResolverConfig{
+		"Query.users": {StatefulBinding: &mock.GraphQLStatefulBinding{Table: "users", Action: "list"}},
+	})
+
+	data := executeStateful(t, executor, `query($role: String) { users(role: $role, limit: 5) { id } }`,
+		map[string]interface{}{"role": "user"})
+
+	users, ok := data["users"].([]interface{})
+	if !ok {
+		t.Fatalf("data['users'] is not a list, got %T", data["users"])
+	}
+	if len(users) != 1 || users[0].(map[string]interface{})["id"] != "u2" {
+		t.Errorf("users = %v, want [u2]", users)
+	}
+	filter := fake.requests[0].Filter
+	if filter.Limit != 5 || filter.Offset != 0 {
+		t.Errorf("pagination = limit %d offset %d, want 5/0", filter.Limit, filter.Offset)
+	}
+	if filter.Filters["role"] != "user" {
+		t.Errorf("filters = %v, want role=user", filter.Filters)
+	}
+	if _, ok := filter.Filters["limit"]; ok {
+		t.Error("limit should not be a filter")
+	}
+}
+
+func TestStateful_ListFilterMapping(t *testing.T) {
+	executor, fake := newStatefulTestExecutor(t, map[string]ResolverConfig{
+		"Query.users": {StatefulBinding: &mock.GraphQLStatefulBinding{
+			Table:  "users",
+			Action: "list",
+			Filter: map[string]string{"role": "role"},
+		}},
+	})
+
+	executeStateful(t, executor, `query { users(role: "admin", offset: 1) { id } }`, nil)
+
+	filter := fake.requests[0].Filter
+	if len(filter.Filters) != 1 || filter.Filters["role"] != "admin" {
+		t.Errorf("filters = %v, want only role=admin", filter.Filters)
+	}
+	if filter.Offset != 1 {
+		t.Errorf("offset = %d, want 1", filter.Offset)
+	}
+}
+
+func TestStateful_ListEnvelopeForObjectType(t *testing.T) {
+	executor, _ := newStatefulTestExecutor(t, map[string]ResolverConfig{
+		"Query.usersPage": {StatefulBinding: &mock.GraphQLStatefulBinding{Table: "users", Action: "list"}},
+	})
+
+	data := executeStateful(t, executor, `query { usersPage(limit: 1) { data { id } meta { total count } } }`, nil)
+
+	page, ok := data["usersPage"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("data['usersPage'] is not a map, got %T", data["usersPage"])
+	}
+	meta := page["meta"].(map[string]interface{})
+	if meta["total"] != 2 || meta["count"] != 1 {
+		t.Errorf("meta = %v, want total 2 count 1", meta)
+	}
+	if items := page["data"].([]interface{}); len(items) != 1 {
+		t.Errorf("got %d items, want 1", len(items))
+	}
+}
+
+func TestStateful_Mutations(t *testing.T) {
+	executor, fake := newStatefulTestExecutor(t, map[string]ResolverConfig{
+		"Query.user":          {StatefulBinding: &mock.GraphQLStatefulBinding{Table: "users", Action: "get"}},
+		"Mutation.createUser": {StatefulBinding: &mock.GraphQLStatefulBinding{Table: "users", Action: "create"}},
+		"Mutation.updateUser": {StatefulBinding: &mock.GraphQLStatefulBinding{Table: "users", Action: "update"}},
+		"Mutation.deleteUser": {StatefulBinding: &mock.GraphQLStatefulBinding{Table: "users", Action: "delete"}},
+	})
+
+	data := executeStateful(t, executor, `mutation { createUser(input: {name: "Carol", role: "user"}) { id name } }`, nil)
+	created := data["createUser"].(map[string]interface{})
+	id, _ := created["id"].(string)
+	if id == "" || created["name"] != "Carol" {
+		t.Fatalf("createUser = %v, want id and name Carol", created)
+	}
+	if _, ok := fake.requests[0].Data["input"]; ok {
+		t.Error("create payload should be the input object, not the raw arguments")
+	}
+
+	data = executeStateful(t, executor, `mutation($id: ID!) { updateUser(id: $id, input: {name: "Caroline"}) { name } }`,
+		map[string]interface{}{"id": id})
+	if data["updateUser"].(map[string]interface{})["name"] != "Caroline" {
+		t.Errorf("updateUser = %v, want name Caroline", data["updateUser"])
+	}
+	if fake.requests[1].ResourceID != id {
+		t.Errorf("update ResourceID = %q, want %q", fake.requests[1].ResourceID, id)
+	}
+
+	data = executeStateful(t, executor, `query($id: ID!) { user(id: $id) { name } }`, map[string]interface{}{"id": id})
+	if data["user"].(map[string]interface{})["name"] != "Caroline" {
+		t.Errorf("REST-visible state not updated: %v", data["user"])
+	}
+
+	data = executeStateful(t, executor, `mutation($id: ID!) { deleteUser(id: $id) }`, map[string]interface{}{"id": id})
+	if data["deleteUser"] != true {
+		t.Errorf("deleteUser = %v, want true", data["deleteUser"])
+	}
+	if _, ok := fake.tables["users"][id]; ok {
+		t.Error("user should be deleted")
+	}
+}
+
+func TestStateful_NestedRelationships(t *testing.T) {
+	executor, fake := newStatefulTestExecutor(t, map[string]ResolverConfig{
+		"Query.user":     {StatefulBinding: &mock.GraphQLStatefulBinding{Table: "users", Action: "get"}},
+		"Query.order":    {StatefulBinding: &mock.GraphQLStatefulBinding{Table: "orders", Action: "get", IDArg: "orderId"}},
+		"User.orders":    {StatefulBinding: &mock.GraphQLStatefulBinding{Table: "orders", Action: "list", ForeignKey: "userId"}},
+		"Order.customer": {StatefulBinding: &mock.GraphQLStatefulBinding{Table: "users", Action: "get", ParentField: "userId"}},
+	})
+
+	data := executeStateful(t, executor, `query { user(id: "u1") { name orders { id total customer { name } } } }`, nil)
+
+	user := data["user"].(map[string]interface{})
+	orders, ok := user["orders"].([]interface{})
+	if !ok {
+		t.Fatalf("user.orders is not a list, got %T", user["orders"])
+	}
+	if len(orders) != 2 {
+		t.Fatalf("got %d orders, want 2", len(orders))
+	}
+	for _, o := range orders {
+		order := o.(map[string]interface{})
+		if _, ok := order["userId"]; ok {
+			t.Error("unselected field userId should be pruned")
+		}
+		customer := order["customer"].(map[string]interface{})
+		if customer["name"] != "Alice" {
+			t.Errorf("order.customer.name = %v, want Alice", customer["name"])
+		}
+	}
+	if got := fake.requests[1].Filter.Filters["userId"]; got != "u1" {
+		t.Errorf("orders filter userId = %q, want u1", got)
+	}
+
+	data = executeStateful(t, executor, `query { order(orderId: "o3") { customer { name } } }`, nil)
+	customer := data["order"].(map[string]interface{})["customer"].(map[string]interface{})
+	if customer["name"] != "Bob" {
+		t.Errorf("order.customer.name = %v, want Bob", customer["name"])
+	}
+}
+
+// idFieldStatefulExecutor reports configured ID fields for the fake tables.
+type idFieldStatefulExecutor struct {
+	*fakeStatefulExecutor
+	idFields map[string]string
+}
+
+func (f *idFieldStatefulExecutor) StatefulIDField(_ context.Context, _, table string) string {
+	return f.idFields[table]
+}
+
+func TestStateful_NestedListUsesParentIDField(t *testing.T) {
+	executor, fake := newStatefulTestExecutor(t, map[string]ResolverConfig{
+		"Query.user":  {StatefulBinding: &mock.GraphQLStatefulBinding{Table: "users", Action: "get"}},
+		"User.orders": {StatefulBinding: &mock.GraphQLStatefulBinding{Table: "orders", Action: "list", ForeignKey: "userId"}},
+	})
+	fake.tables["users"] = map[string]map[string]interface{}{
+		"alice": {"username": "alice", "name": "Alice"},
+	}
+	fake.tables["orders"]["o1"]["userId"] = "alice"
+	executor.SetStatefulExecutor(&idFieldStatefulExecutor{fakeStatefulExecutor: fake, idFields: map[string]string{"users": "username"}})
+
+	data := executeStateful(t, executor, `query { user(id: "alice") { name orders { id } } }`, nil)
+
+	orders := data["user"].(map[string]interface{})["orders"].([]interface{})
+	if len(orders) != 1 || orders[0].(map[string]interface{})["id"] != "o1" {
+		t.Errorf("orders = %v, want [o1]", orders)
+	}
+	if got := fake.requests[1].Filter.Filters["userId"]; got != "alice" {
+		t.Errorf("orders filter userId = %q, want alice", got)
+	}
+}
+
+func TestStateful_NestedErrorPath(t *testing.T) {
+	executor, fake := newStatefulTestExecutor(t, map[string]ResolverConfig{
+		"Query.order":    {StatefulBinding: &mock.GraphQLStatefulBinding{Table: "orders", Action: "get", IDArg: "orderId"}},
+		"Order.customer": {StatefulBinding: &mock.GraphQLStatefulBinding{Table: "users", Action: "get", ParentField: "userId"}},
+	})
+	delete(fake.tables["users"], "u2")
+
+	resp := executor.Execute(context.Background(), &GraphQLRequest{Query: `query { order(orderId: "o3") { id customer { name } } }`})
+
+	if len(resp.Errors) != 1 {
+		t.Fatalf("got %d errors, want 1", len(resp.Errors))
+	}
+	path := resp.Errors[0].Path
+	if len(path) != 2 || path[0] != "order" || path[1] != "customer" {
+		t.Errorf("error path = %v, want [order customer]", path)
+	}
+	order := resp.Data.(map[string]interface{})["order"].(map[string]interface{})
+	if order["id"] != "o3" || order["customer"] != nil {
+		t.Errorf("order = %v, want id o3 and null customer", order)
+	}
+}
+
+func TestStateful_StaticParentWithBoundChild(t *testing.T) {
+	executor, _ := newStatefulTestExecutor(t, map[string]ResolverConfig{
+		"Query.user":  {Response: map[string]interface{}{"id": "u2", "name": "Static Bob"}},
+		"User.orders": {StatefulBinding: &mock.GraphQLStatefulBinding{Table: "orders", Action: "list", ForeignKey: "userId"}},
+	})
+
+	data := executeStateful(t, executor, `query { user(id: "u2") { name orders { id } } }`, nil)
+
+	orders := data["user"].(map[string]interface{})["orders"].([]interface{})
+	if len(orders) != 1 || orders[0].(map[string]interface{})["id"] != "o3" {
+		t.Errorf("orders = %v, want [o3]", orders)
+	}
+}
+
+func TestStateful_CustomOperation(t *testing.T) {
+	executor, fake := newStatefulTestExecutor(t, map[string]ResolverConfig{
+		"Mutation.createUser": {StatefulBinding: &mock.GraphQLStatefulBinding{Action: "custom", Operation: "RegisterUser"}},
+	})
+
+	resp := executor.Execute(context.Background(), &GraphQLRequest{Query: `mutation { createUser(input: {name: "Dan"}) { id } }`})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("Execute() returned errors: %v", resp.Errors[0].Message)
+	}
+
+	req := fake.requests[0]
+	if req.Action != StatefulActionCustom || req.OperationName != "RegisterUser" {
+		t.Errorf("request = %+v, want custom RegisterUser", req)
+	}
+	if req.Data["name"] != "Dan" {
+		t.Errorf("custom payload = %v, want name Dan", req.Data)
+	}
+}
+
+func TestStateful_NoExecutor(t *testing.T) {
+	schema, err := ParseSchema(statefulTestSchema)
+	if err != nil {
+		t.Fatalf("ParseSchema() error = %v", err)
+	}
+	executor := NewExecutor(schema, &GraphQLConfig{Resolvers: map[string]ResolverConfig{
+		"Query.user": {StatefulBinding: &mock.GraphQLStatefulBinding{Table: "users", Action: "get"}},
+	}})
+
+	resp := executor.Execute(context.Background(), &GraphQLRequest{Query: `query { user(id: "u1") { id } }`})
+
+	if len(resp.Errors) != 1 {
+		t.Fatalf("got %d errors, want 1", len(resp.Errors))
+	}
+	if resp.Errors[0].Extensions["code"] != "INTERNAL_SERVER_ERROR" {
+		t.Errorf("error code = %v, want INTERNAL_SERVER_ERROR", resp.Errors[0].Extensions["code"])
+	}
+}
@@ -1,5 +1,7 @@
 package graphql
 
+import "github.com/getmockd/mockd/pkg/mock"
+
 // GraphQLConfig represents a GraphQL endpoint configuration.
 type GraphQLConfig struct {
 	// ID is the unique identifier for this GraphQL endpoint.
@@ -10,6 +12,8 @@ type GraphQLConfig struct {
 	ParentID string `json:"parentId,omitempty" yaml:"parentId,omitempty"`
 	// MetaSortKey is used for manual ordering within a folder
 	MetaSortKey float64 `json:"metaSortKey,omitempty" yaml:"metaSortKey,omitempty"`
+	// WorkspaceID is the workspace whose stateful tables back bound resolvers.
+	WorkspaceID string `json:"workspaceId,omitempty" yaml:"workspaceId,omitempty"`
 	// Path is the URL path where this GraphQL endpoint is served.
 	Path string `json:"path" yaml:"path"`
 	// Schema is the inline GraphQL SDL schema definition.
@@ -40,6 +44,11 @@ type ResolverConfig struct {
 	Match *ResolverMatch `json:"match,omitempty" yaml:"match,omitempty"`
 	// Error configures an error response instead of data.
 	Error *GraphQLErrorConfig `json:"error,omitempty" yaml:"error,omitempty"`
+	// Variants holds additional match variants for the same field, evaluated
+	// in order after this one. Nested Variants on a variant are ignored.
+	Variants []ResolverConfig `json:"variants,omitempty" yaml:"variants,omitempty"`
+	// StatefulBinding resolves the field from a stateful table instead of Response.
+	StatefulBinding *mock.GraphQLStatefulBinding `json:"statefulBinding,omitempty" yaml:"statefulBinding,omitempty"`
 }
 
 // ResolverMatch specifies matching conditions for a resolver.
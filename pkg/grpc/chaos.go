@@ -0,0 +1,348 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/getmockd/mockd/pkg/chaos"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcpeer "google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// ChaosSource returns the chaos injector currently in effect, or nil when
+// chaos is off. It is called for every call, so runtime changes to the chaos
+// configuration apply to running servers immediately.
+type ChaosSource func() *chaos.Injector
+
+// trailerDropFlushDelay gives queued headers and messages time to reach the
+// client before the connection is closed for a grpc_drop_trailers fault.
+const trailerDropFlushDelay = 50 * time.Millisecond
+
+// SetChaosSource configures where the server gets its chaos injector from.
+func (s *Server) SetChaosSource(source ChaosSource) {
+	s.chaosMu.Lock()
+	defer s.chaosMu.Unlock()
+	s.chaosSource = source
+}
+
+// chaosFaults returns the faults to inject into a call, with the injector
+// that selected them. Health checks and reflection are never disrupted.
+func (s *Server) chaosFaults(fullMethod string) (*chaos.Injector, []chaos.FaultConfig) {
+	s.chaosMu.RLock()
+	source := s.chaosSource
+	s.chaosMu.RUnlock()
+	if source == nil {
+		return nil, nil
+	}
+	injector := source()
+	if injector == nil || !injector.IsEnabled() {
+		return nil, nil
+	}
+	if strings.HasPrefix(fullMethod, "/grpc.health.") || strings.HasPrefix(fullMethod, "/grpc.reflection.") {
+		return nil, nil
+	}
+	return injector, injector.ShouldInjectGRPC(fullMethod)
+}
+
+// chaosPlan holds the faults that act after the handler has started.
+type chaosPlan struct {
+	abortAfter   int // messages before the stream is aborted; -1 disables
+	abortErr     error
+	dropTrailers bool
+}
+
+// applyChaos injects the faults that act before the handler runs (latency,
+// errors, deadlines) and returns the rest as a plan. A non-nil error fails
+// the call without running the handler.
+func (s *Server) applyChaos(ctx context.Context, injector *chaos.Injector, faults []chaos.FaultConfig) (*chaosPlan, error) {
+	plan := &chaosPlan{abortAfter: -1}
+	for _, fault := range faults {
+		cfg := fault.Config
+		switch fault.Type { //nolint:exhaustive // HTTP-only fault types do not apply to gRPC
+		case chaos.FaultLatency:
+			if err := injector.InjectLatencyFromConfig(ctx, cfg); err != nil {
+				return nil, status.FromContextError(err).Err()
+			}
+
+		case chaos.FaultError:
+			code := injector.StatusCodeFromConfig(cfg)
+			return nil, status.Error(httpStatusToGRPCCode(code), http.StatusText(code))
+
+		case chaos.FaultGRPCStatus:
+			return nil, s.toGRPCError(chaosErrorConfig(cfg, "UNAVAILABLE"))
+
+		case chaos.FaultGRPCResourceExhausted:
+			errCfg := chaosErrorConfig(cfg, "RESOURCE_EXHAUSTED")
+			errCfg.Code = "RESOURCE_EXHAUSTED"
+			errCfg.Details = map[string]interface{}{
+				"retry_info": map[string]interface{}{"retry_delay": stringConfig(cfg, "retryDelay", "1s")},
+			}
+			return nil, s.toGRPCError(errCfg)
+
+		case chaos.FaultTimeout:
+			return nil, chaosDeadline(ctx, durationConfig(cfg, "duration", 30*time.Second))
+
+		case chaos.FaultGRPCDeadline:
+			return nil, chaosDeadline(ctx, durationConfig(cfg, "after", 0))
+
+		case chaos.FaultGRPCStreamAbort:
+			plan.abortAfter = intConfig(cfg, "afterMessages", 1)
+			plan.abortErr = s.toGRPCError(chaosErrorConfig(cfg, "ABORTED"))
+
+		case chaos.FaultGRPCDropTrailers:
+			plan.dropTrailers = true
+		}
+	}
+	return plan, nil
+}
+
+// chaosDeadline waits for the client deadline or the given time, whichever
+// comes first, then returns DEADLINE_EXCEEDED.
+func chaosDeadline(ctx context.Context, wait time.Duration) error {
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+		}
+	}
+	return status.Error(codes.DeadlineExceeded, "chaos: deadline exceeded")
+}
+
+// chaosUnaryInterceptor injects chaos into unary calls.
+func (s *Server) chaosUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	injector, faults := s.chaosFaults(info.FullMethod)
+	if len(faults) == 0 {
+		return handler(ctx, req)
+	}
+	plan, err := s.applyChaos(ctx, injector, faults)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := handler(ctx, req)
+	if plan.dropTrailers {
+		// A unary response travels with its trailer, so only the headers get out.
+		_ = grpc.SendHeader(ctx, nil)
+		s.dropConnection(ctx)
+	}
+	return resp, err
+}
+
+// chaosStreamInterceptor injects chaos into streaming calls.
+func (s *Server) chaosStreamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	injector, faults := s.chaosFaults(info.FullMethod)
+	if len(faults) == 0 {
+		return handler(srv, stream)
+	}
+	plan, err := s.applyChaos(stream.Context(), injector, faults)
+	if err != nil {
+		return err
+	}
+
+	if plan.abortAfter >= 0 {
+		cs := &chaosStream{ServerStream: stream, abortAfter: int64(plan.abortAfter), abortErr: plan.abortErr}
+		err = handler(srv, cs)
+		if cs.aborted.Load() {
+			err = cs.abortErr
+		}
+	} else {
+		err = handler(srv, stream)
+	}
+
+	if plan.dropTrailers {
+		_ = stream.SendHeader(nil)
+		s.dropConnection(stream.Context())
+	}
+	return err
+}
+
+// chaosStream aborts a stream once more than abortAfter messages have been
+// sent or received.
+type chaosStream struct {
+	grpc.ServerStream
+	abortAfter int64
+	abortErr   error
+	messages   atomic.Int64
+	aborted    atomic.Bool
+}
+
+func (cs *chaosStream) tick() bool {
+	if cs.messages.Add(1) > cs.abortAfter {
+		cs.aborted.Store(true)
+	}
+	return cs.aborted.Load()
+}
+
+func (cs *chaosStream) SendMsg(m interface{}) error {
+	if cs.tick() {
+		return cs.abortErr
+	}
+	return cs.ServerStream.SendMsg(m)
+}
+
+func (cs *chaosStream) RecvMsg(m interface{}) error {
+	if cs.tick() {
+		return cs.abortErr
+	}
+	return cs.ServerStream.RecvMsg(m)
+}
+
+// dropConnection closes the client connection of a call so that it ends
+// without a status trailer. Other calls multiplexed on the connection fail too.
+func (s *Server) dropConnection(ctx context.Context) {
+	p, ok := grpcpeer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return
+	}
+	s.mu.RLock()
+	tl, _ := s.listener.(*trackingListener)
+	s.mu.RUnlock()
+	if tl == nil {
+		return
+	}
+	time.Sleep(trailerDropFlushDelay)
+	tl.closeConn(p.Addr.String())
+}
+
+// trackingListener remembers accepted connections by remote address so that
+// a call's connection can be closed from its handler.
+type trackingListener struct {
+	net.Listener
+	mu    sync.Mutex
+	conns map[string]net.Conn
+}
+
+func newTrackingListener(l net.Listener) *trackingListener {
+	return &trackingListener{Listener: l, conns: make(map[string]net.Conn)}
+}
+
+func (l *trackingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	tc := &trackedConn{Conn: conn, listener: l}
+	l.mu.Lock()
+	l.conns[conn.RemoteAddr().String()] = tc
+	l.mu.Unlock()
+	return tc, nil
+}
+
+func (l *trackingListener) closeConn(addr string) {
+	l.mu.Lock()
+	conn := l.conns[addr]
+	l.mu.Unlock()
+	if conn != nil {
+		_ = conn.Close()
+	}
+}
+
+// trackedConn removes itself from its listener when closed.
+type trackedConn struct {
+	net.Conn
+	listener *trackingListener
+	once     sync.Once
+}
+
+func (c *trackedConn) Close() error {
+	c.once.Do(func() {
+		c.listener.mu.Lock()
+		delete(c.listener.conns, c.RemoteAddr().String())
+		c.listener.mu.Unlock()
+	})
+	return c.Conn.Close()
+}
+
+// chaosErrorConfig builds the status of an error fault from its config:
+// "code" (name or number), "message" and "details" (as in method errors).
+func chaosErrorConfig(cfg map[string]interface{}, defaultCode string) *GRPCErrorConfig {
+	code := defaultCode
+	switch v := cfg["code"].(type) {
+	case string:
+		if ValidateStatusCode(strings.ToUpper(v)) {
+			code = strings.ToUpper(v)
+		}
+	case float64:
+		if name, ok := grpcStatusName[int(v)]; ok {
+			code = name
+		}
+	case int:
+		if name, ok := grpcStatusName[v]; ok {
+			code = name
+		}
+	}
+	errCfg := &GRPCErrorConfig{
+		Code:    code,
+		Message: stringConfig(cfg, "message", "chaos: "+strings.ToLower(strings.ReplaceAll(code, "_", " "))),
+	}
+	if details, ok := cfg["details"].(map[string]interface{}); ok {
+		errCfg.Details = details
+	}
+	return errCfg
+}
+
+// httpStatusToGRPCCode maps an HTTP status code to the gRPC code a gateway
+// would translate it to.
+func httpStatusToGRPCCode(code int) codes.Code {
+	switch code {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case 499:
+		return codes.Canceled
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	default:
+		return codes.Internal
+	}
+}
+
+func stringConfig(cfg map[string]interface{}, key, def string) string {
+	if v, ok := cfg[key].(string); ok && v != "" {
+		return v
+	}
+	return def
+}
+
+func durationConfig(cfg map[string]interface{}, key string, def time.Duration) time.Duration {
+	if d, err := time.ParseDuration(stringConfig(cfg, key, "")); err == nil {
+		return d
+	}
+	return def
+}
+
+func intConfig(cfg map[string]interface{}, key string, def int) int {
+	switch v := cfg[key].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	case string:
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return def
+}
@@ -0,0 +1,186 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/getmockd/mockd/pkg/chaos"
+	"github.com/getmockd/mockd/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// startChaosTestServer starts the stateful test server with two users and
+// the given chaos config.
+func startChaosTestServer(t *testing.T, cfg *chaos.ChaosConfig) (*Server, *grpc.ClientConn) {
+	t.Helper()
+	binding := func(action string) MethodConfig {
+		return MethodConfig{StatefulBinding: &mock.StatefulBinding{Table: "users", Action: action}}
+	}
+	srv, fake, conn := startStatefulTestServer(t, map[string]MethodConfig{
+		"GetUser":     binding("get"),
+		"ListUsers":   binding("list"),
+		"StreamUsers": binding("list"),
+	})
+	fake.rows["u1"] = map[string]interface{}{"id": "u1", "name": "Alice"}
+	fake.rows["u2"] = map[string]interface{}{"id": "u2", "name": "Bob"}
+
+	cfg.Enabled = true
+	injector, err := chaos.NewInjector(cfg)
+	require.NoError(t, err)
+	srv.SetChaosSource(func() *chaos.Injector { return injector })
+	return srv, conn
+}
+
+// streamUsers reads StreamUsers until the stream ends, returning the IDs received.
+func streamUsers(t *testing.T, srv *Server, conn *grpc.ClientConn) ([]string, error) {
+	t.Helper()
+	method := srv.GetMethodDescriptor("crm.UserService", "StreamUsers")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, "/crm.UserService/StreamUsers")
+	require.NoError(t, err)
+	require.NoError(t, stream.SendMsg(dynamicpb.NewMessage(method.Input())))
+	require.NoError(t, stream.CloseSend())
+
+	var ids []string
+	for {
+		msg := dynamicpb.NewMessage(method.Output())
+		if err := stream.RecvMsg(msg); err != nil {
+			if err == io.EOF {
+				return ids, nil
+			}
+			return ids, err
+		}
+		ids = append(ids, getField(msg, "id").(string))
+	}
+}
+
+func TestChaos_GRPCStatusTargetsMethod(t *testing.T) {
+	srv, conn := startChaosTestServer(t, &chaos.ChaosConfig{
+		Rules: []chaos.ChaosRule{{
+			Protocol:    chaos.ProtocolGRPC,
+			PathPattern: "UserService/GetUser$",
+			Faults: []chaos.FaultConfig{{
+				Type:        chaos.FaultGRPCStatus,
+				Probability: 1,
+				Config: map[string]interface{}{
+					"code":    "UNAVAILABLE",
+					"message": "backend down",
+					"details": map[string]interface{}{"error_info": map[string]interface{}{"reason": "CHAOS"}},
+				},
+			}},
+			Probability: 1,
+		}},
+	})
+
+	_, err := invokeStateful(t, srv, conn, "GetUser", map[string]interface{}{"user_id": "u1"})
+	st := status.Convert(err)
+	assert.Equal(t, codes.Unavailable, st.Code())
+	assert.Equal(t, "backend down", st.Message())
+	require.Len(t, st.Details(), 1)
+	assert.Equal(t, "CHAOS", st.Details()[0].(*errdetails.ErrorInfo).GetReason())
+
+	_, err = invokeStateful(t, srv, conn, "ListUsers", map[string]interface{}{})
+	assert.NoError(t, err, "other methods are not targeted")
+}
+
+func TestChaos_GRPCResourceExhaustedWithRetryInfo(t *testing.T) {
+	srv, conn := startChaosTestServer(t, &chaos.ChaosConfig{
+		Rules: []chaos.ChaosRule{{
+			Protocol:    chaos.ProtocolGRPC,
+			PathPattern: ".*",
+			Faults: []chaos.FaultConfig{{
+				Type:        chaos.FaultGRPCResourceExhausted,
+				Probability: 1,
+				Config:      map[string]interface{}{"retryDelay": "3s"},
+			}},
+			Probability: 1,
+		}},
+	})
+
+	_, err := invokeStateful(t, srv, conn, "GetUser", map[string]interface{}{"user_id": "u1"})
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	require.Len(t, st.Details(), 1)
+	assert.Equal(t, 3*time.Second, st.Details()[0].(*errdetails.RetryInfo).GetRetryDelay().AsDuration())
+}
+
+func TestChaos_GRPCStreamAbortAfterMessages(t *testing.T) {
+	srv, conn := startChaosTestServer(t, &chaos.ChaosConfig{
+		Rules: []chaos.ChaosRule{{
+			Protocol:    chaos.ProtocolGRPC,
+			PathPattern: "StreamUsers",
+			Faults: []chaos.FaultConfig{{
+				Type:        chaos.FaultGRPCStreamAbort,
+				Probability: 1,
+				// One received request plus one sent item.
+				Config: map[string]interface{}{"afterMessages": 2, "code": "UNAVAILABLE"},
+			}},
+			Probability: 1,
+		}},
+	})
+
+	ids, err := streamUsers(t, srv, conn)
+	assert.Equal(t, []string{"u1"}, ids)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestChaos_GRPCDeadline(t *testing.T) {
+	srv, conn := startChaosTestServer(t, &chaos.ChaosConfig{
+		Rules: []chaos.ChaosRule{{
+			Protocol:    chaos.ProtocolGRPC,
+			PathPattern: "GetUser",
+			Faults:      []chaos.FaultConfig{{Type: chaos.FaultGRPCDeadline, Probability: 1}},
+			Probability: 1,
+		}},
+	})
+
+	_, err := invokeStateful(t, srv, conn, "GetUser", map[string]interface{}{"user_id": "u1"})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+}
+
+func TestChaos_GRPCDropTrailers(t *testing.T) {
+	srv, conn := startChaosTestServer(t, &chaos.ChaosConfig{
+		Rules: []chaos.ChaosRule{{
+			Protocol:    chaos.ProtocolGRPC,
+			PathPattern: "StreamUsers",
+			Faults:      []chaos.FaultConfig{{Type: chaos.FaultGRPCDropTrailers, Probability: 1}},
+			Probability: 1,
+		}},
+	})
+
+	ids, err := streamUsers(t, srv, conn)
+	assert.Equal(t, []string{"u1", "u2"}, ids, "messages are delivered")
+	assert.Error(t, err, "the stream must not end cleanly")
+}
+
+func TestChaos_GRPCGlobalRulesAndHTTPRules(t *testing.T) {
+	srv, conn := startChaosTestServer(t, &chaos.ChaosConfig{
+		Rules: []chaos.ChaosRule{{
+			// HTTP rules never apply to gRPC calls.
+			PathPattern: ".*",
+			Faults:      []chaos.FaultConfig{{Type: chaos.FaultError, Probability: 1, Config: map[string]interface{}{"defaultCode": 400}}},
+			Probability: 1,
+		}},
+		GlobalRules: &chaos.GlobalChaosRules{
+			ErrorRate: &chaos.ErrorRateFault{Probability: 1, StatusCodes: []int{503}},
+		},
+	})
+
+	_, err := invokeStateful(t, srv, conn, "GetUser", map[string]interface{}{"user_id": "u1"})
+	assert.Equal(t, codes.Unavailable, status.Code(err), "global 503 maps to UNAVAILABLE")
+
+	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err, "health checks are never disrupted")
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+}
@@ -0,0 +1,569 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/getmockd/mockd/pkg/logging"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoprint"
+	"github.com/jhump/protoreflect/grpcreflect"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Recording proxy errors.
+var (
+	// ErrMissingUpstream is returned when a RecordingProxyConfig has no upstream.
+	ErrMissingUpstream = errors.New("grpc recording proxy: missing upstream")
+)
+
+// reflectionTimeout bounds a server reflection lookup, so an unresponsive
+// upstream delays recording a call by at most this long.
+var reflectionTimeout = 5 * time.Second
+
+// GRPCRecordingData is a call captured by a RecordingProxy. Messages are
+// decoded to their protojson form; they are empty when no descriptor for
+// the method could be found.
+type GRPCRecordingData struct {
+	Service         string
+	Method          string
+	StreamType      string
+	Metadata        map[string][]string
+	Requests        []map[string]any
+	Responses       []map[string]any
+	ResponseHeaders map[string][]string
+	Trailers        map[string][]string
+	StatusCode      string
+	StatusMessage   string
+	Duration        time.Duration
+	// ProtoFiles and ImportPaths are the proxy's proto files, if any.
+	ProtoFiles  []string
+	ImportPaths []string
+	// ProtoContent is the source of the file declaring the service when it
+	// was fetched with server reflection.
+	ProtoContent string
+}
+
+// GRPCRecordingStore is the interface for storing gRPC recordings.
+type GRPCRecordingStore interface {
+	Add(GRPCRecordingData) error
+}
+
+// RecordingProxyConfig configures a RecordingProxy.
+type RecordingProxyConfig struct {
+	// ID identifies the proxy. Defaults to the listen address.
+	ID string `json:"id,omitempty"`
+	// Listen is the address to accept calls on, e.g. ":50052". Defaults to
+	// an ephemeral localhost port.
+	Listen string `json:"listen,omitempty"`
+	// Upstream is the host:port of the real gRPC server.
+	Upstream string `json:"upstream"`
+	// UpstreamTLS dials the upstream over TLS with the system roots.
+	UpstreamTLS bool `json:"upstreamTls,omitempty"`
+	// ProtoFile, ProtoFiles and ImportPaths describe the upstream's
+	// services. Methods they do not declare are resolved with the
+	// upstream's server reflection.
+	ProtoFile   string   `json:"protoFile,omitempty"`
+	ProtoFiles  []string `json:"protoFiles,omitempty"`
+	ImportPaths []string `json:"importPaths,omitempty"`
+}
+
+// GetProtoFiles returns all proto files from the config.
+func (c *RecordingProxyConfig) GetProtoFiles() []string {
+	var files []string
+	if c.ProtoFile != "" {
+		files = append(files, c.ProtoFile)
+	}
+	return append(files, c.ProtoFiles...)
+}
+
+// RecordingProxyStatus reports a running RecordingProxy.
+type RecordingProxyStatus struct {
+	ID         string   `json:"id"`
+	Address    string   `json:"address"`
+	Upstream   string   `json:"upstream"`
+	ProtoFiles []string `json:"protoFiles,omitempty"`
+	Calls      int64    `json:"calls"`
+	Recorded   int64    `json:"recorded"`
+}
+
+// RecordingProxy is a transparent gRPC proxy that forwards every call to an
+// upstream server and records it. Messages are forwarded as raw bytes, so
+// calls to methods without a descriptor still work; they are just recorded
+// without decoded messages.
+type RecordingProxy struct {
+	cfg      RecordingProxyConfig
+	schema   *ProtoSchema
+	conn     *grpc.ClientConn
+	server   *grpc.Server
+	listener net.Listener
+	log      *slog.Logger
+
+	mu      sync.Mutex
+	store   GRPCRecordingStore
+	methods map[string]*recordedMethod // full method ("/pkg.Svc/Method") -> descriptor, nil if unresolvable
+	sources map[string]string          // file path -> printed proto source
+
+	calls    atomic.Int64
+	recorded atomic.Int64
+}
+
+// recordedMethod is a method descriptor and where it came from.
+type recordedMethod struct {
+	desc         protoreflect.MethodDescriptor
+	protoContent string
+}
+
+// NewRecordingProxy parses the proxy's proto files and prepares a connection
+// to the upstream. Call Start to accept calls.
+func NewRecordingProxy(cfg RecordingProxyConfig, store GRPCRecordingStore) (*RecordingProxy, error) {
+	if cfg.Upstream == "" {
+		return nil, ErrMissingUpstream
+	}
+	if cfg.Listen == "" {
+		cfg.Listen = "127.0.0.1:0"
+	}
+
+	p := &RecordingProxy{
+		cfg:     cfg,
+		store:   store,
+		log:     logging.Nop(),
+		methods: make(map[string]*recordedMethod),
+		sources: make(map[string]string),
+	}
+
+	if files := cfg.GetProtoFiles(); len(files) > 0 {
+		schema, err := ParseProtoFiles(files, cfg.ImportPaths)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse proto files: %w", err)
+		}
+		p.schema = schema
+	}
+
+	creds := insecure.NewCredentials()
+	if cfg.UpstreamTLS {
+		creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+	conn, err := grpc.NewClient(cfg.Upstream, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("invalid upstream %q: %w", cfg.Upstream, err)
+	}
+	p.conn = conn
+	return p, nil
+}
+
+// SetLogger sets the operational logger.
+func (p *RecordingProxy) SetLogger(log *slog.Logger) {
+	if log != nil {
+		p.log = log
+	} else {
+		p.log = logging.Nop()
+	}
+}
+
+// SetRecordingStore replaces the store calls are recorded to. A nil store
+// forwards calls without recording them.
+func (p *RecordingProxy) SetRecordingStore(store GRPCRecordingStore) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.store = store
+}
+
+// Start begins accepting calls.
+func (p *RecordingProxy) Start() error {
+	listener, err := net.Listen("tcp", p.cfg.Listen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", p.cfg.Listen, err)
+	}
+	p.listener = listener
+	if p.cfg.ID == "" {
+		p.cfg.ID = listener.Addr().String()
+	}
+
+	p.server = grpc.NewServer(
+		grpc.UnknownServiceHandler(p.handleStream),
+		grpc.ForceServerCodec(rawCodec{}),
+	)
+	server := p.server
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			p.log.Error("gRPC recording proxy error", "error", err)
+		}
+	}()
+	return nil
+}
+
+// Stop stops accepting calls, cancels calls in flight and closes the
+// upstream connection.
+func (p *RecordingProxy) Stop() error {
+	if p.server != nil {
+		p.server.Stop()
+	}
+	return p.conn.Close()
+}
+
+// ID returns the proxy's identifier.
+func (p *RecordingProxy) ID() string {
+	return p.cfg.ID
+}
+
+// Addr returns the address the proxy accepts calls on.
+func (p *RecordingProxy) Addr() string {
+	if p.listener == nil {
+		return p.cfg.Listen
+	}
+	return p.listener.Addr().String()
+}
+
+// Status reports the proxy's configuration and call counts.
+func (p *RecordingProxy) Status() RecordingProxyStatus {
+	return RecordingProxyStatus{
+		ID:         p.cfg.ID,
+		Address:    p.Addr(),
+		Upstream:   p.cfg.Upstream,
+		ProtoFiles: p.cfg.GetProtoFiles(),
+		Calls:      p.calls.Load(),
+		Recorded:   p.recorded.Load(),
+	}
+}
+
+// proxyStreamDesc lets one handler forward every kind of call.
+var proxyStreamDesc = &grpc.StreamDesc{ServerStreams: true, ClientStreams: true}
+
+// proxyCall collects the raw messages of one forwarded call.
+type proxyCall struct {
+	mu        sync.Mutex
+	requests  [][]byte
+	responses [][]byte
+}
+
+func (c *proxyCall) add(msgs *[][]byte, b []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	*msgs = append(*msgs, b)
+}
+
+// handleStream forwards a call to the upstream and records it once it ends.
+func (p *RecordingProxy) handleStream(_ any, ss grpc.ServerStream) error {
+	start := time.Now()
+	p.calls.Add(1)
+
+	fullMethod, ok := grpc.MethodFromServerStream(ss)
+	if !ok {
+		return status.Error(codes.Internal, "cannot determine method")
+	}
+
+	md, _ := metadata.FromIncomingContext(ss.Context())
+	ctx, cancel := context.WithCancel(metadata.NewOutgoingContext(ss.Context(), forwardedMetadata(md)))
+	defer cancel()
+
+	call := &proxyCall{}
+	var header, trailer metadata.MD
+
+	err := p.forward(ctx, ss, fullMethod, call, &header, &trailer)
+	if trailer != nil {
+		ss.SetTrailer(trailer)
+	}
+	p.record(fullMethod, md, call, header, trailer, err, time.Since(start))
+	return err
+}
+
+func (p *RecordingProxy) forward(ctx context.Context, ss grpc.ServerStream, fullMethod string, call *proxyCall, header, trailer *metadata.MD) error {
+	cs, err := p.conn.NewStream(ctx, proxyStreamDesc, fullMethod, grpc.ForceCodec(rawCodec{}))
+	if err != nil {
+		return err
+	}
+
+	// Client -> upstream. If the client goes away, ctx is cancelled and the
+	// upstream call ends with it.
+	go func() {
+		for {
+			f := &frame{}
+			if err := ss.RecvMsg(f); err != nil {
+				if errors.Is(err, io.EOF) {
+					_ = cs.CloseSend()
+				}
+				return
+			}
+			call.add(&call.requests, f.payload)
+			if err := cs.SendMsg(f); err != nil {
+				// The upstream ended the call; RecvMsg below reports why.
+				return
+			}
+		}
+	}()
+
+	// Upstream -> client
+	if h, err := cs.Header(); err == nil {
+		*header = h
+		if err := ss.SendHeader(h); err != nil {
+			return err
+		}
+	}
+	for {
+		f := &frame{}
+		if err := cs.RecvMsg(f); err != nil {
+			*trailer = cs.Trailer()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		call.add(&call.responses, f.payload)
+		if err := ss.SendMsg(f); err != nil {
+			return err
+		}
+	}
+}
+
+// forwardedMetadata drops metadata the upstream connection sets itself.
+func forwardedMetadata(md metadata.MD) metadata.MD {
+	out := metadata.MD{}
+	for k, v := range md {
+		if strings.HasPrefix(k, ":") || k == "content-type" || k == "user-agent" || k == "te" {
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// record decodes a finished call and adds it to the store.
+func (p *RecordingProxy) record(fullMethod string, md metadata.MD, call *proxyCall, header, trailer metadata.MD, callErr error, duration time.Duration) {
+	p.mu.Lock()
+	store := p.store
+	p.mu.Unlock()
+	if store == nil {
+		return
+	}
+
+	service, method := splitFullMethod(fullMethod)
+	st := status.Convert(callErr)
+	data := GRPCRecordingData{
+		Service:         service,
+		Method:          method,
+		Metadata:        forwardedMetadata(md),
+		ResponseHeaders: header,
+		Trailers:        trailer,
+		StatusCode:      grpcCodeToString(st.Code()),
+		StatusMessage:   st.Message(),
+		Duration:        duration,
+		ProtoFiles:      p.cfg.GetProtoFiles(),
+		ImportPaths:     p.cfg.ImportPaths,
+	}
+	if st.Code() == codes.OK {
+		data.StatusMessage = ""
+	}
+
+	call.mu.Lock()
+	requests, responses := call.requests, call.responses
+	call.mu.Unlock()
+
+	if rm := p.resolveMethod(fullMethod); rm != nil {
+		data.StreamType = streamTypeOf(rm.desc)
+		data.ProtoContent = rm.protoContent
+		data.Requests = decodeMessages(rm.desc.Input(), requests)
+		data.Responses = decodeMessages(rm.desc.Output(), responses)
+	} else {
+		p.log.Warn("no descriptor for gRPC method, recording without messages", "method", fullMethod)
+		data.StreamType = inferStreamType(len(requests), len(responses))
+	}
+
+	if err := store.Add(data); err != nil {
+		p.log.Error("failed to store gRPC recording", "method", fullMethod, "error", err)
+		return
+	}
+	p.recorded.Add(1)
+}
+
+// resolveMethod finds a method's descriptor in the proto files, then with
+// the upstream's server reflection. Results, including failures, are cached;
+// a reflection lookup that timed out is retried by the next call.
+func (p *RecordingProxy) resolveMethod(fullMethod string) *recordedMethod {
+	service, method := splitFullMethod(fullMethod)
+
+	p.mu.Lock()
+	if rm, ok := p.methods[fullMethod]; ok {
+		p.mu.Unlock()
+		return rm
+	}
+	if p.schema != nil {
+		if svc := p.schema.GetService(service); svc != nil {
+			if m := svc.GetMethod(method); m != nil {
+				rm := &recordedMethod{desc: m.GetDescriptor()}
+				p.methods[fullMethod] = rm
+				p.mu.Unlock()
+				return rm
+			}
+		}
+	}
+	p.mu.Unlock()
+
+	// The lookup talks to the upstream, so it runs without holding p.mu.
+	fd, m, err := p.reflectMethod(service, method)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if rm, ok := p.methods[fullMethod]; ok {
+		return rm // resolved by a concurrent call
+	}
+	var rm *recordedMethod
+	if m != nil {
+		rm = &recordedMethod{desc: m, protoContent: p.protoSource(fd)}
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		p.methods[fullMethod] = rm
+	}
+	return rm
+}
+
+// reflectMethod resolves a method with the upstream's server reflection,
+// giving up after reflectionTimeout. It returns a nil method when the
+// upstream does not declare it.
+func (p *RecordingProxy) reflectMethod(service, method string) (*desc.FileDescriptor, protoreflect.MethodDescriptor, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), reflectionTimeout)
+	defer cancel()
+	client := grpcreflect.NewClientAuto(ctx, p.conn)
+	defer client.Reset()
+
+	fd, err := client.FileContainingSymbol(service)
+	if err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		p.log.Debug("server reflection lookup failed", "service", service, "error", err)
+		return nil, nil, err
+	}
+	svc := fd.UnwrapFile().Services().ByName(protoreflect.FullName(service).Name())
+	if svc == nil {
+		return nil, nil, nil
+	}
+	return fd, svc.Methods().ByName(protoreflect.Name(method)), nil
+}
+
+// protoSource prints a reflected file as .proto source that a mock can load
+// as inline content. Files importing anything but the well-known types
+// cannot stand alone and yield "".
+func (p *RecordingProxy) protoSource(fd *desc.FileDescriptor) string {
+	if src, ok := p.sources[fd.GetName()]; ok {
+		return src
+	}
+	src := ""
+	standalone := true
+	for _, dep := range fd.GetDependencies() {
+		if !strings.HasPrefix(dep.GetName(), "google/protobuf/") {
+			standalone = false
+			break
+		}
+	}
+	if standalone {
+		if out, err := (&protoprint.Printer{}).PrintProtoToString(fd); err == nil {
+			src = out
+		}
+	}
+	p.sources[fd.GetName()] = src
+	return src
+}
+
+// splitFullMethod splits "/pkg.Service/Method" into its service and method.
+func splitFullMethod(fullMethod string) (string, string) {
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return service, method
+}
+
+func streamTypeOf(m protoreflect.MethodDescriptor) string {
+	switch {
+	case m.IsStreamingClient() && m.IsStreamingServer():
+		return "bidirectional"
+	case m.IsStreamingClient():
+		return "client_streaming"
+	case m.IsStreamingServer():
+		return "server_streaming"
+	default:
+		return "unary"
+	}
+}
+
+// inferStreamType guesses a call's stream type from its message counts.
+func inferStreamType(requests, responses int) string {
+	switch {
+	case requests > 1 && responses > 1:
+		return "bidirectional"
+	case requests > 1:
+		return "client_streaming"
+	case responses > 1:
+		return "server_streaming"
+	default:
+		return "unary"
+	}
+}
+
+// decodeMessages decodes raw messages to their protojson maps.
+func decodeMessages(md protoreflect.MessageDescriptor, raw [][]byte) []map[string]any {
+	if len(raw) == 0 {
+		return nil
+	}
+	out := make([]map[string]any, 0, len(raw))
+	for _, b := range raw {
+		msg := dynamicpb.NewMessage(md)
+		if err := proto.Unmarshal(b, msg); err != nil {
+			continue
+		}
+		data, err := protojson.Marshal(msg)
+		if err != nil {
+			continue
+		}
+		var m map[string]any
+		if err := json.Unmarshal(data, &m); err != nil {
+			continue
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// frame is a message forwarded without decoding.
+type frame struct {
+	payload []byte
+}
+
+// rawCodec passes messages through as bytes.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	f, ok := v.(*frame)
+	if !ok {
+		return nil, fmt.Errorf("rawCodec: unexpected message type %T", v)
+	}
+	return f.payload, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	f, ok := v.(*frame)
+	if !ok {
+		return fmt.Errorf("rawCodec: unexpected message type %T", v)
+	}
+	f.payload = append([]byte(nil), data...)
+	return nil
+}
+
+func (rawCodec) Name() string { return "proto" }
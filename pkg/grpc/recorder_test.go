@@ -0,0 +1,228 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jhump/protoreflect/dynamic"
+	"github.com/jhump/protoreflect/dynamic/grpcdynamic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// memRecordingStore collects recordings in memory.
+type memRecordingStore struct {
+	mu   sync.Mutex
+	data []GRPCRecordingData
+}
+
+func (s *memRecordingStore) Add(d GRPCRecordingData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = append(s.data, d)
+	return nil
+}
+
+func (s *memRecordingStore) all() []GRPCRecordingData {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]GRPCRecordingData(nil), s.data...)
+}
+
+// startUpstream starts a mock server to record against.
+func startUpstream(t *testing.T, reflection bool) *Server {
+	t.Helper()
+	config := &GRPCConfig{
+		Port:       0,
+		Reflection: reflection,
+		Services: map[string]ServiceConfig{
+			"test.UserService": {
+				Methods: map[string]MethodConfig{
+					"GetUser": {
+						Match: &MethodMatch{Request: map[string]interface{}{"id": "missing"}},
+						Error: &GRPCErrorConfig{Code: "NOT_FOUND", Message: "user not found"},
+						Variants: []MethodConfig{{
+							Response: map[string]interface{}{"id": "user-1", "name": "User One"},
+						}},
+					},
+					"ListUsers": {
+						Responses: []interface{}{
+							map[string]interface{}{"id": "user-1"},
+							map[string]interface{}{"id": "user-2"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	srv, err := NewServer(config, getTestSchema(t))
+	require.NoError(t, err)
+	require.NoError(t, srv.Start(context.Background()))
+	t.Cleanup(func() { _ = srv.Stop(context.Background(), 5*time.Second) })
+	return srv
+}
+
+func startRecordingProxy(t *testing.T, cfg RecordingProxyConfig, store GRPCRecordingStore) *grpc.ClientConn {
+	t.Helper()
+	proxy, err := NewRecordingProxy(cfg, store)
+	require.NoError(t, err)
+	require.NoError(t, proxy.Start())
+	t.Cleanup(func() { _ = proxy.Stop() })
+
+	conn, err := grpc.NewClient(proxy.Addr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestNewRecordingProxy_RequiresUpstream(t *testing.T) {
+	_, err := NewRecordingProxy(RecordingProxyConfig{}, nil)
+	assert.ErrorIs(t, err, ErrMissingUpstream)
+}
+
+func TestRecordingProxy_ReflectionDoesNotBlockOnStalledUpstream(t *testing.T) {
+	// The upstream accepts connections but never speaks HTTP/2.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { _ = conn.Close() })
+		}
+	}()
+
+	defer func(old time.Duration) { reflectionTimeout = old }(reflectionTimeout)
+	reflectionTimeout = 300 * time.Millisecond
+
+	proxy, err := NewRecordingProxy(RecordingProxyConfig{Upstream: listener.Addr().String()}, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = proxy.Stop() })
+
+	done := make(chan *recordedMethod)
+	go func() { done <- proxy.resolveMethod("/test.Greeter/SayHello") }()
+
+	// The lookup in flight must not hold the proxy's lock.
+	locked := make(chan struct{})
+	go func() {
+		proxy.SetRecordingStore(&memRecordingStore{})
+		close(locked)
+	}()
+	select {
+	case <-locked:
+	case <-time.After(reflectionTimeout / 2):
+		t.Fatal("SetRecordingStore blocked on a reflection lookup")
+	}
+
+	select {
+	case rm := <-done:
+		assert.Nil(t, rm)
+	case <-time.After(5 * reflectionTimeout):
+		t.Fatal("reflection lookup did not time out")
+	}
+	proxy.mu.Lock()
+	_, cached := proxy.methods["/test.Greeter/SayHello"]
+	proxy.mu.Unlock()
+	assert.False(t, cached, "a timed-out lookup should be retried")
+}
+
+func TestRecordingProxy_UnaryWithReflection(t *testing.T) {
+	upstream := startUpstream(t, true)
+	store := &memRecordingStore{}
+	conn := startRecordingProxy(t, RecordingProxyConfig{Upstream: upstream.Address()}, store)
+
+	methodDesc := getMethodDesc(t, getTestDescriptors(t), "test.UserService", "GetUser")
+	stub := grpcdynamic.NewStub(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-tenant", "acme")
+
+	req := dynamic.NewMessage(methodDesc.GetInputType())
+	req.SetFieldByName("id", "user-1")
+	resp, err := stub.InvokeRpc(ctx, methodDesc, req)
+	require.NoError(t, err)
+	assert.Equal(t, "User One", resp.(*dynamic.Message).GetFieldByName("name"))
+
+	req.SetFieldByName("id", "missing")
+	_, err = stub.InvokeRpc(ctx, methodDesc, req)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.NotFound, st.Code())
+	assert.Equal(t, "user not found", st.Message())
+
+	recs := store.all()
+	require.Len(t, recs, 2)
+
+	ok0 := recs[0]
+	assert.Equal(t, "test.UserService", ok0.Service)
+	assert.Equal(t, "GetUser", ok0.Method)
+	assert.Equal(t, "unary", ok0.StreamType)
+	assert.Equal(t, "OK", ok0.StatusCode)
+	assert.Equal(t, []string{"acme"}, ok0.Metadata["x-tenant"])
+	require.Len(t, ok0.Requests, 1)
+	assert.Equal(t, "user-1", ok0.Requests[0]["id"])
+	require.Len(t, ok0.Responses, 1)
+	assert.Equal(t, "User One", ok0.Responses[0]["name"])
+	assert.Contains(t, ok0.ProtoContent, "service UserService")
+	assert.Empty(t, ok0.ProtoFiles)
+
+	assert.Equal(t, "NOT_FOUND", recs[1].StatusCode)
+	assert.Equal(t, "user not found", recs[1].StatusMessage)
+	assert.Empty(t, recs[1].Responses)
+}
+
+func TestRecordingProxy_ServerStreamingWithProtoFile(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	protoPath := filepath.Join(wd, "..", "..", "tests", "fixtures", "grpc", "test.proto")
+
+	upstream := startUpstream(t, false)
+	store := &memRecordingStore{}
+	conn := startRecordingProxy(t, RecordingProxyConfig{Upstream: upstream.Address(), ProtoFile: protoPath}, store)
+
+	methodDesc := getMethodDesc(t, getTestDescriptors(t), "test.UserService", "ListUsers")
+	stub := grpcdynamic.NewStub(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req := dynamic.NewMessage(methodDesc.GetInputType())
+	req.SetFieldByName("page_size", int32(2))
+	stream, err := stub.InvokeRpcServerStream(ctx, methodDesc, req)
+	require.NoError(t, err)
+	received := 0
+	for {
+		if _, err := stream.RecvMsg(); err != nil {
+			break
+		}
+		received++
+	}
+	assert.Equal(t, 2, received)
+
+	recs := store.all()
+	require.Len(t, recs, 1)
+	rec := recs[0]
+	assert.Equal(t, "server_streaming", rec.StreamType)
+	assert.Equal(t, "OK", rec.StatusCode)
+	assert.Equal(t, []string{protoPath}, rec.ProtoFiles)
+	assert.Empty(t, rec.ProtoContent)
+	require.Len(t, rec.Requests, 1)
+	assert.Equal(t, float64(2), rec.Requests[0]["pageSize"])
+	require.Len(t, rec.Responses, 2)
+	assert.Equal(t, "user-2", rec.Responses[1]["id"])
+}
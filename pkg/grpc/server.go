@@ -16,6 +16,7 @@ import (
 
 	"github.com/getmockd/mockd/pkg/logging"
 	"github.com/getmockd/mockd/pkg/metrics"
+	"github.com/getmockd/mockd/pkg/mock"
 	"github.com/getmockd/mockd/pkg/protocol"
 	"github.com/getmockd/mockd/pkg/requestlog"
 	"github.com/getmockd/mockd/pkg/template"
@@ -92,6 +93,14 @@ type Server struct {
 	// Request logging support
 	requestLoggerMu sync.RWMutex
 	requestLogger   requestlog.Logger
+
+	// Stateful support (optional: backs methods with a StatefulBinding)
+	statefulMu       sync.RWMutex
+	statefulExecutor StatefulExecutor
+
+	// Chaos support (optional: injects faults configured for gRPC)
+	chaosMu     sync.RWMutex
+	chaosSource ChaosSource
 }
 
 // NewServer creates a new gRPC mock server.
@@ -138,16 +147,19 @@ func (s *Server) Start(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to listen on %s: %w", addr, err)
 	}
-	s.listener = listener
+	tracked := newTrackingListener(listener)
+	s.listener = tracked
 
 	// Reflect the actual bound port back to config (important when Port=0)
 	if tcpAddr, ok := listener.Addr().(*net.TCPAddr); ok {
 		s.config.Port = tcpAddr.Port
 	}
 
-	// Create gRPC server with unknown service handler
+	// Create gRPC server with unknown service handler and chaos interceptors
 	s.grpcServer = grpc.NewServer(
 		grpc.UnknownServiceHandler(s.handleStream),
+		grpc.ChainUnaryInterceptor(s.chaosUnaryInterceptor),
+		grpc.ChainStreamInterceptor(s.chaosStreamInterceptor),
 	)
 
 	// Register all services dynamically
@@ -165,7 +177,7 @@ func (s *Server) Start(ctx context.Context) error {
 	// so the goroutine doesn't dereference s.grpcServer after Stop() nils it.
 	grpcSrv := s.grpcServer
 	go func() {
-		if err := grpcSrv.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		if err := grpcSrv.Serve(tracked); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
 			// Log error but don't crash - server may have been stopped
 			s.log.Error("gRPC server error", "error", err)
 		}
@@ -624,7 +636,14 @@ func (r *schemaDescriptorResolver) FindDescriptorByName(name protoreflect.FullNa
 // makeUnaryHandler creates a unary handler for a specific service/method.
 func (s *Server) makeUnaryHandler(serviceName, methodName string) func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-		return s.handleUnary(srv, ctx, dec, interceptor, serviceName, methodName)
+		if interceptor == nil {
+			return s.handleUnary(srv, ctx, dec, nil, serviceName, methodName)
+		}
+		// The request is decoded inside handleUnary, so interceptors see a nil request.
+		info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/" + methodName}
+		return interceptor(ctx, nil, info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+			return s.handleUnary(srv, ctx, dec, nil, serviceName, methodName)
+		})
 	}
 }
 
@@ -703,6 +722,11 @@ func (s *Server) handleUnary(_ interface{}, ctx context.Context, dec func(interf
 		return nil, grpcErr
 	}
 
+	// Serve from the bound table if configured
+	if methodCfg.StatefulBinding != nil {
+		return s.handleStatefulUnary(ctx, startTime, fullPath, method, serviceName, methodName, methodCfg.StatefulBinding, md, reqMap)
+	}
+
 	// Create template context for response processing
 	templateCtx := s.createTemplateContext(md, reqMap)
 
@@ -722,6 +746,25 @@ func (s *Server) handleUnary(_ interface{}, ctx context.Context, dec func(interf
 	return resp, nil
 }
 
+// handleStatefulUnary serves a unary call from the method's bound table.
+func (s *Server) handleStatefulUnary(ctx context.Context, startTime time.Time, fullPath string, method *MethodDescriptor, serviceName, methodName string, binding *mock.StatefulBinding, md metadata.MD, reqMap map[string]interface{}) (interface{}, error) {
+	responses, grpcErr := s.statefulResponseData(ctx, method, methodName, binding, reqMap)
+	if grpcErr != nil {
+		s.logGRPCCall(startTime, fullPath, serviceName, methodName, streamUnary, md, reqMap, nil, grpcErr)
+		return nil, grpcErr
+	}
+
+	resp, err := buildStatefulMessage(method.GetOutputDescriptor(), responses[0])
+	if err != nil {
+		grpcErr := status.Errorf(codes.Internal, "failed to build response: %v", err)
+		s.logGRPCCall(startTime, fullPath, serviceName, methodName, streamUnary, md, reqMap, nil, grpcErr)
+		return nil, grpcErr
+	}
+
+	s.logGRPCCall(startTime, fullPath, serviceName, methodName, streamUnary, md, reqMap, dynamicMessageToMap(resp), nil)
+	return resp, nil
+}
+
 // handleStream handles the unknown service handler (fallback for unregistered services).
 func (s *Server) handleStream(srv interface{}, stream grpc.ServerStream) error {
 	// Extract method info from stream
@@ -861,6 +904,22 @@ func (s *Server) handleServerStreaming(stream grpc.ServerStream, method *MethodD
 
 	// Create template context for response processing
 	templateCtx := s.createTemplateContext(md, reqMap)
+	buildResponse := func(data interface{}) (*dynamicpb.Message, error) {
+		return s.buildResponse(method, data, templateCtx)
+	}
+
+	// Stream rows from the bound table if configured
+	if methodCfg.StatefulBinding != nil {
+		var grpcErr error
+		responses, grpcErr = s.statefulResponseData(ctx, method, methodName, methodCfg.StatefulBinding, reqMap)
+		if grpcErr != nil {
+			s.logGRPCCall(startTime, fullPath, serviceName, methodName, streamServerStream, md, reqMap, nil, grpcErr)
+			return grpcErr
+		}
+		buildResponse = func(data interface{}) (*dynamicpb.Message, error) {
+			return buildStatefulMessage(method.GetOutputDescriptor(), data)
+		}
+	}
 
 	// Collect responses for logging
 	var collectedResponses []interface{}
@@ -873,7 +932,7 @@ func (s *Server) handleServerStreaming(stream grpc.ServerStream, method *MethodD
 			return grpcErr
 		}
 
-		resp, err := s.buildResponse(method, respData, templateCtx)
+		resp, err := buildResponse(respData)
 		if err != nil {
 			grpcErr := status.Errorf(codes.Internal, "failed to build response: %v", err)
 			s.logGRPCCall(startTime, fullPath, serviceName, methodName, streamServerStream, md, reqMap, collectedResponses, grpcErr)
@@ -962,6 +1021,11 @@ func (s *Server) handleClientStreaming(stream grpc.ServerStream, method *MethodD
 		s.logGRPCCall(startTime, fullPath, serviceName, methodName, streamClientStream, md, allRequests, nil, err)
 		return err
 	}
+	if methodCfg.StatefulBinding != nil {
+		err := status.Errorf(codes.Unimplemented, "statefulBinding is not supported on %s methods", streamTypeToString(streamClientStream))
+		s.logGRPCCall(startTime, fullPath, serviceName, methodName, streamClientStream, md, allRequests, nil, err)
+		return err
+	}
 
 	// Apply delay (context-aware for client/tracker cancellation)
 	s.applyDelayWithContext(ctx, methodCfg.Delay)
@@ -1030,6 +1094,11 @@ func (s *Server) handleBidirectionalStreaming(stream grpc.ServerStream, method *
 		s.logGRPCCall(startTime, fullPath, serviceName, methodName, streamBidi, md, nil, nil, err)
 		return err
 	}
+	if methodCfg.StatefulBinding != nil {
+		err := status.Errorf(codes.Unimplemented, "statefulBinding is not supported on %s methods", streamTypeToString(streamBidi))
+		s.logGRPCCall(startTime, fullPath, serviceName, methodName, streamBidi, md, nil, nil, err)
+		return err
+	}
 
 	// Apply initial delay (context-aware for client/tracker cancellation)
 	s.applyDelayWithContext(ctx, methodCfg.Delay)
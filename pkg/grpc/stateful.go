@@ -0,0 +1,405 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/getmockd/mockd/pkg/mock"
+	"github.com/getmockd/mockd/pkg/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// StatefulAction represents a table action for stateful methods.
+type StatefulAction string
+
+const (
+	StatefulActionGet    StatefulAction = "get"
+	StatefulActionList   StatefulAction = "list"
+	StatefulActionCreate StatefulAction = "create"
+	StatefulActionUpdate StatefulAction = "update"
+	StatefulActionPatch  StatefulAction = "patch"
+	StatefulActionDelete StatefulAction = "delete"
+	StatefulActionCustom StatefulAction = "custom"
+)
+
+// defaultListLimit is the page size used when a list request does not set one.
+const defaultListLimit = 100
+
+// wellKnownPrefix identifies google.protobuf types (Timestamp, FieldMask, ...)
+// which are never treated as the payload or response wrapper.
+const wellKnownPrefix = "google.protobuf."
+
+// StatefulRequest is a protocol-agnostic request to perform a table action.
+// The server builds it from the decoded request message and the method's
+// binding; the StatefulExecutor (provided by the engine) executes it.
+type StatefulRequest struct {
+	// Resource is the stateful table name (e.g., "users").
+	Resource string
+	// Action is the table action to perform.
+	Action StatefulAction
+	// OperationName is the custom operation to execute when Action is "custom".
+	// It defaults to the gRPC method name.
+	OperationName string
+	// ResourceID is the item ID for single-item actions.
+	ResourceID string
+	// Data is the request payload, keyed by protobuf JSON field names.
+	Data map[string]interface{}
+	// Filter contains pagination and equality filters for list.
+	Filter *StatefulFilter
+}
+
+// StatefulFilter contains pagination/filter parameters for list actions.
+type StatefulFilter struct {
+	Limit   int
+	Offset  int
+	Sort    string
+	Order   string
+	Filters map[string]string
+}
+
+// StatefulResult is the protocol-agnostic result of a table action.
+type StatefulResult struct {
+	// Item is the single item result (get, create, update, patch, delete, custom).
+	Item map[string]interface{}
+	// Items is the list result.
+	Items []map[string]interface{}
+	// Meta contains pagination metadata for list results.
+	Meta *StatefulListMeta
+	// Error is the gRPC error to return, if any. Details use the same keys
+	// as GRPCErrorConfig.Details (resource_info, bad_request, ...).
+	Error *GRPCErrorConfig
+}
+
+// StatefulListMeta contains pagination metadata.
+type StatefulListMeta struct {
+	Total  int
+	Count  int
+	Offset int
+	Limit  int
+}
+
+// StatefulExecutor is the interface that the engine provides to execute
+// table actions. This decouples the gRPC package from pkg/stateful; the
+// engine wires in an implementation that delegates to stateful.Bridge.
+type StatefulExecutor interface {
+	ExecuteStateful(ctx context.Context, req *StatefulRequest) *StatefulResult
+}
+
+// SetStatefulExecutor configures the executor used by methods with a
+// StatefulBinding. Without one, bound methods fail with codes.Internal.
+func (s *Server) SetStatefulExecutor(executor StatefulExecutor) {
+	s.statefulMu.Lock()
+	defer s.statefulMu.Unlock()
+	s.statefulExecutor = executor
+}
+
+// GetStatefulExecutor returns the stateful executor, if configured.
+func (s *Server) GetStatefulExecutor() StatefulExecutor {
+	s.statefulMu.RLock()
+	defer s.statefulMu.RUnlock()
+	return s.statefulExecutor
+}
+
+// statefulResponseData executes a bound method against its table and returns
+// the response payloads to send. Lists on server-streaming methods produce one
+// payload per row; every other combination produces exactly one payload.
+// Errors are gRPC status errors.
+func (s *Server) statefulResponseData(ctx context.Context, method *MethodDescriptor, methodName string, binding *mock.StatefulBinding, reqMap map[string]interface{}) ([]interface{}, error) {
+	executor := s.GetStatefulExecutor()
+	if executor == nil {
+		return nil, status.Error(codes.Internal, "stateful executor is not configured")
+	}
+
+	req := buildStatefulRequest(method.GetInputDescriptor(), methodName, binding, reqMap)
+	result := executor.ExecuteStateful(ctx, req)
+	if result == nil {
+		result = &StatefulResult{}
+	}
+	if result.Error != nil {
+		return nil, s.toGRPCError(result.Error)
+	}
+
+	outputDesc := method.GetOutputDescriptor()
+	if outputDesc == nil {
+		return nil, status.Error(codes.Internal, "cannot get output descriptor")
+	}
+
+	if result.Items != nil {
+		if method.IsServerStreaming() {
+			responses := make([]interface{}, len(result.Items))
+			for i, item := range result.Items {
+				responses[i] = shapeItem(outputDesc, item)
+			}
+			return responses, nil
+		}
+		data, err := listResponseData(outputDesc, result)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return []interface{}{data}, nil
+	}
+
+	data := map[string]interface{}{}
+	if result.Item != nil {
+		data = shapeItem(outputDesc, result.Item)
+	}
+	if req.Action == StatefulActionDelete {
+		for _, name := range []string{"success", "deleted"} {
+			if f := outputDesc.Fields().ByJSONName(name); f != nil && f.Kind() == protoreflect.BoolKind {
+				data[name] = true
+			}
+		}
+	}
+	return []interface{}{data}, nil
+}
+
+// buildStatefulMessage converts a table payload into a response message.
+// Fields the message does not declare (e.g. createdAt) are dropped.
+func buildStatefulMessage(desc protoreflect.MessageDescriptor, data interface{}) (*dynamicpb.Message, error) {
+	msg := dynamicpb.NewMessage(desc)
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal stateful response: %w", err)
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(jsonData, msg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal stateful response into %s: %w", desc.FullName(), err)
+	}
+	return msg, nil
+}
+
+// buildStatefulRequest maps a decoded request message to a table request.
+// reqMap is keyed by protobuf JSON field names, so table fields use the same
+// lowerCamelCase names as a REST façade over the table.
+//
+//   - get/delete read the ID from "id" or "<singular table>Id".
+//   - create/update/patch use the request's single message field (e.g.
+//     CreateUserRequest.user) as the payload when there is one, or else
+//     every other field.
+//   - list reads pageSize/limit, offset/pageToken, orderBy/sort and order
+//     as pagination; other scalar fields are equality filters.
+//   - custom passes the whole request as the operation input.
+func buildStatefulRequest(inputDesc protoreflect.MessageDescriptor, methodName string, binding *mock.StatefulBinding, reqMap map[string]interface{}) *StatefulRequest {
+	action := StatefulAction(binding.Action)
+	req := &StatefulRequest{
+		Resource:      binding.Table,
+		Action:        action,
+		OperationName: binding.Operation,
+	}
+	if req.OperationName == "" {
+		req.OperationName = methodName
+	}
+
+	data := make(map[string]interface{}, len(reqMap))
+	for k, v := range reqMap {
+		data[k] = v
+	}
+	idKey := statefulIDField(inputDesc, binding.Table)
+
+	switch action {
+	case StatefulActionGet, StatefulActionDelete:
+		if id, ok := data[idKey]; ok && idKey != "" {
+			req.ResourceID = fmt.Sprintf("%v", id)
+		}
+
+	case StatefulActionUpdate, StatefulActionPatch:
+		payload := requestPayload(inputDesc, data, idKey)
+		if id, ok := data[idKey]; ok && idKey != "" {
+			req.ResourceID = fmt.Sprintf("%v", id)
+		} else if id, ok := payload["id"]; ok {
+			req.ResourceID = fmt.Sprintf("%v", id)
+		}
+		delete(payload, "id")
+		req.Data = payload
+
+	case StatefulActionCreate:
+		payload := requestPayload(inputDesc, data, "")
+		if id, ok := payload["id"]; ok {
+			req.ResourceID = fmt.Sprintf("%v", id)
+		}
+		req.Data = payload
+
+	case StatefulActionList:
+		req.Filter = listFilter(data)
+
+	case StatefulActionCustom:
+		req.Data = data
+	}
+
+	return req
+}
+
+// statefulIDField returns the JSON name of the request field holding the item
+// ID: "id" when declared, else "<singular table>Id" (e.g. userId), else "".
+func statefulIDField(desc protoreflect.MessageDescriptor, table string) string {
+	if desc == nil {
+		return ""
+	}
+	if desc.Fields().ByJSONName("id") != nil {
+		return "id"
+	}
+	if table != "" {
+		name := util.Singularize(table) + "Id"
+		if desc.Fields().ByJSONName(name) != nil {
+			return name
+		}
+	}
+	return ""
+}
+
+// requestPayload returns the request's single message field when it has one
+// (CreateUserRequest{User user}), or else every field except skip.
+func requestPayload(desc protoreflect.MessageDescriptor, data map[string]interface{}, skip string) map[string]interface{} {
+	payload := make(map[string]interface{})
+	if f := singleMessageField(desc); f != nil {
+		if nested, ok := data[f.JSONName()].(map[string]interface{}); ok {
+			for k, v := range nested {
+				payload[k] = v
+			}
+			return payload
+		}
+	}
+	for k, v := range data {
+		if k != skip {
+			payload[k] = v
+		}
+	}
+	return payload
+}
+
+// listFilter extracts pagination and equality filters from a list request.
+// orderBy accepts AIP-style "field desc".
+func listFilter(data map[string]interface{}) *StatefulFilter {
+	filter := &StatefulFilter{
+		Limit:   defaultListLimit,
+		Sort:    "createdAt",
+		Order:   "desc",
+		Filters: make(map[string]string),
+	}
+	for key, v := range data {
+		switch key {
+		case "pageSize", "limit":
+			if n, ok := intValue(v); ok && n > 0 {
+				filter.Limit = n
+			}
+		case "offset", "pageToken":
+			if n, ok := intValue(v); ok && n >= 0 {
+				filter.Offset = n
+			}
+		case "orderBy", "sort":
+			parts := strings.Fields(fmt.Sprintf("%v", v))
+			if len(parts) > 0 {
+				filter.Sort = parts[0]
+			}
+			if len(parts) > 1 {
+				filter.Order = strings.ToLower(parts[1])
+			}
+		case "order":
+			filter.Order = strings.ToLower(fmt.Sprintf("%v", v))
+		default:
+			switch v.(type) {
+			case map[string]interface{}, []interface{}:
+				// Only scalar fields are equality filters.
+			default:
+				filter.Filters[key] = fmt.Sprintf("%v", v)
+			}
+		}
+	}
+	return filter
+}
+
+// listResponseData builds a list response: rows go in the first repeated
+// message field, and total/totalSize/totalCount and nextPageToken are filled
+// when the message declares them.
+func listResponseData(desc protoreflect.MessageDescriptor, result *StatefulResult) (map[string]interface{}, error) {
+	var itemsField protoreflect.FieldDescriptor
+	fields := desc.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		f := fields.Get(i)
+		if f.IsList() && f.Kind() == protoreflect.MessageKind {
+			itemsField = f
+			break
+		}
+	}
+	if itemsField == nil {
+		return nil, fmt.Errorf("response message %s has no repeated message field for list results", desc.FullName())
+	}
+
+	items := make([]interface{}, len(result.Items))
+	for i, item := range result.Items {
+		items[i] = item
+	}
+	data := map[string]interface{}{itemsField.JSONName(): items}
+
+	if meta := result.Meta; meta != nil {
+		for _, name := range []string{"total", "totalSize", "totalCount"} {
+			if fields.ByJSONName(name) != nil {
+				data[name] = meta.Total
+			}
+		}
+		if fields.ByJSONName("nextPageToken") != nil && meta.Offset+meta.Count < meta.Total {
+			data["nextPageToken"] = strconv.Itoa(meta.Offset + meta.Count)
+		}
+	}
+	return data, nil
+}
+
+// shapeItem returns item unchanged when the message declares any of its
+// fields, or wrapped in the message's single message field otherwise
+// (GetUserResponse{User user}).
+func shapeItem(desc protoreflect.MessageDescriptor, item map[string]interface{}) map[string]interface{} {
+	fields := desc.Fields()
+	for key := range item {
+		if fields.ByJSONName(key) != nil || fields.ByName(protoreflect.Name(key)) != nil {
+			return item
+		}
+	}
+	if f := singleMessageField(desc); f != nil {
+		return map[string]interface{}{f.JSONName(): item}
+	}
+	return item
+}
+
+// singleMessageField returns the message's only singular, non-well-known
+// message field, or nil when there is not exactly one.
+func singleMessageField(desc protoreflect.MessageDescriptor) protoreflect.FieldDescriptor {
+	if desc == nil {
+		return nil
+	}
+	var found protoreflect.FieldDescriptor
+	fields := desc.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		f := fields.Get(i)
+		if f.Kind() != protoreflect.MessageKind || f.IsList() || f.IsMap() {
+			continue
+		}
+		if strings.HasPrefix(string(f.Message().FullName()), wellKnownPrefix) {
+			continue
+		}
+		if found != nil {
+			return nil
+		}
+		found = f
+	}
+	return found
+}
+
+// intValue converts a protojson value (number, or string for 64-bit
+// integers and page tokens) to int.
+func intValue(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case float64:
+		return int(n), true
+	case string:
+		i, err := strconv.Atoi(n)
+		return i, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,358 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/getmockd/mockd/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const statefulTestProto = `
+syntax = "proto3";
+
+package crm;
+
+service UserService {
+  rpc CreateUser(CreateUserRequest) returns (User);
+  rpc GetUser(GetUserRequest) returns (GetUserResponse);
+  rpc UpdateUser(UpdateUserRequest) returns (User);
+  rpc DeleteUser(GetUserRequest) returns (DeleteUserResponse);
+  rpc ListUsers(ListUsersRequest) returns (ListUsersResponse);
+  rpc StreamUsers(ListUsersRequest) returns (stream User);
+  rpc Import(stream User) returns (User);
+}
+
+message User {
+  string id = 1;
+  string name = 2;
+  string role = 3;
+}
+
+message CreateUserRequest {
+  User user = 1;
+}
+
+message GetUserRequest {
+  string user_id = 1;
+}
+
+message GetUserResponse {
+  User user = 1;
+}
+
+message UpdateUserRequest {
+  string id = 1;
+  User user = 2;
+}
+
+message DeleteUserResponse {
+  bool success = 1;
+}
+
+message ListUsersRequest {
+  int32 page_size = 1;
+  string page_token = 2;
+  string role = 3;
+}
+
+message ListUsersResponse {
+  repeated User users = 1;
+  int32 total_size = 2;
+  string next_page_token = 3;
+}
+`
+
+// fakeGRPCStatefulExecutor is an in-memory table store recording every request.
+type fakeGRPCStatefulExecutor struct {
+	rows     map[string]map[string]interface{}
+	requests []*StatefulRequest
+	nextID   int
+}
+
+func (f *fakeGRPCStatefulExecutor) ExecuteStateful(_ context.Context, req *StatefulRequest) *StatefulResult {
+	f.requests = append(f.requests, req)
+	notFound := &StatefulResult{Error: &GRPCErrorConfig{
+		Code:    "NOT_FOUND",
+		Message: fmt.Sprintf("%s %q not found", req.Resource, req.ResourceID),
+		Details: map[string]interface{}{
+			"resource_info": map[string]interface{}{"resource_type": req.Resource, "resource_name": req.ResourceID},
+		},
+	}}
+
+	switch req.Action {
+	case StatefulActionCreate:
+		f.nextID++
+		item := map[string]interface{}{"id": fmt.Sprintf("u%d", f.nextID), "createdAt": "2026-01-01T00:00:00Z"}
+		for k, v := range req.Data {
+			item[k] = v
+		}
+		f.rows[item["id"].(string)] = item
+		return &StatefulResult{Item: item}
+	case StatefulActionGet:
+		item, ok := f.rows[req.ResourceID]
+		if !ok {
+			return notFound
+		}
+		return &StatefulResult{Item: item}
+	case StatefulActionUpdate:
+		item, ok := f.rows[req.ResourceID]
+		if !ok {
+			return notFound
+		}
+		for k, v := range req.Data {
+			item[k] = v
+		}
+		return &StatefulResult{Item: item}
+	case StatefulActionDelete:
+		item, ok := f.rows[req.ResourceID]
+		if !ok {
+			return notFound
+		}
+		delete(f.rows, req.ResourceID)
+		return &StatefulResult{Item: item}
+	case StatefulActionList:
+		ids := make([]string, 0, len(f.rows))
+		for id, row := range f.rows {
+			if role, ok := req.Filter.Filters["role"]; ok && row["role"] != role {
+				continue
+			}
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		total := len(ids)
+		start := min(req.Filter.Offset, total)
+		end := min(start+req.Filter.Limit, total)
+		items := make([]map[string]interface{}, 0, end-start)
+		for _, id := range ids[start:end] {
+			items = append(items, f.rows[id])
+		}
+		return &StatefulResult{Items: items, Meta: &StatefulListMeta{Total: total, Count: len(items), Offset: start, Limit: req.Filter.Limit}}
+	}
+	return &StatefulResult{}
+}
+
+func startStatefulTestServer(t *testing.T, methods map[string]MethodConfig) (*Server, *fakeGRPCStatefulExecutor, *grpc.ClientConn) {
+	t.Helper()
+	schema, err := ParseProtoContent(statefulTestProto)
+	require.NoError(t, err)
+
+	srv, err := NewServer(&GRPCConfig{
+		Port:     0,
+		Services: map[string]ServiceConfig{"crm.UserService": {Methods: methods}},
+	}, schema)
+	require.NoError(t, err)
+
+	fake := &fakeGRPCStatefulExecutor{rows: map[string]map[string]interface{}{}}
+	srv.SetStatefulExecutor(fake)
+
+	require.NoError(t, srv.Start(context.Background()))
+	t.Cleanup(func() { _ = srv.Stop(context.Background(), 5*time.Second) })
+
+	conn, err := grpc.NewClient(srv.Address(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return srv, fake, conn
+}
+
+// invokeStateful calls a unary method with a request built from fields.
+func invokeStateful(t *testing.T, srv *Server, conn *grpc.ClientConn, methodName string, fields map[string]interface{}) (*dynamicpb.Message, error) {
+	t.Helper()
+	method := srv.GetMethodDescriptor("crm.UserService", methodName)
+	require.NotNil(t, method)
+
+	req, err := buildStatefulMessage(method.Input(), fields)
+	require.NoError(t, err)
+	resp := dynamicpb.NewMessage(method.Output())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = conn.Invoke(ctx, "/crm.UserService/"+methodName, req, resp)
+	return resp, err
+}
+
+func getField(msg protoreflect.Message, name string) interface{} {
+	return msg.Get(msg.Descriptor().Fields().ByName(protoreflect.Name(name))).Interface()
+}
+
+func TestStatefulUnaryCRUD(t *testing.T) {
+	srv, fake, conn := startStatefulTestServer(t, map[string]MethodConfig{
+		"CreateUser": {StatefulBinding: &mock.StatefulBinding{Table: "users", Action: "create"}},
+		"GetUser":    {StatefulBinding: &mock.StatefulBinding{Table: "users", Action: "get"}},
+		"UpdateUser": {StatefulBinding: &mock.StatefulBinding{Table: "users", Action: "update"}},
+		"DeleteUser": {StatefulBinding: &mock.StatefulBinding{Table: "users", Action: "delete"}},
+	})
+
+	// CreateUser: the single message field is the payload; createdAt is dropped.
+	created, err := invokeStateful(t, srv, conn, "CreateUser", map[string]interface{}{
+		"user": map[string]interface{}{"name": "Alice", "role": "admin"},
+	})
+	require.NoError(t, err)
+	id := getField(created, "id").(string)
+	assert.NotEmpty(t, id)
+	assert.Equal(t, "Alice", getField(created, "name"))
+	assert.Equal(t, map[string]interface{}{"name": "Alice", "role": "admin"}, fake.requests[0].Data)
+
+	// GetUser: ID comes from user_id; the item is wrapped in GetUserResponse.user.
+	got, err := invokeStateful(t, srv, conn, "GetUser", map[string]interface{}{"userId": id})
+	require.NoError(t, err)
+	user := getField(got, "user").(protoreflect.Message)
+	assert.Equal(t, "Alice", getField(user, "name"))
+	assert.Equal(t, id, fake.requests[1].ResourceID)
+
+	// UpdateUser: ID from the id field, payload from the user field.
+	updated, err := invokeStateful(t, srv, conn, "UpdateUser", map[string]interface{}{
+		"id":   id,
+		"user": map[string]interface{}{"name": "Alicia"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Alicia", getField(updated, "name"))
+	assert.Equal(t, "admin", getField(updated, "role"))
+
+	// DeleteUser: success is set on the response.
+	deleted, err := invokeStateful(t, srv, conn, "DeleteUser", map[string]interface{}{"userId": id})
+	require.NoError(t, err)
+	assert.Equal(t, true, getField(deleted, "success"))
+	assert.Empty(t, fake.rows)
+}
+
+func TestStatefulUnaryNotFoundDetails(t *testing.T) {
+	srv, _, conn := startStatefulTestServer(t, map[string]MethodConfig{
+		"GetUser": {StatefulBinding: &mock.StatefulBinding{Table: "users", Action: "get"}},
+	})
+
+	_, err := invokeStateful(t, srv, conn, "GetUser", map[string]interface{}{"userId": "missing"})
+	require.Error(t, err)
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.NotFound, st.Code())
+	require.Len(t, st.Details(), 1)
+	info, ok := st.Details()[0].(*errdetails.ResourceInfo)
+	require.True(t, ok, "expected ResourceInfo, got %T", st.Details()[0])
+	assert.Equal(t, "users", info.ResourceType)
+	assert.Equal(t, "missing", info.ResourceName)
+}
+
+func TestStatefulUnaryList(t *testing.T) {
+	srv, fake, conn := startStatefulTestServer(t, map[string]MethodConfig{
+		"ListUsers": {StatefulBinding: &mock.StatefulBinding{Table: "users", Action: "list"}},
+	})
+	fake.rows["u1"] = map[string]interface{}{"id": "u1", "name": "Alice", "role": "admin"}
+	fake.rows["u2"] = map[string]interface{}{"id": "u2", "name": "Bob", "role": "user"}
+	fake.rows["u3"] = map[string]interface{}{"id": "u3", "name": "Carol", "role": "user"}
+
+	resp, err := invokeStateful(t, srv, conn, "ListUsers", map[string]interface{}{"pageSize": 1, "role": "user"})
+	require.NoError(t, err)
+
+	users := getField(resp, "users").(protoreflect.List)
+	require.Equal(t, 1, users.Len())
+	assert.Equal(t, "u2", getField(users.Get(0).Message(), "id"))
+	assert.Equal(t, int32(2), getField(resp, "total_size"))
+	assert.Equal(t, "1", getField(resp, "next_page_token"))
+
+	filter := fake.requests[0].Filter
+	assert.Equal(t, 1, filter.Limit)
+	assert.Equal(t, map[string]string{"role": "user"}, filter.Filters)
+
+	// Follow the page token to the last page.
+	resp, err = invokeStateful(t, srv, conn, "ListUsers", map[string]interface{}{"pageSize": 1, "role": "user", "pageToken": "1"})
+	require.NoError(t, err)
+	users = getField(resp, "users").(protoreflect.List)
+	require.Equal(t, 1, users.Len())
+	assert.Equal(t, "u3", getField(users.Get(0).Message(), "id"))
+	assert.Equal(t, "", getField(resp, "next_page_token"))
+}
+
+func TestStatefulServerStreamingList(t *testing.T) {
+	srv, fake, conn := startStatefulTestServer(t, map[string]MethodConfig{
+		"StreamUsers": {StatefulBinding: &mock.StatefulBinding{Table: "users", Action: "list"}},
+	})
+	fake.rows["u1"] = map[string]interface{}{"id": "u1", "name": "Alice"}
+	fake.rows["u2"] = map[string]interface{}{"id": "u2", "name": "Bob"}
+
+	method := srv.GetMethodDescriptor("crm.UserService", "StreamUsers")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, "/crm.UserService/StreamUsers")
+	require.NoError(t, err)
+	require.NoError(t, stream.SendMsg(dynamicpb.NewMessage(method.Input())))
+	require.NoError(t, stream.CloseSend())
+
+	var ids []string
+	for {
+		msg := dynamicpb.NewMessage(method.Output())
+		err := stream.RecvMsg(msg)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		ids = append(ids, getField(msg, "id").(string))
+	}
+	assert.Equal(t, []string{"u1", "u2"}, ids)
+}
+
+func TestStatefulClientStreamingUnsupported(t *testing.T) {
+	srv, _, conn := startStatefulTestServer(t, map[string]MethodConfig{
+		"Import": {StatefulBinding: &mock.StatefulBinding{Table: "users", Action: "create"}},
+	})
+
+	method := srv.GetMethodDescriptor("crm.UserService", "Import")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ClientStreams: true}, "/crm.UserService/Import")
+	require.NoError(t, err)
+	require.NoError(t, stream.SendMsg(dynamicpb.NewMessage(method.Input())))
+	require.NoError(t, stream.CloseSend())
+
+	err = stream.RecvMsg(dynamicpb.NewMessage(method.Output()))
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestStatefulNoExecutor(t *testing.T) {
+	srv, _, conn := startStatefulTestServer(t, map[string]MethodConfig{
+		"GetUser": {StatefulBinding: &mock.StatefulBinding{Table: "users", Action: "get"}},
+	})
+	srv.SetStatefulExecutor(nil)
+
+	_, err := invokeStateful(t, srv, conn, "GetUser", map[string]interface{}{"userId": "u1"})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestBuildStatefulRequest(t *testing.T) {
+	schema, err := ParseProtoContent(statefulTestProto)
+	require.NoError(t, err)
+	svc := schema.GetService("crm.UserService")
+
+	t.Run("list maps AIP ordering", func(t *testing.T) {
+		req := buildStatefulRequest(svc.GetMethod("ListUsers").GetInputDescriptor(), "ListUsers",
+			&mock.StatefulBinding{Table: "users", Action: "list"},
+			map[string]interface{}{"orderBy": "name DESC", "role": "admin"})
+		assert.Equal(t, "name", req.Filter.Sort)
+		assert.Equal(t, "desc", req.Filter.Order)
+		assert.Equal(t, defaultListLimit, req.Filter.Limit)
+		assert.Equal(t, map[string]string{"role": "admin"}, req.Filter.Filters)
+	})
+
+	t.Run("custom defaults operation to method name", func(t *testing.T) {
+		req := buildStatefulRequest(svc.GetMethod("CreateUser").GetInputDescriptor(), "CreateUser",
+			&mock.StatefulBinding{Action: "custom"},
+			map[string]interface{}{"user": map[string]interface{}{"name": "Dan"}})
+		assert.Equal(t, "CreateUser", req.OperationName)
+		assert.Equal(t, map[string]interface{}{"user": map[string]interface{}{"name": "Dan"}}, req.Data)
+	})
+}
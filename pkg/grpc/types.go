@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+
+	"github.com/getmockd/mockd/pkg/mock"
 )
 
 // GRPCConfig represents a gRPC endpoint configuration for the mock server.
@@ -95,6 +97,12 @@ type MethodConfig struct {
 	// passes. An unconditioned (empty/nil Match) variant acts as a default and
 	// should be ordered last. Nested Variants on a variant are ignored.
 	Variants []MethodConfig `json:"variants,omitempty" yaml:"variants,omitempty"`
+
+	// StatefulBinding binds this method to a stateful table+action.
+	// When set (and the server has a StatefulExecutor), the request message
+	// is mapped to table fields through the method's descriptors instead of
+	// returning Response/Responses.
+	StatefulBinding *mock.StatefulBinding `json:"statefulBinding,omitempty" yaml:"statefulBinding,omitempty"`
 }
 
 // MethodMatch defines conditions for matching incoming gRPC requests.
@@ -1,6 +1,10 @@
 package mcp
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/getmockd/mockd/pkg/cli"
+)
 
 // =============================================================================
 // Chaos Engineering Handlers
@@ -225,3 +229,82 @@ func handleManageCircuitBreaker(args map[string]interface{}, session *MCPSession
 		return ToolResultError("invalid action: " + action + " (must be 'trip' or 'reset')"), nil
 	}
 }
+
+// handleManageChaosProfile lists, creates, updates and deletes user-defined
+// chaos profiles. Uses a single tool with an `action` parameter.
+func handleManageChaosProfile(args map[string]interface{}, session *MCPSession, server *Server) (*ToolResult, error) {
+	client := session.GetAdminClient()
+	if client == nil {
+		return ToolResultError("admin client not available"), nil
+	}
+
+	action := getString(args, "action", "")
+	name := getString(args, "name", "")
+	if action == "" {
+		return ToolResultError("action is required (list, get, create, update, delete)"), nil
+	}
+	if action != "list" && name == "" {
+		return ToolResultError("name is required for action=" + action), nil
+	}
+
+	switch action {
+	case "list":
+		profiles, err := client.ListChaosProfiles()
+		if err != nil {
+			//nolint:nilerr // MCP spec: tool errors are returned in result content, not as JSON-RPC errors
+			return ToolResultError("failed to list chaos profiles: " + adminError(err, session.GetAdminURL())), nil
+		}
+		return ToolResultJSON(map[string]interface{}{
+			"profiles": profiles,
+			"count":    len(profiles),
+		})
+
+	case "get":
+		profile, err := client.GetChaosProfile(name)
+		if err != nil {
+			//nolint:nilerr // MCP spec: tool errors are returned in result content, not as JSON-RPC errors
+			if isConnectionError(err) {
+				return ToolResultError("failed to get chaos profile: " + adminError(err, session.GetAdminURL())), nil
+			}
+			return ToolResultError("chaos profile not found: " + name), nil
+		}
+		return ToolResultJSON(profile)
+
+	case "create", "update":
+		chaosConfig := getMap(args, "config")
+		if chaosConfig == nil {
+			return ToolResultError("config is required for action=" + action), nil
+		}
+		profile := map[string]interface{}{
+			"name":        name,
+			"description": getString(args, "description", ""),
+			"config":      chaosConfig,
+		}
+
+		var saved *cli.ChaosProfileInfo
+		var err error
+		if action == "create" {
+			saved, err = client.CreateChaosProfile(profile)
+		} else {
+			saved, err = client.UpdateChaosProfile(name, profile)
+		}
+		if err != nil {
+			//nolint:nilerr // MCP spec: tool errors are returned in result content, not as JSON-RPC errors
+			return ToolResultError("failed to " + action + " chaos profile: " + adminError(err, session.GetAdminURL())), nil
+		}
+		return ToolResultJSON(saved)
+
+	case "delete":
+		if err := client.DeleteChaosProfile(name); err != nil {
+			//nolint:nilerr // MCP spec: tool errors are returned in result content, not as JSON-RPC errors
+			return ToolResultError("failed to delete chaos profile: " + adminError(err, session.GetAdminURL())), nil
+		}
+		return ToolResultJSON(map[string]interface{}{
+			"deleted": true,
+			"name":    name,
+		})
+
+	default:
+		return ToolResultError("unknown action: " + action + ". Use list, get, create, update, or delete"), nil
+	}
+}
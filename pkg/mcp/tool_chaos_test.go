@@ -1299,3 +1299,68 @@ func TestHandleManageCircuitBreaker_NoClient(t *testing.T) {
 
 // Ensure json import is used (compiler satisfaction).
 var _ = json.Marshal
+
+// =============================================================================
+// handleManageChaosProfile Tests
+// =============================================================================
+
+func TestHandleManageChaosProfile_Create(t *testing.T) {
+	t.Parallel()
+
+	var captured map[string]interface{}
+	client := &mockAdminClient{
+		createChaosProfileFn: func(profile map[string]interface{}) (*cli.ChaosProfileInfo, error) {
+			captured = profile
+			return &cli.ChaosProfileInfo{Name: "partner-degraded"}, nil
+		},
+	}
+
+	session := newTestSession(client)
+	server := newTestServer(client)
+
+	args := map[string]interface{}{
+		"action":      "create",
+		"name":        "partner-degraded",
+		"description": "Partner API at its SLO limits",
+		"config": map[string]interface{}{
+			"latency": map[string]interface{}{"min": "300ms", "max": "1200ms", "probability": 1.0},
+		},
+	}
+	result, err := handleManageChaosProfile(args, session, server)
+	if err != nil {
+		t.Fatalf("handleManageChaosProfile() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got error: %s", resultText(t, result))
+	}
+
+	if captured["name"] != "partner-degraded" || captured["description"] != "Partner API at its SLO limits" {
+		t.Errorf("profile sent to admin = %v", captured)
+	}
+	if _, ok := captured["config"].(map[string]interface{})["latency"]; !ok {
+		t.Errorf("profile config not passed through: %v", captured["config"])
+	}
+}
+
+func TestHandleManageChaosProfile_Validation(t *testing.T) {
+	t.Parallel()
+
+	client := &mockAdminClient{}
+	session := newTestSession(client)
+	server := newTestServer(client)
+
+	for _, args := range []map[string]interface{}{
+		{},
+		{"action": "delete"},
+		{"action": "create", "name": "partner-degraded"},
+		{"action": "rename", "name": "partner-degraded"},
+	} {
+		result, err := handleManageChaosProfile(args, session, server)
+		if err != nil {
+			t.Fatalf("handleManageChaosProfile(%v) error = %v", args, err)
+		}
+		if !result.IsError {
+			t.Errorf("handleManageChaosProfile(%v) succeeded, want an error result", args)
+		}
+	}
+}
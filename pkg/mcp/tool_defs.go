@@ -45,13 +45,14 @@ func allToolDefinitions() []ToolDefinition {
 		defClearRequestLogs,
 
 		// =====================================================================
-		// Chaos Engineering (5 tools)
+		// Chaos Engineering (6 tools)
 		// =====================================================================
 		defGetChaosConfig,
 		defSetChaosConfig,
 		defResetChaosStats,
 		defGetStatefulFaults,
 		defManageCircuitBreaker,
+		defManageChaosProfile,
 
 		// =====================================================================
 		// Verification (3 tools)
@@ -541,12 +542,11 @@ var defSetChaosConfig = ToolDefinition{
 			},
 			"profile": map[string]interface{}{
 				"type":        "string",
-				"description": "Named chaos profile",
-				"enum":        []string{"slow-api", "degraded", "flaky", "offline", "timeout", "rate-limited", "mobile-3g", "satellite", "dns-flaky", "overloaded"},
+				"description": "Named chaos profile: a built-in one (slow-api, degraded, flaky, offline, timeout, rate-limited, mobile-3g, satellite, dns-flaky, overloaded) or a user-defined one created with manage_chaos_profile",
 			},
 			"rules": map[string]interface{}{
 				"type":        "array",
-				"description": "Raw chaos rules for advanced fault types. Each rule has probability (0-1), optional pathPattern, optional methods, and faults array. Fault types: latency, error, slow_body, corrupt_body, partial_response, connection_reset, circuit_breaker, retry_after, progressive_degradation, chunked_dribble. Each fault has type, probability (0-1), and config object.",
+				"description": "Raw chaos rules for advanced fault types. Each rule has probability (0-1), optional protocol (http, grpc, websocket, sse or mqtt; gRPC rules match pathPattern against package.Service/Method, MQTT rules against the topic), optional pathPattern, optional methods, and faults array. Fault types: latency, error, slow_body, corrupt_body, partial_response, connection_reset, circuit_breaker, retry_after, progressive_degradation, chunked_dribble; for gRPC rules: latency, error, timeout, grpc_status, grpc_resource_exhausted, grpc_stream_abort, grpc_drop_trailers, grpc_deadline; for websocket, sse and mqtt rules: message_drop, message_duplicate, message_reorder, message_delay, message_close, message_throttle. Each fault has type, probability (0-1), and config object.",
 				"items": map[string]interface{}{
 					"type": "object",
 					"properties": map[string]interface{}{
@@ -554,15 +554,24 @@ var defSetChaosConfig = ToolDefinition{
 							"type":        "number",
 							"description": "Rule match probability 0.0-1.0",
 						},
+						"protocol": map[string]interface{}{
+							"type":        "string",
+							"description": "Traffic the rule applies to (default http)",
+							"enum":        []string{"http", "grpc", "websocket", "sse", "mqtt"},
+						},
 						"pathPattern": map[string]interface{}{
 							"type":        "string",
-							"description": "Regex pattern to match request paths (empty = all paths)",
+							"description": "Regex pattern to match request paths, or package.Service/Method for gRPC rules (empty = all paths)",
 						},
 						"methods": map[string]interface{}{
 							"type":        "array",
 							"description": "HTTP methods to match (empty = all methods)",
 							"items":       map[string]interface{}{"type": "string"},
 						},
+						"match": map[string]interface{}{
+							"type":        "object",
+							"description": "Narrow an HTTP rule to specific callers and mocks; every condition set must hold. Keys: headers and query (name -> regex), clientIPs (IPs or CIDRs), clientCN (regex on the mTLS client certificate CN), jwtClaims (bearer token claim -> regex, signature not verified), mockIds, mockName (regex), tags (mock tags, any of), workspaces (workspace IDs, \"default\" for the default workspace)",
+						},
 						"faults": map[string]interface{}{
 							"type":        "array",
 							"description": "Faults to inject when rule matches",
@@ -633,6 +642,34 @@ var defManageCircuitBreaker = ToolDefinition{
 	},
 }
 
+var defManageChaosProfile = ToolDefinition{
+	Name:        "manage_chaos_profile",
+	Description: "Manage user-defined chaos profiles: named chaos configurations applied by name with set_chaos_config like the built-in profiles. They are persisted and included in config exports. Use 'list' to see built-in and custom profiles, 'get' for details, 'create' or 'update' to save a profile, and 'delete' to remove one. Built-in profiles cannot be changed.",
+	InputSchema: map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"action": map[string]interface{}{
+				"type":        "string",
+				"description": "Profile action",
+				"enum":        []string{"list", "get", "create", "update", "delete"},
+			},
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Profile name (required for get, create, update, delete); letters, digits, '.', '_' and '-'",
+			},
+			"description": map[string]interface{}{
+				"type":        "string",
+				"description": "Short profile description (create, update)",
+			},
+			"config": map[string]interface{}{
+				"type":        "object",
+				"description": "Chaos configuration the profile applies (required for create, update), in the shape returned by get_chaos_config: latency {min, max, probability}, errorRate {probability, statusCodes, defaultCode}, bandwidth {bytesPerSecond, probability} and rules",
+			},
+		},
+		"required": []string{"action"},
+	},
+}
+
 // =============================================================================
 // Verification Definitions
 // =============================================================================
@@ -3,6 +3,7 @@ package mcp
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"testing"
 
 	apitypes "github.com/getmockd/mockd/pkg/api/types"
@@ -45,6 +46,9 @@ type mockAdminClient struct {
 	listChaosProfilesFn   func() ([]cli.ChaosProfileInfo, error)
 	getChaosProfileFn     func(name string) (*cli.ChaosProfileInfo, error)
 	applyChaosProfileFn   func(name string) error
+	createChaosProfileFn  func(profile map[string]interface{}) (*cli.ChaosProfileInfo, error)
+	updateChaosProfileFn  func(name string, profile map[string]interface{}) (*cli.ChaosProfileInfo, error)
+	deleteChaosProfileFn  func(name string) error
 	getChaosStatsFn       func() (map[string]interface{}, error)
 	resetChaosStatsFn     func() error
 	getStatefulFaultsFn   func() (map[string]interface{}, error)
@@ -236,6 +240,59 @@ func (m *mockAdminClient) ApplyChaosProfile(name string) error {
 	return nil
 }
 
+func (m *mockAdminClient) CreateChaosProfile(profile map[string]interface{}) (*cli.ChaosProfileInfo, error) {
+	if m.createChaosProfileFn != nil {
+		return m.createChaosProfileFn(profile)
+	}
+	return nil, nil
+}
+
+func (m *mockAdminClient) UpdateChaosProfile(name string, profile map[string]interface{}) (*cli.ChaosProfileInfo, error) {
+	if m.updateChaosProfileFn != nil {
+		return m.updateChaosProfileFn(name, profile)
+	}
+	return nil, nil
+}
+
+func (m *mockAdminClient) DeleteChaosProfile(name string) error {
+	if m.deleteChaosProfileFn != nil {
+		return m.deleteChaosProfileFn(name)
+	}
+	return nil
+}
+
+func (m *mockAdminClient) StartChaosExperiment(map[string]interface{}) (map[string]interface{}, error) {
+	return nil, nil
+}
+
+func (m *mockAdminClient) ListChaosExperiments() ([]map[string]interface{}, error) {
+	return nil, nil
+}
+
+func (m *mockAdminClient) GetChaosExperiment(string) (map[string]interface{}, error) {
+	return nil, nil
+}
+
+func (m *mockAdminClient) AbortChaosExperiment(string) (map[string]interface{}, error) {
+	return nil, nil
+}
+
+func (m *mockAdminClient) ListTCPProxies() ([]map[string]interface{}, error) {
+	return nil, nil
+}
+
+func (m *mockAdminClient) CreateTCPProxy(map[string]interface{}) (map[string]interface{}, error) {
+	return nil, nil
+}
+
+func (m *mockAdminClient) DeleteTCPProxy(string) error {
+	return nil
+}
+
+func (m *mockAdminClient) SetTCPToxics(string, []interface{}) (map[string]interface{}, error) {
+	return nil, nil
+}
+
 func (m *mockAdminClient) GetChaosStats() (map[string]interface{}, error) {
 	if m.getChaosStatsFn != nil {
 		return m.getChaosStatsFn()
@@ -243,6 +300,30 @@ func (m *mockAdminClient) GetChaosStats() (map[string]interface{}, error) {
 	return nil, nil
 }
 
+func (m *mockAdminClient) GetChaosFaultLog() (map[string]interface{}, error) {
+	return map[string]interface{}{}, nil
+}
+
+func (m *mockAdminClient) ResetChaosFaultLog() error {
+	return nil
+}
+
+func (m *mockAdminClient) GetShadowConfig() (map[string]interface{}, error) {
+	return map[string]interface{}{}, nil
+}
+
+func (m *mockAdminClient) SetShadowConfig(cfg map[string]interface{}) error {
+	return nil
+}
+
+func (m *mockAdminClient) GetDriftReport(mockID string) (map[string]interface{}, error) {
+	return map[string]interface{}{}, nil
+}
+
+func (m *mockAdminClient) ResetDrift() error {
+	return nil
+}
+
 func (m *mockAdminClient) ResetChaosStats() error {
 	if m.resetChaosStatsFn != nil {
 		return m.resetChaosStatsFn()
@@ -321,6 +402,14 @@ func (m *mockAdminClient) ResetStatefulResource(workspaceID string, name string)
 	return nil
 }
 
+func (m *mockAdminClient) ImportStatefulItems(_ string, _ string, _ io.Reader, _ cli.StatefulImportOptions) (*apitypes.StatefulImportResponse, error) {
+	return &apitypes.StatefulImportResponse{}, nil
+}
+
+func (m *mockAdminClient) ExportStatefulItems(_ string, _ string, _ string, _ io.Writer) error {
+	return nil
+}
+
 func (m *mockAdminClient) DeleteStatefulResource(workspaceID string, name string) error {
 	if m.deleteStatefulResourceFn != nil {
 		return m.deleteStatefulResourceFn(workspaceID, name)
@@ -57,6 +57,7 @@ func (r *ToolRegistry) registerBuiltinTools() {
 		"reset_chaos_stats":      handleResetChaosStats,
 		"get_stateful_faults":    handleGetStatefulFaults,
 		"manage_circuit_breaker": handleManageCircuitBreaker,
+		"manage_chaos_profile":   handleManageChaosProfile,
 
 		// Verification
 		"verify_mock":          handleVerifyMock,
@@ -77,6 +77,15 @@ var (
 	// MatchMissesTotal counts requests that didn't match any mock.
 	MatchMissesTotal *Counter
 
+	// ShadowComparisonsTotal counts shadow mode comparisons of mock responses
+	// with the upstream.
+	// Labels: mock_id, result (match, drift, error)
+	ShadowComparisonsTotal *Counter
+
+	// ShadowDifferencesTotal counts the differences found by shadow mode.
+	// Labels: mock_id, kind (status, header, body, missing, extra, type)
+	ShadowDifferencesTotal *Counter
+
 	// ErrorsTotal counts errors by type.
 	// Labels: type (timeout, connection, validation, internal)
 	ErrorsTotal *Counter
@@ -182,6 +191,19 @@ func Init() *Registry {
 			"Number of requests that did not match any mock",
 		)
 
+		// Shadow mode metrics
+		ShadowComparisonsTotal = defaultRegistry.NewCounter(
+			"mockd_shadow_comparisons_total",
+			"Number of shadow comparisons of mock responses with the upstream",
+			"mock_id", "result",
+		)
+
+		ShadowDifferencesTotal = defaultRegistry.NewCounter(
+			"mockd_shadow_differences_total",
+			"Number of differences between mock and upstream responses found by shadow mode",
+			"mock_id", "kind",
+		)
+
 		// Error metrics
 		ErrorsTotal = defaultRegistry.NewCounter(
 			"mockd_errors_total",
@@ -239,6 +261,8 @@ func Reset() {
 	ProxyRequestsTotal = nil
 	MatchHitsTotal = nil
 	MatchMissesTotal = nil
+	ShadowComparisonsTotal = nil
+	ShadowDifferencesTotal = nil
 	ErrorsTotal = nil
 	UptimeSeconds = nil
 	PortInfo = nil
@@ -19,6 +19,8 @@
 //   - mockd_active_connections: Gauge for active stateful connections (labels: protocol)
 //   - mockd_mocks_total: Gauge for configured mocks (labels: type)
 //   - mockd_mocks_enabled: Gauge for enabled mocks (labels: type)
+//   - mockd_shadow_comparisons_total: Counter for shadow mode comparisons (labels: mock_id, result)
+//   - mockd_shadow_differences_total: Counter for shadow mode differences (labels: mock_id, kind)
 //
 // # Label Conventions
 //
@@ -429,11 +429,43 @@ func TestSSEConfig_Validate_MutualExclusivity(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "only statefulStream - ok",
+			config: SSEConfig{
+				StatefulStream: &StatefulStreamConfig{Table: "orders"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "events and statefulStream - error",
+			config: SSEConfig{
+				Events:         []SSEEventDef{event},
+				StatefulStream: &StatefulStreamConfig{Table: "orders"},
+			},
+			wantErr:   true,
+			errSubstr: "mutually exclusive",
+		},
+		{
+			name: "statefulStream without table - error",
+			config: SSEConfig{
+				StatefulStream: &StatefulStreamConfig{},
+			},
+			wantErr:   true,
+			errSubstr: "table is required",
+		},
+		{
+			name: "statefulStream with unknown event - error",
+			config: SSEConfig{
+				StatefulStream: &StatefulStreamConfig{Table: "orders", Events: []string{"touched"}},
+			},
+			wantErr:   true,
+			errSubstr: "must be one of created, updated, deleted, reset",
+		},
 		{
 			name:      "none specified - error",
 			config:    SSEConfig{},
 			wantErr:   true,
-			errSubstr: "one of events, generator, or template is required",
+			errSubstr: "one of events, generator, template, or statefulStream is required",
 		},
 	}
 
@@ -1645,6 +1677,86 @@ func TestMock_Validate_GraphQL(t *testing.T) {
 			wantErr:   true,
 			errSubstr: "path cannot contain '..'",
 		},
+		{
+			name: "stateful resolvers are valid without response",
+			mock: Mock{
+				ID:   "gql-stateful",
+				Type: TypeGraphQL,
+				GraphQL: &GraphQLSpec{
+					Path:   "/graphql",
+					Schema: "type Query { user(id: ID!): User }\ntype User { id: ID orders: [Order] }\ntype Order { id: ID }",
+					Resolvers: map[string]ResolverConfig{
+						"Query.user":  {StatefulBinding: &GraphQLStatefulBinding{Table: "users", Action: "get"}},
+						"User.orders": {StatefulBinding: &GraphQLStatefulBinding{Table: "orders", Action: "list", ForeignKey: "userId"}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "stateful resolver missing table",
+			mock: Mock{
+				ID:   "gql-stateful-notable",
+				Type: TypeGraphQL,
+				GraphQL: &GraphQLSpec{
+					Path:   "/graphql",
+					Schema: "type Query { user: String }",
+					Resolvers: map[string]ResolverConfig{
+						"Query.user": {StatefulBinding: &GraphQLStatefulBinding{Action: "get"}},
+					},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "table is required when statefulBinding is set",
+		},
+		{
+			name: "stateful resolver invalid action",
+			mock: Mock{
+				ID:   "gql-stateful-badaction",
+				Type: TypeGraphQL,
+				GraphQL: &GraphQLSpec{
+					Path:   "/graphql",
+					Schema: "type Query { user: String }",
+					Resolvers: map[string]ResolverConfig{
+						"Query.user": {StatefulBinding: &GraphQLStatefulBinding{Table: "users", Action: "bogus"}},
+					},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "invalid stateful action",
+		},
+		{
+			name: "stateful resolver custom without operation",
+			mock: Mock{
+				ID:   "gql-stateful-noop",
+				Type: TypeGraphQL,
+				GraphQL: &GraphQLSpec{
+					Path:   "/graphql",
+					Schema: "type Mutation { transfer: String }",
+					Resolvers: map[string]ResolverConfig{
+						"Mutation.transfer": {StatefulBinding: &GraphQLStatefulBinding{Action: "custom"}},
+					},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "operation is required when action is custom",
+		},
+		{
+			name: "stateful resolver foreignKey on get",
+			mock: Mock{
+				ID:   "gql-stateful-fk",
+				Type: TypeGraphQL,
+				GraphQL: &GraphQLSpec{
+					Path:   "/graphql",
+					Schema: "type Query { user: String }",
+					Resolvers: map[string]ResolverConfig{
+						"User.orders": {StatefulBinding: &GraphQLStatefulBinding{Table: "orders", Action: "get", ForeignKey: "userId"}},
+					},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "foreignKey is only supported for list",
+		},
 	}
 
 	for _, tt := range tests {
@@ -1893,6 +2005,65 @@ func TestMock_Validate_GRPC(t *testing.T) {
 			wantErr:   true,
 			errSubstr: "path cannot contain '..'",
 		},
+		{
+			name: "stateful methods are valid without response",
+			mock: Mock{
+				ID:   "grpc-stateful",
+				Type: TypeGRPC,
+				GRPC: &GRPCSpec{
+					Port:      50051,
+					ProtoFile: "service.proto",
+					Services: map[string]ServiceConfig{
+						"crm.UserService": {Methods: map[string]MethodConfig{
+							"GetUser":  {StatefulBinding: &StatefulBinding{Table: "users", Action: "get"}},
+							"Transfer": {StatefulBinding: &StatefulBinding{Action: "custom"}},
+						}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "stateful method missing table",
+			mock: Mock{
+				ID:   "grpc-stateful-notable",
+				Type: TypeGRPC,
+				GRPC: &GRPCSpec{
+					Port:      50051,
+					ProtoFile: "service.proto",
+					Services: map[string]ServiceConfig{
+						"crm.UserService": {Methods: map[string]MethodConfig{
+							"GetUser": {StatefulBinding: &StatefulBinding{Action: "get"}},
+						}},
+					},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "table is required when statefulBinding is set",
+		},
+		{
+			name: "stateful variant invalid action",
+			mock: Mock{
+				ID:   "grpc-stateful-badvariant",
+				Type: TypeGRPC,
+				GRPC: &GRPCSpec{
+					Port:      50051,
+					ProtoFile: "service.proto",
+					Services: map[string]ServiceConfig{
+						"crm.UserService": {Methods: map[string]MethodConfig{
+							"GetUser": {
+								Response: map[string]any{"id": "1"},
+								Variants: []MethodConfig{
+									{StatefulBinding: &StatefulBinding{Table: "users", Action: "bogus"}},
+								},
+							},
+						}},
+					},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "invalid stateful action",
+		},
 	}
 
 	for _, tt := range tests {
@@ -44,6 +44,9 @@ type Mock struct {
 	// Description is an optional longer description
 	Description string `json:"description,omitempty" yaml:"description,omitempty"`
 
+	// Tags are free-form labels, e.g. for targeting chaos rules at a group of mocks
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+
 	// Enabled indicates whether this mock is active
 	Enabled *bool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
 
@@ -294,6 +297,46 @@ type StatefulBinding struct {
 	Response *StatefulBindingResponse `json:"-" yaml:"-"` // not serialized — resolved at load time
 }
 
+// Stateful stream event names accepted in StatefulStreamConfig.Events.
+const (
+	StatefulStreamCreated = "created"
+	StatefulStreamUpdated = "updated"
+	StatefulStreamDeleted = "deleted"
+	StatefulStreamReset   = "reset"
+)
+
+// StatefulStreamConfig binds an SSE or WebSocket endpoint to the change feed
+// of a stateful table, so every create, update and delete is pushed to
+// connected clients.
+type StatefulStreamConfig struct {
+	// Table is the name of the stateful table to watch.
+	Table string `json:"table" yaml:"table"`
+	// Events limits which changes are sent: created, updated, deleted, reset.
+	// Defaults to all of them.
+	Events []string `json:"events,omitempty" yaml:"events,omitempty"`
+	// Filter is an expression evaluated per change; only changes for which it
+	// returns true are sent. Available variables: event, item, previous.
+	Filter string `json:"filter,omitempty" yaml:"filter,omitempty"`
+	// Envelope controls how each change is framed on the wire.
+	Envelope *StatefulStreamEnvelope `json:"envelope,omitempty" yaml:"envelope,omitempty"`
+	// Buffer is the per-connection queue size (default: 64). Changes that
+	// arrive while the queue is full are dropped.
+	Buffer int `json:"buffer,omitempty" yaml:"buffer,omitempty"`
+}
+
+// StatefulStreamEnvelope configures the framing of streamed changes.
+type StatefulStreamEnvelope struct {
+	// EventType is the event name pattern. Supported placeholders are
+	// {table}, {singular} and {action}. Default: "{singular}.{action}",
+	// which produces names like "order.updated".
+	EventType string `json:"eventType,omitempty" yaml:"eventType,omitempty"`
+	// Raw sends only the item as the payload instead of the full envelope.
+	Raw bool `json:"raw,omitempty" yaml:"raw,omitempty"`
+	// Fields renames envelope fields (type, table, id, data, previous,
+	// timestamp, sequence). Mapping a field to "" removes it.
+	Fields map[string]string `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
 // StatefulBindingResponse holds the merged response transform for a binding.
 // This is a runtime-only type, not part of the config YAML.
 type StatefulBindingResponse struct {
@@ -474,6 +517,9 @@ type SSEConfig struct {
 	Resume         SSEResumeConfig     `json:"resume" yaml:"resume"`
 	Template       string              `json:"template,omitempty" yaml:"template,omitempty"`
 	TemplateParams map[string]any      `json:"templateParams,omitempty" yaml:"templateParams,omitempty"`
+	// StatefulStream pushes live changes from a stateful table instead of
+	// scripted events.
+	StatefulStream *StatefulStreamConfig `json:"statefulStream,omitempty" yaml:"statefulStream,omitempty"`
 }
 
 // SSEEventDef defines a single SSE event.
@@ -595,6 +641,8 @@ type WebSocketSpec struct {
 	IdleTimeout        string             `json:"idleTimeout,omitempty" yaml:"idleTimeout,omitempty"`
 	MaxConnections     int                `json:"maxConnections,omitempty" yaml:"maxConnections,omitempty"`
 	EchoMode           *bool              `json:"echoMode,omitempty" yaml:"echoMode,omitempty"`
+	// StatefulStream pushes live changes from a stateful table to every connection.
+	StatefulStream *StatefulStreamConfig `json:"statefulStream,omitempty" yaml:"statefulStream,omitempty"`
 }
 
 // WSMatcherConfig defines a WebSocket message matcher.
@@ -659,11 +707,54 @@ type GraphQLSpec struct {
 }
 
 // ResolverConfig configures how a GraphQL field is resolved.
+//
+// As with gRPC methods, the Resolvers map holds one ResolverConfig per field,
+// so additional argument-match variants for the same field live in Variants.
+// The top-level config is evaluated first, then Variants in order; the first
+// whose Match passes wins. An unconditioned variant acts as a default and
+// should be ordered last.
 type ResolverConfig struct {
 	Response any                 `json:"response,omitempty" yaml:"response,omitempty"`
 	Delay    string              `json:"delay,omitempty" yaml:"delay,omitempty"`
 	Match    *ResolverMatch      `json:"match,omitempty" yaml:"match,omitempty"`
 	Error    *GraphQLErrorConfig `json:"error,omitempty" yaml:"error,omitempty"`
+
+	// Variants holds additional match variants for the same field. Nested
+	// Variants on a variant are ignored.
+	Variants []ResolverConfig `json:"variants,omitempty" yaml:"variants,omitempty"`
+
+	// StatefulBinding resolves the field from a stateful table instead of
+	// returning Response.
+	StatefulBinding *GraphQLStatefulBinding `json:"statefulBinding,omitempty" yaml:"statefulBinding,omitempty"`
+}
+
+// GraphQLStatefulBinding binds a GraphQL field resolver to a stateful table
+// action. Root fields (Query.user, Mutation.createUser) take their ID, filters
+// and payload from arguments; fields on object types (User.orders) resolve
+// relationships from the parent object.
+type GraphQLStatefulBinding struct {
+	// Table is the name of the stateful table.
+	Table string `json:"table" yaml:"table"`
+	// Action is one of get, list, create, update, patch, delete, custom.
+	Action string `json:"action" yaml:"action"`
+	// Operation names the custom operation to execute when Action is "custom".
+	Operation string `json:"operation,omitempty" yaml:"operation,omitempty"`
+	// IDArg is the argument holding the item ID for get, update, patch and
+	// delete (default: "id").
+	IDArg string `json:"idArg,omitempty" yaml:"idArg,omitempty"`
+	// InputArg is the argument holding the payload for create, update, patch
+	// and custom (default: "input"). When the field has no such argument, the
+	// remaining arguments form the payload.
+	InputArg string `json:"inputArg,omitempty" yaml:"inputArg,omitempty"`
+	// Filter maps table fields to argument names for list. When empty, every
+	// argument except limit, offset, sort and order is an equality filter.
+	Filter map[string]string `json:"filter,omitempty" yaml:"filter,omitempty"`
+	// ParentField is the parent object's field holding the item ID, for
+	// nested get bindings such as Order.customer (parentField: customerId).
+	ParentField string `json:"parentField,omitempty" yaml:"parentField,omitempty"`
+	// ForeignKey is the table field that references the parent's id, for
+	// nested list bindings such as User.orders (foreignKey: userId).
+	ForeignKey string `json:"foreignKey,omitempty" yaml:"foreignKey,omitempty"`
 }
 
 // ResolverMatch specifies matching conditions for a resolver.
@@ -747,6 +838,11 @@ type MethodConfig struct {
 	// Response/Error. Nested Variants on a variant are ignored — only one
 	// level is meaningful.
 	Variants []MethodConfig `json:"variants,omitempty" yaml:"variants,omitempty"`
+
+	// StatefulBinding binds this method to a stateful table+action.
+	// When set, the method reads and writes the table instead of returning
+	// Response/Responses. Supported on unary and server-streaming methods.
+	StatefulBinding *StatefulBinding `json:"statefulBinding,omitempty" yaml:"statefulBinding,omitempty"`
 }
 
 // MethodMatch defines conditions for matching incoming gRPC requests.
@@ -148,6 +148,12 @@ func (m *Mock) validateWebSocket() error {
 		return &ValidationError{Field: "websocket.path", Message: "path must start with /"}
 	}
 
+	if m.WebSocket.StatefulStream != nil {
+		if err := m.WebSocket.StatefulStream.Validate("websocket.statefulStream"); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -358,10 +364,11 @@ func (r *HTTPResponse) Validate() error {
 
 // Validate checks if the SSEConfig is valid.
 func (s *SSEConfig) Validate() error {
-	// Either events, generator, or template must be specified
+	// Either events, generator, template, or statefulStream must be specified
 	hasEvents := len(s.Events) > 0
 	hasGenerator := s.Generator != nil
 	hasTemplate := s.Template != ""
+	hasStream := s.StatefulStream != nil
 
 	// Count how many data sources are specified
 	count := 0
@@ -374,12 +381,21 @@ func (s *SSEConfig) Validate() error {
 	if hasTemplate {
 		count++
 	}
+	if hasStream {
+		count++
+	}
 
 	if count == 0 {
-		return &ValidationError{Field: "sse", Message: "one of events, generator, or template is required"}
+		return &ValidationError{Field: "sse", Message: "one of events, generator, template, or statefulStream is required"}
 	}
 	if count > 1 {
-		return &ValidationError{Field: "sse", Message: "events, generator, and template are mutually exclusive"}
+		return &ValidationError{Field: "sse", Message: "events, generator, template, and statefulStream are mutually exclusive"}
+	}
+
+	if hasStream {
+		if err := s.StatefulStream.Validate("sse.statefulStream"); err != nil {
+			return err
+		}
 	}
 
 	// Validate each event if present
@@ -515,6 +531,54 @@ func (m *Mock) validateGraphQL() error {
 		}
 	}
 
+	for path, resolver := range m.GraphQL.Resolvers {
+		if resolver.StatefulBinding != nil {
+			if err := resolver.StatefulBinding.Validate(fmt.Sprintf("graphql.resolvers[%s].statefulBinding", path)); err != nil {
+				return err
+			}
+		}
+		for i, variant := range resolver.Variants {
+			if variant.StatefulBinding != nil {
+				if err := variant.StatefulBinding.Validate(fmt.Sprintf("graphql.resolvers[%s].variants[%d].statefulBinding", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// validStatefulActions lists the table actions accepted by stateful bindings.
+var validStatefulActions = map[string]bool{
+	"get": true, "list": true, "create": true,
+	"update": true, "patch": true, "delete": true, "custom": true,
+}
+
+// Validate checks a GraphQL resolver's stateful binding. field is the
+// binding's path, used in error messages.
+func (b *GraphQLStatefulBinding) Validate(field string) error {
+	if b.Table == "" && b.Action != "custom" {
+		return &ValidationError{Field: field + ".table", Message: "table is required when statefulBinding is set"}
+	}
+	if b.Action == "" {
+		return &ValidationError{Field: field + ".action", Message: "action is required when statefulBinding is set"}
+	}
+	if !validStatefulActions[b.Action] {
+		return &ValidationError{
+			Field:   field + ".action",
+			Message: fmt.Sprintf("invalid stateful action %q; valid values: get, list, create, update, patch, delete, custom", b.Action),
+		}
+	}
+	if b.Action == "custom" && b.Operation == "" {
+		return &ValidationError{Field: field + ".operation", Message: "operation is required when action is custom"}
+	}
+	if b.ForeignKey != "" && b.Action != "list" {
+		return &ValidationError{Field: field + ".foreignKey", Message: "foreignKey is only supported for list"}
+	}
+	if b.ParentField != "" && b.Action != "get" {
+		return &ValidationError{Field: field + ".parentField", Message: "parentField is only supported for get"}
+	}
 	return nil
 }
 
@@ -570,6 +634,42 @@ func (m *Mock) validateGRPC() error {
 		}
 	}
 
+	// Validate stateful bindings on methods and their match variants
+	for svcName, svc := range m.GRPC.Services {
+		for methodName, method := range svc.Methods {
+			field := fmt.Sprintf("grpc.services[%s].methods[%s].statefulBinding", svcName, methodName)
+			if err := validateGRPCStatefulBinding(field, method.StatefulBinding); err != nil {
+				return err
+			}
+			for i, variant := range method.Variants {
+				if err := validateGRPCStatefulBinding(fmt.Sprintf("grpc.services[%s].methods[%s].variants[%d].statefulBinding", svcName, methodName, i), variant.StatefulBinding); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateGRPCStatefulBinding checks a gRPC method's stateful binding. Custom
+// actions may omit the table; the operation defaults to the method name.
+func validateGRPCStatefulBinding(field string, b *StatefulBinding) error {
+	if b == nil {
+		return nil
+	}
+	if b.Table == "" && b.Action != "custom" {
+		return &ValidationError{Field: field + ".table", Message: "table is required when statefulBinding is set"}
+	}
+	if b.Action == "" {
+		return &ValidationError{Field: field + ".action", Message: "action is required when statefulBinding is set"}
+	}
+	if !validStatefulActions[b.Action] {
+		return &ValidationError{
+			Field:   field + ".action",
+			Message: fmt.Sprintf("invalid stateful action %q; valid values: get, list, create, update, patch, delete, custom", b.Action),
+		}
+	}
 	return nil
 }
 
@@ -603,11 +703,6 @@ func (m *Mock) validateSOAP() error {
 	}
 
 	// Validate operations if present
-	validStatefulActions := map[string]bool{
-		"get": true, "list": true, "create": true,
-		"update": true, "patch": true, "delete": true, "custom": true,
-	}
-
 	for name, op := range m.SOAP.Operations {
 		if op.StatefulBinding != nil {
 			if op.StatefulBinding.Table == "" {
@@ -726,3 +821,25 @@ func (m *Mock) validateOAuth() error {
 
 	return nil
 }
+
+// Validate checks if the StatefulStreamConfig is valid. field is the path
+// used in error messages (e.g. "sse.statefulStream").
+func (s *StatefulStreamConfig) Validate(field string) error {
+	if s.Table == "" {
+		return &ValidationError{Field: field + ".table", Message: "table is required"}
+	}
+	for i, ev := range s.Events {
+		switch ev {
+		case StatefulStreamCreated, StatefulStreamUpdated, StatefulStreamDeleted, StatefulStreamReset:
+		default:
+			return &ValidationError{
+				Field:   fmt.Sprintf("%s.events[%d]", field, i),
+				Message: "must be one of created, updated, deleted, reset",
+			}
+		}
+	}
+	if s.Buffer < 0 {
+		return &ValidationError{Field: field + ".buffer", Message: "must be >= 0"}
+	}
+	return nil
+}
@@ -74,6 +74,8 @@ type Broker struct {
 	// stopping is set to 1 during shutdown to prevent hook callbacks from
 	// acquiring the broker mutex, which would deadlock with server.Close().
 	stopping atomic.Int32
+	// msgChaos holds per-topic message chaos state.
+	msgChaos topicChaosState
 }
 
 // markMockResponseTopic marks a topic as currently being published by a mock response.
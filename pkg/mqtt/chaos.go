@@ -0,0 +1,190 @@
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/getmockd/mockd/pkg/chaos"
+	"github.com/mochi-mqtt/server/v2/packets"
+)
+
+// ChaosSource returns the chaos injector currently in effect, or nil when
+// chaos is off. It is consulted for every published message.
+type ChaosSource func() *chaos.Injector
+
+// topicChaosState holds the per-topic message chaos of a broker. Each topic is
+// one message stream; the states are dropped when the injector changes.
+type topicChaosState struct {
+	mu       sync.Mutex
+	source   ChaosSource
+	injector *chaos.Injector
+	topics   map[string]*chaos.MessageChaos
+
+	// republishes counts chaos re-publishes (duplicates, reordered messages)
+	// per topic that have not yet passed through OnPublish.
+	republishes map[string]int
+}
+
+// SetChaosSource sets where the broker gets message chaos for its topics from.
+func (b *Broker) SetChaosSource(source ChaosSource) {
+	b.msgChaos.mu.Lock()
+	defer b.msgChaos.mu.Unlock()
+	b.msgChaos.source = source
+	b.msgChaos.injector = nil
+	b.msgChaos.topics = nil
+}
+
+// topicMessageChaos returns the message chaos for topic, or nil when no mqtt
+// chaos rule applies.
+func (b *Broker) topicMessageChaos(topic string) *chaos.MessageChaos {
+	b.msgChaos.mu.Lock()
+	defer b.msgChaos.mu.Unlock()
+
+	if b.msgChaos.source == nil {
+		return nil
+	}
+	injector := b.msgChaos.source()
+	if injector != b.msgChaos.injector {
+		b.msgChaos.injector = injector
+		b.msgChaos.topics = nil
+	}
+	if injector == nil {
+		return nil
+	}
+	if mc, ok := b.msgChaos.topics[topic]; ok {
+		return mc
+	}
+	if b.msgChaos.topics == nil {
+		b.msgChaos.topics = make(map[string]*chaos.MessageChaos)
+	}
+	mc := injector.NewMessageChaos(chaos.ProtocolMQTT, topic)
+	b.msgChaos.topics[topic] = mc
+	return mc
+}
+
+// resetTopicChaos starts a fresh message stream for topic, after a
+// message_close fault has disconnected its subscribers.
+func (b *Broker) resetTopicChaos(topic string) {
+	b.msgChaos.mu.Lock()
+	defer b.msgChaos.mu.Unlock()
+	delete(b.msgChaos.topics, topic)
+}
+
+// takeChaosRepublish reports whether an inline publish to topic is a chaos
+// re-publish, consuming it if so.
+func (b *Broker) takeChaosRepublish(topic string) bool {
+	b.msgChaos.mu.Lock()
+	defer b.msgChaos.mu.Unlock()
+	if b.msgChaos.republishes[topic] == 0 {
+		return false
+	}
+	b.msgChaos.republishes[topic]--
+	if b.msgChaos.republishes[topic] == 0 {
+		delete(b.msgChaos.republishes, topic)
+	}
+	return true
+}
+
+// chaosRepublish publishes a copy of pk that skips OnPublish processing.
+func (b *Broker) chaosRepublish(pk packets.Packet) {
+	b.msgChaos.mu.Lock()
+	if b.msgChaos.republishes == nil {
+		b.msgChaos.republishes = make(map[string]int)
+	}
+	b.msgChaos.republishes[pk.TopicName]++
+	b.msgChaos.mu.Unlock()
+
+	if err := b.server.Publish(pk.TopicName, pk.Payload, pk.FixedHeader.Retain, pk.FixedHeader.Qos); err != nil {
+		b.takeChaosRepublish(pk.TopicName)
+		b.log.Error("failed to re-publish chaos message", "topic", pk.TopicName, "error", err)
+	}
+}
+
+// chaosPublish tracks whether a publish was delivered in place by its own
+// OnPublish call, and whether it has been delivered at all. Deliveries after
+// OnPublish has returned are re-published.
+type chaosPublish struct {
+	mu        sync.Mutex
+	returned  bool
+	inPlace   bool
+	delivered bool
+}
+
+// applyMessageChaos routes a publish through its topic's message chaos and
+// returns the error OnPublish should return: nil to deliver the packet, or
+// packets.CodeSuccessIgnore to acknowledge it without delivering it.
+// onDeliver runs once, when the message is first delivered; it never runs
+// for a dropped message or one cut off by a close fault.
+func (b *Broker) applyMessageChaos(pk packets.Packet, onDeliver func()) error {
+	mc := b.topicMessageChaos(pk.TopicName)
+	if mc == nil {
+		onDeliver()
+		return nil
+	}
+
+	state := &chaosPublish{}
+	err := mc.Send(context.Background(), len(pk.Payload), func() error {
+		state.mu.Lock()
+		first := !state.delivered
+		state.delivered = true
+		if !state.returned && !state.inPlace {
+			state.inPlace = true
+			state.mu.Unlock()
+			return nil
+		}
+		state.mu.Unlock()
+		go func() {
+			if first {
+				onDeliver()
+			}
+			b.chaosRepublish(pk)
+		}()
+		return nil
+	})
+
+	var closeErr *chaos.CloseError
+	if errors.As(err, &closeErr) {
+		b.resetTopicChaos(pk.TopicName)
+		go b.disconnectSubscribers(pk.TopicName, closeErr)
+		return packets.CodeSuccessIgnore
+	}
+
+	state.mu.Lock()
+	state.returned = true
+	inPlace := state.inPlace
+	state.mu.Unlock()
+	if !inPlace {
+		return packets.CodeSuccessIgnore
+	}
+	onDeliver()
+	return nil
+}
+
+// disconnectSubscribers disconnects every client subscribed to topic with the
+// reason code and reason of a message_close fault (default 0x80).
+func (b *Broker) disconnectSubscribers(topic string, closeErr *chaos.CloseError) {
+	code := packets.ErrUnspecifiedError
+	if closeErr.Code != 0 {
+		code = packets.Code{Code: byte(closeErr.Code), Reason: closeErr.Reason}
+	} else {
+		code.Reason = closeErr.Reason
+	}
+
+	subs := b.server.Topics.Subscribers(topic)
+	ids := make(map[string]struct{}, len(subs.Subscriptions))
+	for id := range subs.Subscriptions {
+		ids[id] = struct{}{}
+	}
+	for _, group := range subs.Shared {
+		for id := range group {
+			ids[id] = struct{}{}
+		}
+	}
+
+	for id := range ids {
+		if cl, ok := b.server.Clients.Get(id); ok && !cl.Net.Inline {
+			_ = b.server.DisconnectClient(cl, code)
+		}
+	}
+}
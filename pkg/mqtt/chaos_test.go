@@ -0,0 +1,150 @@
+package mqtt
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	mqttclient "github.com/eclipse/paho.mqtt.golang"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/getmockd/mockd/pkg/chaos"
+)
+
+// startChaosTestBroker starts a broker whose message chaos comes from the
+// given rules and connects a client subscribed to sensors/#.
+func startChaosTestBroker(t *testing.T, rules ...chaos.ChaosRule) (*Broker, *chaosTestSubscriber) {
+	t.Helper()
+	broker, err := NewBroker(&MQTTConfig{Port: 0, Enabled: true})
+	require.NoError(t, err)
+	require.NoError(t, broker.Start(context.Background()))
+	t.Cleanup(func() { _ = broker.Stop(context.Background(), 5*time.Second) })
+
+	injector, err := chaos.NewInjector(&chaos.ChaosConfig{Enabled: true, Rules: rules})
+	require.NoError(t, err)
+	broker.SetChaosSource(func() *chaos.Injector { return injector })
+
+	sub := &chaosTestSubscriber{lost: make(chan struct{})}
+	opts := mqttclient.NewClientOptions()
+	opts.AddBroker(fmt.Sprintf("tcp://localhost:%d", broker.Port()))
+	opts.SetClientID("chaos-subscriber")
+	opts.SetAutoReconnect(false)
+	opts.SetConnectionLostHandler(func(mqttclient.Client, error) { close(sub.lost) })
+	client := mqttclient.NewClient(opts)
+	token := client.Connect()
+	require.True(t, token.WaitTimeout(5*time.Second))
+	require.NoError(t, token.Error())
+	t.Cleanup(func() { client.Disconnect(100) })
+
+	token = client.Subscribe("sensors/#", 0, func(_ mqttclient.Client, msg mqttclient.Message) {
+		sub.mu.Lock()
+		sub.payloads = append(sub.payloads, string(msg.Payload()))
+		sub.mu.Unlock()
+	})
+	require.True(t, token.WaitTimeout(5*time.Second))
+	require.NoError(t, token.Error())
+	return broker, sub
+}
+
+type chaosTestSubscriber struct {
+	mu       sync.Mutex
+	payloads []string
+	lost     chan struct{}
+}
+
+func (s *chaosTestSubscriber) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.payloads...)
+}
+
+func messageRule(topic string, faultType chaos.FaultType, cfg map[string]interface{}) chaos.ChaosRule {
+	return chaos.ChaosRule{
+		Protocol:    chaos.ProtocolMQTT,
+		PathPattern: topic,
+		Faults:      []chaos.FaultConfig{{Type: faultType, Probability: 1, Config: cfg}},
+		Probability: 1,
+	}
+}
+
+func TestMessageChaos_DropAndDuplicatePerTopic(t *testing.T) {
+	broker, sub := startChaosTestBroker(t,
+		messageRule("^sensors/drop$", chaos.FaultMessageDrop, nil),
+		messageRule("^sensors/dup$", chaos.FaultMessageDuplicate, nil),
+	)
+
+	require.NoError(t, broker.Publish("sensors/drop", []byte("lost"), 0, false))
+	require.NoError(t, broker.Publish("sensors/dup", []byte("twice"), 0, false))
+	require.NoError(t, broker.Publish("sensors/plain", []byte("once"), 0, false))
+
+	assert.Eventually(t, func() bool { return len(sub.received()) == 3 }, 2*time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	assert.ElementsMatch(t, []string{"twice", "twice", "once"}, sub.received())
+}
+
+func TestMessageChaos_CloseDisconnectsSubscribers(t *testing.T) {
+	broker, sub := startChaosTestBroker(t,
+		messageRule("^sensors/temp$", chaos.FaultMessageClose, map[string]interface{}{"afterMessages": 1, "reason": "chaos"}),
+	)
+
+	require.NoError(t, broker.Publish("sensors/temp", []byte("first"), 0, false))
+	require.Eventually(t, func() bool { return len(sub.received()) == 1 }, 2*time.Second, 10*time.Millisecond)
+	require.NoError(t, broker.Publish("sensors/temp", []byte("second"), 0, false))
+
+	select {
+	case <-sub.lost:
+	case <-time.After(2 * time.Second):
+		t.Fatal("subscriber was not disconnected")
+	}
+	assert.Equal(t, []string{"first"}, sub.received())
+}
+
+type chaosTestRecordings struct {
+	mu     sync.Mutex
+	topics []string
+}
+
+func (r *chaosTestRecordings) Add(data MQTTRecordingData) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.topics = append(r.topics, data.Topic)
+	return nil
+}
+
+func (r *chaosTestRecordings) recorded() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.topics...)
+}
+
+func TestMessageChaos_DroppedMessagesAreNotProcessed(t *testing.T) {
+	broker, sub := startChaosTestBroker(t,
+		messageRule("^sensors/drop$", chaos.FaultMessageDrop, nil),
+	)
+	recordings := &chaosTestRecordings{}
+	broker.SetRecordingStore(recordings)
+	broker.EnableRecording()
+
+	var mu sync.Mutex
+	var notified []string
+	broker.Subscribe("sensors/#", func(topic string, _ []byte) {
+		mu.Lock()
+		notified = append(notified, topic)
+		mu.Unlock()
+	})
+
+	require.NoError(t, broker.Publish("sensors/drop", []byte("lost"), 0, false))
+	require.NoError(t, broker.Publish("sensors/plain", []byte("kept"), 0, false))
+
+	require.Eventually(t, func() bool { return len(sub.received()) == 1 }, 2*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool { return len(recordings.recorded()) == 1 }, 2*time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+
+	assert.Equal(t, []string{"sensors/plain"}, recordings.recorded())
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"sensors/plain"}, notified)
+}
@@ -254,15 +254,32 @@ func (h *MessageHook) OnPublish(cl *mqtt.Client, pk packets.Packet) (packets.Pac
 		}
 	}
 
+	// Chaos re-publishes (duplicates, reordered messages) were processed
+	// when the message was first delivered; only deliver them.
+	if cl.Net.Inline && h.broker.takeChaosRepublish(pk.TopicName) {
+		return pk, nil
+	}
+
 	startTime := time.Now()
 	topic := pk.TopicName
-	payload := pk.Payload
 
 	// Record metrics (deferred to capture duration)
 	defer func() {
 		recordMQTTMetrics("PUBLISH", topic, time.Since(startTime))
 	}()
 
+	// Message chaos decides whether and when subscribers get the message.
+	// Recording, logging and mock responses run once the message is
+	// delivered, so a dropped message leaves no trace beyond metrics.
+	return pk, h.broker.applyMessageChaos(pk, func() { h.handleDelivered(cl, pk) })
+}
+
+// handleDelivered records and logs a delivered publish, notifies internal
+// subscribers and test panels, and triggers mock responses and topic handlers.
+func (h *MessageHook) handleDelivered(cl *mqtt.Client, pk packets.Packet) {
+	topic := pk.TopicName
+	payload := pk.Payload
+
 	// Record message if recording is enabled
 	h.broker.mu.RLock()
 	recordingEnabled := h.broker.recordingEnabled
@@ -342,8 +359,6 @@ func (h *MessageHook) OnPublish(cl *mqtt.Client, pk packets.Packet) (packets.Pac
 			}
 		}
 	}
-
-	return pk, nil
 }
 
 // createPublishLogEntry creates a log entry for a publish event
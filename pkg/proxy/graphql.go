@@ -0,0 +1,91 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/getmockd/mockd/pkg/graphql"
+	"github.com/getmockd/mockd/pkg/recording"
+)
+
+// introspectionTimeout bounds the introspection request sent while recording.
+const introspectionTimeout = 10 * time.Second
+
+// captureGraphQLSchema fetches the schema of a GraphQL endpoint through
+// introspection the first time a call to it is recorded and stores it on
+// that recording, so conversion can use the real schema instead of one
+// inferred from traffic. Each endpoint is tried once; failures (for example
+// servers with introspection disabled) are logged and ignored.
+func (p *Proxy) captureGraphQLSchema(r *http.Request, rec *recording.Recording) {
+	if !p.introspectGraphQL || !recording.IsGraphQLRecording(rec) {
+		return
+	}
+
+	endpoint := graphqlEndpoint(r, rec)
+	p.graphqlMu.Lock()
+	attempted := p.graphqlEndpoints[endpoint]
+	p.graphqlEndpoints[endpoint] = true
+	p.graphqlMu.Unlock()
+	if attempted {
+		return
+	}
+
+	sdl, err := p.introspect(r, endpoint)
+	if err != nil {
+		p.log("GraphQL introspection of %s failed: %v", endpoint, err)
+		return
+	}
+	rec.GraphQLSchema = sdl
+	p.log("Captured GraphQL schema from %s", endpoint)
+}
+
+// introspect sends the introspection query to endpoint with the recorded
+// request's headers, so authenticated APIs accept it.
+func (p *Proxy) introspect(r *http.Request, endpoint string) (string, error) {
+	body, err := json.Marshal(graphql.GraphQLRequest{Query: graphql.IntrospectionQuery})
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), introspectionTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body)) //nolint:gosec // G704 — proxy: introspecting the recorded endpoint is intentional
+	if err != nil {
+		return "", err
+	}
+	copyHeaders(req.Header, r.Header)
+	removeHopByHopHeaders(req.Header)
+	req.Header.Del("Content-Length")
+	req.Header.Del("Accept-Encoding")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req) //nolint:gosec // G704 — proxy: introspecting the recorded endpoint is intentional
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, DefaultMaxBodySize))
+	if err != nil {
+		return "", err
+	}
+	return graphql.SchemaFromIntrospection(data)
+}
+
+// graphqlEndpoint returns the absolute URL of the recorded GraphQL endpoint.
+func graphqlEndpoint(r *http.Request, rec *recording.Recording) string {
+	if r.URL.IsAbs() {
+		return r.URL.Scheme + "://" + r.URL.Host + r.URL.Path
+	}
+	scheme := rec.Request.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	return scheme + "://" + r.Host + r.URL.Path
+}
@@ -0,0 +1,111 @@
+package proxy
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/getmockd/mockd/pkg/graphql"
+	"github.com/getmockd/mockd/pkg/recording"
+)
+
+func TestProxy_CapturesGraphQLSchema(t *testing.T) {
+	schema, err := graphql.ParseSchema(`type Query { user(id: ID!): User } type User { id: ID! name: String }`)
+	if err != nil {
+		t.Fatalf("ParseSchema() error = %v", err)
+	}
+	cfg := &graphql.GraphQLConfig{
+		Introspection: true,
+		Resolvers: map[string]graphql.ResolverConfig{
+			"Query.user": {Response: map[string]any{"id": "1", "name": "Ann"}},
+		},
+	}
+	handler := graphql.NewHandler(graphql.NewExecutor(schema, cfg), cfg)
+
+	var introspections atomic.Int32
+	var missingAuth atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			missingAuth.Add(1)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "__schema") {
+			introspections.Add(1)
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(upstream.Close)
+
+	target, err := ParseUpstream(upstream.URL)
+	if err != nil {
+		t.Fatalf("ParseUpstream() error = %v", err)
+	}
+	store := recording.NewStore()
+	store.CreateSession("graphql", nil)
+	p := New(Options{Mode: ModeRecord, Upstream: target, Store: store, GraphQLIntrospection: true})
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "http://mockd.local/graphql",
+			strings.NewReader(`{"query":"{ user(id: \"1\") { id name } }"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer token")
+		rec := httptest.NewRecorder()
+		p.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+		}
+	}
+
+	if got := introspections.Load(); got != 1 {
+		t.Errorf("introspection requests = %d, want 1", got)
+	}
+	if got := missingAuth.Load(); got != 0 {
+		t.Errorf("%d upstream requests lacked the recorded Authorization header", got)
+	}
+
+	recordings, total := p.Store().ListRecordings(recording.RecordingFilter{})
+	if total != 2 {
+		t.Fatalf("recorded %d requests, want 2", total)
+	}
+	withSchema := 0
+	for _, r := range recordings {
+		if r.GraphQLSchema != "" {
+			withSchema++
+			if !strings.Contains(r.GraphQLSchema, "user(id: ID!): User") {
+				t.Errorf("GraphQLSchema = %q", r.GraphQLSchema)
+			}
+		}
+	}
+	if withSchema != 1 {
+		t.Errorf("%d recordings carry the schema, want 1", withSchema)
+	}
+}
+
+func TestProxy_GraphQLIntrospectionDisabled(t *testing.T) {
+	var requests atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"ping":true}}`))
+	}))
+	t.Cleanup(upstream.Close)
+
+	target, err := ParseUpstream(upstream.URL)
+	if err != nil {
+		t.Fatalf("ParseUpstream() error = %v", err)
+	}
+	p := New(Options{Mode: ModeRecord, Upstream: target})
+
+	req := httptest.NewRequest(http.MethodPost, "http://mockd.local/graphql", strings.NewReader(`{"query":"{ ping }"}`))
+	req.Header.Set("Content-Type", "application/json")
+	p.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("upstream requests = %d, want only the proxied call", got)
+	}
+}
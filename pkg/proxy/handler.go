@@ -51,6 +51,21 @@ func (p *Proxy) handleHTTP(w http.ResponseWriter, r *http.Request) {
 	// Log the request
 	p.log("[%s] %s %s", r.Method, r.Host, r.URL.Path)
 
+	decision := p.decide(r, reqBody)
+	if decision.replay != nil {
+		resp := replayResponse(decision.replay, r)
+		copyHeaders(w.Header(), resp.Header)
+		w.WriteHeader(resp.StatusCode)
+		_, _ = w.Write(decision.replay.Response.Body)
+		p.log("Replayed: %s %s (%d) from %s", r.Method, r.URL.Path, resp.StatusCode, decision.replay.ID)
+		return
+	}
+	if decision.fail {
+		p.log("Replay miss: %s %s", r.Method, r.URL.Path)
+		http.Error(w, missMessage(r), http.StatusBadGateway)
+		return
+	}
+
 	// Forward the request
 	resp, err := p.forwardRequest(r)
 	if err != nil {
@@ -70,35 +85,8 @@ func (p *Proxy) handleHTTP(w http.ResponseWriter, r *http.Request) {
 
 	duration := time.Since(startTime)
 
-	// Check if we should record this request
-	p.mu.RLock()
-	mode := p.mode
-	filter := p.filter
-	p.mu.RUnlock()
-
-	if mode == ModeRecord {
-		// Check filter
-		shouldRecord := true
-		if filter != nil {
-			shouldRecord = filter.ShouldRecord(r.Host, r.URL.Path)
-		}
-
-		if shouldRecord {
-			// Create recording
-			rec := recording.NewRecording("")
-			rec.CaptureRequest(r, reqBody)
-			rec.CaptureResponse(resp, respBody, duration)
-
-			// Persist to disk (primary storage for CLI usage)
-			p.persistToDisk(rec)
-
-			// Also add to in-memory store (for admin API usage)
-			if err := p.store.AddRecording(rec); err != nil {
-				p.log("Error storing recording: %v", err)
-			}
-
-			p.log("Recorded: %s %s (%d) [%v]", r.Method, r.URL.Path, resp.StatusCode, duration)
-		}
+	if decision.record {
+		p.record(r, reqBody, resp, respBody, duration)
 	}
 
 	// Copy response to client
@@ -109,6 +97,28 @@ func (p *Proxy) handleHTTP(w http.ResponseWriter, r *http.Request) {
 	p.log("Response: %d %s [%v]", resp.StatusCode, resp.Status, duration)
 }
 
+// record redacts a forwarded exchange and stores it on disk and in the store.
+func (p *Proxy) record(r *http.Request, reqBody []byte, resp *http.Response, respBody []byte, duration time.Duration) {
+	rec := recording.NewRecording("")
+	rec.CaptureRequest(r, reqBody)
+	if r.URL.Scheme != "" {
+		rec.Request.Scheme = r.URL.Scheme
+	}
+	rec.CaptureResponse(resp, respBody, duration)
+	p.captureGraphQLSchema(r, rec)
+	p.redactor.RedactRecording(rec)
+
+	// Persist to disk (primary storage for CLI usage)
+	p.persistToDisk(rec)
+
+	// Also add to in-memory store (for admin API usage)
+	if err := p.store.AddRecording(rec); err != nil {
+		p.log("Error storing recording: %v", err)
+	}
+
+	p.log("Recorded: %s %s%s (%d) [%v]", r.Method, r.Host, r.URL.Path, resp.StatusCode, duration)
+}
+
 // forwardRequest forwards an HTTP request to the target server and returns the response.
 func (p *Proxy) forwardRequest(r *http.Request) (*http.Response, error) {
 	// Construct the target URL
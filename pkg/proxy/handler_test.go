@@ -1,8 +1,12 @@
 package proxy
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -70,3 +74,56 @@ func TestHandleHTTPForwardsProxyRequest(t *testing.T) {
 		t.Fatalf("unexpected body: %q", rec.Body.String())
 	}
 }
+
+// TestHandleHTTPRedactsBeforePersisting verifies that secrets never reach the
+// disk or the store, while the client still receives the real response.
+func TestHandleHTTPRedactsBeforePersisting(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"token":"live-secret"}`))
+	}))
+	defer target.Close()
+
+	redactor, err := recording.NewRedactor(&recording.RedactionConfig{Rules: []recording.RedactionRule{
+		{Header: "Authorization", Action: recording.RedactMask},
+		{JSONPath: "$.token", Action: recording.RedactHash},
+	}})
+	if err != nil {
+		t.Fatalf("NewRedactor() error = %v", err)
+	}
+
+	store := recording.NewStore()
+	store.CreateSession("t", nil)
+	dir := t.TempDir()
+	p := New(Options{Mode: ModeRecord, Store: store, DiskDir: dir, Redactor: redactor})
+
+	req := httptest.NewRequest(http.MethodGet, target.URL+"/token", nil)
+	req.Header.Set("Authorization", "Bearer sk_live_123")
+	rec := httptest.NewRecorder()
+	p.handleHTTP(rec, req)
+
+	if rec.Body.String() != `{"token":"live-secret"}` {
+		t.Fatalf("client received %q, want the unredacted response", rec.Body.String())
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*", "rec_*.json"))
+	if len(files) != 1 {
+		t.Fatalf("found %d recordings on disk, want 1", len(files))
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var onDisk recording.Recording
+	if err := json.Unmarshal(data, &onDisk); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "sk_live_123") || strings.Contains(string(onDisk.Response.Body), "live-secret") {
+		t.Errorf("recording on disk holds secrets: %s", data)
+	}
+
+	recordings, _ := store.ListRecordings(recording.RecordingFilter{})
+	if len(recordings) != 1 || recordings[0].Request.Headers.Get("Authorization") != recording.DefaultRedactMask {
+		t.Errorf("stored recording was not redacted")
+	}
+}
@@ -11,8 +11,6 @@ import (
 	"strings"
 	"sync"
 	"time"
-
-	"github.com/getmockd/mockd/pkg/recording"
 )
 
 // handleConnect handles HTTPS CONNECT requests for TLS interception.
@@ -133,6 +131,21 @@ func (p *Proxy) handleHTTPSRequest(clientConn net.Conn, r *http.Request, fullHos
 	// Log the request
 	p.log("[HTTPS] %s %s%s", r.Method, r.Host, r.URL.Path)
 
+	decision := p.decide(r, reqBody)
+	if decision.replay != nil {
+		if err := replayResponse(decision.replay, r).Write(clientConn); err != nil {
+			p.log("Error writing replayed response: %v", err)
+			return
+		}
+		p.log("Replayed HTTPS: %s %s (%d) from %s", r.Method, r.URL.Path, decision.replay.Response.StatusCode, decision.replay.ID)
+		return
+	}
+	if decision.fail {
+		p.log("Replay miss: %s %s%s", r.Method, r.Host, r.URL.Path)
+		writeHTTPError(clientConn, http.StatusBadGateway, missMessage(r))
+		return
+	}
+
 	// Connect to target server
 	targetHost := fullHost
 	if !strings.Contains(targetHost, ":") {
@@ -176,35 +189,8 @@ func (p *Proxy) handleHTTPSRequest(clientConn net.Conn, r *http.Request, fullHos
 
 	duration := time.Since(startTime)
 
-	// Check if we should record this request
-	p.mu.RLock()
-	mode := p.mode
-	filter := p.filter
-	p.mu.RUnlock()
-
-	if mode == ModeRecord {
-		// Check filter
-		shouldRecord := true
-		if filter != nil {
-			shouldRecord = filter.ShouldRecord(r.Host, r.URL.Path)
-		}
-
-		if shouldRecord {
-			// Create recording
-			rec := recording.NewRecording("")
-			rec.CaptureRequest(r, reqBody)
-			rec.CaptureResponse(resp, respBody, duration)
-
-			// Persist to disk (primary storage for CLI usage)
-			p.persistToDisk(rec)
-
-			// Also add to in-memory store (for admin API usage)
-			if err := p.store.AddRecording(rec); err != nil {
-				p.log("Error storing HTTPS recording: %v", err)
-			}
-
-			p.log("Recorded HTTPS: %s %s (%d) [%v]", r.Method, r.URL.Path, resp.StatusCode, duration)
-		}
+	if decision.record {
+		p.record(r, reqBody, resp, respBody, duration)
 	}
 
 	// Write response back to client with body restored
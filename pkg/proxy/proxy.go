@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/getmockd/mockd/pkg/recording"
@@ -21,12 +23,21 @@ const (
 	ModeRecord Mode = "record"
 	// ModePassthrough forwards traffic without storing.
 	ModePassthrough Mode = "passthrough"
+	// ModeReplay answers requests from the store's recordings; OnMiss decides
+	// what happens to requests no recording matches.
+	ModeReplay Mode = "replay"
 )
 
 // Options configures proxy behavior.
 type Options struct {
 	// Mode is the initial operating mode
 	Mode Mode
+	// OnMiss is the replay miss policy (default MissFail)
+	OnMiss MissPolicy
+	// Upstream, when set, makes the proxy a reverse proxy that forwards every
+	// request to this base URL (see ParseUpstream) instead of a forward proxy
+	// that clients must be configured to use
+	Upstream *url.URL
 	// Filter is the traffic filter configuration
 	Filter *FilterConfig
 	// Store is the recording store for captured traffic (in-memory, used by admin API)
@@ -35,6 +46,13 @@ type Options struct {
 	// When set, each recording is written as a JSON file organized by host.
 	// This is the primary persistence mechanism for CLI proxy usage.
 	DiskDir string
+	// Redactor, when set, redacts every recording before it is written to
+	// disk or added to the store
+	Redactor *recording.Redactor
+	// GraphQLIntrospection, when set, fetches the schema of each GraphQL
+	// endpoint through introspection the first time a call to it is
+	// recorded, and stores it on that recording
+	GraphQLIntrospection bool
 	// CAManager handles certificate generation for HTTPS
 	CAManager *CAManager
 	// Logger for traffic logging (nil = no logging)
@@ -43,14 +61,24 @@ type Options struct {
 
 // Proxy is an HTTP/HTTPS MITM proxy server.
 type Proxy struct {
-	mu      sync.RWMutex
-	mode    Mode
-	filter  *FilterConfig
-	store   *recording.Store
-	diskDir string
-	ca      *CAManager
-	logger  *log.Logger
-	client  *http.Client // Shared HTTP client for connection pooling
+	mu       sync.RWMutex
+	mode     Mode
+	onMiss   MissPolicy
+	upstream *url.URL
+	filter   *FilterConfig
+	store    *recording.Store
+	diskDir  string
+	redactor *recording.Redactor
+	ca       *CAManager
+	logger   *log.Logger
+	client   *http.Client // Shared HTTP client for connection pooling
+
+	introspectGraphQL bool
+	graphqlMu         sync.Mutex
+	graphqlEndpoints  map[string]bool // endpoints introspection was attempted for
+
+	replayHits   atomic.Int64
+	replayMisses atomic.Int64
 }
 
 // New creates a new Proxy with the given options.
@@ -60,6 +88,11 @@ func New(opts Options) *Proxy {
 		mode = ModeRecord
 	}
 
+	onMiss := opts.OnMiss
+	if onMiss == "" {
+		onMiss = MissFail
+	}
+
 	filter := opts.Filter
 	if filter == nil {
 		filter = NewFilterConfig()
@@ -71,12 +104,19 @@ func New(opts Options) *Proxy {
 	}
 
 	return &Proxy{
-		mode:    mode,
-		filter:  filter,
-		store:   store,
-		diskDir: opts.DiskDir,
-		ca:      opts.CAManager,
-		logger:  opts.Logger,
+		mode:     mode,
+		onMiss:   onMiss,
+		upstream: opts.Upstream,
+		filter:   filter,
+		store:    store,
+		diskDir:  opts.DiskDir,
+		redactor: opts.Redactor,
+		ca:       opts.CAManager,
+		logger:   opts.Logger,
+
+		introspectGraphQL: opts.GraphQLIntrospection,
+		graphqlEndpoints:  make(map[string]bool),
+
 		client: &http.Client{
 			CheckRedirect: func(req *http.Request, via []*http.Request) error {
 				return http.ErrUseLastResponse // Don't follow redirects
@@ -108,6 +148,28 @@ func (p *Proxy) SetMode(mode Mode) {
 	}
 }
 
+// OnMiss returns the replay miss policy.
+func (p *Proxy) OnMiss() MissPolicy {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.onMiss
+}
+
+// SetOnMiss changes the replay miss policy at runtime.
+func (p *Proxy) SetOnMiss(policy MissPolicy) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onMiss = policy
+	if p.logger != nil {
+		p.logger.Printf("Proxy replay miss policy changed to: %s", policy)
+	}
+}
+
+// ReplayStats returns the replay hit and miss counts.
+func (p *Proxy) ReplayStats() ReplayStats {
+	return ReplayStats{Hits: p.replayHits.Load(), Misses: p.replayMisses.Load()}
+}
+
 // Filter returns the current filter configuration.
 func (p *Proxy) Filter() *FilterConfig {
 	p.mu.RLock()
@@ -134,7 +196,9 @@ func (p *Proxy) CAManager() *CAManager {
 
 // ServeHTTP implements http.Handler for the proxy.
 func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if r.Method == http.MethodConnect {
+	if p.upstream != nil {
+		p.handleReverse(w, r)
+	} else if r.Method == http.MethodConnect {
 		p.handleConnect(w, r)
 	} else {
 		p.handleHTTP(w, r)
@@ -0,0 +1,167 @@
+package proxy
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/getmockd/mockd/pkg/recording"
+)
+
+// MissPolicy selects what replay mode does with a request no recording matches.
+type MissPolicy string
+
+const (
+	// MissFail answers unmatched requests with 502 Bad Gateway (the default).
+	MissFail MissPolicy = "fail"
+	// MissForward forwards unmatched requests without recording them.
+	MissForward MissPolicy = "forward"
+	// MissRecord forwards unmatched requests and records them, so later
+	// identical requests replay.
+	MissRecord MissPolicy = "record"
+)
+
+// ParseMode parses a proxy mode name.
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(s); m {
+	case ModeRecord, ModePassthrough, ModeReplay:
+		return m, nil
+	default:
+		return "", fmt.Errorf("invalid mode %q (must be 'record', 'passthrough' or 'replay')", s)
+	}
+}
+
+// ParseMissPolicy parses a replay miss policy name; empty means MissFail.
+func ParseMissPolicy(s string) (MissPolicy, error) {
+	switch p := MissPolicy(s); p {
+	case "":
+		return MissFail, nil
+	case MissFail, MissForward, MissRecord:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid miss policy %q (must be 'fail', 'forward' or 'record')", s)
+	}
+}
+
+// ReplayStats counts replay lookups since the proxy started.
+type ReplayStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// findRecording returns the recording that best answers a request, or nil.
+// Candidates must have the same method and host and the same path under
+// recording.SmartPathMatcher, so /users/42 replays a recording of /users/7.
+// Among them an exact path counts most, then the same query string, then
+// the same body; ties go to the newest recording.
+func (p *Proxy) findRecording(r *http.Request, body []byte) *recording.Recording {
+	recordings, _ := p.store.ListRecordings(recording.RecordingFilter{Method: r.Method})
+	smartPath := recording.SmartPathMatcher(r.URL.Path)
+
+	var best *recording.Recording
+	bestScore := -1
+	for _, rec := range recordings {
+		if !strings.EqualFold(rec.Request.Host, r.Host) || recording.SmartPathMatcher(rec.Request.Path) != smartPath {
+			continue
+		}
+		score := 0
+		if rec.Request.Path == r.URL.Path {
+			score += 4
+		}
+		if recordedQuery(rec) == r.URL.RawQuery {
+			score += 2
+		}
+		if bytes.Equal(rec.Request.Body, body) {
+			score++
+		}
+		if score > bestScore || (score == bestScore && !rec.Timestamp.Before(best.Timestamp)) {
+			best, bestScore = rec, score
+		}
+	}
+	return best
+}
+
+// recordedQuery returns the raw query string of a recorded request.
+func recordedQuery(rec *recording.Recording) string {
+	u, err := url.Parse(rec.Request.URL)
+	if err != nil {
+		return ""
+	}
+	return u.RawQuery
+}
+
+// replayResponse builds the response a recording answers a request with.
+func replayResponse(rec *recording.Recording, r *http.Request) *http.Response {
+	header := rec.Response.Headers.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Del("Content-Length")
+	header.Del("Transfer-Encoding")
+
+	status := rec.Response.Status
+	if status == "" {
+		status = fmt.Sprintf("%d %s", rec.Response.StatusCode, http.StatusText(rec.Response.StatusCode))
+	}
+	return &http.Response{
+		StatusCode:    rec.Response.StatusCode,
+		Status:        status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(rec.Response.Body)),
+		ContentLength: int64(len(rec.Response.Body)),
+		Request:       r,
+	}
+}
+
+// replayDecision is what the proxy does with a request in its current mode.
+type replayDecision struct {
+	// replay is the recording to answer with, if any.
+	replay *recording.Recording
+	// fail is set when replay mode found no recording and must not forward.
+	fail bool
+	// record is set when the forwarded exchange should be recorded.
+	record bool
+}
+
+// decide looks a request up according to the proxy's mode and miss policy.
+// Requests the filter excludes are never replayed or recorded.
+func (p *Proxy) decide(r *http.Request, body []byte) replayDecision {
+	p.mu.RLock()
+	mode, onMiss, filter := p.mode, p.onMiss, p.filter
+	p.mu.RUnlock()
+
+	if filter != nil && !filter.ShouldRecord(r.Host, r.URL.Path) {
+		return replayDecision{}
+	}
+	switch mode {
+	case ModeRecord:
+		return replayDecision{record: true}
+	case ModeReplay:
+		if rec := p.findRecording(r, body); rec != nil {
+			p.replayHits.Add(1)
+			return replayDecision{replay: rec}
+		}
+		p.replayMisses.Add(1)
+		switch onMiss {
+		case MissForward:
+			return replayDecision{}
+		case MissRecord:
+			return replayDecision{record: true}
+		default:
+			return replayDecision{fail: true}
+		}
+	default:
+		return replayDecision{}
+	}
+}
+
+// missMessage is the body of the 502 answering a replay miss.
+func missMessage(r *http.Request) string {
+	return fmt.Sprintf("mockd proxy is replaying and has no recording for %s %s%s", r.Method, r.Host, r.URL.RequestURI())
+}
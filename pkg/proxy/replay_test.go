@@ -0,0 +1,159 @@
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/getmockd/mockd/pkg/recording"
+)
+
+// replayFixture starts an upstream counting its requests and a proxy in
+// replay mode whose store holds one recording of GET <upstream>/users/7.
+func replayFixture(t *testing.T, onMiss MissPolicy) (*Proxy, *httptest.Server, *atomic.Int64) {
+	t.Helper()
+	var hits atomic.Int64
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_, _ = w.Write([]byte("live " + r.URL.Path))
+	}))
+	t.Cleanup(upstream.Close)
+
+	store := recording.NewStore()
+	store.CreateSession("cassette", nil)
+	rec := recording.NewRecording("")
+	rec.CaptureRequest(httptest.NewRequest(http.MethodGet, upstream.URL+"/users/7", nil), nil)
+	rec.Response = recording.RecordedResponse{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Headers:    http.Header{"Content-Type": {"application/json"}, "Content-Length": {"999"}},
+		Body:       []byte(`{"id":7}`),
+	}
+	if err := store.AddRecording(rec); err != nil {
+		t.Fatalf("AddRecording() error = %v", err)
+	}
+
+	return New(Options{Mode: ModeReplay, OnMiss: onMiss, Store: store}), upstream, &hits
+}
+
+func proxyGet(p *Proxy, url string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	p.handleHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+	return rec
+}
+
+func TestReplay_ServesRecordings(t *testing.T) {
+	p, upstream, hits := replayFixture(t, MissFail)
+
+	for _, path := range []string{"/users/7", "/users/42"} {
+		rec := proxyGet(p, upstream.URL+path)
+		if rec.Code != http.StatusOK || rec.Body.String() != `{"id":7}` {
+			t.Errorf("GET %s = %d %q, want the recording", path, rec.Code, rec.Body.String())
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("GET %s Content-Type = %q", path, ct)
+		}
+		if cl := rec.Header().Get("Content-Length"); cl == "999" {
+			t.Errorf("GET %s replayed the recorded Content-Length", path)
+		}
+	}
+	if n := hits.Load(); n != 0 {
+		t.Errorf("upstream received %d requests in replay mode", n)
+	}
+	if stats := p.ReplayStats(); stats.Hits != 2 || stats.Misses != 0 {
+		t.Errorf("ReplayStats() = %+v, want 2 hits", stats)
+	}
+}
+
+func TestReplay_PrefersExactPath(t *testing.T) {
+	p, upstream, _ := replayFixture(t, MissFail)
+	exact := recording.NewRecording("")
+	exact.CaptureRequest(httptest.NewRequest(http.MethodGet, upstream.URL+"/users/42", nil), nil)
+	exact.Response = recording.RecordedResponse{StatusCode: http.StatusOK, Body: []byte(`{"id":42}`)}
+	_ = p.Store().AddRecording(exact)
+
+	if body := proxyGet(p, upstream.URL+"/users/42").Body.String(); body != `{"id":42}` {
+		t.Errorf("GET /users/42 = %q, want the exact recording", body)
+	}
+	if body := proxyGet(p, upstream.URL+"/users/7").Body.String(); body != `{"id":7}` {
+		t.Errorf("GET /users/7 = %q, want the exact recording", body)
+	}
+}
+
+func TestReplay_MissPolicies(t *testing.T) {
+	t.Run("fail", func(t *testing.T) {
+		p, upstream, hits := replayFixture(t, MissFail)
+		rec := proxyGet(p, upstream.URL+"/orders")
+		if rec.Code != http.StatusBadGateway || !strings.Contains(rec.Body.String(), "no recording") {
+			t.Errorf("miss = %d %q, want 502", rec.Code, rec.Body.String())
+		}
+		if hits.Load() != 0 {
+			t.Error("a failed miss reached the upstream")
+		}
+		if stats := p.ReplayStats(); stats.Misses != 1 {
+			t.Errorf("ReplayStats() = %+v, want 1 miss", stats)
+		}
+	})
+
+	t.Run("forward", func(t *testing.T) {
+		p, upstream, hits := replayFixture(t, MissForward)
+		for range 2 {
+			if body := proxyGet(p, upstream.URL+"/orders").Body.String(); body != "live /orders" {
+				t.Errorf("miss = %q, want the upstream response", body)
+			}
+		}
+		if hits.Load() != 2 {
+			t.Errorf("upstream received %d requests, want 2", hits.Load())
+		}
+	})
+
+	t.Run("record", func(t *testing.T) {
+		p, upstream, hits := replayFixture(t, MissRecord)
+		for range 2 {
+			if body := proxyGet(p, upstream.URL+"/orders").Body.String(); body != "live /orders" {
+				t.Errorf("GET /orders = %q", body)
+			}
+		}
+		if hits.Load() != 1 {
+			t.Errorf("upstream received %d requests, want 1 (the second replays)", hits.Load())
+		}
+		if stats := p.ReplayStats(); stats.Hits != 1 || stats.Misses != 1 {
+			t.Errorf("ReplayStats() = %+v, want 1 hit and 1 miss", stats)
+		}
+	})
+}
+
+func TestReplay_SwitchModeAtRuntime(t *testing.T) {
+	p, upstream, hits := replayFixture(t, MissFail)
+
+	p.SetMode(ModePassthrough)
+	if body := proxyGet(p, upstream.URL+"/users/7").Body.String(); body != "live /users/7" {
+		t.Errorf("passthrough = %q, want the upstream response", body)
+	}
+
+	p.SetMode(ModeReplay)
+	p.SetOnMiss(MissForward)
+	if body := proxyGet(p, upstream.URL+"/orders").Body.String(); body != "live /orders" {
+		t.Errorf("replay miss with forward = %q", body)
+	}
+	if hits.Load() != 2 {
+		t.Errorf("upstream received %d requests, want 2", hits.Load())
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	if m, err := ParseMode("replay"); err != nil || m != ModeReplay {
+		t.Errorf("ParseMode(replay) = %q, %v", m, err)
+	}
+	if _, err := ParseMode("rewind"); err == nil {
+		t.Error("ParseMode(rewind) succeeded")
+	}
+	if p, err := ParseMissPolicy(""); err != nil || p != MissFail {
+		t.Errorf("ParseMissPolicy(\"\") = %q, %v, want fail", p, err)
+	}
+	if _, err := ParseMissPolicy("skip"); err == nil {
+		t.Error("ParseMissPolicy(skip) succeeded")
+	}
+}
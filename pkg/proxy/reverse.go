@@ -0,0 +1,290 @@
+package proxy
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseUpstream parses the base URL a reverse proxy forwards to. It must be an
+// absolute http or https URL without query or fragment; its path, if any, is
+// prefixed to every forwarded path.
+func ParseUpstream(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upstream URL %q: %w", s, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid upstream URL %q: scheme must be http or https", s)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid upstream URL %q: missing host", s)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("invalid upstream URL %q: must not have a query or fragment", s)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = ""
+	return u, nil
+}
+
+// Upstream returns the reverse proxy upstream, or nil for a forward proxy.
+func (p *Proxy) Upstream() *url.URL {
+	return p.upstream
+}
+
+// handleReverse handles a request to a reverse proxy. The request is rewritten
+// to target the upstream and then replayed, forwarded and recorded exactly like
+// a forward proxy request, so recordings hold the upstream's URLs. Responses
+// are rewritten so Location headers, cookies and absolute URLs in bodies point
+// back at the proxy.
+func (p *Proxy) handleReverse(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+
+	if r.Method == http.MethodConnect {
+		http.Error(w, "mockd reverse proxy does not accept CONNECT requests", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Read and buffer the request body
+	var reqBody []byte
+	if r.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(io.LimitReader(r.Body, DefaultMaxBodySize))
+		if err != nil {
+			p.log("Error reading request body: %v", err)
+			http.Error(w, "Error reading request", http.StatusBadGateway)
+			return
+		}
+		_ = r.Body.Close()
+	}
+
+	out := p.upstreamRequest(r, reqBody)
+	rw := newRewriter(p.upstream, r)
+
+	p.log("[%s] %s -> %s", r.Method, r.URL.RequestURI(), out.URL)
+
+	decision := p.decide(out, reqBody)
+	if decision.replay != nil {
+		resp := replayResponse(decision.replay, out)
+		rw.writeResponse(w, resp.StatusCode, resp.Header, decision.replay.Response.Body)
+		p.log("Replayed: %s %s (%d) from %s", r.Method, out.URL.Path, resp.StatusCode, decision.replay.ID)
+		return
+	}
+	if decision.fail {
+		p.log("Replay miss: %s %s", r.Method, out.URL.Path)
+		http.Error(w, missMessage(out), http.StatusBadGateway)
+		return
+	}
+
+	resp, err := p.client.Do(out) //nolint:gosec // G704 — reverse proxy: forwarding to the configured upstream is intentional
+	if err != nil {
+		p.log("Error forwarding request: %v", err)
+		http.Error(w, "Error forwarding request: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, DefaultMaxBodySize))
+	if err != nil {
+		p.log("Error reading response body: %v", err)
+		http.Error(w, "Error reading response", http.StatusBadGateway)
+		return
+	}
+
+	duration := time.Since(startTime)
+
+	if decision.record {
+		p.record(out, reqBody, resp, respBody, duration)
+	}
+
+	rw.writeResponse(w, resp.StatusCode, resp.Header, respBody)
+
+	p.log("Response: %d %s [%v]", resp.StatusCode, resp.Status, duration)
+}
+
+// upstreamRequest builds the request forwarded to the upstream.
+func (p *Proxy) upstreamRequest(r *http.Request, body []byte) *http.Request {
+	target := *p.upstream
+	target.Path = p.upstream.Path + r.URL.Path
+	target.RawQuery = r.URL.RawQuery
+
+	out, _ := http.NewRequestWithContext(r.Context(), r.Method, target.String(), bytes.NewReader(body))
+	copyHeaders(out.Header, r.Header)
+	removeHopByHopHeaders(out.Header)
+	// Let the transport negotiate and decode compression so response bodies
+	// can be rewritten.
+	out.Header.Del("Accept-Encoding")
+
+	if clientIP, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		out.Header.Set("X-Forwarded-For", clientIP)
+	}
+	out.Header.Set("X-Forwarded-Host", r.Host)
+	out.Header.Set("X-Forwarded-Proto", requestScheme(r))
+	return out
+}
+
+// requestScheme returns the scheme a client used to reach the proxy.
+func requestScheme(r *http.Request) string {
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		return proto
+	}
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// rewriter maps the upstream's URLs in a response to the proxy's.
+type rewriter struct {
+	upstream *url.URL
+	// from and to are the upstream base URL and the proxy's, without a
+	// trailing slash.
+	from, to string
+}
+
+func newRewriter(upstream *url.URL, r *http.Request) *rewriter {
+	return &rewriter{
+		upstream: upstream,
+		from:     upstream.Scheme + "://" + upstream.Host + upstream.Path,
+		to:       requestScheme(r) + "://" + r.Host,
+	}
+}
+
+// writeResponse writes a rewritten response to the client.
+func (rw *rewriter) writeResponse(w http.ResponseWriter, status int, header http.Header, body []byte) {
+	for key, values := range header {
+		switch http.CanonicalHeaderKey(key) {
+		case "Location", "Content-Location":
+			for _, v := range values {
+				w.Header().Add(key, rw.location(v))
+			}
+		case "Set-Cookie":
+			for _, v := range values {
+				w.Header().Add(key, rw.cookie(v))
+			}
+		case "Content-Length", "Transfer-Encoding", "Connection":
+		default:
+			for _, v := range values {
+				w.Header().Add(key, v)
+			}
+		}
+	}
+
+	body = rw.body(header, body)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// location rewrites a Location or Content-Location value.
+func (rw *rewriter) location(v string) string {
+	if rest, ok := rw.trimBase(v); ok {
+		return rw.to + rest
+	}
+	// A relative redirect under the upstream's base path.
+	if rw.upstream.Path != "" && strings.HasPrefix(v, "/") {
+		if rest, ok := trimPathPrefix(v, rw.upstream.Path); ok {
+			return rest
+		}
+	}
+	return v
+}
+
+// trimBase strips the upstream base URL from an absolute URL.
+func (rw *rewriter) trimBase(v string) (string, bool) {
+	if len(v) < len(rw.from) || !strings.EqualFold(v[:len(rw.from)], rw.from) {
+		return "", false
+	}
+	rest := v[len(rw.from):]
+	if rest != "" && !strings.ContainsRune("/?#", rune(rest[0])) {
+		return "", false // e.g. https://api.example.com.evil
+	}
+	return rest, true
+}
+
+// trimPathPrefix strips a path prefix on a segment boundary, keeping a
+// leading slash.
+func trimPathPrefix(p, prefix string) (string, bool) {
+	if !strings.HasPrefix(p, prefix) {
+		return "", false
+	}
+	rest := p[len(prefix):]
+	switch {
+	case rest == "":
+		return "/", true
+	case strings.ContainsRune("/?#", rune(rest[0])):
+		if rest[0] != '/' {
+			rest = "/" + rest
+		}
+		return rest, true
+	default:
+		return "", false
+	}
+}
+
+// cookie rewrites a Set-Cookie value: a Domain naming the upstream is
+// dropped so the cookie belongs to the proxy's host, and a Path under the
+// upstream's base path loses the base path.
+func (rw *rewriter) cookie(v string) string {
+	c, err := http.ParseSetCookie(v)
+	if err != nil {
+		return v
+	}
+	if c.Domain != "" {
+		domain := strings.TrimPrefix(strings.ToLower(c.Domain), ".")
+		upstreamHost := strings.ToLower(rw.upstream.Hostname())
+		if upstreamHost == domain || strings.HasSuffix(upstreamHost, "."+domain) {
+			c.Domain = ""
+		}
+	}
+	if rw.upstream.Path != "" && c.Path != "" {
+		if rest, ok := trimPathPrefix(c.Path, rw.upstream.Path); ok {
+			c.Path = rest
+		}
+	}
+	if c.Secure && strings.HasPrefix(rw.to, "http://") {
+		// Clients do not send Secure cookies over plain HTTP.
+		c.Secure = false
+	}
+	if s := c.String(); s != "" {
+		return s
+	}
+	return v
+}
+
+// body replaces the upstream's absolute base URL in textual bodies, including
+// the JSON form that escapes slashes.
+func (rw *rewriter) body(header http.Header, body []byte) []byte {
+	if len(body) == 0 || header.Get("Content-Encoding") != "" || !isTextual(header.Get("Content-Type")) {
+		return body
+	}
+	body = bytes.ReplaceAll(body, []byte(rw.from), []byte(rw.to))
+	escape := func(s string) []byte { return []byte(strings.ReplaceAll(s, "/", `\/`)) }
+	return bytes.ReplaceAll(body, escape(rw.from), escape(rw.to))
+}
+
+// isTextual reports whether a content type is text that may hold URLs.
+func isTextual(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	if strings.HasPrefix(mediaType, "text/") {
+		return true
+	}
+	switch {
+	case strings.HasSuffix(mediaType, "json"), strings.HasSuffix(mediaType, "+xml"),
+		mediaType == "application/xml", mediaType == "application/javascript",
+		mediaType == "application/x-www-form-urlencoded":
+		return true
+	}
+	return false
+}
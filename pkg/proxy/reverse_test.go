@@ -0,0 +1,127 @@
+package proxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/getmockd/mockd/pkg/recording"
+)
+
+func TestParseUpstream(t *testing.T) {
+	u, err := ParseUpstream("https://api.example.com/v1/")
+	if err != nil {
+		t.Fatalf("ParseUpstream() error = %v", err)
+	}
+	if u.Host != "api.example.com" || u.Path != "/v1" {
+		t.Errorf("ParseUpstream() = host %q path %q", u.Host, u.Path)
+	}
+	for _, bad := range []string{"api.example.com", "ftp://api.example.com", "https://", "https://api.example.com/?q=1"} {
+		if _, err := ParseUpstream(bad); err == nil {
+			t.Errorf("ParseUpstream(%q) succeeded", bad)
+		}
+	}
+}
+
+// reverseFixture starts an upstream serving under /api and a recording
+// reverse proxy in front of it.
+func reverseFixture(t *testing.T) (*httptest.Server, *Proxy, *[]string) {
+	t.Helper()
+	var seen []string
+	upstream := httptest.NewUnstartedServer(nil)
+	upstream.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.URL.RequestURI())
+		base := "http://" + r.Host + "/api"
+		switch r.URL.Path {
+		case "/api/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Domain: "127.0.0.1", Path: "/api", Secure: true})
+			w.Header().Set("Location", base+"/users/1")
+			w.WriteHeader(http.StatusFound)
+		case "/api/users/1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `{"self":"`+base+`/users/1","escaped":"`+strings.ReplaceAll(base+"/users/1", "/", `\/`)+`"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	upstream.Start()
+	t.Cleanup(upstream.Close)
+
+	target, err := ParseUpstream(upstream.URL + "/api")
+	if err != nil {
+		t.Fatalf("ParseUpstream() error = %v", err)
+	}
+	store := recording.NewStore()
+	store.CreateSession("reverse", nil)
+	p := New(Options{Mode: ModeRecord, Upstream: target, Store: store})
+	return upstream, p, &seen
+}
+
+func TestReverseProxy_RewritesResponses(t *testing.T) {
+	upstream, p, seen := reverseFixture(t)
+
+	req := httptest.NewRequest(http.MethodPost, "http://mockd.local:8888/login?next=1", strings.NewReader("user=a"))
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, req)
+
+	if len(*seen) != 1 || (*seen)[0] != "/api/login?next=1" {
+		t.Fatalf("upstream saw %v, want /api/login?next=1", *seen)
+	}
+	if rec.Code != http.StatusFound {
+		t.Fatalf("status = %d, want 302", rec.Code)
+	}
+	if loc := rec.Header().Get("Location"); loc != "http://mockd.local:8888/users/1" {
+		t.Errorf("Location = %q, want it rewritten to the proxy", loc)
+	}
+	cookie, err := http.ParseSetCookie(rec.Header().Get("Set-Cookie"))
+	if err != nil {
+		t.Fatalf("ParseSetCookie() error = %v", err)
+	}
+	if cookie.Domain != "" || cookie.Path != "/" || cookie.Secure {
+		t.Errorf("Set-Cookie = %q, want no Domain, Path=/ and no Secure", rec.Header().Get("Set-Cookie"))
+	}
+
+	rec = httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://mockd.local:8888/users/1", nil))
+	want := `{"self":"http://mockd.local:8888/users/1","escaped":"http:\/\/mockd.local:8888\/users\/1"}`
+	if body := rec.Body.String(); body != want {
+		t.Errorf("body = %s\nwant %s", body, want)
+	}
+	if cl := rec.Header().Get("Content-Length"); cl != strconv.Itoa(len(want)) {
+		t.Errorf("Content-Length = %q, want %d", cl, len(want))
+	}
+
+	// Recordings hold the upstream's URLs, not the proxy's.
+	recordings, total := p.Store().ListRecordings(recording.RecordingFilter{})
+	if total != 2 {
+		t.Fatalf("recorded %d requests, want 2", total)
+	}
+	host := strings.TrimPrefix(upstream.URL, "http://")
+	for _, r := range recordings {
+		if r.Request.Host != host || !strings.HasPrefix(r.Request.Path, "/api/") {
+			t.Errorf("recording = %s %s, want upstream host and path", r.Request.Host, r.Request.Path)
+		}
+	}
+	if string(recordings[0].Request.Body) != "user=a" {
+		t.Errorf("recorded body = %q", recordings[0].Request.Body)
+	}
+}
+
+func TestReverseProxy_Replay(t *testing.T) {
+	upstream, p, seen := reverseFixture(t)
+	p.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost:8888/users/1", nil))
+	upstream.Close()
+
+	p.SetMode(ModeReplay)
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://localhost:8888/users/1", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"self":"http://localhost:8888/users/1"`) {
+		t.Errorf("replayed %d %s", rec.Code, rec.Body.String())
+	}
+	if len(*seen) != 1 {
+		t.Errorf("upstream saw %d requests, want 1", len(*seen))
+	}
+}
@@ -23,6 +23,9 @@ type ConvertOptions struct {
 	IncludeHeaders bool // Include request headers in matcher
 	Deduplicate    bool // Remove duplicate request patterns
 	SmartMatch     bool // Convert dynamic path segments like /users/123 to /users/{id}
+	GraphQL        bool // Convert GraphQL calls to GraphQL mocks instead of HTTP mocks
+	Stateful       bool // Infer stateful tables and CRUD bindings from REST resources
+	Templates      bool // Replace echoed request values and volatile fields with templates
 }
 
 // DefaultConvertOptions returns the default conversion options.
@@ -73,6 +76,22 @@ type ConversionResult struct {
 	Warnings []SensitiveDataWarning      `json:"warnings,omitempty"`
 	Filtered int                         `json:"filtered"` // Number of recordings filtered out
 	Total    int                         `json:"total"`    // Total recordings processed
+
+	// GraphQLOperations and GraphQLWarnings describe GraphQL calls converted
+	// to GraphQL mocks when ConvertOptions.GraphQL is set.
+	GraphQLOperations []GraphQLOperationSummary `json:"graphqlOperations,omitempty"`
+	GraphQLWarnings   []string                  `json:"graphqlWarnings,omitempty"`
+
+	// Tables and Extend hold the stateful tables and bindings inferred when
+	// ConvertOptions.Stateful is set; Mocks then includes the bound mocks.
+	Tables            []*config.TableConfig     `json:"tables,omitempty"`
+	Extend            []*config.ExtendBinding   `json:"extend,omitempty"`
+	StatefulResources []StatefulResourceSummary `json:"statefulResources,omitempty"`
+	StatefulWarnings  []string                  `json:"statefulWarnings,omitempty"`
+
+	// Substitutions lists every value replaced by a template expression
+	// when ConvertOptions.Templates is set.
+	Substitutions []TemplateSubstitution `json:"substitutions,omitempty"`
 }
 
 // StreamConvertOptions configures how stream recordings are converted to configs.
@@ -237,8 +256,36 @@ func ConvertRecordingsWithOptions(recordings []*Recording, opts SessionConvertOp
 		result.Warnings = append(result.Warnings, warnings...)
 	}
 
+	// GraphQL calls share one path, so convert them before deduplication
+	var graphqlMocks []*config.MockConfiguration
+	if opts.GraphQL {
+		var graphqlRecs []*Recording
+		graphqlRecs, filtered = SplitGraphQLRecordings(filtered)
+		if len(graphqlRecs) > 0 {
+			converted := ConvertGraphQLRecordings(graphqlRecs, DefaultGraphQLConvertOptions())
+			graphqlMocks = converted.Mocks
+			result.GraphQLOperations = converted.Operations
+			result.GraphQLWarnings = converted.Warnings
+		}
+	}
+
+	// REST resources become stateful tables; only the rest stay static
+	var statefulMocks []*config.MockConfiguration
+	if opts.Stateful {
+		inferred := InferStatefulResources(filtered)
+		filtered = inferred.Remaining
+		statefulMocks = inferred.Mocks
+		result.Tables = inferred.Tables
+		result.Extend = inferred.Extend
+		result.StatefulResources = inferred.Resources
+		result.StatefulWarnings = inferred.Warnings
+	}
+
 	// Convert with deduplication strategy
-	mocks := ToMocksWithStrategy(filtered, opts.ConvertOptions, opts.Duplicates)
+	selected := selectByStrategy(filtered, opts.Duplicates)
+	convertOpts := opts.ConvertOptions
+	convertOpts.Deduplicate = false
+	mocks := ToMocks(selected, convertOpts)
 
 	// Apply smart matching if enabled
 	if opts.SmartMatch {
@@ -247,10 +294,35 @@ func ConvertRecordingsWithOptions(recordings []*Recording, opts SessionConvertOp
 				m.HTTP.Matcher.Path = SmartPathMatcher(m.HTTP.Matcher.Path)
 			}
 		}
+	}
+
+	// Template echoed values; this may turn echoed ID segments into parameters
+	if opts.Templates {
+		inferrer := NewTemplateInferrer(recordings)
+		for i, m := range mocks {
+			result.Substitutions = append(result.Substitutions, inferrer.Apply(selected[i], m)...)
+		}
+	}
+
+	if opts.SmartMatch || opts.Templates {
 		mocks = DeduplicatePaths(mocks, opts.Duplicates)
 	}
+	if len(result.Substitutions) > 0 {
+		kept := make(map[string]bool, len(mocks))
+		for _, m := range mocks {
+			kept[m.ID] = true
+		}
+		subs := result.Substitutions[:0]
+		for _, sub := range result.Substitutions {
+			if kept[sub.MockID] {
+				subs = append(subs, sub)
+			}
+		}
+		result.Substitutions = subs
+	}
 
-	result.Mocks = mocks
+	mocks = append(mocks, statefulMocks...)
+	result.Mocks = append(mocks, graphqlMocks...)
 	return result
 }
 
@@ -407,9 +479,15 @@ func matchesStatusRange(statusCode int, rangeStr string) bool {
 
 // ToMocksWithStrategy converts recordings to mocks with a deduplication strategy.
 func ToMocksWithStrategy(recordings []*Recording, opts ConvertOptions, strategy string) []*config.MockConfiguration {
+	opts.Deduplicate = false // Deduplicated by the strategy
+	return ToMocks(selectByStrategy(recordings, strategy), opts)
+}
+
+// selectByStrategy picks the recordings to convert for each method + path
+// according to a duplicate strategy: "first", "last" or "all".
+func selectByStrategy(recordings []*Recording, strategy string) []*Recording {
 	if strategy == "all" || strategy == "" {
-		opts.Deduplicate = false
-		return ToMocks(recordings, opts)
+		return recordings
 	}
 
 	// Group recordings by method + path
@@ -436,8 +514,7 @@ func ToMocksWithStrategy(recordings []*Recording, opts ConvertOptions, strategy
 		}
 	}
 
-	opts.Deduplicate = false // Already deduplicated
-	return ToMocks(selected, opts)
+	return selected
 }
 
 // SmartPathMatcher converts a concrete path to a parameterized pattern.
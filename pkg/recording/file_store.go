@@ -17,8 +17,9 @@ import (
 
 // FileStore provides persistent file-based storage for stream recordings.
 type FileStore struct {
-	mu     sync.RWMutex
-	config StorageConfig
+	mu       sync.RWMutex
+	config   StorageConfig
+	redactor *Redactor
 
 	// Active recording sessions
 	sessions map[string]*StreamRecordingSession
@@ -35,6 +36,7 @@ type StreamRecordingSession struct {
 	closed    bool
 	startTime time.Time
 	seq       int64
+	redactor  *Redactor
 }
 
 // ID returns the recording ID (which is used as the session ID).
@@ -66,6 +68,11 @@ func NewFileStore(config StorageConfig) (*FileStore, error) {
 		config.RedactValue = "[REDACTED]"
 	}
 
+	redactor, err := NewRedactor(config.Redaction)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create data directory if it doesn't exist
 	if err := os.MkdirAll(config.DataDir, 0700); err != nil {
 		return nil, fmt.Errorf("failed to create data directory: %w", err)
@@ -73,6 +80,7 @@ func NewFileStore(config StorageConfig) (*FileStore, error) {
 
 	store := &FileStore{
 		config:       config,
+		redactor:     redactor,
 		sessions:     make(map[string]*StreamRecordingSession),
 		summaryCache: make(map[string]*RecordingSummary),
 		cacheTTL:     5 * time.Second,
@@ -97,7 +105,7 @@ func (s *FileStore) StartRecording(protocol Protocol, metadata RecordingMetadata
 
 	// Filter sensitive headers
 	if metadata.Headers != nil {
-		metadata.Headers = s.filterHeaders(metadata.Headers)
+		metadata.Headers = s.redactor.RedactHeaderMap(s.filterHeaders(metadata.Headers))
 	}
 
 	recording := NewStreamRecording(protocol, metadata)
@@ -105,6 +113,7 @@ func (s *FileStore) StartRecording(protocol Protocol, metadata RecordingMetadata
 		recording: recording,
 		startTime: recording.StartTime,
 		seq:       0,
+		redactor:  s.redactor,
 	}
 
 	s.sessions[recording.ID] = session
@@ -115,6 +124,25 @@ func (s *FileStore) StartRecording(protocol Protocol, metadata RecordingMetadata
 	return session, nil
 }
 
+// SetSessionRedactor replaces the redaction of an active session, for example
+// with rules configured when the recording was started. Headers already
+// captured are redacted again.
+func (s *FileStore) SetSessionRedactor(sessionID string, redactor *Redactor) error {
+	s.mu.RLock()
+	session, ok := s.sessions[sessionID]
+	s.mu.RUnlock()
+
+	if !ok {
+		return ErrNotFound
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.redactor = redactor
+	session.recording.Metadata.Headers = redactor.RedactHeaderMap(session.recording.Metadata.Headers)
+	return nil
+}
+
 // filterHeaders removes sensitive headers from the map.
 func (s *FileStore) filterHeaders(headers map[string]string) map[string]string {
 	filtered := make(map[string]string)
@@ -152,6 +180,10 @@ func (s *FileStore) AppendWebSocketFrame(sessionID string, dir Direction, msgTyp
 		return ErrNoActiveSession
 	}
 
+	if msgType == MessageTypeText {
+		data = session.redactor.RedactPayload(data)
+	}
+
 	session.seq++
 	frame := NewWebSocketFrame(session.seq, session.startTime, dir, msgType, data)
 	session.recording.AddWebSocketFrame(frame)
@@ -209,6 +241,7 @@ func (s *FileStore) AppendSSEEvent(sessionID string, eventType, data, id string,
 		firstEventTime = session.recording.SSE.Events[0].Timestamp
 	}
 
+	data = string(session.redactor.RedactPayload([]byte(data)))
 	event := NewSSEEvent(session.seq, firstEventTime, eventType, data, id, retry)
 	session.recording.AddSSEEvent(event)
 
@@ -0,0 +1,593 @@
+// Package recording provides conversion from recorded GraphQL traffic to mock configurations.
+package recording
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/getmockd/mockd/internal/id"
+	"github.com/getmockd/mockd/pkg/config"
+	"github.com/getmockd/mockd/pkg/graphql"
+	"github.com/getmockd/mockd/pkg/mock"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// GraphQLConvertOptions configures how recorded GraphQL traffic is converted.
+type GraphQLConvertOptions struct {
+	// MatchArgs builds Match.Args from each root field's arguments
+	MatchArgs bool `json:"matchArgs,omitempty"`
+
+	// IncludeDelay includes recorded latency as delay
+	IncludeDelay bool `json:"includeDelay,omitempty"`
+
+	// PreserveErrors converts failed root fields to Error configs; failed
+	// fields are skipped otherwise
+	PreserveErrors bool `json:"preserveErrors,omitempty"`
+
+	// InferSchema builds a schema from the recorded queries and responses
+	// when no schema was captured by introspection
+	InferSchema bool `json:"inferSchema,omitempty"`
+}
+
+// DefaultGraphQLConvertOptions returns default conversion options.
+func DefaultGraphQLConvertOptions() GraphQLConvertOptions {
+	return GraphQLConvertOptions{
+		MatchArgs:      true,
+		IncludeDelay:   false,
+		PreserveErrors: true,
+		InferSchema:    true,
+	}
+}
+
+// GraphQLOperationSummary describes a recorded operation and the root
+// fields it selected.
+type GraphQLOperationSummary struct {
+	Name   string   `json:"name"`
+	Type   string   `json:"type"`
+	Fields []string `json:"fields"`
+	Count  int      `json:"count"`
+}
+
+// GraphQLConvertResult contains the result of converting GraphQL recordings.
+type GraphQLConvertResult struct {
+	Mocks         []*config.MockConfiguration `json:"mocks"`
+	Operations    []GraphQLOperationSummary   `json:"operations"`
+	ResolverCount int                         `json:"resolverCount"`
+	VariantCount  int                         `json:"variantCount"`
+	Total         int                         `json:"total"`
+	Skipped       int                         `json:"skipped"`
+	Warnings      []string                    `json:"warnings,omitempty"`
+}
+
+// graphqlResponse is a decoded GraphQL response body.
+type graphqlResponse struct {
+	Data   map[string]any         `json:"data"`
+	Errors []recordedGraphQLError `json:"errors"`
+}
+
+type recordedGraphQLError struct {
+	Message    string         `json:"message"`
+	Path       []any          `json:"path"`
+	Extensions map[string]any `json:"extensions"`
+}
+
+// IsGraphQLRecording reports whether a recording holds a GraphQL request: a
+// POST with a JSON body carrying a query (or a batch of them), a POST with
+// an application/graphql body, or a GET with a query parameter. The query
+// must parse as a GraphQL document, so REST endpoints that happen to accept
+// a "query" field are not mistaken for GraphQL.
+func IsGraphQLRecording(r *Recording) bool {
+	requests := graphqlRequests(r)
+	if len(requests) == 0 {
+		return false
+	}
+	for _, req := range requests {
+		doc, err := parser.ParseQuery(&ast.Source{Input: req.Query})
+		if err != nil || len(doc.Operations) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// SplitGraphQLRecordings separates GraphQL recordings from the rest.
+func SplitGraphQLRecordings(recordings []*Recording) (graphqlRecs, others []*Recording) {
+	for _, r := range recordings {
+		if IsGraphQLRecording(r) {
+			graphqlRecs = append(graphqlRecs, r)
+		} else {
+			others = append(others, r)
+		}
+	}
+	return graphqlRecs, others
+}
+
+// graphqlRequests extracts the GraphQL requests carried by a recording.
+func graphqlRequests(r *Recording) []graphql.GraphQLRequest {
+	if r == nil {
+		return nil
+	}
+
+	switch r.Request.Method {
+	case http.MethodGet:
+		u, err := url.Parse(r.Request.URL)
+		if err != nil {
+			return nil
+		}
+		params := u.Query()
+		req := graphql.GraphQLRequest{
+			Query:         params.Get("query"),
+			OperationName: params.Get("operationName"),
+		}
+		if req.Query == "" {
+			return nil
+		}
+		if vars := params.Get("variables"); vars != "" {
+			_ = json.Unmarshal([]byte(vars), &req.Variables)
+		}
+		return []graphql.GraphQLRequest{req}
+
+	case http.MethodPost:
+		if strings.HasPrefix(r.Request.Headers.Get("Content-Type"), "application/graphql") {
+			if len(r.Request.Body) == 0 {
+				return nil
+			}
+			return []graphql.GraphQLRequest{{Query: string(r.Request.Body)}}
+		}
+
+		body := bytes.TrimSpace(r.Request.Body)
+		var requests []graphql.GraphQLRequest
+		switch {
+		case len(body) > 0 && body[0] == '[':
+			if err := json.Unmarshal(body, &requests); err != nil {
+				return nil
+			}
+		case len(body) > 0 && body[0] == '{':
+			var req graphql.GraphQLRequest
+			if err := json.Unmarshal(body, &req); err != nil {
+				return nil
+			}
+			requests = append(requests, req)
+		}
+		if len(requests) == 0 {
+			return nil
+		}
+		for _, req := range requests {
+			if strings.TrimSpace(req.Query) == "" {
+				return nil
+			}
+		}
+		return requests
+	}
+
+	return nil
+}
+
+// graphqlResponses decodes the response body, which holds one response per
+// request for batches.
+func graphqlResponses(r *Recording, batch bool) ([]graphqlResponse, error) {
+	body := bytes.TrimSpace(r.Response.Body)
+	if batch && len(body) > 0 && body[0] == '[' {
+		var responses []graphqlResponse
+		if err := json.Unmarshal(body, &responses); err != nil {
+			return nil, err
+		}
+		return responses, nil
+	}
+	var resp graphqlResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	return []graphqlResponse{resp}, nil
+}
+
+// graphqlBuilder accumulates resolvers for one GraphQL endpoint.
+type graphqlBuilder struct {
+	opts     GraphQLConvertOptions
+	result   *GraphQLConvertResult
+	fields   map[string][]mock.ResolverConfig
+	seen     map[string]map[string]bool
+	order    []string
+	ops      map[string]*GraphQLOperationSummary
+	opOrder  []string
+	captured string
+	inferrer *schemaInferrer
+}
+
+// ToGraphQLSpec converts recordings of one GraphQL endpoint to a
+// GraphQLSpec. Each root field becomes a resolver keyed on its operation
+// type ("Query.user"); recordings with different arguments become match
+// variants, recordings with identical arguments keep the first, and a call
+// without arguments becomes the field's fallback.
+func ToGraphQLSpec(recordings []*Recording, opts GraphQLConvertOptions) *mock.GraphQLSpec {
+	result := &GraphQLConvertResult{}
+	return buildGraphQLSpec(recordings, opts, result)
+}
+
+// ConvertGraphQLRecordings converts GraphQL recordings to mocks with stats,
+// producing one GraphQL mock per recorded endpoint path.
+func ConvertGraphQLRecordings(recordings []*Recording, opts GraphQLConvertOptions) *GraphQLConvertResult {
+	result := &GraphQLConvertResult{
+		Total:      len(recordings),
+		Mocks:      make([]*config.MockConfiguration, 0),
+		Operations: make([]GraphQLOperationSummary, 0),
+		Warnings:   make([]string, 0),
+	}
+
+	byPath := make(map[string][]*Recording)
+	paths := make([]string, 0)
+	for _, r := range recordings {
+		if _, exists := byPath[r.Request.Path]; !exists {
+			paths = append(paths, r.Request.Path)
+		}
+		byPath[r.Request.Path] = append(byPath[r.Request.Path], r)
+	}
+
+	for _, path := range paths {
+		spec := buildGraphQLSpec(byPath[path], opts, result)
+		if spec == nil {
+			continue
+		}
+
+		for _, resolver := range spec.Resolvers {
+			result.ResolverCount++
+			result.VariantCount += 1 + len(resolver.Variants)
+		}
+
+		now := time.Now()
+		enabled := true
+		result.Mocks = append(result.Mocks, &config.MockConfiguration{
+			ID:        id.Short(),
+			Name:      "GraphQL " + path,
+			Type:      mock.TypeGraphQL,
+			Enabled:   &enabled,
+			CreatedAt: now,
+			UpdatedAt: now,
+			GraphQL:   spec,
+		})
+	}
+
+	return result
+}
+
+// ToGraphQLMocks converts GraphQL recordings to one mock per endpoint path.
+func ToGraphQLMocks(recordings []*Recording, opts GraphQLConvertOptions) []*config.MockConfiguration {
+	return ConvertGraphQLRecordings(recordings, opts).Mocks
+}
+
+// buildGraphQLSpec converts the recordings of a single endpoint, adding
+// operation summaries and warnings to result.
+func buildGraphQLSpec(recordings []*Recording, opts GraphQLConvertOptions, result *GraphQLConvertResult) *mock.GraphQLSpec {
+	if len(recordings) == 0 {
+		return nil
+	}
+
+	b := &graphqlBuilder{
+		opts:     opts,
+		result:   result,
+		fields:   make(map[string][]mock.ResolverConfig),
+		seen:     make(map[string]map[string]bool),
+		ops:      make(map[string]*GraphQLOperationSummary),
+		inferrer: newSchemaInferrer(),
+	}
+	for _, r := range recordings {
+		b.addRecording(r)
+	}
+	for _, name := range b.opOrder {
+		result.Operations = append(result.Operations, *b.ops[name])
+	}
+	if len(b.fields) == 0 {
+		return nil
+	}
+
+	spec := &mock.GraphQLSpec{
+		Path:          recordings[0].Request.Path,
+		Introspection: true,
+		Resolvers:     make(map[string]mock.ResolverConfig),
+	}
+
+	spec.Schema = b.captured
+	if spec.Schema == "" && opts.InferSchema {
+		spec.Schema = b.inferrer.SDL()
+	}
+
+	// Key resolvers on the schema's root type names when they are custom.
+	roots := map[string]string{"query": "Query", "mutation": "Mutation"}
+	if spec.Schema == "" {
+		b.warn("No schema was captured or inferred; set schema or schemaFile before loading the mock")
+	} else if schema, err := graphql.ParseSchema(spec.Schema); err != nil {
+		b.warn(fmt.Sprintf("Schema for %s does not parse and needs editing: %v", spec.Path, err))
+	} else {
+		if q := schema.AST().Query; q != nil {
+			roots["query"] = q.Name
+		}
+		if m := schema.AST().Mutation; m != nil {
+			roots["mutation"] = m.Name
+		}
+	}
+
+	for _, key := range b.order {
+		configs := b.fields[key]
+
+		// Specific variants must be evaluated before the fallback.
+		sort.SliceStable(configs, func(i, j int) bool {
+			return configs[i].Match != nil && configs[j].Match == nil
+		})
+
+		resolver := configs[0]
+		if len(configs) > 1 {
+			resolver.Variants = configs[1:]
+		}
+
+		opType, field, _ := strings.Cut(key, ".")
+		spec.Resolvers[roots[opType]+"."+field] = resolver
+	}
+
+	return spec
+}
+
+func (b *graphqlBuilder) warn(msg string) {
+	b.result.Warnings = append(b.result.Warnings, msg)
+}
+
+// addRecording adds the root fields of every request in a recording.
+func (b *graphqlBuilder) addRecording(r *Recording) {
+	if r.GraphQLSchema != "" && b.captured == "" {
+		b.captured = r.GraphQLSchema
+	}
+
+	requests := graphqlRequests(r)
+	responses, err := graphqlResponses(r, len(requests) > 1)
+	if err != nil || len(responses) != len(requests) {
+		b.result.Skipped++
+		b.warn("Recording " + r.ID + " has no decodable GraphQL response")
+		return
+	}
+
+	for i, req := range requests {
+		b.addRequest(r, req, responses[i])
+	}
+}
+
+// addRequest converts the root fields selected by a single request.
+func (b *graphqlBuilder) addRequest(r *Recording, req graphql.GraphQLRequest, resp graphqlResponse) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: req.Query})
+	if err != nil {
+		b.result.Skipped++
+		b.warn("Recording " + r.ID + " has a query that does not parse: " + err.Error())
+		return
+	}
+
+	op := selectOperation(doc, req.OperationName)
+	if op == nil {
+		b.result.Skipped++
+		b.warn("Recording " + r.ID + " does not identify which operation to run")
+		return
+	}
+	if op.Operation == ast.Subscription {
+		b.result.Skipped++
+		b.warn("Recording " + r.ID + " is a subscription; configure subscriptions by hand")
+		return
+	}
+
+	fields := rootFields(doc, op.SelectionSet)
+
+	// A recorded introspection query supplies the schema.
+	if isIntrospectionOnly(fields) {
+		if b.captured == "" && resp.Data["__schema"] != nil {
+			data, _ := json.Marshal(map[string]any{"__schema": resp.Data["__schema"]})
+			if sdl, err := graphql.SchemaFromIntrospection(data); err == nil {
+				b.captured = sdl
+			}
+		}
+		return
+	}
+
+	b.inferrer.observeOperation(doc, op, resp.Data, req.Variables)
+	b.summarize(op, req.OperationName, fields)
+
+	opType := string(op.Operation)
+	for _, field := range fields {
+		if strings.HasPrefix(field.Name, "__") {
+			continue
+		}
+		alias := fieldAlias(field)
+		cfg := mock.ResolverConfig{}
+
+		value, present := resp.Data[alias]
+		if fieldErr := rootFieldError(resp.Errors, alias); fieldErr != nil && value == nil {
+			if !b.opts.PreserveErrors {
+				continue
+			}
+			cfg.Error = &mock.GraphQLErrorConfig{
+				Message:    fieldErr.Message,
+				Extensions: fieldErr.Extensions,
+			}
+		} else if !present {
+			continue
+		} else {
+			cfg.Response = unaliasValue(doc, value, field.SelectionSet)
+		}
+
+		if b.opts.IncludeDelay && r.Duration > 0 {
+			cfg.Delay = r.Duration.String()
+		}
+		if b.opts.MatchArgs {
+			if args := fieldArgs(field, req.Variables); len(args) > 0 {
+				cfg.Match = &mock.ResolverMatch{Args: args}
+			}
+		}
+
+		b.addResolver(opType+"."+field.Name, cfg)
+	}
+}
+
+// addResolver adds a resolver config unless one with the same match exists.
+func (b *graphqlBuilder) addResolver(key string, cfg mock.ResolverConfig) {
+	if _, exists := b.fields[key]; !exists {
+		b.order = append(b.order, key)
+		b.seen[key] = make(map[string]bool)
+	}
+
+	matchKey := ""
+	if cfg.Match != nil {
+		data, _ := json.Marshal(cfg.Match)
+		matchKey = string(data)
+	}
+	if b.seen[key][matchKey] {
+		return
+	}
+	b.seen[key][matchKey] = true
+	b.fields[key] = append(b.fields[key], cfg)
+}
+
+// summarize records an operation in the result's operation list.
+func (b *graphqlBuilder) summarize(op *ast.OperationDefinition, operationName string, fields []*ast.Field) {
+	name := operationName
+	if name == "" {
+		name = op.Name
+	}
+	if name == "" {
+		name = "(anonymous)"
+	}
+
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if !strings.HasPrefix(f.Name, "__") {
+			names = append(names, f.Name)
+		}
+	}
+
+	key := string(op.Operation) + " " + name + " " + strings.Join(names, ",")
+	summary, exists := b.ops[key]
+	if !exists {
+		summary = &GraphQLOperationSummary{Name: name, Type: string(op.Operation), Fields: names}
+		b.ops[key] = summary
+		b.opOrder = append(b.opOrder, key)
+	}
+	summary.Count++
+}
+
+// selectOperation picks the operation to run from a document.
+func selectOperation(doc *ast.QueryDocument, name string) *ast.OperationDefinition {
+	if name != "" {
+		return doc.Operations.ForName(name)
+	}
+	if len(doc.Operations) == 1 {
+		return doc.Operations[0]
+	}
+	return nil
+}
+
+// rootFields returns the fields of a selection set with fragments expanded.
+// Fields selected twice under the same alias are returned once.
+func rootFields(doc *ast.QueryDocument, selections ast.SelectionSet) []*ast.Field {
+	fields := make([]*ast.Field, 0)
+	seen := make(map[string]bool)
+	var walk func(ast.SelectionSet, map[string]bool)
+	walk = func(set ast.SelectionSet, visiting map[string]bool) {
+		for _, sel := range set {
+			switch s := sel.(type) {
+			case *ast.Field:
+				alias := fieldAlias(s)
+				if !seen[alias] {
+					seen[alias] = true
+					fields = append(fields, s)
+				}
+			case *ast.InlineFragment:
+				walk(s.SelectionSet, visiting)
+			case *ast.FragmentSpread:
+				frag := doc.Fragments.ForName(s.Name)
+				if frag != nil && !visiting[s.Name] {
+					visiting[s.Name] = true
+					walk(frag.SelectionSet, visiting)
+					delete(visiting, s.Name)
+				}
+			}
+		}
+	}
+	walk(selections, make(map[string]bool))
+	return fields
+}
+
+func fieldAlias(f *ast.Field) string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+func isIntrospectionOnly(fields []*ast.Field) bool {
+	for _, f := range fields {
+		if f.Name != "__schema" && f.Name != "__type" && f.Name != "__typename" {
+			return false
+		}
+	}
+	return len(fields) > 0
+}
+
+// rootFieldError returns the first error whose path starts at alias.
+func rootFieldError(errs []recordedGraphQLError, alias string) *recordedGraphQLError {
+	for i := range errs {
+		if len(errs[i].Path) > 0 && errs[i].Path[0] == alias {
+			return &errs[i]
+		}
+	}
+	return nil
+}
+
+// fieldArgs resolves a field's arguments against the request variables.
+// Null arguments and unset variables are left out so they do not constrain
+// the match.
+func fieldArgs(field *ast.Field, variables map[string]any) map[string]any {
+	args := make(map[string]any)
+	for _, arg := range field.Arguments {
+		value, err := arg.Value.Value(variables)
+		if err != nil || value == nil {
+			continue
+		}
+		args[arg.Name] = value
+	}
+	return args
+}
+
+// unaliasValue rewrites a response value so its keys use field names
+// instead of the aliases the recorded query chose, which is the shape mock
+// resolver responses are expected in.
+func unaliasValue(doc *ast.QueryDocument, value any, selections ast.SelectionSet) any {
+	if len(selections) == 0 {
+		return value
+	}
+
+	switch v := value.(type) {
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = unaliasValue(doc, item, selections)
+		}
+		return out
+
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for _, field := range rootFields(doc, selections) {
+			val, ok := v[fieldAlias(field)]
+			if !ok || field.Name == "__typename" {
+				continue
+			}
+			if _, exists := out[field.Name]; exists {
+				continue
+			}
+			out[field.Name] = unaliasValue(doc, val, field.SelectionSet)
+		}
+		return out
+	}
+
+	return value
+}
@@ -0,0 +1,242 @@
+package recording
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vektah/gqlparser/v2"
+
+	"github.com/getmockd/mockd/pkg/graphql"
+	"github.com/getmockd/mockd/pkg/mock"
+)
+
+// gqlRec builds a recorded POST /graphql call.
+func gqlRec(t *testing.T, query string, variables map[string]any, response string) *Recording {
+	t.Helper()
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := NewRecording("session")
+	rec.Request = RecordedRequest{
+		Method:  http.MethodPost,
+		URL:     "http://api.example.com/graphql",
+		Path:    "/graphql",
+		Host:    "api.example.com",
+		Headers: http.Header{"Content-Type": {"application/json"}},
+		Body:    body,
+	}
+	rec.Response = RecordedResponse{
+		StatusCode: http.StatusOK,
+		Headers:    http.Header{"Content-Type": {"application/json"}},
+		Body:       []byte(response),
+	}
+	return rec
+}
+
+const getUserQuery = `query GetUser($id: ID!) { user(id: $id) { id name posts(first: 2) { title } } }`
+
+func TestIsGraphQLRecording(t *testing.T) {
+	post := gqlRec(t, getUserQuery, nil, `{}`)
+	if !IsGraphQLRecording(post) {
+		t.Error("POST with a JSON query is not detected")
+	}
+
+	get := NewRecording("session")
+	get.Request = RecordedRequest{Method: http.MethodGet, URL: "/graphql?query=%7Bme%7Bid%7D%7D", Path: "/graphql"}
+	if !IsGraphQLRecording(get) {
+		t.Error("GET with a query parameter is not detected")
+	}
+
+	rawBody := NewRecording("session")
+	rawBody.Request = RecordedRequest{
+		Method:  http.MethodPost,
+		Path:    "/graphql",
+		Headers: http.Header{"Content-Type": {"application/graphql"}},
+		Body:    []byte(`{ me { id } }`),
+	}
+	if !IsGraphQLRecording(rawBody) {
+		t.Error("application/graphql body is not detected")
+	}
+
+	search := NewRecording("session")
+	search.Request = RecordedRequest{
+		Method:  http.MethodPost,
+		Path:    "/search",
+		Headers: http.Header{"Content-Type": {"application/json"}},
+		Body:    []byte(`{"query": "red shoes"}`),
+	}
+	if IsGraphQLRecording(search) {
+		t.Error("REST body with a query field is detected as GraphQL")
+	}
+}
+
+func TestConvertGraphQLRecordings_ResolversAndVariants(t *testing.T) {
+	recs := []*Recording{
+		gqlRec(t, getUserQuery, map[string]any{"id": "1"},
+			`{"data":{"user":{"id":"1","name":"Ann","posts":[{"title":"Hello"}]}}}`),
+		// Same arguments, different response: the first recording wins.
+		gqlRec(t, getUserQuery, map[string]any{"id": "1"},
+			`{"data":{"user":{"id":"1","name":"Changed","posts":[]}}}`),
+		gqlRec(t, getUserQuery, map[string]any{"id": "404"},
+			`{"data":{"user":null},"errors":[{"message":"user not found","path":["user"],"extensions":{"code":"NOT_FOUND"}}]}`),
+		// Two root fields, one aliased, with a sub-field alias.
+		gqlRec(t, `{ me: user(id: "2") { id displayName: name } stats { count ratio } }`, nil,
+			`{"data":{"me":{"id":"2","displayName":"Bob"},"stats":{"count":3,"ratio":0.5}}}`),
+		gqlRec(t, `mutation CreateUser($input: CreateUserInput!) { createUser(input: $input) { id } }`,
+			map[string]any{"input": map[string]any{"name": "Cy", "age": 30}},
+			`{"data":{"createUser":{"id":"3"}}}`),
+	}
+
+	result := ConvertGraphQLRecordings(recs, DefaultGraphQLConvertOptions())
+	if len(result.Mocks) != 1 {
+		t.Fatalf("got %d mocks, want 1 (warnings: %v)", len(result.Mocks), result.Warnings)
+	}
+	m := result.Mocks[0]
+	if m.Type != mock.TypeGraphQL || m.GraphQL.Path != "/graphql" || !m.GraphQL.Introspection {
+		t.Errorf("mock = %+v", m)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("Warnings = %v", result.Warnings)
+	}
+	if result.ResolverCount != 3 || result.VariantCount != 5 {
+		t.Errorf("ResolverCount = %d, VariantCount = %d", result.ResolverCount, result.VariantCount)
+	}
+
+	user := m.GraphQL.Resolvers["Query.user"]
+	if user.Match == nil || user.Match.Args["id"] != "1" {
+		t.Fatalf("Query.user Match = %+v", user.Match)
+	}
+	if name := user.Response.(map[string]any)["name"]; name != "Ann" {
+		t.Errorf("Query.user name = %v, want the first recording", name)
+	}
+	if len(user.Variants) != 2 {
+		t.Fatalf("got %d variants, want 2", len(user.Variants))
+	}
+	if e := user.Variants[0].Error; e == nil || e.Message != "user not found" || e.Extensions["code"] != "NOT_FOUND" {
+		t.Errorf("error variant = %+v", user.Variants[0])
+	}
+	aliased := user.Variants[1]
+	if aliased.Match.Args["id"] != "2" || aliased.Response.(map[string]any)["name"] != "Bob" {
+		t.Errorf("aliased variant = %+v, want args and response keyed on field names", aliased)
+	}
+
+	if create := m.GraphQL.Resolvers["Mutation.createUser"]; create.Match == nil ||
+		create.Match.Args["input"].(map[string]any)["name"] != "Cy" {
+		t.Errorf("Mutation.createUser = %+v", create)
+	}
+
+	if len(result.Operations) != 3 {
+		t.Fatalf("Operations = %+v", result.Operations)
+	}
+	if op := result.Operations[0]; op.Name != "GetUser" || op.Type != "query" || op.Count != 3 || op.Fields[0] != "user" {
+		t.Errorf("first operation = %+v", op)
+	}
+
+	// The inferred schema accepts every recorded query.
+	schema, err := graphql.ParseSchema(m.GraphQL.Schema)
+	if err != nil {
+		t.Fatalf("inferred schema does not parse: %v\n%s", err, m.GraphQL.Schema)
+	}
+	for _, rec := range recs {
+		for _, req := range graphqlRequests(rec) {
+			if _, errs := gqlparser.LoadQuery(schema.AST(), req.Query); errs != nil {
+				t.Errorf("query %q does not validate: %v\n%s", req.Query, errs, m.GraphQL.Schema)
+			}
+		}
+	}
+	if got := schema.GetField("User", "posts").Type.String(); got != "[Post]" {
+		t.Errorf("User.posts type = %s, want [Post]", got)
+	}
+	if got := schema.GetField("Stats", "ratio").Type.String(); got != "Float" {
+		t.Errorf("Stats.ratio type = %s, want Float", got)
+	}
+	if !schema.IsInputType("CreateUserInput") || schema.GetType("CreateUserInput").Fields.ForName("age").Type.Name() != "Int" {
+		t.Error("CreateUserInput was not inferred from the variables")
+	}
+}
+
+func TestConvertGraphQLRecordings_SchemaSources(t *testing.T) {
+	const sdl = "type Query {\n\tuser(id: ID!): User\n}\ntype User {\n\tid: ID!\n}\n"
+
+	captured := gqlRec(t, getUserQuery, map[string]any{"id": "1"}, `{"data":{"user":{"id":"1"}}}`)
+	captured.GraphQLSchema = sdl
+	result := ConvertGraphQLRecordings([]*Recording{captured}, DefaultGraphQLConvertOptions())
+	if got := result.Mocks[0].GraphQL.Schema; got != sdl {
+		t.Errorf("Schema = %q, want the captured schema", got)
+	}
+
+	// A recorded introspection response supplies the schema.
+	schema, err := graphql.ParseSchema(sdl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	introspection := graphql.NewExecutor(schema, &graphql.GraphQLConfig{Introspection: true}).
+		Execute(t.Context(), &graphql.GraphQLRequest{Query: graphql.IntrospectionQuery})
+	body, _ := json.Marshal(introspection)
+	introspected := gqlRec(t, graphql.IntrospectionQuery, nil, string(body))
+	plain := gqlRec(t, getUserQuery, map[string]any{"id": "1"}, `{"data":{"user":{"id":"1"}}}`)
+
+	result = ConvertGraphQLRecordings([]*Recording{plain, introspected}, DefaultGraphQLConvertOptions())
+	got := result.Mocks[0].GraphQL.Schema
+	if !strings.Contains(got, "user(id: ID!): User") {
+		t.Errorf("Schema = %q, want the introspected schema", got)
+	}
+	if len(result.Mocks[0].GraphQL.Resolvers) != 1 {
+		t.Errorf("Resolvers = %v, want the introspection query left out", result.Mocks[0].GraphQL.Resolvers)
+	}
+
+	opts := DefaultGraphQLConvertOptions()
+	opts.InferSchema = false
+	result = ConvertGraphQLRecordings([]*Recording{plain}, opts)
+	if result.Mocks[0].GraphQL.Schema != "" || len(result.Warnings) != 1 {
+		t.Errorf("Schema = %q, Warnings = %v", result.Mocks[0].GraphQL.Schema, result.Warnings)
+	}
+}
+
+func TestConvertGraphQLRecordings_SkipsAndOptions(t *testing.T) {
+	failed := gqlRec(t, getUserQuery, map[string]any{"id": "404"},
+		`{"data":{"user":null},"errors":[{"message":"not found","path":["user"]}]}`)
+	subscription := gqlRec(t, `subscription { userCreated { id } }`, nil, `{}`)
+	undecodable := gqlRec(t, getUserQuery, nil, `<html>bad gateway</html>`)
+	ok := gqlRec(t, getUserQuery, nil, `{"data":{"user":{"id":"1"}}}`)
+	ok.Duration = 40 * time.Millisecond
+
+	opts := GraphQLConvertOptions{IncludeDelay: true, InferSchema: true}
+	result := ConvertGraphQLRecordings([]*Recording{failed, subscription, undecodable, ok}, opts)
+	if result.Total != 4 || result.Skipped != 2 || len(result.Warnings) != 2 {
+		t.Errorf("Total = %d, Skipped = %d, Warnings = %v", result.Total, result.Skipped, result.Warnings)
+	}
+	user := result.Mocks[0].GraphQL.Resolvers["Query.user"]
+	if user.Match != nil || user.Error != nil || len(user.Variants) != 0 || user.Delay != "40ms" {
+		t.Errorf("Query.user = %+v, want the successful call only, unmatched, with delay", user)
+	}
+}
+
+func TestConvertGraphQLRecordings_BatchAndPaths(t *testing.T) {
+	batch := gqlRec(t, "", nil, `[{"data":{"a":{"id":"1"}}},{"data":{"b":{"id":"2"}}}]`)
+	batch.Request.Body = []byte(`[{"query":"{ a { id } }"},{"query":"{ b { id } }"}]`)
+	other := gqlRec(t, `{ c }`, nil, `{"data":{"c":true}}`)
+	other.Request.Path = "/admin/graphql"
+
+	result := ConvertGraphQLRecordings([]*Recording{batch, other}, DefaultGraphQLConvertOptions())
+	if len(result.Mocks) != 2 {
+		t.Fatalf("got %d mocks, want one per path", len(result.Mocks))
+	}
+	resolvers := result.Mocks[0].GraphQL.Resolvers
+	if _, ok := resolvers["Query.a"]; !ok {
+		t.Errorf("batch resolvers = %v", resolvers)
+	}
+	if _, ok := resolvers["Query.b"]; !ok {
+		t.Errorf("batch resolvers = %v", resolvers)
+	}
+	if result.Mocks[1].GraphQL.Path != "/admin/graphql" {
+		t.Errorf("second mock path = %s", result.Mocks[1].GraphQL.Path)
+	}
+	if schema, err := graphql.ParseSchema(result.Mocks[1].GraphQL.Schema); err != nil || schema.GetQueryField("c").Type.Name() != "Boolean" {
+		t.Errorf("schema = %q, err = %v", result.Mocks[1].GraphQL.Schema, err)
+	}
+}
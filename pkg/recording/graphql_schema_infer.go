@@ -0,0 +1,416 @@
+package recording
+
+import (
+	"bytes"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/getmockd/mockd/pkg/util"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+)
+
+// schemaInferrer builds an approximate schema from recorded operations. It
+// walks each query's selection set alongside the response data: object
+// types are named after __typename, a fragment's type condition, or the
+// field name; scalar types come from the JSON values; argument types come
+// from variable definitions or literal kinds. Every field is nullable since
+// a single response cannot show otherwise.
+type schemaInferrer struct {
+	types map[string]*inferredType
+	order []string
+}
+
+type inferredType struct {
+	kind   ast.DefinitionKind
+	fields map[string]*inferredField
+	order  []string
+	values []string
+}
+
+type inferredField struct {
+	typ      *ast.Type
+	guessed  bool
+	args     map[string]*ast.Type
+	argOrder []string
+}
+
+// inferContext carries the operation being observed.
+type inferContext struct {
+	doc  *ast.QueryDocument
+	op   *ast.OperationDefinition
+	vars map[string]any
+}
+
+var builtinScalarTypes = map[string]bool{
+	"String":  true,
+	"Int":     true,
+	"Float":   true,
+	"Boolean": true,
+	"ID":      true,
+}
+
+func newSchemaInferrer() *schemaInferrer {
+	return &schemaInferrer{types: make(map[string]*inferredType)}
+}
+
+// typeFor returns the named type, creating it with kind if needed. A type
+// first seen as a scalar is upgraded when it turns out to be an input object.
+func (s *schemaInferrer) typeFor(name string, kind ast.DefinitionKind) *inferredType {
+	t, exists := s.types[name]
+	if !exists {
+		t = &inferredType{kind: kind, fields: make(map[string]*inferredField)}
+		s.types[name] = t
+		s.order = append(s.order, name)
+	} else if t.kind == ast.Scalar && kind == ast.InputObject {
+		t.kind = kind
+	}
+	return t
+}
+
+func (t *inferredType) field(name string) *inferredField {
+	f, exists := t.fields[name]
+	if !exists {
+		f = &inferredField{args: make(map[string]*ast.Type)}
+		t.fields[name] = f
+		t.order = append(t.order, name)
+	}
+	return f
+}
+
+func (t *inferredType) addValue(value string) {
+	for _, v := range t.values {
+		if v == value {
+			return
+		}
+	}
+	t.values = append(t.values, value)
+}
+
+// setType records a field type. Int widens to Float, and a type guessed
+// from a null value is replaced by one seen with data.
+func (f *inferredField) setType(typ *ast.Type, guessed bool) {
+	switch {
+	case typ == nil:
+	case f.typ == nil, f.guessed && !guessed:
+		f.typ = typ
+		f.guessed = guessed
+	case f.typ.Name() == "Int" && typ.Name() == "Float":
+		f.typ = typ
+	}
+}
+
+func (f *inferredField) setArg(name string, typ *ast.Type) {
+	existing, exists := f.args[name]
+	if !exists {
+		f.argOrder = append(f.argOrder, name)
+	}
+	if existing == nil {
+		f.args[name] = typ
+	}
+}
+
+// observeOperation records the types selected by an operation.
+func (s *schemaInferrer) observeOperation(doc *ast.QueryDocument, op *ast.OperationDefinition, data map[string]any, vars map[string]any) {
+	root := "Query"
+	if op.Operation == ast.Mutation {
+		root = "Mutation"
+	}
+	ctx := &inferContext{doc: doc, op: op, vars: vars}
+	s.observeSelection(ctx, root, op.SelectionSet, data)
+}
+
+func (s *schemaInferrer) observeSelection(ctx *inferContext, typeName string, set ast.SelectionSet, value any) {
+	t := s.typeFor(typeName, ast.Object)
+	obj, _ := value.(map[string]any)
+
+	for _, field := range rootFields(ctx.doc, set) {
+		if strings.HasPrefix(field.Name, "__") {
+			continue
+		}
+		f := t.field(field.Name)
+		for _, arg := range field.Arguments {
+			f.setArg(arg.Name, s.literalType(ctx, field.Name, arg.Name, arg.Value))
+		}
+
+		var v any
+		if obj != nil {
+			v = obj[fieldAlias(field)]
+		}
+
+		if len(field.SelectionSet) == 0 {
+			f.setType(s.scalarType(field.Name, v), false)
+			continue
+		}
+
+		elems, depth := flattenList(v)
+		name := objectTypeName(ctx.doc, field, elems, depth)
+		typ := ast.NamedType(name, nil)
+		for i := 0; i < depth; i++ {
+			typ = ast.ListType(typ, nil)
+		}
+		f.setType(typ, v == nil)
+
+		if len(elems) == 0 {
+			s.observeSelection(ctx, name, field.SelectionSet, nil)
+		}
+		for _, elem := range elems {
+			s.observeSelection(ctx, name, field.SelectionSet, elem)
+		}
+	}
+}
+
+// literalType infers the type of an argument value.
+func (s *schemaInferrer) literalType(ctx *inferContext, fieldName, argName string, v *ast.Value) *ast.Type {
+	if v == nil {
+		return nil
+	}
+
+	switch v.Kind {
+	case ast.Variable:
+		def := ctx.op.VariableDefinitions.ForName(v.Raw)
+		if def == nil {
+			return nil
+		}
+		s.observeInput(def.Type, ctx.vars[v.Raw])
+		return def.Type
+	case ast.IntValue:
+		return ast.NamedType("Int", nil)
+	case ast.FloatValue:
+		return ast.NamedType("Float", nil)
+	case ast.StringValue, ast.BlockValue:
+		return ast.NamedType("String", nil)
+	case ast.BooleanValue:
+		return ast.NamedType("Boolean", nil)
+	case ast.EnumValue:
+		name := upperFirst(argName)
+		s.typeFor(name, ast.Enum).addValue(v.Raw)
+		return ast.NamedType(name, nil)
+	case ast.ListValue:
+		for _, child := range v.Children {
+			if elem := s.literalType(ctx, fieldName, argName, child.Value); elem != nil {
+				return ast.ListType(elem, nil)
+			}
+		}
+	case ast.ObjectValue:
+		name := inputTypeName(fieldName, argName)
+		t := s.typeFor(name, ast.InputObject)
+		for _, child := range v.Children {
+			t.field(child.Name).setType(s.literalType(ctx, fieldName, child.Name, child.Value), false)
+		}
+		return ast.NamedType(name, nil)
+	}
+
+	return nil
+}
+
+// observeInput declares the named type of a variable from its value. Input
+// objects get their fields from the value's keys; anything else that is not
+// a built-in scalar (enums, custom scalars) is declared as a custom scalar,
+// which accepts any value.
+func (s *schemaInferrer) observeInput(typ *ast.Type, value any) {
+	if typ.Elem != nil {
+		if items, ok := value.([]any); ok {
+			for _, item := range items {
+				s.observeInput(typ.Elem, item)
+			}
+		} else {
+			s.observeInput(typ.Elem, nil)
+		}
+		return
+	}
+	if builtinScalarTypes[typ.NamedType] {
+		return
+	}
+
+	obj, ok := value.(map[string]any)
+	if !ok {
+		s.typeFor(typ.NamedType, ast.Scalar)
+		return
+	}
+
+	t := s.typeFor(typ.NamedType, ast.InputObject)
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		t.field(k).setType(s.inputValueType(k, obj[k]), false)
+	}
+}
+
+func (s *schemaInferrer) inputValueType(key string, value any) *ast.Type {
+	switch v := value.(type) {
+	case map[string]any:
+		typ := ast.NamedType(upperFirst(key)+"Input", nil)
+		s.observeInput(typ, v)
+		return typ
+	case []any:
+		for _, item := range v {
+			if elem := s.inputValueType(key, item); elem != nil {
+				return ast.ListType(elem, nil)
+			}
+		}
+		return nil
+	}
+	return s.scalarType(key, value)
+}
+
+// scalarType infers a leaf type from a JSON value. Objects under a leaf
+// field are typed as a custom JSON scalar.
+func (s *schemaInferrer) scalarType(name string, value any) *ast.Type {
+	switch v := value.(type) {
+	case string:
+		if name == "id" {
+			return ast.NamedType("ID", nil)
+		}
+		return ast.NamedType("String", nil)
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt32 && v <= math.MaxInt32 {
+			return ast.NamedType("Int", nil)
+		}
+		return ast.NamedType("Float", nil)
+	case bool:
+		return ast.NamedType("Boolean", nil)
+	case []any:
+		for _, item := range v {
+			if elem := s.scalarType(name, item); elem != nil {
+				return ast.ListType(elem, nil)
+			}
+		}
+	case map[string]any:
+		s.typeFor("JSON", ast.Scalar)
+		return ast.NamedType("JSON", nil)
+	}
+	return nil
+}
+
+// SDL formats the inferred types as a schema document.
+func (s *schemaInferrer) SDL() string {
+	if len(s.types) == 0 {
+		return ""
+	}
+	if _, ok := s.types["Query"]; !ok {
+		s.typeFor("Query", ast.Object)
+	}
+
+	names := make([]string, 0, len(s.order))
+	for _, root := range []string{"Query", "Mutation"} {
+		if _, ok := s.types[root]; ok {
+			names = append(names, root)
+		}
+	}
+	for _, name := range s.order {
+		if name != "Query" && name != "Mutation" {
+			names = append(names, name)
+		}
+	}
+
+	doc := &ast.SchemaDocument{}
+	for _, name := range names {
+		t := s.types[name]
+		def := &ast.Definition{Kind: t.kind, Name: name}
+
+		switch t.kind {
+		case ast.Enum:
+			for _, v := range t.values {
+				def.EnumValues = append(def.EnumValues, &ast.EnumValueDefinition{Name: v})
+			}
+		case ast.Object, ast.InputObject:
+			for _, fieldName := range t.order {
+				f := t.fields[fieldName]
+				fd := &ast.FieldDefinition{Name: fieldName, Type: orString(f.typ)}
+				for _, argName := range f.argOrder {
+					fd.Arguments = append(fd.Arguments, &ast.ArgumentDefinition{
+						Name: argName,
+						Type: orString(f.args[argName]),
+					})
+				}
+				def.Fields = append(def.Fields, fd)
+			}
+			// Types must declare at least one field.
+			if len(def.Fields) == 0 {
+				def.Fields = append(def.Fields, &ast.FieldDefinition{Name: "_empty", Type: ast.NamedType("Boolean", nil)})
+			}
+		}
+		doc.Definitions = append(doc.Definitions, def)
+	}
+
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf).FormatSchemaDocument(doc)
+	return buf.String()
+}
+
+// orString returns typ, or String for values that were never seen.
+func orString(typ *ast.Type) *ast.Type {
+	if typ == nil {
+		return ast.NamedType("String", nil)
+	}
+	return typ
+}
+
+// flattenList unwraps nested lists, returning the non-null elements and the
+// list depth.
+func flattenList(value any) ([]map[string]any, int) {
+	switch v := value.(type) {
+	case map[string]any:
+		return []map[string]any{v}, 0
+	case []any:
+		elems := make([]map[string]any, 0, len(v))
+		depth := 1
+		for _, item := range v {
+			inner, d := flattenList(item)
+			elems = append(elems, inner...)
+			if item != nil && d+1 > depth {
+				depth = d + 1
+			}
+		}
+		return elems, depth
+	}
+	return nil, 0
+}
+
+// objectTypeName names an object type from the response's __typename, the
+// type condition of a fragment selected on the field, or the field name.
+func objectTypeName(doc *ast.QueryDocument, field *ast.Field, elems []map[string]any, depth int) string {
+	for _, elem := range elems {
+		if name, ok := elem["__typename"].(string); ok && name != "" {
+			return name
+		}
+	}
+	for _, sel := range field.SelectionSet {
+		switch s := sel.(type) {
+		case *ast.InlineFragment:
+			if s.TypeCondition != "" {
+				return s.TypeCondition
+			}
+		case *ast.FragmentSpread:
+			if frag := doc.Fragments.ForName(s.Name); frag != nil {
+				return frag.TypeCondition
+			}
+		}
+	}
+
+	name := field.Name
+	if depth > 0 {
+		name = util.Singularize(name)
+	}
+	return upperFirst(name)
+}
+
+// inputTypeName names the input object passed as argName to fieldName.
+func inputTypeName(fieldName, argName string) string {
+	if argName == "input" {
+		return upperFirst(fieldName) + "Input"
+	}
+	return upperFirst(argName) + "Input"
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
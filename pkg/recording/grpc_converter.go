@@ -0,0 +1,292 @@
+// Package recording provides conversion from gRPC recordings to mock configurations.
+package recording
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/getmockd/mockd/pkg/mock"
+)
+
+// GRPCConvertOptions configures how gRPC recordings are converted to configs.
+type GRPCConvertOptions struct {
+	// MatchRequest builds Match.Request from the request's scalar fields
+	MatchRequest bool `json:"matchRequest,omitempty"`
+
+	// MatchMetadata lists metadata keys to include in Match.Metadata
+	MatchMetadata []string `json:"matchMetadata,omitempty"`
+
+	// IncludeDelay includes recorded latency as delay
+	IncludeDelay bool `json:"includeDelay,omitempty"`
+
+	// PreserveErrors converts failed calls to Error configs; failed calls
+	// are skipped otherwise
+	PreserveErrors bool `json:"preserveErrors,omitempty"`
+
+	// Port is the port for the generated gRPC mock
+	Port int `json:"port,omitempty"`
+}
+
+// DefaultGRPCConvertOptions returns default conversion options.
+func DefaultGRPCConvertOptions() GRPCConvertOptions {
+	return GRPCConvertOptions{
+		MatchRequest:   true,
+		IncludeDelay:   false,
+		PreserveErrors: true,
+		Port:           50051,
+	}
+}
+
+// ToMethodConfig converts a single gRPC recording to a MethodConfig.
+func ToMethodConfig(rec *GRPCRecording, opts GRPCConvertOptions) *mock.MethodConfig {
+	if rec == nil {
+		return nil
+	}
+
+	cfg := &mock.MethodConfig{}
+
+	if rec.IsError() {
+		cfg.Error = &mock.GRPCErrorConfig{
+			Code:    rec.StatusCode,
+			Message: rec.StatusMessage,
+		}
+	} else {
+		switch rec.StreamType {
+		case "server_streaming", "bidirectional":
+			cfg.Responses = make([]any, 0, len(rec.Responses))
+			for _, resp := range rec.Responses {
+				cfg.Responses = append(cfg.Responses, resp)
+			}
+		default:
+			if len(rec.Responses) > 0 {
+				cfg.Response = rec.Responses[0]
+			}
+		}
+	}
+
+	// Include delay if configured
+	if opts.IncludeDelay && rec.Duration > 0 {
+		cfg.Delay = rec.Duration.String()
+	}
+
+	cfg.Match = grpcMatch(rec, opts)
+
+	return cfg
+}
+
+// grpcMatch builds the match conditions for a recording. The request match
+// uses the message the mock server matches on: the first request of unary
+// and server-streaming calls and the last of client-streaming calls.
+// Bidirectional calls are matched on metadata only.
+func grpcMatch(rec *GRPCRecording, opts GRPCConvertOptions) *mock.MethodMatch {
+	match := &mock.MethodMatch{}
+
+	if opts.MatchRequest && len(rec.Requests) > 0 {
+		var req map[string]any
+		switch rec.StreamType {
+		case "bidirectional":
+		case "client_streaming":
+			req = rec.Requests[len(rec.Requests)-1]
+		default:
+			req = rec.Requests[0]
+		}
+		for key, value := range req {
+			switch value.(type) {
+			case string, float64, bool:
+				if match.Request == nil {
+					match.Request = make(map[string]any)
+				}
+				match.Request[key] = value
+			}
+		}
+	}
+
+	for _, key := range opts.MatchMetadata {
+		key = strings.ToLower(key)
+		if values := rec.Metadata[key]; len(values) > 0 {
+			if match.Metadata == nil {
+				match.Metadata = make(map[string]string)
+			}
+			match.Metadata[key] = values[0]
+		}
+	}
+
+	if len(match.Request) == 0 && len(match.Metadata) == 0 {
+		return nil
+	}
+	return match
+}
+
+// ToGRPCSpec converts recordings to a complete GRPCSpec. Recordings of the
+// same method become match variants; recordings with identical match
+// conditions keep the first, and a recording without conditions becomes the
+// method's fallback.
+func ToGRPCSpec(recordings []*GRPCRecording, opts GRPCConvertOptions) *mock.GRPCSpec {
+	if len(recordings) == 0 {
+		return nil
+	}
+
+	type methodKey struct{ service, method string }
+	groups := make(map[methodKey][]mock.MethodConfig)
+	seen := make(map[methodKey]map[string]bool)
+	order := make([]methodKey, 0)
+
+	for _, rec := range recordings {
+		if rec.IsError() && !opts.PreserveErrors {
+			continue
+		}
+		cfg := ToMethodConfig(rec, opts)
+		if cfg == nil {
+			continue
+		}
+
+		key := methodKey{rec.Service, rec.Method}
+		if _, exists := groups[key]; !exists {
+			order = append(order, key)
+			seen[key] = make(map[string]bool)
+		}
+
+		matchKey := ""
+		if cfg.Match != nil {
+			b, _ := json.Marshal(cfg.Match)
+			matchKey = string(b)
+		}
+		if seen[key][matchKey] {
+			continue
+		}
+		seen[key][matchKey] = true
+		groups[key] = append(groups[key], *cfg)
+	}
+
+	services := make(map[string]mock.ServiceConfig)
+	for _, key := range order {
+		configs := groups[key]
+
+		// Specific variants must be evaluated before the fallback.
+		sort.SliceStable(configs, func(i, j int) bool {
+			return configs[i].Match != nil && configs[j].Match == nil
+		})
+
+		method := configs[0]
+		if len(configs) > 1 {
+			method.Variants = configs[1:]
+		}
+
+		svc, exists := services[key.service]
+		if !exists {
+			svc = mock.ServiceConfig{Methods: make(map[string]mock.MethodConfig)}
+		}
+		svc.Methods[key.method] = method
+		services[key.service] = svc
+	}
+
+	spec := &mock.GRPCSpec{
+		Port:       opts.Port,
+		Services:   services,
+		Reflection: true,
+	}
+	applyGRPCProtoSource(spec, recordings)
+
+	return spec
+}
+
+// applyGRPCProtoSource sets the spec's proto files from the recordings,
+// falling back to proto source fetched with server reflection.
+func applyGRPCProtoSource(spec *mock.GRPCSpec, recordings []*GRPCRecording) {
+	files := make([]string, 0)
+	paths := make([]string, 0)
+	seenFile := make(map[string]bool)
+	seenPath := make(map[string]bool)
+
+	for _, rec := range recordings {
+		for _, f := range rec.ProtoFiles {
+			if !seenFile[f] {
+				seenFile[f] = true
+				files = append(files, f)
+			}
+		}
+		for _, p := range rec.ImportPaths {
+			if !seenPath[p] {
+				seenPath[p] = true
+				paths = append(paths, p)
+			}
+		}
+	}
+
+	if len(files) > 0 {
+		spec.ProtoFiles = files
+		if len(paths) > 0 {
+			spec.ImportPaths = paths
+		}
+		return
+	}
+
+	for _, rec := range recordings {
+		if rec.ProtoContent != "" {
+			spec.ProtoContent = rec.ProtoContent
+			return
+		}
+	}
+}
+
+// GRPCConvertResult contains the result of converting gRPC recordings.
+type GRPCConvertResult struct {
+	Spec         *mock.GRPCSpec `json:"spec"`
+	ServiceCount int            `json:"serviceCount"`
+	MethodCount  int            `json:"methodCount"`
+	VariantCount int            `json:"variantCount"`
+	Total        int            `json:"total"`
+	Warnings     []string       `json:"warnings,omitempty"`
+}
+
+// ConvertGRPCRecordings converts a set of recordings to a GRPCSpec with stats.
+func ConvertGRPCRecordings(recordings []*GRPCRecording, opts GRPCConvertOptions) *GRPCConvertResult {
+	result := &GRPCConvertResult{
+		Total:    len(recordings),
+		Warnings: make([]string, 0),
+	}
+
+	if len(recordings) == 0 {
+		return result
+	}
+
+	result.Spec = ToGRPCSpec(recordings, opts)
+
+	// Count services, methods and variants
+	if result.Spec != nil {
+		result.ServiceCount = len(result.Spec.Services)
+		for _, svc := range result.Spec.Services {
+			result.MethodCount += len(svc.Methods)
+			for _, method := range svc.Methods {
+				result.VariantCount += 1 + len(method.Variants)
+			}
+		}
+	}
+
+	// Add warnings for calls that could not be decoded or described
+	contents := make(map[string]bool)
+	hasFiles := false
+	for _, rec := range recordings {
+		if len(rec.Requests) == 0 && len(rec.Responses) == 0 && !rec.IsError() {
+			result.Warnings = append(result.Warnings,
+				"Recording "+rec.ID+" for "+rec.FullMethod()+" has no decoded messages")
+		}
+		if len(rec.ProtoFiles) > 0 {
+			hasFiles = true
+		}
+		if rec.ProtoContent != "" {
+			contents[rec.ProtoContent] = true
+		}
+	}
+	if result.Spec != nil && result.Spec.ProtoContent != "" && len(contents) > 1 {
+		result.Warnings = append(result.Warnings,
+			"Recordings span several proto files fetched by reflection; only the first is included, add the others with protoFiles")
+	}
+	if result.Spec != nil && !hasFiles && len(contents) == 0 {
+		result.Warnings = append(result.Warnings,
+			"No proto source was recorded; set protoFile or protoContent before loading the mock")
+	}
+
+	return result
+}
@@ -0,0 +1,155 @@
+package recording
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func grpcRec(method, streamType string, req []map[string]any, resp []map[string]any) *GRPCRecording {
+	rec := NewGRPCRecording("users.v1.UserService", method, streamType)
+	rec.Requests = req
+	rec.Responses = resp
+	rec.ProtoFiles = []string{"users.proto"}
+	rec.ImportPaths = []string{"./proto"}
+	return rec
+}
+
+func TestToGRPCSpec_VariantsPerRequest(t *testing.T) {
+	first := grpcRec("GetUser", "unary",
+		[]map[string]any{{"id": "1", "fields": []any{"name"}}},
+		[]map[string]any{{"id": "1", "name": "Ann"}})
+	first.Metadata = map[string][]string{"x-tenant": {"acme"}}
+	duplicate := grpcRec("GetUser", "unary",
+		[]map[string]any{{"id": "1"}},
+		[]map[string]any{{"id": "1", "name": "Changed"}})
+	missing := grpcRec("GetUser", "unary", []map[string]any{{"id": "404"}}, nil)
+	missing.StatusCode = "NOT_FOUND"
+	missing.StatusMessage = "user 404 not found"
+	fallback := grpcRec("GetUser", "unary", nil, []map[string]any{{"id": "0"}})
+
+	spec := ToGRPCSpec([]*GRPCRecording{fallback, first, duplicate, missing}, GRPCConvertOptions{
+		MatchRequest:   true,
+		MatchMetadata:  []string{"X-Tenant"},
+		PreserveErrors: true,
+		Port:           50051,
+	})
+	if spec == nil {
+		t.Fatal("ToGRPCSpec() = nil")
+	}
+	if spec.Port != 50051 || !spec.Reflection {
+		t.Errorf("Port = %d, Reflection = %v", spec.Port, spec.Reflection)
+	}
+	if len(spec.ProtoFiles) != 1 || spec.ProtoFiles[0] != "users.proto" || len(spec.ImportPaths) != 1 {
+		t.Errorf("ProtoFiles = %v, ImportPaths = %v", spec.ProtoFiles, spec.ImportPaths)
+	}
+
+	method := spec.Services["users.v1.UserService"].Methods["GetUser"]
+	if method.Match == nil || method.Match.Request["id"] != "1" || method.Match.Metadata["x-tenant"] != "acme" {
+		t.Fatalf("primary Match = %+v", method.Match)
+	}
+	if _, ok := method.Match.Request["fields"]; ok {
+		t.Error("Match.Request includes a repeated field")
+	}
+	if resp := method.Response.(map[string]any); resp["name"] != "Ann" {
+		t.Errorf("primary Response = %v", resp)
+	}
+
+	// duplicate has no tenant metadata, so it is a distinct variant
+	if len(method.Variants) != 3 {
+		t.Fatalf("got %d variants, want 3", len(method.Variants))
+	}
+	if e := method.Variants[1].Error; e == nil || e.Code != "NOT_FOUND" || e.Message != "user 404 not found" {
+		t.Errorf("error variant = %+v", method.Variants[1])
+	}
+	if last := method.Variants[2]; last.Match != nil {
+		t.Errorf("fallback variant is not last: %+v", method.Variants)
+	}
+}
+
+func TestToGRPCSpec_Deduplicates(t *testing.T) {
+	a := grpcRec("GetUser", "unary", []map[string]any{{"id": "1"}}, []map[string]any{{"name": "Ann"}})
+	b := grpcRec("GetUser", "unary", []map[string]any{{"id": "1"}}, []map[string]any{{"name": "Bob"}})
+
+	spec := ToGRPCSpec([]*GRPCRecording{a, b}, DefaultGRPCConvertOptions())
+	method := spec.Services["users.v1.UserService"].Methods["GetUser"]
+	if len(method.Variants) != 0 || method.Response.(map[string]any)["name"] != "Ann" {
+		t.Errorf("method = %+v, want the first recording only", method)
+	}
+}
+
+func TestToMethodConfig_StreamTypes(t *testing.T) {
+	reqs := []map[string]any{{"id": "1"}, {"id": "2"}}
+	resps := []map[string]any{{"n": float64(1)}, {"n": float64(2)}}
+
+	server := ToMethodConfig(grpcRec("ListUsers", "server_streaming", reqs[:1], resps), DefaultGRPCConvertOptions())
+	if len(server.Responses) != 2 || server.Response != nil || server.Match.Request["id"] != "1" {
+		t.Errorf("server streaming = %+v", server)
+	}
+
+	client := ToMethodConfig(grpcRec("Upload", "client_streaming", reqs, resps[:1]), DefaultGRPCConvertOptions())
+	if client.Response == nil || client.Match.Request["id"] != "2" {
+		t.Errorf("client streaming = %+v, want the last request matched", client)
+	}
+
+	bidi := ToMethodConfig(grpcRec("Chat", "bidirectional", reqs, resps), DefaultGRPCConvertOptions())
+	if len(bidi.Responses) != 2 || bidi.Match != nil {
+		t.Errorf("bidirectional = %+v, want no request match", bidi)
+	}
+
+	rec := grpcRec("GetUser", "unary", reqs[:1], resps[:1])
+	rec.Duration = 25 * time.Millisecond
+	if cfg := ToMethodConfig(rec, GRPCConvertOptions{IncludeDelay: true}); cfg.Delay != "25ms" || cfg.Match != nil {
+		t.Errorf("Delay = %q, Match = %+v", cfg.Delay, cfg.Match)
+	}
+}
+
+func TestConvertGRPCRecordings(t *testing.T) {
+	ok := grpcRec("GetUser", "unary", []map[string]any{{"id": "1"}}, []map[string]any{{"id": "1"}})
+	failed := grpcRec("DeleteUser", "unary", []map[string]any{{"id": "1"}}, nil)
+	failed.StatusCode = "PERMISSION_DENIED"
+	undecoded := NewGRPCRecording("other.v1.Other", "Ping", "unary")
+
+	result := ConvertGRPCRecordings([]*GRPCRecording{ok, failed, undecoded}, GRPCConvertOptions{MatchRequest: true})
+	if result.Total != 3 || result.ServiceCount != 2 || result.MethodCount != 2 {
+		t.Errorf("result = %+v", result)
+	}
+	if _, ok := result.Spec.Services["users.v1.UserService"].Methods["DeleteUser"]; ok {
+		t.Error("failed call converted without PreserveErrors")
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], undecoded.ID) {
+		t.Errorf("Warnings = %v", result.Warnings)
+	}
+
+	reflected := NewGRPCRecording("other.v1.Other", "Ping", "unary")
+	reflected.Responses = []map[string]any{{}}
+	reflected.ProtoContent = "syntax = \"proto3\";"
+	result = ConvertGRPCRecordings([]*GRPCRecording{reflected}, DefaultGRPCConvertOptions())
+	if result.Spec.ProtoContent != reflected.ProtoContent || len(result.Spec.ProtoFiles) != 0 {
+		t.Errorf("Spec = %+v, want the reflected proto content", result.Spec)
+	}
+
+	if empty := ConvertGRPCRecordings(nil, DefaultGRPCConvertOptions()); empty.Spec != nil || empty.Total != 0 {
+		t.Errorf("empty result = %+v", empty)
+	}
+}
+
+func TestRedactor_RedactGRPC(t *testing.T) {
+	r := mustRedactor(t, "",
+		RedactionRule{Header: "Authorization", Action: RedactMask},
+		RedactionRule{JSONPath: "$.password", Action: RedactDrop},
+	)
+
+	rec := grpcRec("Login", "unary",
+		[]map[string]any{{"user": "ann", "password": "hunter2"}},
+		[]map[string]any{{"token": "t"}})
+	rec.Metadata = map[string][]string{"authorization": {"Bearer secret"}}
+	r.RedactGRPC(rec)
+
+	if got := rec.Metadata["authorization"]; len(got) != 1 || got[0] != DefaultRedactMask {
+		t.Errorf("authorization = %v, want masked", got)
+	}
+	if _, ok := rec.Requests[0]["password"]; ok || rec.Requests[0]["user"] != "ann" {
+		t.Errorf("request = %v, want only password dropped", rec.Requests[0])
+	}
+}
@@ -0,0 +1,108 @@
+// Package recording provides types for gRPC call recording.
+package recording
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+)
+
+// GRPCRecording represents a captured gRPC call. Streaming calls hold every
+// message in order; messages are in their protojson form.
+type GRPCRecording struct {
+	ID        string    `json:"id"`
+	Timestamp time.Time `json:"timestamp"`
+
+	// Service is the fully qualified service name, e.g. "users.v1.UserService"
+	Service string `json:"service"`
+
+	// Method is the method name, e.g. "GetUser"
+	Method string `json:"method"`
+
+	// StreamType is unary, server_streaming, client_streaming or bidirectional
+	StreamType string `json:"streamType"`
+
+	// Metadata is the request metadata sent by the client
+	Metadata map[string][]string `json:"metadata,omitempty"`
+
+	// Requests are the messages sent by the client
+	Requests []map[string]any `json:"requests,omitempty"`
+
+	// Responses are the messages sent by the server
+	Responses []map[string]any `json:"responses,omitempty"`
+
+	// ResponseHeaders and Trailers are the metadata sent by the server
+	ResponseHeaders map[string][]string `json:"responseHeaders,omitempty"`
+	Trailers        map[string][]string `json:"trailers,omitempty"`
+
+	// StatusCode is the gRPC status code name, e.g. "OK" or "NOT_FOUND"
+	StatusCode string `json:"statusCode"`
+
+	// StatusMessage is the error message of a failed call
+	StatusMessage string `json:"statusMessage,omitempty"`
+
+	// Duration is the time taken for the call
+	Duration time.Duration `json:"duration"`
+
+	// ProtoFiles and ImportPaths describe the service when the recording
+	// proxy was given proto files
+	ProtoFiles  []string `json:"protoFiles,omitempty"`
+	ImportPaths []string `json:"importPaths,omitempty"`
+
+	// ProtoContent is the service's proto source when it was fetched with
+	// server reflection
+	ProtoContent string `json:"protoContent,omitempty"`
+}
+
+// GRPCRecordingFilter defines filtering options for gRPC recordings.
+type GRPCRecordingFilter struct {
+	// Service filters by fully qualified service name
+	Service string `json:"service,omitempty"`
+
+	// Method filters by method name
+	Method string `json:"method,omitempty"`
+
+	// StatusCode filters by status code name
+	StatusCode string `json:"statusCode,omitempty"`
+
+	// Limit is the maximum number of recordings to return
+	Limit int `json:"limit,omitempty"`
+
+	// Offset is the number of recordings to skip
+	Offset int `json:"offset,omitempty"`
+}
+
+// NewGRPCRecording creates a new gRPC recording with a unique ID.
+func NewGRPCRecording(service, method, streamType string) *GRPCRecording {
+	return &GRPCRecording{
+		ID:         generateGRPCID(),
+		Timestamp:  time.Now(),
+		Service:    service,
+		Method:     method,
+		StreamType: streamType,
+		StatusCode: "OK",
+	}
+}
+
+// generateGRPCID generates a unique identifier for gRPC recordings.
+func generateGRPCID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("grpc-%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// GetID returns the recording ID (implements Recordable).
+func (r GRPCRecording) GetID() string { return r.ID }
+
+// GetTimestamp returns the recording timestamp (implements Recordable).
+func (r GRPCRecording) GetTimestamp() time.Time { return r.Timestamp }
+
+// FullMethod returns the call's path, e.g. "/users.v1.UserService/GetUser".
+func (r *GRPCRecording) FullMethod() string {
+	return "/" + r.Service + "/" + r.Method
+}
+
+// IsError returns true if the call ended with a non-OK status.
+func (r *GRPCRecording) IsError() bool {
+	return r.StatusCode != "" && r.StatusCode != "OK"
+}
@@ -0,0 +1,82 @@
+// Package recording provides storage for gRPC recordings.
+package recording
+
+import (
+	"time"
+)
+
+// GRPCStore provides storage for gRPC recordings.
+type GRPCStore struct {
+	*RecordingStore[GRPCRecording]
+}
+
+// NewGRPCStore creates a new gRPC recording store.
+func NewGRPCStore(maxSize int) *GRPCStore {
+	return &GRPCStore{NewRecordingStore[GRPCRecording](maxSize, "grpc_")}
+}
+
+// NewGRPCStoreWithDir creates a new gRPC store with persistent storage.
+func NewGRPCStoreWithDir(maxSize int, dataDir string) (*GRPCStore, error) {
+	s, err := NewRecordingStoreWithDir[GRPCRecording](maxSize, dataDir, "grpc_")
+	if err != nil {
+		return nil, err
+	}
+	return &GRPCStore{s}, nil
+}
+
+// List returns recordings matching the filter.
+func (s *GRPCStore) List(filter GRPCRecordingFilter) ([]*GRPCRecording, int) {
+	return s.ListFiltered(func(r *GRPCRecording) bool {
+		if filter.Service != "" && r.Service != filter.Service {
+			return false
+		}
+		if filter.Method != "" && r.Method != filter.Method {
+			return false
+		}
+		if filter.StatusCode != "" && r.StatusCode != filter.StatusCode {
+			return false
+		}
+		return true
+	}, filter.Offset, filter.Limit)
+}
+
+// ListByService returns recordings for a specific service.
+func (s *GRPCStore) ListByService(service string) []*GRPCRecording {
+	recordings, _ := s.List(GRPCRecordingFilter{Service: service})
+	return recordings
+}
+
+// GRPCRecordingStats contains statistics about gRPC recordings.
+type GRPCRecordingStats struct {
+	TotalRecordings int            `json:"totalRecordings"`
+	ByService       map[string]int `json:"byService"`
+	ByMethod        map[string]int `json:"byMethod"`
+	ByStreamType    map[string]int `json:"byStreamType"`
+	ByStatusCode    map[string]int `json:"byStatusCode"`
+	OldestTimestamp *time.Time     `json:"oldestTimestamp,omitempty"`
+	NewestTimestamp *time.Time     `json:"newestTimestamp,omitempty"`
+}
+
+// Stats returns statistics about the recordings.
+func (s *GRPCStore) Stats() *GRPCRecordingStats {
+	all := s.All()
+
+	stats := &GRPCRecordingStats{
+		TotalRecordings: len(all),
+		ByService:       make(map[string]int),
+		ByMethod:        make(map[string]int),
+		ByStreamType:    make(map[string]int),
+		ByStatusCode:    make(map[string]int),
+	}
+
+	for _, r := range all {
+		stats.ByService[r.Service]++
+		stats.ByMethod[r.FullMethod()]++
+		stats.ByStreamType[r.StreamType]++
+		stats.ByStatusCode[r.StatusCode]++
+
+		UpdateTimestampRange(&stats.OldestTimestamp, &stats.NewestTimestamp, r.Timestamp)
+	}
+
+	return stats
+}
@@ -21,6 +21,7 @@ type DiskSessionMeta struct {
 	EndTime        string   `json:"endTime,omitempty"`
 	Port           int      `json:"port"`
 	Mode           string   `json:"mode"`
+	Upstream       string   `json:"upstream,omitempty"`
 	RecordingCount int      `json:"recordingCount"`
 	Hosts          []string `json:"hosts,omitempty"`
 	Filters        *struct {
@@ -18,6 +18,10 @@ type Recording struct {
 	Response RecordedResponse `json:"response"`
 
 	Duration time.Duration `json:"duration"`
+
+	// GraphQLSchema holds the upstream schema as SDL when the proxy fetched
+	// it through introspection while recording GraphQL traffic.
+	GraphQLSchema string `json:"graphqlSchema,omitempty"`
 }
 
 // RecordedRequest represents the captured request details.
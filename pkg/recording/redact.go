@@ -0,0 +1,540 @@
+// Package recording provides capture-time redaction for recordings.
+package recording
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	mathrand "math/rand/v2"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/beevik/etree"
+	"github.com/getmockd/mockd/pkg/template"
+	"github.com/ohler55/ojg/jp"
+)
+
+// RedactAction is what a redaction rule does to a matched value.
+type RedactAction string
+
+const (
+	// RedactDrop removes the header, cookie, query parameter, JSON field or
+	// XML element; regex matches are replaced with nothing.
+	RedactDrop RedactAction = "drop"
+	// RedactMask replaces the value with a fixed string.
+	RedactMask RedactAction = "mask"
+	// RedactHash replaces the value with a keyed hash of it.
+	RedactHash RedactAction = "hash"
+	// RedactFake replaces the value with a faker value derived from it.
+	RedactFake RedactAction = "fake"
+)
+
+// DefaultRedactMask is the replacement of the mask action.
+const DefaultRedactMask = "[REDACTED]"
+
+// RedactionConfig configures the redaction applied to recordings before
+// they are stored or written to disk.
+type RedactionConfig struct {
+	Rules []RedactionRule `json:"rules" yaml:"rules"`
+	// Salt keys the hash and fake actions. The same salt maps the same value
+	// to the same output in every session; without one, outputs are stable
+	// within a session only.
+	Salt string `json:"salt,omitempty" yaml:"salt,omitempty"`
+}
+
+// RedactionRule selects values with exactly one target field and applies
+// an action to them. Hash and fake outputs are stable per original value, so
+// an ID redacted in one response still matches the same ID elsewhere.
+type RedactionRule struct {
+	// Header names a request or response header (case-insensitive).
+	Header string `json:"header,omitempty" yaml:"header,omitempty"`
+	// Cookie names a cookie in Cookie and Set-Cookie headers.
+	Cookie string `json:"cookie,omitempty" yaml:"cookie,omitempty"`
+	// Query names a URL query parameter.
+	Query string `json:"query,omitempty" yaml:"query,omitempty"`
+	// JSONPath selects values in JSON bodies and payloads, e.g. $..email.
+	JSONPath string `json:"jsonPath,omitempty" yaml:"jsonPath,omitempty"`
+	// XPath selects elements, or an attribute with /@name, in XML bodies
+	// such as SOAP envelopes, e.g. //Password.
+	XPath string `json:"xpath,omitempty" yaml:"xpath,omitempty"`
+	// Regex matches text in bodies and payloads. With a capture group, only
+	// the first group is redacted.
+	Regex string `json:"regex,omitempty" yaml:"regex,omitempty"`
+
+	Action RedactAction `json:"action" yaml:"action"`
+	// Mask is the replacement of the mask action (default "[REDACTED]").
+	Mask string `json:"mask,omitempty" yaml:"mask,omitempty"`
+	// Fake is the faker type of the fake action, e.g. "email" or "name".
+	Fake string `json:"fake,omitempty" yaml:"fake,omitempty"`
+}
+
+// Redactor applies a RedactionConfig. A nil Redactor redacts nothing.
+type Redactor struct {
+	rules  []redactRule
+	key    []byte
+	engine *template.Engine
+}
+
+type redactRule struct {
+	RedactionRule
+	jsonPath jp.Expr
+	regex    *regexp.Regexp
+}
+
+// NewRedactor validates a redaction config. It returns nil when the config
+// has no rules.
+func NewRedactor(cfg *RedactionConfig) (*Redactor, error) {
+	if cfg == nil || len(cfg.Rules) == 0 {
+		return nil, nil
+	}
+
+	r := &Redactor{engine: template.New()}
+	if cfg.Salt != "" {
+		r.key = []byte(cfg.Salt)
+	} else {
+		r.key = make([]byte, 32)
+		_, _ = rand.Read(r.key)
+	}
+
+	for i, rule := range cfg.Rules {
+		compiled, err := r.compile(rule)
+		if err != nil {
+			return nil, fmt.Errorf("redaction rule %d: %w", i, err)
+		}
+		r.rules = append(r.rules, compiled)
+	}
+	return r, nil
+}
+
+func (r *Redactor) compile(rule RedactionRule) (redactRule, error) {
+	compiled := redactRule{RedactionRule: rule}
+
+	targets := 0
+	for _, t := range []string{rule.Header, rule.Cookie, rule.Query, rule.JSONPath, rule.XPath, rule.Regex} {
+		if t != "" {
+			targets++
+		}
+	}
+	if targets != 1 {
+		return compiled, errors.New("set exactly one of header, cookie, query, jsonPath, xpath or regex")
+	}
+
+	switch rule.Action {
+	case RedactDrop, RedactMask, RedactHash:
+	case RedactFake:
+		if rule.Fake == "" {
+			return compiled, errors.New("fake action requires a faker type")
+		}
+		if out, _ := r.engine.Process("{{faker."+rule.Fake+"}}", &template.Context{}); out == "" {
+			return compiled, fmt.Errorf("unknown faker type %q", rule.Fake)
+		}
+	default:
+		return compiled, fmt.Errorf("invalid action %q (must be drop, mask, hash or fake)", rule.Action)
+	}
+
+	var err error
+	if rule.JSONPath != "" {
+		if compiled.jsonPath, err = jp.ParseString(rule.JSONPath); err != nil {
+			return compiled, fmt.Errorf("invalid jsonPath %q: %w", rule.JSONPath, err)
+		}
+	}
+	if rule.XPath != "" {
+		if _, err = etree.CompilePath(xpathElement(rule.XPath)); err != nil {
+			return compiled, fmt.Errorf("invalid xpath %q: %w", rule.XPath, err)
+		}
+	}
+	if rule.Regex != "" {
+		if compiled.regex, err = regexp.Compile(rule.Regex); err != nil {
+			return compiled, fmt.Errorf("invalid regex %q: %w", rule.Regex, err)
+		}
+	}
+	return compiled, nil
+}
+
+// replace returns the redacted form of a value.
+func (r *Redactor) replace(rule *redactRule, value string) string {
+	switch rule.Action {
+	case RedactHash:
+		sum := r.sum(value)
+		return hex.EncodeToString(sum[:16])
+	case RedactFake:
+		sum := r.sum(rule.Fake + "\x00" + value)
+		rng := mathrand.New(mathrand.NewPCG(binary.LittleEndian.Uint64(sum[:8]), binary.LittleEndian.Uint64(sum[8:16])))
+		out, _ := r.engine.Process("{{faker."+rule.Fake+"}}", &template.Context{Rand: rng})
+		return out
+	case RedactDrop:
+		return ""
+	default:
+		if rule.Mask != "" {
+			return rule.Mask
+		}
+		return DefaultRedactMask
+	}
+}
+
+func (r *Redactor) sum(value string) []byte {
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+// RedactRecording redacts an HTTP recording in place.
+func (r *Redactor) RedactRecording(rec *Recording) {
+	if r == nil || rec == nil {
+		return
+	}
+	r.redactHeaders(rec.Request.Headers, "Cookie")
+	r.redactHeaders(rec.Response.Headers, "Set-Cookie")
+	if u, err := url.Parse(rec.Request.URL); err == nil && u.RawQuery != "" {
+		if query, changed := r.redactQuery(u.RawQuery); changed {
+			u.RawQuery = query
+			rec.Request.URL = u.String()
+		}
+	}
+	rec.Request.Body = r.redactBody(rec.Request.Body, rec.Request.Headers.Get("Content-Encoding"))
+	rec.Response.Body = r.redactBody(rec.Response.Body, rec.Response.Headers.Get("Content-Encoding"))
+}
+
+// RedactSOAP redacts a SOAP recording in place.
+func (r *Redactor) RedactSOAP(rec *SOAPRecording) {
+	if r == nil || rec == nil {
+		return
+	}
+	rec.RequestHeaders = r.RedactHeaderMap(rec.RequestHeaders)
+	rec.ResponseHeaders = r.RedactHeaderMap(rec.ResponseHeaders)
+	rec.RequestBody = string(r.redactBody([]byte(rec.RequestBody), ""))
+	rec.ResponseBody = string(r.redactBody([]byte(rec.ResponseBody), ""))
+}
+
+// RedactMQTT redacts an MQTT recording's payload in place.
+func (r *Redactor) RedactMQTT(rec *MQTTRecording) {
+	if r == nil || rec == nil {
+		return
+	}
+	rec.Payload = r.redactBody(rec.Payload, "")
+}
+
+// RedactGRPC redacts a gRPC recording's metadata and messages in place.
+func (r *Redactor) RedactGRPC(rec *GRPCRecording) {
+	if r == nil || rec == nil {
+		return
+	}
+	r.redactHeaders(http.Header(rec.Metadata), "Cookie")
+	r.redactHeaders(http.Header(rec.ResponseHeaders), "Set-Cookie")
+	r.redactHeaders(http.Header(rec.Trailers), "Set-Cookie")
+	rec.Requests = r.redactMessages(rec.Requests)
+	rec.Responses = r.redactMessages(rec.Responses)
+}
+
+// redactMessages redacts decoded messages through their JSON form.
+func (r *Redactor) redactMessages(msgs []map[string]any) []map[string]any {
+	for i, msg := range msgs {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			continue
+		}
+		var redacted map[string]any
+		if err := json.Unmarshal(r.redactBody(data, ""), &redacted); err == nil {
+			msgs[i] = redacted
+		}
+	}
+	return msgs
+}
+
+// RedactPayload redacts a message body such as a WebSocket text frame or an
+// SSE event's data.
+func (r *Redactor) RedactPayload(data []byte) []byte {
+	if r == nil {
+		return data
+	}
+	return r.redactBody(data, "")
+}
+
+// RedactHeaderMap redacts single-valued headers, as stream and SOAP
+// recordings store them.
+func (r *Redactor) RedactHeaderMap(headers map[string]string) map[string]string {
+	if r == nil || len(headers) == 0 {
+		return headers
+	}
+	h := make(http.Header, len(headers))
+	for k, v := range headers {
+		h[k] = []string{v}
+	}
+	r.redactHeaders(h, "Cookie")
+	r.redactHeaders(h, "Set-Cookie")
+
+	out := make(map[string]string, len(h))
+	for k, v := range h {
+		if len(v) > 0 {
+			out[k] = v[0]
+		}
+	}
+	return out
+}
+
+// redactHeaders applies header rules, then cookie rules to the named cookie
+// header (Cookie for requests, Set-Cookie for responses).
+func (r *Redactor) redactHeaders(h http.Header, cookieHeader string) {
+	if h == nil {
+		return
+	}
+	for i := range r.rules {
+		rule := &r.rules[i]
+		switch {
+		case rule.Header != "":
+			for key, values := range h {
+				if !strings.EqualFold(key, rule.Header) {
+					continue
+				}
+				if rule.Action == RedactDrop {
+					delete(h, key)
+					continue
+				}
+				for j, v := range values {
+					values[j] = r.replace(rule, v)
+				}
+			}
+		case rule.Cookie != "":
+			for key, values := range h {
+				if !strings.EqualFold(key, cookieHeader) {
+					continue
+				}
+				var kept []string
+				for _, v := range values {
+					if cookieHeader == "Set-Cookie" {
+						v = r.redactSetCookie(rule, v)
+					} else {
+						v = r.redactCookie(rule, v)
+					}
+					if v != "" {
+						kept = append(kept, v)
+					}
+				}
+				if len(kept) == 0 {
+					delete(h, key)
+				} else {
+					h[key] = kept
+				}
+			}
+		}
+	}
+}
+
+// redactCookie redacts one cookie in a Cookie header value.
+func (r *Redactor) redactCookie(rule *redactRule, header string) string {
+	pairs := strings.Split(header, ";")
+	kept := pairs[:0]
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || name != rule.Cookie {
+			kept = append(kept, strings.TrimSpace(pair))
+			continue
+		}
+		if rule.Action != RedactDrop {
+			kept = append(kept, name+"="+r.replace(rule, value))
+		}
+	}
+	return strings.Join(kept, "; ")
+}
+
+// redactSetCookie redacts a Set-Cookie header value, returning "" to drop it.
+func (r *Redactor) redactSetCookie(rule *redactRule, header string) string {
+	pair, attrs, _ := strings.Cut(header, ";")
+	name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+	if !ok || name != rule.Cookie {
+		return header
+	}
+	if rule.Action == RedactDrop {
+		return ""
+	}
+	out := name + "=" + r.replace(rule, value)
+	if attrs != "" {
+		out += ";" + attrs
+	}
+	return out
+}
+
+// redactQuery applies query rules to a raw query string.
+func (r *Redactor) redactQuery(rawQuery string) (string, bool) {
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return rawQuery, false
+	}
+	changed := false
+	for i := range r.rules {
+		rule := &r.rules[i]
+		if rule.Query == "" {
+			continue
+		}
+		vs, ok := values[rule.Query]
+		if !ok {
+			continue
+		}
+		changed = true
+		if rule.Action == RedactDrop {
+			values.Del(rule.Query)
+			continue
+		}
+		for j, v := range vs {
+			vs[j] = r.replace(rule, v)
+		}
+	}
+	if !changed {
+		return rawQuery, false
+	}
+	return values.Encode(), true
+}
+
+// redactBody applies JSONPath rules to JSON, XPath rules to XML and regex
+// rules to any text. Compressed and binary bodies are left alone.
+func (r *Redactor) redactBody(body []byte, contentEncoding string) []byte {
+	if len(body) == 0 || (contentEncoding != "" && !strings.EqualFold(contentEncoding, "identity")) || !utf8.Valid(body) {
+		return body
+	}
+
+	trimmed := bytes.TrimSpace(body)
+	switch {
+	case len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '['):
+		body = r.redactJSON(body)
+	case len(trimmed) > 0 && trimmed[0] == '<':
+		body = r.redactXML(body)
+	}
+
+	for i := range r.rules {
+		rule := &r.rules[i]
+		if rule.regex == nil {
+			continue
+		}
+		body = rule.regex.ReplaceAllFunc(body, func(match []byte) []byte {
+			sub := rule.regex.FindSubmatchIndex(match)
+			if len(sub) < 4 || sub[2] < 0 {
+				return []byte(r.replace(rule, string(match)))
+			}
+			out := append([]byte{}, match[:sub[2]]...)
+			out = append(out, r.replace(rule, string(match[sub[2]:sub[3]]))...)
+			return append(out, match[sub[3]:]...)
+		})
+	}
+	return body
+}
+
+func (r *Redactor) redactJSON(body []byte) []byte {
+	var data any
+	changed := false
+	for i := range r.rules {
+		rule := &r.rules[i]
+		if rule.jsonPath == nil {
+			continue
+		}
+		if data == nil {
+			dec := json.NewDecoder(bytes.NewReader(body))
+			dec.UseNumber()
+			if err := dec.Decode(&data); err != nil {
+				return body
+			}
+		}
+		if len(rule.jsonPath.Get(data)) == 0 {
+			continue
+		}
+		changed = true
+		if rule.Action == RedactDrop {
+			data, _ = rule.jsonPath.Remove(data)
+			continue
+		}
+		data, _ = rule.jsonPath.Modify(data, func(element any) (any, bool) {
+			switch v := element.(type) {
+			case map[string]any, []any:
+				return r.replace(rule, jsonString(v)), true
+			case string:
+				return r.replace(rule, v), true
+			default:
+				return r.replace(rule, fmt.Sprint(v)), true
+			}
+		})
+	}
+	if !changed {
+		return body
+	}
+	out, err := json.Marshal(data)
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+func jsonString(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func (r *Redactor) redactXML(body []byte) []byte {
+	var doc *etree.Document
+	changed := false
+	for i := range r.rules {
+		rule := &r.rules[i]
+		if rule.XPath == "" {
+			continue
+		}
+		if doc == nil {
+			doc = etree.NewDocument()
+			if err := doc.ReadFromBytes(body); err != nil {
+				return body
+			}
+		}
+		attr := xpathAttr(rule.XPath)
+		for _, elem := range doc.FindElements(xpathElement(rule.XPath)) {
+			switch {
+			case attr != "":
+				a := elem.SelectAttr(attr)
+				if a == nil {
+					continue
+				}
+				if rule.Action == RedactDrop {
+					elem.RemoveAttr(attr)
+				} else {
+					a.Value = r.replace(rule, a.Value)
+				}
+			case rule.Action == RedactDrop:
+				if parent := elem.Parent(); parent != nil {
+					parent.RemoveChild(elem)
+				}
+			default:
+				elem.SetText(r.replace(rule, strings.TrimSpace(elem.Text())))
+			}
+			changed = true
+		}
+	}
+	if !changed {
+		return body
+	}
+	out, err := doc.WriteToBytes()
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+// xpathElement returns the element part of an XPath that may end in /@attr.
+func xpathElement(xpath string) string {
+	if i := strings.LastIndex(xpath, "/@"); i >= 0 {
+		return xpath[:i]
+	}
+	return xpath
+}
+
+// xpathAttr returns the attribute an XPath ends in, if any.
+func xpathAttr(xpath string) string {
+	if i := strings.LastIndex(xpath, "/@"); i >= 0 {
+		return xpath[i+2:]
+	}
+	return ""
+}
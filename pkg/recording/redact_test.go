@@ -0,0 +1,200 @@
+package recording
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func mustRedactor(t *testing.T, salt string, rules ...RedactionRule) *Redactor {
+	t.Helper()
+	r, err := NewRedactor(&RedactionConfig{Rules: rules, Salt: salt})
+	if err != nil {
+		t.Fatalf("NewRedactor() error = %v", err)
+	}
+	return r
+}
+
+func TestNewRedactor_Validation(t *testing.T) {
+	if r, err := NewRedactor(nil); r != nil || err != nil {
+		t.Errorf("NewRedactor(nil) = %v, %v, want nil, nil", r, err)
+	}
+
+	tests := []struct {
+		name string
+		rule RedactionRule
+	}{
+		{"no target", RedactionRule{Action: RedactMask}},
+		{"two targets", RedactionRule{Header: "Authorization", Query: "token", Action: RedactMask}},
+		{"bad action", RedactionRule{Header: "Authorization", Action: "shred"}},
+		{"fake without type", RedactionRule{Header: "X-User", Action: RedactFake}},
+		{"unknown faker", RedactionRule{Header: "X-User", Action: RedactFake, Fake: "nonsense"}},
+		{"bad jsonPath", RedactionRule{JSONPath: "$[", Action: RedactMask}},
+		{"bad regex", RedactionRule{Regex: "(", Action: RedactMask}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewRedactor(&RedactionConfig{Rules: []RedactionRule{tt.rule}}); err == nil {
+				t.Error("NewRedactor() succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestRedactor_NilIsNoop(t *testing.T) {
+	var r *Redactor
+	rec := &Recording{Request: RecordedRequest{Headers: http.Header{"Authorization": {"Bearer x"}}}}
+	r.RedactRecording(rec)
+	if rec.Request.Headers.Get("Authorization") != "Bearer x" {
+		t.Error("nil Redactor changed a recording")
+	}
+	if got := string(r.RedactPayload([]byte("secret"))); got != "secret" {
+		t.Errorf("RedactPayload() = %q", got)
+	}
+}
+
+func TestRedactor_RedactRecording(t *testing.T) {
+	r := mustRedactor(t, "",
+		RedactionRule{Header: "authorization", Action: RedactMask},
+		RedactionRule{Header: "X-Api-Key", Action: RedactDrop},
+		RedactionRule{Cookie: "session", Action: RedactHash},
+		RedactionRule{Query: "token", Action: RedactMask, Mask: "xxx"},
+		RedactionRule{JSONPath: "$..email", Action: RedactFake, Fake: "email"},
+		RedactionRule{JSONPath: "$.password", Action: RedactDrop},
+	)
+
+	rec := &Recording{
+		Request: RecordedRequest{
+			URL: "https://api.example.com/login?token=abc&page=2",
+			Headers: http.Header{
+				"Authorization": {"Bearer secret"},
+				"X-Api-Key":     {"k"},
+				"Cookie":        {"theme=dark; session=s1"},
+			},
+			Body: []byte(`{"email":"ann@example.com","password":"hunter2"}`),
+		},
+		Response: RecordedResponse{
+			Headers: http.Header{"Set-Cookie": {"session=s1; Path=/; HttpOnly"}},
+			Body:    []byte(`{"user":{"id":7,"email":"ann@example.com"}}`),
+		},
+	}
+	r.RedactRecording(rec)
+
+	if got := rec.Request.Headers.Get("Authorization"); got != DefaultRedactMask {
+		t.Errorf("Authorization = %q, want masked", got)
+	}
+	if _, ok := rec.Request.Headers["X-Api-Key"]; ok {
+		t.Error("X-Api-Key was not dropped")
+	}
+	if !strings.Contains(rec.Request.URL, "token=xxx") || !strings.Contains(rec.Request.URL, "page=2") {
+		t.Errorf("URL = %q, want only token masked", rec.Request.URL)
+	}
+
+	// The session cookie hashes to the same value in both directions.
+	cookie := rec.Request.Headers.Get("Cookie")
+	if !strings.HasPrefix(cookie, "theme=dark; session=") || strings.Contains(cookie, "s1") {
+		t.Fatalf("Cookie = %q, want only session hashed", cookie)
+	}
+	hashed := strings.TrimPrefix(cookie, "theme=dark; session=")
+	if setCookie := rec.Response.Headers.Get("Set-Cookie"); setCookie != "session="+hashed+"; Path=/; HttpOnly" {
+		t.Errorf("Set-Cookie = %q, want session=%s with attributes kept", setCookie, hashed)
+	}
+
+	// The email fakes to the same address in the request and response.
+	req, resp := string(rec.Request.Body), string(rec.Response.Body)
+	if strings.Contains(req, "ann@example.com") || strings.Contains(resp, "ann@example.com") {
+		t.Fatalf("email not redacted: %s / %s", req, resp)
+	}
+	if strings.Contains(req, "password") {
+		t.Errorf("password not dropped: %s", req)
+	}
+	fake := strings.TrimSuffix(strings.TrimPrefix(req, `{"email":"`), `"}`)
+	if !strings.Contains(fake, "@") || !strings.Contains(resp, `"email":"`+fake+`"`) {
+		t.Errorf("request email %q does not match response %s", fake, resp)
+	}
+}
+
+func TestRedactor_StableAcrossSessionsWithSalt(t *testing.T) {
+	rule := RedactionRule{Regex: `acct_\w+`, Action: RedactHash}
+	a := mustRedactor(t, "pepper", rule)
+	b := mustRedactor(t, "pepper", rule)
+	c := mustRedactor(t, "other", rule)
+
+	in := []byte("charge acct_123 and acct_123")
+	outA := string(a.RedactPayload(in))
+	if outA != string(b.RedactPayload(in)) {
+		t.Error("the same salt produced different hashes")
+	}
+	if outA == string(c.RedactPayload(in)) {
+		t.Error("different salts produced the same hash")
+	}
+	parts := strings.Fields(outA)
+	if len(parts) != 4 || parts[1] != parts[3] || strings.Contains(outA, "acct_123") {
+		t.Errorf("RedactPayload() = %q, want the same hash twice", outA)
+	}
+}
+
+func TestRedactor_RegexCaptureGroup(t *testing.T) {
+	r := mustRedactor(t, "", RedactionRule{Regex: `card=(\d+)`, Action: RedactMask, Mask: "****"})
+	if got := string(r.RedactPayload([]byte("card=4242424242424242&cvc=1"))); got != "card=****&cvc=1" {
+		t.Errorf("RedactPayload() = %q", got)
+	}
+}
+
+func TestRedactor_SOAPAndMQTT(t *testing.T) {
+	r := mustRedactor(t, "",
+		RedactionRule{XPath: "//Password", Action: RedactMask},
+		RedactionRule{XPath: "//User/@ssn", Action: RedactDrop},
+		RedactionRule{JSONPath: "$.token", Action: RedactMask},
+	)
+
+	soap := NewSOAPRecording("/ws", "Login", "1.1")
+	soap.SetRequestBody(`<Envelope><Body><Login><User ssn="123-45-6789">ann</User><Password>hunter2</Password></Login></Body></Envelope>`)
+	r.RedactSOAP(soap)
+	if strings.Contains(soap.RequestBody, "hunter2") || strings.Contains(soap.RequestBody, "ssn") {
+		t.Errorf("SOAP body not redacted: %s", soap.RequestBody)
+	}
+	if !strings.Contains(soap.RequestBody, "<Password>"+DefaultRedactMask+"</Password>") {
+		t.Errorf("SOAP body = %s, want Password masked", soap.RequestBody)
+	}
+
+	mqtt := NewMQTTRecording("devices/1", []byte(`{"token":"t0k","temp":21}`), 0, false, "c1", MQTTDirectionPublish)
+	r.RedactMQTT(mqtt)
+	if got := string(mqtt.Payload); got != `{"temp":21,"token":"[REDACTED]"}` {
+		t.Errorf("MQTT payload = %s", got)
+	}
+}
+
+func TestFileStore_RedactsStreams(t *testing.T) {
+	store, err := NewFileStore(StorageConfig{
+		DataDir:   t.TempDir(),
+		Redaction: &RedactionConfig{Rules: []RedactionRule{{JSONPath: "$.card", Action: RedactMask}}},
+	})
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+
+	session, err := store.StartRecording(ProtocolWebSocket, RecordingMetadata{Path: "/ws"})
+	if err != nil {
+		t.Fatalf("StartRecording() error = %v", err)
+	}
+	if err := store.AppendWebSocketFrame(session.ID(), DirectionClientToServer, MessageTypeText, []byte(`{"card":"4242"}`)); err != nil {
+		t.Fatalf("AppendWebSocketFrame() error = %v", err)
+	}
+
+	override := mustRedactor(t, "", RedactionRule{Regex: "4242", Action: RedactDrop})
+	if err := store.SetSessionRedactor(session.ID(), override); err != nil {
+		t.Fatalf("SetSessionRedactor() error = %v", err)
+	}
+	if err := store.AppendWebSocketFrame(session.ID(), DirectionServerToClient, MessageTypeText, []byte(`{"card":"4242"}`)); err != nil {
+		t.Fatalf("AppendWebSocketFrame() error = %v", err)
+	}
+
+	frames := session.Recording().WebSocket.Frames
+	if len(frames) != 2 {
+		t.Fatalf("recorded %d frames, want 2", len(frames))
+	}
+	if frames[0].Data != `{"card":"[REDACTED]"}` || frames[1].Data != `{"card":""}` {
+		t.Errorf("frames = %q, %q", frames[0].Data, frames[1].Data)
+	}
+}
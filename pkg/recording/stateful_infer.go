@@ -0,0 +1,877 @@
+// Package recording provides inference of stateful tables from recorded REST traffic.
+package recording
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getmockd/mockd/pkg/config"
+	"github.com/getmockd/mockd/pkg/util"
+)
+
+// Stateful actions an inferred binding can use, in the order they are reported.
+var statefulActionOrder = []string{"list", "create", "get", "update", "patch", "delete"}
+
+// restActions maps a method on a collection or item path to a stateful action.
+var restActions = map[string]string{
+	"GET collection":  "list",
+	"POST collection": "create",
+	"GET item":        "get",
+	"PUT item":        "update",
+	"PATCH item":      "patch",
+	"DELETE item":     "delete",
+}
+
+// StatefulResourceSummary describes a REST resource inferred from a session.
+type StatefulResourceSummary struct {
+	Table          string   `json:"table"`
+	CollectionPath string   `json:"collectionPath"`
+	ItemPath       string   `json:"itemPath,omitempty"`
+	IDField        string   `json:"idField"`
+	IDStrategy     string   `json:"idStrategy,omitempty"`
+	IDPrefix       string   `json:"idPrefix,omitempty"`
+	ParentField    string   `json:"parentField,omitempty"`
+	SeedRows       int      `json:"seedRows"`
+	Actions        []string `json:"actions"`
+	Recordings     int      `json:"recordings"`
+}
+
+// StatefulInferResult contains the tables, bindings and placeholder mocks
+// inferred from a recorded session.
+type StatefulInferResult struct {
+	Tables    []*config.TableConfig       `json:"tables"`
+	Extend    []*config.ExtendBinding     `json:"extend"`
+	Mocks     []*config.MockConfiguration `json:"mocks"`
+	Resources []StatefulResourceSummary   `json:"resources"`
+	Warnings  []string                    `json:"warnings,omitempty"`
+
+	// Remaining holds the recordings no resource covers; they are converted
+	// to static mocks as usual.
+	Remaining []*Recording `json:"-"`
+}
+
+// restObservation is one item seen in a response of a resource.
+type restObservation struct {
+	item    map[string]interface{}
+	pathID  string   // item ID taken from the request path, if any
+	parents []string // values of the parent path parameters
+	created bool     // the item was returned by a create
+}
+
+// restCandidate gathers the recordings of one collection path.
+type restCandidate struct {
+	segments []string // collection path segments; "" marks a parameter
+	ops      map[string][]*Recording
+	obs      []restObservation
+	lists    []interface{} // decoded list response bodies
+	count    int
+}
+
+// InferStatefulResources detects REST resources in recorded traffic and
+// infers a stateful table for each. A resource is a collection path (such as
+// /users) whose items are read or written through item paths (/users/{id}),
+// or which is both listed and created. For each resource it infers the ID
+// field and ID strategy, seed rows holding every observed item that existed
+// before the session, the list envelope and a ResponseTransform reproducing
+// the observed JSON shape, and emits one mock plus one extend binding per
+// observed action. Recordings of other endpoints are returned in Remaining.
+func InferStatefulResources(recordings []*Recording) *StatefulInferResult {
+	result := &StatefulInferResult{
+		Tables:    make([]*config.TableConfig, 0),
+		Extend:    make([]*config.ExtendBinding, 0),
+		Mocks:     make([]*config.MockConfiguration, 0),
+		Resources: make([]StatefulResourceSummary, 0),
+	}
+
+	sorted := make([]*Recording, len(recordings))
+	copy(sorted, recordings)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	knownIDs := collectResponseIDs(sorted)
+	candidates := make(map[string]*restCandidate)
+	order := make([]string, 0)
+	routed := make(map[*Recording]*restCandidate)
+
+	for _, r := range sorted {
+		segs := pathSegments(r.Request.Path)
+		if len(segs) == 0 {
+			continue
+		}
+		params := make([]bool, len(segs))
+		for i, seg := range segs {
+			params[i] = isIDSegment(seg, knownIDs)
+		}
+
+		last := len(segs) - 1
+		scope := "collection"
+		collEnd := len(segs)
+		if params[last] {
+			scope = "item"
+			collEnd = last
+		}
+		if collEnd == 0 || params[collEnd-1] {
+			continue
+		}
+		action, ok := restActions[strings.ToUpper(r.Request.Method)+" "+scope]
+		if !ok {
+			continue
+		}
+
+		template := make([]string, collEnd)
+		var parents []string
+		for i := 0; i < collEnd; i++ {
+			if params[i] {
+				parents = append(parents, segs[i])
+				continue
+			}
+			template[i] = segs[i]
+		}
+		key := strings.Join(template, "/")
+		c := candidates[key]
+		if c == nil {
+			c = &restCandidate{segments: template, ops: make(map[string][]*Recording)}
+			candidates[key] = c
+			order = append(order, key)
+		}
+		c.ops[action] = append(c.ops[action], r)
+		c.count++
+		routed[r] = c
+
+		pathID := ""
+		if scope == "item" {
+			pathID = segs[last]
+		}
+		c.observe(r, action, pathID, parents)
+	}
+
+	accepted := make(map[*restCandidate]bool)
+	tableNames := make(map[string]bool)
+	for _, key := range order {
+		c := candidates[key]
+		if !c.isResource() {
+			continue
+		}
+		name := uniqueTableName(c.segments, tableNames)
+		if !result.addResource(c, name) {
+			continue
+		}
+		tableNames[name] = true
+		accepted[c] = true
+	}
+
+	for _, r := range recordings {
+		if c, ok := routed[r]; ok && accepted[c] {
+			continue
+		}
+		result.Remaining = append(result.Remaining, r)
+	}
+
+	return result
+}
+
+// observe records the items a successful JSON response carries.
+func (c *restCandidate) observe(r *Recording, action, pathID string, parents []string) {
+	if r.Response.StatusCode < 200 || r.Response.StatusCode >= 300 || len(r.Response.Body) == 0 {
+		return
+	}
+	var body interface{}
+	if err := json.Unmarshal(r.Response.Body, &body); err != nil {
+		return
+	}
+
+	switch action {
+	case "list":
+		c.lists = append(c.lists, body)
+		items, _ := listItems(body)
+		for _, item := range items {
+			c.obs = append(c.obs, restObservation{item: item, parents: parents})
+		}
+	case "delete":
+		// Delete bodies describe the deleted item rather than carry it
+	default:
+		if item, ok := body.(map[string]interface{}); ok {
+			c.obs = append(c.obs, restObservation{
+				item:    item,
+				pathID:  pathID,
+				parents: parents,
+				created: action == "create",
+			})
+		}
+	}
+}
+
+// isResource reports whether the candidate looks like a REST resource.
+func (c *restCandidate) isResource() bool {
+	if len(c.obs) == 0 {
+		return false
+	}
+	for action := range c.ops {
+		switch action {
+		case "get", "update", "patch", "delete":
+			return true
+		}
+	}
+	return len(c.ops["list"]) > 0 && len(c.ops["create"]) > 0
+}
+
+// addResource infers the table, transform, mocks and bindings of a resource.
+// It reports false when no ID field can be found.
+func (result *StatefulInferResult) addResource(c *restCandidate, name string) bool {
+	idField := inferIDField(c.obs)
+	if idField == "" {
+		path, _ := renderResourcePaths(c.segments, "")
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("%s: no ID field found in observed items, left as static mocks", path))
+		return false
+	}
+
+	parentField := inferParentField(c.obs, idField)
+	collectionPath, itemPath := renderResourcePaths(c.segments, parentField)
+
+	table := &config.TableConfig{
+		Name:        name,
+		ParentField: parentField,
+	}
+	if idField != "id" {
+		table.IDField = idField
+	}
+
+	ids := make([]string, 0, len(c.obs))
+	seen := make(map[string]bool)
+	created := make(map[string]bool)
+	numericIDs := false
+	for _, o := range c.obs {
+		v, ok := scalarString(o.item[idField])
+		if !ok {
+			continue
+		}
+		if _, isNum := o.item[idField].(float64); isNum {
+			numericIDs = true
+		}
+		if o.created {
+			created[v] = true
+		}
+		if !seen[v] {
+			seen[v] = true
+			ids = append(ids, v)
+		}
+	}
+	table.IDStrategy, table.IDPrefix = inferIDStrategy(ids)
+
+	// Seed with the first observed version of every item that existed before
+	// the session, so replaying the session's creates does not collide.
+	seeded := make(map[string]bool)
+	for _, o := range c.obs {
+		v, ok := scalarString(o.item[idField])
+		if !ok || created[v] || seeded[v] {
+			continue
+		}
+		seeded[v] = true
+		row := make(map[string]interface{}, len(o.item))
+		for k, val := range o.item {
+			row[k] = val
+		}
+		row[idField] = v
+		table.SeedData = append(table.SeedData, row)
+	}
+	if numericIDs {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("%s: numeric IDs are stored and returned as strings", name))
+	}
+
+	transform, warnings := inferResponseTransform(c, idField)
+	for _, w := range warnings {
+		result.Warnings = append(result.Warnings, name+": "+w)
+	}
+	table.Response = transform
+
+	summary := StatefulResourceSummary{
+		Table:          name,
+		CollectionPath: collectionPath,
+		IDField:        idField,
+		IDStrategy:     table.IDStrategy,
+		IDPrefix:       table.IDPrefix,
+		ParentField:    parentField,
+		SeedRows:       len(table.SeedData),
+		Recordings:     c.count,
+	}
+
+	for _, action := range statefulActionOrder {
+		recs := c.ops[action]
+		if len(recs) == 0 {
+			continue
+		}
+		path := collectionPath
+		if action != "list" && action != "create" {
+			path = itemPath
+			summary.ItemPath = itemPath
+		}
+		m := ToMock(pickSuccessful(recs), DefaultConvertOptions())
+		m.Name = name + " " + action
+		m.HTTP.Matcher.Method = strings.ToUpper(m.HTTP.Matcher.Method)
+		m.HTTP.Matcher.Path = path
+		result.Mocks = append(result.Mocks, m)
+		result.Extend = append(result.Extend, &config.ExtendBinding{
+			Mock:   m.HTTP.Matcher.Method + " " + path,
+			Table:  name,
+			Action: action,
+		})
+		summary.Actions = append(summary.Actions, action)
+	}
+
+	result.Tables = append(result.Tables, table)
+	result.Resources = append(result.Resources, summary)
+	return true
+}
+
+// inferResponseTransform builds the transform that reproduces the observed
+// item and list shapes on top of mockd's stateful responses.
+func inferResponseTransform(c *restCandidate, idField string) (*config.ResponseTransform, []string) {
+	var warnings []string
+	transform := &config.ResponseTransform{}
+	fields := &config.FieldTransform{}
+
+	// Items are stored without their ID field and returned under "id"
+	if idField != "id" {
+		fields.Rename = map[string]string{"id": idField}
+	}
+
+	// Timestamps: mockd adds createdAt/updatedAt, upstream names vary
+	createdKey := commonKey(c.obs, "createdAt", "created_at", "created", "createdOn", "inserted_at")
+	updatedKey := commonKey(c.obs, "updatedAt", "updated_at", "updated", "updatedOn", "modified_at")
+	switch {
+	case createdKey == "" && updatedKey == "":
+		transform.Timestamps = &config.TimestampTransform{Format: "none"}
+	default:
+		ts := &config.TimestampTransform{}
+		for _, pair := range [][2]string{{"createdAt", createdKey}, {"updatedAt", updatedKey}} {
+			switch pair[1] {
+			case "":
+				fields.Hide = append(fields.Hide, pair[0])
+			case pair[0]:
+			default:
+				if ts.Fields == nil {
+					ts.Fields = make(map[string]string)
+				}
+				ts.Fields[pair[0]] = pair[1]
+			}
+			if pair[1] != "" && ts.Format == "" {
+				if _, isNum := firstValue(c.obs, pair[1]).(float64); isNum {
+					ts.Format = "unix"
+				}
+			}
+		}
+		if ts.Format != "" || len(ts.Fields) > 0 {
+			transform.Timestamps = ts
+		}
+	}
+
+	// Type discriminators such as "object": "customer" are constant per resource
+	for _, key := range []string{"object", "kind"} {
+		if v, ok := constantValue(c.obs, key); ok {
+			if fields.Inject == nil {
+				fields.Inject = make(map[string]interface{})
+			}
+			fields.Inject[key] = v
+		}
+	}
+
+	if len(fields.Rename) > 0 || len(fields.Hide) > 0 || len(fields.Inject) > 0 {
+		transform.Fields = fields
+	}
+
+	if len(c.lists) > 0 {
+		list, bare := inferListTransform(c.lists[0])
+		transform.List = list
+		if bare {
+			warnings = append(warnings, "list responses are bare JSON arrays; the stateful list is wrapped in {\"data\": [...]}")
+		}
+	}
+
+	if status := firstSuccessStatus(c.ops["create"]); status != 0 && status != 201 {
+		transform.Create = &config.VerbOverride{Status: status}
+	}
+	if deletes := c.ops["delete"]; len(deletes) > 0 {
+		if override := inferDeleteOverride(pickSuccessful(deletes), c.obs, idField); override != nil {
+			transform.Delete = override
+		}
+	}
+
+	if transform.Timestamps == nil && transform.Fields == nil && transform.List == nil &&
+		transform.Create == nil && transform.Delete == nil {
+		return nil, warnings
+	}
+	return transform, warnings
+}
+
+// listMetaKeys maps observed pagination keys to mockd's meta names.
+var listMetaKeys = map[string]string{
+	"total":       "total",
+	"total_count": "total",
+	"totalCount":  "total",
+	"totalItems":  "total",
+	"limit":       "limit",
+	"per_page":    "limit",
+	"page_size":   "limit",
+	"pageSize":    "limit",
+	"offset":      "offset",
+	"skip":        "offset",
+	"count":       "count",
+}
+
+// inferListTransform derives the list envelope from a list response body.
+// It reports bare when the upstream returns a plain array.
+func inferListTransform(body interface{}) (list *config.ListTransform, bare bool) {
+	obj, ok := body.(map[string]interface{})
+	if !ok {
+		return nil, true
+	}
+	_, dataField := listItems(body)
+	if dataField == "" {
+		return nil, false
+	}
+
+	// mockd's default envelope is {"data": [...], "meta": {...}}
+	defaultShape := dataField == "data"
+	for key := range obj {
+		if key != "data" && key != "meta" {
+			defaultShape = false
+		}
+	}
+	if defaultShape {
+		return nil, false
+	}
+
+	list = &config.ListTransform{DataField: dataField, HideMeta: true}
+	if dataField == "data" {
+		list.DataField = ""
+	}
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if key == dataField {
+			continue
+		}
+		if meta, ok := listMetaKeys[key]; ok {
+			list.HideMeta = false
+			if meta != key {
+				if list.MetaFields == nil {
+					list.MetaFields = make(map[string]string)
+				}
+				list.MetaFields[meta] = key
+			}
+			continue
+		}
+		if list.ExtraFields == nil {
+			list.ExtraFields = make(map[string]interface{})
+		}
+		list.ExtraFields[key] = obj[key]
+	}
+	return list, false
+}
+
+// inferDeleteOverride reproduces a delete response that is not a bare 204.
+// Body values equal to the deleted item's ID or fields become {{item.*}}
+// templates.
+func inferDeleteOverride(r *Recording, obs []restObservation, idField string) *config.VerbOverride {
+	override := &config.VerbOverride{}
+	if r.Response.StatusCode != 204 {
+		override.Status = r.Response.StatusCode
+	}
+
+	var body map[string]interface{}
+	if len(r.Response.Body) > 0 && json.Unmarshal(r.Response.Body, &body) == nil && len(body) > 0 {
+		pathID := ""
+		if segs := pathSegments(r.Request.Path); len(segs) > 0 {
+			pathID = segs[len(segs)-1]
+		}
+		var deleted map[string]interface{}
+		for _, o := range obs {
+			if v, ok := scalarString(o.item[idField]); ok && v == pathID {
+				deleted = o.item
+				break
+			}
+		}
+		override.Body = make(map[string]interface{}, len(body))
+		for key, val := range body {
+			s, isScalar := scalarString(val)
+			switch {
+			case isScalar && s == pathID:
+				override.Body[key] = "{{item.id}}"
+			case isScalar && deleted != nil && key != idField && deleted[key] == val:
+				override.Body[key] = "{{item." + key + "}}"
+			default:
+				override.Body[key] = val
+			}
+		}
+	}
+
+	if override.Status == 0 && override.Body == nil {
+		return nil
+	}
+	return override
+}
+
+// listItems finds the array of objects in a list response: the body itself,
+// or the first array-of-objects field (preferring common names).
+func listItems(body interface{}) ([]map[string]interface{}, string) {
+	switch v := body.(type) {
+	case []interface{}:
+		return objectsOf(v), ""
+	case map[string]interface{}:
+		for _, key := range []string{"data", "items", "results", "records", "entries"} {
+			if arr, ok := v[key].([]interface{}); ok {
+				return objectsOf(arr), key
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if arr, ok := v[key].([]interface{}); ok {
+				if items := objectsOf(arr); len(items) > 0 {
+					return items, key
+				}
+			}
+		}
+	}
+	return nil, ""
+}
+
+// objectsOf returns the object elements of a JSON array.
+func objectsOf(arr []interface{}) []map[string]interface{} {
+	items := make([]map[string]interface{}, 0, len(arr))
+	for _, el := range arr {
+		if obj, ok := el.(map[string]interface{}); ok {
+			items = append(items, obj)
+		}
+	}
+	return items
+}
+
+// naturalKeys are non-ID field names APIs commonly use as item keys in paths.
+var naturalKeys = map[string]bool{
+	"slug": true, "key": true, "code": true, "sku": true,
+	"handle": true, "login": true, "username": true, "name": true,
+}
+
+// inferIDField picks the item key holding the ID: the ID-like or natural key
+// whose values match the IDs seen in item paths, else "id", else another
+// ID-like key present in every item.
+func inferIDField(obs []restObservation) string {
+	common := commonKeys(obs)
+	best, bestScore := "", 0
+	for _, key := range common {
+		if !isIDKey(key) && !naturalKeys[key] {
+			continue
+		}
+		score := 0
+		for _, o := range obs {
+			if o.pathID == "" {
+				continue
+			}
+			if v, ok := scalarString(o.item[key]); ok && v == o.pathID {
+				score++
+			}
+		}
+		if score > bestScore {
+			best, bestScore = key, score
+		}
+	}
+	if best != "" {
+		return best
+	}
+	for _, key := range common {
+		if key == "id" {
+			return key
+		}
+	}
+	for _, key := range common {
+		if isIDKey(key) {
+			return key
+		}
+	}
+	return ""
+}
+
+// inferParentField finds the item field holding the value of the innermost
+// parent path parameter, so nested lists can be filtered by parent.
+func inferParentField(obs []restObservation, idField string) string {
+	candidates := make(map[string]bool)
+	first := true
+	for _, o := range obs {
+		if len(o.parents) == 0 {
+			continue
+		}
+		parent := o.parents[len(o.parents)-1]
+		matches := make(map[string]bool)
+		for key, val := range o.item {
+			if v, ok := scalarString(val); ok && key != idField && v == parent {
+				matches[key] = true
+			}
+		}
+		if first {
+			candidates, first = matches, false
+			continue
+		}
+		for key := range candidates {
+			if !matches[key] {
+				delete(candidates, key)
+			}
+		}
+	}
+	keys := make([]string, 0, len(candidates))
+	for key := range candidates {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if len(keys) == 0 {
+		return ""
+	}
+	return keys[0]
+}
+
+// ulidPattern matches a Crockford base32 ULID.
+var ulidPattern = regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{26}$`)
+
+// inferIDStrategy picks the ID strategy generating IDs like the observed ones.
+func inferIDStrategy(ids []string) (strategy, prefix string) {
+	if len(ids) == 0 {
+		return "", ""
+	}
+	all := func(match func(string) bool) bool {
+		for _, v := range ids {
+			if !match(v) {
+				return false
+			}
+		}
+		return true
+	}
+	switch {
+	case all(isUUID):
+		return "", ""
+	case all(isNumericID):
+		return "sequence", ""
+	case all(ulidPattern.MatchString):
+		return "ulid", ""
+	}
+
+	// A shared prefix ending in a separator, as in Stripe's "cus_"
+	p := ids[0]
+	for _, v := range ids[1:] {
+		for !strings.HasPrefix(v, p) {
+			p = p[:len(p)-1]
+		}
+	}
+	if i := strings.LastIndexAny(p, "_-"); i > 0 {
+		p = p[:i+1]
+		if all(func(v string) bool { return len(v) > len(p) }) {
+			return "prefix", p
+		}
+	}
+	return "short", ""
+}
+
+// renderResourcePaths renders the collection and item path patterns. The
+// innermost parent parameter is named after the parent field, others after
+// the preceding segment, and the item parameter is {id}.
+func renderResourcePaths(segments []string, parentField string) (collection, item string) {
+	lastParam := -1
+	for i, seg := range segments {
+		if seg == "" {
+			lastParam = i
+		}
+	}
+	parts := make([]string, len(segments))
+	for i, seg := range segments {
+		if seg != "" {
+			parts[i] = seg
+			continue
+		}
+		switch {
+		case i == lastParam && parentField != "":
+			parts[i] = "{" + parentField + "}"
+		case i > 0 && segments[i-1] != "":
+			parts[i] = "{" + util.Singularize(segments[i-1]) + "Id}"
+		default:
+			parts[i] = "{param" + strconv.Itoa(i) + "}"
+		}
+	}
+	collection = "/" + strings.Join(parts, "/")
+	return collection, collection + "/{id}"
+}
+
+// uniqueTableName names a table after the last static segment of its
+// collection path, qualifying it with the parent segment on collision.
+func uniqueTableName(segments []string, taken map[string]bool) string {
+	var static []string
+	for _, seg := range segments {
+		if seg != "" {
+			static = append(static, seg)
+		}
+	}
+	name := static[len(static)-1]
+	if taken[name] && len(static) > 1 {
+		name = static[len(static)-2] + "_" + name
+	}
+	base := name
+	for n := 2; taken[name]; n++ {
+		name = base + "_" + strconv.Itoa(n)
+	}
+	return name
+}
+
+// collectResponseIDs gathers the values of ID-like fields in JSON response
+// bodies so short IDs in paths (e.g. cus_123) are recognized as parameters.
+func collectResponseIDs(recordings []*Recording) map[string]bool {
+	ids := make(map[string]bool)
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			for key, val := range t {
+				if s, ok := scalarString(val); ok && isIDKey(key) && s != "" {
+					ids[s] = true
+					continue
+				}
+				walk(val)
+			}
+		case []interface{}:
+			for _, el := range t {
+				walk(el)
+			}
+		}
+	}
+	for _, r := range recordings {
+		if len(r.Response.Body) == 0 {
+			continue
+		}
+		var body interface{}
+		if json.Unmarshal(r.Response.Body, &body) == nil {
+			walk(body)
+		}
+	}
+	return ids
+}
+
+// isIDSegment reports whether a path segment is an item ID.
+func isIDSegment(seg string, knownIDs map[string]bool) bool {
+	return isUUID(seg) || isNumericID(seg) || isAlphanumericID(seg) || knownIDs[seg]
+}
+
+// isIDKey reports whether a JSON key names an identifier.
+func isIDKey(key string) bool {
+	return key == "id" || key == "_id" || key == "uuid" ||
+		strings.HasSuffix(key, "_id") || strings.HasSuffix(key, "Id") || strings.HasSuffix(key, "ID")
+}
+
+// commonKeys returns the keys present in every observed item, sorted.
+func commonKeys(obs []restObservation) []string {
+	counts := make(map[string]int)
+	for _, o := range obs {
+		for key := range o.item {
+			counts[key]++
+		}
+	}
+	keys := make([]string, 0, len(counts))
+	for key, n := range counts {
+		if n == len(obs) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// commonKey returns the first of names present in every observed item.
+func commonKey(obs []restObservation, names ...string) string {
+	for _, name := range names {
+		present := true
+		for _, o := range obs {
+			if _, ok := o.item[name]; !ok {
+				present = false
+				break
+			}
+		}
+		if present {
+			return name
+		}
+	}
+	return ""
+}
+
+// firstValue returns the first observed value of a key.
+func firstValue(obs []restObservation, key string) interface{} {
+	for _, o := range obs {
+		if v, ok := o.item[key]; ok {
+			return v
+		}
+	}
+	return nil
+}
+
+// constantValue returns the string value a key has in every observed item.
+func constantValue(obs []restObservation, key string) (string, bool) {
+	var value string
+	for i, o := range obs {
+		s, ok := o.item[key].(string)
+		if !ok || (i > 0 && s != value) {
+			return "", false
+		}
+		value = s
+	}
+	return value, len(obs) > 0
+}
+
+// scalarString returns a string or number JSON value as a string.
+func scalarString(v interface{}) (string, bool) {
+	switch t := v.(type) {
+	case string:
+		return t, true
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), true
+	}
+	return "", false
+}
+
+// pathSegments splits a path into its non-empty segments.
+func pathSegments(path string) []string {
+	var segs []string
+	for _, seg := range strings.Split(path, "/") {
+		if seg != "" {
+			segs = append(segs, seg)
+		}
+	}
+	return segs
+}
+
+// pickSuccessful returns the first 2xx recording, or the first recording.
+func pickSuccessful(recs []*Recording) *Recording {
+	for _, r := range recs {
+		if r.Response.StatusCode >= 200 && r.Response.StatusCode < 300 {
+			return r
+		}
+	}
+	return recs[0]
+}
+
+// firstSuccessStatus returns the status of the first 2xx recording, or 0.
+func firstSuccessStatus(recs []*Recording) int {
+	for _, r := range recs {
+		if r.Response.StatusCode >= 200 && r.Response.StatusCode < 300 {
+			return r.Response.StatusCode
+		}
+	}
+	return 0
+}
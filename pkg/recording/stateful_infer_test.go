@@ -0,0 +1,190 @@
+package recording
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// restRec builds a recorded JSON call at a fixed offset into the session.
+func restRec(offset int, method, path string, status int, body string) *Recording {
+	rec := NewRecording("session")
+	rec.Timestamp = time.Date(2026, 1, 1, 0, 0, offset, 0, time.UTC)
+	rec.Request = RecordedRequest{
+		Method: method,
+		URL:    "https://api.example.com" + path,
+		Path:   path,
+		Host:   "api.example.com",
+	}
+	rec.Response = RecordedResponse{
+		StatusCode: status,
+		Headers:    http.Header{"Content-Type": {"application/json"}},
+		Body:       []byte(body),
+	}
+	return rec
+}
+
+func TestInferStatefulResources_StripeStyle(t *testing.T) {
+	recs := []*Recording{
+		restRec(0, "GET", "/v1/customers", 200,
+			`{"object":"list","url":"/v1/customers","has_more":false,"data":[`+
+				`{"id":"cus_A1b2C3","object":"customer","name":"Ann","created":1700000000},`+
+				`{"id":"cus_D4e5F6","object":"customer","name":"Bob","created":1700000050}]}`),
+		restRec(1, "POST", "/v1/customers", 200,
+			`{"id":"cus_G7h8I9","object":"customer","name":"Cy","created":1700000100}`),
+		restRec(2, "GET", "/v1/customers/cus_A1b2C3", 200,
+			`{"id":"cus_A1b2C3","object":"customer","name":"Ann","created":1700000000}`),
+		restRec(3, "DELETE", "/v1/customers/cus_D4e5F6", 200,
+			`{"id":"cus_D4e5F6","object":"customer","deleted":true}`),
+		restRec(4, "GET", "/health", 200, `{"ok":true}`),
+	}
+
+	result := InferStatefulResources(recs)
+
+	if len(result.Tables) != 1 {
+		t.Fatalf("tables = %d, want 1 (warnings: %v)", len(result.Tables), result.Warnings)
+	}
+	table := result.Tables[0]
+	if table.Name != "customers" {
+		t.Errorf("table name = %q, want customers", table.Name)
+	}
+	if table.IDField != "" {
+		t.Errorf("IDField = %q, want default", table.IDField)
+	}
+	if table.IDStrategy != "prefix" || table.IDPrefix != "cus_" {
+		t.Errorf("ID strategy = %q/%q, want prefix/cus_", table.IDStrategy, table.IDPrefix)
+	}
+
+	// Items created during the session are not seeded
+	if len(table.SeedData) != 2 {
+		t.Fatalf("seed rows = %d, want 2", len(table.SeedData))
+	}
+	for _, row := range table.SeedData {
+		if row["id"] == "cus_G7h8I9" {
+			t.Error("item created in the session was seeded")
+		}
+	}
+
+	resp := table.Response
+	if resp == nil {
+		t.Fatal("no response transform inferred")
+	}
+	if resp.Create == nil || resp.Create.Status != 200 {
+		t.Errorf("create override = %+v, want status 200", resp.Create)
+	}
+	if resp.Delete == nil || resp.Delete.Status != 200 {
+		t.Fatalf("delete override = %+v, want status 200", resp.Delete)
+	}
+	if resp.Delete.Body["id"] != "{{item.id}}" || resp.Delete.Body["deleted"] != true {
+		t.Errorf("delete body = %v", resp.Delete.Body)
+	}
+	if resp.Fields == nil || resp.Fields.Inject["object"] != "customer" {
+		t.Errorf("fields = %+v, want object injected", resp.Fields)
+	}
+	if resp.Timestamps == nil || resp.Timestamps.Format != "unix" || resp.Timestamps.Fields["createdAt"] != "created" {
+		t.Errorf("timestamps = %+v, want unix created", resp.Timestamps)
+	}
+	if resp.List == nil || !resp.List.HideMeta || resp.List.ExtraFields["object"] != "list" {
+		t.Errorf("list = %+v, want Stripe envelope", resp.List)
+	}
+
+	if len(result.Extend) != 4 {
+		t.Fatalf("bindings = %d, want 4", len(result.Extend))
+	}
+	want := map[string]string{
+		"GET /v1/customers":         "list",
+		"POST /v1/customers":        "create",
+		"GET /v1/customers/{id}":    "get",
+		"DELETE /v1/customers/{id}": "delete",
+	}
+	for _, b := range result.Extend {
+		if want[b.Mock] != b.Action || b.Table != "customers" {
+			t.Errorf("binding %s -> %s.%s not expected", b.Mock, b.Table, b.Action)
+		}
+	}
+	if len(result.Mocks) != 4 {
+		t.Errorf("mocks = %d, want 4", len(result.Mocks))
+	}
+
+	if len(result.Remaining) != 1 || result.Remaining[0].Request.Path != "/health" {
+		t.Errorf("remaining = %d recordings, want only /health", len(result.Remaining))
+	}
+}
+
+func TestInferStatefulResources_NestedResource(t *testing.T) {
+	recs := []*Recording{
+		restRec(0, "GET", "/users/42/orders", 200,
+			`[{"id":7,"user_id":42,"total":10},{"id":8,"user_id":42,"total":25}]`),
+		restRec(1, "GET", "/users/42/orders/7", 200,
+			`{"id":7,"user_id":42,"total":10}`),
+		restRec(2, "PATCH", "/users/42/orders/8", 200,
+			`{"id":8,"user_id":42,"total":30}`),
+	}
+
+	result := InferStatefulResources(recs)
+
+	if len(result.Resources) != 1 {
+		t.Fatalf("resources = %d, want 1", len(result.Resources))
+	}
+	summary := result.Resources[0]
+	if summary.Table != "orders" || summary.ParentField != "user_id" {
+		t.Errorf("summary = %+v, want orders with parent user_id", summary)
+	}
+	if summary.CollectionPath != "/users/{user_id}/orders" || summary.ItemPath != "/users/{user_id}/orders/{id}" {
+		t.Errorf("paths = %q, %q", summary.CollectionPath, summary.ItemPath)
+	}
+	if summary.IDStrategy != "sequence" {
+		t.Errorf("IDStrategy = %q, want sequence", summary.IDStrategy)
+	}
+
+	// The first observed version of an item is seeded, with a string ID
+	table := result.Tables[0]
+	if len(table.SeedData) != 2 || table.SeedData[1]["id"] != "8" || table.SeedData[1]["total"] != float64(25) {
+		t.Errorf("seed = %v", table.SeedData)
+	}
+	if table.Response == nil || table.Response.Timestamps == nil || table.Response.Timestamps.Format != "none" {
+		t.Errorf("timestamps should be omitted, got %+v", table.Response)
+	}
+	if len(result.Warnings) != 2 {
+		t.Errorf("warnings = %v, want numeric ID and bare array notes", result.Warnings)
+	}
+}
+
+func TestInferStatefulResources_NotAResource(t *testing.T) {
+	recs := []*Recording{
+		restRec(0, "GET", "/status", 200, `{"up":true}`),
+		restRec(1, "POST", "/login", 200, `{"token":"abc"}`),
+		restRec(2, "GET", "/reports/2026", 200, `{"year":2026}`),
+	}
+
+	result := InferStatefulResources(recs)
+
+	if len(result.Tables) != 0 {
+		t.Errorf("tables = %d, want 0", len(result.Tables))
+	}
+	if len(result.Remaining) != len(recs) {
+		t.Errorf("remaining = %d, want %d", len(result.Remaining), len(recs))
+	}
+	if len(result.Warnings) != 1 {
+		t.Errorf("warnings = %v, want a missing ID field note for /reports", result.Warnings)
+	}
+}
+
+func TestConvertRecordingsWithOptions_Stateful(t *testing.T) {
+	recs := []*Recording{
+		restRec(0, "GET", "/api/todos/1", 200, `{"id":"1","title":"a"}`),
+		restRec(1, "PUT", "/api/todos/1", 200, `{"id":"1","title":"b"}`),
+		restRec(2, "GET", "/api/version", 200, `{"v":"1.0"}`),
+	}
+	opts := DefaultSessionConvertOptions()
+	opts.Stateful = true
+
+	result := ConvertRecordingsWithOptions(recs, opts)
+
+	if len(result.Tables) != 1 || len(result.Extend) != 2 {
+		t.Fatalf("tables = %d, extend = %d, want 1 and 2", len(result.Tables), len(result.Extend))
+	}
+	if len(result.Mocks) != 3 {
+		t.Errorf("mocks = %d, want 2 bound + 1 static", len(result.Mocks))
+	}
+}
@@ -0,0 +1,562 @@
+// Package recording provides template inference for mocks converted from recordings.
+package recording
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getmockd/mockd/pkg/config"
+	"github.com/getmockd/mockd/pkg/util"
+)
+
+// TemplateSubstitution records a recorded response value replaced by a
+// template expression during conversion.
+type TemplateSubstitution struct {
+	MockID   string `json:"mockId"`
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	Location string `json:"location"` // "body" or "header"
+	Field    string `json:"field"`    // JSON path in the body, or header name
+	Value    string `json:"value"`    // Recorded value that was replaced
+	Template string `json:"template"` // Template expression that replaced it
+	Source   string `json:"source"`   // "path", "query", "body", "header" or "volatile"
+}
+
+// volatileWindow bounds how far a timestamp may be from the recording time
+// to count as generated at response time rather than stored data.
+const volatileWindow = 24 * time.Hour
+
+// echoSkipHeaders are request headers whose values are not treated as echoes.
+var echoSkipHeaders = map[string]bool{
+	"Host":                true,
+	"Content-Length":      true,
+	"Content-Type":        true,
+	"Accept":              true,
+	"Accept-Encoding":     true,
+	"Accept-Language":     true,
+	"User-Agent":          true,
+	"Connection":          true,
+	"Cookie":              true,
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+}
+
+// echoCandidate is a request value that may be echoed in the response.
+type echoCandidate struct {
+	value    string
+	template string
+	source   string
+	segment  int    // path segment to turn into a parameter on first use, or -1
+	param    string // name of that parameter
+}
+
+// TemplateInferrer replaces recorded response values with template
+// expressions. Values the upstream echoed from the request (path segments,
+// query parameters, JSON body fields and headers) become {{request.*}}
+// expressions; UUIDs and timestamps generated at response time become
+// {{uuid}}, {{now}} or {{timestamp}}.
+type TemplateInferrer struct {
+	// referenced holds every value sent in a request of the session. A UUID
+	// the client sends back later is an entity ID, not a volatile value.
+	referenced map[string]bool
+}
+
+// NewTemplateInferrer creates an inferrer for the recordings of a session.
+func NewTemplateInferrer(session []*Recording) *TemplateInferrer {
+	ti := &TemplateInferrer{referenced: make(map[string]bool)}
+	for _, r := range session {
+		for _, seg := range pathSegments(r.Request.Path) {
+			ti.referenced[seg] = true
+		}
+		for _, values := range requestQuery(r) {
+			for _, v := range values {
+				ti.referenced[v] = true
+			}
+		}
+		for _, values := range r.Request.Headers {
+			for _, v := range values {
+				ti.referenced[v] = true
+			}
+		}
+		walkRequestBody(r, func(_ string, v string) {
+			ti.referenced[v] = true
+		})
+	}
+	return ti
+}
+
+// Apply templates the response of m, which was converted from r, and
+// returns the substitutions made. An echoed ID segment of a literal path
+// becomes a path parameter of m's matcher. Only JSON bodies are templated.
+func (ti *TemplateInferrer) Apply(r *Recording, m *config.MockConfiguration) []TemplateSubstitution {
+	if m == nil || m.HTTP == nil || m.HTTP.Matcher == nil || m.HTTP.Response == nil {
+		return nil
+	}
+	candidates := echoCandidates(r, m.HTTP.Matcher.Path)
+	subs := make([]TemplateSubstitution, 0)
+	record := func(location, field, value string, used []*echoCandidate, template string) {
+		source := "volatile"
+		if len(used) > 0 {
+			source = used[0].source
+		}
+		for _, c := range used {
+			ti.useCandidate(m, c)
+		}
+		subs = append(subs, TemplateSubstitution{
+			MockID:   m.ID,
+			Method:   m.HTTP.Matcher.Method,
+			Location: location,
+			Field:    field,
+			Value:    value,
+			Template: template,
+			Source:   source,
+		})
+	}
+
+	resp := m.HTTP.Response
+	if leaves, ok := jsonLeaves([]byte(resp.Body)); ok {
+		body := []byte(resp.Body)
+		type edit struct {
+			start, end int
+			text       string
+		}
+		var edits []edit
+		for _, leaf := range leaves {
+			switch v := leaf.value.(type) {
+			case string:
+				if c := matchEcho(candidates, v, leaf.key); c != nil {
+					record("body", leaf.path, v, []*echoCandidate{c}, c.template)
+					edits = append(edits, edit{leaf.start, leaf.end, quoteJSON(c.template)})
+					continue
+				}
+				if replaced, used := replaceEchoes(candidates, v); len(used) > 0 {
+					record("body", leaf.path, v, used, replaced)
+					edits = append(edits, edit{leaf.start, leaf.end, quoteJSON(replaced)})
+					continue
+				}
+				if tpl := ti.volatileString(v, r.Timestamp); tpl != "" {
+					record("body", leaf.path, v, nil, tpl)
+					edits = append(edits, edit{leaf.start, leaf.end, quoteJSON(tpl)})
+				}
+			case json.Number:
+				s := v.String()
+				if c := matchEcho(candidates, s, leaf.key); c != nil && isNumericID(c.value) {
+					record("body", leaf.path, s, []*echoCandidate{c}, c.template)
+					edits = append(edits, edit{leaf.start, leaf.end, c.template})
+					continue
+				}
+				if isVolatileEpoch(s, r.Timestamp) {
+					record("body", leaf.path, s, nil, "{{timestamp}}")
+					edits = append(edits, edit{leaf.start, leaf.end, "{{timestamp}}"})
+				}
+			}
+		}
+		for i := len(edits) - 1; i >= 0; i-- {
+			e := edits[i]
+			body = append(body[:e.start:e.start], append([]byte(e.text), body[e.end:]...)...)
+		}
+		resp.Body = string(body)
+	}
+
+	names := make([]string, 0, len(resp.Headers))
+	for name := range resp.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := resp.Headers[name]
+		if strings.EqualFold(name, "Content-Type") {
+			continue
+		}
+		if c := matchEcho(candidates, value, ""); c != nil {
+			record("header", name, value, []*echoCandidate{c}, c.template)
+			resp.Headers[name] = c.template
+			continue
+		}
+		if replaced, used := replaceEchoes(candidates, value); len(used) > 0 {
+			record("header", name, value, used, replaced)
+			resp.Headers[name] = replaced
+			continue
+		}
+		if isUUID(value) && !ti.referenced[value] {
+			record("header", name, value, nil, "{{uuid}}")
+			resp.Headers[name] = "{{uuid}}"
+		}
+	}
+
+	// The matcher path may have gained parameters, so report the final one
+	for i := range subs {
+		subs[i].Path = m.HTTP.Matcher.Path
+	}
+	return subs
+}
+
+// useCandidate turns the path segment of a path candidate into a parameter
+// the first time its value is templated.
+func (ti *TemplateInferrer) useCandidate(m *config.MockConfiguration, c *echoCandidate) {
+	if c.segment < 0 {
+		return
+	}
+	segs := strings.Split(m.HTTP.Matcher.Path, "/")
+	if c.segment < len(segs) {
+		segs[c.segment] = "{" + c.param + "}"
+		m.HTTP.Matcher.Path = strings.Join(segs, "/")
+	}
+	c.segment = -1
+}
+
+// volatileString returns the generator replacing a value produced at
+// response time: a UUID never sent back by the client, or a timestamp close
+// to the recording time.
+func (ti *TemplateInferrer) volatileString(v string, recorded time.Time) string {
+	if isUUID(v) && !ti.referenced[v] {
+		return "{{uuid}}"
+	}
+	if recorded.IsZero() || len(v) < 20 {
+		return ""
+	}
+	t, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return ""
+	}
+	if d := t.Sub(recorded); d < volatileWindow && d > -volatileWindow {
+		return "{{now}}"
+	}
+	return ""
+}
+
+// isVolatileEpoch reports whether a number is a Unix timestamp in seconds
+// close to the recording time.
+func isVolatileEpoch(s string, recorded time.Time) bool {
+	if recorded.IsZero() {
+		return false
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return false
+	}
+	d := time.Duration(n-recorded.Unix()) * time.Second
+	return d < volatileWindow && d > -volatileWindow
+}
+
+// echoCandidates lists the request values that may be echoed, in priority
+// order: path segments, query parameters, JSON body fields, then headers.
+func echoCandidates(r *Recording, matcherPath string) []*echoCandidate {
+	var candidates []*echoCandidate
+	seen := make(map[string]bool)
+	add := func(c *echoCandidate) {
+		if c.value == "" || seen[c.value] {
+			return
+		}
+		seen[c.value] = true
+		candidates = append(candidates, c)
+	}
+
+	segs := strings.Split(r.Request.Path, "/")
+	patternSegs := strings.Split(matcherPath, "/")
+	if len(segs) == len(patternSegs) {
+		taken := make(map[string]bool)
+		for _, p := range patternSegs {
+			if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+				taken[p[1:len(p)-1]] = true
+			}
+		}
+		for i, seg := range segs {
+			p := patternSegs[i]
+			switch {
+			case seg == "":
+			case strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}"):
+				add(&echoCandidate{value: seg, template: "{{request.pathParam." + p[1:len(p)-1] + "}}", source: "path", segment: -1})
+			case p == seg && (isUUID(seg) || isNumericID(seg) || isAlphanumericID(seg)):
+				name := segmentParamName(segs, i, taken)
+				taken[name] = true
+				add(&echoCandidate{value: seg, template: "{{request.pathParam." + name + "}}", source: "path", segment: i, param: name})
+			}
+		}
+	}
+
+	query := requestQuery(r)
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if len(query[key]) > 0 && len(query[key][0]) >= 3 {
+			add(&echoCandidate{value: query[key][0], template: "{{request.query." + key + "}}", source: "query", segment: -1})
+		}
+	}
+
+	walkRequestBody(r, func(path, v string) {
+		if len(v) >= 3 {
+			add(&echoCandidate{value: v, template: "{{request.body." + path + "}}", source: "body", segment: -1})
+		}
+	})
+
+	names := make([]string, 0, len(r.Request.Headers))
+	for name := range r.Request.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		canonical := http.CanonicalHeaderKey(name)
+		if echoSkipHeaders[canonical] || len(r.Request.Headers[name]) == 0 {
+			continue
+		}
+		if v := r.Request.Headers[name][0]; len(v) >= 3 {
+			add(&echoCandidate{value: v, template: "{{request.header." + canonical + "}}", source: "header", segment: -1})
+		}
+	}
+
+	return candidates
+}
+
+// segmentParamName names the parameter for an ID segment: "id" for the
+// last segment, else after the preceding segment ("users" → "userId").
+func segmentParamName(segs []string, i int, taken map[string]bool) string {
+	name := "id"
+	if i < len(segs)-1 {
+		name = "param" + strconv.Itoa(i)
+		if i > 0 && segs[i-1] != "" {
+			name = util.Singularize(segs[i-1]) + "Id"
+		}
+	}
+	base := name
+	for n := 2; taken[name]; n++ {
+		name = base + strconv.Itoa(n)
+	}
+	return name
+}
+
+// matchEcho returns the candidate a response value equals. Values shorter
+// than three characters only match under an ID-like key.
+func matchEcho(candidates []*echoCandidate, v, key string) *echoCandidate {
+	if v == "" || (len(v) < 3 && !isIDKey(key)) {
+		return nil
+	}
+	for _, c := range candidates {
+		if c.value == v {
+			return c
+		}
+	}
+	return nil
+}
+
+// replaceEchoes replaces candidate values embedded in a longer string, such
+// as an ID in a URL, when they are delimited by non-word characters. It
+// returns the candidates used, none when nothing was replaced. Values
+// shorter than four characters are too likely to occur by chance ("Widget 1"),
+// so a short path value is only replaced as a whole segment of a URL.
+func replaceEchoes(candidates []*echoCandidate, s string) (string, []*echoCandidate) {
+	var used []*echoCandidate
+	urlLike := strings.HasPrefix(s, "/") || strings.Contains(s, "://")
+	for _, c := range candidates {
+		short := len(c.value) < 4
+		if short && (c.source != "path" || !urlLike) {
+			continue
+		}
+		var out strings.Builder
+		rest := s
+		replaced := false
+		for {
+			i := strings.Index(rest, c.value)
+			if i < 0 {
+				out.WriteString(rest)
+				break
+			}
+			end := i + len(c.value)
+			if isWordByteAt(rest, i-1) || isWordByteAt(rest, end) || short && !isPathSegmentAt(rest, i, end) {
+				out.WriteString(rest[:end])
+				rest = rest[end:]
+				continue
+			}
+			out.WriteString(rest[:i])
+			out.WriteString(c.template)
+			rest = rest[end:]
+			replaced = true
+		}
+		if replaced {
+			s = out.String()
+			used = append(used, c)
+		}
+	}
+	return s, used
+}
+
+// isWordByteAt reports whether s[i] exists and is part of an identifier.
+func isWordByteAt(s string, i int) bool {
+	if i < 0 || i >= len(s) {
+		return false
+	}
+	b := s[i]
+	return b == '_' || b == '-' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// isPathSegmentAt reports whether s[i:end] is a whole path segment: preceded
+// by a slash and followed by a slash, query, fragment or the end of s.
+func isPathSegmentAt(s string, i, end int) bool {
+	if i == 0 || s[i-1] != '/' {
+		return false
+	}
+	return end == len(s) || s[end] == '/' || s[end] == '?' || s[end] == '#'
+}
+
+// requestQuery parses the query string of a recorded request.
+func requestQuery(r *Recording) url.Values {
+	u, err := url.Parse(r.Request.URL)
+	if err != nil {
+		return nil
+	}
+	return u.Query()
+}
+
+// walkRequestBody calls fn with the dot path and value of every string
+// field in a JSON request body.
+func walkRequestBody(r *Recording, fn func(path, value string)) {
+	if len(r.Request.Body) == 0 {
+		return
+	}
+	var body interface{}
+	if json.Unmarshal(r.Request.Body, &body) != nil {
+		return
+	}
+	var walk func(prefix string, v interface{})
+	walk = func(prefix string, v interface{}) {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			for key, val := range t {
+				if strings.Contains(key, ".") {
+					continue
+				}
+				walk(joinPath(prefix, key), val)
+			}
+		case []interface{}:
+			for i, val := range t {
+				walk(joinPath(prefix, strconv.Itoa(i)), val)
+			}
+		case string:
+			if prefix != "" {
+				fn(prefix, t)
+			}
+		}
+	}
+	walk("", body)
+}
+
+// joinPath appends a segment to a dot path.
+func joinPath(prefix, segment string) string {
+	if prefix == "" {
+		return segment
+	}
+	return prefix + "." + segment
+}
+
+// jsonLeaf is a string or number value in a JSON document with its byte span.
+type jsonLeaf struct {
+	path       string      // JSONPath such as $.items[0].id
+	key        string      // enclosing object key, "" inside arrays
+	start, end int         // byte span of the value token
+	value      interface{} // string or json.Number
+}
+
+// jsonLeaves tokenizes a JSON document and returns its scalar values with
+// their positions, so they can be replaced without reformatting the body.
+func jsonLeaves(body []byte) ([]jsonLeaf, bool) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return nil, false
+	}
+
+	type frame struct {
+		path      string
+		array     bool
+		index     int
+		key       string
+		expectKey bool
+	}
+	var stack []*frame
+	childPath := func() (string, string) {
+		if len(stack) == 0 {
+			return "$", ""
+		}
+		top := stack[len(stack)-1]
+		if top.array {
+			return top.path + "[" + strconv.Itoa(top.index) + "]", ""
+		}
+		return top.path + "." + top.key, top.key
+	}
+	advance := func() {
+		if len(stack) == 0 {
+			return
+		}
+		top := stack[len(stack)-1]
+		if top.array {
+			top.index++
+		} else {
+			top.expectKey = true
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var leaves []jsonLeaf
+	for {
+		start := int(dec.InputOffset())
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, false
+		}
+		end := int(dec.InputOffset())
+		for start < end && strings.IndexByte(" \t\r\n,:", body[start]) >= 0 {
+			start++
+		}
+
+		switch t := tok.(type) {
+		case json.Delim:
+			switch t {
+			case '{', '[':
+				path, _ := childPath()
+				stack = append(stack, &frame{path: path, array: t == '[', expectKey: t == '{'})
+			default:
+				stack = stack[:len(stack)-1]
+				advance()
+			}
+		case string:
+			if len(stack) > 0 && !stack[len(stack)-1].array && stack[len(stack)-1].expectKey {
+				top := stack[len(stack)-1]
+				top.key = t
+				top.expectKey = false
+				continue
+			}
+			path, key := childPath()
+			leaves = append(leaves, jsonLeaf{path: path, key: key, start: start, end: end, value: t})
+			advance()
+		case json.Number:
+			path, key := childPath()
+			leaves = append(leaves, jsonLeaf{path: path, key: key, start: start, end: end, value: t})
+			advance()
+		default:
+			advance()
+		}
+	}
+	return leaves, true
+}
+
+// quoteJSON encodes a string as a JSON string literal without HTML escaping.
+func quoteJSON(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,151 @@
+package recording
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTemplateInferrer_Echoes(t *testing.T) {
+	rec := restRec(0, "POST", "/api/orders/ord-48213/items", 201,
+		`{"order": "ord-48213", "sku": "SKU-991", "note": "gift wrap", "page": "2",
+  "self": "/api/orders/ord-48213/items", "trace": "trace-abc-123"}`)
+	rec.Request.URL = "https://api.example.com/api/orders/ord-48213/items?page=2&currency=EUR"
+	rec.Request.Body = []byte(`{"sku": "SKU-991", "options": {"note": "gift wrap"}}`)
+	rec.Request.Headers = http.Header{
+		"X-Trace-Id": {"trace-abc-123"},
+		"Accept":     {"application/json"},
+	}
+	rec.Response.Headers = http.Header{
+		"Content-Type":     {"application/json"},
+		"X-Correlation-Id": {"trace-abc-123"},
+	}
+
+	m := ToMock(rec, DefaultConvertOptions())
+	m.HTTP.Matcher.Path = "/api/orders/{id}/items"
+	subs := NewTemplateInferrer([]*Recording{rec}).Apply(rec, m)
+
+	body := m.HTTP.Response.Body
+	for _, want := range []string{
+		`"order": "{{request.pathParam.id}}"`,
+		`"sku": "{{request.body.sku}}"`,
+		`"note": "{{request.body.options.note}}"`,
+		`"self": "/api/orders/{{request.pathParam.id}}/items"`,
+		`"trace": "{{request.header.X-Trace-Id}}"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %s:\n%s", want, body)
+		}
+	}
+	// Values shorter than three characters are not treated as echoes
+	if !strings.Contains(body, `"page": "2"`) {
+		t.Errorf("short query value was templated:\n%s", body)
+	}
+	// Formatting of the recorded body is preserved
+	if !strings.Contains(body, ",\n  \"self\"") {
+		t.Errorf("body formatting changed:\n%s", body)
+	}
+	if got := m.HTTP.Response.Headers["X-Correlation-Id"]; got != "{{request.header.X-Trace-Id}}" {
+		t.Errorf("X-Correlation-Id = %q", got)
+	}
+
+	if len(subs) != 6 {
+		t.Fatalf("substitutions = %d, want 6: %+v", len(subs), subs)
+	}
+	first := subs[0]
+	if first.Location != "body" || first.Field != "$.order" || first.Value != "ord-48213" ||
+		first.Source != "path" || first.Path != "/api/orders/{id}/items" || first.MockID != m.ID {
+		t.Errorf("first substitution = %+v", first)
+	}
+}
+
+func TestTemplateInferrer_ParameterizesEchoedSegment(t *testing.T) {
+	rec := restRec(0, "GET", "/users/4821/orders/17", 200, `{"id": 17, "user": {"id": 4821}, "qty": 17}`)
+	m := ToMock(rec, DefaultConvertOptions())
+
+	subs := NewTemplateInferrer([]*Recording{rec}).Apply(rec, m)
+
+	if m.HTTP.Matcher.Path != "/users/{userId}/orders/{id}" {
+		t.Errorf("path = %q", m.HTTP.Matcher.Path)
+	}
+	want := `{"id": {{request.pathParam.id}}, "user": {"id": {{request.pathParam.userId}}}, "qty": 17}`
+	if m.HTTP.Response.Body != want {
+		t.Errorf("body = %s, want %s", m.HTTP.Response.Body, want)
+	}
+	if len(subs) != 2 {
+		t.Errorf("substitutions = %+v", subs)
+	}
+}
+
+func TestTemplateInferrer_VolatileValues(t *testing.T) {
+	recorded := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	created := NewRecording("session")
+	created.Timestamp = recorded
+	created.Request = RecordedRequest{Method: "POST", URL: "/jobs", Path: "/jobs"}
+	created.Response = RecordedResponse{
+		StatusCode: 202,
+		Headers:    http.Header{"X-Request-Id": {"0f8fad5b-d9cb-469f-a165-70867728950e"}},
+		Body: []byte(`{"job": "7c9e6679-7425-40de-944b-e07fc1f90ae7", "requestId": "16fd2706-8baf-433b-82eb-8c7fada847da",` +
+			` "queuedAt": "2026-03-01T12:00:00.512Z", "since": "2019-05-01T00:00:00Z", "ts": 1772366400}`),
+	}
+	// The job ID is sent back later, so it is an entity ID rather than volatile
+	poll := restRec(1, "GET", "/jobs/7c9e6679-7425-40de-944b-e07fc1f90ae7", 200, `{}`)
+
+	m := ToMock(created, DefaultConvertOptions())
+	subs := NewTemplateInferrer([]*Recording{created, poll}).Apply(created, m)
+
+	want := `{"job": "7c9e6679-7425-40de-944b-e07fc1f90ae7", "requestId": "{{uuid}}",` +
+		` "queuedAt": "{{now}}", "since": "2019-05-01T00:00:00Z", "ts": {{timestamp}}}`
+	if m.HTTP.Response.Body != want {
+		t.Errorf("body = %s, want %s", m.HTTP.Response.Body, want)
+	}
+	if got := m.HTTP.Response.Headers["X-Request-Id"]; got != "{{uuid}}" {
+		t.Errorf("X-Request-Id = %q", got)
+	}
+	for _, sub := range subs {
+		if sub.Source != "volatile" {
+			t.Errorf("substitution %+v should be volatile", sub)
+		}
+	}
+	if len(subs) != 4 {
+		t.Errorf("substitutions = %d, want 4", len(subs))
+	}
+}
+
+func TestConvertRecordingsWithOptions_Templates(t *testing.T) {
+	recs := []*Recording{
+		restRec(0, "GET", "/users/1001", 200, `{"id":"1001","name":"Ann"}`),
+		restRec(1, "GET", "/users/1002", 200, `{"id":"1002","name":"Bob"}`),
+		restRec(2, "GET", "/health", 200, `{"ok":true}`),
+	}
+	opts := DefaultSessionConvertOptions()
+	opts.Templates = true
+
+	result := ConvertRecordingsWithOptions(recs, opts)
+
+	// Both user recordings become /users/{id}; the first is kept
+	if len(result.Mocks) != 2 {
+		t.Fatalf("mocks = %d, want 2", len(result.Mocks))
+	}
+	if len(result.Substitutions) != 1 || result.Substitutions[0].MockID != result.Mocks[0].ID {
+		t.Errorf("substitutions = %+v, want only the kept mock's", result.Substitutions)
+	}
+	if result.Mocks[0].HTTP.Response.Body != `{"id":"{{request.pathParam.id}}","name":"Ann"}` {
+		t.Errorf("body = %s", result.Mocks[0].HTTP.Response.Body)
+	}
+}
+
+func TestTemplateInferrer_ShortPathValueInText(t *testing.T) {
+	rec := restRec(0, "GET", "/items/1", 200,
+		`{"id": 1, "name": "Widget 1", "self": "/items/1", "reviews": "/items/1/reviews?page=1"}`)
+	m := ToMock(rec, DefaultConvertOptions())
+
+	NewTemplateInferrer([]*Recording{rec}).Apply(rec, m)
+
+	want := `{"id": {{request.pathParam.id}}, "name": "Widget 1", "self": "/items/{{request.pathParam.id}}", ` +
+		`"reviews": "/items/{{request.pathParam.id}}/reviews?page=1"}`
+	if m.HTTP.Response.Body != want {
+		t.Errorf("body = %s, want %s", m.HTTP.Response.Body, want)
+	}
+}
@@ -168,6 +168,10 @@ type StorageConfig struct {
 
 	// RedactValue is the replacement value for redacted content.
 	RedactValue string `json:"redactValue"`
+
+	// Redaction is applied to every recording's headers, frames and events
+	// as they are captured. Sessions can override it.
+	Redaction *RedactionConfig `json:"redaction,omitempty"`
 }
 
 // DefaultFilterHeaders are headers filtered by default for security.
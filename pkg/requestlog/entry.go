@@ -68,6 +68,9 @@ type Entry struct {
 	// Only populated when MatchedMockID is empty (404 responses).
 	NearMisses []NearMissInfo `json:"nearMisses,omitempty"`
 
+	// Chaos describes the chaos faults injected into the request, if any.
+	Chaos *ChaosMeta `json:"chaos,omitempty"`
+
 	// Protocol-specific metadata (only one will be populated based on Protocol).
 	GRPC      *GRPCMeta      `json:"grpc,omitempty"`
 	WebSocket *WebSocketMeta `json:"websocket,omitempty"`
@@ -110,3 +110,13 @@ type GraphQLMeta struct {
 	// ErrorCount is the number of GraphQL errors in response.
 	ErrorCount int `json:"errorCount,omitempty"`
 }
+
+// ChaosMeta links a request to the chaos fault log record of the faults
+// injected into it.
+type ChaosMeta struct {
+	// FaultSeq is the sequence number of the fault log record.
+	FaultSeq int64 `json:"faultSeq"`
+
+	// Faults lists the types of the injected faults.
+	Faults []string `json:"faults"`
+}
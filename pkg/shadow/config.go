@@ -0,0 +1,103 @@
+package shadow
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout bounds an upstream request when Config.Timeout is empty.
+const DefaultTimeout = 10 * time.Second
+
+// DefaultMethods are shadowed when Config.Methods is empty. Only safe methods
+// are sent to the upstream by default, so enabling shadow mode never repeats
+// a client's writes against the real API.
+var DefaultMethods = []string{http.MethodGet, http.MethodHead, http.MethodOptions}
+
+// Config configures shadow mode.
+type Config struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Upstream is the base URL of the real API. The request path and query
+	// are appended to it.
+	Upstream string `json:"upstream" yaml:"upstream"`
+
+	// MockIDs limits shadowing to these mocks; empty shadows every HTTP mock.
+	MockIDs []string `json:"mockIds,omitempty" yaml:"mockIds,omitempty"`
+	// PathPattern limits shadowing to request paths matching this regex.
+	PathPattern string `json:"pathPattern,omitempty" yaml:"pathPattern,omitempty"`
+	// Methods limits shadowing to these HTTP methods. Default: DefaultMethods.
+	// Methods with side effects, such as POST or DELETE, are only shadowed
+	// when listed, since the upstream executes them too.
+	Methods []string `json:"methods,omitempty" yaml:"methods,omitempty"`
+	// SampleRate is the fraction of selected requests shadowed, from 0 to 1.
+	// Zero shadows every selected request.
+	SampleRate float64 `json:"sampleRate,omitempty" yaml:"sampleRate,omitempty"`
+
+	// Timeout bounds each upstream request (e.g. "5s"). Default: 10s.
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// Headers are added to every upstream request, such as credentials the
+	// mock's clients do not send.
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	Ignore IgnoreRules `json:"ignore,omitempty" yaml:"ignore,omitempty"`
+}
+
+// IgnoreRules lists the differences not reported as drift.
+type IgnoreRules struct {
+	// Status ignores status code differences.
+	Status bool `json:"status,omitempty" yaml:"status,omitempty"`
+	// Headers are header names not compared (case-insensitive).
+	Headers []string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	// Fields are JSON body fields not compared, with their sub-fields. A
+	// path such as "$.data[*].created" matches that field only; a bare name
+	// such as "request_id" matches the field at any depth.
+	Fields []string `json:"fields,omitempty" yaml:"fields,omitempty"`
+	// MissingFields ignores fields the upstream returns but the mock does
+	// not, for mocks that return a subset of the real response.
+	MissingFields bool `json:"missingFields,omitempty" yaml:"missingFields,omitempty"`
+}
+
+// Validate checks the configuration.
+func (c *Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Upstream == "" {
+		return errors.New("validation: upstream is required")
+	}
+	u, err := url.Parse(c.Upstream)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("validation: upstream must be an http or https URL, got %q", c.Upstream)
+	}
+	if c.PathPattern != "" {
+		if _, err := regexp.Compile(c.PathPattern); err != nil {
+			return fmt.Errorf("validation: invalid pathPattern: %w", err)
+		}
+	}
+	if c.SampleRate < 0 || c.SampleRate > 1 {
+		return fmt.Errorf("validation: sampleRate must be between 0 and 1, got %v", c.SampleRate)
+	}
+	if c.Timeout != "" {
+		if d, err := time.ParseDuration(c.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("validation: invalid timeout %q", c.Timeout)
+		}
+	}
+	for _, f := range c.Ignore.Fields {
+		if strings.TrimSpace(f) == "" {
+			return errors.New("validation: ignore fields must not be empty")
+		}
+	}
+	return nil
+}
+
+// timeout returns the upstream request timeout.
+func (c *Config) timeout() time.Duration {
+	if d, err := time.ParseDuration(c.Timeout); err == nil && d > 0 {
+		return d
+	}
+	return DefaultTimeout
+}
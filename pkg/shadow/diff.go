@@ -0,0 +1,286 @@
+package shadow
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Difference kinds.
+const (
+	KindStatus  = "status"  // Status codes differ
+	KindHeader  = "header"  // A mock header is missing upstream, or Content-Type differs
+	KindBody    = "body"    // One body is JSON and the other is not
+	KindMissing = "missing" // The upstream returns a field the mock does not
+	KindExtra   = "extra"   // The mock returns a field the upstream does not
+	KindType    = "type"    // A field has different JSON types
+)
+
+// Response is an HTTP response compared in shadow mode.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Difference is one structural difference between the mock's response and
+// the upstream's.
+type Difference struct {
+	Kind string `json:"kind"`
+	// Path is the JSON path of a field, such as "$.data[*].id", or the
+	// header name. Array elements are merged under "[*]".
+	Path     string `json:"path,omitempty"`
+	Mock     string `json:"mock,omitempty"`     // Mock status, header value or JSON type
+	Upstream string `json:"upstream,omitempty"` // Upstream status, header value or JSON type
+}
+
+// key identifies a difference across comparisons, regardless of values.
+func (d Difference) key() string {
+	return d.Kind + " " + d.Path
+}
+
+// skipHeaders are headers set by the HTTP stack rather than the mock, or
+// that vary on every response.
+var skipHeaders = map[string]bool{
+	"Date":              true,
+	"Content-Length":    true,
+	"Transfer-Encoding": true,
+	"Connection":        true,
+	"Keep-Alive":        true,
+	"Server":            true,
+}
+
+// Compare diffs the mock's response against the upstream's and returns the
+// differences not ignored, sorted by kind and path.
+func Compare(mock, upstream *Response, ignore IgnoreRules) []Difference {
+	var diffs []Difference
+	if !ignore.Status && mock.StatusCode != upstream.StatusCode {
+		diffs = append(diffs, Difference{
+			Kind:     KindStatus,
+			Mock:     strconv.Itoa(mock.StatusCode),
+			Upstream: strconv.Itoa(upstream.StatusCode),
+		})
+	}
+	diffs = append(diffs, compareHeaders(mock.Header, upstream.Header, ignore.Headers)...)
+	diffs = append(diffs, compareBodies(mock.Body, upstream.Body, ignore)...)
+
+	sort.SliceStable(diffs, func(i, j int) bool {
+		if diffs[i].Kind != diffs[j].Kind {
+			return diffs[i].Kind < diffs[j].Kind
+		}
+		return diffs[i].Path < diffs[j].Path
+	})
+	return diffs
+}
+
+// compareHeaders checks that every header the mock sets is present upstream
+// and that the Content-Type media types match.
+func compareHeaders(mock, upstream http.Header, ignored []string) []Difference {
+	skip := make(map[string]bool, len(ignored))
+	for _, name := range ignored {
+		skip[http.CanonicalHeaderKey(name)] = true
+	}
+
+	names := make([]string, 0, len(mock))
+	for name := range mock {
+		names = append(names, http.CanonicalHeaderKey(name))
+	}
+	sort.Strings(names)
+
+	var diffs []Difference
+	for _, name := range names {
+		if skip[name] || skipHeaders[name] || strings.HasPrefix(name, "X-Mockd-") {
+			continue
+		}
+		mockValue := mock.Get(name)
+		upstreamValue := upstream.Get(name)
+		switch {
+		case len(upstream.Values(name)) == 0:
+			diffs = append(diffs, Difference{Kind: KindHeader, Path: name, Mock: mockValue})
+		case name == "Content-Type" && mediaType(mockValue) != mediaType(upstreamValue):
+			diffs = append(diffs, Difference{Kind: KindHeader, Path: name, Mock: mediaType(mockValue), Upstream: mediaType(upstreamValue)})
+		}
+	}
+	return diffs
+}
+
+// mediaType returns the lower-case media type of a Content-Type value.
+func mediaType(contentType string) string {
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mt
+	}
+	mt, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(mt))
+}
+
+// compareBodies compares the shapes of JSON bodies. Bodies that are both
+// non-JSON are not compared.
+func compareBodies(mockBody, upstreamBody []byte, ignore IgnoreRules) []Difference {
+	mockJSON, mockOK := decodeJSON(mockBody)
+	upstreamJSON, upstreamOK := decodeJSON(upstreamBody)
+	switch {
+	case !mockOK && !upstreamOK:
+		return nil
+	case mockOK != upstreamOK:
+		return []Difference{{Kind: KindBody, Path: "$", Mock: bodyFormat(mockBody, mockOK), Upstream: bodyFormat(upstreamBody, upstreamOK)}}
+	}
+
+	c := &comparer{ignore: ignore}
+	c.compare("$", shapeOf(mockJSON), shapeOf(upstreamJSON))
+	return c.diffs
+}
+
+// decodeJSON decodes a JSON body, reporting false for empty or non-JSON ones.
+func decodeJSON(body []byte) (any, bool) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil, false
+	}
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+// bodyFormat describes a body for a KindBody difference.
+func bodyFormat(body []byte, isJSON bool) string {
+	switch {
+	case isJSON:
+		return "json"
+	case len(bytes.TrimSpace(body)) == 0:
+		return "empty"
+	default:
+		return "non-json"
+	}
+}
+
+// shape is the merged structure of one or more JSON values: the types seen,
+// the fields of objects and the shape of array elements.
+type shape struct {
+	types  map[string]bool
+	fields map[string]*shape
+	elem   *shape
+}
+
+// shapeOf returns the shape of a decoded JSON value.
+func shapeOf(v any) *shape {
+	s := &shape{types: make(map[string]bool)}
+	s.add(v)
+	return s
+}
+
+// add merges a decoded JSON value into the shape.
+func (s *shape) add(v any) {
+	switch t := v.(type) {
+	case map[string]any:
+		s.types["object"] = true
+		if s.fields == nil {
+			s.fields = make(map[string]*shape)
+		}
+		for key, val := range t {
+			if f, ok := s.fields[key]; ok {
+				f.add(val)
+			} else {
+				s.fields[key] = shapeOf(val)
+			}
+		}
+	case []any:
+		s.types["array"] = true
+		for _, val := range t {
+			if s.elem == nil {
+				s.elem = shapeOf(val)
+			} else {
+				s.elem.add(val)
+			}
+		}
+	case string:
+		s.types["string"] = true
+	case float64:
+		s.types["number"] = true
+	case bool:
+		s.types["boolean"] = true
+	case nil:
+		s.types["null"] = true
+	}
+}
+
+// typeName lists the shape's types, ignoring null when another type was
+// seen: a field that is sometimes null is compatible with its type.
+func (s *shape) typeName() string {
+	names := make([]string, 0, len(s.types))
+	for t := range s.types {
+		if t != "null" || len(s.types) == 1 {
+			names = append(names, t)
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, "|")
+}
+
+// comparer collects the differences between two shapes.
+type comparer struct {
+	ignore IgnoreRules
+	diffs  []Difference
+}
+
+func (c *comparer) compare(path string, mock, upstream *shape) {
+	if c.ignored(path) {
+		return
+	}
+	if mt, ut := mock.typeName(), upstream.typeName(); mt != ut {
+		c.diffs = append(c.diffs, Difference{Kind: KindType, Path: path, Mock: mt, Upstream: ut})
+	}
+
+	if mock.types["object"] && upstream.types["object"] {
+		for key, u := range upstream.fields {
+			fieldPath := path + "." + key
+			m, ok := mock.fields[key]
+			switch {
+			case ok:
+				c.compare(fieldPath, m, u)
+			case !c.ignore.MissingFields && !c.ignored(fieldPath):
+				c.diffs = append(c.diffs, Difference{Kind: KindMissing, Path: fieldPath, Upstream: u.typeName()})
+			}
+		}
+		for key, m := range mock.fields {
+			fieldPath := path + "." + key
+			if _, ok := upstream.fields[key]; !ok && !c.ignored(fieldPath) {
+				c.diffs = append(c.diffs, Difference{Kind: KindExtra, Path: fieldPath, Mock: m.typeName()})
+			}
+		}
+	}
+
+	// Empty arrays have no element shape to compare
+	if mock.elem != nil && upstream.elem != nil {
+		c.compare(path+"[*]", mock.elem, upstream.elem)
+	}
+}
+
+// ignored reports whether a JSON path matches an ignore rule.
+func (c *comparer) ignored(path string) bool {
+	for _, rule := range c.ignore.Fields {
+		if strings.HasPrefix(rule, "$") {
+			if path == rule || strings.HasPrefix(path, rule+".") || strings.HasPrefix(path, rule+"[") {
+				return true
+			}
+			continue
+		}
+		for rest := path; ; {
+			i := strings.Index(rest, "."+rule)
+			if i < 0 {
+				break
+			}
+			end := i + 1 + len(rule)
+			if end == len(rest) || rest[end] == '.' || rest[end] == '[' {
+				return true
+			}
+			rest = rest[end:]
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package shadow
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func jsonResponse(status int, body string) *Response {
+	return &Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       []byte(body),
+	}
+}
+
+func TestCompare_Structural(t *testing.T) {
+	mock := jsonResponse(200, `{"id":"1","name":"Ann","age":30,"tags":["a"],"items":[{"sku":"x","qty":1}],"legacy":true}`)
+	upstream := jsonResponse(201, `{"id":"2","name":"Bob","age":"30","tags":[],"items":[{"sku":"y","qty":2,"price":9.5},{"sku":"z","qty":null}],"email":"b@example.com"}`)
+	upstream.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	got := Compare(mock, upstream, IgnoreRules{})
+	want := []Difference{
+		{Kind: KindExtra, Path: "$.legacy", Mock: "boolean"},
+		{Kind: KindMissing, Path: "$.email", Upstream: "string"},
+		{Kind: KindMissing, Path: "$.items[*].price", Upstream: "number"},
+		{Kind: KindStatus, Mock: "200", Upstream: "201"},
+		{Kind: KindType, Path: "$.age", Mock: "number", Upstream: "string"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Compare() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestCompare_IgnoreRules(t *testing.T) {
+	mock := jsonResponse(200, `{"data":[{"id":"1","created":1}],"meta":{"total":1}}`)
+	mock.Header.Set("X-Version", "2")
+	upstream := jsonResponse(404, `{"data":[{"id":"1","created":"2026-01-01","request_id":"r1"}],"meta":{"total":"1","page":{"next":null}},"request_id":"r1"}`)
+
+	ignore := IgnoreRules{
+		Status:  true,
+		Headers: []string{"x-version"},
+		Fields:  []string{"$.data[*].created", "$.meta", "request_id"},
+	}
+	if got := Compare(mock, upstream, ignore); len(got) != 0 {
+		t.Errorf("Compare() = %+v, want no differences", got)
+	}
+
+	ignore = IgnoreRules{MissingFields: true, Fields: []string{"$.data[*].created", "$.meta"}, Status: true, Headers: []string{"X-Version"}}
+	if got := Compare(mock, upstream, ignore); len(got) != 0 {
+		t.Errorf("Compare() with missingFields = %+v, want no differences", got)
+	}
+}
+
+func TestCompare_HeadersAndBodies(t *testing.T) {
+	mock := &Response{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": {"application/json"}, "X-Rate-Limit": {"100"}, "Date": {"today"}},
+		Body:       []byte(`{"ok":true}`),
+	}
+	upstream := &Response{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": {"text/html"}},
+		Body:       []byte(`<html>maintenance</html>`),
+	}
+
+	got := Compare(mock, upstream, IgnoreRules{})
+	want := []Difference{
+		{Kind: KindBody, Path: "$", Mock: "json", Upstream: "non-json"},
+		{Kind: KindHeader, Path: "Content-Type", Mock: "application/json", Upstream: "text/html"},
+		{Kind: KindHeader, Path: "X-Rate-Limit", Mock: "100"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Compare() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// Non-JSON bodies are not compared
+	text := &Response{StatusCode: 200, Header: http.Header{}, Body: []byte("pong")}
+	if got := Compare(text, &Response{StatusCode: 200, Header: http.Header{}, Body: []byte("PONG")}, IgnoreRules{}); len(got) != 0 {
+		t.Errorf("Compare() of text bodies = %+v", got)
+	}
+}
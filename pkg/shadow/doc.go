@@ -0,0 +1,35 @@
+// Package shadow compares mock responses with the real upstream API.
+//
+// In shadow mode the mock answers the client as usual, and a copy of the
+// request is sent asynchronously to the upstream. The two responses are
+// diffed structurally rather than byte for byte:
+//
+//   - Status: the status codes must be equal
+//   - Headers: the Content-Type media types must be equal, and every header
+//     the mock sets must be present upstream
+//   - Body: JSON bodies must have the same shape — the same fields, with
+//     values of the same JSON types. Array elements are merged into one
+//     shape, so lists of different lengths compare equal
+//
+// Ignore rules drop differences that are expected, such as fields only the
+// real API returns. The Tracker aggregates the differences per mock into a
+// drift report and counts comparisons in Prometheus metrics.
+//
+// # Usage
+//
+//	tracker := shadow.NewTracker()
+//	s, err := shadow.New(&shadow.Config{
+//		Enabled:  true,
+//		Upstream: "https://api.example.com",
+//		Ignore:   shadow.IgnoreRules{Fields: []string{"request_id"}},
+//	}, tracker)
+//
+//	// In the HTTP handler, after matching a mock:
+//	if s.Selects(mockID, r.Method, r.URL.Path) {
+//		capture := shadow.NewCapture(w)
+//		// ... write the mock response to capture ...
+//		s.Submit(mockID, r, body, capture.Response())
+//	}
+//
+//	report := tracker.Report()
+package shadow
@@ -0,0 +1,234 @@
+package shadow
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/getmockd/mockd/pkg/metrics"
+)
+
+// maxDifferences bounds the distinct differences kept per mock.
+const maxDifferences = 100
+
+// Comparison results, the result label of mockd_shadow_comparisons_total.
+const (
+	ResultMatch = "match"
+	ResultDrift = "drift"
+	ResultError = "error"
+)
+
+// Report is the drift report: shadow comparison counts and the differences
+// found for each mock.
+type Report struct {
+	// Enabled and Upstream describe the current shadow configuration.
+	Enabled  bool   `json:"enabled"`
+	Upstream string `json:"upstream,omitempty"`
+
+	Compared int64 `json:"compared"`
+	Drifted  int64 `json:"drifted"`
+	Errors   int64 `json:"errors"`
+	// Dropped counts requests not shadowed because the queue was full.
+	Dropped int64 `json:"dropped,omitempty"`
+
+	// Mocks lists the shadowed mocks, drifted ones first.
+	Mocks []MockDrift `json:"mocks"`
+}
+
+// MockDrift is the drift of one mock.
+type MockDrift struct {
+	MockID string `json:"mockId"`
+	// Method and Path are those of the last shadowed request.
+	Method string `json:"method"`
+	Path   string `json:"path"`
+
+	Compared int64 `json:"compared"`
+	Drifted  int64 `json:"drifted"`
+	Errors   int64 `json:"errors"`
+
+	LastCompared time.Time  `json:"lastCompared"`
+	LastDrift    *time.Time `json:"lastDrift,omitempty"`
+	// LastError is the last upstream request error.
+	LastError string `json:"lastError,omitempty"`
+
+	// Differences lists the distinct differences seen, most frequent first.
+	Differences []DriftDifference `json:"differences,omitempty"`
+}
+
+// DriftDifference is a difference with the number of comparisons that
+// found it. Mock and Upstream hold the values of the last one.
+type DriftDifference struct {
+	Difference
+	Count    int64     `json:"count"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// Tracker aggregates shadow comparisons into a drift report. It is safe for
+// concurrent use and outlives configuration changes.
+type Tracker struct {
+	mu      sync.Mutex
+	mocks   map[string]*mockDrift
+	dropped int64
+}
+
+// mockDrift is the tracked state of one mock.
+type mockDrift struct {
+	MockDrift
+	differences map[string]*DriftDifference
+}
+
+// NewTracker creates an empty drift tracker.
+func NewTracker() *Tracker {
+	return &Tracker{mocks: make(map[string]*mockDrift)}
+}
+
+// Record records the differences of a comparison; none means no drift.
+func (t *Tracker) Record(mockID, method, path string, diffs []Difference) {
+	now := time.Now()
+	t.mu.Lock()
+	md := t.mock(mockID, method, path)
+	md.Compared++
+	md.LastCompared = now
+	if len(diffs) > 0 {
+		md.Drifted++
+		md.LastDrift = &now
+		for _, d := range diffs {
+			key := d.key()
+			dd, ok := md.differences[key]
+			if !ok {
+				if len(md.differences) >= maxDifferences {
+					continue
+				}
+				dd = &DriftDifference{}
+				md.differences[key] = dd
+			}
+			dd.Difference = d
+			dd.Count++
+			dd.LastSeen = now
+		}
+	}
+	t.mu.Unlock()
+
+	result := ResultMatch
+	if len(diffs) > 0 {
+		result = ResultDrift
+	}
+	recordComparison(mockID, result)
+	for _, d := range diffs {
+		recordDifference(mockID, d.Kind)
+	}
+}
+
+// RecordError records an upstream request that failed.
+func (t *Tracker) RecordError(mockID, method, path string, err error) {
+	t.mu.Lock()
+	md := t.mock(mockID, method, path)
+	md.Errors++
+	md.LastError = err.Error()
+	t.mu.Unlock()
+
+	recordComparison(mockID, ResultError)
+}
+
+// RecordDropped counts a request not shadowed because the queue was full.
+func (t *Tracker) RecordDropped() {
+	t.mu.Lock()
+	t.dropped++
+	t.mu.Unlock()
+}
+
+// mock returns the state of a mock, creating it. t.mu must be held.
+func (t *Tracker) mock(mockID, method, path string) *mockDrift {
+	md, ok := t.mocks[mockID]
+	if !ok {
+		md = &mockDrift{
+			MockDrift:   MockDrift{MockID: mockID},
+			differences: make(map[string]*DriftDifference),
+		}
+		t.mocks[mockID] = md
+	}
+	md.Method = method
+	md.Path = path
+	return md
+}
+
+// Report returns the drift report. Enabled and Upstream are left to the caller.
+func (t *Tracker) Report() *Report {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	report := &Report{Dropped: t.dropped, Mocks: make([]MockDrift, 0, len(t.mocks))}
+	for _, md := range t.mocks {
+		report.Compared += md.Compared
+		report.Drifted += md.Drifted
+		report.Errors += md.Errors
+		report.Mocks = append(report.Mocks, md.snapshot())
+	}
+	sort.Slice(report.Mocks, func(i, j int) bool {
+		a, b := report.Mocks[i], report.Mocks[j]
+		if a.Drifted != b.Drifted {
+			return a.Drifted > b.Drifted
+		}
+		return a.MockID < b.MockID
+	})
+	return report
+}
+
+// Mock returns the drift of one mock, or false if it was never shadowed.
+func (t *Tracker) Mock(mockID string) (MockDrift, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	md, ok := t.mocks[mockID]
+	if !ok {
+		return MockDrift{}, false
+	}
+	return md.snapshot(), true
+}
+
+// Reset clears the report. Prometheus counters keep counting.
+func (t *Tracker) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.mocks = make(map[string]*mockDrift)
+	t.dropped = 0
+}
+
+// snapshot copies the mock's drift with its differences sorted. t.mu must
+// be held.
+func (md *mockDrift) snapshot() MockDrift {
+	out := md.MockDrift
+	if md.LastDrift != nil {
+		last := *md.LastDrift
+		out.LastDrift = &last
+	}
+	out.Differences = make([]DriftDifference, 0, len(md.differences))
+	for _, dd := range md.differences {
+		out.Differences = append(out.Differences, *dd)
+	}
+	sort.Slice(out.Differences, func(i, j int) bool {
+		a, b := out.Differences[i], out.Differences[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.key() < b.key()
+	})
+	return out
+}
+
+// recordComparison increments mockd_shadow_comparisons_total.
+func recordComparison(mockID, result string) {
+	if metrics.ShadowComparisonsTotal != nil {
+		if vec, err := metrics.ShadowComparisonsTotal.WithLabels(mockID, result); err == nil {
+			_ = vec.Inc()
+		}
+	}
+}
+
+// recordDifference increments mockd_shadow_differences_total.
+func recordDifference(mockID, kind string) {
+	if metrics.ShadowDifferencesTotal != nil {
+		if vec, err := metrics.ShadowDifferencesTotal.WithLabels(mockID, kind); err == nil {
+			_ = vec.Inc()
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package shadow
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	mathrand "math/rand/v2"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Shadow request limits. Requests arriving while the queue is full are
+// dropped rather than delaying mock responses.
+const (
+	queueSize   = 256
+	workerCount = 4
+	maxBodySize = 10 << 20 // 10MB
+)
+
+// hopHeaders are not forwarded to the upstream. Accept-Encoding is left to
+// the transport so compressed responses are decoded before comparison.
+var hopHeaders = []string{
+	"Connection", "Keep-Alive", "Proxy-Authenticate", "Proxy-Authorization",
+	"Proxy-Connection", "Te", "Trailer", "Transfer-Encoding", "Upgrade",
+	"Content-Length", "Accept-Encoding",
+}
+
+// Shadower sends selected mock requests to the upstream in the background
+// and records how the upstream's responses differ from the mock's.
+type Shadower struct {
+	cfg      Config
+	tracker  *Tracker
+	client   *http.Client
+	timeout  time.Duration
+	upstream *url.URL
+	pathRe   *regexp.Regexp
+	mockIDs  map[string]bool
+	methods  map[string]bool
+
+	mu     sync.RWMutex // guards closed and sends on queue
+	closed bool
+	queue  chan *job
+	wg     sync.WaitGroup
+}
+
+// job is a mock request and response waiting to be shadowed.
+type job struct {
+	mockID   string
+	method   string
+	path     string
+	rawQuery string
+	header   http.Header
+	body     []byte
+	mock     *Response
+}
+
+// New validates cfg and starts a Shadower recording into tracker.
+func New(cfg *Config, tracker *Tracker) (*Shadower, error) {
+	if cfg == nil || !cfg.Enabled {
+		return nil, errors.New("shadow mode is not enabled")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	upstream, _ := url.Parse(cfg.Upstream)
+
+	// Redirects are compared as returned rather than followed, since the
+	// mock returns the redirect itself
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+
+	s := &Shadower{
+		cfg:      *cfg,
+		tracker:  tracker,
+		client:   client,
+		timeout:  cfg.timeout(),
+		upstream: upstream,
+		mockIDs:  make(map[string]bool, len(cfg.MockIDs)),
+		methods:  make(map[string]bool, len(cfg.Methods)),
+		queue:    make(chan *job, queueSize),
+	}
+	if cfg.PathPattern != "" {
+		s.pathRe = regexp.MustCompile(cfg.PathPattern)
+	}
+	for _, id := range cfg.MockIDs {
+		s.mockIDs[id] = true
+	}
+	methods := cfg.Methods
+	if len(methods) == 0 {
+		methods = DefaultMethods
+	}
+	for _, m := range methods {
+		s.methods[strings.ToUpper(m)] = true
+	}
+
+	for range workerCount {
+		s.wg.Add(1)
+		go s.work()
+	}
+	return s, nil
+}
+
+// Config returns the shadow configuration.
+func (s *Shadower) Config() Config {
+	return s.cfg
+}
+
+// Selects reports whether a request served by a mock should be shadowed,
+// applying the mock, path and method filters and the sample rate.
+func (s *Shadower) Selects(mockID, method, path string) bool {
+	if len(s.mockIDs) > 0 && !s.mockIDs[mockID] {
+		return false
+	}
+	if !s.methods[method] {
+		return false
+	}
+	if s.pathRe != nil && !s.pathRe.MatchString(path) {
+		return false
+	}
+	if s.cfg.SampleRate > 0 && mathrand.Float64() >= s.cfg.SampleRate { //nolint:gosec // sampling, not security
+		return false
+	}
+	return true
+}
+
+// Submit queues a request for the upstream and the mock's response to it.
+// It copies what it needs from r and never blocks: when the queue is full
+// the request is dropped and counted in the report.
+func (s *Shadower) Submit(mockID string, r *http.Request, body []byte, mock *Response) {
+	j := &job{
+		mockID:   mockID,
+		method:   r.Method,
+		path:     r.URL.Path,
+		rawQuery: r.URL.RawQuery,
+		header:   r.Header.Clone(),
+		body:     bytes.Clone(body),
+		mock:     mock,
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.queue <- j:
+	default:
+		s.tracker.RecordDropped()
+	}
+}
+
+// Close stops accepting requests and waits for queued ones to finish.
+func (s *Shadower) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.queue)
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// work shadows queued requests until the queue is closed.
+func (s *Shadower) work() {
+	defer s.wg.Done()
+	for j := range s.queue {
+		upstream, err := s.forward(j)
+		if err != nil {
+			s.tracker.RecordError(j.mockID, j.method, j.path, err)
+			continue
+		}
+		s.tracker.Record(j.mockID, j.method, j.path, Compare(j.mock, upstream, s.cfg.Ignore))
+	}
+}
+
+// forward sends a queued request to the upstream and reads its response.
+func (s *Shadower) forward(j *job) (*Response, error) {
+	target := *s.upstream
+	target.Path = strings.TrimSuffix(s.upstream.Path, "/") + j.path
+	target.RawPath = ""
+	target.RawQuery = j.rawQuery
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, j.method, target.String(), bytes.NewReader(j.body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build upstream request: %w", err)
+	}
+	req.Header = j.header
+	for _, name := range hopHeaders {
+		req.Header.Del(name)
+	}
+	for name, value := range s.cfg.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("upstream request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upstream response: %w", err)
+	}
+	return &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: body}, nil
+}
+
+// Capture is an http.ResponseWriter that keeps a copy of the response
+// written through it, for comparison with the upstream.
+type Capture struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+// NewCapture wraps w.
+func NewCapture(w http.ResponseWriter) *Capture {
+	return &Capture{ResponseWriter: w}
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (c *Capture) WriteHeader(code int) {
+	if c.status == 0 {
+		c.status = code
+	}
+	c.ResponseWriter.WriteHeader(code)
+}
+
+// Write implements http.ResponseWriter.
+func (c *Capture) Write(b []byte) (int, error) {
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+	if c.body.Len() < maxBodySize {
+		c.body.Write(b[:min(len(b), maxBodySize-c.body.Len())])
+	}
+	return c.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController.
+func (c *Capture) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+// Response returns the captured response.
+func (c *Capture) Response() *Response {
+	status := c.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return &Response{
+		StatusCode: status,
+		Header:     c.Header().Clone(),
+		Body:       bytes.Clone(c.body.Bytes()),
+	}
+}
@@ -0,0 +1,203 @@
+package shadow
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getmockd/mockd/pkg/metrics"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"disabled", Config{}, false},
+		{"valid", Config{Enabled: true, Upstream: "https://api.example.com/v1", SampleRate: 0.5, Timeout: "2s"}, false},
+		{"no upstream", Config{Enabled: true}, true},
+		{"bad scheme", Config{Enabled: true, Upstream: "ftp://example.com"}, true},
+		{"bad pattern", Config{Enabled: true, Upstream: "http://x", PathPattern: "("}, true},
+		{"bad sample rate", Config{Enabled: true, Upstream: "http://x", SampleRate: 2}, true},
+		{"bad timeout", Config{Enabled: true, Upstream: "http://x", Timeout: "soon"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestShadower_Selects(t *testing.T) {
+	s, err := New(&Config{
+		Enabled:     true,
+		Upstream:    "http://upstream",
+		MockIDs:     []string{"users"},
+		Methods:     []string{"get"},
+		PathPattern: "^/api/",
+	}, NewTracker())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	tests := []struct {
+		mockID, method, path string
+		want                 bool
+	}{
+		{"users", "GET", "/api/users", true},
+		{"orders", "GET", "/api/orders", false},
+		{"users", "POST", "/api/users", false},
+		{"users", "GET", "/health", false},
+	}
+	for _, tt := range tests {
+		if got := s.Selects(tt.mockID, tt.method, tt.path); got != tt.want {
+			t.Errorf("Selects(%s, %s, %s) = %v, want %v", tt.mockID, tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestShadower_SelectsSafeMethodsByDefault(t *testing.T) {
+	s, err := New(&Config{Enabled: true, Upstream: "http://upstream"}, NewTracker())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	for method, want := range map[string]bool{
+		http.MethodGet: true, http.MethodHead: true, http.MethodOptions: true,
+		http.MethodPost: false, http.MethodPut: false, http.MethodPatch: false, http.MethodDelete: false,
+	} {
+		if got := s.Selects("users", method, "/api/users"); got != want {
+			t.Errorf("Selects(%s) = %v, want %v", method, got, want)
+		}
+	}
+}
+
+func TestShadower_DoesNotFollowRedirects(t *testing.T) {
+	var followed bool
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			followed = true
+			_, _ = w.Write([]byte("login page"))
+			return
+		}
+		http.Redirect(w, r, "/login", http.StatusFound)
+	}))
+	defer upstream.Close()
+
+	tracker := NewTracker()
+	s, err := New(&Config{Enabled: true, Upstream: upstream.URL}, tracker)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	capture := NewCapture(rec)
+	capture.Header().Set("Location", "/login")
+	capture.WriteHeader(http.StatusFound)
+	s.Submit("redirect-mock", httptest.NewRequest(http.MethodGet, "/account", nil), nil, capture.Response())
+	s.Close()
+
+	if followed {
+		t.Error("upstream redirect was followed")
+	}
+	drift, ok := tracker.Mock("redirect-mock")
+	if !ok || drift.Compared != 1 || drift.Drifted != 0 {
+		t.Errorf("drift = %+v", drift)
+	}
+}
+
+func TestShadower_ComparesWithUpstream(t *testing.T) {
+	metrics.Reset()
+	registry := metrics.Init()
+	defer metrics.Reset()
+
+	var gotPath, gotAuth, gotBody string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.RequestURI()
+		gotAuth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"7","name":"Ann","email":"a@example.com"}`))
+	}))
+	defer upstream.Close()
+
+	tracker := NewTracker()
+	s, err := New(&Config{
+		Enabled:  true,
+		Upstream: upstream.URL + "/v1/",
+		Headers:  map[string]string{"Authorization": "Bearer real"},
+	}, tracker)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The mock answers through a capturing writer
+	rec := httptest.NewRecorder()
+	capture := NewCapture(rec)
+	capture.Header().Set("Content-Type", "application/json")
+	capture.WriteHeader(http.StatusOK)
+	_, _ = capture.Write([]byte(`{"id":"7","name":"Ann"}`))
+	if rec.Body.String() != `{"id":"7","name":"Ann"}` {
+		t.Fatalf("client got %q", rec.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/users/7?expand=1", strings.NewReader(`{"name":"Ann"}`))
+	s.Submit("user-mock", req, []byte(`{"name":"Ann"}`), capture.Response())
+
+	failing, err := New(&Config{Enabled: true, Upstream: "http://127.0.0.1:1", Timeout: "1s"}, tracker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failing.Submit("other-mock", httptest.NewRequest(http.MethodGet, "/x", nil), nil, capture.Response())
+
+	// Close waits for queued requests to be shadowed
+	s.Close()
+	failing.Close()
+
+	if gotPath != "/v1/users/7?expand=1" || gotAuth != "Bearer real" || gotBody != `{"name":"Ann"}` {
+		t.Errorf("upstream got path %q, auth %q, body %q", gotPath, gotAuth, gotBody)
+	}
+
+	report := tracker.Report()
+	if report.Compared != 1 || report.Drifted != 1 || report.Errors != 1 || len(report.Mocks) != 2 {
+		t.Fatalf("report = %+v", report)
+	}
+	drift, ok := tracker.Mock("user-mock")
+	if !ok || drift.Method != http.MethodPost || drift.Path != "/users/7" || len(drift.Differences) != 1 {
+		t.Fatalf("drift = %+v", drift)
+	}
+	if d := drift.Differences[0]; d.Kind != KindMissing || d.Path != "$.email" || d.Count != 1 {
+		t.Errorf("difference = %+v", d)
+	}
+	if other, _ := tracker.Mock("other-mock"); other.Errors != 1 || other.LastError == "" {
+		t.Errorf("failed upstream = %+v", other)
+	}
+
+	rr := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for _, line := range []string{
+		`mockd_shadow_comparisons_total{mock_id="user-mock",result="drift"} 1`,
+		`mockd_shadow_comparisons_total{mock_id="other-mock",result="error"} 1`,
+		`mockd_shadow_differences_total{kind="missing",mock_id="user-mock"} 1`,
+	} {
+		if !strings.Contains(rr.Body.String(), line) {
+			t.Errorf("metrics missing %s", line)
+		}
+	}
+
+	tracker.Reset()
+	if report := tracker.Report(); report.Compared != 0 || len(report.Mocks) != 0 {
+		t.Errorf("report after reset = %+v", report)
+	}
+
+	// Submitting after Close is a no-op
+	s.Submit("user-mock", req, nil, capture.Response())
+}
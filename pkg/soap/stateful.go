@@ -96,7 +96,7 @@ func (h *Handler) GetStatefulExecutor() StatefulExecutor {
 // handleStatefulOperation executes a stateful CRUD operation and returns
 // the SOAP response body XML. Returns (nil, nil) if the operation is not stateful.
 // opName is the SOAP operation name (e.g., "TransferFunds"), needed for custom operation lookup.
-func (h *Handler) handleStatefulOperation(opName string, opConfig *OperationConfig, doc *etree.Document) ([]byte, *SOAPFault) {
+func (h *Handler) handleStatefulOperation(ctx context.Context, opName string, opConfig *OperationConfig, doc *etree.Document) ([]byte, *SOAPFault) {
 	if opConfig.StatefulBinding == nil || h.statefulExecutor == nil {
 		return nil, nil
 	}
@@ -105,7 +105,7 @@ func (h *Handler) handleStatefulOperation(opName string, opConfig *OperationConf
 	req := buildStatefulRequest(opName, opConfig, doc)
 
 	// Execute via the executor (provided by the engine, backed by stateful.Bridge)
-	result := h.statefulExecutor.ExecuteStateful(context.Background(), req)
+	result := h.statefulExecutor.ExecuteStateful(ctx, req)
 
 	// Check for errors — return as SOAP fault
 	if result.Error != nil {
@@ -360,8 +360,10 @@ func writeValueAsXML(buf *bytes.Buffer, key string, value interface{}) {
 	}
 }
 
-// singularize performs a simple pluralization removal.
-// "users" → "user", "orders" → "order", "addresses" → "address"
+// singularize performs a simple pluralization removal for SOAP element names.
+// "users" → "user", "orders" → "order", "addresses" → "address",
+// "statuses" → "status". Unlike util.Singularize it strips "es" after any
+// "s", which existing SOAP responses depend on.
 func singularize(s string) string {
 	if strings.HasSuffix(s, "ses") {
 		return strings.TrimSuffix(s, "es")
@@ -806,6 +806,9 @@ func TestSingularize(t *testing.T) {
 		{"orders", "order"},
 		{"addresses", "address"},
 		{"categories", "category"},
+		{"statuses", "status"},
+		{"buses", "bus"},
+		{"aliases", "alias"},
 		{"class", "class"},   // "ss" ending should not be stripped
 		{"data", "data"},     // doesn't end in 's'
 		{"person", "person"}, // doesn't end in 's'
@@ -2,6 +2,7 @@ package sse
 
 import (
 	"context"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
@@ -9,12 +10,15 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/getmockd/mockd/pkg/chaos"
 	"github.com/getmockd/mockd/pkg/config"
+	"github.com/getmockd/mockd/pkg/logging"
 	"github.com/getmockd/mockd/pkg/metrics"
 	"github.com/getmockd/mockd/pkg/mock"
 	"github.com/getmockd/mockd/pkg/protocol"
 	"github.com/getmockd/mockd/pkg/recording"
 	"github.com/getmockd/mockd/pkg/requestlog"
+	"github.com/getmockd/mockd/pkg/stateful"
 	"github.com/getmockd/mockd/pkg/template"
 	"github.com/getmockd/mockd/pkg/util"
 )
@@ -22,6 +26,10 @@ import (
 // SSERecordingHookFactory creates SSE recording hooks for new connections.
 type SSERecordingHookFactory func(streamID string, mockID string, path string) (recording.SSERecordingHook, error)
 
+// ChaosSource returns the chaos injector currently in effect, or nil when
+// chaos is off. It is consulted once per new stream.
+type ChaosSource func() *chaos.Injector
+
 // SSEHandler handles SSE streaming responses for mock endpoints.
 type SSEHandler struct {
 	encoder                *Encoder
@@ -36,6 +44,12 @@ type SSEHandler struct {
 	id                     string                  // handler ID for protocol.Handler interface
 	requestLoggerMu        sync.RWMutex            // mutex for thread-safe requestLogger access
 	requestLogger          requestlog.Logger       // logger for SSE request events
+	statefulStoreMu        sync.RWMutex            // mutex for thread-safe statefulStore access
+	statefulStore          *stateful.StateStore    // source for statefulStream endpoints
+	chaosSourceMu          sync.RWMutex            // mutex for thread-safe chaosSource access
+	chaosSource            ChaosSource             // optional: message chaos for new streams
+	logMu                  sync.RWMutex            // mutex for thread-safe log access
+	log                    *slog.Logger            // operational logger for errors/warnings
 }
 
 // NewSSEHandler creates a new SSE handler.
@@ -49,6 +63,24 @@ func NewSSEHandler(maxConnections int) *SSEHandler {
 	}
 }
 
+// SetOperationalLogger sets the logger for errors and warnings.
+// This method is thread-safe.
+func (h *SSEHandler) SetOperationalLogger(log *slog.Logger) {
+	h.logMu.Lock()
+	defer h.logMu.Unlock()
+	h.log = log
+}
+
+// logger returns the operational logger, or a no-op logger if none is set.
+func (h *SSEHandler) logger() *slog.Logger {
+	h.logMu.RLock()
+	defer h.logMu.RUnlock()
+	if h.log == nil {
+		return logging.Nop()
+	}
+	return h.log
+}
+
 // SetRecordingHookFactory sets a factory for creating per-connection recording hooks.
 func (h *SSEHandler) SetRecordingHookFactory(factory SSERecordingHookFactory) {
 	h.recordingHookFactoryMu.Lock()
@@ -79,6 +111,25 @@ func (h *SSEHandler) GetRequestLogger() requestlog.Logger {
 	return h.requestLogger
 }
 
+// SetChaosSource sets where new streams get their message chaos from.
+func (h *SSEHandler) SetChaosSource(source ChaosSource) {
+	h.chaosSourceMu.Lock()
+	defer h.chaosSourceMu.Unlock()
+	h.chaosSource = source
+}
+
+// messageChaos returns the message chaos for a new stream on path, or nil
+// when no sse chaos rule applies.
+func (h *SSEHandler) messageChaos(path string) *chaos.MessageChaos {
+	h.chaosSourceMu.RLock()
+	source := h.chaosSource
+	h.chaosSourceMu.RUnlock()
+	if source == nil {
+		return nil
+	}
+	return source().NewMessageChaos(chaos.ProtocolSSE, path)
+}
+
 // SetTemplateEngine sets the template engine for processing response templates.
 func (h *SSEHandler) SetTemplateEngine(engine *template.Engine) {
 	h.templateEngine = engine
@@ -208,6 +259,21 @@ func (h *SSEHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, m *config
 		return
 	}
 
+	// Subscribe to the table before committing to a stream so a missing
+	// table or bad filter is reported as a normal HTTP error.
+	var changes *stateful.ChangeStream
+	var sub *stateful.Subscription
+	if m.HTTP.SSE.StatefulStream != nil {
+		var status int
+		var err error
+		changes, sub, status, err = h.subscribeChanges(r, m)
+		if err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+		defer sub.Close()
+	}
+
 	// Set SSE headers
 	h.setSSEHeaders(w)
 
@@ -241,6 +307,8 @@ func (h *SSEHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, m *config
 		writer:    w,
 		flusher:   flusher,
 		config:    sseConfig,
+		changes:   changes,
+		chaos:     h.messageChaos(r.URL.Path),
 	}
 
 	// Check for Last-Event-ID header for resumption
@@ -278,7 +346,11 @@ func (h *SSEHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, m *config
 
 	// Run the stream
 	stream.Status = StreamStatusActive
-	h.runStream(stream, m.HTTP.SSE)
+	if sub != nil {
+		h.runChangeStream(stream, sub)
+	} else {
+		h.runStream(stream, m.HTTP.SSE)
+	}
 
 	// Log connection close with duration and event count
 	durationMs := int(time.Since(stream.StartTime).Milliseconds())
@@ -759,8 +831,9 @@ func (h *SSEHandler) sendEvent(stream *SSEStream, event *SSEEventDef, eventIndex
 		event.ID = strconv.FormatInt(eventIndex+1, 10)
 	}
 
-	// Process template expressions in event data
-	if h.templateEngine != nil {
+	// Process template expressions in event data. Live table changes carry
+	// user data and are sent verbatim.
+	if h.templateEngine != nil && stream.changes == nil {
 		event.Data = h.templateEngine.ProcessInterface(event.Data, nil)
 	}
 
@@ -770,9 +843,19 @@ func (h *SSEHandler) sendEvent(stream *SSEStream, event *SSEEventDef, eventIndex
 		return err
 	}
 
+	// Route the event through message chaos, which may drop, delay, duplicate
+	// or hold it back. A message_close fault ends the stream.
+	sent := *event
+	return stream.chaos.Send(stream.ctx, len(formatted), func() error {
+		return h.writeEvent(stream, &sent, formatted, eventIndex)
+	})
+}
+
+// writeEvent writes a formatted event to the client and records it.
+func (h *SSEHandler) writeEvent(stream *SSEStream, event *SSEEventDef, formatted string, eventIndex int64) error {
 	// Write to response
 	stream.mu.Lock()
-	_, err = stream.writer.Write([]byte(formatted))
+	_, err := stream.writer.Write([]byte(formatted))
 	if err != nil {
 		stream.mu.Unlock()
 		return err
@@ -840,6 +923,8 @@ func (h *SSEHandler) handleTermination(stream *SSEStream, terminationType string
 
 	switch terminationType {
 	case TerminationGraceful:
+		// Deliver events still held back by message chaos
+		_ = stream.chaos.Flush()
 		// Send final event if configured
 		if termConfig.FinalEvent != nil {
 			_ = h.sendEvent(stream, termConfig.FinalEvent, stream.EventsSent)
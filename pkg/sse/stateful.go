@@ -0,0 +1,131 @@
+package sse
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/getmockd/mockd/pkg/config"
+	"github.com/getmockd/mockd/pkg/stateful"
+)
+
+// SetStatefulStore sets the store whose change feed backs statefulStream endpoints.
+// This method is thread-safe.
+func (h *SSEHandler) SetStatefulStore(store *stateful.StateStore) {
+	h.statefulStoreMu.Lock()
+	defer h.statefulStoreMu.Unlock()
+	h.statefulStore = store
+}
+
+// GetStatefulStore returns the store used for statefulStream endpoints.
+// This method is thread-safe.
+func (h *SSEHandler) GetStatefulStore() *stateful.StateStore {
+	h.statefulStoreMu.RLock()
+	defer h.statefulStoreMu.RUnlock()
+	return h.statefulStore
+}
+
+// subscribeChanges compiles the mock's statefulStream config and subscribes to
+// the table's change feed, in the isolation partition carried by the request
+// context if any. On failure it returns the HTTP status to report.
+func (h *SSEHandler) subscribeChanges(r *http.Request, m *config.MockConfiguration) (*stateful.ChangeStream, *stateful.Subscription, int, error) {
+	store := h.GetStatefulStore()
+	if store == nil {
+		return nil, nil, http.StatusServiceUnavailable, errors.New("stateful store not available")
+	}
+
+	changes, err := stateful.CompileChangeStream(m.HTTP.SSE.StatefulStream)
+	if err != nil {
+		return nil, nil, http.StatusInternalServerError, err
+	}
+
+	sub, err := changes.Subscribe(store, stateful.PartitionWorkspaceID(m.WorkspaceID, stateful.PartitionFromContext(r.Context())))
+	if err != nil {
+		var notFound *stateful.NotFoundError
+		if errors.As(err, &notFound) {
+			return nil, nil, http.StatusNotFound, err
+		}
+		return nil, nil, http.StatusInternalServerError, err
+	}
+	return changes, sub, http.StatusOK, nil
+}
+
+// runChangeStream forwards table changes to the client until it disconnects,
+// the subscription closes, or a lifecycle limit is reached.
+func (h *SSEHandler) runChangeStream(stream *SSEStream, sub *stateful.Subscription) {
+	ctx := stream.ctx
+
+	retryDirective := h.encoder.FormatRetry(DefaultRetryMs)
+	stream.mu.Lock()
+	_, err := stream.writer.Write([]byte(retryDirective))
+	if err == nil {
+		stream.flusher.Flush()
+	}
+	stream.mu.Unlock()
+	if err != nil {
+		stream.Status = StreamStatusClosed
+		return
+	}
+	stream.BytesSent += int64(len(retryDirective))
+
+	var keepaliveCh <-chan time.Time
+	if stream.config.Lifecycle.KeepaliveInterval > 0 {
+		ticker := time.NewTicker(time.Duration(stream.config.Lifecycle.KeepaliveInterval) * time.Second)
+		defer ticker.Stop()
+		keepaliveCh = ticker.C
+	}
+
+	var timeoutCh <-chan time.Time
+	if stream.config.Lifecycle.ConnectionTimeout > 0 {
+		timer := time.NewTimer(time.Duration(stream.config.Lifecycle.ConnectionTimeout) * time.Second)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+
+	var eventCount int64
+	for {
+		if stream.config.Lifecycle.MaxEvents > 0 && eventCount >= int64(stream.config.Lifecycle.MaxEvents) {
+			h.handleTermination(stream, TerminationGraceful)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			stream.Status = StreamStatusClosed
+			return
+
+		case <-timeoutCh:
+			h.handleTermination(stream, TerminationGraceful)
+			return
+
+		case <-keepaliveCh:
+			if err := h.sendKeepalive(stream); err != nil {
+				stream.Status = StreamStatusClosed
+				return
+			}
+
+		case change, ok := <-sub.Events():
+			if !ok {
+				h.handleTermination(stream, TerminationGraceful)
+				return
+			}
+			// Filter errors (e.g. a field missing on some items) skip the change.
+			match, err := stream.changes.Match(change)
+			if err != nil {
+				h.logger().Warn("stateful stream filter failed, change skipped",
+					"table", stream.changes.Table(), "event", change.Type, "id", change.ItemID, "error", err)
+			}
+			if !match {
+				continue
+			}
+			msg := stream.changes.Render(change)
+			event := &SSEEventDef{Type: msg.Event, ID: msg.ID, Data: msg.Data}
+			if err := h.sendEvent(stream, event, eventCount); err != nil {
+				stream.Status = StreamStatusClosed
+				return
+			}
+			eventCount++
+			stream.EventsSent = eventCount
+		}
+	}
+}
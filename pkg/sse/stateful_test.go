@@ -0,0 +1,221 @@
+package sse
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getmockd/mockd/pkg/config"
+	"github.com/getmockd/mockd/pkg/mock"
+	"github.com/getmockd/mockd/pkg/stateful"
+)
+
+func newStreamMockConfig(stream *mock.StatefulStreamConfig, lifecycle mock.SSELifecycleConfig) *config.MockConfiguration {
+	return &config.MockConfiguration{
+		ID:   "orders-stream",
+		Type: mock.TypeHTTP,
+		HTTP: &mock.HTTPSpec{
+			Matcher: &mock.HTTPMatcher{Method: "GET", Path: "/v1/orders/stream"},
+			SSE: &mock.SSEConfig{
+				StatefulStream: stream,
+				Lifecycle:      lifecycle,
+			},
+		},
+	}
+}
+
+// waitForSubscriber reports whether a client subscribed to the store's
+// change feed within two seconds.
+func waitForSubscriber(store *stateful.StateStore) bool {
+	deadline := time.Now().Add(2 * time.Second)
+	for store.Changes().SubscriberCount() == 0 {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return true
+}
+
+func TestStatefulStream_PushesFilteredChanges(t *testing.T) {
+	store := stateful.NewStateStore()
+	if err := store.Register("", &stateful.ResourceConfig{Name: "orders"}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	handler := NewSSEHandler(10)
+	handler.SetStatefulStore(store)
+
+	mockCfg := newStreamMockConfig(&mock.StatefulStreamConfig{
+		Table:  "orders",
+		Filter: `item.total > 100`,
+	}, mock.SSELifecycleConfig{MaxEvents: 2, ConnectionTimeout: 5})
+	ts := startTestServer(handler, mockCfg)
+	defer ts.Close()
+
+	go func() {
+		if !waitForSubscriber(store) {
+			return // the connection timeout ends the request
+		}
+		orders := store.Get("", "orders")
+		_, _ = orders.Create(map[string]interface{}{"id": "small", "total": 5}, nil)
+		_, _ = orders.Create(map[string]interface{}{"id": "big", "total": 500}, nil)
+		_, _ = orders.Patch("big", map[string]interface{}{"status": "paid"})
+	}()
+
+	resp, err := http.Get(ts.URL + "/v1/orders/stream")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading body: %v", err)
+	}
+
+	events := parseSSEEvents(string(body))
+	if len(events) != 3 { // retry directive + 2 changes
+		t.Fatalf("got %d events, want 3; body:\n%s", len(events), body)
+	}
+
+	wantTypes := []string{"order.created", "order.updated"}
+	for i, want := range wantTypes {
+		ev := events[i+1]
+		if ev.Type != want {
+			t.Errorf("event[%d].Type = %q, want %q", i, ev.Type, want)
+		}
+		if ev.ID == "" {
+			t.Errorf("event[%d] has no id", i)
+		}
+		var envelope map[string]interface{}
+		if err := json.Unmarshal([]byte(ev.Data), &envelope); err != nil {
+			t.Fatalf("event[%d] data is not JSON: %v", i, err)
+		}
+		if envelope["id"] != "big" {
+			t.Errorf("event[%d] id = %v, want big", i, envelope["id"])
+		}
+	}
+}
+
+func TestStatefulStream_LogsFilterErrors(t *testing.T) {
+	store := stateful.NewStateStore()
+	if err := store.Register("", &stateful.ResourceConfig{Name: "orders"}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	var logs bytes.Buffer
+	handler := NewSSEHandler(10)
+	handler.SetStatefulStore(store)
+	handler.SetOperationalLogger(slog.New(slog.NewTextHandler(&logs, nil)))
+
+	mockCfg := newStreamMockConfig(&mock.StatefulStreamConfig{
+		Table:  "orders",
+		Filter: `item.total > 100`,
+	}, mock.SSELifecycleConfig{MaxEvents: 1, ConnectionTimeout: 5})
+	ts := startTestServer(handler, mockCfg)
+	defer ts.Close()
+
+	go func() {
+		if !waitForSubscriber(store) {
+			return
+		}
+		orders := store.Get("", "orders")
+		_, _ = orders.Create(map[string]interface{}{"id": "untotalled"}, nil)
+		_, _ = orders.Create(map[string]interface{}{"id": "big", "total": 500}, nil)
+	}()
+
+	resp, err := http.Get(ts.URL + "/v1/orders/stream")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if events := parseSSEEvents(string(body)); len(events) != 2 {
+		t.Fatalf("got %d events, want 2; body:\n%s", len(events), body)
+	}
+	if !strings.Contains(logs.String(), "stateful stream filter failed") || !strings.Contains(logs.String(), "id=untotalled") {
+		t.Errorf("filter error not logged; logs:\n%s", logs.String())
+	}
+}
+
+func TestStatefulStream_UsesIsolationPartition(t *testing.T) {
+	store := stateful.NewStateStore()
+	if err := store.Register("", &stateful.ResourceConfig{Name: "orders"}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	handler := NewSSEHandler(10)
+	handler.SetStatefulStore(store)
+
+	mockCfg := newStreamMockConfig(&mock.StatefulStreamConfig{Table: "orders"},
+		mock.SSELifecycleConfig{MaxEvents: 1, ConnectionTimeout: 5})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(stateful.ContextWithPartition(r.Context(), "run-a"))
+		handler.ServeHTTP(w, r, mockCfg)
+	}))
+	defer ts.Close()
+
+	go func() {
+		if !waitForSubscriber(store) {
+			return
+		}
+		_, _ = store.Get("", "orders").Create(map[string]interface{}{"id": "shared"}, nil)
+		_, _ = store.Get(stateful.PartitionWorkspaceID("", "run-a"), "orders").Create(map[string]interface{}{"id": "isolated"}, nil)
+	}()
+
+	resp, err := http.Get(ts.URL + "/v1/orders/stream")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	events := parseSSEEvents(string(body))
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2; body:\n%s", len(events), body)
+	}
+	var envelope map[string]interface{}
+	if err := json.Unmarshal([]byte(events[1].Data), &envelope); err != nil {
+		t.Fatalf("event data is not JSON: %v", err)
+	}
+	if envelope["id"] != "isolated" {
+		t.Errorf("streamed id = %v, want isolated (shared table changes must not leak)", envelope["id"])
+	}
+}
+
+func TestStatefulStream_ErrorsBeforeStreaming(t *testing.T) {
+	stream := &mock.StatefulStreamConfig{Table: "orders"}
+
+	tests := []struct {
+		name   string
+		store  *stateful.StateStore
+		stream *mock.StatefulStreamConfig
+		want   int
+	}{
+		{"no store", nil, stream, http.StatusServiceUnavailable},
+		{"unknown table", stateful.NewStateStore(), stream, http.StatusNotFound},
+		{"bad filter", stateful.NewStateStore(), &mock.StatefulStreamConfig{Table: "orders", Filter: "item.total >"}, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewSSEHandler(10)
+			handler.SetStatefulStore(tt.store)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/v1/orders/stream", nil)
+			handler.ServeHTTP(w, r, newStreamMockConfig(tt.stream, mock.SSELifecycleConfig{}))
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+			if ct := w.Header().Get("Content-Type"); ct == ContentTypeEventStream {
+				t.Error("error response must not be an event stream")
+			}
+		})
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/getmockd/mockd/pkg/chaos"
 	"github.com/getmockd/mockd/pkg/mock"
+	"github.com/getmockd/mockd/pkg/stateful"
 )
 
 // Config type aliases - canonical definitions are in pkg/mock/types.go
@@ -93,13 +95,15 @@ type SSEStream struct {
 	Status StreamStatus `json:"status"`
 
 	// Internal fields (not serialized)
-	ctx      context.Context     `json:"-"`
-	cancel   context.CancelFunc  `json:"-"`
-	writer   http.ResponseWriter `json:"-"`
-	flusher  http.Flusher        `json:"-"`
-	config   *SSEConfig          `json:"-"`
-	mu       sync.Mutex          `json:"-"`
-	recorder *StreamRecorder     `json:"-"`
+	ctx      context.Context        `json:"-"`
+	cancel   context.CancelFunc     `json:"-"`
+	writer   http.ResponseWriter    `json:"-"`
+	flusher  http.Flusher           `json:"-"`
+	config   *SSEConfig             `json:"-"`
+	mu       sync.Mutex             `json:"-"`
+	recorder *StreamRecorder        `json:"-"`
+	changes  *stateful.ChangeStream `json:"-"` // set when streaming a stateful table
+	chaos    *chaos.MessageChaos    `json:"-"` // set when an sse chaos rule applies
 }
 
 // SSEConnectionManager tracks active SSE connections
@@ -134,6 +134,33 @@ func (b *Bridge) GetResponseConfig(workspaceID string, name string) *config.Resp
 	return r.ResponseConfig()
 }
 
+// Idempotency returns the cache of responses stored by idempotency key.
+// Protocol handlers record and replay responses through it; the Bridge itself
+// never consults it, so direct Execute calls are not deduplicated.
+// The cache belongs to the store so resets forget the affected keys.
+func (b *Bridge) Idempotency() *IdempotencyCache {
+	return b.store.Idempotency()
+}
+
+// GetIdempotencyPolicy returns the idempotency policy for a request bound to a
+// table and/or custom operation. An operation's own policy takes precedence
+// over the table's. Returns nil if neither enables idempotency.
+func (b *Bridge) GetIdempotencyPolicy(workspaceID, table, operation string) *IdempotencyPolicy {
+	if operation != "" {
+		if op := b.GetCustomOperation(workspaceID, operation); op != nil && op.Idempotency != nil {
+			// Validated when the operation was registered.
+			policy, _ := NewIdempotencyPolicy(op.Idempotency)
+			return policy
+		}
+	}
+	if table != "" {
+		if r := b.store.Get(workspaceID, table); r != nil {
+			return r.IdempotencyPolicy()
+		}
+	}
+	return nil
+}
+
 // SetTracer configures an optional tracer for custom operation spans.
 func (b *Bridge) SetTracer(t *tracing.Tracer) {
 	b.tracer = t
@@ -156,7 +183,9 @@ func (b *Bridge) RegisterCustomOperation(workspaceID string, name string, op *Cu
 }
 
 // GetCustomOperation returns a registered custom operation by workspace and name.
+// Isolation partitions share the operations of their base workspace.
 func (b *Bridge) GetCustomOperation(workspaceID string, name string) *CustomOperation {
+	workspaceID, _ = SplitPartitionWorkspaceID(workspaceID)
 	b.customMu.RLock()
 	defer b.customMu.RUnlock()
 	ws := b.customOps[workspaceID]
@@ -0,0 +1,273 @@
+package stateful
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/getmockd/mockd/pkg/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func importString(t *testing.T, r *StatefulResource, payload string, opts ImportOptions) *ImportResult {
+	t.Helper()
+	rows := NewRowReader(strings.NewReader(payload), opts.Format, r.FieldTypes())
+	result, err := r.Import(context.Background(), rows, opts)
+	require.NoError(t, err)
+	return result
+}
+
+func TestResolveBulkFormat(t *testing.T) {
+	tests := []struct {
+		name, format, contentType string
+		want                      BulkFormat
+		wantErr                   bool
+	}{
+		{name: "explicit wins", format: "csv", contentType: "application/json", want: BulkFormatCSV},
+		{name: "jsonl alias", format: "jsonl", want: BulkFormatNDJSON},
+		{name: "ndjson content type", contentType: "application/x-ndjson", want: BulkFormatNDJSON},
+		{name: "csv content type with charset", contentType: "text/csv; charset=utf-8", want: BulkFormatCSV},
+		{name: "default json", want: BulkFormatJSON},
+		{name: "unknown", format: "xml", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveBulkFormat(tt.format, tt.contentType)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCoerceCSVValue(t *testing.T) {
+	tests := []struct {
+		cell, fieldType string
+		want            interface{}
+		wantErr         bool
+	}{
+		{cell: "42", want: float64(42)},
+		{cell: "-3.5e2", want: float64(-350)},
+		{cell: "007", want: "007"},
+		{cell: "+1", want: "+1"},
+		{cell: "true", want: true},
+		{cell: "null", want: nil},
+		{cell: `{"a":1}`, want: map[string]interface{}{"a": float64(1)}},
+		{cell: "[1,2]", want: []interface{}{float64(1), float64(2)}},
+		{cell: "{not json", want: "{not json"},
+		{cell: "hello", want: "hello"},
+		{cell: "42", fieldType: "string", want: "42"},
+		{cell: "7", fieldType: "integer", want: float64(7)},
+		{cell: "yes", fieldType: "boolean", wantErr: true},
+		{cell: "abc", fieldType: "number", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cell+"/"+tt.fieldType, func(t *testing.T) {
+			got, err := CoerceCSVValue(tt.cell, tt.fieldType)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestImport_JSONArray(t *testing.T) {
+	r := NewStatefulResource(&ResourceConfig{Name: "users"})
+	result := importString(t, r, `[{"id":"u1","name":"Alice"},{"id":"u2","name":"Bob"}]`, ImportOptions{})
+
+	assert.Equal(t, 2, result.Total)
+	assert.Equal(t, 2, result.Created)
+	assert.Equal(t, 0, result.Failed)
+	assert.Equal(t, "Alice", r.Get("u1").Data["name"])
+}
+
+func TestImport_JSONArrayRejectsNonArray(t *testing.T) {
+	r := NewStatefulResource(&ResourceConfig{Name: "users"})
+	rows := NewRowReader(strings.NewReader(`{"id":"u1"}`), BulkFormatJSON, nil)
+	_, err := r.Import(context.Background(), rows, ImportOptions{})
+	assert.ErrorContains(t, err, "expected an array")
+}
+
+func TestImport_NDJSONSkipsBadLines(t *testing.T) {
+	r := NewStatefulResource(&ResourceConfig{Name: "users"})
+	payload := "{\"id\":\"u1\"}\n\nnot-json\n{\"id\":\"u2\"}"
+	result := importString(t, r, payload, ImportOptions{Format: BulkFormatNDJSON})
+
+	assert.Equal(t, 3, result.Total)
+	assert.Equal(t, 2, result.Created)
+	assert.Equal(t, 1, result.Failed)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, 2, result.Errors[0].Row)
+}
+
+func TestImport_CSVCoercesUsingValidationTypes(t *testing.T) {
+	r := NewStatefulResource(&ResourceConfig{
+		Name: "customers",
+		Validation: &validation.StatefulValidation{
+			Fields: map[string]*validation.FieldValidator{
+				"zip": {Type: "string"},
+			},
+		},
+	})
+	payload := "id,zip,age,active,tags\nc1,90210,31,true,\"[\"\"a\"\"]\"\nc2,01234,,false,\n"
+	result := importString(t, r, payload, ImportOptions{Format: BulkFormatCSV})
+
+	require.Equal(t, 2, result.Created, "errors: %v", result.Errors)
+	c1 := r.Get("c1").Data
+	assert.Equal(t, "90210", c1["zip"])
+	assert.Equal(t, float64(31), c1["age"])
+	assert.Equal(t, true, c1["active"])
+	assert.Equal(t, []interface{}{"a"}, c1["tags"])
+
+	c2 := r.Get("c2").Data
+	assert.Equal(t, "01234", c2["zip"])
+	_, hasAge := c2["age"]
+	assert.False(t, hasAge, "empty cells should be treated as absent")
+}
+
+func TestImport_CSVColumnCountMismatch(t *testing.T) {
+	r := NewStatefulResource(&ResourceConfig{Name: "users"})
+	result := importString(t, r, "id,name\nu1,Alice\nu2\n", ImportOptions{Format: BulkFormatCSV})
+
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Failed)
+}
+
+func TestImport_Modes(t *testing.T) {
+	newResource := func() *StatefulResource {
+		r := NewStatefulResource(&ResourceConfig{
+			Name:     "users",
+			SeedData: []map[string]interface{}{{"id": "u1", "name": "Seed"}, {"id": "u9", "name": "Other"}},
+		})
+		require.NoError(t, r.loadSeed())
+		return r
+	}
+	payload := `[{"id":"u1","name":"Alice"},{"id":"u2","name":"Bob"}]`
+
+	t.Run("append conflicts on existing IDs", func(t *testing.T) {
+		r := newResource()
+		result := importString(t, r, payload, ImportOptions{Mode: ImportModeAppend})
+		assert.Equal(t, 1, result.Created)
+		assert.Equal(t, 1, result.Failed)
+		assert.Equal(t, "u1", result.Errors[0].ID)
+		assert.Equal(t, "Seed", r.Get("u1").Data["name"])
+	})
+
+	t.Run("upsert replaces existing IDs", func(t *testing.T) {
+		r := newResource()
+		result := importString(t, r, payload, ImportOptions{Mode: ImportModeUpsert})
+		assert.Equal(t, 1, result.Created)
+		assert.Equal(t, 1, result.Updated)
+		assert.Equal(t, "Alice", r.Get("u1").Data["name"])
+		assert.Equal(t, 3, r.Count())
+	})
+
+	t.Run("replace clears first", func(t *testing.T) {
+		r := newResource()
+		result := importString(t, r, payload, ImportOptions{Mode: ImportModeReplace})
+		assert.Equal(t, 2, result.Created)
+		assert.Equal(t, 2, r.Count())
+		assert.Nil(t, r.Get("u9"))
+	})
+}
+
+func TestImport_StopOnError(t *testing.T) {
+	r := NewStatefulResource(&ResourceConfig{Name: "users", MaxItems: 1})
+	result := importString(t, r, `[{"id":"a"},{"id":"b"},{"id":"c"}]`, ImportOptions{StopOnError: true})
+
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, 1, r.Count())
+}
+
+func TestImport_Validate(t *testing.T) {
+	r := NewStatefulResource(&ResourceConfig{
+		Name: "users",
+		Validation: &validation.StatefulValidation{
+			Required: []string{"email"},
+		},
+	})
+	result := importString(t, r, `[{"id":"a","email":"a@example.com"},{"id":"b"}]`, ImportOptions{Validate: true})
+
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, "b", result.Errors[0].ID)
+}
+
+func TestImport_NumericIDsAndSequence(t *testing.T) {
+	r := NewStatefulResource(&ResourceConfig{Name: "orders", IDStrategy: IDStrategySequence})
+	result := importString(t, r, "id,total\n7,10\n3,20\n", ImportOptions{Format: BulkFormatCSV})
+	require.Equal(t, 2, result.Created)
+	require.NotNil(t, r.Get("7"))
+
+	item, err := r.Create(map[string]interface{}{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "8", item.ID, "sequence should continue after the highest imported ID")
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	for _, format := range []BulkFormat{BulkFormatJSON, BulkFormatNDJSON, BulkFormatCSV} {
+		t.Run(string(format), func(t *testing.T) {
+			src := NewStatefulResource(&ResourceConfig{
+				Name:    "orders",
+				IDField: "orderId",
+				SeedData: []map[string]interface{}{
+					{"orderId": "o1", "total": float64(12.5), "meta": map[string]interface{}{"vip": true}},
+					{"orderId": "o2", "status": "shipped"},
+				},
+			})
+			require.NoError(t, src.loadSeed())
+
+			var buf bytes.Buffer
+			n, err := src.Export(&buf, format)
+			require.NoError(t, err)
+			assert.Equal(t, 2, n)
+
+			dst := NewStatefulResource(&ResourceConfig{Name: "orders", IDField: "orderId"})
+			result := importString(t, dst, buf.String(), ImportOptions{Format: format})
+			require.Equal(t, 2, result.Created, "errors: %v", result.Errors)
+
+			o1 := dst.Get("o1")
+			require.NotNil(t, o1)
+			assert.Equal(t, float64(12.5), o1.Data["total"])
+			assert.Equal(t, map[string]interface{}{"vip": true}, o1.Data["meta"])
+			assert.True(t, o1.CreatedAt.Equal(src.Get("o1").CreatedAt), "createdAt should survive a round trip")
+			assert.Equal(t, "shipped", dst.Get("o2").Data["status"])
+		})
+	}
+}
+
+func TestExport_CSVHeaderOrder(t *testing.T) {
+	r := NewStatefulResource(&ResourceConfig{
+		Name:     "users",
+		SeedData: []map[string]interface{}{{"id": "u1", "zeta": "z", "alpha": "a"}},
+	})
+	require.NoError(t, r.loadSeed())
+
+	var buf bytes.Buffer
+	_, err := r.Export(&buf, BulkFormatCSV)
+	require.NoError(t, err)
+
+	header := strings.SplitN(buf.String(), "\n", 2)[0]
+	assert.Equal(t, "id,alpha,zeta,createdAt,updatedAt", header)
+}
+
+func TestExport_EmptyJSONIsArray(t *testing.T) {
+	r := NewStatefulResource(&ResourceConfig{Name: "users"})
+	var buf bytes.Buffer
+	_, err := r.Export(&buf, BulkFormatJSON)
+	require.NoError(t, err)
+
+	var rows []interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &rows))
+	assert.Empty(t, rows)
+}
@@ -0,0 +1,176 @@
+package stateful
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ChangeType identifies the kind of mutation carried by a ChangeEvent.
+type ChangeType string
+
+// Change types published on the ChangeFeed.
+const (
+	ChangeCreated ChangeType = "created"
+	ChangeUpdated ChangeType = "updated"
+	ChangeDeleted ChangeType = "deleted"
+	ChangeReset   ChangeType = "reset"
+)
+
+// DefaultChangeBuffer is the per-subscription queue size used when none is given.
+const DefaultChangeBuffer = 64
+
+// ChangeEvent describes a single mutation of a stateful resource.
+type ChangeEvent struct {
+	// Sequence increases monotonically across the whole feed.
+	Sequence    uint64
+	Type        ChangeType
+	WorkspaceID string
+	Resource    string
+	ItemID      string
+	// Item is the item after the change, or the removed item for deletes.
+	// Nil for resets.
+	Item map[string]interface{}
+	// Previous is the item before an update. Nil for other change types.
+	Previous  map[string]interface{}
+	Timestamp time.Time
+}
+
+// ChangeFeed fans out resource mutations to subscribers.
+//
+// Publishing never blocks: a subscriber whose queue is full misses the event
+// and its Dropped counter is incremented. This keeps slow stream clients from
+// stalling CRUD requests.
+type ChangeFeed struct {
+	mu       sync.RWMutex
+	subs     map[*Subscription]struct{}
+	sequence atomic.Uint64
+	active   atomic.Int32
+}
+
+// NewChangeFeed creates an empty ChangeFeed.
+func NewChangeFeed() *ChangeFeed {
+	return &ChangeFeed{subs: make(map[*Subscription]struct{})}
+}
+
+// Subscription receives ChangeEvents for one resource in one workspace.
+type Subscription struct {
+	feed        *ChangeFeed
+	workspaceID string
+	resource    string
+	ch          chan ChangeEvent
+	dropped     atomic.Int64
+	closeOnce   sync.Once
+}
+
+// Subscribe registers a subscription for changes to resource in workspaceID.
+// A non-positive buffer uses DefaultChangeBuffer. Callers must Close the
+// subscription when done.
+func (f *ChangeFeed) Subscribe(workspaceID, resource string, buffer int) *Subscription {
+	if buffer <= 0 {
+		buffer = DefaultChangeBuffer
+	}
+	sub := &Subscription{
+		feed:        f,
+		workspaceID: workspaceID,
+		resource:    resource,
+		ch:          make(chan ChangeEvent, buffer),
+	}
+
+	f.mu.Lock()
+	f.subs[sub] = struct{}{}
+	f.mu.Unlock()
+	f.active.Add(1)
+	return sub
+}
+
+// SubscriberCount returns the number of open subscriptions.
+func (f *ChangeFeed) SubscriberCount() int {
+	return int(f.active.Load())
+}
+
+// hasSubscribers is a cheap check used to skip building events nobody reads.
+func (f *ChangeFeed) hasSubscribers() bool {
+	return f != nil && f.active.Load() > 0
+}
+
+// Publish delivers an event to every matching subscription. Sequence and
+// Timestamp are assigned if unset.
+func (f *ChangeFeed) Publish(ev ChangeEvent) {
+	if !f.hasSubscribers() {
+		return
+	}
+	ev.Sequence = f.sequence.Add(1)
+	if ev.Timestamp.IsZero() {
+		ev.Timestamp = time.Now()
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for sub := range f.subs {
+		if sub.resource != ev.Resource || sub.workspaceID != ev.WorkspaceID {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
+
+// Events returns the channel on which changes are delivered. It is closed by Close.
+func (s *Subscription) Events() <-chan ChangeEvent {
+	return s.ch
+}
+
+// Dropped returns how many events were discarded because the queue was full.
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// Close unregisters the subscription and closes its channel. Safe to call more than once.
+func (s *Subscription) Close() {
+	s.closeOnce.Do(func() {
+		s.feed.mu.Lock()
+		delete(s.feed.subs, s)
+		close(s.ch)
+		s.feed.mu.Unlock()
+		s.feed.active.Add(-1)
+	})
+}
+
+// publishLocked emits a change for this resource on its feed.
+// Must be called with r.mu held so events are ordered like the writes.
+func (r *StatefulResource) publishLocked(typ ChangeType, itemID string, item, previous *ResourceItem) {
+	if !r.feed.hasSubscribers() {
+		return
+	}
+	ev := ChangeEvent{
+		Type:        typ,
+		WorkspaceID: r.workspaceID,
+		Resource:    r.name,
+		ItemID:      itemID,
+	}
+	if item != nil {
+		ev.Item = r.itemJSON(item)
+		ev.Timestamp = item.UpdatedAt
+	}
+	if previous != nil {
+		ev.Previous = r.itemJSON(previous)
+	}
+	if typ == ChangeDeleted || typ == ChangeReset {
+		// The stored timestamp belongs to the last write, not the removal.
+		ev.Timestamp = time.Time{}
+	}
+	r.feed.Publish(ev)
+}
+
+// itemJSON renders an item as a flat map including the configured ID field.
+func (r *StatefulResource) itemJSON(item *ResourceItem) map[string]interface{} {
+	data := item.ToJSON()
+	if r.idField != DefaultIDField {
+		data[r.idField] = item.ID
+	}
+	return data
+}
@@ -0,0 +1,233 @@
+package stateful
+
+import (
+	"testing"
+	"time"
+
+	"github.com/getmockd/mockd/pkg/config"
+	"github.com/getmockd/mockd/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFeedStore(t *testing.T, seed []map[string]interface{}) *StateStore {
+	t.Helper()
+	store := NewStateStore()
+	require.NoError(t, store.Register("", &ResourceConfig{Name: "orders", SeedData: seed}))
+	return store
+}
+
+func nextChange(t *testing.T, sub *Subscription) ChangeEvent {
+	t.Helper()
+	select {
+	case ev := <-sub.Events():
+		return ev
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for change event")
+		return ChangeEvent{}
+	}
+}
+
+func TestChangeFeed_PublishesCRUD(t *testing.T) {
+	store := newFeedStore(t, nil)
+	sub := store.Changes().Subscribe("", "orders", 0)
+	defer sub.Close()
+	orders := store.Get("", "orders")
+
+	_, err := orders.Create(map[string]interface{}{"id": "o1", "status": "pending"}, nil)
+	require.NoError(t, err)
+	created := nextChange(t, sub)
+	assert.Equal(t, ChangeCreated, created.Type)
+	assert.Equal(t, "o1", created.ItemID)
+	assert.Equal(t, "pending", created.Item["status"])
+	assert.Nil(t, created.Previous)
+
+	_, err = orders.Patch("o1", map[string]interface{}{"status": "paid"})
+	require.NoError(t, err)
+	updated := nextChange(t, sub)
+	assert.Equal(t, ChangeUpdated, updated.Type)
+	assert.Equal(t, "paid", updated.Item["status"])
+	assert.Equal(t, "pending", updated.Previous["status"])
+	assert.Greater(t, updated.Sequence, created.Sequence)
+
+	_, err = orders.Delete("o1")
+	require.NoError(t, err)
+	deleted := nextChange(t, sub)
+	assert.Equal(t, ChangeDeleted, deleted.Type)
+	assert.Equal(t, "paid", deleted.Item["status"])
+
+	_, err = store.Reset("", "orders")
+	require.NoError(t, err)
+	assert.Equal(t, ChangeReset, nextChange(t, sub).Type)
+}
+
+func TestChangeFeed_ScopedToWorkspaceAndResource(t *testing.T) {
+	store := newFeedStore(t, nil)
+	require.NoError(t, store.Register("", &ResourceConfig{Name: "users"}))
+	require.NoError(t, store.Register("ws2", &ResourceConfig{Name: "orders"}))
+	sub := store.Changes().Subscribe("", "orders", 0)
+	defer sub.Close()
+
+	_, err := store.Get("", "users").Create(map[string]interface{}{}, nil)
+	require.NoError(t, err)
+	_, err = store.Get("ws2", "orders").Create(map[string]interface{}{}, nil)
+	require.NoError(t, err)
+	_, err = store.Get("", "orders").Create(map[string]interface{}{"id": "mine"}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "mine", nextChange(t, sub).ItemID)
+	assert.Empty(t, sub.Events())
+}
+
+func TestChangeFeed_DropsWhenFull(t *testing.T) {
+	store := newFeedStore(t, nil)
+	sub := store.Changes().Subscribe("", "orders", 1)
+	defer sub.Close()
+	orders := store.Get("", "orders")
+
+	for i := 0; i < 3; i++ {
+		_, err := orders.Create(map[string]interface{}{}, nil)
+		require.NoError(t, err)
+	}
+	assert.Len(t, sub.Events(), 1)
+	assert.Equal(t, int64(2), sub.Dropped())
+}
+
+func TestChangeFeed_CloseUnsubscribes(t *testing.T) {
+	store := newFeedStore(t, nil)
+	sub := store.Changes().Subscribe("", "orders", 0)
+	assert.Equal(t, 1, store.Changes().SubscriberCount())
+
+	sub.Close()
+	sub.Close()
+	assert.Equal(t, 0, store.Changes().SubscriberCount())
+	_, open := <-sub.Events()
+	assert.False(t, open)
+
+	_, err := store.Get("", "orders").Create(map[string]interface{}{}, nil)
+	require.NoError(t, err)
+}
+
+func TestChangeFeed_LifecycleAndRollback(t *testing.T) {
+	store := NewStateStore()
+	require.NoError(t, store.Register("", &ResourceConfig{
+		Name:      "orders",
+		SeedData:  []map[string]interface{}{{"id": "o1", "status": "pending"}},
+		Lifecycle: []*config.LifecycleTransition{{From: "pending", To: "shipped", Delay: "0s"}},
+	}))
+	sub := store.Changes().Subscribe("", "orders", 0)
+	defer sub.Close()
+	orders := store.Get("", "orders")
+
+	NewLifecycleScheduler(store, 0).Tick(time.Now())
+	moved := nextChange(t, sub)
+	assert.Equal(t, ChangeUpdated, moved.Type)
+	assert.Equal(t, "shipped", moved.Item["status"])
+	assert.Equal(t, "pending", moved.Previous["status"])
+
+	// A rolled-back create is announced as a delete.
+	_, err := orders.Create(map[string]interface{}{"id": "tmp"}, nil)
+	require.NoError(t, err)
+	nextChange(t, sub)
+	require.NoError(t, restoreResourceItem(orders, "tmp", nil))
+	undo := nextChange(t, sub)
+	assert.Equal(t, ChangeDeleted, undo.Type)
+	assert.Equal(t, "tmp", undo.ItemID)
+}
+
+func TestChangeStream_MatchAndRender(t *testing.T) {
+	cs, err := CompileChangeStream(&mock.StatefulStreamConfig{
+		Table:  "orders",
+		Events: []string{"updated"},
+		Filter: `item.status == "shipped" && previous.status != "shipped"`,
+	})
+	require.NoError(t, err)
+
+	ev := ChangeEvent{
+		Sequence:  7,
+		Type:      ChangeUpdated,
+		Resource:  "orders",
+		ItemID:    "o1",
+		Item:      map[string]interface{}{"id": "o1", "status": "shipped"},
+		Previous:  map[string]interface{}{"id": "o1", "status": "pending"},
+		Timestamp: time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC),
+	}
+	ok, err := cs.Match(ev)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	created := ev
+	created.Type = ChangeCreated
+	ok, _ = cs.Match(created)
+	assert.False(t, ok, "event type not selected")
+
+	msg := cs.Render(ev)
+	assert.Equal(t, "order.updated", msg.Event)
+	assert.Equal(t, "7", msg.ID)
+	assert.Equal(t, map[string]interface{}{
+		"type":      "order.updated",
+		"table":     "orders",
+		"id":        "o1",
+		"data":      ev.Item,
+		"previous":  ev.Previous,
+		"timestamp": "2026-03-10T10:00:00Z",
+		"sequence":  uint64(7),
+	}, msg.Data)
+}
+
+func TestChangeStream_Envelope(t *testing.T) {
+	ev := ChangeEvent{Sequence: 1, Type: ChangeCreated, Resource: "categories", ItemID: "c1", Item: map[string]interface{}{"id": "c1"}}
+
+	cs, err := CompileChangeStream(&mock.StatefulStreamConfig{
+		Table: "categories",
+		Envelope: &mock.StatefulStreamEnvelope{
+			EventType: "{table}:{action}",
+			Fields:    map[string]string{"type": "event", "data": "payload", "timestamp": "", "sequence": ""},
+		},
+	})
+	require.NoError(t, err)
+	msg := cs.Render(ev)
+	assert.Equal(t, "categories:created", msg.Event)
+	assert.Equal(t, map[string]interface{}{
+		"event":   "categories:created",
+		"table":   "categories",
+		"id":      "c1",
+		"payload": ev.Item,
+	}, msg.Data)
+
+	raw, err := CompileChangeStream(&mock.StatefulStreamConfig{
+		Table:    "categories",
+		Envelope: &mock.StatefulStreamEnvelope{Raw: true},
+	})
+	require.NoError(t, err)
+	msg = raw.Render(ev)
+	assert.Equal(t, "category.created", msg.Event)
+	assert.Equal(t, ev.Item, msg.Data)
+}
+
+func TestCompileChangeStream_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *mock.StatefulStreamConfig
+	}{
+		{"nil", nil},
+		{"missing table", &mock.StatefulStreamConfig{}},
+		{"unknown event", &mock.StatefulStreamConfig{Table: "orders", Events: []string{"touched"}}},
+		{"bad filter", &mock.StatefulStreamConfig{Table: "orders", Filter: "item.status =="}},
+		{"non-bool filter", &mock.StatefulStreamConfig{Table: "orders", Filter: "1 + 1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CompileChangeStream(tt.cfg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestChangeStream_SubscribeUnknownTable(t *testing.T) {
+	cs, err := CompileChangeStream(&mock.StatefulStreamConfig{Table: "missing"})
+	require.NoError(t, err)
+	_, err = cs.Subscribe(NewStateStore(), "")
+	var notFound *NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+}
@@ -0,0 +1,204 @@
+package stateful
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+	"github.com/getmockd/mockd/pkg/mock"
+	"github.com/getmockd/mockd/pkg/util"
+)
+
+// DefaultStreamEventType is the event name pattern used when a stream
+// envelope does not configure one.
+const DefaultStreamEventType = "{singular}.{action}"
+
+// Envelope field names, as used in StatefulStreamEnvelope.Fields.
+const (
+	envelopeType      = "type"
+	envelopeTable     = "table"
+	envelopeID        = "id"
+	envelopeData      = "data"
+	envelopePrevious  = "previous"
+	envelopeTimestamp = "timestamp"
+	envelopeSequence  = "sequence"
+)
+
+// StreamMessage is a change rendered for delivery over SSE or WebSocket.
+type StreamMessage struct {
+	// Event is the event name, e.g. "order.updated".
+	Event string
+	// ID is the feed sequence number, usable as an SSE event ID.
+	ID string
+	// Data is the payload: either the envelope or, in raw mode, the item.
+	Data interface{}
+}
+
+// ChangeStream is a compiled mock.StatefulStreamConfig. It decides which
+// changes a stream client receives and how they are framed.
+type ChangeStream struct {
+	table     string
+	singular  string
+	buffer    int
+	events    map[ChangeType]bool
+	filter    *vm.Program
+	eventType string
+	raw       bool
+	fields    map[string]string
+}
+
+// CompileChangeStream validates a stream config and compiles its filter.
+func CompileChangeStream(cfg *mock.StatefulStreamConfig) (*ChangeStream, error) {
+	if cfg == nil {
+		return nil, errors.New("stateful stream config is nil")
+	}
+	if cfg.Table == "" {
+		return nil, errors.New("stateful stream: table is required")
+	}
+
+	s := &ChangeStream{
+		table:     cfg.Table,
+		singular:  util.Singularize(cfg.Table),
+		buffer:    cfg.Buffer,
+		eventType: DefaultStreamEventType,
+	}
+
+	if len(cfg.Events) > 0 {
+		s.events = make(map[ChangeType]bool, len(cfg.Events))
+		for _, ev := range cfg.Events {
+			switch t := ChangeType(ev); t {
+			case ChangeCreated, ChangeUpdated, ChangeDeleted, ChangeReset:
+				s.events[t] = true
+			default:
+				return nil, fmt.Errorf("stateful stream: unknown event %q", ev)
+			}
+		}
+	}
+
+	if cfg.Filter != "" {
+		env := map[string]interface{}{
+			"event":    "",
+			"item":     map[string]interface{}{},
+			"previous": map[string]interface{}{},
+		}
+		program, err := expr.Compile(cfg.Filter, expr.Env(env), expr.AsBool())
+		if err != nil {
+			return nil, fmt.Errorf("stateful stream: invalid filter %q: %w", cfg.Filter, err)
+		}
+		s.filter = program
+	}
+
+	if env := cfg.Envelope; env != nil {
+		if env.EventType != "" {
+			s.eventType = env.EventType
+		}
+		s.raw = env.Raw
+		s.fields = env.Fields
+	}
+
+	return s, nil
+}
+
+// Table returns the name of the watched table.
+func (s *ChangeStream) Table() string {
+	return s.table
+}
+
+// Subscribe opens a subscription on the store's change feed for the stream's
+// table. It fails if the table is not registered in the workspace.
+func (s *ChangeStream) Subscribe(store *StateStore, workspaceID string) (*Subscription, error) {
+	if store.Get(workspaceID, s.table) == nil {
+		return nil, &NotFoundError{Resource: s.table}
+	}
+	return store.Changes().Subscribe(workspaceID, s.table, s.buffer), nil
+}
+
+// Match reports whether a change should be sent to the client. Filter
+// evaluation errors are returned so callers can log them; the change is not
+// sent in that case.
+func (s *ChangeStream) Match(ev ChangeEvent) (bool, error) {
+	if s.events != nil && !s.events[ev.Type] {
+		return false, nil
+	}
+	if s.filter == nil {
+		return true, nil
+	}
+
+	item := ev.Item
+	if item == nil {
+		item = map[string]interface{}{}
+	}
+	previous := ev.Previous
+	if previous == nil {
+		previous = map[string]interface{}{}
+	}
+	out, err := expr.Run(s.filter, map[string]interface{}{
+		"event":    string(ev.Type),
+		"item":     item,
+		"previous": previous,
+	})
+	if err != nil {
+		return false, fmt.Errorf("stateful stream filter: %w", err)
+	}
+	ok, _ := out.(bool)
+	return ok, nil
+}
+
+// Render frames a change according to the stream's envelope settings.
+func (s *ChangeStream) Render(ev ChangeEvent) StreamMessage {
+	msg := StreamMessage{
+		Event: s.eventName(ev.Type),
+		ID:    strconv.FormatUint(ev.Sequence, 10),
+	}
+	if s.raw {
+		if ev.Item != nil {
+			msg.Data = ev.Item
+		} else {
+			msg.Data = map[string]interface{}{}
+		}
+		return msg
+	}
+
+	envelope := make(map[string]interface{}, 7)
+	s.setField(envelope, envelopeType, msg.Event)
+	s.setField(envelope, envelopeTable, ev.Resource)
+	if ev.ItemID != "" {
+		s.setField(envelope, envelopeID, ev.ItemID)
+	}
+	if ev.Item != nil {
+		s.setField(envelope, envelopeData, ev.Item)
+	}
+	if ev.Previous != nil {
+		s.setField(envelope, envelopePrevious, ev.Previous)
+	}
+	s.setField(envelope, envelopeTimestamp, ev.Timestamp.UTC().Format(time.RFC3339Nano))
+	s.setField(envelope, envelopeSequence, ev.Sequence)
+	msg.Data = envelope
+	return msg
+}
+
+// eventName expands the event type pattern for a change type.
+func (s *ChangeStream) eventName(t ChangeType) string {
+	return strings.NewReplacer(
+		"{table}", s.table,
+		"{singular}", s.singular,
+		"{action}", string(t),
+	).Replace(s.eventType)
+}
+
+// setField stores value under the configured name for an envelope field.
+// Fields renamed to "" are omitted.
+func (s *ChangeStream) setField(envelope map[string]interface{}, field string, value interface{}) {
+	name := field
+	if renamed, ok := s.fields[field]; ok {
+		name = renamed
+	}
+	if name == "" {
+		return
+	}
+	envelope[name] = value
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"sort"
 	"strconv"
 	"strings"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/expr-lang/expr"
 	"github.com/expr-lang/expr/vm"
+	"github.com/getmockd/mockd/pkg/config"
 	"github.com/getmockd/mockd/pkg/tracing"
 )
 
@@ -37,6 +39,10 @@ type CustomOperation struct {
 	// evaluated against the accumulated context (input + step variables).
 	// Example: {"newBalance": "source.balance - input.amount"}
 	Response map[string]string `json:"response,omitempty" yaml:"response,omitempty"`
+
+	// Idempotency enables Idempotency-Key handling when the operation is
+	// invoked over HTTP. Nil disables it.
+	Idempotency *config.IdempotencyConfig `json:"idempotency,omitempty" yaml:"idempotency,omitempty"`
 }
 
 // StepType identifies what kind of step to execute.
@@ -68,6 +74,13 @@ const (
 	StepList StepType = "list"
 	// StepValidate evaluates a boolean condition and halts the operation with an error if false.
 	StepValidate StepType = "validate"
+	// StepIf evaluates a condition and runs Steps when it is truthy, Else otherwise.
+	StepIf StepType = "if"
+	// StepForEach evaluates an array expression and runs Steps once per element,
+	// binding the element to the As variable.
+	StepForEach StepType = "foreach"
+	// StepHTTP performs an outbound HTTP request and stores the response in the As variable.
+	StepHTTP StepType = "http"
 )
 
 // Step is a single step in a custom operation pipeline.
@@ -102,16 +115,51 @@ type Step struct {
 	// Example: {"accountId": "params.id", "status": "'pending'"}
 	Filter map[string]string `json:"filter,omitempty" yaml:"filter,omitempty"`
 
-	// Condition is a boolean expr expression for validate steps.
-	// If it evaluates to false, the operation halts with ErrorMessage.
+	// Condition is a boolean expr expression for validate and if steps.
+	// If it evaluates to false, a validate step halts the operation with ErrorMessage
+	// and an if step runs its Else branch.
 	// Example: "source.balance >= input.amount"
 	Condition string `json:"condition,omitempty" yaml:"condition,omitempty"`
 
-	// ErrorMessage is the error message returned when a validate step's Condition is false.
+	// ErrorMessage is the error message returned when a validate step's Condition is false
+	// or an http step fails.
 	ErrorMessage string `json:"errorMessage,omitempty" yaml:"errorMessage,omitempty"`
 
-	// ErrorStatus is the HTTP status code returned when a validate step fails (default: 400).
+	// ErrorStatus is the HTTP status code returned when a validate step fails (default: 400)
+	// or an http step fails (default: 502).
 	ErrorStatus int `json:"errorStatus,omitempty" yaml:"errorStatus,omitempty"`
+
+	// Steps are the nested steps run by if (when Condition is truthy) and foreach
+	// (once per element) steps. Nested steps share the operation's context and
+	// rollback journal.
+	Steps []Step `json:"steps,omitempty" yaml:"steps,omitempty"`
+
+	// Else are the nested steps run by an if step when Condition is falsy.
+	Else []Step `json:"else,omitempty" yaml:"else,omitempty"`
+
+	// Items is an expr expression resolving to the array a foreach step iterates.
+	// Example: "filter(charges, .status == 'captured')"
+	Items string `json:"items,omitempty" yaml:"items,omitempty"`
+
+	// Method is the HTTP method for http steps (default: GET, or POST when Body is set).
+	Method string `json:"method,omitempty" yaml:"method,omitempty"`
+
+	// URL is the request URL for http steps. {{ expr }} placeholders are
+	// evaluated against the context.
+	// Example: "https://payments.local/charges/{{ charge.id }}/refund"
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+
+	// Headers are request headers for http steps. Values support {{ expr }} placeholders.
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	// Body is the request body template for http steps. {{ expr }} placeholders
+	// are replaced with the expression result: strings are inserted as-is and
+	// other values as JSON.
+	// Example: `{"chargeId": "{{ charge.id }}", "amount": {{ charge.amount }}}`
+	Body string `json:"body,omitempty" yaml:"body,omitempty"`
+
+	// Timeout bounds an http step's request as a Go duration string (default: 10s).
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
 }
 
 // OperationExecutor executes custom multi-step operations against stateful resources.
@@ -120,6 +168,7 @@ type Step struct {
 type OperationExecutor struct {
 	store        *StateStore
 	tracer       *tracing.Tracer
+	httpClient   *http.Client
 	programMu    sync.RWMutex
 	programCache map[string]*vm.Program
 }
@@ -128,6 +177,7 @@ type OperationExecutor struct {
 func NewOperationExecutor(store *StateStore) *OperationExecutor {
 	return &OperationExecutor{
 		store:        store,
+		httpClient:   &http.Client{},
 		programCache: make(map[string]*vm.Program),
 	}
 }
@@ -294,6 +344,12 @@ func (e *OperationExecutor) executeStep(ctx context.Context, stepIndex int, step
 		err = e.stepList(step, exprCtx, workspaceID)
 	case StepValidate:
 		err = e.stepValidate(step, exprCtx)
+	case StepIf:
+		err = e.stepIf(ctx, step, exprCtx, tx, workspaceID)
+	case StepForEach:
+		err = e.stepForEach(ctx, step, exprCtx, tx, workspaceID)
+	case StepHTTP:
+		err = e.stepHTTP(ctx, step, exprCtx)
 	default:
 		err = fmt.Errorf("unknown step type: %s", step.Type)
 	}
@@ -533,26 +589,7 @@ func (e *OperationExecutor) stepValidate(step Step, exprCtx map[string]interface
 		return fmt.Errorf("condition expression failed: %w", err)
 	}
 
-	// Check if the result is truthy
-	passed := false
-	switch v := val.(type) {
-	case bool:
-		passed = v
-	case int:
-		passed = v != 0
-	case int64:
-		passed = v != 0
-	case float64:
-		passed = v != 0
-	case string:
-		passed = v != ""
-	case nil:
-		passed = false
-	default:
-		passed = true // non-nil = truthy
-	}
-
-	if !passed {
+	if !isTruthy(val) {
 		msg := step.ErrorMessage
 		if msg == "" {
 			msg = "validation failed: " + step.Condition
@@ -567,6 +604,27 @@ func (e *OperationExecutor) stepValidate(step Step, exprCtx map[string]interface
 	return nil
 }
 
+// isTruthy reports whether an expression result counts as true for
+// validate and if conditions.
+func isTruthy(val interface{}) bool {
+	switch v := val.(type) {
+	case bool:
+		return v
+	case int:
+		return v != 0
+	case int64:
+		return v != 0
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case nil:
+		return false
+	default:
+		return true // non-nil = truthy
+	}
+}
+
 // evalExpr evaluates an expr-lang expression against the given context, using a compile cache.
 func (e *OperationExecutor) evalExpr(expression string, env map[string]interface{}) (interface{}, error) {
 	program, err := e.compileExpr(expression, env)
@@ -649,6 +707,9 @@ func NormalizeCustomOperation(op *CustomOperation) (ConsistencyMode, error) {
 	if err != nil {
 		return "", err
 	}
+	if _, err := NewIdempotencyPolicy(op.Idempotency); err != nil {
+		return "", fmt.Errorf("invalid idempotency: %w", err)
+	}
 	op.Consistency = mode
 	return mode, nil
 }
@@ -734,11 +795,23 @@ func restoreResourceItem(resource *StatefulResource, id string, before *Resource
 	resource.mu.Lock()
 	defer resource.mu.Unlock()
 
+	// Rolled-back writes were already published, so publish the compensating
+	// change to keep change feed subscribers in sync with the store.
+	current := resource.items[id]
 	if before == nil {
 		delete(resource.items, id)
+		if current != nil {
+			resource.publishLocked(ChangeDeleted, id, current, nil)
+		}
 		return nil
 	}
-	resource.items[id] = cloneResourceItem(before)
+	restored := cloneResourceItem(before)
+	resource.items[id] = restored
+	if current != nil {
+		resource.publishLocked(ChangeUpdated, id, restored, current)
+	} else {
+		resource.publishLocked(ChangeCreated, id, restored, nil)
+	}
 	return nil
 }
 
@@ -0,0 +1,256 @@
+package stateful
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultHTTPStepTimeout bounds http steps that do not set Timeout.
+	defaultHTTPStepTimeout = 10 * time.Second
+	// maxHTTPStepResponseBytes caps how much of an http step response is read.
+	maxHTTPStepResponseBytes = 10 << 20
+)
+
+// stepTemplatePattern matches {{ expr }} placeholders in http step templates.
+var stepTemplatePattern = regexp.MustCompile(`\{\{\s*(.+?)\s*\}\}`)
+
+// runNestedSteps executes a nested step list with the operation's context and
+// rollback journal, so atomic operations undo nested mutations too.
+func (e *OperationExecutor) runNestedSteps(ctx context.Context, label string, steps []Step, exprCtx map[string]interface{}, tx *rollbackJournal, workspaceID string) error {
+	for i, step := range steps {
+		if err := e.executeStep(ctx, i, step, exprCtx, tx, workspaceID); err != nil {
+			return fmt.Errorf("%s step %d (%s) failed: %w", label, i, step.Type, err)
+		}
+	}
+	return nil
+}
+
+// stepIf runs Steps when Condition is truthy and Else otherwise.
+func (e *OperationExecutor) stepIf(ctx context.Context, step Step, exprCtx map[string]interface{}, tx *rollbackJournal, workspaceID string) error {
+	if step.Condition == "" {
+		return errors.New("if step requires condition expression")
+	}
+	if len(step.Steps) == 0 && len(step.Else) == 0 {
+		return errors.New("if step requires steps or else")
+	}
+
+	val, err := e.evalExpr(step.Condition, exprCtx)
+	if err != nil {
+		return fmt.Errorf("condition expression failed: %w", err)
+	}
+
+	if isTruthy(val) {
+		return e.runNestedSteps(ctx, "then", step.Steps, exprCtx, tx, workspaceID)
+	}
+	return e.runNestedSteps(ctx, "else", step.Else, exprCtx, tx, workspaceID)
+}
+
+// stepForEach evaluates Items and runs Steps once per element, binding the
+// element to the As variable. The variable's previous value is restored
+// afterwards; variables set by nested steps remain visible.
+func (e *OperationExecutor) stepForEach(ctx context.Context, step Step, exprCtx map[string]interface{}, tx *rollbackJournal, workspaceID string) error {
+	if step.Items == "" {
+		return errors.New("foreach step requires items expression")
+	}
+	if step.As == "" {
+		return errors.New("foreach step requires 'as' variable name")
+	}
+	if len(step.Steps) == 0 {
+		return errors.New("foreach step requires steps")
+	}
+
+	val, err := e.evalExpr(step.Items, exprCtx)
+	if err != nil {
+		return fmt.Errorf("items expression failed: %w", err)
+	}
+	items, err := toSlice(val)
+	if err != nil {
+		return fmt.Errorf("items expression: %w", err)
+	}
+
+	prev, hadPrev := exprCtx[step.As]
+	defer func() {
+		if hadPrev {
+			exprCtx[step.As] = prev
+		} else {
+			delete(exprCtx, step.As)
+		}
+	}()
+
+	for i, item := range items {
+		exprCtx[step.As] = item
+		if err := e.runNestedSteps(ctx, fmt.Sprintf("item %d:", i), step.Steps, exprCtx, tx, workspaceID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toSlice converts an expression result to a slice of elements.
+// A nil result is treated as an empty list.
+func toSlice(val interface{}) ([]interface{}, error) {
+	switch v := val.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		return v, nil
+	case []map[string]interface{}:
+		out := make([]interface{}, len(v))
+		for i := range v {
+			out[i] = v[i]
+		}
+		return out, nil
+	}
+	rv := reflect.ValueOf(val)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("expected an array, got %T", val)
+	}
+	out := make([]interface{}, rv.Len())
+	for i := range out {
+		out[i] = rv.Index(i).Interface()
+	}
+	return out, nil
+}
+
+// stepHTTP performs an outbound request and stores {status, headers, body}
+// in the As variable. JSON response bodies are decoded; others are kept as
+// strings. Transport failures and non-2xx responses fail the step, which
+// rolls back prior mutations in atomic mode. The request itself cannot be
+// rolled back.
+func (e *OperationExecutor) stepHTTP(ctx context.Context, step Step, exprCtx map[string]interface{}) error {
+	if step.URL == "" {
+		return errors.New("http step requires url")
+	}
+
+	timeout := defaultHTTPStepTimeout
+	if step.Timeout != "" {
+		d, err := time.ParseDuration(step.Timeout)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid http step timeout %q", step.Timeout)
+		}
+		timeout = d
+	}
+
+	url, err := e.renderTemplate(step.URL, exprCtx)
+	if err != nil {
+		return fmt.Errorf("url template failed: %w", err)
+	}
+	var body io.Reader
+	if step.Body != "" {
+		rendered, err := e.renderTemplate(step.Body, exprCtx)
+		if err != nil {
+			return fmt.Errorf("body template failed: %w", err)
+		}
+		body = strings.NewReader(rendered)
+	}
+
+	method := strings.ToUpper(step.Method)
+	if method == "" {
+		method = http.MethodGet
+		if body != nil {
+			method = http.MethodPost
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return fmt.Errorf("building http request: %w", err)
+	}
+	for name, tmpl := range step.Headers {
+		val, err := e.renderTemplate(tmpl, exprCtx)
+		if err != nil {
+			return fmt.Errorf("header %q template failed: %w", name, err)
+		}
+		req.Header.Set(name, val)
+	}
+	if body != nil && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return httpStepError(step, fmt.Sprintf("%s %s failed: %v", method, url, err))
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxHTTPStepResponseBytes))
+	if err != nil {
+		return httpStepError(step, fmt.Sprintf("%s %s: reading response: %v", method, url, err))
+	}
+
+	if step.As != "" {
+		headers := make(map[string]interface{}, len(resp.Header))
+		for name := range resp.Header {
+			headers[name] = resp.Header.Get(name)
+		}
+		var decoded interface{}
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			decoded = string(raw)
+		}
+		exprCtx[step.As] = map[string]interface{}{
+			"status":  resp.StatusCode,
+			"headers": headers,
+			"body":    decoded,
+		}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return httpStepError(step, fmt.Sprintf("%s %s returned %d", method, url, resp.StatusCode))
+	}
+	return nil
+}
+
+// httpStepError builds the error for a failed http step, honoring the step's
+// ErrorMessage and ErrorStatus overrides.
+func httpStepError(step Step, detail string) error {
+	msg := step.ErrorMessage
+	if msg == "" {
+		msg = "http step: " + detail
+	}
+	status := step.ErrorStatus
+	if status == 0 {
+		status = http.StatusBadGateway
+	}
+	return &ValidationError{Message: msg, Status: status}
+}
+
+// renderTemplate replaces {{ expr }} placeholders with their evaluated values.
+// Strings are inserted as-is; other values are JSON-encoded.
+func (e *OperationExecutor) renderTemplate(tmpl string, exprCtx map[string]interface{}) (string, error) {
+	var firstErr error
+	out := stepTemplatePattern.ReplaceAllStringFunc(tmpl, func(match string) string {
+		if firstErr != nil {
+			return ""
+		}
+		expression := stepTemplatePattern.FindStringSubmatch(match)[1]
+		val, err := e.evalExpr(expression, exprCtx)
+		if err != nil {
+			firstErr = err
+			return ""
+		}
+		if s, ok := val.(string); ok {
+			return s
+		}
+		b, err := json.Marshal(val)
+		if err != nil {
+			firstErr = fmt.Errorf("encode %q: %w", expression, err)
+			return ""
+		}
+		return string(b)
+	})
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return out, nil
+}
@@ -2,8 +2,13 @@ package stateful
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/getmockd/mockd/pkg/tracing"
@@ -1170,3 +1175,273 @@ func TestCustomOperationConsistency_DoesNotMutate(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, ConsistencyMode(""), op.Consistency, "should not mutate op.Consistency")
 }
+
+// --- Control flow and http step tests ---
+
+// setupRefundTest creates an order with three charges, two of them captured.
+func setupRefundTest(t *testing.T) (*StateStore, *OperationExecutor) {
+	t.Helper()
+	store := NewStateStore()
+	require.NoError(t, store.Register("", &ResourceConfig{
+		Name:     "orders",
+		SeedData: []map[string]interface{}{{"id": "ord-1", "status": "paid"}},
+	}))
+	require.NoError(t, store.Register("", &ResourceConfig{
+		Name: "charges",
+		SeedData: []map[string]interface{}{
+			{"id": "ch-1", "orderId": "ord-1", "amount": float64(30), "status": "captured"},
+			{"id": "ch-2", "orderId": "ord-1", "amount": float64(20), "status": "pending"},
+			{"id": "ch-3", "orderId": "ord-1", "amount": float64(50), "status": "captured"},
+		},
+	}))
+	return store, NewOperationExecutor(store)
+}
+
+// refundAllOp lists an order's charges, refunds each captured one through the
+// payment provider, and marks the order refunded.
+func refundAllOp(providerURL string) *CustomOperation {
+	return &CustomOperation{
+		Name:        "RefundOrder",
+		Consistency: ConsistencyAtomic,
+		Steps: []Step{
+			{Type: StepList, Resource: "charges", Filter: map[string]string{"orderId": "input.orderId"}, As: "charges"},
+			{Type: StepSet, Var: "refunded", Value: "0"},
+			{
+				Type:  StepForEach,
+				Items: `filter(charges, .status == "captured")`,
+				As:    "charge",
+				Steps: []Step{
+					{
+						Type:    StepHTTP,
+						URL:     providerURL + "/charges/{{ charge.id }}/refunds",
+						Headers: map[string]string{"Idempotency-Key": "refund-{{ charge.id }}"},
+						Body:    `{"amount": {{ charge.amount }}, "reason": "{{ input.reason }}"}`,
+						As:      "refund",
+					},
+					{Type: StepUpdate, Resource: "charges", ID: "charge.id", Set: map[string]string{
+						"status":   `"refunded"`,
+						"refundId": "refund.body.id",
+					}},
+					{Type: StepSet, Var: "refunded", Value: "refunded + charge.amount"},
+				},
+			},
+			{
+				Type:      StepIf,
+				Condition: "refunded > 0",
+				Steps: []Step{
+					{Type: StepUpdate, Resource: "orders", ID: "input.orderId", Set: map[string]string{"status": `"refunded"`}},
+				},
+				Else: []Step{
+					{Type: StepSet, Var: "note", Value: `"nothing to refund"`},
+				},
+			},
+		},
+		Response: map[string]string{"refunded": "refunded"},
+	}
+}
+
+func TestExecutor_RefundAllCapturedCharges(t *testing.T) {
+	var requests []map[string]interface{}
+	var mu sync.Mutex
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		body["path"] = r.URL.Path
+		body["key"] = r.Header.Get("Idempotency-Key")
+		body["method"] = r.Method
+		body["contentType"] = r.Header.Get("Content-Type")
+		mu.Lock()
+		requests = append(requests, body)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "re_` + strings.Split(r.URL.Path, "/")[2] + `"}`))
+	}))
+	defer provider.Close()
+
+	store, executor := setupRefundTest(t)
+	result := executor.Execute(context.Background(), refundAllOp(provider.URL), &OperationRequest{
+		Data: map[string]interface{}{"orderId": "ord-1", "reason": "customer request"},
+	})
+	require.Equal(t, StatusSuccess, result.Status, "error: %v", result.Error)
+	assert.Equal(t, float64(80), result.Item.Data["refunded"])
+
+	require.Len(t, requests, 2)
+	byPath := make(map[interface{}]map[string]interface{})
+	for _, r := range requests {
+		byPath[r["path"]] = r
+	}
+	first := byPath["/charges/ch-1/refunds"]
+	require.NotNil(t, first)
+	assert.Equal(t, "refund-ch-1", first["key"])
+	assert.Equal(t, http.MethodPost, first["method"])
+	assert.Equal(t, "application/json", first["contentType"])
+	assert.Equal(t, float64(30), first["amount"])
+	assert.Equal(t, "customer request", first["reason"])
+	assert.Contains(t, byPath, "/charges/ch-3/refunds")
+
+	charges := store.Get("", "charges")
+	assert.Equal(t, "refunded", charges.Get("ch-1").Data["status"])
+	assert.Equal(t, "re_ch-1", charges.Get("ch-1").Data["refundId"])
+	assert.Equal(t, "pending", charges.Get("ch-2").Data["status"])
+	assert.Equal(t, "refunded", charges.Get("ch-3").Data["status"])
+	assert.Equal(t, "refunded", store.Get("", "orders").Get("ord-1").Data["status"])
+}
+
+func TestExecutor_RefundAll_ProviderFailureRollsBack(t *testing.T) {
+	var calls int32
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 2 {
+			w.WriteHeader(http.StatusPaymentRequired)
+			_, _ = w.Write([]byte(`{"error": "card_declined"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": "re_1"}`))
+	}))
+	defer provider.Close()
+
+	store, executor := setupRefundTest(t)
+	result := executor.Execute(context.Background(), refundAllOp(provider.URL), &OperationRequest{
+		Data: map[string]interface{}{"orderId": "ord-1", "reason": "dup"},
+	})
+	require.Equal(t, StatusValidationError, result.Status)
+	assert.Contains(t, result.Error.Error(), "step 2 (foreach) failed: item 1: step 0 (http) failed")
+	assert.Contains(t, result.Error.Error(), "returned 402")
+
+	var ve *ValidationError
+	require.ErrorAs(t, result.Error, &ve)
+	assert.Equal(t, http.StatusBadGateway, ve.StatusCode())
+
+	// The charge updated before the failing refund is restored.
+	charges := store.Get("", "charges")
+	for _, id := range []string{"ch-1", "ch-3"} {
+		assert.Equal(t, "captured", charges.Get(id).Data["status"], id)
+		assert.NotContains(t, charges.Get(id).Data, "refundId", id)
+	}
+	assert.Equal(t, "paid", store.Get("", "orders").Get("ord-1").Data["status"])
+}
+
+func TestExecutor_IfStep_Else(t *testing.T) {
+	_, executor := setupRefundTest(t)
+	op := refundAllOp("http://127.0.0.1:0")
+	result := executor.Execute(context.Background(), op, &OperationRequest{
+		Data: map[string]interface{}{"orderId": "ord-none"},
+	})
+	require.Equal(t, StatusSuccess, result.Status, "error: %v", result.Error)
+	assert.Equal(t, 0, result.Item.Data["refunded"])
+}
+
+func TestExecutor_IfStep_NestedFailureRollsBack(t *testing.T) {
+	store, executor := setupExecutorTest(t)
+	op := &CustomOperation{
+		Name:        "nested",
+		Consistency: ConsistencyAtomic,
+		Steps: []Step{
+			{Type: StepUpdate, Resource: "accounts", ID: `"acc-1"`, Set: map[string]string{"balance": "0"}},
+			{
+				Type:      StepIf,
+				Condition: "true",
+				Steps: []Step{
+					{Type: StepCreate, Resource: "logs", Set: map[string]string{"msg": `"drained"`}},
+					{Type: StepValidate, Condition: "false", ErrorMessage: "nope", ErrorStatus: 409},
+				},
+			},
+		},
+	}
+
+	result := executor.Execute(context.Background(), op, &OperationRequest{})
+	require.Equal(t, StatusValidationError, result.Status)
+	assert.Contains(t, result.Error.Error(), "step 1 (if) failed: then step 1 (validate) failed")
+	assert.Contains(t, result.Error.Error(), "nope")
+	assert.Equal(t, float64(1000), store.Get("", "accounts").Get("acc-1").Data["balance"])
+	assert.Equal(t, 0, store.Get("", "logs").Count())
+}
+
+func TestExecutor_ForEachStep_RestoresLoopVariable(t *testing.T) {
+	_, executor := setupExecutorTest(t)
+	op := &CustomOperation{
+		Name: "sum",
+		Steps: []Step{
+			{Type: StepSet, Var: "n", Value: `"outer"`},
+			{Type: StepSet, Var: "total", Value: "0"},
+			{Type: StepForEach, Items: "[1, 2, 3]", As: "n", Steps: []Step{
+				{Type: StepSet, Var: "total", Value: "total + n"},
+			}},
+		},
+		Response: map[string]string{"total": "total", "n": "n"},
+	}
+
+	result := executor.Execute(context.Background(), op, &OperationRequest{})
+	require.Equal(t, StatusSuccess, result.Status, "error: %v", result.Error)
+	assert.Equal(t, 6, result.Item.Data["total"])
+	assert.Equal(t, "outer", result.Item.Data["n"])
+}
+
+func TestExecutor_ControlFlowStep_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		step Step
+		want string
+	}{
+		{"if without condition", Step{Type: StepIf, Steps: []Step{{Type: StepSet, Var: "x", Value: "1"}}}, "requires condition"},
+		{"if without branches", Step{Type: StepIf, Condition: "true"}, "requires steps or else"},
+		{"foreach without items", Step{Type: StepForEach, As: "x", Steps: []Step{{Type: StepSet, Var: "y", Value: "1"}}}, "requires items"},
+		{"foreach without as", Step{Type: StepForEach, Items: "[1]", Steps: []Step{{Type: StepSet, Var: "y", Value: "1"}}}, "requires 'as'"},
+		{"foreach over non-array", Step{Type: StepForEach, Items: "42", As: "x", Steps: []Step{{Type: StepSet, Var: "y", Value: "1"}}}, "expected an array"},
+		{"http without url", Step{Type: StepHTTP}, "requires url"},
+		{"http bad timeout", Step{Type: StepHTTP, URL: "http://localhost", Timeout: "soon"}, "invalid http step timeout"},
+		{"http bad template", Step{Type: StepHTTP, URL: "http://localhost/{{ missing. }}"}, "url template failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, executor := setupExecutorTest(t)
+			result := executor.Execute(context.Background(), &CustomOperation{Name: "bad", Steps: []Step{tt.step}}, &OperationRequest{})
+			require.Equal(t, StatusError, result.Status)
+			assert.Contains(t, result.Error.Error(), tt.want)
+		})
+	}
+}
+
+func TestExecutor_HTTPStep_CapturesResponse(t *testing.T) {
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Rate-Remaining", "9")
+		_, _ = w.Write([]byte("plain " + r.Method))
+	}))
+	defer provider.Close()
+
+	_, executor := setupExecutorTest(t)
+	op := &CustomOperation{
+		Name: "fetch",
+		Steps: []Step{
+			{Type: StepHTTP, Method: "put", URL: provider.URL, As: "resp"},
+		},
+		Response: map[string]string{
+			"status":    "resp.status",
+			"body":      "resp.body",
+			"remaining": `resp.headers["X-Rate-Remaining"]`,
+		},
+	}
+
+	result := executor.Execute(context.Background(), op, &OperationRequest{})
+	require.Equal(t, StatusSuccess, result.Status, "error: %v", result.Error)
+	assert.Equal(t, 200, result.Item.Data["status"])
+	assert.Equal(t, "plain PUT", result.Item.Data["body"])
+	assert.Equal(t, "9", result.Item.Data["remaining"])
+}
+
+func TestExecutor_HTTPStep_ErrorOverrides(t *testing.T) {
+	_, executor := setupExecutorTest(t)
+	op := &CustomOperation{
+		Name: "unreachable",
+		Steps: []Step{
+			{Type: StepHTTP, URL: "http://127.0.0.1:1/", Timeout: "1s", ErrorMessage: "provider down", ErrorStatus: 503},
+		},
+	}
+
+	result := executor.Execute(context.Background(), op, &OperationRequest{})
+	require.Equal(t, StatusValidationError, result.Status)
+	var ve *ValidationError
+	require.ErrorAs(t, result.Error, &ve)
+	assert.Equal(t, "provider down", ve.Message)
+	assert.Equal(t, 503, ve.StatusCode())
+}
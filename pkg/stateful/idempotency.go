@@ -0,0 +1,241 @@
+package stateful
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/getmockd/mockd/pkg/config"
+)
+
+// DefaultIdempotencyHeader is the request header carrying an idempotency key.
+const DefaultIdempotencyHeader = "Idempotency-Key"
+
+// DefaultIdempotencyTTL is how long keys are remembered when no TTL is configured.
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// idempotencySweepInterval bounds how often expired keys are purged.
+const idempotencySweepInterval = time.Minute
+
+// IdempotencyPolicy is a resolved config.IdempotencyConfig.
+type IdempotencyPolicy struct {
+	Header   string
+	TTL      time.Duration
+	Required bool
+}
+
+// NewIdempotencyPolicy resolves defaults and validates an idempotency config.
+// A nil config yields a nil policy (idempotency disabled).
+func NewIdempotencyPolicy(cfg *config.IdempotencyConfig) (*IdempotencyPolicy, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	policy := &IdempotencyPolicy{
+		Header:   cfg.Header,
+		TTL:      DefaultIdempotencyTTL,
+		Required: cfg.Required,
+	}
+	if policy.Header == "" {
+		policy.Header = DefaultIdempotencyHeader
+	}
+	if cfg.TTL != "" {
+		ttl, err := time.ParseDuration(cfg.TTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ttl %q: %w", cfg.TTL, err)
+		}
+		if ttl <= 0 {
+			return nil, fmt.Errorf("invalid ttl %q: must be positive", cfg.TTL)
+		}
+		policy.TTL = ttl
+	}
+	return policy, nil
+}
+
+// StoredResponse is a protocol response captured for replay.
+type StoredResponse struct {
+	StatusCode int
+	Header     map[string][]string
+	Body       []byte
+}
+
+// IdempotencyKeyError is returned when an idempotency key cannot be honoured:
+// it was first used with a different request, or that request is still running.
+type IdempotencyKeyError struct {
+	Key        string
+	InProgress bool
+}
+
+func (e *IdempotencyKeyError) Error() string {
+	if e.InProgress {
+		return fmt.Sprintf("a request with idempotency key %q is still in progress", e.Key)
+	}
+	return fmt.Sprintf("idempotency key %q was already used with a different request", e.Key)
+}
+
+// StatusCode returns the HTTP status code for this error.
+func (e *IdempotencyKeyError) StatusCode() int {
+	return http.StatusConflict
+}
+
+// ErrorCode returns the protocol-agnostic error code.
+func (e *IdempotencyKeyError) ErrorCode() ErrorCode {
+	return ErrCodeConflict
+}
+
+// Hint returns a user-friendly suggestion for resolving this error.
+func (e *IdempotencyKeyError) Hint() string {
+	if e.InProgress {
+		return "Wait for the original request to finish before retrying."
+	}
+	return "Use a new idempotency key for a request with a different body."
+}
+
+// idempotencyEntry tracks one key. A nil response marks a request in flight.
+type idempotencyEntry struct {
+	fingerprint string
+	response    *StoredResponse
+	expires     time.Time
+}
+
+// IdempotencyScope builds the cache scope for a request bound to a table
+// and/or custom operation in a workspace. Keys remembered under it are
+// forgotten when the StateStore resets or drops that table.
+func IdempotencyScope(workspaceID, table, operation string) string {
+	return workspaceID + "\x00" + table + "\x00" + operation
+}
+
+// splitIdempotencyID splits a cache entry ID built from IdempotencyScope into
+// its workspace and table. The workspace is taken as everything before the
+// last three separators because partition workspace IDs contain one too.
+func splitIdempotencyID(id string) (workspaceID, table string, ok bool) {
+	parts := strings.Split(id, "\x00")
+	if len(parts) < 4 {
+		return "", "", false
+	}
+	n := len(parts)
+	return strings.Join(parts[:n-3], "\x00"), parts[n-3], true
+}
+
+// IdempotencyCache remembers responses by idempotency key so retried requests
+// can be answered without re-executing them.
+//
+// Keys are namespaced by a caller-chosen scope (typically built with
+// IdempotencyScope), so the same key used against two tables is independent.
+type IdempotencyCache struct {
+	mu        sync.Mutex
+	entries   map[string]*idempotencyEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewIdempotencyCache creates an empty IdempotencyCache.
+func NewIdempotencyCache() *IdempotencyCache {
+	return &IdempotencyCache{
+		entries: make(map[string]*idempotencyEntry),
+		now:     time.Now,
+	}
+}
+
+// Begin claims a key for a request with the given fingerprint.
+//
+// If the key was previously completed with the same fingerprint, the stored
+// response is returned and the caller must replay it instead of executing.
+// If the key is unknown (or expired), it is marked in flight and Begin returns
+// (nil, nil); the caller must then call Complete or Abort. Any other reuse
+// returns an *IdempotencyKeyError.
+func (c *IdempotencyCache) Begin(scope, key, fingerprint string, ttl time.Duration) (*StoredResponse, error) {
+	if key == "" {
+		return nil, errors.New("idempotency key must not be empty")
+	}
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.sweepLocked(now)
+
+	id := scope + "\x00" + key
+	if entry, ok := c.entries[id]; ok && now.Before(entry.expires) {
+		if entry.fingerprint != fingerprint {
+			return nil, &IdempotencyKeyError{Key: key}
+		}
+		if entry.response == nil {
+			return nil, &IdempotencyKeyError{Key: key, InProgress: true}
+		}
+		return entry.response, nil
+	}
+
+	c.entries[id] = &idempotencyEntry{fingerprint: fingerprint, expires: now.Add(ttl)}
+	return nil, nil
+}
+
+// Complete stores the response for a key claimed by Begin.
+func (c *IdempotencyCache) Complete(scope, key string, resp *StoredResponse) {
+	if resp == nil {
+		c.Abort(scope, key)
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[scope+"\x00"+key]; ok {
+		entry.response = resp
+	}
+}
+
+// Abort releases a key claimed by Begin without storing a response, so the
+// request can be retried.
+func (c *IdempotencyCache) Abort(scope, key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id := scope + "\x00" + key
+	if entry, ok := c.entries[id]; ok && entry.response == nil {
+		delete(c.entries, id)
+	}
+}
+
+// Clear forgets every key.
+func (c *IdempotencyCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*idempotencyEntry)
+}
+
+// clearMatching forgets every key whose scope was built by IdempotencyScope
+// and whose workspace and table satisfy match.
+func (c *IdempotencyCache) clearMatching(match func(workspaceID, table string) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id := range c.entries {
+		if workspaceID, table, ok := splitIdempotencyID(id); ok && match(workspaceID, table) {
+			delete(c.entries, id)
+		}
+	}
+}
+
+// Len returns the number of remembered keys, including expired keys that
+// have not been purged yet.
+func (c *IdempotencyCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// sweepLocked purges expired keys at most once per idempotencySweepInterval.
+// Must be called with c.mu held.
+func (c *IdempotencyCache) sweepLocked(now time.Time) {
+	if now.Sub(c.lastSweep) < idempotencySweepInterval {
+		return
+	}
+	c.lastSweep = now
+	for id, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, id)
+		}
+	}
+}
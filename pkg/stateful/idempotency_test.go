@@ -0,0 +1,175 @@
+package stateful
+
+import (
+	"testing"
+	"time"
+
+	"github.com/getmockd/mockd/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIdempotencyPolicy(t *testing.T) {
+	policy, err := NewIdempotencyPolicy(nil)
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+
+	policy, err = NewIdempotencyPolicy(&config.IdempotencyConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultIdempotencyHeader, policy.Header)
+	assert.Equal(t, DefaultIdempotencyTTL, policy.TTL)
+
+	policy, err = NewIdempotencyPolicy(&config.IdempotencyConfig{Header: "X-Request-Key", TTL: "10m", Required: true})
+	require.NoError(t, err)
+	assert.Equal(t, "X-Request-Key", policy.Header)
+	assert.Equal(t, 10*time.Minute, policy.TTL)
+	assert.True(t, policy.Required)
+
+	_, err = NewIdempotencyPolicy(&config.IdempotencyConfig{TTL: "soon"})
+	require.Error(t, err)
+	_, err = NewIdempotencyPolicy(&config.IdempotencyConfig{TTL: "-1m"})
+	require.Error(t, err)
+}
+
+func TestIdempotencyCache_ReplayAndConflict(t *testing.T) {
+	cache := NewIdempotencyCache()
+
+	stored, err := cache.Begin("payments", "key-1", "fp-a", time.Hour)
+	require.NoError(t, err)
+	require.Nil(t, stored)
+
+	// A retry while the first request is running is rejected.
+	_, err = cache.Begin("payments", "key-1", "fp-a", time.Hour)
+	var keyErr *IdempotencyKeyError
+	require.ErrorAs(t, err, &keyErr)
+	assert.True(t, keyErr.InProgress)
+
+	resp := &StoredResponse{StatusCode: 201, Body: []byte(`{"id":"pay_1"}`)}
+	cache.Complete("payments", "key-1", resp)
+
+	stored, err = cache.Begin("payments", "key-1", "fp-a", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, resp, stored)
+
+	_, err = cache.Begin("payments", "key-1", "fp-b", time.Hour)
+	require.ErrorAs(t, err, &keyErr)
+	assert.False(t, keyErr.InProgress)
+	assert.Equal(t, 409, keyErr.StatusCode())
+	assert.Equal(t, ErrCodeConflict, GetErrorCode(err))
+
+	// Scopes are independent.
+	stored, err = cache.Begin("refunds", "key-1", "fp-b", time.Hour)
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+}
+
+func TestIdempotencyCache_AbortReleasesKey(t *testing.T) {
+	cache := NewIdempotencyCache()
+
+	_, err := cache.Begin("payments", "key-1", "fp-a", time.Hour)
+	require.NoError(t, err)
+	cache.Abort("payments", "key-1")
+
+	// After an abort the key may be reused, even with a different request.
+	stored, err := cache.Begin("payments", "key-1", "fp-b", time.Hour)
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+
+	// Abort never discards a completed response.
+	cache.Complete("payments", "key-1", &StoredResponse{StatusCode: 201})
+	cache.Abort("payments", "key-1")
+	stored, err = cache.Begin("payments", "key-1", "fp-b", time.Hour)
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+}
+
+func TestIdempotencyCache_Expiry(t *testing.T) {
+	cache := NewIdempotencyCache()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	_, err := cache.Begin("payments", "key-1", "fp-a", time.Minute)
+	require.NoError(t, err)
+	cache.Complete("payments", "key-1", &StoredResponse{StatusCode: 201})
+
+	now = now.Add(2 * time.Minute)
+	stored, err := cache.Begin("payments", "key-1", "fp-b", time.Minute)
+	require.NoError(t, err, "expired keys can be reused")
+	assert.Nil(t, stored)
+	assert.Equal(t, 1, cache.Len())
+
+	cache.Clear()
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestStateStore_ResetForgetsIdempotencyKeys(t *testing.T) {
+	store := NewStateStore()
+	require.NoError(t, store.Register("", &ResourceConfig{Name: "payments"}))
+	require.NoError(t, store.Register("", &ResourceConfig{Name: "refunds"}))
+	cache := store.Idempotency()
+
+	remember := func(workspaceID, table string) {
+		t.Helper()
+		scope := IdempotencyScope(workspaceID, table, "")
+		_, err := cache.Begin(scope, "key-1", "fp", time.Hour)
+		require.NoError(t, err)
+		cache.Complete(scope, "key-1", &StoredResponse{StatusCode: 201})
+	}
+	remembered := func(workspaceID, table string) bool {
+		t.Helper()
+		stored, err := cache.Begin(IdempotencyScope(workspaceID, table, ""), "key-1", "fp", time.Hour)
+		require.NoError(t, err)
+		return stored != nil
+	}
+	partition := PartitionWorkspaceID("", "tenant-a")
+
+	remember("", "payments")
+	remember("", "refunds")
+	remember(partition, "payments")
+	_, err := store.Reset("", "payments")
+	require.NoError(t, err)
+	assert.False(t, remembered("", "payments"), "reset table forgets its keys")
+	assert.True(t, remembered("", "refunds"), "other tables keep their keys")
+	assert.True(t, remembered(partition, "payments"), "partitions keep their keys")
+
+	require.NotNil(t, store.Get(partition, "payments"))
+	assert.True(t, store.DropPartition("", "tenant-a"))
+	assert.False(t, remembered(partition, "payments"), "dropped partition forgets its keys")
+
+	remember("", "refunds")
+	_, err = store.ClearResource("", "refunds")
+	require.NoError(t, err)
+	assert.False(t, remembered("", "refunds"), "cleared table forgets its keys")
+}
+
+func TestBridge_GetIdempotencyPolicy(t *testing.T) {
+	store := NewStateStore()
+	require.NoError(t, store.Register("", &ResourceConfig{
+		Name:        "payments",
+		Idempotency: &config.IdempotencyConfig{TTL: "1h"},
+	}))
+	require.NoError(t, store.Register("", &ResourceConfig{Name: "users"}))
+	bridge := NewBridge(store)
+	bridge.RegisterCustomOperation("", "Capture", &CustomOperation{
+		Name:        "Capture",
+		Idempotency: &config.IdempotencyConfig{Header: "X-Capture-Key"},
+	})
+
+	policy := bridge.GetIdempotencyPolicy("", "payments", "")
+	require.NotNil(t, policy)
+	assert.Equal(t, time.Hour, policy.TTL)
+
+	assert.Nil(t, bridge.GetIdempotencyPolicy("", "users", ""))
+
+	policy = bridge.GetIdempotencyPolicy("", "payments", "Capture")
+	require.NotNil(t, policy)
+	assert.Equal(t, "X-Capture-Key", policy.Header, "operation policy takes precedence")
+
+	// Config round-trips through the resource.
+	assert.Equal(t, "1h", store.Get("", "payments").Config().Idempotency.TTL)
+
+	require.Error(t, store.Register("", &ResourceConfig{
+		Name:        "bad",
+		Idempotency: &config.IdempotencyConfig{TTL: "forever"},
+	}))
+}
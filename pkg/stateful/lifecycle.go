@@ -0,0 +1,307 @@
+package stateful
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+	"github.com/getmockd/mockd/internal/cron"
+	"github.com/getmockd/mockd/pkg/config"
+)
+
+// DefaultLifecycleField is the item field a lifecycle transition reads and
+// writes when none is configured.
+const DefaultLifecycleField = "status"
+
+// DefaultLifecycleInterval is how often the scheduler evaluates lifecycle rules.
+const DefaultLifecycleInterval = time.Second
+
+// lifecycleRule is a compiled config.LifecycleTransition.
+type lifecycleRule struct {
+	cfg       *config.LifecycleTransition
+	field     string
+	delay     time.Duration
+	schedule  *cron.Schedule
+	condition *vm.Program
+
+	// Cron bookkeeping, guarded by the owning resource's mutex.
+	armed    bool
+	nextFire time.Time
+}
+
+// LifecycleChange describes one item moved by a lifecycle transition.
+type LifecycleChange struct {
+	Resource string
+	ItemID   string
+	Field    string
+	From     string
+	To       string
+}
+
+// compileLifecycle validates lifecycle transitions and prepares them for evaluation.
+func compileLifecycle(transitions []*config.LifecycleTransition) ([]*lifecycleRule, error) {
+	if len(transitions) == 0 {
+		return nil, nil
+	}
+
+	rules := make([]*lifecycleRule, 0, len(transitions))
+	for i, t := range transitions {
+		if t == nil {
+			return nil, fmt.Errorf("lifecycle[%d]: must not be null", i)
+		}
+		rule, err := compileLifecycleRule(t)
+		if err != nil {
+			return nil, fmt.Errorf("lifecycle[%d]: %w", i, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func compileLifecycleRule(t *config.LifecycleTransition) (*lifecycleRule, error) {
+	if t.From == "" || t.To == "" {
+		return nil, errors.New("from and to are required")
+	}
+
+	rule := &lifecycleRule{cfg: t, field: t.Field}
+	if rule.field == "" {
+		rule.field = DefaultLifecycleField
+	}
+
+	switch {
+	case t.Delay == "" && t.Cron == "":
+		return nil, errors.New("one of delay or cron is required")
+	case t.Delay != "" && t.Cron != "":
+		return nil, errors.New("delay and cron are mutually exclusive")
+	case t.Delay != "":
+		d, err := time.ParseDuration(t.Delay)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid delay %q", t.Delay)
+		}
+		rule.delay = d
+	default:
+		schedule, err := cron.Parse(t.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron %q: %w", t.Cron, err)
+		}
+		rule.schedule = schedule
+	}
+
+	if t.Condition != "" {
+		env := map[string]interface{}{
+			"item": map[string]interface{}{},
+			"now":  time.Time{},
+		}
+		program, err := expr.Compile(t.Condition, expr.Env(env), expr.AsBool())
+		if err != nil {
+			return nil, fmt.Errorf("invalid condition %q: %w", t.Condition, err)
+		}
+		rule.condition = program
+	}
+
+	return rule, nil
+}
+
+// due reports whether a cron rule fires at now, advancing its schedule.
+// Delay rules are always due; their timing is checked per item.
+// Must be called with the resource mutex held.
+func (rule *lifecycleRule) due(now time.Time) bool {
+	if rule.schedule == nil {
+		return true
+	}
+	if !rule.armed {
+		rule.armed = true
+		rule.nextFire = rule.schedule.Next(now)
+		return false
+	}
+	if rule.nextFire.IsZero() || now.Before(rule.nextFire) {
+		return false
+	}
+	rule.nextFire = rule.schedule.Next(now)
+	return true
+}
+
+// matches reports whether an item should be moved by this rule at now.
+func (rule *lifecycleRule) matches(item *ResourceItem, idField string, now time.Time) (bool, error) {
+	if state, _ := item.Data[rule.field].(string); state != rule.cfg.From {
+		return false, nil
+	}
+	if rule.schedule == nil && now.Sub(item.UpdatedAt) < rule.delay {
+		return false, nil
+	}
+	if rule.condition == nil {
+		return true, nil
+	}
+
+	data := item.ToJSON()
+	if idField != DefaultIDField {
+		data[idField] = item.ID
+	}
+	out, err := expr.Run(rule.condition, map[string]interface{}{"item": data, "now": now})
+	if err != nil {
+		return false, fmt.Errorf("lifecycle condition %q: %w", rule.cfg.Condition, err)
+	}
+	ok, _ := out.(bool)
+	return ok, nil
+}
+
+// HasLifecycle returns true if lifecycle transitions are configured for this resource.
+func (r *StatefulResource) HasLifecycle() bool {
+	return len(r.lifecycle) > 0
+}
+
+// ApplyLifecycle evaluates the resource's lifecycle transitions at now and
+// moves every matching item. Each item moves at most once per call, so chained
+// transitions (a → b → c) advance one step at a time. Condition evaluation
+// errors skip the affected item and are returned alongside the changes.
+func (r *StatefulResource) ApplyLifecycle(now time.Time) ([]LifecycleChange, []error) {
+	if len(r.lifecycle) == 0 {
+		return nil, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var (
+		changes []LifecycleChange
+		errs    []error
+		moved   map[string]bool
+	)
+	for _, rule := range r.lifecycle {
+		if !rule.due(now) {
+			continue
+		}
+		ruleErrored := false
+		for id, item := range r.items {
+			if moved[id] {
+				continue
+			}
+			ok, err := rule.matches(item, r.idField, now)
+			if err != nil {
+				// Report a broken condition once per rule rather than once per item.
+				if !ruleErrored {
+					errs = append(errs, err)
+					ruleErrored = true
+				}
+				continue
+			}
+			if !ok {
+				continue
+			}
+
+			data := make(map[string]interface{}, len(item.Data))
+			for k, v := range item.Data {
+				data[k] = v
+			}
+			data[rule.field] = rule.cfg.To
+			next := &ResourceItem{
+				ID:        id,
+				Data:      data,
+				CreatedAt: item.CreatedAt,
+				UpdatedAt: now,
+			}
+			r.items[id] = next
+			r.publishLocked(ChangeUpdated, id, next, item)
+
+			if moved == nil {
+				moved = make(map[string]bool)
+			}
+			moved[id] = true
+			changes = append(changes, LifecycleChange{
+				Resource: r.name,
+				ItemID:   id,
+				Field:    rule.field,
+				From:     rule.cfg.From,
+				To:       rule.cfg.To,
+			})
+		}
+	}
+	return changes, errs
+}
+
+// LifecycleScheduler periodically applies lifecycle transitions to every
+// resource in a StateStore and reports each move through the store's Observer
+// as an update.
+type LifecycleScheduler struct {
+	store    *StateStore
+	interval time.Duration
+
+	started   atomic.Bool
+	stopCh    chan struct{}
+	stoppedCh chan struct{}
+	stopOnce  sync.Once
+}
+
+// NewLifecycleScheduler creates a scheduler for the given store.
+// A non-positive interval uses DefaultLifecycleInterval.
+func NewLifecycleScheduler(store *StateStore, interval time.Duration) *LifecycleScheduler {
+	if interval <= 0 {
+		interval = DefaultLifecycleInterval
+	}
+	return &LifecycleScheduler{
+		store:     store,
+		interval:  interval,
+		stopCh:    make(chan struct{}),
+		stoppedCh: make(chan struct{}),
+	}
+}
+
+// Start launches the background evaluation loop. Calling Start more than once has no effect.
+func (s *LifecycleScheduler) Start() {
+	if !s.started.CompareAndSwap(false, true) {
+		return
+	}
+	go s.run()
+}
+
+// Stop halts the evaluation loop and waits for an in-flight tick to finish.
+// It is safe to call Stop on a scheduler that was never started.
+func (s *LifecycleScheduler) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+		if s.started.Load() {
+			<-s.stoppedCh
+		}
+	})
+}
+
+func (s *LifecycleScheduler) run() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	defer close(s.stoppedCh)
+
+	for {
+		select {
+		case now := <-ticker.C:
+			s.Tick(now)
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// Tick runs a single evaluation pass at now and returns the changes made.
+// It is called by the background loop and can be used directly for
+// deterministic control in tests.
+func (s *LifecycleScheduler) Tick(now time.Time) []LifecycleChange {
+	observer := s.store.GetObserver()
+
+	var all []LifecycleChange
+	for _, resource := range s.store.lifecycleResources() {
+		start := time.Now()
+		changes, errs := resource.ApplyLifecycle(now)
+		for _, err := range errs {
+			observer.OnError(resource.Name(), "lifecycle", err)
+		}
+		elapsed := time.Since(start)
+		for _, c := range changes {
+			observer.OnUpdate(c.Resource, c.ItemID, elapsed)
+		}
+		all = append(all, changes...)
+	}
+	return all
+}
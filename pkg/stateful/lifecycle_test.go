@@ -0,0 +1,155 @@
+package stateful
+
+import (
+	"testing"
+	"time"
+
+	"github.com/getmockd/mockd/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLifecycleStore(t *testing.T, transitions []*config.LifecycleTransition, seed []map[string]interface{}) *StateStore {
+	t.Helper()
+	store := NewStateStore()
+	require.NoError(t, store.Register("", &ResourceConfig{
+		Name:      "orders",
+		SeedData:  seed,
+		Lifecycle: transitions,
+	}))
+	return store
+}
+
+func TestLifecycle_DelayChainsOneStepPerTick(t *testing.T) {
+	store := newLifecycleStore(t, []*config.LifecycleTransition{
+		{From: "pending", To: "processing", Delay: "5s"},
+		{From: "processing", To: "shipped", Delay: "10s"},
+	}, []map[string]interface{}{{"id": "o1", "status": "pending"}})
+	scheduler := NewLifecycleScheduler(store, 0)
+	orders := store.Get("", "orders")
+	start := orders.Get("o1").UpdatedAt
+
+	assert.Empty(t, scheduler.Tick(start.Add(4*time.Second)))
+	assert.Equal(t, "pending", orders.Get("o1").Data["status"])
+
+	changes := scheduler.Tick(start.Add(5 * time.Second))
+	require.Len(t, changes, 1)
+	assert.Equal(t, LifecycleChange{Resource: "orders", ItemID: "o1", Field: "status", From: "pending", To: "processing"}, changes[0])
+	assert.Equal(t, "processing", orders.Get("o1").Data["status"])
+
+	// The second delay counts from the first transition.
+	assert.Empty(t, scheduler.Tick(start.Add(14*time.Second)))
+	require.Len(t, scheduler.Tick(start.Add(15*time.Second)), 1)
+	assert.Equal(t, "shipped", orders.Get("o1").Data["status"])
+}
+
+func TestLifecycle_Condition(t *testing.T) {
+	store := newLifecycleStore(t, []*config.LifecycleTransition{
+		{From: "processing", To: "succeeded", Delay: "0s", Condition: "item.amount < 1000"},
+	}, []map[string]interface{}{
+		{"id": "small", "status": "processing", "amount": 50},
+		{"id": "large", "status": "processing", "amount": 5000},
+	})
+	orders := store.Get("", "orders")
+
+	changes := NewLifecycleScheduler(store, 0).Tick(time.Now())
+	require.Len(t, changes, 1)
+	assert.Equal(t, "small", changes[0].ItemID)
+	assert.Equal(t, "processing", orders.Get("large").Data["status"])
+}
+
+func TestLifecycle_CustomField(t *testing.T) {
+	store := newLifecycleStore(t, []*config.LifecycleTransition{
+		{Field: "state", From: "active", To: "expired", Delay: "1h"},
+	}, []map[string]interface{}{{"id": "t1", "state": "active", "status": "active"}})
+	orders := store.Get("", "orders")
+
+	NewLifecycleScheduler(store, 0).Tick(time.Now().Add(2 * time.Hour))
+	assert.Equal(t, "expired", orders.Get("t1").Data["state"])
+	assert.Equal(t, "active", orders.Get("t1").Data["status"])
+}
+
+func TestLifecycle_CronFiresOnSchedule(t *testing.T) {
+	store := newLifecycleStore(t, []*config.LifecycleTransition{
+		{From: "open", To: "closed", Cron: "0 * * * *"},
+	}, []map[string]interface{}{{"id": "o1", "status": "open"}})
+	scheduler := NewLifecycleScheduler(store, 0)
+	base := time.Date(2026, 3, 10, 10, 15, 0, 0, time.UTC)
+
+	// The first tick only arms the schedule.
+	assert.Empty(t, scheduler.Tick(base))
+	assert.Empty(t, scheduler.Tick(base.Add(30*time.Minute)))
+	assert.Len(t, scheduler.Tick(base.Add(45*time.Minute)), 1)
+}
+
+func TestLifecycle_ObserverSeesUpdates(t *testing.T) {
+	store := newLifecycleStore(t, []*config.LifecycleTransition{
+		{From: "pending", To: "done", Delay: "0s"},
+		{From: "pending", To: "broken", Delay: "0s", Condition: "item.missing.field == 1"},
+	}, []map[string]interface{}{{"id": "a", "status": "pending"}, {"id": "b", "status": "pending"}})
+	obs := NewMetricsObserver()
+	store.SetObserver(obs)
+
+	NewLifecycleScheduler(store, 0).Tick(time.Now())
+
+	snapshot := obs.Snapshot()
+	assert.Equal(t, int64(2), snapshot.UpdateCount)
+	assert.Equal(t, int64(0), snapshot.ErrorCount, "items moved by the first rule are not evaluated again")
+}
+
+func TestLifecycle_ConditionErrorReportedOncePerRule(t *testing.T) {
+	store := newLifecycleStore(t, []*config.LifecycleTransition{
+		{From: "pending", To: "done", Delay: "0s", Condition: "item.meta.flag == true"},
+	}, []map[string]interface{}{{"id": "a", "status": "pending"}, {"id": "b", "status": "pending"}})
+	obs := NewMetricsObserver()
+	store.SetObserver(obs)
+
+	assert.Empty(t, NewLifecycleScheduler(store, 0).Tick(time.Now()))
+	assert.Equal(t, int64(1), obs.Snapshot().ErrorCount)
+}
+
+func TestLifecycle_RegisterRejectsInvalidRules(t *testing.T) {
+	tests := []struct {
+		name string
+		rule *config.LifecycleTransition
+	}{
+		{"missing to", &config.LifecycleTransition{From: "a", Delay: "1s"}},
+		{"no schedule", &config.LifecycleTransition{From: "a", To: "b"}},
+		{"both schedules", &config.LifecycleTransition{From: "a", To: "b", Delay: "1s", Cron: "@hourly"}},
+		{"bad delay", &config.LifecycleTransition{From: "a", To: "b", Delay: "soon"}},
+		{"bad cron", &config.LifecycleTransition{From: "a", To: "b", Cron: "every minute"}},
+		{"bad condition", &config.LifecycleTransition{From: "a", To: "b", Delay: "1s", Condition: "item.x +"}},
+		{"non-bool condition", &config.LifecycleTransition{From: "a", To: "b", Delay: "1s", Condition: "1 + 1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewStateStore().Register("", &ResourceConfig{
+				Name:      "orders",
+				Lifecycle: []*config.LifecycleTransition{tt.rule},
+			})
+			assert.ErrorContains(t, err, "invalid lifecycle")
+		})
+	}
+}
+
+func TestLifecycle_ConfigRoundTrip(t *testing.T) {
+	rules := []*config.LifecycleTransition{{From: "a", To: "b", Delay: "1s"}}
+	store := newLifecycleStore(t, rules, nil)
+	assert.Equal(t, rules, store.Get("", "orders").Config().Lifecycle)
+}
+
+func TestLifecycleScheduler_StartStop(t *testing.T) {
+	store := newLifecycleStore(t, []*config.LifecycleTransition{
+		{From: "pending", To: "done", Delay: "0s"},
+	}, []map[string]interface{}{{"id": "a", "status": "pending"}})
+	scheduler := NewLifecycleScheduler(store, 10*time.Millisecond)
+	scheduler.Start()
+	defer scheduler.Stop()
+
+	assert.Eventually(t, func() bool {
+		return store.Get("", "orders").Get("a").Data["status"] == "done"
+	}, time.Second, 10*time.Millisecond)
+
+	// Stopping a never-started scheduler must not block.
+	NewLifecycleScheduler(store, 0).Stop()
+}
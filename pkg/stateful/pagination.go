@@ -0,0 +1,350 @@
+package stateful
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/getmockd/mockd/pkg/config"
+)
+
+// PaginationStyle selects how a list request addresses a page.
+type PaginationStyle string
+
+const (
+	// PaginationOffset pages with limit/offset.
+	PaginationOffset PaginationStyle = "offset"
+	// PaginationPage pages with a 1-based page number and page size.
+	PaginationPage PaginationStyle = "page"
+	// PaginationCursor pages with item ID cursors (Stripe-style starting_after/ending_before).
+	PaginationCursor PaginationStyle = "cursor"
+	// PaginationToken pages with an opaque base64 token returned by the previous page.
+	PaginationToken PaginationStyle = "token"
+)
+
+// DefaultPageLimit is the page size when neither the request nor the config sets one.
+const DefaultPageLimit = 100
+
+// PaginationPolicy is a resolved config.PaginationConfig.
+type PaginationPolicy struct {
+	Style            PaginationStyle
+	LimitParam       string
+	OffsetParam      string
+	PageParam        string
+	CursorParam      string
+	BeforeParam      string
+	TokenField       string
+	DefaultLimit     int
+	MaxLimit         int
+	LinkHeader       bool
+	TotalCountHeader string
+	Links            bool
+}
+
+// NewPaginationPolicy resolves defaults and validates a pagination config.
+// A nil config yields a nil policy (the default limit/offset and cursor params).
+func NewPaginationPolicy(cfg *config.PaginationConfig) (*PaginationPolicy, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	p := &PaginationPolicy{
+		Style:            PaginationStyle(cfg.Style),
+		LimitParam:       cfg.LimitParam,
+		OffsetParam:      cfg.OffsetParam,
+		PageParam:        cfg.PageParam,
+		CursorParam:      cfg.CursorParam,
+		BeforeParam:      cfg.BeforeParam,
+		TokenField:       cfg.TokenField,
+		DefaultLimit:     cfg.DefaultLimit,
+		MaxLimit:         cfg.MaxLimit,
+		LinkHeader:       cfg.LinkHeader,
+		TotalCountHeader: cfg.TotalCountHeader,
+		Links:            cfg.Links,
+	}
+	if p.Style == "" {
+		p.Style = PaginationOffset
+	}
+	if p.DefaultLimit < 0 || p.MaxLimit < 0 {
+		return nil, fmt.Errorf("defaultLimit and maxLimit must not be negative")
+	}
+	if p.DefaultLimit == 0 {
+		p.DefaultLimit = DefaultPageLimit
+	}
+	if p.MaxLimit > 0 && p.DefaultLimit > p.MaxLimit {
+		p.DefaultLimit = p.MaxLimit
+	}
+
+	switch p.Style {
+	case PaginationOffset:
+		p.LimitParam = withDefault(p.LimitParam, "limit")
+		p.OffsetParam = withDefault(p.OffsetParam, "offset")
+	case PaginationPage:
+		p.LimitParam = withDefault(p.LimitParam, "per_page")
+		p.PageParam = withDefault(p.PageParam, "page")
+	case PaginationCursor:
+		p.LimitParam = withDefault(p.LimitParam, "limit")
+		p.CursorParam = withDefault(p.CursorParam, "starting_after")
+		p.BeforeParam = withDefault(p.BeforeParam, "ending_before")
+	case PaginationToken:
+		p.LimitParam = withDefault(p.LimitParam, "limit")
+		p.CursorParam = withDefault(p.CursorParam, "next_token")
+		p.TokenField = withDefault(p.TokenField, p.CursorParam)
+	default:
+		return nil, fmt.Errorf("unknown pagination style %q (expected offset, page, cursor or token)", cfg.Style)
+	}
+	return p, nil
+}
+
+func withDefault(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
+
+// Params returns the query parameters the policy consumes, so that they are
+// not treated as field filters.
+func (p *PaginationPolicy) Params() []string {
+	params := []string{p.LimitParam}
+	for _, param := range []string{p.OffsetParam, p.PageParam, p.CursorParam, p.BeforeParam} {
+		if param != "" {
+			params = append(params, param)
+		}
+	}
+	return params
+}
+
+// ParseQuery sets the pagination fields of filter from the request query,
+// replacing whatever was there. A malformed page token is a ValidationError.
+func (p *PaginationPolicy) ParseQuery(query url.Values, filter *QueryFilter) error {
+	filter.Limit = p.DefaultLimit
+	filter.Offset = 0
+	filter.StartingAfter = ""
+	filter.EndingBefore = ""
+
+	if n, ok := positiveIntParam(query, p.LimitParam); ok {
+		filter.Limit = n
+	}
+	if p.MaxLimit > 0 && filter.Limit > p.MaxLimit {
+		filter.Limit = p.MaxLimit
+	}
+
+	switch p.Style {
+	case PaginationOffset:
+		if v := query.Get(p.OffsetParam); v != "" {
+			if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+				filter.Offset = n
+			}
+		}
+	case PaginationPage:
+		if n, ok := positiveIntParam(query, p.PageParam); ok {
+			filter.Offset = (n - 1) * filter.Limit
+		}
+	case PaginationCursor:
+		filter.StartingAfter = query.Get(p.CursorParam)
+		if filter.StartingAfter == "" {
+			filter.EndingBefore = query.Get(p.BeforeParam)
+		}
+	case PaginationToken:
+		if token := query.Get(p.CursorParam); token != "" {
+			id, err := DecodePageToken(token)
+			if err != nil {
+				return &ValidationError{Message: "invalid page token", Field: p.CursorParam}
+			}
+			filter.StartingAfter = id
+		}
+	}
+	return nil
+}
+
+func positiveIntParam(query url.Values, name string) (int, bool) {
+	n, err := strconv.Atoi(query.Get(name))
+	return n, err == nil && n > 0
+}
+
+// EncodePageToken returns the opaque token pointing after the item with the given ID.
+func EncodePageToken(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
+// DecodePageToken returns the item ID encoded by EncodePageToken.
+func DecodePageToken(token string) (string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// PageLinks holds the navigation URLs of a list page. Empty means "no such page".
+type PageLinks struct {
+	Self  string
+	First string
+	Prev  string
+	Next  string
+	Last  string
+}
+
+// PageInfo is the pagination output for one list response: navigation links,
+// the next-page token and the total count.
+type PageInfo struct {
+	policy    *PaginationPolicy
+	Links     PageLinks
+	NextToken string
+	Total     int
+}
+
+// Page computes the pagination output for a list response. requestURL is the
+// absolute URL of the list request; list must not have been transformed yet,
+// so that item IDs are still under "id".
+func (p *PaginationPolicy) Page(requestURL *url.URL, filter *QueryFilter, list *PaginatedResponse) *PageInfo {
+	info := &PageInfo{policy: p, Total: list.Meta.Total}
+	link := func(set map[string]string, del ...string) string {
+		u := *requestURL
+		q := u.Query()
+		for _, name := range del {
+			q.Del(name)
+		}
+		for name, value := range set {
+			q.Set(name, value)
+		}
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+	info.Links.Self = requestURL.String()
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = p.DefaultLimit
+	}
+	total := list.Meta.Total
+	lastOffset := 0
+	if total > 0 {
+		lastOffset = (total - 1) / limit * limit
+	}
+	firstID, lastID := pageBoundaryIDs(list.Data)
+
+	switch p.Style {
+	case PaginationOffset:
+		offsetLink := func(offset int) string {
+			return link(map[string]string{p.OffsetParam: strconv.Itoa(offset)})
+		}
+		info.Links.First = offsetLink(0)
+		info.Links.Last = offsetLink(lastOffset)
+		if filter.Offset > 0 {
+			info.Links.Prev = offsetLink(max(filter.Offset-limit, 0))
+		}
+		if list.Meta.HasMore {
+			info.Links.Next = offsetLink(filter.Offset + limit)
+		}
+	case PaginationPage:
+		pageLink := func(page int) string {
+			return link(map[string]string{p.PageParam: strconv.Itoa(page)})
+		}
+		page := filter.Offset/limit + 1
+		info.Links.First = pageLink(1)
+		info.Links.Last = pageLink(lastOffset/limit + 1)
+		if page > 1 {
+			info.Links.Prev = pageLink(page - 1)
+		}
+		if list.Meta.HasMore {
+			info.Links.Next = pageLink(page + 1)
+		}
+	case PaginationCursor:
+		info.Links.First = link(nil, p.CursorParam, p.BeforeParam)
+		backward := filter.EndingBefore != ""
+		// Going backward, has_more reports items before the page.
+		morePrev := (backward && list.Meta.HasMore) || (!backward && filter.StartingAfter != "")
+		moreNext := (!backward && list.Meta.HasMore) || backward
+		if morePrev && firstID != "" {
+			info.Links.Prev = link(map[string]string{p.BeforeParam: firstID}, p.CursorParam)
+		}
+		if moreNext && lastID != "" {
+			info.Links.Next = link(map[string]string{p.CursorParam: lastID}, p.BeforeParam)
+		}
+	case PaginationToken:
+		info.Links.First = link(nil, p.CursorParam)
+		if list.Meta.HasMore && lastID != "" {
+			info.NextToken = EncodePageToken(lastID)
+			info.Links.Next = link(map[string]string{p.CursorParam: info.NextToken})
+		}
+	}
+	return info
+}
+
+// pageBoundaryIDs returns the IDs of the first and last items of a page.
+func pageBoundaryIDs(items []map[string]interface{}) (first, last string) {
+	if len(items) == 0 {
+		return "", ""
+	}
+	first, _ = items[0]["id"].(string)
+	last, _ = items[len(items)-1]["id"].(string)
+	return first, last
+}
+
+// WriteHeaders sets the Link and total count headers the policy asks for.
+func (pi *PageInfo) WriteHeaders(h http.Header) {
+	if pi.policy.LinkHeader {
+		if link := pi.Links.header(); link != "" {
+			h.Set("Link", link)
+		}
+	}
+	if pi.policy.TotalCountHeader != "" {
+		h.Set(pi.policy.TotalCountHeader, strconv.Itoa(pi.Total))
+	}
+}
+
+// header formats the links as an RFC 5988 Link header value.
+func (l PageLinks) header() string {
+	var parts []string
+	for _, rel := range []struct{ name, url string }{
+		{"first", l.First}, {"prev", l.Prev}, {"next", l.Next}, {"last", l.Last},
+	} {
+		if rel.url != "" {
+			parts = append(parts, fmt.Sprintf("<%s>; rel=%q", rel.url, rel.name))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Decorate adds the body fields the policy asks for (the next token and
+// JSON:API links) to a list response built by TransformList.
+func (pi *PageInfo) Decorate(response interface{}) interface{} {
+	p := pi.policy
+	if p.Style != PaginationToken && !p.Links {
+		return response
+	}
+
+	var envelope map[string]interface{}
+	switch v := response.(type) {
+	case map[string]interface{}:
+		envelope = v
+	case *PaginatedResponse:
+		envelope = map[string]interface{}{DefaultDataField: v.Data, "meta": v.Meta}
+	default:
+		return response
+	}
+
+	if p.Style == PaginationToken {
+		if pi.NextToken != "" {
+			envelope[p.TokenField] = pi.NextToken
+		} else {
+			envelope[p.TokenField] = nil
+		}
+	}
+	if p.Links {
+		links := map[string]interface{}{"self": pi.Links.Self}
+		for name, u := range map[string]string{"first": pi.Links.First, "prev": pi.Links.Prev, "next": pi.Links.Next, "last": pi.Links.Last} {
+			if u != "" {
+				links[name] = u
+			} else {
+				links[name] = nil
+			}
+		}
+		envelope["links"] = links
+	}
+	return envelope
+}
@@ -0,0 +1,145 @@
+package stateful
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/getmockd/mockd/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPagedResource registers a resource with 25 items, IDs i01..i25.
+func newPagedResource(t *testing.T, cfg *config.PaginationConfig) *StatefulResource {
+	t.Helper()
+	seed := make([]map[string]interface{}, 25)
+	for i := range seed {
+		seed[i] = map[string]interface{}{"id": fmt.Sprintf("i%02d", i+1)}
+	}
+	store := NewStateStore()
+	require.NoError(t, store.Register("", &ResourceConfig{Name: "items", SeedData: seed, Pagination: cfg}))
+	return store.Get("", "items")
+}
+
+// listPage runs a list request for rawQuery against r, sorted by ID.
+func listPage(t *testing.T, r *StatefulResource, rawQuery string) (*QueryFilter, *PaginatedResponse, *PageInfo) {
+	t.Helper()
+	u, err := url.Parse("http://api.test/items?" + rawQuery)
+	require.NoError(t, err)
+	filter := DefaultQueryFilter()
+	filter.Sort, filter.Order = "id", "asc"
+	require.NoError(t, r.PaginationPolicy().ParseQuery(u.Query(), filter))
+	list := r.List(filter)
+	return filter, list, r.PaginationPolicy().Page(u, filter, list)
+}
+
+func TestNewPaginationPolicy_Defaults(t *testing.T) {
+	policy, err := NewPaginationPolicy(nil)
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+
+	policy, err = NewPaginationPolicy(&config.PaginationConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, PaginationOffset, policy.Style)
+	assert.Equal(t, []string{"limit", "offset"}, policy.Params())
+	assert.Equal(t, DefaultPageLimit, policy.DefaultLimit)
+
+	policy, err = NewPaginationPolicy(&config.PaginationConfig{Style: "page"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"per_page", "page"}, policy.Params())
+
+	policy, err = NewPaginationPolicy(&config.PaginationConfig{Style: "token"})
+	require.NoError(t, err)
+	assert.Equal(t, "next_token", policy.TokenField)
+
+	_, err = NewPaginationPolicy(&config.PaginationConfig{Style: "keyset"})
+	require.ErrorContains(t, err, "unknown pagination style")
+}
+
+func TestPagination_PageStyleWithLinkHeader(t *testing.T) {
+	r := newPagedResource(t, &config.PaginationConfig{Style: "page", LinkHeader: true, TotalCountHeader: "X-Total-Count", MaxLimit: 10})
+
+	filter, list, page := listPage(t, r, "page=2&per_page=50")
+	assert.Equal(t, 10, filter.Limit, "per_page is capped at maxLimit")
+	assert.Equal(t, 10, filter.Offset)
+	assert.Equal(t, "i11", list.Data[0]["id"])
+
+	h := http.Header{}
+	page.WriteHeaders(h)
+	assert.Equal(t, "25", h.Get("X-Total-Count"))
+	assert.Equal(t,
+		`<http://api.test/items?page=1&per_page=50>; rel="first", `+
+			`<http://api.test/items?page=1&per_page=50>; rel="prev", `+
+			`<http://api.test/items?page=3&per_page=50>; rel="next", `+
+			`<http://api.test/items?page=3&per_page=50>; rel="last"`,
+		h.Get("Link"))
+
+	_, list, page = listPage(t, r, "page=3")
+	assert.Len(t, list.Data, 5)
+	assert.Empty(t, page.Links.Next)
+}
+
+func TestPagination_OffsetStyleWithJSONAPILinks(t *testing.T) {
+	r := newPagedResource(t, &config.PaginationConfig{Links: true, DefaultLimit: 10})
+
+	_, list, page := listPage(t, r, "offset=20")
+	assert.Len(t, list.Data, 5)
+	body := page.Decorate(&PaginatedResponse{Data: list.Data, Meta: list.Meta}).(map[string]interface{})
+	links := body["links"].(map[string]interface{})
+	assert.Equal(t, "http://api.test/items?offset=0", links["first"])
+	assert.Equal(t, "http://api.test/items?offset=10", links["prev"])
+	assert.Nil(t, links["next"])
+	assert.Equal(t, "http://api.test/items?offset=20", links["last"])
+	assert.Contains(t, body, "data")
+	assert.Contains(t, body, "meta")
+}
+
+func TestPagination_TokenStyle(t *testing.T) {
+	r := newPagedResource(t, &config.PaginationConfig{Style: "token", CursorParam: "pageToken", TokenField: "nextPageToken"})
+
+	var ids []interface{}
+	query := "limit=10"
+	for pages := 0; pages < 5; pages++ {
+		_, list, page := listPage(t, r, query)
+		for _, item := range list.Data {
+			ids = append(ids, item["id"])
+		}
+		body := page.Decorate(map[string]interface{}{"items": list.Data}).(map[string]interface{})
+		if body["nextPageToken"] == nil {
+			break
+		}
+		token := body["nextPageToken"].(string)
+		decoded, err := DecodePageToken(token)
+		require.NoError(t, err)
+		assert.Equal(t, list.Data[len(list.Data)-1]["id"], decoded)
+		query = "limit=10&pageToken=" + token
+	}
+	assert.Len(t, ids, 25)
+	assert.Equal(t, "i25", ids[24])
+
+	filter := DefaultQueryFilter()
+	err := r.PaginationPolicy().ParseQuery(url.Values{"pageToken": {"%%%"}}, filter)
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+}
+
+func TestPagination_CursorStyleLinks(t *testing.T) {
+	r := newPagedResource(t, &config.PaginationConfig{Style: "cursor", LinkHeader: true})
+
+	_, list, page := listPage(t, r, "limit=10")
+	assert.Empty(t, page.Links.Prev)
+	assert.Equal(t, "http://api.test/items?limit=10&starting_after=i10", page.Links.Next)
+	assert.Equal(t, "i01", list.Data[0]["id"])
+
+	_, list, page = listPage(t, r, "limit=10&starting_after=i10")
+	assert.Equal(t, "i11", list.Data[0]["id"])
+	assert.Equal(t, "http://api.test/items?ending_before=i11&limit=10", page.Links.Prev)
+	assert.Equal(t, "http://api.test/items?limit=10&starting_after=i20", page.Links.Next)
+
+	_, list, page = listPage(t, r, "limit=10&ending_before=i11")
+	assert.Equal(t, "i01", list.Data[0]["id"])
+	assert.Empty(t, page.Links.Prev)
+	assert.Equal(t, "http://api.test/items?limit=10&starting_after=i10", page.Links.Next)
+}
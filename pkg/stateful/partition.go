@@ -0,0 +1,257 @@
+package stateful
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// partitionSeparator joins a workspace ID and an isolation key into a
+// partition workspace ID. NUL cannot appear in workspace IDs or header values.
+const partitionSeparator = "\x00"
+
+// DefaultPartitionTTL is how long an idle partition is kept when no TTL is set.
+const DefaultPartitionTTL = 30 * time.Minute
+
+// partitionSweepInterval bounds how often idle partitions are evicted.
+const partitionSweepInterval = 10 * time.Second
+
+// PartitionWorkspaceID returns the workspace ID under which an isolation
+// partition's tables live. Every API that accepts a workspace ID (Bridge,
+// custom operations, StateStore) accepts a partition workspace ID: tables are
+// copied from the base workspace on first access, starting from their seed
+// data. An empty partition returns workspaceID unchanged.
+func PartitionWorkspaceID(workspaceID, partition string) string {
+	if partition == "" {
+		return workspaceID
+	}
+	return workspaceID + partitionSeparator + partition
+}
+
+// SplitPartitionWorkspaceID splits a partition workspace ID into the base
+// workspace ID and partition key. For a plain workspace ID the partition is "".
+func SplitPartitionWorkspaceID(id string) (workspaceID, partition string) {
+	workspaceID, partition, _ = strings.Cut(id, partitionSeparator)
+	return workspaceID, partition
+}
+
+// partitionContextKey is the context key for an isolation partition key.
+type partitionContextKey struct{}
+
+// ContextWithPartition returns a copy of ctx carrying an isolation partition
+// key. Protocol handlers that reach stateful tables without the original
+// request, such as GraphQL resolvers and change streams, read it back with
+// PartitionFromContext.
+func ContextWithPartition(ctx context.Context, partition string) context.Context {
+	if partition == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, partitionContextKey{}, partition)
+}
+
+// PartitionFromContext returns the isolation partition key carried by ctx,
+// or "" if there is none.
+func PartitionFromContext(ctx context.Context) string {
+	partition, _ := ctx.Value(partitionContextKey{}).(string)
+	return partition
+}
+
+// partitionState tracks when a partition was created and last used.
+type partitionState struct {
+	created    time.Time
+	lastAccess atomic.Int64 // unix nanoseconds
+}
+
+func (p *partitionState) touch(now time.Time) {
+	p.lastAccess.Store(now.UnixNano())
+}
+
+// PartitionInfo describes one isolation partition.
+type PartitionInfo struct {
+	Key        string    `json:"key"`
+	Created    time.Time `json:"created"`
+	LastAccess time.Time `json:"lastAccess"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	// Resources maps each table copied into the partition to its item count.
+	// Tables not yet accessed in the partition are not listed.
+	Resources map[string]int `json:"resources"`
+}
+
+// SetPartitionTTL sets how long a partition may stay idle before it is
+// evicted. Zero or negative restores DefaultPartitionTTL.
+func (s *StateStore) SetPartitionTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = DefaultPartitionTTL
+	}
+	s.partitionTTL.Store(int64(ttl))
+}
+
+// PartitionTTL returns the idle timeout for partitions.
+func (s *StateStore) PartitionTTL() time.Duration {
+	return time.Duration(s.partitionTTL.Load())
+}
+
+// getPartitioned returns a partition's copy of a base resource, creating the
+// partition and copying the table on first access. Returns nil if the base
+// workspace has no such resource.
+func (s *StateStore) getPartitioned(id, name string) *StatefulResource {
+	now := s.now()
+
+	s.mu.RLock()
+	resource := s.workspaces[id][name]
+	state := s.partitions[id]
+	s.mu.RUnlock()
+	if resource != nil && state != nil {
+		state.touch(now)
+		return resource
+	}
+
+	baseID, _ := SplitPartitionWorkspaceID(id)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweepPartitionsLocked(now)
+
+	state = s.partitions[id]
+	if state == nil {
+		state = &partitionState{created: now}
+		s.partitions[id] = state
+	}
+	state.touch(now)
+
+	ws := s.workspace(id)
+	if resource = ws[name]; resource != nil {
+		return resource
+	}
+	base := s.workspaces[baseID][name]
+	if base == nil {
+		return nil
+	}
+	resource = base.partitionCopy(id, s.changes)
+	ws[name] = resource
+	return resource
+}
+
+// Partitions returns the live partitions of a workspace, sorted by key.
+func (s *StateStore) Partitions(workspaceID string) []*PartitionInfo {
+	s.mu.Lock()
+	s.sweepPartitionsLocked(s.now())
+	s.mu.Unlock()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	infos := make([]*PartitionInfo, 0)
+	for id := range s.partitions {
+		if base, key := SplitPartitionWorkspaceID(id); base == workspaceID {
+			infos = append(infos, s.partitionInfoLocked(id, key))
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Key < infos[j].Key })
+	return infos
+}
+
+// Partition returns a single partition, or nil if it does not exist.
+func (s *StateStore) Partition(workspaceID, key string) *PartitionInfo {
+	id := PartitionWorkspaceID(workspaceID, key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.partitions[id] == nil {
+		return nil
+	}
+	return s.partitionInfoLocked(id, key)
+}
+
+// DropPartition discards a partition and all of its state. The next request
+// with the same key starts again from seed data. Returns false if the
+// partition does not exist.
+func (s *StateStore) DropPartition(workspaceID, key string) bool {
+	id := PartitionWorkspaceID(workspaceID, key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.partitions[id] == nil {
+		return false
+	}
+	s.dropPartitionLocked(id)
+	return true
+}
+
+// partitionInfoLocked builds a PartitionInfo. Must be called with s.mu held.
+func (s *StateStore) partitionInfoLocked(id, key string) *PartitionInfo {
+	state := s.partitions[id]
+	lastAccess := time.Unix(0, state.lastAccess.Load())
+	info := &PartitionInfo{
+		Key:        key,
+		Created:    state.created,
+		LastAccess: lastAccess,
+		ExpiresAt:  lastAccess.Add(s.PartitionTTL()),
+		Resources:  make(map[string]int, len(s.workspaces[id])),
+	}
+	for name, r := range s.workspaces[id] {
+		info.Resources[name] = r.Count()
+	}
+	return info
+}
+
+// sweepPartitionsLocked evicts partitions idle for longer than the TTL, at
+// most once per partitionSweepInterval. Must be called with s.mu held for writing.
+func (s *StateStore) sweepPartitionsLocked(now time.Time) {
+	if now.Sub(s.lastPartitionSweep) < partitionSweepInterval {
+		return
+	}
+	s.lastPartitionSweep = now
+	cutoff := now.Add(-s.PartitionTTL()).UnixNano()
+	for id, state := range s.partitions {
+		if state.lastAccess.Load() < cutoff {
+			s.dropPartitionLocked(id)
+		}
+	}
+}
+
+// dropPartitionLocked removes a partition. Must be called with s.mu held for writing.
+func (s *StateStore) dropPartitionLocked(id string) {
+	delete(s.partitions, id)
+	delete(s.workspaces, id)
+	s.idempotency.clearMatching(func(ws, _ string) bool { return ws == id })
+}
+
+// dropPartitionsOfLocked removes every partition of a base workspace.
+// Must be called with s.mu held for writing.
+func (s *StateStore) dropPartitionsOfLocked(workspaceID string) {
+	for id := range s.partitions {
+		if base, _ := SplitPartitionWorkspaceID(id); base == workspaceID {
+			s.dropPartitionLocked(id)
+		}
+	}
+}
+
+// partitionCopy returns a resource with r's configuration and seed rows but
+// none of its runtime changes, owned by the given partition workspace.
+func (r *StatefulResource) partitionCopy(workspaceID string, feed *ChangeFeed) *StatefulResource {
+	cfg := r.Config()
+	cfg.SeedGenerate = nil // generated rows are shared below, not regenerated
+
+	cp := NewStatefulResource(cfg)
+
+	r.mu.RLock()
+	cp.generatedSeed = r.generatedSeed
+	cp.seedGenerateCfg = r.seedGenerateCfg
+	cp.idempotency = r.idempotency
+	cp.idempotencyCfg = r.idempotencyCfg
+	cp.pagination = r.pagination
+	cp.paginationCfg = r.paginationCfg
+	r.mu.RUnlock()
+
+	// Recompile so cron bookkeeping is per partition; the rules were
+	// validated when the base resource was registered.
+	cp.lifecycle, _ = compileLifecycle(cfg.Lifecycle)
+	cp.workspaceID = workspaceID
+	cp.feed = feed
+
+	// Seed IDs were fixed when the base resource loaded, so this cannot fail.
+	_ = cp.loadSeed()
+	return cp
+}
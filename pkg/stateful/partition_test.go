@@ -0,0 +1,125 @@
+package stateful
+
+import (
+	"testing"
+	"time"
+
+	"github.com/getmockd/mockd/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartitionWorkspaceID(t *testing.T) {
+	assert.Equal(t, "ws", PartitionWorkspaceID("ws", ""))
+
+	ws, partition := SplitPartitionWorkspaceID(PartitionWorkspaceID("ws", "run-1"))
+	assert.Equal(t, "ws", ws)
+	assert.Equal(t, "run-1", partition)
+
+	ws, partition = SplitPartitionWorkspaceID("ws")
+	assert.Equal(t, "ws", ws)
+	assert.Empty(t, partition)
+}
+
+func TestPartition_CopyOnFirstAccess(t *testing.T) {
+	store := NewStateStore()
+	require.NoError(t, store.Register("", &ResourceConfig{
+		Name:         "users",
+		SeedData:     []map[string]interface{}{{"id": "u1", "name": "Alice"}},
+		SeedGenerate: &config.SeedGenerateConfig{Count: 2, Seed: 3, Fields: map[string]interface{}{"name": "{{faker.name}}"}},
+	}))
+	shared := store.Get("", "users")
+
+	runA := PartitionWorkspaceID("", "run-a")
+	runB := PartitionWorkspaceID("", "run-b")
+	usersA := store.Get(runA, "users")
+	require.NotNil(t, usersA)
+	assert.NotSame(t, shared, usersA)
+	assert.Same(t, usersA, store.Get(runA, "users"))
+	assert.Equal(t, shared.Count(), usersA.Count(), "partition starts from seed data")
+	assert.Nil(t, store.Get(runA, "orders"))
+
+	_, err := usersA.Create(map[string]interface{}{"name": "Bob"}, nil)
+	require.NoError(t, err)
+	_, err = usersA.Delete("u1")
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, shared.Count(), "shared table is untouched")
+	assert.Equal(t, 3, store.Get(runB, "users").Count(), "other partitions are untouched")
+	assert.NotNil(t, store.Get(runB, "users").Get("u1"))
+
+	// Generated rows are shared, not regenerated per partition.
+	for _, item := range shared.List(&QueryFilter{Limit: 10}).Data {
+		if item["id"] == "u1" {
+			continue
+		}
+		copied := store.Get(runB, "users").Get(item["id"].(string))
+		require.NotNil(t, copied)
+		assert.Equal(t, item["name"], copied.Data["name"])
+	}
+
+	assert.Equal(t, []string{"users"}, store.List(runA))
+
+	// Resetting a partition table restores seed data in that partition only.
+	_, err = store.Reset(runA, "users")
+	require.NoError(t, err)
+	assert.NotNil(t, usersA.Get("u1"))
+}
+
+func TestPartition_ListInspectAndDrop(t *testing.T) {
+	store := NewStateStore()
+	require.NoError(t, store.Register("", &ResourceConfig{Name: "users", SeedData: []map[string]interface{}{{"id": "u1"}}}))
+	require.NoError(t, store.Register("", &ResourceConfig{Name: "orders"}))
+
+	store.Get(PartitionWorkspaceID("", "run-b"), "users")
+	store.Get(PartitionWorkspaceID("", "run-a"), "orders")
+
+	partitions := store.Partitions("")
+	require.Len(t, partitions, 2)
+	assert.Equal(t, "run-a", partitions[0].Key)
+	assert.Equal(t, map[string]int{"orders": 0}, partitions[0].Resources)
+	assert.Equal(t, map[string]int{"users": 1}, partitions[1].Resources)
+	assert.Empty(t, store.Partitions("other"))
+
+	info := store.Partition("", "run-b")
+	require.NotNil(t, info)
+	assert.Equal(t, info.LastAccess.Add(DefaultPartitionTTL), info.ExpiresAt)
+	assert.Nil(t, store.Partition("", "missing"))
+
+	_, err := store.Get(PartitionWorkspaceID("", "run-b"), "users").Delete("u1")
+	require.NoError(t, err)
+	assert.True(t, store.DropPartition("", "run-b"))
+	assert.False(t, store.DropPartition("", "run-b"))
+	assert.Nil(t, store.Partition("", "run-b"))
+	assert.Equal(t, 1, store.Get(PartitionWorkspaceID("", "run-b"), "users").Count(), "a dropped partition restarts from seed data")
+
+	// Unregistering a table removes it from partitions; clearing the
+	// workspace drops them.
+	require.NoError(t, store.Unregister("", "users"))
+	assert.Nil(t, store.Get(PartitionWorkspaceID("", "run-b"), "users"))
+	store.Clear("")
+	assert.Empty(t, store.Partitions(""))
+}
+
+func TestPartition_TTLEviction(t *testing.T) {
+	store := NewStateStore()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	store.SetPartitionTTL(time.Minute)
+	require.NoError(t, store.Register("", &ResourceConfig{Name: "users"}))
+
+	idle := PartitionWorkspaceID("", "idle")
+	active := PartitionWorkspaceID("", "active")
+	_, err := store.Get(idle, "users").Create(map[string]interface{}{"name": "x"}, nil)
+	require.NoError(t, err)
+	store.Get(active, "users")
+
+	now = now.Add(45 * time.Second)
+	store.Get(active, "users")
+
+	now = now.Add(30 * time.Second)
+	partitions := store.Partitions("")
+	require.Len(t, partitions, 1)
+	assert.Equal(t, "active", partitions[0].Key)
+	assert.Equal(t, 0, store.Get(idle, "users").Count(), "an evicted partition restarts from seed data")
+}
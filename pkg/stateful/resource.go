@@ -37,10 +37,19 @@ type StatefulResource struct {
 	maxItems         int
 	items            map[string]*ResourceItem
 	seedData         []map[string]interface{}
+	generatedSeed    []map[string]interface{}   // rows from seedGenerate, loaded after seedData
+	seedGenerateCfg  *config.SeedGenerateConfig // source config, kept for Config()
 	validator        *validation.StatefulValidator
 	validationConfig *validation.StatefulValidation
 	responseCfg      *config.ResponseTransform
 	relationships    map[string]*RelationshipInfo // for ?expand[] support
+	lifecycle        []*lifecycleRule             // time-driven state transitions
+	idempotency      *IdempotencyPolicy           // Idempotency-Key handling, nil when disabled
+	idempotencyCfg   *config.IdempotencyConfig    // source config, kept for Config()
+	pagination       *PaginationPolicy            // HTTP list paging style, nil for the default
+	paginationCfg    *config.PaginationConfig     // source config, kept for Config()
+	workspaceID      string                       // owning workspace, set on Register
+	feed             *ChangeFeed                  // change notifications, set on Register
 }
 
 // NewStatefulResource creates a new StatefulResource from config.
@@ -179,8 +188,19 @@ func (r *StatefulResource) ResponseConfig() *config.ResponseTransform {
 	return r.responseCfg
 }
 
+// IdempotencyPolicy returns the resource's idempotency policy, or nil if disabled.
+func (r *StatefulResource) IdempotencyPolicy() *IdempotencyPolicy {
+	return r.idempotency
+}
+
+// PaginationPolicy returns the resource's HTTP list pagination policy, or nil
+// for the default limit/offset and cursor parameters.
+func (r *StatefulResource) PaginationPolicy() *PaginationPolicy {
+	return r.pagination
+}
+
 // loadSeed populates the resource with seed data on first initialization.
-// Unlike Reset, this also persists generated IDs back into seedData for deterministic resets,
+// Unlike Reset, this also persists generated IDs back into the seed rows for deterministic resets,
 // and returns an error on duplicate IDs.
 func (r *StatefulResource) loadSeed() error {
 	r.mu.Lock()
@@ -188,13 +208,13 @@ func (r *StatefulResource) loadSeed() error {
 
 	r.items = make(map[string]*ResourceItem)
 
-	for i, data := range r.seedData {
+	for i, data := range r.seedRows() {
 		item := FromJSON(data, r.idField)
 
 		if item.ID == "" {
 			item.ID = r.generateID()
 			// Persist generated ID so Reset() reuses it (deterministic across resets)
-			r.seedData[i][r.idField] = item.ID
+			data[r.idField] = item.ID
 		} else {
 			r.trackSequenceID(item.ID)
 		}
@@ -209,6 +229,16 @@ func (r *StatefulResource) loadSeed() error {
 	return nil
 }
 
+// seedRows returns the hand-written seed data followed by generated rows.
+func (r *StatefulResource) seedRows() []map[string]interface{} {
+	if len(r.generatedSeed) == 0 {
+		return r.seedData
+	}
+	rows := make([]map[string]interface{}, 0, len(r.seedData)+len(r.generatedSeed))
+	rows = append(rows, r.seedData...)
+	return append(rows, r.generatedSeed...)
+}
+
 // Create adds a new item to the resource.
 func (r *StatefulResource) Create(data map[string]interface{}, pathParams map[string]string) (*ResourceItem, error) {
 	r.mu.Lock()
@@ -244,6 +274,7 @@ func (r *StatefulResource) Create(data map[string]interface{}, pathParams map[st
 	item.UpdatedAt = now
 
 	r.items[item.ID] = item
+	r.publishLocked(ChangeCreated, item.ID, item, nil)
 	return item, nil
 }
 
@@ -322,6 +353,7 @@ func (r *StatefulResource) Update(id string, data map[string]interface{}) (*Reso
 	item.UpdatedAt = time.Now()
 
 	r.items[id] = item
+	r.publishLocked(ChangeUpdated, id, item, existing)
 	return item, nil
 }
 
@@ -358,6 +390,7 @@ func (r *StatefulResource) Patch(id string, data map[string]interface{}) (*Resou
 	}
 
 	r.items[id] = item
+	r.publishLocked(ChangeUpdated, id, item, existing)
 	return item, nil
 }
 
@@ -374,6 +407,7 @@ func (r *StatefulResource) Delete(id string) (*ResourceItem, error) {
 	}
 
 	delete(r.items, id)
+	r.publishLocked(ChangeDeleted, id, item, nil)
 	return item, nil
 }
 
@@ -387,7 +421,7 @@ func (r *StatefulResource) Reset() {
 		r.sequenceCounter = 0
 	}
 
-	for _, data := range r.seedData {
+	for _, data := range r.seedRows() {
 		item := FromJSON(data, r.idField)
 		if item.ID == "" {
 			item.ID = r.generateID()
@@ -396,6 +430,7 @@ func (r *StatefulResource) Reset() {
 		}
 		r.stampAndStore(item)
 	}
+	r.publishLocked(ChangeReset, "", nil, nil)
 }
 
 // trackSequenceID updates the sequence counter if this ID is a higher numeric value.
@@ -423,6 +458,7 @@ func (r *StatefulResource) Clear() int {
 
 	count := len(r.items)
 	r.items = make(map[string]*ResourceItem)
+	r.publishLocked(ChangeReset, "", nil, nil)
 	return count
 }
 
@@ -441,7 +477,7 @@ func (r *StatefulResource) Info() *ResourceInfo {
 	return &ResourceInfo{
 		Name:        r.name,
 		ItemCount:   len(r.items),
-		SeedCount:   len(r.seedData),
+		SeedCount:   len(r.seedData) + len(r.generatedSeed),
 		IDField:     r.idField,
 		ParentField: r.parentField,
 		MaxItems:    r.maxItems,
@@ -548,5 +584,11 @@ func (r *StatefulResource) Config() *ResourceConfig {
 			}
 		}
 	}
+	for _, rule := range r.lifecycle {
+		cfg.Lifecycle = append(cfg.Lifecycle, rule.cfg)
+	}
+	cfg.Idempotency = r.idempotencyCfg
+	cfg.Pagination = r.paginationCfg
+	cfg.SeedGenerate = r.seedGenerateCfg
 	return cfg
 }
@@ -0,0 +1,146 @@
+package stateful
+
+import (
+	"errors"
+	"fmt"
+	mathrand "math/rand/v2"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/getmockd/mockd/pkg/config"
+	"github.com/getmockd/mockd/pkg/template"
+)
+
+// singleExpressionPattern matches a template that is exactly one {{expression}}.
+var singleExpressionPattern = regexp.MustCompile(`^\{\{[^}]+\}\}$`)
+
+// generateSeedRows materialises a seedGenerate block into seed rows.
+// lookup resolves related tables in the same workspace; it returns nil for
+// unknown tables.
+func generateSeedRows(cfg *config.SeedGenerateConfig, lookup func(table string) *StatefulResource) ([]map[string]interface{}, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if cfg.Count <= 0 {
+		return nil, errors.New("count must be greater than 0")
+	}
+
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := mathrand.New(mathrand.NewPCG(uint64(seed), 0))
+
+	// Resolve parent values up front, in a stable order, so that the same
+	// seed always picks the same parents.
+	relFields := sortedKeys(cfg.Relationships)
+	parents := make(map[string][]interface{}, len(relFields))
+	for _, field := range relFields {
+		rel := cfg.Relationships[field]
+		if rel == nil || rel.Table == "" {
+			return nil, fmt.Errorf("relationship %q: table is required", field)
+		}
+		target := lookup(rel.Table)
+		if target == nil {
+			return nil, fmt.Errorf("relationship %q: table %q is not registered (declare it before this table)", field, rel.Table)
+		}
+		values := target.fieldValues(rel.Field)
+		if len(values) == 0 {
+			return nil, fmt.Errorf("relationship %q: table %q has no items to reference", field, rel.Table)
+		}
+		parents[field] = values
+	}
+
+	// Each generation gets its own sequences so sequence("n") restarts.
+	engine := template.New()
+	ctx := &template.Context{Rand: rng}
+	fields := sortedKeys(cfg.Fields)
+
+	rows := make([]map[string]interface{}, 0, cfg.Count)
+	for i := 0; i < cfg.Count; i++ {
+		row := make(map[string]interface{}, len(fields)+len(relFields))
+		for _, field := range fields {
+			value, err := generateSeedValue(engine, ctx, cfg.Fields[field])
+			if err != nil {
+				return nil, fmt.Errorf("field %q: %w", field, err)
+			}
+			row[field] = value
+		}
+		for _, field := range relFields {
+			values := parents[field]
+			row[field] = values[rng.IntN(len(values))]
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// generateSeedValue renders one field value. Strings are templates; a
+// template that is a single expression yielding a number or boolean is
+// stored with that type.
+func generateSeedValue(engine *template.Engine, ctx *template.Context, value interface{}) (interface{}, error) {
+	tmpl, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+	out, err := engine.Process(tmpl, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if singleExpressionPattern.MatchString(tmpl) {
+		return coerceGeneratedValue(out), nil
+	}
+	return out, nil
+}
+
+// coerceGeneratedValue converts canonical integer, float and boolean strings
+// to their JSON types. Values such as "007" stay strings.
+func coerceGeneratedValue(s string) interface{} {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil && strconv.FormatInt(n, 10) == s {
+		return n
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && strconv.FormatFloat(f, 'f', -1, 64) == s {
+		return f
+	}
+	if b, err := strconv.ParseBool(s); err == nil && strconv.FormatBool(b) == s {
+		return b
+	}
+	return s
+}
+
+// fieldValues returns the value of field for every item, ordered by item ID.
+// An empty field (or the ID field) returns the item IDs.
+func (r *StatefulResource) fieldValues(field string) []interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := make([]string, 0, len(r.items))
+	for id := range r.items {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	values := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		if field == "" || field == r.idField {
+			values = append(values, id)
+			continue
+		}
+		if v, ok := r.items[id].Data[field]; ok {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,134 @@
+package stateful
+
+import (
+	"testing"
+
+	"github.com/getmockd/mockd/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeedGenerate_RowsAndTypes(t *testing.T) {
+	store := NewStateStore()
+	require.NoError(t, store.Register("", &ResourceConfig{
+		Name:       "users",
+		IDStrategy: IDStrategySequence,
+		SeedData:   []map[string]interface{}{{"name": "Admin"}},
+		SeedGenerate: &config.SeedGenerateConfig{
+			Count: 50,
+			Seed:  7,
+			Fields: map[string]interface{}{
+				"name":    "{{faker.name}}",
+				"email":   "{{faker.email}}",
+				"account": `ACC-{{sequence("acct", 100)}}`,
+				"age":     "{{random.int(18, 90)}}",
+				"active":  true,
+			},
+		},
+	}))
+
+	users := store.Get("", "users")
+	require.Equal(t, 51, users.Count(), "hand-written seed data plus generated rows")
+	assert.Equal(t, 51, users.Info().SeedCount)
+	assert.Equal(t, "Admin", users.Get("1").Data["name"])
+
+	second := users.Get("2").Data
+	assert.NotEmpty(t, second["name"])
+	assert.Contains(t, second["email"], "@")
+	assert.Equal(t, "ACC-100", second["account"])
+	assert.IsType(t, int64(0), second["age"])
+	assert.Equal(t, true, second["active"])
+	assert.Equal(t, "ACC-149", users.Get("51").Data["account"])
+}
+
+func TestSeedGenerate_DeterministicWithSeed(t *testing.T) {
+	generate := func() []map[string]interface{} {
+		rows, err := generateSeedRows(&config.SeedGenerateConfig{
+			Count:  20,
+			Seed:   42,
+			Fields: map[string]interface{}{"id": "{{uuid}}", "city": "{{faker.city}}", "score": "{{random.float(0, 1, 2)}}"},
+		}, func(string) *StatefulResource { return nil })
+		require.NoError(t, err)
+		return rows
+	}
+	assert.Equal(t, generate(), generate())
+}
+
+func TestSeedGenerate_Relationships(t *testing.T) {
+	store := NewStateStore()
+	require.NoError(t, store.Register("", &ResourceConfig{
+		Name:     "customers",
+		SeedData: []map[string]interface{}{{"id": "c1", "code": "A"}, {"id": "c2", "code": "B"}},
+	}))
+	require.NoError(t, store.Register("", &ResourceConfig{
+		Name: "orders",
+		SeedGenerate: &config.SeedGenerateConfig{
+			Count: 100,
+			Seed:  1,
+			Relationships: map[string]*config.Relationship{
+				"customerId":   {Table: "customers"},
+				"customerCode": {Table: "customers", Field: "code"},
+			},
+		},
+	}))
+
+	seen := map[interface{}]bool{}
+	for _, item := range store.Get("", "orders").List(&QueryFilter{Limit: 100}).Data {
+		assert.Contains(t, []interface{}{"c1", "c2"}, item["customerId"])
+		assert.Contains(t, []interface{}{"A", "B"}, item["customerCode"])
+		seen[item["customerId"]] = true
+	}
+	assert.Len(t, seen, 2, "parents are picked at random")
+
+	err := store.Register("", &ResourceConfig{
+		Name: "invoices",
+		SeedGenerate: &config.SeedGenerateConfig{
+			Count:         1,
+			Relationships: map[string]*config.Relationship{"accountId": {Table: "accounts"}},
+		},
+	})
+	require.ErrorContains(t, err, `table "accounts" is not registered`)
+}
+
+func TestSeedGenerate_ResetRestoresGeneratedRows(t *testing.T) {
+	store := NewStateStore()
+	require.NoError(t, store.Register("", &ResourceConfig{
+		Name:         "products",
+		SeedGenerate: &config.SeedGenerateConfig{Count: 10, Fields: map[string]interface{}{"sku": "{{random.string(8)}}"}},
+	}))
+	products := store.Get("", "products")
+	before := products.List(&QueryFilter{Limit: 100, Sort: "id"}).Data
+
+	_, err := products.Delete(before[0]["id"].(string))
+	require.NoError(t, err)
+	_, err = products.Create(map[string]interface{}{"sku": "extra"}, nil)
+	require.NoError(t, err)
+
+	_, err = store.Reset("", "products")
+	require.NoError(t, err)
+	after := products.List(&QueryFilter{Limit: 100, Sort: "id"}).Data
+	require.Len(t, after, 10)
+	for i := range before {
+		assert.Equal(t, before[i]["id"], after[i]["id"])
+		assert.Equal(t, before[i]["sku"], after[i]["sku"])
+	}
+
+	// The generator config, not the rows, is exported.
+	cfg := products.Config()
+	assert.Empty(t, cfg.SeedData)
+	assert.Equal(t, 10, cfg.SeedGenerate.Count)
+}
+
+func TestSeedGenerate_InvalidCount(t *testing.T) {
+	store := NewStateStore()
+	err := store.Register("", &ResourceConfig{Name: "empty", SeedGenerate: &config.SeedGenerateConfig{}})
+	require.ErrorContains(t, err, "count must be greater than 0")
+}
+
+func TestCoerceGeneratedValue(t *testing.T) {
+	assert.Equal(t, int64(42), coerceGeneratedValue("42"))
+	assert.Equal(t, 3.25, coerceGeneratedValue("3.25"))
+	assert.Equal(t, false, coerceGeneratedValue("false"))
+	assert.Equal(t, "007", coerceGeneratedValue("007"))
+	assert.Equal(t, "Alice", coerceGeneratedValue("Alice"))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -17,14 +18,32 @@ type StateStore struct {
 	mu         sync.RWMutex
 	workspaces map[string]map[string]*StatefulResource // workspaceID → name → resource
 	observer   Observer
+	changes    *ChangeFeed
+
+	// Responses remembered by idempotency key. Keys are forgotten when the
+	// table they were used against is reset, cleared or dropped.
+	idempotency *IdempotencyCache
+
+	// Isolation partitions: partition workspace ID → bookkeeping.
+	// Their tables live in workspaces under the same ID.
+	partitions         map[string]*partitionState
+	partitionTTL       atomic.Int64 // time.Duration
+	lastPartitionSweep time.Time
+	now                func() time.Time
 }
 
 // NewStateStore creates a new StateStore.
 func NewStateStore() *StateStore {
-	return &StateStore{
-		workspaces: make(map[string]map[string]*StatefulResource),
-		observer:   &NoopObserver{},
+	s := &StateStore{
+		workspaces:  make(map[string]map[string]*StatefulResource),
+		observer:    &NoopObserver{},
+		changes:     NewChangeFeed(),
+		idempotency: NewIdempotencyCache(),
+		partitions:  make(map[string]*partitionState),
+		now:         time.Now,
 	}
+	s.partitionTTL.Store(int64(DefaultPartitionTTL))
+	return s
 }
 
 // SetObserver sets the observer for metrics/logging hooks.
@@ -46,6 +65,16 @@ func (s *StateStore) GetObserver() Observer {
 	return s.observer
 }
 
+// Changes returns the feed on which every resource mutation is published.
+func (s *StateStore) Changes() *ChangeFeed {
+	return s.changes
+}
+
+// Idempotency returns the cache of responses stored by idempotency key.
+func (s *StateStore) Idempotency() *IdempotencyCache {
+	return s.idempotency
+}
+
 // workspace returns the resource map for a workspace, creating it if needed.
 // Must be called with s.mu held for writing.
 func (s *StateStore) workspace(workspaceID string) map[string]*StatefulResource {
@@ -81,7 +110,38 @@ func (s *StateStore) Register(workspaceID string, config *ResourceConfig) error
 		return fmt.Errorf("resource %q already registered", config.Name)
 	}
 
+	lifecycle, err := compileLifecycle(config.Lifecycle)
+	if err != nil {
+		return fmt.Errorf("invalid lifecycle for %q: %w", config.Name, err)
+	}
+
+	idempotency, err := NewIdempotencyPolicy(config.Idempotency)
+	if err != nil {
+		return fmt.Errorf("invalid idempotency for %q: %w", config.Name, err)
+	}
+
+	pagination, err := NewPaginationPolicy(config.Pagination)
+	if err != nil {
+		return fmt.Errorf("invalid pagination for %q: %w", config.Name, err)
+	}
+
+	generated, err := generateSeedRows(config.SeedGenerate, func(table string) *StatefulResource {
+		return ws[table]
+	})
+	if err != nil {
+		return fmt.Errorf("invalid seedGenerate for %q: %w", config.Name, err)
+	}
+
 	resource := NewStatefulResource(config)
+	resource.lifecycle = lifecycle
+	resource.idempotency = idempotency
+	resource.idempotencyCfg = config.Idempotency
+	resource.pagination = pagination
+	resource.paginationCfg = config.Pagination
+	resource.generatedSeed = generated
+	resource.seedGenerateCfg = config.SeedGenerate
+	resource.workspaceID = workspaceID
+	resource.feed = s.changes
 
 	// Load seed data if provided
 	if err := resource.loadSeed(); err != nil {
@@ -93,7 +153,12 @@ func (s *StateStore) Register(workspaceID string, config *ResourceConfig) error
 }
 
 // Get returns a stateful resource by name from the given workspace.
+// For a partition workspace ID (see PartitionWorkspaceID) the partition's
+// copy of the table is returned, created from seed data on first access.
 func (s *StateStore) Get(workspaceID string, name string) *StatefulResource {
+	if _, partition := SplitPartitionWorkspaceID(workspaceID); partition != "" {
+		return s.getPartitioned(workspaceID, name)
+	}
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	ws := s.workspaceRO(workspaceID)
@@ -104,7 +169,9 @@ func (s *StateStore) Get(workspaceID string, name string) *StatefulResource {
 }
 
 // List returns all resource names in the given workspace in sorted order.
+// A partition lists the tables of its base workspace.
 func (s *StateStore) List(workspaceID string) []string {
+	workspaceID, _ = SplitPartitionWorkspaceID(workspaceID)
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -129,6 +196,11 @@ func (s *StateStore) List(workspaceID string) []string {
 func (s *StateStore) Reset(workspaceID string, resourceName string) (*ResetResponse, error) {
 	start := time.Now()
 
+	// A partition copies a table on first access; make sure it exists.
+	if _, partition := SplitPartitionWorkspaceID(workspaceID); partition != "" && resourceName != "" {
+		s.Get(workspaceID, resourceName)
+	}
+
 	// Snapshot the target resources under a read lock.
 	s.mu.RLock()
 	type target struct {
@@ -170,6 +242,11 @@ func (s *StateStore) Reset(workspaceID string, resourceName string) (*ResetRespo
 	}
 	sort.Strings(resetNames) // deterministic ordering
 
+	// Keys used against the old state must not replay stale responses.
+	s.idempotency.clearMatching(func(ws, table string) bool {
+		return ws == workspaceID && (resourceName == "" || table == resourceName)
+	})
+
 	observer.OnReset(resetNames, time.Since(start))
 
 	return &ResetResponse{
@@ -179,12 +256,34 @@ func (s *StateStore) Reset(workspaceID string, resourceName string) (*ResetRespo
 	}, nil
 }
 
-// Clear removes all resources from the given workspace.
-// If workspaceID is empty string, clears the default workspace.
+// lifecycleResources returns every resource, across all workspaces, that has
+// lifecycle transitions configured.
+func (s *StateStore) lifecycleResources() []*StatefulResource {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var resources []*StatefulResource
+	for _, ws := range s.workspaces {
+		for _, r := range ws {
+			if r.HasLifecycle() {
+				resources = append(resources, r)
+			}
+		}
+	}
+	return resources
+}
+
+// Clear removes all resources from the given workspace, including its
+// isolation partitions. If workspaceID is empty string, clears the default workspace.
 func (s *StateStore) Clear(workspaceID string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	delete(s.workspaces, workspaceID)
+	s.dropPartitionsOfLocked(workspaceID)
+	s.idempotency.clearMatching(func(ws, _ string) bool {
+		base, _ := SplitPartitionWorkspaceID(ws)
+		return base == workspaceID
+	})
 }
 
 // ClearAll removes all resources from all workspaces.
@@ -192,6 +291,8 @@ func (s *StateStore) ClearAll() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.workspaces = make(map[string]map[string]*StatefulResource)
+	s.partitions = make(map[string]*partitionState)
+	s.idempotency.Clear()
 }
 
 // Overview returns information about all registered stateful resources in a workspace.
@@ -277,7 +378,11 @@ func (s *StateStore) ClearResource(workspaceID string, name string) (int, error)
 		return 0, fmt.Errorf("resource %q not found", name)
 	}
 
-	return resource.Clear(), nil
+	n := resource.Clear()
+	s.idempotency.clearMatching(func(ws, table string) bool {
+		return ws == workspaceID && table == name
+	})
+	return n, nil
 }
 
 // Unregister removes a stateful resource definition from the store entirely.
@@ -293,5 +398,14 @@ func (s *StateStore) Unregister(workspaceID string, name string) error {
 		return fmt.Errorf("resource %q not found", name)
 	}
 	delete(ws, name)
+	for id := range s.partitions {
+		if base, _ := SplitPartitionWorkspaceID(id); base == workspaceID {
+			delete(s.workspaces[id], name)
+		}
+	}
+	s.idempotency.clearMatching(func(ws, table string) bool {
+		base, _ := SplitPartitionWorkspaceID(ws)
+		return base == workspaceID && table == name
+	})
 	return nil
 }
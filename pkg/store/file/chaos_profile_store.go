@@ -0,0 +1,98 @@
+package file
+
+import (
+	"context"
+
+	"github.com/getmockd/mockd/pkg/config"
+	"github.com/getmockd/mockd/pkg/store"
+)
+
+// chaosProfileStore implements store.ChaosProfileStore for file-based storage.
+type chaosProfileStore struct {
+	fs *FileStore
+}
+
+// List returns all persisted chaos profiles.
+func (s *chaosProfileStore) List(ctx context.Context) ([]*config.ChaosProfileConfig, error) {
+	s.fs.mu.RLock()
+	defer s.fs.mu.RUnlock()
+
+	if s.fs.data.ChaosProfiles == nil {
+		return []*config.ChaosProfileConfig{}, nil
+	}
+
+	result := make([]*config.ChaosProfileConfig, len(s.fs.data.ChaosProfiles))
+	copy(result, s.fs.data.ChaosProfiles)
+	return result, nil
+}
+
+// Get returns the chaos profile with the given name.
+func (s *chaosProfileStore) Get(ctx context.Context, name string) (*config.ChaosProfileConfig, error) {
+	s.fs.mu.RLock()
+	defer s.fs.mu.RUnlock()
+
+	for _, p := range s.fs.data.ChaosProfiles {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return nil, store.ErrNotFound
+}
+
+// Create persists a new chaos profile.
+func (s *chaosProfileStore) Create(ctx context.Context, profile *config.ChaosProfileConfig) error {
+	s.fs.mu.Lock()
+	defer s.fs.mu.Unlock()
+
+	if s.fs.cfg.ReadOnly {
+		return store.ErrReadOnly
+	}
+
+	for _, existing := range s.fs.data.ChaosProfiles {
+		if existing.Name == profile.Name {
+			return store.ErrAlreadyExists
+		}
+	}
+
+	s.fs.data.ChaosProfiles = append(s.fs.data.ChaosProfiles, profile)
+	s.fs.markDirty()
+	return nil
+}
+
+// Update replaces an existing chaos profile with the same name.
+func (s *chaosProfileStore) Update(ctx context.Context, profile *config.ChaosProfileConfig) error {
+	s.fs.mu.Lock()
+	defer s.fs.mu.Unlock()
+
+	if s.fs.cfg.ReadOnly {
+		return store.ErrReadOnly
+	}
+
+	for i, existing := range s.fs.data.ChaosProfiles {
+		if existing.Name == profile.Name {
+			s.fs.data.ChaosProfiles[i] = profile
+			s.fs.markDirty()
+			return nil
+		}
+	}
+	return store.ErrNotFound
+}
+
+// Delete removes a chaos profile by name.
+func (s *chaosProfileStore) Delete(ctx context.Context, name string) error {
+	s.fs.mu.Lock()
+	defer s.fs.mu.Unlock()
+
+	if s.fs.cfg.ReadOnly {
+		return store.ErrReadOnly
+	}
+
+	for i, p := range s.fs.data.ChaosProfiles {
+		if p.Name == name {
+			s.fs.data.ChaosProfiles = append(s.fs.data.ChaosProfiles[:i], s.fs.data.ChaosProfiles[i+1:]...)
+			s.fs.markDirty()
+			return nil
+		}
+	}
+	return store.ErrNotFound
+}
@@ -0,0 +1,127 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/getmockd/mockd/pkg/chaos"
+	"github.com/getmockd/mockd/pkg/config"
+	"github.com/getmockd/mockd/pkg/store"
+)
+
+func partnerDegradedProfile() *config.ChaosProfileConfig {
+	return &config.ChaosProfileConfig{
+		Name:        "partner-degraded",
+		Description: "Partner API at its SLO limits",
+		Config: chaos.ChaosConfig{
+			Enabled: true,
+			GlobalRules: &chaos.GlobalChaosRules{
+				Latency:   &chaos.LatencyFault{Min: "300ms", Max: "1200ms", Probability: 1},
+				ErrorRate: &chaos.ErrorRateFault{Probability: 0.05, StatusCodes: []int{502, 503}},
+			},
+		},
+	}
+}
+
+func TestChaosProfileStore_CRUD(t *testing.T) {
+	fs := newTestStore(t)
+	ctx := context.Background()
+	cps := fs.ChaosProfiles()
+
+	if err := cps.Create(ctx, partnerDegradedProfile()); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	if err := cps.Create(ctx, partnerDegradedProfile()); !errors.Is(err, store.ErrAlreadyExists) {
+		t.Errorf("duplicate Create() error = %v, want ErrAlreadyExists", err)
+	}
+
+	got, err := cps.Get(ctx, "partner-degraded")
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if got.Config.GlobalRules.Latency.Max != "1200ms" {
+		t.Errorf("latency max = %q, want 1200ms", got.Config.GlobalRules.Latency.Max)
+	}
+
+	updated := partnerDegradedProfile()
+	updated.Description = "Partner API down"
+	updated.Config.GlobalRules = &chaos.GlobalChaosRules{ErrorRate: &chaos.ErrorRateFault{Probability: 1}}
+	if err := cps.Update(ctx, updated); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+	got, _ = cps.Get(ctx, "partner-degraded")
+	if got.Description != "Partner API down" || got.Config.GlobalRules.Latency != nil {
+		t.Errorf("Update() not applied: %+v", got)
+	}
+	if err := cps.Update(ctx, &config.ChaosProfileConfig{Name: "missing"}); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Update() of missing profile error = %v, want ErrNotFound", err)
+	}
+
+	list, _ := cps.List(ctx)
+	if len(list) != 1 {
+		t.Fatalf("List() returned %d profiles, want 1", len(list))
+	}
+
+	if err := cps.Delete(ctx, "partner-degraded"); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if _, err := cps.Get(ctx, "partner-degraded"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Get() after delete error = %v, want ErrNotFound", err)
+	}
+	if err := cps.Delete(ctx, "partner-degraded"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("second Delete() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestChaosProfileStore_ReadOnly(t *testing.T) {
+	fs := newReadOnlyStore(t)
+	ctx := context.Background()
+	cps := fs.ChaosProfiles()
+
+	if err := cps.Create(ctx, partnerDegradedProfile()); !errors.Is(err, store.ErrReadOnly) {
+		t.Errorf("Create: expected ErrReadOnly, got %v", err)
+	}
+	if err := cps.Update(ctx, partnerDegradedProfile()); !errors.Is(err, store.ErrReadOnly) {
+		t.Errorf("Update: expected ErrReadOnly, got %v", err)
+	}
+	if err := cps.Delete(ctx, "partner-degraded"); !errors.Is(err, store.ErrReadOnly) {
+		t.Errorf("Delete: expected ErrReadOnly, got %v", err)
+	}
+}
+
+func TestChaosProfileStore_Persistence(t *testing.T) {
+	dir := t.TempDir()
+	cfg := store.Config{
+		DataDir:   dir,
+		ConfigDir: filepath.Join(dir, "config"),
+		CacheDir:  filepath.Join(dir, "cache"),
+		StateDir:  filepath.Join(dir, "state"),
+	}
+	ctx := context.Background()
+
+	fs1 := New(cfg)
+	if err := fs1.Open(ctx); err != nil {
+		t.Fatalf("Session 1 Open() failed: %v", err)
+	}
+	_ = fs1.ChaosProfiles().Create(ctx, partnerDegradedProfile())
+	if err := fs1.Close(); err != nil {
+		t.Fatalf("Session 1 Close() failed: %v", err)
+	}
+
+	fs2 := New(cfg)
+	if err := fs2.Open(ctx); err != nil {
+		t.Fatalf("Session 2 Open() failed: %v", err)
+	}
+	defer func() { _ = fs2.Close() }()
+
+	got, err := fs2.ChaosProfiles().Get(ctx, "partner-degraded")
+	if err != nil {
+		t.Fatalf("Get() after reload failed: %v", err)
+	}
+	if got.Config.GlobalRules == nil || got.Config.GlobalRules.ErrorRate == nil ||
+		len(got.Config.GlobalRules.ErrorRate.StatusCodes) != 2 {
+		t.Errorf("chaos profile config not persisted correctly: %+v", got.Config)
+	}
+}
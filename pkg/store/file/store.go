@@ -53,6 +53,9 @@ type storeData struct {
 	// Custom operation definitions (persisted across restarts)
 	CustomOperations []*config.CustomOperationConfig `json:"customOperations,omitempty"`
 
+	// User-defined chaos profiles (persisted across restarts)
+	ChaosProfiles []*config.ChaosProfileConfig `json:"chaosProfiles,omitempty"`
+
 	Folders     []*config.Folder         `json:"folders,omitempty"`
 	Recordings  []*store.Recording       `json:"recordings,omitempty"`
 	RequestLog  []*store.RequestLogEntry `json:"requestLog,omitempty"`
@@ -285,6 +288,11 @@ func (s *FileStore) CustomOperations() store.CustomOperationStore {
 	return &customOperationStore{fs: s}
 }
 
+// ChaosProfiles returns the chaos profile store.
+func (s *FileStore) ChaosProfiles() store.ChaosProfileStore {
+	return &chaosProfileStore{fs: s}
+}
+
 // Folders returns the folder store.
 func (s *FileStore) Folders() store.FolderStore {
 	return &folderStore{fs: s}
@@ -172,6 +172,21 @@ type CustomOperationStore interface {
 	DeleteAll(ctx context.Context, workspaceID string) error
 }
 
+// ChaosProfileStore handles persistence for user-defined chaos profiles.
+// Profiles are global: their name is their identity.
+type ChaosProfileStore interface {
+	// List returns all persisted chaos profiles.
+	List(ctx context.Context) ([]*config.ChaosProfileConfig, error)
+	// Get returns the chaos profile with the given name.
+	Get(ctx context.Context, name string) (*config.ChaosProfileConfig, error)
+	// Create persists a new chaos profile.
+	Create(ctx context.Context, profile *config.ChaosProfileConfig) error
+	// Update replaces an existing chaos profile with the same name.
+	Update(ctx context.Context, profile *config.ChaosProfileConfig) error
+	// Delete removes a chaos profile by name.
+	Delete(ctx context.Context, name string) error
+}
+
 // FolderFilter provides filtering criteria for folder list operations.
 type FolderFilter struct {
 	WorkspaceID *string // Filter by workspace (nil = no filter, "" = default workspace)
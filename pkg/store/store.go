@@ -187,6 +187,7 @@ type Store interface {
 	Preferences() PreferencesStore
 	StatefulResources() StatefulResourceStore
 	CustomOperations() CustomOperationStore
+	ChaosProfiles() ChaosProfileStore
 
 	// Transactions (for backends that support it)
 	Begin(ctx context.Context) (Transaction, error)
@@ -0,0 +1,104 @@
+package tcpproxy
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Manager owns a set of named proxies.
+type Manager struct {
+	mu      sync.Mutex
+	proxies map[string]*Proxy
+}
+
+// NewManager returns an empty manager.
+func NewManager() *Manager {
+	return &Manager{proxies: make(map[string]*Proxy)}
+}
+
+// Create starts a proxy from cfg.
+func (m *Manager) Create(cfg Config) (*Proxy, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.proxies[cfg.Name]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrProxyExists, cfg.Name)
+	}
+	p, err := Start(cfg)
+	if err != nil {
+		return nil, err
+	}
+	m.proxies[cfg.Name] = p
+	return p, nil
+}
+
+// Get returns the named proxy.
+func (m *Manager) Get(name string) (*Proxy, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.proxies[name]
+	return p, ok
+}
+
+// List returns the status of every proxy, sorted by name.
+func (m *Manager) List() []Status {
+	m.mu.Lock()
+	proxies := make([]*Proxy, 0, len(m.proxies))
+	for _, p := range m.proxies {
+		proxies = append(proxies, p)
+	}
+	m.mu.Unlock()
+
+	out := make([]Status, len(proxies))
+	for i, p := range proxies {
+		out[i] = p.Status()
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Delete stops and removes the named proxy.
+func (m *Manager) Delete(name string) error {
+	m.mu.Lock()
+	p, ok := m.proxies[name]
+	delete(m.proxies, name)
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrProxyNotFound, name)
+	}
+	return p.Close()
+}
+
+// Stats returns every proxy's stats by name, or nil when there are none.
+func (m *Manager) Stats() map[string]Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.proxies) == 0 {
+		return nil
+	}
+	out := make(map[string]Stats, len(m.proxies))
+	for name, p := range m.proxies {
+		out[name] = p.Stats()
+	}
+	return out
+}
+
+// ResetStats resets every proxy's stats.
+func (m *Manager) ResetStats() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, p := range m.proxies {
+		p.ResetStats()
+	}
+}
+
+// Close stops every proxy.
+func (m *Manager) Close() {
+	m.mu.Lock()
+	proxies := m.proxies
+	m.proxies = make(map[string]*Proxy)
+	m.mu.Unlock()
+	for _, p := range proxies {
+		_ = p.Close()
+	}
+}
@@ -0,0 +1,552 @@
+package tcpproxy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// dialTimeout bounds how long a proxy waits to connect to its upstream.
+const dialTimeout = 10 * time.Second
+
+// bufferSize is the size of the reads the proxy forwards.
+const bufferSize = 32 * 1024
+
+// errConnClosed is returned by sleeps interrupted by the connection closing.
+var errConnClosed = errors.New("connection closed")
+
+// Config configures a proxy.
+type Config struct {
+	// Name identifies the proxy.
+	Name string `json:"name" yaml:"name"`
+	// Listen is the address to accept connections on, e.g. "127.0.0.1:15432".
+	// Port 0 picks a free port.
+	Listen string `json:"listen" yaml:"listen"`
+	// Upstream is the address connections are forwarded to.
+	Upstream string `json:"upstream" yaml:"upstream"`
+	// Toxics degrade the proxied connections.
+	Toxics []Toxic `json:"toxics,omitempty" yaml:"toxics,omitempty"`
+}
+
+// Stats are a proxy's counters since it started or its stats were reset.
+type Stats struct {
+	Connections       int64 `json:"connections"`
+	ActiveConnections int64 `json:"activeConnections"`
+	UpstreamErrors    int64 `json:"upstreamErrors"`
+	BytesUpstream     int64 `json:"bytesUpstream"`
+	BytesDownstream   int64 `json:"bytesDownstream"`
+	// Toxics counts, per toxic name, the connections the toxic applied to.
+	Toxics map[string]int64 `json:"toxics,omitempty"`
+}
+
+// Status describes a running proxy.
+type Status struct {
+	Name string `json:"name"`
+	// Listen is the address the proxy actually listens on.
+	Listen   string  `json:"listen"`
+	Upstream string  `json:"upstream"`
+	Toxics   []Toxic `json:"toxics"`
+	Stats    Stats   `json:"stats"`
+}
+
+// Proxy accepts TCP connections and forwards them to an upstream, applying
+// its toxics. Toxic changes apply to open connections immediately.
+type Proxy struct {
+	name     string
+	upstream string
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu     sync.RWMutex
+	toxics []*compiledToxic
+	conns  map[*proxyConn]struct{}
+	closed bool
+
+	connections    atomic.Int64
+	active         atomic.Int64
+	upstreamErrors atomic.Int64
+	bytesUpstream  atomic.Int64
+	bytesDown      atomic.Int64
+
+	statsMu     sync.Mutex
+	activations map[string]int64
+}
+
+// Start validates cfg, listens on cfg.Listen and starts forwarding.
+func Start(cfg Config) (*Proxy, error) {
+	if !nameRe.MatchString(cfg.Name) {
+		return nil, fmt.Errorf("invalid proxy name %q: use letters, digits, '.', '_' and '-'", cfg.Name)
+	}
+	if cfg.Listen == "" {
+		return nil, errors.New("listen address is required")
+	}
+	if cfg.Upstream == "" {
+		return nil, errors.New("upstream address is required")
+	}
+	if _, _, err := net.SplitHostPort(cfg.Upstream); err != nil {
+		return nil, fmt.Errorf("invalid upstream address %q: %w", cfg.Upstream, err)
+	}
+	toxics, err := compileToxics(cfg.Toxics)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		return nil, fmt.Errorf("listen on %s: %w", cfg.Listen, err)
+	}
+
+	p := &Proxy{
+		name:        cfg.Name,
+		upstream:    cfg.Upstream,
+		listener:    listener,
+		toxics:      toxics,
+		conns:       make(map[*proxyConn]struct{}),
+		activations: make(map[string]int64),
+	}
+	p.wg.Add(1)
+	go p.acceptLoop()
+	return p, nil
+}
+
+// Name returns the proxy's name.
+func (p *Proxy) Name() string { return p.name }
+
+// Addr returns the address the proxy listens on.
+func (p *Proxy) Addr() string { return p.listener.Addr().String() }
+
+// Close stops accepting connections, closes the open ones and waits for
+// them to finish.
+func (p *Proxy) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	err := p.listener.Close()
+	for c := range p.conns {
+		c.close()
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+	return err
+}
+
+// Toxics returns the proxy's toxics with their defaults filled in.
+func (p *Proxy) Toxics() []Toxic {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return toxicList(p.toxics)
+}
+
+// SetToxics replaces every toxic of the proxy.
+func (p *Proxy) SetToxics(toxics []Toxic) error {
+	compiled, err := compileToxics(toxics)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.setToxicsLocked(compiled)
+	return nil
+}
+
+// AddToxic adds a toxic to the proxy and returns it with its defaults
+// filled in.
+func (p *Proxy) AddToxic(toxic Toxic) (Toxic, error) {
+	compiled, err := compileToxic(toxic)
+	if err != nil {
+		return Toxic{}, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, t := range p.toxics {
+		if t.Name == compiled.Name {
+			return Toxic{}, fmt.Errorf("%w: %s", ErrToxicExists, compiled.Name)
+		}
+	}
+	toxics := append(append([]*compiledToxic(nil), p.toxics...), compiled)
+	p.setToxicsLocked(toxics)
+	return compiled.Toxic, nil
+}
+
+// RemoveToxic removes the named toxic from the proxy.
+func (p *Proxy) RemoveToxic(name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	toxics := make([]*compiledToxic, 0, len(p.toxics))
+	for _, t := range p.toxics {
+		if t.Name != name {
+			toxics = append(toxics, t)
+		}
+	}
+	if len(toxics) == len(p.toxics) {
+		return fmt.Errorf("%w: %s", ErrToxicNotFound, name)
+	}
+	p.setToxicsLocked(toxics)
+	return nil
+}
+
+// setToxicsLocked installs toxics and re-arms the open connections' timers.
+// The caller holds p.mu.
+func (p *Proxy) setToxicsLocked(toxics []*compiledToxic) {
+	p.toxics = toxics
+	for c := range p.conns {
+		c.arm(toxics)
+	}
+}
+
+// Stats returns the proxy's counters.
+func (p *Proxy) Stats() Stats {
+	s := Stats{
+		Connections:       p.connections.Load(),
+		ActiveConnections: p.active.Load(),
+		UpstreamErrors:    p.upstreamErrors.Load(),
+		BytesUpstream:     p.bytesUpstream.Load(),
+		BytesDownstream:   p.bytesDown.Load(),
+	}
+	p.statsMu.Lock()
+	defer p.statsMu.Unlock()
+	if len(p.activations) > 0 {
+		s.Toxics = make(map[string]int64, len(p.activations))
+		for name, n := range p.activations {
+			s.Toxics[name] = n
+		}
+	}
+	return s
+}
+
+// ResetStats zeroes the proxy's counters, except for the active connections.
+func (p *Proxy) ResetStats() {
+	p.connections.Store(0)
+	p.upstreamErrors.Store(0)
+	p.bytesUpstream.Store(0)
+	p.bytesDown.Store(0)
+	p.statsMu.Lock()
+	p.activations = make(map[string]int64)
+	p.statsMu.Unlock()
+}
+
+// Status returns the proxy's configuration and stats.
+func (p *Proxy) Status() Status {
+	return Status{
+		Name:     p.name,
+		Listen:   p.Addr(),
+		Upstream: p.upstream,
+		Toxics:   p.Toxics(),
+		Stats:    p.Stats(),
+	}
+}
+
+func (p *Proxy) recordActivation(name string) {
+	p.statsMu.Lock()
+	p.activations[name]++
+	p.statsMu.Unlock()
+}
+
+// streamToxics returns the current toxics for one direction.
+func (p *Proxy) streamToxics(stream string) []*compiledToxic {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	var out []*compiledToxic
+	for _, t := range p.toxics {
+		if t.Stream == stream {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func (p *Proxy) acceptLoop() {
+	defer p.wg.Done()
+	for {
+		client, err := p.listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+			continue
+		}
+		p.wg.Add(1)
+		go p.handle(client)
+	}
+}
+
+// handle proxies one client connection until both directions are done.
+func (p *Proxy) handle(client net.Conn) {
+	defer p.wg.Done()
+	p.connections.Add(1)
+
+	upstream, err := net.DialTimeout("tcp", p.upstream, dialTimeout)
+	if err != nil {
+		p.upstreamErrors.Add(1)
+		_ = client.Close()
+		return
+	}
+
+	c := &proxyConn{
+		proxy:    p,
+		client:   client,
+		upstream: upstream,
+		rolls:    make(map[*compiledToxic]bool),
+		timers:   make(map[*compiledToxic]*time.Timer),
+		done:     make(chan struct{}),
+	}
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		c.close()
+		return
+	}
+	p.conns[c] = struct{}{}
+	c.arm(p.toxics)
+	p.mu.Unlock()
+
+	p.active.Add(1)
+	defer p.active.Add(-1)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		c.pipe(StreamUpstream, client, upstream, &p.bytesUpstream)
+	}()
+	go func() {
+		defer wg.Done()
+		c.pipe(StreamDownstream, upstream, client, &p.bytesDown)
+	}()
+	wg.Wait()
+	c.close()
+
+	p.mu.Lock()
+	delete(p.conns, c)
+	p.mu.Unlock()
+}
+
+// proxyConn is one proxied connection.
+type proxyConn struct {
+	proxy    *Proxy
+	client   net.Conn
+	upstream net.Conn
+	done     chan struct{}
+	once     sync.Once
+
+	mu       sync.Mutex
+	rolls    map[*compiledToxic]bool // whether each toxic applies to this connection
+	timers   map[*compiledToxic]*time.Timer
+	halfOpen bool // a half_open toxic kept the connection open; discard writes
+}
+
+// applies reports whether t applies to c, rolling its toxicity the first
+// time t is seen on c.
+func (c *proxyConn) applies(t *compiledToxic) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	on, ok := c.rolls[t]
+	if !ok {
+		on = t.roll()
+		c.rolls[t] = on
+		if on {
+			c.proxy.recordActivation(t.Name)
+		}
+	}
+	return on
+}
+
+// arm starts the timers of the timeout and reset_peer toxics that apply to
+// c and stops the timers of toxics that were removed.
+func (c *proxyConn) arm(toxics []*compiledToxic) {
+	current := make(map[*compiledToxic]bool, len(toxics))
+	for _, t := range toxics {
+		current[t] = true
+	}
+	c.mu.Lock()
+	for t, timer := range c.timers {
+		if !current[t] {
+			timer.Stop()
+			delete(c.timers, t)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, t := range toxics {
+		var fire func()
+		switch {
+		case t.Type == ToxicResetPeer:
+			fire = c.reset
+		case t.Type == ToxicTimeout && t.timeout > 0:
+			fire = c.close
+		default:
+			continue
+		}
+		if !c.applies(t) {
+			continue
+		}
+		c.mu.Lock()
+		if _, ok := c.timers[t]; !ok {
+			c.timers[t] = time.AfterFunc(t.timeout, fire)
+		}
+		c.mu.Unlock()
+	}
+}
+
+// active returns the first toxic of the given type on stream that applies to c.
+func (c *proxyConn) active(stream, toxicType string) *compiledToxic {
+	for _, t := range c.proxy.streamToxics(stream) {
+		if t.Type == toxicType && c.applies(t) {
+			return t
+		}
+	}
+	return nil
+}
+
+// pipe copies src to dst through the stream's toxics until src is done.
+func (c *proxyConn) pipe(stream string, src, dst net.Conn, counter *atomic.Int64) {
+	buf := make([]byte, bufferSize)
+	for {
+		n, err := src.Read(buf)
+		if n > 0 && !c.isHalfOpen() {
+			if werr := c.forward(stream, dst, buf[:n], counter); werr != nil && !c.isHalfOpen() {
+				c.close()
+				return
+			}
+		}
+		if err == nil {
+			continue
+		}
+
+		// src is done. A half_open toxic hides that from dst, which keeps
+		// waiting on a connection nobody answers any more.
+		if !errors.Is(err, io.EOF) {
+			c.close()
+			return
+		}
+		if c.active(stream, ToxicHalfOpen) != nil {
+			c.mu.Lock()
+			c.halfOpen = true
+			c.mu.Unlock()
+			return
+		}
+		if cw, ok := dst.(interface{ CloseWrite() error }); ok {
+			_ = cw.CloseWrite()
+		} else {
+			c.close()
+		}
+		return
+	}
+}
+
+func (c *proxyConn) isHalfOpen() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.halfOpen
+}
+
+// forward writes data to dst, applying the stream's toxics.
+func (c *proxyConn) forward(stream string, dst net.Conn, data []byte, counter *atomic.Int64) error {
+	var latency time.Duration
+	var bandwidth, slicer *compiledToxic
+	for _, t := range c.proxy.streamToxics(stream) {
+		if !c.applies(t) {
+			continue
+		}
+		switch t.Type {
+		case ToxicTimeout:
+			return nil // black-holed until the timeout closes the connection
+		case ToxicLatency:
+			latency += t.chunkDelay()
+		case ToxicBandwidth:
+			bandwidth = t
+		case ToxicSlicer:
+			slicer = t
+		}
+	}
+
+	if err := c.sleep(latency); err != nil {
+		return err
+	}
+
+	var chunks [][]byte
+	switch {
+	case slicer != nil:
+		chunks = slicer.slice(data)
+	case bandwidth != nil:
+		// Write at most a tenth of a second's worth at a time so the rate
+		// stays smooth.
+		chunks = splitEvery(data, max(bandwidth.BytesPerSecond/10, 1))
+	default:
+		chunks = [][]byte{data}
+	}
+
+	for i, chunk := range chunks {
+		if i > 0 && slicer != nil {
+			if err := c.sleep(slicer.delay); err != nil {
+				return err
+			}
+		}
+		if bandwidth != nil {
+			if err := c.sleep(time.Duration(len(chunk)) * time.Second / time.Duration(bandwidth.BytesPerSecond)); err != nil {
+				return err
+			}
+		}
+		if _, err := dst.Write(chunk); err != nil {
+			return err
+		}
+		counter.Add(int64(len(chunk)))
+	}
+	return nil
+}
+
+// sleep waits for d, or returns errConnClosed if the connection closes first.
+func (c *proxyConn) sleep(d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-c.done:
+		return errConnClosed
+	}
+}
+
+// reset closes both sides with an RST instead of a FIN.
+func (c *proxyConn) reset() {
+	for _, conn := range []net.Conn{c.client, c.upstream} {
+		if tcp, ok := conn.(*net.TCPConn); ok {
+			_ = tcp.SetLinger(0)
+		}
+	}
+	c.close()
+}
+
+// close closes both sides and stops the connection's timers.
+func (c *proxyConn) close() {
+	c.once.Do(func() {
+		close(c.done)
+		_ = c.client.Close()
+		_ = c.upstream.Close()
+		c.mu.Lock()
+		for _, timer := range c.timers {
+			timer.Stop()
+		}
+		c.mu.Unlock()
+	})
+}
+
+func toxicList(toxics []*compiledToxic) []Toxic {
+	out := make([]Toxic, len(toxics))
+	for i, t := range toxics {
+		out[i] = t.Toxic
+	}
+	return out
+}
@@ -0,0 +1,287 @@
+package tcpproxy
+
+import (
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startEchoServer starts a TCP server that echoes everything it reads.
+func startEchoServer(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func startProxy(t *testing.T, upstream string, toxics ...Toxic) *Proxy {
+	t.Helper()
+	p, err := Start(Config{Name: "test", Listen: "127.0.0.1:0", Upstream: upstream, Toxics: toxics})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = p.Close() })
+	return p
+}
+
+func dial(t *testing.T, p *Proxy) net.Conn {
+	t.Helper()
+	conn, err := net.Dial("tcp", p.Addr())
+	if err != nil {
+		t.Fatalf("dial proxy: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+// roundTrip writes msg and reads the echo, returning how long it took.
+func roundTrip(t *testing.T, conn net.Conn, msg string) time.Duration {
+	t.Helper()
+	start := time.Now()
+	if _, err := conn.Write([]byte(msg)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	buf := make([]byte, len(msg))
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if string(buf) != msg {
+		t.Fatalf("echo = %q, want %q", buf, msg)
+	}
+	return time.Since(start)
+}
+
+func TestProxy_Forwards(t *testing.T) {
+	p := startProxy(t, startEchoServer(t))
+	conn := dial(t, p)
+	roundTrip(t, conn, "hello")
+
+	stats := p.Stats()
+	if stats.Connections != 1 || stats.BytesUpstream != 5 || stats.BytesDownstream != 5 {
+		t.Errorf("Stats() = %+v, want 1 connection and 5 bytes each way", stats)
+	}
+}
+
+func TestProxy_Latency(t *testing.T) {
+	p := startProxy(t, startEchoServer(t), Toxic{Type: ToxicLatency, Latency: "100ms"})
+	conn := dial(t, p)
+	if d := roundTrip(t, conn, "ping"); d < 100*time.Millisecond {
+		t.Errorf("round trip took %v, want at least 100ms", d)
+	}
+
+	// Toxics apply to open connections as soon as they change.
+	if err := p.RemoveToxic("latency_downstream"); err != nil {
+		t.Fatalf("RemoveToxic() error = %v", err)
+	}
+	if d := roundTrip(t, conn, "ping"); d >= 100*time.Millisecond {
+		t.Errorf("round trip took %v after removing the toxic", d)
+	}
+	if got := p.Stats().Toxics["latency_downstream"]; got != 1 {
+		t.Errorf("toxic activations = %d, want 1", got)
+	}
+}
+
+func TestProxy_Bandwidth(t *testing.T) {
+	p := startProxy(t, startEchoServer(t), Toxic{Type: ToxicBandwidth, BytesPerSecond: 10_000})
+	conn := dial(t, p)
+	if d := roundTrip(t, conn, strings.Repeat("x", 3000)); d < 250*time.Millisecond {
+		t.Errorf("3000 bytes at 10kB/s took %v, want about 300ms", d)
+	}
+}
+
+func TestProxy_Slicer(t *testing.T) {
+	p := startProxy(t, startEchoServer(t), Toxic{Type: ToxicSlicer, AverageSize: 4, SizeVariation: 2, Delay: "1ms"})
+	conn := dial(t, p)
+	roundTrip(t, conn, strings.Repeat("sliced ", 20))
+}
+
+func TestProxy_ResetPeer(t *testing.T) {
+	p := startProxy(t, startEchoServer(t), Toxic{Type: ToxicResetPeer, Timeout: "50ms"})
+	conn := dial(t, p)
+	roundTrip(t, conn, "before")
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, err := conn.Read(make([]byte, 1))
+	if err == nil || errors.Is(err, io.EOF) {
+		t.Fatalf("Read() error = %v, want a connection reset", err)
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		t.Fatalf("connection was not reset: %v", err)
+	}
+}
+
+func TestProxy_Timeout(t *testing.T) {
+	p := startProxy(t, startEchoServer(t), Toxic{Type: ToxicTimeout, Timeout: "100ms"})
+	conn := dial(t, p)
+	if _, err := conn.Write([]byte("lost")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	start := time.Now()
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, err := conn.Read(make([]byte, 16))
+	if n != 0 || err == nil {
+		t.Fatalf("Read() = %d, %v; want the data dropped and the connection closed", n, err)
+	}
+	if d := time.Since(start); d < 80*time.Millisecond || d > time.Second {
+		t.Errorf("connection closed after %v, want about 100ms", d)
+	}
+}
+
+func TestProxy_HalfOpen(t *testing.T) {
+	// The upstream answers once and hangs up.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		_, _ = conn.Write([]byte("bye"))
+		_ = conn.Close()
+	}()
+
+	p := startProxy(t, ln.Addr().String(), Toxic{Type: ToxicHalfOpen})
+	conn := dial(t, p)
+	buf := make([]byte, 3)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if _, err := io.ReadFull(conn, buf); err != nil || string(buf) != "bye" {
+		t.Fatalf("read = %q, %v", buf, err)
+	}
+
+	// The client never learns the upstream is gone.
+	_ = conn.SetReadDeadline(time.Now().Add(150 * time.Millisecond))
+	_, err = conn.Read(buf)
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Fatalf("Read() error = %v, want a timeout on the half-open connection", err)
+	}
+}
+
+func TestProxy_Toxicity(t *testing.T) {
+	never := 0.0
+	p := startProxy(t, startEchoServer(t), Toxic{Type: ToxicLatency, Latency: "1s", Toxicity: &never})
+	conn := dial(t, p)
+	if d := roundTrip(t, conn, "fast"); d >= time.Second {
+		t.Errorf("round trip took %v with toxicity 0", d)
+	}
+	if len(p.Stats().Toxics) != 0 {
+		t.Errorf("Stats().Toxics = %v, want none", p.Stats().Toxics)
+	}
+}
+
+func TestProxy_UpstreamDown(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	p := startProxy(t, addr)
+	conn := dial(t, p)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if _, err := conn.Read(make([]byte, 1)); err == nil {
+		t.Fatal("Read() succeeded with the upstream down")
+	}
+	if got := p.Stats().UpstreamErrors; got != 1 {
+		t.Errorf("UpstreamErrors = %d, want 1", got)
+	}
+}
+
+func TestToxic_Validate(t *testing.T) {
+	tooToxic := 2.0
+	for _, toxic := range []Toxic{
+		{Type: "explode"},
+		{Type: ToxicLatency},
+		{Type: ToxicLatency, Latency: "soon"},
+		{Type: ToxicBandwidth},
+		{Type: ToxicSlicer, AverageSize: 4, SizeVariation: 4},
+		{Type: ToxicTimeout, Stream: "sideways"},
+		{Type: ToxicHalfOpen, Name: "bad name"},
+		{Type: ToxicHalfOpen, Toxicity: &tooToxic},
+	} {
+		if err := toxic.Validate(); err == nil {
+			t.Errorf("Validate() accepted %+v", toxic)
+		}
+	}
+
+	p := startProxy(t, startEchoServer(t))
+	if err := p.SetToxics([]Toxic{{Type: ToxicHalfOpen}, {Type: ToxicHalfOpen}}); !errors.Is(err, ErrToxicExists) {
+		t.Errorf("SetToxics() with duplicate names error = %v, want ErrToxicExists", err)
+	}
+	added, err := p.AddToxic(Toxic{Type: ToxicTimeout, Stream: StreamUpstream})
+	if err != nil || added.Name != "timeout_upstream" {
+		t.Fatalf("AddToxic() = %+v, %v", added, err)
+	}
+	if _, err := p.AddToxic(Toxic{Type: ToxicTimeout, Stream: StreamUpstream}); !errors.Is(err, ErrToxicExists) {
+		t.Errorf("AddToxic() duplicate error = %v, want ErrToxicExists", err)
+	}
+	if err := p.RemoveToxic("missing"); !errors.Is(err, ErrToxicNotFound) {
+		t.Errorf("RemoveToxic() error = %v, want ErrToxicNotFound", err)
+	}
+}
+
+func TestManager(t *testing.T) {
+	m := NewManager()
+	defer m.Close()
+	upstream := startEchoServer(t)
+
+	for _, name := range []string{"redis", "postgres"} {
+		if _, err := m.Create(Config{Name: name, Listen: "127.0.0.1:0", Upstream: upstream}); err != nil {
+			t.Fatalf("Create(%s) error = %v", name, err)
+		}
+	}
+	if _, err := m.Create(Config{Name: "redis", Listen: "127.0.0.1:0", Upstream: upstream}); !errors.Is(err, ErrProxyExists) {
+		t.Errorf("Create() duplicate error = %v, want ErrProxyExists", err)
+	}
+	if _, err := m.Create(Config{Name: "bad/name", Listen: "127.0.0.1:0", Upstream: upstream}); err == nil {
+		t.Error("Create() accepted an invalid name")
+	}
+
+	list := m.List()
+	if len(list) != 2 || list[0].Name != "postgres" || list[1].Name != "redis" {
+		t.Fatalf("List() = %+v, want postgres and redis", list)
+	}
+
+	p, _ := m.Get("redis")
+	conn := dial(t, p)
+	roundTrip(t, conn, "stats")
+	if got := m.Stats()["redis"].BytesUpstream; got != 5 {
+		t.Errorf("Stats()[redis].BytesUpstream = %d, want 5", got)
+	}
+	m.ResetStats()
+	if got := m.Stats()["redis"].BytesUpstream; got != 0 {
+		t.Errorf("BytesUpstream after ResetStats() = %d", got)
+	}
+
+	if err := m.Delete("redis"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := m.Delete("redis"); !errors.Is(err, ErrProxyNotFound) {
+		t.Errorf("Delete() again error = %v, want ErrProxyNotFound", err)
+	}
+}
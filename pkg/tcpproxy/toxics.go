@@ -0,0 +1,207 @@
+// Package tcpproxy forwards TCP connections from a local listener to an
+// upstream address and degrades them with toxics: latency, bandwidth limits,
+// slicing, timeouts, connection resets and half-open connections. It lets
+// mockd degrade real dependencies such as databases and message brokers the
+// way pkg/chaos degrades the traffic mockd answers itself.
+package tcpproxy
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"time"
+)
+
+// Toxic types.
+const (
+	ToxicLatency   = "latency"
+	ToxicBandwidth = "bandwidth"
+	ToxicSlicer    = "slicer"
+	ToxicTimeout   = "timeout"
+	ToxicResetPeer = "reset_peer"
+	ToxicHalfOpen  = "half_open"
+)
+
+// Streams a toxic can apply to.
+const (
+	// StreamUpstream is the data sent by the client to the upstream.
+	StreamUpstream = "upstream"
+	// StreamDownstream is the data sent by the upstream back to the client.
+	StreamDownstream = "downstream"
+)
+
+// Errors returned by proxies and the manager.
+var (
+	ErrProxyExists   = errors.New("tcp proxy already exists")
+	ErrProxyNotFound = errors.New("tcp proxy not found")
+	ErrToxicExists   = errors.New("toxic already exists")
+	ErrToxicNotFound = errors.New("toxic not found")
+)
+
+// nameRe restricts proxy and toxic names to URL-safe identifiers.
+var nameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Toxic degrades the data flowing in one direction of a proxied connection.
+type Toxic struct {
+	// Name identifies the toxic within its proxy. Defaults to "<type>_<stream>".
+	Name string `json:"name" yaml:"name"`
+	// Type is one of latency, bandwidth, slicer, timeout, reset_peer and half_open.
+	Type string `json:"type" yaml:"type"`
+	// Stream is the direction the toxic applies to: "downstream" (default)
+	// or "upstream".
+	Stream string `json:"stream,omitempty" yaml:"stream,omitempty"`
+	// Toxicity is the probability that the toxic applies to a connection,
+	// decided once per connection. Nil means always.
+	Toxicity *float64 `json:"toxicity,omitempty" yaml:"toxicity,omitempty"`
+
+	// Latency delays every chunk of data, plus or minus Jitter (latency).
+	Latency string `json:"latency,omitempty" yaml:"latency,omitempty"`
+	Jitter  string `json:"jitter,omitempty" yaml:"jitter,omitempty"`
+	// BytesPerSecond caps the throughput (bandwidth).
+	BytesPerSecond int `json:"bytesPerSecond,omitempty" yaml:"bytesPerSecond,omitempty"`
+	// AverageSize and SizeVariation size the small writes data is split
+	// into, with Delay between them (slicer).
+	AverageSize   int    `json:"averageSize,omitempty" yaml:"averageSize,omitempty"`
+	SizeVariation int    `json:"sizeVariation,omitempty" yaml:"sizeVariation,omitempty"`
+	Delay         string `json:"delay,omitempty" yaml:"delay,omitempty"`
+	// Timeout is how long a connection lives before reset_peer resets it or
+	// timeout closes it. Until then, timeout drops all data; a timeout of 0
+	// never closes the connection.
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// Validate checks the toxic's type, stream and parameters.
+func (t Toxic) Validate() error {
+	_, err := compileToxic(t)
+	return err
+}
+
+// compiledToxic is a Toxic with defaults filled in and durations parsed.
+type compiledToxic struct {
+	Toxic
+	toxicity float64
+	latency  time.Duration
+	jitter   time.Duration
+	delay    time.Duration
+	timeout  time.Duration
+}
+
+func compileToxic(t Toxic) (*compiledToxic, error) {
+	if t.Stream == "" {
+		t.Stream = StreamDownstream
+	}
+	if t.Stream != StreamUpstream && t.Stream != StreamDownstream {
+		return nil, fmt.Errorf("toxic stream must be %q or %q, got %q", StreamUpstream, StreamDownstream, t.Stream)
+	}
+	if t.Name == "" {
+		t.Name = t.Type + "_" + t.Stream
+	}
+	if !nameRe.MatchString(t.Name) {
+		return nil, fmt.Errorf("invalid toxic name %q", t.Name)
+	}
+
+	c := &compiledToxic{Toxic: t, toxicity: 1}
+	if t.Toxicity != nil {
+		if *t.Toxicity < 0 || *t.Toxicity > 1 {
+			return nil, fmt.Errorf("toxic %s: toxicity must be between 0 and 1", t.Name)
+		}
+		c.toxicity = *t.Toxicity
+	}
+
+	var err error
+	parse := func(field, value string) time.Duration {
+		if value == "" || err != nil {
+			return 0
+		}
+		d, perr := time.ParseDuration(value)
+		if perr != nil || d < 0 {
+			err = fmt.Errorf("toxic %s: %s must be a non-negative Go duration, got %q", t.Name, field, value)
+		}
+		return d
+	}
+
+	switch t.Type {
+	case ToxicLatency:
+		c.latency = parse("latency", t.Latency)
+		c.jitter = parse("jitter", t.Jitter)
+		if err == nil && c.latency == 0 && c.jitter == 0 {
+			err = fmt.Errorf("toxic %s: latency needs latency or jitter", t.Name)
+		}
+	case ToxicBandwidth:
+		if t.BytesPerSecond <= 0 {
+			err = fmt.Errorf("toxic %s: bandwidth needs a positive bytesPerSecond", t.Name)
+		}
+	case ToxicSlicer:
+		c.delay = parse("delay", t.Delay)
+		if err == nil && (t.AverageSize <= 0 || t.SizeVariation < 0 || t.SizeVariation >= t.AverageSize) {
+			err = fmt.Errorf("toxic %s: slicer needs a positive averageSize larger than sizeVariation", t.Name)
+		}
+	case ToxicTimeout, ToxicResetPeer:
+		c.timeout = parse("timeout", t.Timeout)
+	case ToxicHalfOpen:
+	default:
+		err = fmt.Errorf("unknown toxic type %q", t.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// compileToxics compiles a proxy's toxic list, rejecting duplicate names.
+func compileToxics(toxics []Toxic) ([]*compiledToxic, error) {
+	out := make([]*compiledToxic, 0, len(toxics))
+	seen := make(map[string]bool, len(toxics))
+	for _, t := range toxics {
+		c, err := compileToxic(t)
+		if err != nil {
+			return nil, err
+		}
+		if seen[c.Name] {
+			return nil, fmt.Errorf("%w: %s", ErrToxicExists, c.Name)
+		}
+		seen[c.Name] = true
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// roll decides whether the toxic applies to a new connection.
+func (t *compiledToxic) roll() bool {
+	return t.toxicity >= 1 || rand.Float64() < t.toxicity
+}
+
+// chunkDelay returns the latency for one chunk of data.
+func (t *compiledToxic) chunkDelay() time.Duration {
+	d := t.latency
+	if t.jitter > 0 {
+		d += time.Duration(rand.Int63n(int64(2*t.jitter)+1)) - t.jitter
+	}
+	return max(d, 0)
+}
+
+// slice splits data into writes of AverageSize plus or minus SizeVariation.
+func (t *compiledToxic) slice(data []byte) [][]byte {
+	var chunks [][]byte
+	for len(data) > 0 {
+		size := t.AverageSize
+		if t.SizeVariation > 0 {
+			size += rand.Intn(2*t.SizeVariation+1) - t.SizeVariation
+		}
+		size = min(max(size, 1), len(data))
+		chunks = append(chunks, data[:size])
+		data = data[size:]
+	}
+	return chunks
+}
+
+// splitEvery splits data into chunks of at most size bytes.
+func splitEvery(data []byte, size int) [][]byte {
+	var chunks [][]byte
+	for len(data) > size {
+		chunks = append(chunks, data[:size])
+		data = data[size:]
+	}
+	return append(chunks, data)
+}
@@ -1,6 +1,7 @@
-// Package util provides shared helpers for safe file-path validation and
-// log-body truncation used across mockd packages.
+// Package util provides shared helpers for safe file-path validation,
+// log-body truncation and resource naming used across mockd packages.
 //
 //   - SafeFilePath / SafeFilePathAllowAbsolute — reject path-traversal attempts
 //   - TruncateBody — cap request/response bodies for safe logging
+//   - Singularize — derive an item name from a plural resource name
 package util
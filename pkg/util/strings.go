@@ -57,3 +57,22 @@ func TruncateBody(data string, maxSize int) string {
 	}
 	return data
 }
+
+// Singularize strips a simple English plural suffix from a resource or table
+// name: "users" → "user", "courses" → "course", "categories" → "category",
+// "addresses" → "address", "boxes" → "box". Words that do not look plural,
+// such as "class", "status" or "data", are returned unchanged.
+func Singularize(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies") && len(s) > 3:
+		return strings.TrimSuffix(s, "ies") + "y"
+	case strings.HasSuffix(s, "sses"), strings.HasSuffix(s, "xes"), strings.HasSuffix(s, "zzes"),
+		strings.HasSuffix(s, "ches"), strings.HasSuffix(s, "shes"):
+		return strings.TrimSuffix(s, "es")
+	case strings.HasSuffix(s, "ss"), strings.HasSuffix(s, "us"), strings.HasSuffix(s, "is"):
+		return s
+	case strings.HasSuffix(s, "s"):
+		return strings.TrimSuffix(s, "s")
+	}
+	return s
+}
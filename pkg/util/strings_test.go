@@ -160,3 +160,24 @@ func TestTruncateBody_DefaultMaxSize(t *testing.T) {
 	result2 := TruncateBody(shortData, 0)
 	assert.Equal(t, shortData, result2)
 }
+
+func TestSingularize(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]string{
+		"users":      "user",
+		"orders":     "order",
+		"courses":    "course",
+		"addresses":  "address",
+		"categories": "category",
+		"boxes":      "box",
+		"matches":    "match",
+		"class":      "class",
+		"status":     "status",
+		"data":       "data",
+		"person":     "person",
+		"sheep":      "sheep",
+	} {
+		assert.Equal(t, want, Singularize(in), in)
+	}
+}
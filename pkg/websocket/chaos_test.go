@@ -0,0 +1,59 @@
+package websocket
+
+import (
+	"testing"
+
+	gorillaWs "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/getmockd/mockd/pkg/chaos"
+)
+
+func TestHandlerE2E_MessageChaos(t *testing.T) {
+	endpoint, err := NewEndpoint(&EndpointConfig{Path: "/ws/chaos"})
+	require.NoError(t, err)
+	ts, manager := setupHandler(t, endpoint)
+	defer ts.Close()
+
+	injector, err := chaos.NewInjector(&chaos.ChaosConfig{
+		Enabled: true,
+		Rules: []chaos.ChaosRule{{
+			Protocol:    chaos.ProtocolWebSocket,
+			PathPattern: "^/ws/chaos$",
+			Faults: []chaos.FaultConfig{
+				{Type: chaos.FaultMessageDuplicate, Probability: 1},
+				{Type: chaos.FaultMessageClose, Probability: 1, Config: map[string]interface{}{
+					"afterMessages": 1, "code": 4001, "reason": "chaos close",
+				}},
+			},
+			Probability: 1,
+		}},
+	})
+	require.NoError(t, err)
+	manager.SetChaosSource(func() *chaos.Injector { return injector })
+
+	conn, err := dialWSConn(t, ts, "/ws/chaos")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// The first echo is duplicated.
+	require.NoError(t, conn.WriteMessage(gorillaWs.TextMessage, []byte("hello")))
+	for range 2 {
+		_, msg, err := conn.ReadMessage()
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(msg))
+	}
+
+	// The second echo closes the connection with the configured code.
+	require.NoError(t, conn.WriteMessage(gorillaWs.TextMessage, []byte("again")))
+	_, _, err = conn.ReadMessage()
+	var closeErr *gorillaWs.CloseError
+	require.ErrorAs(t, err, &closeErr)
+	assert.Equal(t, 4001, closeErr.Code)
+	assert.Equal(t, "chaos close", closeErr.Text)
+
+	stats := injector.GetStats()
+	assert.Equal(t, int64(2), stats.MessagesProcessed)
+	assert.Equal(t, int64(1), stats.FaultsByType[chaos.FaultMessageClose])
+}
@@ -21,6 +21,8 @@ func EndpointFromConfig(cfg *config.WebSocketEndpointConfig) (*Endpoint, error)
 		MaxConnections:     cfg.MaxConnections,
 		EchoMode:           cfg.EchoMode,
 		SkipOriginVerify:   cfg.SkipOriginVerify,
+		StatefulStream:     cfg.StatefulStream,
+		WorkspaceID:        cfg.WorkspaceID,
 	}
 
 	// Parse idle timeout
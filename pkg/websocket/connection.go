@@ -3,6 +3,7 @@ package websocket
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"sync"
 	"sync/atomic"
@@ -10,6 +11,7 @@ import (
 
 	ws "github.com/coder/websocket"
 
+	"github.com/getmockd/mockd/pkg/chaos"
 	"github.com/getmockd/mockd/pkg/recording"
 )
 
@@ -30,6 +32,7 @@ type Connection struct {
 	endpoint      *Endpoint
 	manager       *ConnectionManager
 	recordingHook recording.WebSocketRecordingHook
+	chaos         *chaos.MessageChaos // nil unless a websocket chaos rule applies
 	ctx           context.Context
 	cancel        context.CancelFunc
 	mu            sync.RWMutex
@@ -183,8 +186,25 @@ func (c *Connection) IsClosed() bool {
 	return c.closed.Load()
 }
 
-// Send sends a message to the client.
+// Send sends a message to the client, through the connection's message
+// chaos if any. A message_close fault closes the connection with the fault's
+// code and reason (default 1011) and returns ErrConnectionClosed.
 func (c *Connection) Send(msgType MessageType, data []byte) error {
+	err := c.chaos.Send(c.ctx, len(data), func() error { return c.send(msgType, data) })
+	var closeErr *chaos.CloseError
+	if errors.As(err, &closeErr) {
+		code := CloseCode(closeErr.Code)
+		if code == 0 {
+			code = CloseInternalError
+		}
+		_ = c.Close(code, closeErr.Reason)
+		return ErrConnectionClosed
+	}
+	return err
+}
+
+// send writes a message to the client.
+func (c *Connection) send(msgType MessageType, data []byte) error {
 	c.sendMu.RLock()
 	defer c.sendMu.RUnlock()
 
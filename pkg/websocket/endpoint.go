@@ -4,6 +4,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/getmockd/mockd/pkg/mock"
+	"github.com/getmockd/mockd/pkg/stateful"
 	"github.com/getmockd/mockd/pkg/template"
 )
 
@@ -35,6 +37,10 @@ type EndpointConfig struct {
 	// Default: true (allows any origin for development/testing convenience).
 	// Set to false to enforce that Origin matches the Host header.
 	SkipOriginVerify *bool `json:"skipOriginVerify,omitempty"`
+	// StatefulStream pushes live changes from a stateful table to every connection.
+	StatefulStream *mock.StatefulStreamConfig `json:"statefulStream,omitempty"`
+	// WorkspaceID is the workspace whose stateful tables StatefulStream reads.
+	WorkspaceID string `json:"workspaceId,omitempty"`
 }
 
 // HeartbeatConfig configures WebSocket ping/pong keepalive.
@@ -70,6 +76,8 @@ type Endpoint struct {
 	echoMode           bool
 	skipOriginVerify   bool
 	enabled            bool
+	changes            *stateful.ChangeStream
+	workspaceID        string
 
 	manager        *ConnectionManager
 	connections    map[string]*Connection
@@ -93,8 +101,8 @@ func NewEndpoint(cfg *EndpointConfig) (*Endpoint, error) {
 	echoMode := true
 	if cfg.EchoMode != nil {
 		echoMode = *cfg.EchoMode
-	} else if len(cfg.Matchers) > 0 || cfg.Scenario != nil {
-		// Disable echo mode if matchers or scenario are configured
+	} else if len(cfg.Matchers) > 0 || cfg.Scenario != nil || cfg.StatefulStream != nil {
+		// Disable echo mode if matchers, scenario or a table stream are configured
 		echoMode = false
 	}
 
@@ -116,9 +124,19 @@ func NewEndpoint(cfg *EndpointConfig) (*Endpoint, error) {
 		echoMode:           echoMode,
 		skipOriginVerify:   skipOriginVerify,
 		enabled:            true, // Endpoints are enabled by default
+		workspaceID:        cfg.WorkspaceID,
 		connections:        make(map[string]*Connection),
 	}
 
+	// Compile table stream
+	if cfg.StatefulStream != nil {
+		changes, err := stateful.CompileChangeStream(cfg.StatefulStream)
+		if err != nil {
+			return nil, err
+		}
+		e.changes = changes
+	}
+
 	// Compile matchers
 	for _, mc := range cfg.Matchers {
 		m, err := NewMatcher(mc)
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	ws "github.com/coder/websocket"
+	"github.com/getmockd/mockd/pkg/stateful"
 	"github.com/getmockd/mockd/pkg/template"
 )
 
@@ -42,6 +43,18 @@ func (e *Endpoint) HandleUpgrade(w http.ResponseWriter, r *http.Request) error {
 		return err
 	}
 
+	// Subscribe to the table before upgrading so a missing table is reported
+	// as a normal HTTP error.
+	var sub *stateful.Subscription
+	if e.changes != nil {
+		var status int
+		sub, status, err = e.subscribeChanges(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), status)
+			return err
+		}
+	}
+
 	// Accept options
 	acceptOpts := &ws.AcceptOptions{
 		Subprotocols:       e.subprotocols,
@@ -52,6 +65,9 @@ func (e *Endpoint) HandleUpgrade(w http.ResponseWriter, r *http.Request) error {
 	// Accept the WebSocket connection
 	wsConn, err := ws.Accept(w, r, acceptOpts)
 	if err != nil {
+		if sub != nil {
+			sub.Close()
+		}
 		return err
 	}
 
@@ -59,6 +75,9 @@ func (e *Endpoint) HandleUpgrade(w http.ResponseWriter, r *http.Request) error {
 	// If not, we must close the WebSocket connection to avoid leaks.
 	setupComplete := false
 	defer func() {
+		if !setupComplete && sub != nil {
+			sub.Close()
+		}
 		if !setupComplete {
 			// Setup failed after Accept() - close the raw connection
 			_ = wsConn.Close(ws.StatusInternalError, "connection setup failed")
@@ -70,6 +89,9 @@ func (e *Endpoint) HandleUpgrade(w http.ResponseWriter, r *http.Request) error {
 
 	// Create our connection wrapper
 	conn := NewConnection(wsConn, e, negotiatedProtocol, r)
+	if e.manager != nil {
+		conn.chaos = e.manager.messageChaos(r.URL.Path)
+	}
 
 	// Atomically check max connections and add — avoids TOCTOU race
 	// between CanAccept() and AddConnection().
@@ -93,7 +115,7 @@ func (e *Endpoint) HandleUpgrade(w http.ResponseWriter, r *http.Request) error {
 	}
 
 	// Start connection handling in a goroutine
-	go e.handleConnection(conn)
+	go e.handleConnection(conn, sub)
 
 	// Mark setup as complete - connection is now managed by handleConnection
 	setupComplete = true
@@ -102,7 +124,8 @@ func (e *Endpoint) HandleUpgrade(w http.ResponseWriter, r *http.Request) error {
 }
 
 // handleConnection handles the lifecycle of a WebSocket connection.
-func (e *Endpoint) handleConnection(conn *Connection) {
+// sub is the table change subscription for statefulStream endpoints, or nil.
+func (e *Endpoint) handleConnection(conn *Connection, sub *stateful.Subscription) {
 	closeCode := CloseNormalClosure
 
 	defer func() {
@@ -131,6 +154,12 @@ func (e *Endpoint) handleConnection(conn *Connection) {
 		defer executor.Stop()
 	}
 
+	// Forward table changes if configured
+	if sub != nil {
+		defer sub.Close()
+		go e.runChangeStream(conn, sub)
+	}
+
 	// Start heartbeat if configured
 	var heartbeatCancel context.CancelFunc
 	if e.heartbeat != nil && e.heartbeat.Enabled {
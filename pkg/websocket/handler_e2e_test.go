@@ -8,6 +8,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/getmockd/mockd/pkg/mock"
+	"github.com/getmockd/mockd/pkg/stateful"
 	gorillaWs "github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -367,3 +369,100 @@ func TestHandlerE2E_IdleTimeout(t *testing.T) {
 	_, _, err = conn.ReadMessage()
 	require.Error(t, err, "expected read to fail after idle timeout")
 }
+
+func TestHandlerE2E_StatefulStream(t *testing.T) {
+	store := stateful.NewStateStore()
+	require.NoError(t, store.Register("", &stateful.ResourceConfig{Name: "orders"}))
+
+	endpoint, err := NewEndpoint(&EndpointConfig{
+		Path: "/ws/orders",
+		StatefulStream: &mock.StatefulStreamConfig{
+			Table:  "orders",
+			Events: []string{"updated"},
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, endpoint.HasStatefulStream())
+	assert.False(t, endpoint.EchoMode(), "table streams disable echo by default")
+
+	ts, manager := setupHandler(t, endpoint)
+	defer ts.Close()
+	manager.SetStatefulStore(store)
+
+	conn, err := dialWSConn(t, ts, "/ws/orders")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.Eventually(t, func() bool { return store.Changes().SubscriberCount() == 1 }, 2*time.Second, 5*time.Millisecond)
+
+	orders := store.Get("", "orders")
+	_, err = orders.Create(map[string]interface{}{"id": "o1", "status": "pending"}, nil)
+	require.NoError(t, err)
+	_, err = orders.Patch("o1", map[string]interface{}{"status": "shipped"})
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	var msg map[string]interface{}
+	require.NoError(t, conn.ReadJSON(&msg))
+	assert.Equal(t, "order.updated", msg["type"])
+	assert.Equal(t, "o1", msg["id"])
+	assert.Equal(t, "shipped", msg["data"].(map[string]interface{})["status"])
+	assert.Equal(t, "pending", msg["previous"].(map[string]interface{})["status"])
+
+	// Closing the client releases the subscription.
+	require.NoError(t, conn.Close())
+	assert.Eventually(t, func() bool { return store.Changes().SubscriberCount() == 0 }, 2*time.Second, 5*time.Millisecond)
+}
+
+func TestHandlerE2E_StatefulStreamUnknownTable(t *testing.T) {
+	endpoint, err := NewEndpoint(&EndpointConfig{
+		Path:           "/ws/missing",
+		StatefulStream: &mock.StatefulStreamConfig{Table: "missing"},
+	})
+	require.NoError(t, err)
+
+	ts, manager := setupHandler(t, endpoint)
+	defer ts.Close()
+	manager.SetStatefulStore(stateful.NewStateStore())
+
+	_, resp, err := dialWS(t, ts, "/ws/missing") //nolint:bodyclose // closed via t.Cleanup in dialWS
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestHandlerE2E_StatefulStreamIsolationPartition(t *testing.T) {
+	store := stateful.NewStateStore()
+	require.NoError(t, store.Register("", &stateful.ResourceConfig{Name: "orders"}))
+
+	endpoint, err := NewEndpoint(&EndpointConfig{
+		Path:           "/ws/orders",
+		StatefulStream: &mock.StatefulStreamConfig{Table: "orders"},
+	})
+	require.NoError(t, err)
+
+	manager := NewConnectionManager()
+	manager.RegisterEndpoint(endpoint)
+	manager.SetStatefulStore(store)
+	handler := NewWebSocketHandler(manager)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r.WithContext(stateful.ContextWithPartition(r.Context(), "run-a")))
+	}))
+	defer ts.Close()
+
+	conn, err := dialWSConn(t, ts, "/ws/orders")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.Eventually(t, func() bool { return store.Changes().SubscriberCount() == 1 }, 2*time.Second, 5*time.Millisecond)
+
+	_, err = store.Get("", "orders").Create(map[string]interface{}{"id": "shared"}, nil)
+	require.NoError(t, err)
+	_, err = store.Get(stateful.PartitionWorkspaceID("", "run-a"), "orders").Create(map[string]interface{}{"id": "isolated"}, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	var msg map[string]interface{}
+	require.NoError(t, conn.ReadJSON(&msg))
+	assert.Equal(t, "isolated", msg["id"], "shared table changes must not reach an isolated client")
+}
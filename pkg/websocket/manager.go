@@ -2,14 +2,18 @@ package websocket
 
 import (
 	"context"
+	"log/slog"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/getmockd/mockd/pkg/chaos"
+	"github.com/getmockd/mockd/pkg/logging"
 	"github.com/getmockd/mockd/pkg/metrics"
 	"github.com/getmockd/mockd/pkg/protocol"
 	"github.com/getmockd/mockd/pkg/recording"
 	"github.com/getmockd/mockd/pkg/requestlog"
+	"github.com/getmockd/mockd/pkg/stateful"
 	"github.com/getmockd/mockd/pkg/util"
 )
 
@@ -21,6 +25,10 @@ type RecordingHookFactory interface {
 	CreateHook(path string) recording.WebSocketRecordingHook
 }
 
+// ChaosSource returns the chaos injector currently in effect, or nil when
+// chaos is off. It is consulted once per new connection.
+type ChaosSource func() *chaos.Injector
+
 // ConnectionManager manages all WebSocket connections across endpoints.
 type ConnectionManager struct {
 	id          string                     // unique handler identifier
@@ -34,6 +42,9 @@ type ConnectionManager struct {
 	startTime        time.Time
 	requestLogger    requestlog.Logger
 	recordingFactory RecordingHookFactory // optional: creates recording hooks for new connections
+	statefulStore    *stateful.StateStore // optional: source for statefulStream endpoints
+	chaosSource      ChaosSource          // optional: message chaos for new connections
+	log              *slog.Logger         // operational logger for errors/warnings
 
 	mu sync.RWMutex
 }
@@ -88,6 +99,56 @@ func (m *ConnectionManager) GetRecordingHookFactory() RecordingHookFactory {
 	return m.recordingFactory
 }
 
+// SetOperationalLogger sets the logger for errors and warnings.
+func (m *ConnectionManager) SetOperationalLogger(log *slog.Logger) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.log = log
+}
+
+// logger returns the operational logger, or a no-op logger if none is set.
+func (m *ConnectionManager) logger() *slog.Logger {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.log == nil {
+		return logging.Nop()
+	}
+	return m.log
+}
+
+// SetStatefulStore sets the store whose change feed backs statefulStream endpoints.
+func (m *ConnectionManager) SetStatefulStore(store *stateful.StateStore) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.statefulStore = store
+}
+
+// GetStatefulStore returns the store used for statefulStream endpoints.
+func (m *ConnectionManager) GetStatefulStore() *stateful.StateStore {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.statefulStore
+}
+
+// SetChaosSource sets where new connections get their message chaos from.
+func (m *ConnectionManager) SetChaosSource(source ChaosSource) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.chaosSource = source
+}
+
+// messageChaos returns the message chaos for a new connection on path, or
+// nil when no websocket chaos rule applies.
+func (m *ConnectionManager) messageChaos(path string) *chaos.MessageChaos {
+	m.mu.RLock()
+	source := m.chaosSource
+	m.mu.RUnlock()
+	if source == nil {
+		return nil
+	}
+	return source().NewMessageChaos(chaos.ProtocolWebSocket, path)
+}
+
 // RegisterEndpoint registers an endpoint with the manager.
 func (m *ConnectionManager) RegisterEndpoint(e *Endpoint) {
 	m.mu.Lock()
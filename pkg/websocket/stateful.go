@@ -0,0 +1,72 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/getmockd/mockd/pkg/logging"
+	"github.com/getmockd/mockd/pkg/stateful"
+)
+
+// HasStatefulStream returns true if the endpoint pushes stateful table changes.
+func (e *Endpoint) HasStatefulStream() bool {
+	return e.changes != nil
+}
+
+// subscribeChanges subscribes to the endpoint's table change feed, in the
+// isolation partition carried by ctx if any.
+// On failure it returns the HTTP status to report before the upgrade.
+func (e *Endpoint) subscribeChanges(ctx context.Context) (*stateful.Subscription, int, error) {
+	var store *stateful.StateStore
+	if m := e.Manager(); m != nil {
+		store = m.GetStatefulStore()
+	}
+	if store == nil {
+		return nil, http.StatusServiceUnavailable, errors.New("stateful store not available")
+	}
+
+	sub, err := e.changes.Subscribe(store, stateful.PartitionWorkspaceID(e.workspaceID, stateful.PartitionFromContext(ctx)))
+	if err != nil {
+		var notFound *stateful.NotFoundError
+		if errors.As(err, &notFound) {
+			return nil, http.StatusNotFound, err
+		}
+		return nil, http.StatusInternalServerError, err
+	}
+	return sub, http.StatusOK, nil
+}
+
+// runChangeStream sends each matching table change to the connection as a
+// JSON text message until the connection or subscription closes.
+func (e *Endpoint) runChangeStream(conn *Connection, sub *stateful.Subscription) {
+	var log *slog.Logger
+	if m := e.Manager(); m != nil {
+		log = m.logger()
+	} else {
+		log = logging.Nop()
+	}
+	for {
+		select {
+		case <-conn.Context().Done():
+			return
+		case change, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			// Filter errors (e.g. a field missing on some items) skip the change.
+			match, err := e.changes.Match(change)
+			if err != nil {
+				log.Warn("stateful stream filter failed, change skipped",
+					"table", e.changes.Table(), "event", change.Type, "id", change.ItemID, "error", err)
+			}
+			if !match {
+				continue
+			}
+			if err := conn.SendJSON(e.changes.Render(change).Data); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -582,9 +582,10 @@ func TestMCP_ToolsList(t *testing.T) {
 		}
 	}
 
-	// Verify total count is 18 (16 after multiplexing + 2 stateful chaos tools)
-	if len(result.Tools) != 18 {
-		t.Errorf("expected 18 tools, got %d", len(result.Tools))
+	// Verify total count is 19 (16 after multiplexing + 2 stateful chaos tools
+	// + manage_chaos_profile)
+	if len(result.Tools) != 19 {
+		t.Errorf("expected 19 tools, got %d", len(result.Tools))
 	}
 }
 
@@ -116,16 +116,18 @@ func TestCLIBinarySize(t *testing.T) {
 	sizeMB := float64(size) / (1024 * 1024)
 	t.Logf("Binary size: %.2f MB", sizeMB)
 
-	// Binary size is ~41MB due to:
+	// Binary size is ~47MB due to:
 	// - gRPC/protobuf support (~6000 symbols)
 	// - OpenAPI validator
 	// - Protocol compiler for gRPC reflection
+	// - gRPC reflection client and .proto printer for the recording proxy
 	// - MQTT broker
 	// - JSONPath parser
 	// - Cobra + Charmbracelet TUI (huh, bubbletea, lipgloss) for interactive CLI
 	// This is expected for a feature-rich mock server.
-	// A stripped binary (-ldflags="-s -w") is ~29MB.
-	if sizeMB > 45 {
-		t.Errorf("Binary size %.2f MB seems excessive (expected < 45MB)", sizeMB)
+	// The budget leaves ~3MB of headroom; raise it deliberately, not to
+	// silence a regression.
+	if sizeMB > 50 {
+		t.Errorf("Binary size %.2f MB seems excessive (expected < 50MB)", sizeMB)
 	}
 }